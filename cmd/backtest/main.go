@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/iddaa-lens/core/internal/config"
+	"github.com/iddaa-lens/core/pkg/backtest"
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/database/pool"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+func main() {
+	// Load .env file if it exists
+	envPath := filepath.Join(".", ".env")
+	if _, err := os.Stat(envPath); err == nil {
+		if err := godotenv.Load(envPath); err != nil {
+			// Log but don't fail - env vars might be set elsewhere
+			logger.New("backtest").Warn().
+				Err(err).
+				Str("path", envPath).
+				Msg("Failed to load .env file")
+		}
+	}
+
+	defaults := backtest.NewSmartMoneyStrategy()
+	var (
+		from          = flag.String("from", time.Now().AddDate(0, 0, -30).Format(time.DateOnly), "First kickoff date to replay (YYYY-MM-DD)")
+		to            = flag.String("to", time.Now().Format(time.DateOnly), "Replay kickoffs before this date (YYYY-MM-DD)")
+		sport         = flag.String("sport", "", "Sport code to replay, empty for all sports")
+		strategyName  = flag.String("strategy", "smart_money", "Strategy to replay (smart_money for the threshold flags, rules for the active smart_money_rules)")
+		signals       = flag.String("signals", "", "Smart money signals to bet on (reverse_line, sharp_money, steam_move, value_spot), empty for all")
+		staking       = flag.String("staking", "flat,kelly,fractional", "Comma separated staking plans (flat, kelly, fractional)")
+		bankroll      = flag.Float64("bankroll", 100, "Starting bankroll")
+		flatUnits     = flag.Float64("flat-stake", 1, "Stake per bet for flat staking")
+		kellyFraction = flag.Float64("kelly-fraction", 0.25, "Kelly multiplier for fractional staking")
+		maxFraction   = flag.Float64("max-stake-fraction", 0.05, "Cap on a single Kelly stake as a share of bankroll, 0 for none")
+		edge          = flag.Float64("edge", defaults.Edge, "Assumed probability uplift at confidence 1, used by Kelly staking")
+		settleAfter   = flag.Duration("settle-after", 2*time.Hour, "Delay after kickoff before a result is credited")
+		minSharp      = flag.Int("min-sharp-score", int(defaults.Thresholds.MinSharpScore), "Sharp money score an indicator must exceed")
+		minBias       = flag.Float64("min-bias", defaults.Thresholds.MinBiasPct, "Minimum public bias % for value spots")
+		minMovement   = flag.Float64("min-movement", defaults.Thresholds.MinMovementPct, "Minimum odds change % for value spots")
+		reverseMove   = flag.Float64("reverse-movement", defaults.Thresholds.ReverseMovementPct, "Minimum odds change % for reverse line movements")
+		steamMoves    = flag.Int("steam-moves", defaults.Thresholds.SteamMinMoves, "Moves within an hour that make a steam move")
+		format        = flag.String("format", "json", "Report format (json, csv)")
+		output        = flag.String("out", "", "Report file, backtest_report.<format> when empty and - for stdout")
+		betsCSV       = flag.String("bets-csv", "", "Also write every simulated bet to this CSV file")
+		includeBets   = flag.Bool("include-bets", false, "Include individual bets in the JSON report")
+	)
+	flag.Parse()
+
+	logger.SetupLogger()
+	log := logger.New("backtest")
+
+	fromTime, err := time.Parse(time.DateOnly, *from)
+	if err != nil {
+		log.Fatal().Err(err).Str("from", *from).Msg("Invalid -from date")
+	}
+	toTime, err := time.Parse(time.DateOnly, *to)
+	if err != nil {
+		log.Fatal().Err(err).Str("to", *to).Msg("Invalid -to date")
+	}
+	if !toTime.After(fromTime) {
+		log.Fatal().Msg("-to must be after -from")
+	}
+
+	var stakers []backtest.Staker
+	for _, name := range strings.Split(*staking, ",") {
+		staker, err := backtest.ParseStaker(name, *flatUnits, *kellyFraction, *maxFraction)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid -staking")
+		}
+		stakers = append(stakers, staker)
+	}
+
+	if *strategyName != "smart_money" && *strategyName != "rules" {
+		log.Fatal().Str("strategy", *strategyName).Msg("Invalid -strategy")
+	}
+
+	cfg := config.Load()
+	ctx := context.Background()
+
+	// Replays run sequentially, a couple of connections is plenty
+	poolConfig := pool.DefaultConfig()
+	poolConfig.MaxConns = 4
+	poolConfig.MinConns = 1
+	db, err := pool.New(ctx, cfg.DatabaseURL(), poolConfig)
+	if err != nil {
+		log.Fatal().
+			Err(err).
+			Str("action", "db_connect_failed").
+			Msg("Failed to connect to database")
+	}
+	defer db.Close()
+	queries := generated.New(db)
+
+	var strategy backtest.Strategy
+	if *strategyName == "rules" {
+		rules, err := services.NewSmartMoneyTracker(queries).LoadRules(ctx)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load smart money rules")
+		}
+		rulesStrategy := backtest.NewRulesStrategy(rules)
+		rulesStrategy.Edge = *edge
+		strategy = rulesStrategy
+	} else {
+		smartMoney := backtest.NewSmartMoneyStrategy()
+		smartMoney.Signals = backtest.ParseSignals(*signals)
+		smartMoney.Edge = *edge
+		smartMoney.Thresholds.MinSharpScore = int32(*minSharp)
+		smartMoney.Thresholds.MinBiasPct = *minBias
+		smartMoney.Thresholds.MinMovementPct = *minMovement
+		smartMoney.Thresholds.ReverseMovementPct = *reverseMove
+		smartMoney.Thresholds.SteamMinMoves = *steamMoves
+		strategy = smartMoney
+	}
+
+	start := time.Now()
+	data, err := backtest.Load(ctx, queries, fromTime, toTime, *sport)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load replay data")
+	}
+
+	log.Info().
+		Str("action", "data_loaded").
+		Int("events", len(data.Events)).
+		Int("ticks", len(data.Ticks)).
+		Int("settlements", len(data.Settlements)).
+		Dur("duration", time.Since(start)).
+		Msg("Loaded replay data")
+
+	simConfig := backtest.DefaultConfig()
+	simConfig.Bankroll = *bankroll
+	simConfig.SettleAfter = *settleAfter
+	simConfig.IncludeBets = *includeBets
+
+	reports := make([]*backtest.Report, 0, len(stakers))
+	for _, staker := range stakers {
+		report, err := backtest.Run(data, strategy, staker, simConfig)
+		if err != nil {
+			log.Fatal().Err(err).Str("staking", staker.Name()).Msg("Backtest failed")
+		}
+		reports = append(reports, report)
+
+		log.Info().
+			Str("action", "backtest_complete").
+			Str("strategy", report.Strategy).
+			Str("staking", report.Staking).
+			Int("bets", report.Bets).
+			Float64("roi", report.ROI).
+			Float64("yield", report.Yield).
+			Float64("max_drawdown_pct", report.MaxDrawdownPct).
+			Float64("avg_clv", report.AvgCLVPercentage).
+			Msg("Backtest completed")
+	}
+
+	reportPath := *output
+	if reportPath == "" {
+		reportPath = "backtest_report." + *format
+	}
+	if err := writeReport(reportPath, *format, reports); err != nil {
+		log.Fatal().Err(err).Msg("Failed to write report")
+	}
+	log.Info().Str("path", reportPath).Msg("Report written")
+
+	if *betsCSV != "" {
+		if err := writeFile(*betsCSV, func(w io.Writer) error {
+			return backtest.WriteBetsCSV(w, reports)
+		}); err != nil {
+			log.Fatal().Err(err).Msg("Failed to write bets")
+		}
+	}
+}
+
+func writeReport(path, format string, reports []*backtest.Report) error {
+	switch format {
+	case "json":
+		return writeFile(path, func(w io.Writer) error { return backtest.WriteJSON(w, reports) })
+	case "csv":
+		return writeFile(path, func(w io.Writer) error { return backtest.WriteCSV(w, reports) })
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+func writeFile(path string, write func(io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -15,6 +18,7 @@ import (
 	"github.com/iddaa-lens/core/internal/config"
 	"github.com/iddaa-lens/core/pkg/database/generated"
 	"github.com/iddaa-lens/core/pkg/database/pool"
+	"github.com/iddaa-lens/core/pkg/iddaamock"
 	"github.com/iddaa-lens/core/pkg/jobs"
 	"github.com/iddaa-lens/core/pkg/logger"
 	"github.com/iddaa-lens/core/pkg/services"
@@ -34,10 +38,15 @@ func main() {
 	}
 	// Parse command line flags
 	var (
-		jobName           = flag.String("job", "", "Run specific job once (config, sports, events, volume, distribution, analytics, market_config, statistics, leagues, detailed_odds, api_football_league_matching, api_football_team_matching, api_football_league_enrichment, api_football_team_enrichment, smart_money_processor)")
+		jobName           = flag.String("job", "", "Run specific job once (config, sports, events, volume, distribution, analytics, market_config, statistics, leagues, detailed_odds, api_football_league_matching, api_football_team_matching, api_football_league_enrichment, api_football_team_enrichment, smart_money_processor, webhooks, clv, settlement, bookmaker_odds, margins, candles, partitions, odds_cache)")
 		once              = flag.Bool("once", false, "Run job once and exit")
 		healthCheck       = flag.Bool("health-check", false, "Perform health check and exit")
 		useProductionMode = flag.Bool("production-mode", false, "Use production job manager with distributed locking")
+		liveOdds          = flag.Bool("live-odds", false, "Run the live odds worker alongside the cron jobs")
+		liveInterval      = flag.Duration("live-interval", 5*time.Second, "Pause between live odds polls")
+		liveMaxInterval   = flag.Duration("live-max-interval", time.Minute, "Maximum pause between live odds polls under backpressure")
+		liveConcurrency   = flag.Int("live-concurrency", 2, "Sports polled in parallel by the live odds worker")
+		metricsAddr       = flag.String("metrics-addr", "", "Serve Iddaa upstream health on this address (/upstream and expvar /debug/vars), empty to disable")
 	)
 	flag.Parse()
 
@@ -69,15 +78,72 @@ func main() {
 
 	// Initialize services
 	queries := generated.New(db)
-	iddaaClient := services.NewIddaaClient(cfg)
+	iddaaClient := services.NewIddaaClientWithTransport(cfg, iddaaTransport(cfg.Iddaa, log))
 	configService := services.NewConfigService(queries, iddaaClient)
 	sportsService := services.NewSportService(queries, iddaaClient)
 	eventsService := services.NewEventsService(queries, iddaaClient)
+	// The events, detailed odds and live odds jobs each write odds through their own events
+	// service, so every job can pick its ingestion path
+	detailedOddsService := services.NewEventsService(queries, iddaaClient)
+	liveOddsService := services.NewEventsService(queries, iddaaClient)
 	volumeService := services.NewVolumeService(queries, iddaaClient)
 	distributionService := services.NewDistributionService(queries, iddaaClient)
 	marketConfigService := services.NewMarketConfigService(queries, iddaaClient)
 	statisticsService := services.NewStatisticsService(queries, iddaaClient)
 	smartMoneyTracker := services.NewSmartMoneyTracker(queries)
+	webhookService := services.NewWebhookService(queries)
+	closingLineService := services.NewClosingLineService(queries)
+	settlementService := services.NewSettlementService(queries)
+	marginService := services.NewMarginService(queries)
+	candleService := services.NewCandleService(queries)
+	partitionService := services.NewPartitionService(db, cfg.History)
+
+	// History inserts fail without a partition, make sure the coming months exist before any sync
+	// instead of waiting for the nightly maintenance run
+	if created, err := partitionService.EnsurePartitions(context.Background(), time.Now()); err != nil {
+		log.Fatal().
+			Err(err).
+			Str("action", "partitions_failed").
+			Msg("Failed to create history partitions")
+	} else if created > 0 {
+		log.Info().
+			Str("action", "partitions_created").
+			Int("created", created).
+			Msg("Created missing history partitions")
+	}
+
+	// Current odds cache shared by the odds jobs, warmed before the scheduler starts
+	var oddsCache *services.OddsCache
+	if cfg.Ingest.OddsCache {
+		oddsCache = services.NewOddsCache(queries)
+		eventsService.SetOddsCache(oddsCache)
+		detailedOddsService.SetOddsCache(oddsCache)
+		liveOddsService.SetOddsCache(oddsCache)
+	}
+
+	for job, ingest := range map[string]struct {
+		service *services.EventsService
+		mode    string
+	}{
+		"events":        {eventsService, cfg.Ingest.Events},
+		"detailed_odds": {detailedOddsService, cfg.Ingest.DetailedOdds},
+		"live_odds":     {liveOddsService, cfg.Ingest.LiveOdds},
+	} {
+		writer, err := services.NewOddsWriter(ingest.mode, db, queries)
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Str("action", "odds_ingest_invalid").
+				Str("job", job).
+				Msg("Invalid odds ingest mode")
+		}
+		ingest.service.SetOddsWriter(writer)
+		log.Info().
+			Str("action", "odds_ingest_selected").
+			Str("job", job).
+			Str("mode", ingest.mode).
+			Msg("Odds ingestion path selected")
+	}
 
 	// Create job manager (production or standard based on flag)
 	var jobManager jobs.JobManager
@@ -98,6 +164,14 @@ func main() {
 		jobManager = jobs.NewJobManager()
 	}
 
+	// Every run is recorded in job_runs for the admin jobs endpoints
+	runRecorder := jobs.NewJobRunRecorder(queries, cfg.Jobs.InstanceID, cfg.Jobs.RunRetentionDays)
+	jobManager.SetRunRecorder(runRecorder)
+	log.Info().
+		Str("action", "job_runs_enabled").
+		Str("instance_id", runRecorder.InstanceID()).
+		Msg("Recording job runs")
+
 	// Register jobs
 	configJob := jobs.NewConfigSyncJob(configService, "WEB")
 	if err := jobManager.RegisterJob(configJob); err != nil {
@@ -151,7 +225,7 @@ func main() {
 	}
 
 	// Register detailed odds sync job for high-frequency odds tracking
-	detailedOddsJob := jobs.NewDetailedOddsSyncJob(queries, iddaaClient, eventsService)
+	detailedOddsJob := jobs.NewDetailedOddsSyncJob(queries, iddaaClient, detailedOddsService, oddsCache)
 	if err := jobManager.RegisterJob(detailedOddsJob); err != nil {
 		log.Fatalf("Failed to register detailed odds sync job: %v", err)
 	}
@@ -186,6 +260,63 @@ func main() {
 		log.Fatalf("Failed to register Smart Money Processor job: %v", err)
 	}
 
+	// Register webhook dispatch job for movement alert delivery
+	webhookDispatchJob := jobs.NewWebhookDispatchJob(webhookService)
+	if err := jobManager.RegisterJob(webhookDispatchJob); err != nil {
+		log.Fatalf("Failed to register webhook dispatch job: %v", err)
+	}
+
+	// Register closing lines job for CLV computation
+	closingLinesJob := jobs.NewClosingLinesJob(closingLineService)
+	if err := jobManager.RegisterJob(closingLinesJob); err != nil {
+		log.Fatalf("Failed to register closing lines job: %v", err)
+	}
+
+	// Register settlement job for graded outcomes
+	settlementJob := jobs.NewSettlementJob(settlementService)
+	if err := jobManager.RegisterJob(settlementJob); err != nil {
+		log.Fatalf("Failed to register settlement job: %v", err)
+	}
+
+	// Register bookmaker odds sync job for cross-book price comparison
+	bookmakerOddsJob := jobs.NewBookmakerOddsSyncJob(queries)
+	if err := jobManager.RegisterJob(bookmakerOddsJob); err != nil {
+		log.Fatalf("Failed to register bookmaker odds sync job: %v", err)
+	}
+
+	// Register market margins job for overround and fair probabilities
+	marketMarginsJob := jobs.NewMarketMarginsJob(marginService)
+	if err := jobManager.RegisterJob(marketMarginsJob); err != nil {
+		log.Fatalf("Failed to register market margins job: %v", err)
+	}
+
+	// Register odds candles job for OHLC rollups
+	oddsCandlesJob := jobs.NewOddsCandlesJob(candleService)
+	if err := jobManager.RegisterJob(oddsCandlesJob); err != nil {
+		log.Fatalf("Failed to register odds candles job: %v", err)
+	}
+
+	// Register partition maintenance job for history partitions and retention
+	partitionMaintenanceJob := jobs.NewPartitionMaintenanceJob(partitionService)
+	if err := jobManager.RegisterJob(partitionMaintenanceJob); err != nil {
+		log.Fatalf("Failed to register partition maintenance job: %v", err)
+	}
+
+	// Register odds cache consistency check job. The cache is local to this process, so in
+	// production mode every instance checks and rebuilds its own instead of taking the lock.
+	if oddsCache != nil {
+		oddsCacheCheckJob := jobs.NewOddsCacheCheckJob(oddsCache)
+		var err error
+		if productionManager, ok := jobManager.(*jobs.ProductionJobManager); ok {
+			err = productionManager.RegisterJobWithoutLocking(oddsCacheCheckJob)
+		} else {
+			err = jobManager.RegisterJob(oddsCacheCheckJob)
+		}
+		if err != nil {
+			log.Fatalf("Failed to register odds cache check job: %v", err)
+		}
+	}
+
 	// Handle single job execution
 	if *once && *jobName != "" {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
@@ -209,6 +340,14 @@ func main() {
 			"api_football_league_enrichment": "api_football_league_enrichment",
 			"api_football_team_enrichment":   "api_football_team_enrichment",
 			"smart_money_processor":          "smart_money_processor",
+			"webhooks":                       "webhook_dispatch",
+			"clv":                            "closing_lines",
+			"settlement":                     "settlement",
+			"bookmaker_odds":                 "bookmaker_odds_sync",
+			"margins":                        "market_margins",
+			"candles":                        "odds_candles",
+			"partitions":                     "partition_maintenance",
+			"odds_cache":                     "odds_cache_check",
 		}
 
 		actualJobName, exists := jobNameMapping[*jobName]
@@ -229,13 +368,33 @@ func main() {
 		}
 
 		log.Printf("Running %s job once...", *jobName)
-		if err := targetJob.Execute(ctx); err != nil {
+		if err := runRecorder.Run(ctx, targetJob, jobs.TriggerManual); err != nil {
 			log.Fatalf("Failed to execute %s job: %v", *jobName, err)
 		}
 		log.Printf("%s completed successfully", *jobName)
 		return
 	}
 
+	// Warm the odds cache before the first odds sync, a failed warm-up leaves it reading the database
+	// until the odds_cache_check job rebuilds it
+	if oddsCache != nil {
+		warmCtx, cancelWarm := context.WithTimeout(context.Background(), 2*time.Minute)
+		report, err := oddsCache.Rebuild(warmCtx, time.Now())
+		cancelWarm()
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("action", "odds_cache_warm_failed").
+				Msg("Failed to warm odds cache")
+		} else {
+			log.Info().
+				Str("action", "odds_cache_warmed").
+				Int("entries", report.Entries).
+				Int64("version", report.Version).
+				Msg("Odds cache warmed")
+		}
+	}
+
 	// Start job manager
 	jobManager.Start()
 	log.Info().
@@ -243,6 +402,67 @@ func main() {
 		Int("job_count", len(jobManager.GetJobs())).
 		Msg("Cron job service started")
 
+	// Start the live odds worker in its own loop, outside the cron schedule
+	liveCtx, stopLive := context.WithCancel(context.Background())
+	liveDone := make(chan struct{})
+	if *liveOdds {
+		liveConfig := jobs.DefaultLiveOddsWorkerConfig()
+		liveConfig.Interval = *liveInterval
+		liveConfig.MaxInterval = *liveMaxInterval
+		liveConfig.MaxConcurrency = *liveConcurrency
+
+		// Advisory locks are session scoped, so the worker holds its lock on a dedicated connection
+		var liveLocks jobs.JobLockManager
+		if *useProductionMode {
+			conn, err := db.Acquire(context.Background())
+			if err != nil {
+				log.Fatal().Err(err).Msg("Failed to acquire connection for live odds lock")
+			}
+			defer conn.Release()
+			liveLocks = jobs.NewPostgreSQLLockManager(conn.Conn())
+		}
+
+		liveWorker := jobs.NewLiveOddsWorker(iddaaClient, liveOddsService, statisticsService, liveLocks, liveConfig)
+		go func() {
+			defer close(liveDone)
+			if err := liveWorker.Run(liveCtx); err != nil && liveCtx.Err() == nil {
+				log.Error().Err(err).Msg("Live odds worker exited")
+			}
+		}()
+	} else {
+		close(liveDone)
+	}
+
+	// Apply pauses, schedules and manual triggers set through the admin API
+	controlCtx, stopControl := context.WithCancel(context.Background())
+	controlDone := make(chan struct{})
+	if cfg.Jobs.ControlInterval > 0 {
+		controlPlane := jobs.NewJobControlPlane(queries, jobManager, runRecorder.InstanceID(),
+			time.Duration(cfg.Jobs.ControlInterval)*time.Second, cfg.Jobs.RunRetentionDays)
+		go func() {
+			defer close(controlDone)
+			controlPlane.Run(controlCtx)
+		}()
+	} else {
+		close(controlDone)
+	}
+
+	// Expose upstream health; expvar registers /debug/vars on the default mux
+	var metricsServer *http.Server
+	if *metricsAddr != "" {
+		http.HandleFunc("/upstream", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(services.IddaaUpstreamHealth())
+		})
+		metricsServer = &http.Server{Addr: *metricsAddr, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			log.Info().Str("addr", *metricsAddr).Msg("Serving upstream health metrics")
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error().Err(err).Msg("Metrics server failed")
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -252,9 +472,41 @@ func main() {
 		Str("action", "shutdown_initiated").
 		Msg("Shutting down cron job service")
 
+	stopLive()
+	<-liveDone
+	stopControl()
+	<-controlDone
 	jobManager.Stop()
+	if metricsServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_ = metricsServer.Shutdown(shutdownCtx)
+		cancel()
+	}
 
 	log.Info().
 		Str("action", "service_stopped").
 		Msg("Cron job service stopped")
 }
+
+// iddaaTransport sends Iddaa requests to the cmd/iddaa-mock server at IDDAA_MOCK_URL and records
+// the responses into IDDAA_RECORD_DIR. Fixtures keep the real Iddaa URLs and budgets apply per
+// real host, as recording wraps the client's retries which wrap the redirect.
+func iddaaTransport(cfg config.IddaaConfig, log *logger.Logger) services.IddaaTransport {
+	var transport services.IddaaTransport
+	if cfg.MockURL != "" {
+		mock, err := iddaamock.NewMockTransport(cfg.MockURL, nil)
+		if err != nil {
+			log.Error().Err(err).Msg("Ignoring IDDAA_MOCK_URL")
+		} else {
+			transport.Upstream = mock
+			log.Info().Str("mock_url", cfg.MockURL).Msg("Sending Iddaa requests to mock server")
+		}
+	}
+	if cfg.RecordDir != "" {
+		transport.Wrap = func(next http.RoundTripper) http.RoundTripper {
+			return iddaamock.NewRecordingTransport(cfg.RecordDir, next)
+		}
+		log.Info().Str("record_dir", cfg.RecordDir).Msg("Recording Iddaa responses")
+	}
+	return transport
+}
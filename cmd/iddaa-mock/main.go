@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/iddaamock"
+	"github.com/iddaa-lens/core/pkg/logger"
+)
+
+// Replays Iddaa responses recorded with IDDAA_RECORD_DIR. Point the services at it with
+// IDDAA_MOCK_URL=http://localhost:8090 to run jobs end to end without access to iddaa.com.
+func main() {
+	var (
+		addr         = flag.String("addr", ":8090", "Listen address")
+		fixturesDir  = flag.String("fixtures", "fixtures/iddaa", "Directory of recorded fixtures (IDDAA_RECORD_DIR of the recording run)")
+		shift        = flag.Duration("shift", 0, "Added to every recorded event date")
+		shiftToToday = flag.Bool("shift-to-today", false, "Shift by whole days so the newest recording plays as today, overrides -shift")
+		scenarioPath = flag.String("scenario", "", "Scenario file scripting odds drift, goals and suspensions")
+	)
+	flag.Parse()
+
+	logger.SetupLogger()
+	log := logger.New("iddaa-mock")
+
+	store, err := iddaamock.LoadStore(*fixturesDir)
+	if err != nil {
+		log.Fatal().Err(err).Str("dir", *fixturesDir).Msg("Failed to load fixtures")
+	}
+	if store.Len() == 0 {
+		log.Fatal().Str("dir", *fixturesDir).Msg("No fixtures found, record some with IDDAA_RECORD_DIR first")
+	}
+
+	options := iddaamock.Options{Shift: *shift}
+	if *shiftToToday {
+		options.Shift = iddaamock.ShiftToDay(store.LatestRecordedAt(), time.Now())
+	}
+	if *scenarioPath != "" {
+		scenario, err := iddaamock.LoadScenario(*scenarioPath)
+		if err != nil {
+			log.Fatal().Err(err).Str("path", *scenarioPath).Msg("Failed to load scenario")
+		}
+		options.Scenario = scenario
+		log.Info().
+			Str("scenario", scenario.Name).
+			Int("steps", len(scenario.Steps)).
+			Msg("Scenario loaded")
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           iddaamock.NewServer(store, options),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Info().
+			Str("addr", *addr).
+			Int("fixtures", store.Len()).
+			Dur("shift", options.Shift).
+			Msg("Iddaa mock server started")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Err(err).Msg("Mock server failed")
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to shut down mock server")
+	}
+	log.Info().Msg("Iddaa mock server stopped")
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/joho/godotenv"
+
+	"github.com/iddaa-lens/core/internal/config"
+	"github.com/iddaa-lens/core/pkg/archive"
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/database/pool"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// Rebuilds current_odds, odds_history, distributions and volumes from the payload archive written
+// with PAYLOAD_ARCHIVE_DIR, then the odds candles of the replayed range. Payloads are applied in
+// fetch order and stamped with their fetch time.
+func main() {
+	// Load .env file if it exists
+	envPath := filepath.Join(".", ".env")
+	if _, err := os.Stat(envPath); err == nil {
+		if err := godotenv.Load(envPath); err != nil {
+			// Log but don't fail - env vars might be set elsewhere
+			logger.New("reprocess").Warn().
+				Err(err).
+				Str("path", envPath).
+				Msg("Failed to load .env file")
+		}
+	}
+
+	var (
+		from       = flag.String("from", time.Now().UTC().AddDate(0, 0, -1).Format(time.DateOnly), "First fetch date to replay (YYYY-MM-DD, UTC)")
+		to         = flag.String("to", time.Now().UTC().AddDate(0, 0, 1).Format(time.DateOnly), "Replay fetches before this date (YYYY-MM-DD, UTC)")
+		sport      = flag.Int("sport", 0, "Iddaa sport id to replay, 0 for all sports")
+		archiveDir = flag.String("archive", os.Getenv("PAYLOAD_ARCHIVE_DIR"), "Payload archive directory")
+		only       = flag.String("only", "events,detailed,distributions,volumes", "Comma separated payload kinds to replay")
+		reset      = flag.Bool("reset", false, "Replace the history recorded in the range instead of adding to it. Movement alerts move to the replayed odds rows; alerts whose movement is not replayed are deleted with their results and webhook deliveries")
+		dryRun     = flag.Bool("dry-run", false, "List what would be replayed without writing")
+	)
+	flag.Parse()
+
+	logger.SetupLogger()
+	log := logger.New("reprocess")
+
+	fromTime, err := time.Parse(time.DateOnly, *from)
+	if err != nil {
+		log.Fatal().Err(err).Str("from", *from).Msg("Invalid -from date")
+	}
+	toTime, err := time.Parse(time.DateOnly, *to)
+	if err != nil {
+		log.Fatal().Err(err).Str("to", *to).Msg("Invalid -to date")
+	}
+	if !toTime.After(fromTime) {
+		log.Fatal().Msg("-to must be after -from")
+	}
+	if *archiveDir == "" {
+		log.Fatal().Msg("No archive directory, set -archive or PAYLOAD_ARCHIVE_DIR")
+	}
+
+	var kinds []string
+	for _, kind := range strings.Split(*only, ",") {
+		switch kind = strings.TrimSpace(kind); kind {
+		case services.PayloadEvents, services.PayloadDetailed, services.PayloadDistributions, services.PayloadVolumes:
+			kinds = append(kinds, kind)
+		default:
+			log.Fatal().Str("kind", kind).Msg("Invalid -only payload kind")
+		}
+	}
+
+	payloads, err := archive.Open(*archiveDir)
+	if err != nil {
+		log.Fatal().Err(err).Str("dir", *archiveDir).Msg("Failed to open archive")
+	}
+	entries, err := payloads.Entries(archive.Filter{
+		Source:    archive.SourceIddaa,
+		Endpoints: services.ReprocessEndpoints(kinds),
+		SportID:   *sport,
+		From:      fromTime,
+		To:        toTime,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to read archive index")
+	}
+
+	log.Info().
+		Str("from", *from).
+		Str("to", *to).
+		Int("payloads", len(entries)).
+		Strs("kinds", kinds).
+		Msg("Archive loaded")
+
+	if *dryRun {
+		counts := make(map[string]int)
+		for _, entry := range entries {
+			counts[services.PayloadKind(entry)]++
+		}
+		for _, kind := range kinds {
+			log.Info().Str("kind", kind).Int("payloads", counts[kind]).Msg("Would replay")
+		}
+		return
+	}
+
+	cfg := config.Load()
+	// Replayed payloads must not be archived a second time
+	cfg.Archive.Dir = ""
+	ctx := context.Background()
+
+	// Payloads are applied one at a time, a few connections cover the bulk writes
+	poolConfig := pool.DefaultConfig()
+	poolConfig.MaxConns = 4
+	poolConfig.MinConns = 1
+	db, err := pool.New(ctx, cfg.DatabaseURL(), poolConfig)
+	if err != nil {
+		log.Fatal().
+			Err(err).
+			Str("action", "db_connect_failed").
+			Msg("Failed to connect to database")
+	}
+	defer db.Close()
+	queries := generated.New(db)
+
+	// Archived months may predate the partitions kept by retention
+	created, err := services.NewPartitionService(db, cfg.History).EnsureRange(ctx, fromTime, toTime)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create history partitions for the range")
+	}
+	if created > 0 {
+		log.Info().Int("created", created).Msg("Created history partitions for the range")
+	}
+
+	// Odds history rows up to this id are replaced once the replay wrote the new ones
+	var replacedOddsID int32
+	if *reset {
+		replacedOddsID = resetHistory(ctx, queries, fromTime, toTime, kinds)
+	}
+
+	iddaaClient := services.NewIddaaClient(cfg)
+	reprocessor := services.NewReprocessor(
+		queries,
+		fromTime,
+		services.NewEventsService(queries, iddaaClient),
+		services.NewDistributionService(queries, iddaaClient),
+		services.NewVolumeService(queries, iddaaClient),
+	)
+
+	start := time.Now()
+	for i, entry := range entries {
+		body, err := payloads.Get(entry.Hash)
+		if err != nil {
+			log.Error().Err(err).Str("url", entry.URL).Msg("Failed to read archived payload")
+			continue
+		}
+		if err := reprocessor.Apply(ctx, entry, body); err != nil {
+			log.Error().Err(err).Msg("Failed to reprocess payload")
+		}
+
+		if (i+1)%500 == 0 {
+			log.Info().
+				Int("done", i+1).
+				Int("total", len(entries)).
+				Time("fetched_at", entry.FetchedAt).
+				Msg("Reprocess progress")
+		}
+	}
+
+	stats := reprocessor.Stats()
+	event := log.Info().
+		Str("action", "reprocess_complete").
+		Int("skipped", stats.Skipped).
+		Int("failed", stats.Failed).
+		Dur("duration", time.Since(start))
+	for _, kind := range kinds {
+		event = event.Int(kind, stats.Applied[kind])
+	}
+	event.Msg("Reprocess completed")
+
+	if slices.Contains(kinds, services.PayloadEvents) || slices.Contains(kinds, services.PayloadDetailed) {
+		if *reset {
+			replaceOddsHistory(ctx, queries, fromTime, toTime, replacedOddsID)
+		}
+		recomputeClosingLineValues(ctx, queries, fromTime, toTime)
+		// The candle rollup only moves forward, candles of the replayed range are rebuilt here
+		rebuildCandles(ctx, queries, fromTime, toTime)
+	}
+}
+
+// replaceOddsHistory moves the movement alerts of the odds history rows up to replacedID over to
+// the replayed rows, then deletes the replaced rows. The delete trigger removes the alerts that
+// found no replayed row and the CLV rows of the replaced rows.
+func replaceOddsHistory(ctx context.Context, queries *generated.Queries, from, to time.Time, replacedID int32) {
+	log := logger.New("reprocess")
+	if replacedID == 0 {
+		return
+	}
+	fromTime := pgtype.Timestamp{Time: from, Valid: true}
+	toTime := pgtype.Timestamp{Time: to, Valid: true}
+
+	relinked, err := queries.RelinkMovementAlertsRange(ctx, generated.RelinkMovementAlertsRangeParams{
+		MaxID:    replacedID,
+		FromTime: fromTime,
+		ToTime:   toTime,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to move movement alerts to the replayed odds history")
+	}
+
+	deleted, err := queries.DeleteOddsHistoryRange(ctx, generated.DeleteOddsHistoryRangeParams{
+		FromTime: fromTime,
+		ToTime:   toTime,
+		MaxID:    replacedID,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to delete replaced odds history")
+	}
+
+	log.Info().
+		Str("kind", "odds").
+		Int64("alerts_relinked", relinked).
+		Int64("deleted", deleted).
+		Msg("History reset")
+}
+
+// recomputeClosingLineValues computes CLV for the replayed odds history of events whose closing
+// lines are already frozen
+func recomputeClosingLineValues(ctx context.Context, queries *generated.Queries, from, to time.Time) {
+	log := logger.New("reprocess")
+
+	eventIDs, err := queries.GetClosedEventsInHistoryRange(ctx, generated.GetClosedEventsInHistoryRangeParams{
+		FromTime: pgtype.Timestamp{Time: from, Valid: true},
+		ToTime:   pgtype.Timestamp{Time: to, Valid: true},
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get events with closing lines")
+	}
+
+	closingLines := services.NewClosingLineService(queries)
+	var snapshots int64
+	for chunk := range slices.Chunk(eventIDs, 500) {
+		stats, err := closingLines.RecomputeEvents(ctx, chunk)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to recompute closing line values")
+		}
+		snapshots += stats.CLVSnapshots
+	}
+
+	log.Info().
+		Str("action", "clv_recomputed").
+		Int("events", len(eventIDs)).
+		Int64("snapshots", snapshots).
+		Msg("Closing line values recomputed")
+}
+
+// rebuildCandles replaces the odds candles of the replayed range with ones rolled up from the
+// odds_history rows the replay wrote
+func rebuildCandles(ctx context.Context, queries *generated.Queries, from, to time.Time) {
+	log := logger.New("reprocess")
+	start := time.Now()
+
+	stats, err := services.NewCandleService(queries).RebuildRange(ctx, from, to)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to rebuild odds candles")
+	}
+
+	log.Info().
+		Str("action", "candles_rebuilt").
+		Int64("deleted", stats.Deleted).
+		Int64("candles_5m", stats.FiveMinute).
+		Int64("candles_1h", stats.Hourly).
+		Int64("candles_1d", stats.Daily).
+		Dur("duration", time.Since(start)).
+		Msg("Odds candles rebuilt")
+}
+
+// resetHistory clears the history rows the replay is about to write again, so a range can be
+// reprocessed repeatedly without duplicating history. Current rows are overwritten by the replay itself.
+// Odds history is kept until replaceOddsHistory, the returned id marks the rows it replaces.
+func resetHistory(ctx context.Context, queries *generated.Queries, from, to time.Time, kinds []string) int32 {
+	log := logger.New("reprocess")
+	fromTime := pgtype.Timestamp{Time: from, Valid: true}
+	toTime := pgtype.Timestamp{Time: to, Valid: true}
+
+	var replacedOddsID int32
+	for _, kind := range kinds {
+		var (
+			deleted int64
+			err     error
+		)
+		switch kind {
+		case services.PayloadEvents, services.PayloadDetailed:
+			// Bulletins and single events share odds_history
+			if replacedOddsID != 0 {
+				continue
+			}
+			replacedOddsID, err = queries.GetOddsHistoryRangeMaxID(ctx, generated.GetOddsHistoryRangeMaxIDParams{FromTime: fromTime, ToTime: toTime})
+			if err != nil {
+				log.Fatal().Err(err).Msg("Failed to get odds history to replace")
+			}
+			continue
+		case services.PayloadDistributions:
+			deleted, err = queries.DeleteDistributionHistoryRange(ctx, generated.DeleteDistributionHistoryRangeParams{FromTime: fromTime, ToTime: toTime})
+		case services.PayloadVolumes:
+			deleted, err = queries.DeleteVolumeHistoryRange(ctx, generated.DeleteVolumeHistoryRangeParams{FromTime: fromTime, ToTime: toTime})
+		default:
+			continue
+		}
+		if err != nil {
+			log.Fatal().Err(err).Str("kind", kind).Msg("Failed to reset history")
+		}
+		log.Info().Str("kind", kind).Int64("deleted", deleted).Msg("History reset")
+	}
+	return replacedOddsID
+}
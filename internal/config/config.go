@@ -9,11 +9,18 @@ type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
 	External ExternalAPIConfig
+	Iddaa    IddaaConfig
+	Archive  ArchiveConfig
+	History  HistoryConfig
+	Ingest   IngestConfig
+	Jobs     JobsConfig
 }
 
 type ServerConfig struct {
 	Port string
 	Host string
+	// AdminAPIKey guards administrative endpoints such as user creation
+	AdminAPIKey string
 }
 
 type DatabaseConfig struct {
@@ -31,11 +38,70 @@ type ExternalAPIConfig struct {
 	Timeout int
 }
 
+// IddaaConfig controls the Iddaa client transport: fixture recording and replay, retries,
+// per-host rate budgets and the circuit breaker. Zero values use the client defaults.
+type IddaaConfig struct {
+	// MockURL sends every Iddaa request of cmd/cron to a cmd/iddaa-mock server instead of the real hosts
+	MockURL string
+	// RecordDir saves every Iddaa response of cmd/cron as a fixture file in this directory
+	RecordDir string
+	// MaxRetries for 429, 5xx and network errors, negative disables retries
+	MaxRetries int
+	// Requests per second allowed against sportsbookv2, contentv2 and statisticsv2
+	SportsbookRPS int
+	ContentRPS    int
+	StatisticsRPS int
+	// Consecutive failures that open a host's circuit breaker and the seconds it stays open
+	BreakerThreshold int
+	BreakerCooldown  int
+}
+
+// ArchiveConfig controls the raw payload archive used by cmd/reprocess
+type ArchiveConfig struct {
+	// Dir stores every fetched upstream payload when set
+	Dir string
+}
+
+// HistoryConfig controls the monthly partitions of odds_history, outcome_distribution_history
+// and betting_volume_history kept by the partition maintenance job
+type HistoryConfig struct {
+	// Months of history kept per table, 0 keeps every partition
+	OddsRetentionMonths         int
+	DistributionRetentionMonths int
+	VolumeRetentionMonths       int
+	// ArchiveDir receives a gzipped CSV export of every partition before it is dropped;
+	// partitions are never dropped while it is unset
+	ArchiveDir string
+	// PremakeMonths of future partitions created ahead of time
+	PremakeMonths int
+}
+
+// IngestConfig selects how each odds job writes current_odds and odds_history:
+// "unnest" sends parallel arrays through UNNEST, "copy" streams rows with COPY into staging tables
+type IngestConfig struct {
+	Events       string
+	DetailedOdds string
+	LiveOdds     string
+	// OddsCache keeps current odds in memory for change detection instead of reading them before each write
+	OddsCache bool
+}
+
+// JobsConfig controls the job run history kept in job_runs and the runtime job controls
+type JobsConfig struct {
+	// InstanceID tags the runs of this cron process, hostname-pid when empty
+	InstanceID string
+	// RunRetentionDays of job runs and triggers kept, 0 keeps every run
+	RunRetentionDays int
+	// ControlInterval in seconds between polls of job controls and triggers, 0 disables them
+	ControlInterval int
+}
+
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port: getEnv("PORT", "8080"),
-			Host: getEnv("HOST", "localhost"),
+			Port:        getEnv("PORT", "8080"),
+			Host:        getEnv("HOST", "localhost"),
+			AdminAPIKey: getEnv("ADMIN_API_KEY", ""),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -50,6 +116,38 @@ func Load() *Config {
 			APIKey:  getEnv("EXTERNAL_API_KEY", ""),
 			Timeout: getEnvAsInt("EXTERNAL_API_TIMEOUT", 90),
 		},
+		Iddaa: IddaaConfig{
+			MockURL:   getEnv("IDDAA_MOCK_URL", ""),
+			RecordDir: getEnv("IDDAA_RECORD_DIR", ""),
+
+			MaxRetries:       getEnvAsInt("IDDAA_MAX_RETRIES", 0),
+			SportsbookRPS:    getEnvAsInt("IDDAA_SPORTSBOOK_RPS", 0),
+			ContentRPS:       getEnvAsInt("IDDAA_CONTENT_RPS", 0),
+			StatisticsRPS:    getEnvAsInt("IDDAA_STATISTICS_RPS", 0),
+			BreakerThreshold: getEnvAsInt("IDDAA_BREAKER_THRESHOLD", 0),
+			BreakerCooldown:  getEnvAsInt("IDDAA_BREAKER_COOLDOWN", 0),
+		},
+		Archive: ArchiveConfig{
+			Dir: getEnv("PAYLOAD_ARCHIVE_DIR", ""),
+		},
+		History: HistoryConfig{
+			OddsRetentionMonths:         getEnvAsInt("ODDS_HISTORY_RETENTION_MONTHS", 6),
+			DistributionRetentionMonths: getEnvAsInt("DISTRIBUTION_HISTORY_RETENTION_MONTHS", 6),
+			VolumeRetentionMonths:       getEnvAsInt("VOLUME_HISTORY_RETENTION_MONTHS", 12),
+			ArchiveDir:                  getEnv("HISTORY_ARCHIVE_DIR", ""),
+			PremakeMonths:               getEnvAsInt("HISTORY_PREMAKE_MONTHS", 3),
+		},
+		Ingest: IngestConfig{
+			Events:       getEnv("EVENTS_ODDS_INGEST", "unnest"),
+			DetailedOdds: getEnv("DETAILED_ODDS_INGEST", "unnest"),
+			LiveOdds:     getEnv("LIVE_ODDS_INGEST", "unnest"),
+			OddsCache:    getEnvAsBool("ODDS_CACHE", true),
+		},
+		Jobs: JobsConfig{
+			InstanceID:       getEnv("CRON_INSTANCE_ID", ""),
+			RunRetentionDays: getEnvAsInt("JOB_RUNS_RETENTION_DAYS", 30),
+			ControlInterval:  getEnvAsInt("JOB_CONTROL_INTERVAL", 5),
+		},
 	}
 }
 
@@ -69,6 +167,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func (c *Config) DatabaseURL() string {
 	// If DATABASE_URL is set, use it directly
 	if databaseURL := os.Getenv("DATABASE_URL"); databaseURL != "" {
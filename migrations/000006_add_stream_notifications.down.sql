@@ -0,0 +1,6 @@
+-- Remove stream notification triggers
+DROP TRIGGER IF EXISTS movement_alerts_notify ON movement_alerts;
+DROP TRIGGER IF EXISTS odds_history_notify ON odds_history;
+
+DROP FUNCTION IF EXISTS notify_movement_alert_inserted();
+DROP FUNCTION IF EXISTS notify_odds_history_inserted();
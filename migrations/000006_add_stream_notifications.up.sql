@@ -0,0 +1,68 @@
+-- Publish new odds_history and movement_alerts rows over LISTEN/NOTIFY
+-- The cron writers stay unaware of the API; any insert is picked up by /api/stream
+-- Statement level triggers with transition tables keep bulk inserts to a single pass
+-- ====================
+-- ODDS HISTORY
+-- ====================
+CREATE
+OR REPLACE FUNCTION notify_odds_history_inserted() RETURNS TRIGGER AS $$ BEGIN
+PERFORM pg_notify(
+    'odds_history_inserted',
+    json_build_object(
+        'type', 'odds',
+        'event_id', n.event_id,
+        'event_slug', e.slug,
+        'sport_code', s.code,
+        'league_id', e.league_id,
+        'data', row_to_json(n)
+    ) :: text
+)
+FROM
+    new_rows n
+    JOIN events e ON e.id = n.event_id
+    LEFT JOIN sports s ON s.id = e.sport_id;
+
+RETURN NULL;
+
+END;
+
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER odds_history_notify
+AFTER
+INSERT
+    ON odds_history REFERENCING NEW TABLE AS new_rows FOR EACH STATEMENT EXECUTE FUNCTION notify_odds_history_inserted();
+
+-- ====================
+-- MOVEMENT ALERTS
+-- ====================
+CREATE
+OR REPLACE FUNCTION notify_movement_alert_inserted() RETURNS TRIGGER AS $$ BEGIN
+PERFORM pg_notify(
+    'movement_alert_inserted',
+    json_build_object(
+        'type', 'alert',
+        'event_id', oh.event_id,
+        'event_slug', e.slug,
+        'sport_code', s.code,
+        'league_id', e.league_id,
+        'alert_type', n.alert_type,
+        'data', row_to_json(n)
+    ) :: text
+)
+FROM
+    new_rows n
+    JOIN odds_history oh ON oh.id = n.odds_history_id
+    JOIN events e ON e.id = oh.event_id
+    LEFT JOIN sports s ON s.id = e.sport_id;
+
+RETURN NULL;
+
+END;
+
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER movement_alerts_notify
+AFTER
+INSERT
+    ON movement_alerts REFERENCING NEW TABLE AS new_rows FOR EACH STATEMENT EXECUTE FUNCTION notify_movement_alert_inserted();
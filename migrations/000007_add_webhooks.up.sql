@@ -0,0 +1,91 @@
+-- Webhook delivery for movement alerts
+-- ====================
+-- SUBSCRIPTIONS
+-- ====================
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    url TEXT NOT NULL,
+    -- Shared secret used for the HMAC-SHA256 signature
+    secret VARCHAR(255) NOT NULL,
+    -- Filters, empty arrays match everything
+    alert_types TEXT [] NOT NULL DEFAULT '{}',
+    severities TEXT [] NOT NULL DEFAULT '{}',
+    sport_codes TEXT [] NOT NULL DEFAULT '{}',
+    min_confidence REAL NOT NULL DEFAULT 0 CHECK (
+        min_confidence >= 0
+        AND min_confidence <= 1
+    ),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_active ON webhook_subscriptions(is_active)
+WHERE
+    is_active = true;
+
+-- ====================
+-- DELIVERIES
+-- ====================
+-- One row per subscription and alert, retried until delivered or dead
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    alert_id INTEGER NOT NULL REFERENCES movement_alerts(id) ON DELETE CASCADE,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (
+        status IN ('pending', 'retrying', 'delivered', 'dead')
+    ),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(subscription_id, alert_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at)
+WHERE
+    status IN ('pending', 'retrying');
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, created_at DESC);
+
+-- Every HTTP attempt is kept as delivery history
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id SERIAL PRIMARY KEY,
+    delivery_id INTEGER NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL,
+    response_status INTEGER,
+    error TEXT,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    attempted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery ON webhook_delivery_attempts(delivery_id, attempt);
+
+-- ====================
+-- DEAD LETTERS
+-- ====================
+-- Deliveries that exhausted their retries
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    id SERIAL PRIMARY KEY,
+    delivery_id INTEGER NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    alert_id INTEGER NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(delivery_id)
+);
+
+CREATE TRIGGER update_webhook_subscriptions_updated_at BEFORE
+UPDATE
+    ON webhook_subscriptions FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+CREATE TRIGGER update_webhook_deliveries_updated_at BEFORE
+UPDATE
+    ON webhook_deliveries FOR EACH ROW EXECUTE FUNCTION update_updated_at();
@@ -0,0 +1,8 @@
+-- Remove users, API keys and preferences
+DROP TRIGGER IF EXISTS update_smart_money_preferences_updated_at ON smart_money_preferences;
+DROP TRIGGER IF EXISTS update_users_updated_at ON users;
+
+DROP TABLE IF EXISTS user_alert_interactions;
+DROP TABLE IF EXISTS smart_money_preferences;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,92 @@
+-- Users, API keys and per-user smart money preferences
+-- ====================
+-- USERS
+-- ====================
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(255) UNIQUE NOT NULL,
+    name VARCHAR(255),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ====================
+-- API KEYS
+-- ====================
+-- Only the SHA-256 hash of a key is stored, the prefix is kept for display
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL DEFAULT 'default',
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) UNIQUE NOT NULL,
+    last_used_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys(user_id);
+
+-- ====================
+-- SMART MONEY PREFERENCES
+-- ====================
+CREATE TABLE IF NOT EXISTS smart_money_preferences (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER UNIQUE NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- Thresholds
+    min_change_percentage REAL NOT NULL DEFAULT 10,
+    min_multiplier DOUBLE PRECISION NOT NULL DEFAULT 1,
+    min_confidence_score REAL NOT NULL DEFAULT 0.5 CHECK (
+        min_confidence_score >= 0
+        AND min_confidence_score <= 1
+    ),
+    -- Alert types
+    big_mover_alerts BOOLEAN NOT NULL DEFAULT true,
+    reverse_line_alerts BOOLEAN NOT NULL DEFAULT true,
+    sharp_money_alerts BOOLEAN NOT NULL DEFAULT true,
+    value_spot_alerts BOOLEAN NOT NULL DEFAULT true,
+    -- Followed sports and leagues, empty arrays follow everything
+    preferred_sports INTEGER [] NOT NULL DEFAULT '{}',
+    preferred_leagues INTEGER [] NOT NULL DEFAULT '{}',
+    -- Notification settings
+    max_daily_alerts INTEGER NOT NULL DEFAULT 50,
+    push_notifications BOOLEAN NOT NULL DEFAULT false,
+    quiet_hours_start INTEGER CHECK (
+        quiet_hours_start >= 0
+        AND quiet_hours_start <= 23
+    ),
+    quiet_hours_end INTEGER CHECK (
+        quiet_hours_end >= 0
+        AND quiet_hours_end <= 23
+    ),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ====================
+-- ALERT INTERACTIONS
+-- ====================
+-- Per-user engagement, movement_alerts.views/clicks count distinct users
+CREATE TABLE IF NOT EXISTS user_alert_interactions (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    alert_id INTEGER NOT NULL REFERENCES movement_alerts(id) ON DELETE CASCADE,
+    view_count INTEGER NOT NULL DEFAULT 0,
+    click_count INTEGER NOT NULL DEFAULT 0,
+    first_viewed_at TIMESTAMP,
+    last_viewed_at TIMESTAMP,
+    first_clicked_at TIMESTAMP,
+    last_clicked_at TIMESTAMP,
+    PRIMARY KEY (user_id, alert_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_alert_interactions_alert ON user_alert_interactions(alert_id);
+
+CREATE TRIGGER update_users_updated_at BEFORE
+UPDATE
+    ON users FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+CREATE TRIGGER update_smart_money_preferences_updated_at BEFORE
+UPDATE
+    ON smart_money_preferences FOR EACH ROW EXECUTE FUNCTION update_updated_at();
@@ -0,0 +1,3 @@
+-- Remove closing line tables
+DROP TABLE IF EXISTS closing_line_values;
+DROP TABLE IF EXISTS closing_odds;
@@ -0,0 +1,39 @@
+-- Closing lines and closing line value (CLV)
+-- ====================
+-- CLOSING ODDS
+-- ====================
+-- Last pre-kickoff price per market/outcome, frozen once the event finishes
+CREATE TABLE IF NOT EXISTS closing_odds (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    market_type_id INTEGER NOT NULL REFERENCES market_types(id),
+    outcome VARCHAR(100) NOT NULL,
+    market_params JSONB,
+    opening_value DOUBLE PRECISION,
+    closing_value DOUBLE PRECISION NOT NULL CHECK (closing_value > 0),
+    -- When the closing price was observed
+    closing_recorded_at TIMESTAMP NOT NULL,
+    frozen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(event_id, market_type_id, outcome)
+);
+
+CREATE INDEX IF NOT EXISTS idx_closing_odds_event ON closing_odds(event_id);
+
+-- ====================
+-- CLOSING LINE VALUE
+-- ====================
+-- CLV of each pre-kickoff odds_history snapshot against the frozen close
+CREATE TABLE IF NOT EXISTS closing_line_values (
+    odds_history_id INTEGER PRIMARY KEY REFERENCES odds_history(id) ON DELETE CASCADE,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    closing_odds_id INTEGER NOT NULL REFERENCES closing_odds(id) ON DELETE CASCADE,
+    odds_value DOUBLE PRECISION NOT NULL,
+    closing_value DOUBLE PRECISION NOT NULL,
+    -- (odds / closing - 1) * 100, positive means the price beat the close
+    clv_percentage REAL NOT NULL,
+    -- Implied probability edge in percentage points: (1/closing - 1/odds) * 100
+    clv_probability REAL NOT NULL,
+    computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_closing_line_values_event ON closing_line_values(event_id);
@@ -0,0 +1,3 @@
+-- Remove settlement tables
+DROP TABLE IF EXISTS outcome_settlements;
+DROP TABLE IF EXISTS event_results;
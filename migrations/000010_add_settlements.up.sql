@@ -0,0 +1,39 @@
+-- Final scores and outcome settlement
+-- ====================
+-- EVENT RESULTS
+-- ====================
+-- Final scores parsed from the statistics feed, the input to settlement
+CREATE TABLE IF NOT EXISTS event_results (
+    event_id INTEGER PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+    home_score INTEGER NOT NULL,
+    away_score INTEGER NOT NULL,
+    ht_home_score INTEGER,
+    ht_away_score INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- Only bumped when a score changes, so corrections trigger a re-settle
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    settled_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_results_unsettled ON event_results(updated_at)
+WHERE
+    settled_at IS NULL;
+
+-- ====================
+-- OUTCOME SETTLEMENTS
+-- ====================
+CREATE TABLE IF NOT EXISTS outcome_settlements (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    market_type_id INTEGER NOT NULL REFERENCES market_types(id),
+    outcome VARCHAR(100) NOT NULL,
+    -- Market family used to grade the outcome, e.g. 1x2, over_under
+    market_family VARCHAR(30) NOT NULL,
+    result VARCHAR(10) NOT NULL CHECK (
+        result IN ('won', 'lost', 'void', 'push')
+    ),
+    settled_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(event_id, market_type_id, outcome)
+);
+
+CREATE INDEX IF NOT EXISTS idx_outcome_settlements_event ON outcome_settlements(event_id);
@@ -0,0 +1,2 @@
+-- Remove alert performance tracking
+DROP TABLE IF EXISTS alert_results;
@@ -0,0 +1,19 @@
+-- Alert performance tracking
+-- ====================
+-- ALERT RESULTS
+-- ====================
+-- Each movement alert graded against the settled result of its flagged outcome
+-- Profits are for a 1 unit stake: won = odds - 1, lost = -1, void/push = 0
+CREATE TABLE IF NOT EXISTS alert_results (
+    alert_id INTEGER PRIMARY KEY REFERENCES movement_alerts(id) ON DELETE CASCADE,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    settlement_id INTEGER NOT NULL REFERENCES outcome_settlements(id) ON DELETE CASCADE,
+    result VARCHAR(10) NOT NULL CHECK (result IN ('won', 'lost', 'void', 'push')),
+    alert_odds DOUBLE PRECISION NOT NULL,
+    closing_odds DOUBLE PRECISION,
+    profit_at_alert DOUBLE PRECISION NOT NULL,
+    profit_at_close DOUBLE PRECISION,
+    graded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_results_event ON alert_results(event_id);
@@ -0,0 +1,15 @@
+-- Remove rule-based smart money detection
+-- NOT VALID keeps alerts created by custom rules
+ALTER TABLE movement_alerts
+ADD CONSTRAINT movement_alerts_alert_type_check CHECK (
+        alert_type IN (
+            'big_mover',
+            'reverse_line',
+            'sharp_money',
+            'value_spot',
+            'steam_move'
+        )
+    ) NOT VALID;
+
+DROP TRIGGER IF EXISTS update_smart_money_rules_updated_at ON smart_money_rules;
+DROP TABLE IF EXISTS smart_money_rules;
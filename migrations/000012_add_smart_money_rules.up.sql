@@ -0,0 +1,186 @@
+-- Rule-based smart money detection
+-- ====================
+-- SMART MONEY RULES
+-- ====================
+-- Each active rule is evaluated against recent odds movements by the smart money processor.
+-- Conditions are optional and strict (value > min, value < max); NULL means not applied.
+CREATE TABLE IF NOT EXISTS smart_money_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    -- Lower priority rules are evaluated first, the first match per alert type wins
+    priority INTEGER NOT NULL DEFAULT 100,
+    -- Conditions
+    min_change_pct REAL,
+    max_change_pct REAL,
+    min_abs_change_pct REAL,
+    min_multiplier DOUBLE PRECISION,
+    max_multiplier DOUBLE PRECISION,
+    min_bet_pct REAL,
+    max_bet_pct REAL,
+    -- Public bet percentage minus implied probability
+    min_public_bias REAL,
+    -- Volume rank 1 is the most bet event
+    min_volume_rank INTEGER,
+    max_volume_rank INTEGER,
+    max_volume_pct REAL,
+    min_minutes_to_kickoff INTEGER,
+    max_minutes_to_kickoff INTEGER,
+    min_sharp_score INTEGER,
+    min_moves_last_hour INTEGER,
+    -- Alert mapping
+    alert_type VARCHAR(50) NOT NULL CHECK (alert_type ~ '^[a-z][a-z0-9_]*$'),
+    -- NULL derives severity from confidence
+    severity VARCHAR(20) CHECK (
+        severity IN ('low', 'medium', 'high', 'critical')
+    ),
+    confidence_source VARCHAR(30) NOT NULL DEFAULT 'fixed' CHECK (
+        confidence_source IN (
+            'fixed',
+            'sharp_score',
+            'reverse_strength',
+            'public_bias',
+            'moves'
+        )
+    ),
+    base_confidence REAL NOT NULL DEFAULT 0.5 CHECK (
+        base_confidence >= 0
+        AND base_confidence <= 1
+    ),
+    -- Go text/template strings, see services.RuleTemplateData for fields
+    title_template TEXT NOT NULL,
+    message_template TEXT NOT NULL,
+    -- Strongest matches kept per run
+    max_alerts INTEGER NOT NULL DEFAULT 50 CHECK (max_alerts > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_smart_money_rules_updated_at BEFORE
+UPDATE
+    ON smart_money_rules FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+-- Rules may introduce new alert types
+ALTER TABLE movement_alerts DROP CONSTRAINT IF EXISTS movement_alerts_alert_type_check;
+
+-- ====================
+-- DEFAULT RULES
+-- ====================
+-- Equivalent to the previously hard-coded detection
+INSERT INTO
+    smart_money_rules (
+        name,
+        description,
+        priority,
+        min_change_pct,
+        max_change_pct,
+        min_abs_change_pct,
+        min_bet_pct,
+        max_bet_pct,
+        min_public_bias,
+        min_sharp_score,
+        min_moves_last_hour,
+        alert_type,
+        severity,
+        confidence_source,
+        title_template,
+        message_template,
+        max_alerts
+    )
+VALUES
+    (
+        'reverse_line_public_heavy',
+        'Public betting heavy but odds getting worse',
+        10,
+        5,
+        NULL,
+        NULL,
+        65,
+        NULL,
+        NULL,
+        NULL,
+        NULL,
+        'reverse_line',
+        NULL,
+        'reverse_strength',
+        'Reverse Line Movement: {{.Match}}',
+        '🔄 {{.Match}} - {{.Outcome}}: Public betting heavy but odds getting worse (Public: {{printf "%.0f" .BetPercentage}}%, Strength: {{printf "%.0f" .ReverseStrength}})',
+        100
+    ),
+    (
+        'reverse_line_public_light',
+        'Public avoiding but odds getting better',
+        20,
+        NULL,
+        -5,
+        NULL,
+        NULL,
+        35,
+        NULL,
+        NULL,
+        NULL,
+        'reverse_line',
+        NULL,
+        'reverse_strength',
+        'Reverse Line Movement: {{.Match}}',
+        '🔄 {{.Match}} - {{.Outcome}}: Public avoiding but odds getting better (Public: {{printf "%.0f" .BetPercentage}}%, Strength: {{printf "%.0f" .ReverseStrength}})',
+        100
+    ),
+    (
+        'sharp_money',
+        'Combined reverse movement, volume, timing and size score',
+        30,
+        NULL,
+        NULL,
+        4.99,
+        NULL,
+        NULL,
+        NULL,
+        60,
+        NULL,
+        'sharp_money',
+        NULL,
+        'sharp_score',
+        'Sharp Money Detected: {{.Match}}',
+        '🎯 {{.Match}} - {{.Outcome}} shows sharp activity (Score: {{.SharpScore}}/100){{if .HasBetPercentage}} (Public: {{printf "%.0f" .BetPercentage}}%){{end}}',
+        100
+    ),
+    (
+        'steam_move',
+        'At least three moves of the same outcome within an hour',
+        40,
+        NULL,
+        NULL,
+        2.99,
+        NULL,
+        NULL,
+        NULL,
+        NULL,
+        2,
+        'steam_move',
+        'high',
+        'moves',
+        'Steam Move: {{.Match}}',
+        '⚡ {{.Match}} - {{.Outcome}} moving rapidly ({{.MovesLastHour}} moves/hour)',
+        50
+    ),
+    (
+        'value_spot',
+        'Public overbetting an outcome relative to its implied probability',
+        50,
+        NULL,
+        NULL,
+        4.99,
+        NULL,
+        NULL,
+        15,
+        NULL,
+        NULL,
+        'value_spot',
+        NULL,
+        'public_bias',
+        'Value Spot: {{.Match}}',
+        '💰 {{.Match}} - {{.Outcome}} overbet by public (Bet: {{printf "%.0f" .BetPercentage}}%, Fair: {{printf "%.0f" .ImpliedProbability}}%)',
+        50
+    ) ON CONFLICT (name) DO NOTHING;
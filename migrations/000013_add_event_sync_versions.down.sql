@@ -0,0 +1,3 @@
+-- Remove delta event sync state
+DROP TRIGGER IF EXISTS update_event_sync_versions_updated_at ON event_sync_versions;
+DROP TABLE IF EXISTS event_sync_versions;
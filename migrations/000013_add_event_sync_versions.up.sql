@@ -0,0 +1,20 @@
+-- Delta event sync
+-- ====================
+-- EVENT SYNC VERSIONS
+-- ====================
+-- Last bulletin version applied per sport. The events sync job asks Iddaa for the
+-- diff since this version and falls back to a full bulletin when it cannot.
+CREATE TABLE IF NOT EXISTS event_sync_versions (
+    sport_id INTEGER PRIMARY KEY REFERENCES sports(id) ON DELETE CASCADE,
+    version BIGINT NOT NULL DEFAULT 0,
+    last_synced_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_full_sync_at TIMESTAMP,
+    -- Diffs applied since the last full bulletin
+    diffs_since_full INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_event_sync_versions_updated_at BEFORE
+UPDATE
+    ON event_sync_versions FOR EACH ROW EXECUTE FUNCTION update_updated_at();
@@ -0,0 +1,14 @@
+-- Remove live odds capture
+DROP INDEX IF EXISTS idx_current_odds_suspended;
+
+ALTER TABLE current_odds
+DROP COLUMN IF EXISTS suspended_at,
+DROP COLUMN IF EXISTS is_suspended;
+
+DROP INDEX IF EXISTS idx_odds_history_in_play;
+
+ALTER TABLE odds_history
+DROP COLUMN IF EXISTS live_minute,
+DROP COLUMN IF EXISTS live_away_score,
+DROP COLUMN IF EXISTS live_home_score,
+DROP COLUMN IF EXISTS in_play;
@@ -0,0 +1,26 @@
+-- Live odds capture
+-- ====================
+-- ODDS HISTORY: IN-PLAY CONTEXT
+-- ====================
+-- Rows written by the live odds worker carry the score and match minute at the time of the change
+ALTER TABLE odds_history
+ADD COLUMN IF NOT EXISTS in_play BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN IF NOT EXISTS live_home_score INTEGER,
+ADD COLUMN IF NOT EXISTS live_away_score INTEGER,
+ADD COLUMN IF NOT EXISTS live_minute INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_in_play ON odds_history(event_id, recorded_at)
+WHERE
+    in_play;
+
+-- ====================
+-- CURRENT ODDS: MARKET SUSPENSION
+-- ====================
+-- Set while Iddaa reports the market as not open for betting
+ALTER TABLE current_odds
+ADD COLUMN IF NOT EXISTS is_suspended BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_current_odds_suspended ON current_odds(event_id)
+WHERE
+    is_suspended;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS fair_probabilities;
+
+DROP TABLE IF EXISTS market_margin_history;
+
+DROP TRIGGER IF EXISTS update_market_margins_updated_at ON market_margins;
+
+DROP TABLE IF EXISTS market_margins;
@@ -0,0 +1,77 @@
+-- Bookmaker margins and fair probabilities
+-- ====================
+-- MARKET MARGINS
+-- ====================
+-- One row per priced market: an event's market type at one bookmaker, split by market_key
+-- (the market parameters, e.g. the goal line) since one market type can be offered at several lines.
+CREATE TABLE IF NOT EXISTS market_margins (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    market_type_id INTEGER NOT NULL REFERENCES market_types(id),
+    bookmaker VARCHAR(50) NOT NULL DEFAULT 'iddaa' REFERENCES bookmakers(code),
+    market_key VARCHAR(100) NOT NULL DEFAULT '',
+    outcome_count INTEGER NOT NULL,
+    -- Sum of 1/odds over the market's outcomes
+    booksum DOUBLE PRECISION NOT NULL,
+    -- Overround in percent: (booksum - 1) * 100
+    margin_percentage REAL NOT NULL,
+    -- Exponent of the power method and insider share of the Shin method, NULL when not solvable
+    power_k DOUBLE PRECISION,
+    shin_z DOUBLE PRECISION,
+    calculated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (event_id, market_type_id, bookmaker, market_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_market_margins_market_type ON market_margins(market_type_id, bookmaker);
+
+CREATE TRIGGER update_market_margins_updated_at BEFORE
+UPDATE
+    ON market_margins FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+-- ====================
+-- MARKET MARGIN HISTORY
+-- ====================
+-- Appended whenever a market's margin changes
+CREATE TABLE IF NOT EXISTS market_margin_history (
+    id BIGSERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    market_type_id INTEGER NOT NULL REFERENCES market_types(id),
+    bookmaker VARCHAR(50) NOT NULL DEFAULT 'iddaa',
+    market_key VARCHAR(100) NOT NULL DEFAULT '',
+    outcome_count INTEGER NOT NULL,
+    booksum DOUBLE PRECISION NOT NULL,
+    margin_percentage REAL NOT NULL,
+    previous_margin REAL,
+    recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_market_margin_history_event ON market_margin_history(event_id, recorded_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_market_margin_history_market_type ON market_margin_history(market_type_id, recorded_at DESC);
+
+-- ====================
+-- FAIR PROBABILITIES
+-- ====================
+-- Margin-free probabilities of each outcome in percent, one column per de-vig method.
+-- Additive, power and Shin are NULL when the method has no valid solution for the market.
+CREATE TABLE IF NOT EXISTS fair_probabilities (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    market_type_id INTEGER NOT NULL REFERENCES market_types(id),
+    bookmaker VARCHAR(50) NOT NULL DEFAULT 'iddaa' REFERENCES bookmakers(code),
+    market_key VARCHAR(100) NOT NULL DEFAULT '',
+    outcome VARCHAR(100) NOT NULL,
+    odds_value DOUBLE PRECISION NOT NULL,
+    -- Raw 1/odds in percent, including the margin
+    implied_probability REAL NOT NULL,
+    multiplicative REAL NOT NULL,
+    additive REAL,
+    power REAL,
+    shin REAL,
+    -- 1 / fair probability of the default method (Shin, multiplicative when Shin has no solution)
+    fair_odds DOUBLE PRECISION NOT NULL,
+    calculated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (event_id, market_type_id, outcome, bookmaker)
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS odds_candles_1d;
+
+DROP TABLE IF EXISTS odds_candles_1h;
+
+DROP TABLE IF EXISTS odds_candles_5m;
@@ -0,0 +1,99 @@
+-- OHLC odds candles rolled up from odds_history at 5-minute, 1-hour and 1-day resolutions
+-- Candles are keyed like market_margins: market_key holds the market parameters joined with '|',
+-- so every line of a market type (e.g. each over/under goal line) has its own series.
+
+-- ====================
+-- ODDS CANDLES 5M
+-- ====================
+-- 5-minute candles, rolled up from odds_history
+CREATE TABLE IF NOT EXISTS odds_candles_5m (
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    market_type_id INTEGER NOT NULL REFERENCES market_types(id),
+    bookmaker VARCHAR(50) NOT NULL DEFAULT 'iddaa',
+    market_key VARCHAR(100) NOT NULL DEFAULT '',
+    outcome VARCHAR(100) NOT NULL,
+    -- Start of the candle, aligned to the resolution in UTC
+    bucket_start TIMESTAMP NOT NULL,
+    open_odds DOUBLE PRECISION NOT NULL,
+    high_odds DOUBLE PRECISION NOT NULL,
+    low_odds DOUBLE PRECISION NOT NULL,
+    close_odds DOUBLE PRECISION NOT NULL,
+    -- Number of odds_history rows in the candle
+    tick_count INTEGER NOT NULL,
+    first_recorded_at TIMESTAMP NOT NULL,
+    last_recorded_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (
+        event_id,
+        bookmaker,
+        market_type_id,
+        market_key,
+        outcome,
+        bucket_start
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_odds_candles_5m_bucket ON odds_candles_5m(bucket_start);
+
+-- ====================
+-- ODDS CANDLES 1H
+-- ====================
+-- Hourly candles, rolled up from odds_candles_5m
+CREATE TABLE IF NOT EXISTS odds_candles_1h (
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    market_type_id INTEGER NOT NULL REFERENCES market_types(id),
+    bookmaker VARCHAR(50) NOT NULL DEFAULT 'iddaa',
+    market_key VARCHAR(100) NOT NULL DEFAULT '',
+    outcome VARCHAR(100) NOT NULL,
+    -- Start of the candle, aligned to the resolution in UTC
+    bucket_start TIMESTAMP NOT NULL,
+    open_odds DOUBLE PRECISION NOT NULL,
+    high_odds DOUBLE PRECISION NOT NULL,
+    low_odds DOUBLE PRECISION NOT NULL,
+    close_odds DOUBLE PRECISION NOT NULL,
+    -- Number of odds_history rows in the candle
+    tick_count INTEGER NOT NULL,
+    first_recorded_at TIMESTAMP NOT NULL,
+    last_recorded_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (
+        event_id,
+        bookmaker,
+        market_type_id,
+        market_key,
+        outcome,
+        bucket_start
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_odds_candles_1h_bucket ON odds_candles_1h(bucket_start);
+
+-- ====================
+-- ODDS CANDLES 1D
+-- ====================
+-- Daily candles, rolled up from odds_candles_1h
+CREATE TABLE IF NOT EXISTS odds_candles_1d (
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    market_type_id INTEGER NOT NULL REFERENCES market_types(id),
+    bookmaker VARCHAR(50) NOT NULL DEFAULT 'iddaa',
+    market_key VARCHAR(100) NOT NULL DEFAULT '',
+    outcome VARCHAR(100) NOT NULL,
+    -- Start of the candle, aligned to the resolution in UTC
+    bucket_start TIMESTAMP NOT NULL,
+    open_odds DOUBLE PRECISION NOT NULL,
+    high_odds DOUBLE PRECISION NOT NULL,
+    low_odds DOUBLE PRECISION NOT NULL,
+    close_odds DOUBLE PRECISION NOT NULL,
+    -- Number of odds_history rows in the candle
+    tick_count INTEGER NOT NULL,
+    first_recorded_at TIMESTAMP NOT NULL,
+    last_recorded_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (
+        event_id,
+        bookmaker,
+        market_type_id,
+        market_key,
+        outcome,
+        bucket_start
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_odds_candles_1d_bucket ON odds_candles_1d(bucket_start);
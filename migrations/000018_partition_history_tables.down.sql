@@ -0,0 +1,210 @@
+-- Turn the partitioned history tables back into plain tables, keeping every row still stored
+CREATE
+OR REPLACE FUNCTION unpartition_history_table(table_name TEXT) RETURNS VOID AS $$
+DECLARE
+    partitioned_table TEXT := table_name || '_partitioned';
+BEGIN
+    EXECUTE format('ALTER TABLE %I RENAME TO %I', table_name, partitioned_table);
+    EXECUTE format(
+        'CREATE TABLE %I (LIKE %I INCLUDING DEFAULTS INCLUDING CONSTRAINTS)',
+        table_name,
+        partitioned_table
+    );
+    EXECUTE format('ALTER TABLE %I ALTER COLUMN recorded_at DROP NOT NULL', table_name);
+    EXECUTE format('ALTER SEQUENCE %I OWNED BY %I.id', table_name || '_id_seq', table_name);
+
+    EXECUTE format('INSERT INTO %I SELECT * FROM %I', table_name, partitioned_table);
+    EXECUTE format('DROP TABLE %I', partitioned_table);
+
+    EXECUTE format('ALTER TABLE %I ADD PRIMARY KEY (id)', table_name);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TEMPORARY TABLE saved_history_views AS
+SELECT
+    DISTINCT v.relname::text as view_name,
+    v.relkind = 'm' as is_materialized,
+    pg_get_viewdef(v.oid) as definition
+FROM
+    pg_depend d
+    JOIN pg_rewrite r ON r.oid = d.objid
+    JOIN pg_class v ON v.oid = r.ev_class
+WHERE
+    d.refobjid IN (
+        'odds_history'::regclass,
+        'outcome_distribution_history'::regclass,
+        'betting_volume_history'::regclass
+    )
+    AND v.relkind IN ('m', 'v')
+    AND v.oid <> d.refobjid;
+
+CREATE TEMPORARY TABLE saved_history_view_indexes AS
+SELECT
+    indexdef
+FROM
+    pg_indexes
+WHERE
+    schemaname = current_schema()
+    AND tablename IN (
+        SELECT
+            view_name
+        FROM
+            saved_history_views
+    );
+
+DO $$
+DECLARE
+    v RECORD;
+BEGIN
+    FOR v IN SELECT * FROM saved_history_views LOOP
+        IF v.is_materialized THEN
+            EXECUTE format('DROP MATERIALIZED VIEW IF EXISTS %I', v.view_name);
+        ELSE
+            EXECUTE format('DROP VIEW IF EXISTS %I', v.view_name);
+        END IF;
+    END LOOP;
+END $$;
+
+-- ====================
+-- ODDS HISTORY
+-- ====================
+SELECT
+    unpartition_history_table('odds_history');
+
+ALTER TABLE odds_history
+ADD CONSTRAINT odds_history_event_id_fkey FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE,
+ADD CONSTRAINT odds_history_market_type_id_fkey FOREIGN KEY (market_type_id) REFERENCES market_types(id);
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_event_id ON odds_history(event_id);
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_recorded_at ON odds_history(recorded_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_event_market_outcome ON odds_history(event_id, market_type_id, outcome);
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_sharp_money ON odds_history(sharp_money_indicator DESC)
+WHERE
+    sharp_money_indicator > 0;
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_reverse_movement ON odds_history(is_reverse_movement)
+WHERE
+    is_reverse_movement = true;
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_composite ON odds_history(event_id, market_type_id, outcome, recorded_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_sharp_indicator ON odds_history(sharp_money_indicator DESC)
+WHERE
+    sharp_money_indicator > 0.5;
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_reverse ON odds_history(event_id, is_reverse_movement)
+WHERE
+    is_reverse_movement = true;
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_market_params ON odds_history USING GIN (market_params);
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_event_time ON odds_history(event_id, recorded_at DESC) INCLUDE (
+    market_type_id,
+    outcome,
+    odds_value,
+    change_percentage
+);
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_big_movers ON odds_history(abs(change_percentage) DESC, recorded_at DESC)
+WHERE
+    abs(change_percentage) > 5.0;
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_recent_changes ON odds_history(recorded_at DESC, abs(change_percentage))
+WHERE
+    abs(change_percentage) >= 5.0;
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_reverse_movements ON odds_history(event_id, is_reverse_movement, recorded_at DESC)
+WHERE
+    is_reverse_movement = true;
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_significance ON odds_history(significance_level, recorded_at DESC)
+WHERE
+    significance_level IN ('high', 'extreme');
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_in_play ON odds_history(event_id, recorded_at)
+WHERE
+    in_play;
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_other_bookmakers ON odds_history(event_id, bookmaker, recorded_at DESC)
+WHERE
+    bookmaker <> 'iddaa';
+
+CREATE TRIGGER odds_history_notify
+AFTER
+INSERT
+    ON odds_history REFERENCING NEW TABLE AS new_rows FOR EACH STATEMENT EXECUTE FUNCTION notify_odds_history_inserted();
+
+-- Rows pointing into partitions dropped by retention cannot be referenced again
+DELETE FROM movement_alerts
+WHERE
+    odds_history_id NOT IN (
+        SELECT
+            id
+        FROM
+            odds_history
+    );
+
+DELETE FROM closing_line_values
+WHERE
+    odds_history_id NOT IN (
+        SELECT
+            id
+        FROM
+            odds_history
+    );
+
+ALTER TABLE movement_alerts
+ADD CONSTRAINT movement_alerts_odds_history_id_fkey FOREIGN KEY (odds_history_id) REFERENCES odds_history(id) ON DELETE CASCADE;
+
+ALTER TABLE closing_line_values
+ADD CONSTRAINT closing_line_values_odds_history_id_fkey FOREIGN KEY (odds_history_id) REFERENCES odds_history(id) ON DELETE CASCADE;
+
+-- ====================
+-- OUTCOME DISTRIBUTION HISTORY
+-- ====================
+SELECT
+    unpartition_history_table('outcome_distribution_history');
+
+ALTER TABLE outcome_distribution_history
+ADD CONSTRAINT outcome_distribution_history_event_id_fkey FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE;
+
+-- ====================
+-- BETTING VOLUME HISTORY
+-- ====================
+SELECT
+    unpartition_history_table('betting_volume_history');
+
+ALTER TABLE betting_volume_history
+ADD CONSTRAINT betting_volume_history_event_id_fkey FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_betting_volume_event_id ON betting_volume_history(event_id);
+
+CREATE INDEX IF NOT EXISTS idx_betting_volume_recorded_at ON betting_volume_history(recorded_at DESC);
+
+DROP FUNCTION IF EXISTS unpartition_history_table(TEXT);
+
+DROP FUNCTION IF EXISTS create_history_partitions(TEXT, TIMESTAMP, TIMESTAMP);
+
+DO $$
+DECLARE
+    v RECORD;
+BEGIN
+    FOR v IN SELECT * FROM saved_history_views LOOP
+        IF v.is_materialized THEN
+            EXECUTE format('CREATE MATERIALIZED VIEW %I AS %s', v.view_name, rtrim(v.definition, '; ' || chr(10)));
+        ELSE
+            EXECUTE format('CREATE VIEW %I AS %s', v.view_name, rtrim(v.definition, '; ' || chr(10)));
+        END IF;
+    END LOOP;
+
+    FOR v IN SELECT * FROM saved_history_view_indexes LOOP
+        EXECUTE v.indexdef;
+    END LOOP;
+END $$;
+
+DROP TABLE saved_history_view_indexes;
+
+DROP TABLE saved_history_views;
@@ -0,0 +1,259 @@
+-- Time-range partitioning of the history tables
+-- odds_history, outcome_distribution_history and betting_volume_history become tables partitioned
+-- by month on recorded_at. Existing rows are copied into their monthly partition, ids keep coming
+-- from the same sequences. The partition_maintenance job creates future partitions and archives
+-- partitions past their retention before dropping them.
+-- ====================
+-- PARTITION HELPERS
+-- ====================
+-- Creates the missing monthly partitions of a history table from the month of from_time through
+-- the month of to_time. Partitions are named <table>_pYYYY_MM. Returns the number created.
+CREATE
+OR REPLACE FUNCTION create_history_partitions(
+    parent_table TEXT,
+    from_time TIMESTAMP,
+    to_time TIMESTAMP
+) RETURNS INTEGER AS $$
+DECLARE
+    month_start DATE;
+    partition_name TEXT;
+    created INTEGER := 0;
+BEGIN
+    FOR month_start IN
+        SELECT
+            generate_series(
+                date_trunc('month', from_time),
+                date_trunc('month', to_time),
+                INTERVAL '1 month'
+            )::date
+    LOOP
+        partition_name := format('%s_p%s', parent_table, to_char(month_start, 'YYYY_MM'));
+        IF to_regclass(quote_ident(partition_name)) IS NULL THEN
+            EXECUTE format(
+                'CREATE TABLE %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+                partition_name,
+                parent_table,
+                month_start::timestamp,
+                (month_start + INTERVAL '1 month')::timestamp
+            );
+            created := created + 1;
+        END IF;
+    END LOOP;
+
+    RETURN created;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Replaces a plain history table with a partitioned one holding the same rows. Columns, defaults
+-- (including the id sequence) and CHECK constraints are copied; primary key, foreign keys, indexes
+-- and triggers are added by the caller. Partitions cover every stored month up to 3 months ahead.
+CREATE
+OR REPLACE FUNCTION partition_history_table(table_name TEXT) RETURNS VOID AS $$
+DECLARE
+    legacy_table TEXT := table_name || '_unpartitioned';
+    oldest TIMESTAMP;
+    newest TIMESTAMP;
+BEGIN
+    EXECUTE format('ALTER TABLE %I RENAME TO %I', table_name, legacy_table);
+
+    -- The partition key cannot be NULL, rows without a time are stamped with the migration time
+    EXECUTE format('UPDATE %I SET recorded_at = CURRENT_TIMESTAMP WHERE recorded_at IS NULL', legacy_table);
+
+    EXECUTE format(
+        'CREATE TABLE %I (LIKE %I INCLUDING DEFAULTS INCLUDING CONSTRAINTS) PARTITION BY RANGE (recorded_at)',
+        table_name,
+        legacy_table
+    );
+    EXECUTE format('ALTER TABLE %I ALTER COLUMN recorded_at SET NOT NULL', table_name);
+    EXECUTE format('ALTER SEQUENCE %I OWNED BY %I.id', table_name || '_id_seq', table_name);
+
+    EXECUTE format('SELECT MIN(recorded_at), MAX(recorded_at) FROM %I', legacy_table) INTO oldest, newest;
+    PERFORM create_history_partitions(
+        table_name,
+        COALESCE(oldest, CURRENT_TIMESTAMP::timestamp),
+        GREATEST(COALESCE(newest, CURRENT_TIMESTAMP::timestamp), (CURRENT_TIMESTAMP + INTERVAL '3 months')::timestamp)
+    );
+
+    EXECUTE format('INSERT INTO %I SELECT * FROM %I', table_name, legacy_table);
+    EXECUTE format('DROP TABLE %I', legacy_table);
+
+    -- The partition key has to be part of the primary key, lookups by id alone still use its index
+    EXECUTE format('ALTER TABLE %I ADD PRIMARY KEY (id, recorded_at)', table_name);
+END;
+$$ LANGUAGE plpgsql;
+
+-- ====================
+-- DEPENDENT VIEWS
+-- ====================
+-- Views reading the history tables are dropped with the old tables and recreated from their saved
+-- definitions once the partitioned tables are in place
+CREATE TEMPORARY TABLE saved_history_views AS
+SELECT
+    DISTINCT v.relname::text as view_name,
+    v.relkind = 'm' as is_materialized,
+    pg_get_viewdef(v.oid) as definition
+FROM
+    pg_depend d
+    JOIN pg_rewrite r ON r.oid = d.objid
+    JOIN pg_class v ON v.oid = r.ev_class
+WHERE
+    d.refobjid IN (
+        'odds_history'::regclass,
+        'outcome_distribution_history'::regclass,
+        'betting_volume_history'::regclass
+    )
+    AND v.relkind IN ('m', 'v')
+    AND v.oid <> d.refobjid;
+
+CREATE TEMPORARY TABLE saved_history_view_indexes AS
+SELECT
+    indexdef
+FROM
+    pg_indexes
+WHERE
+    schemaname = current_schema()
+    AND tablename IN (
+        SELECT
+            view_name
+        FROM
+            saved_history_views
+    );
+
+DO $$
+DECLARE
+    v RECORD;
+BEGIN
+    FOR v IN SELECT * FROM saved_history_views LOOP
+        IF v.is_materialized THEN
+            EXECUTE format('DROP MATERIALIZED VIEW IF EXISTS %I', v.view_name);
+        ELSE
+            EXECUTE format('DROP VIEW IF EXISTS %I', v.view_name);
+        END IF;
+    END LOOP;
+END $$;
+
+-- ====================
+-- ODDS HISTORY REFERENCES
+-- ====================
+-- A foreign key to a partitioned table has to include the partition key. Alerts and CLV rows keep
+-- their odds_history_id; the retention job deletes them together with the partition they point into.
+ALTER TABLE movement_alerts
+DROP CONSTRAINT IF EXISTS movement_alerts_odds_history_id_fkey;
+
+ALTER TABLE closing_line_values
+DROP CONSTRAINT IF EXISTS closing_line_values_odds_history_id_fkey;
+
+-- ====================
+-- ODDS HISTORY
+-- ====================
+SELECT
+    partition_history_table('odds_history');
+
+ALTER TABLE odds_history
+ADD CONSTRAINT odds_history_event_id_fkey FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE,
+ADD CONSTRAINT odds_history_market_type_id_fkey FOREIGN KEY (market_type_id) REFERENCES market_types(id);
+
+-- Indexes are created on every partition. Indexes covered by a wider one on the old table
+-- (event_id, (event_id, market_type_id, outcome), the two reverse movement and sharp indicator
+-- variants) are not recreated, which keeps the 2-minute history inserts cheaper.
+CREATE INDEX IF NOT EXISTS idx_odds_history_recorded_at ON odds_history(recorded_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_event_time ON odds_history(event_id, recorded_at DESC) INCLUDE (
+    market_type_id,
+    outcome,
+    odds_value,
+    change_percentage
+);
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_composite ON odds_history(
+    event_id,
+    market_type_id,
+    outcome,
+    recorded_at DESC
+);
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_big_movers ON odds_history(abs(change_percentage) DESC, recorded_at DESC)
+WHERE
+    abs(change_percentage) > 5.0;
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_recent_changes ON odds_history(recorded_at DESC, abs(change_percentage))
+WHERE
+    abs(change_percentage) >= 5.0;
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_sharp_money ON odds_history(sharp_money_indicator DESC, recorded_at DESC)
+WHERE
+    sharp_money_indicator > 0;
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_reverse_movements ON odds_history(event_id, is_reverse_movement, recorded_at DESC)
+WHERE
+    is_reverse_movement = true;
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_significance ON odds_history(significance_level, recorded_at DESC)
+WHERE
+    significance_level IN ('high', 'extreme');
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_market_params ON odds_history USING GIN (market_params);
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_in_play ON odds_history(event_id, recorded_at)
+WHERE
+    in_play;
+
+CREATE INDEX IF NOT EXISTS idx_odds_history_other_bookmakers ON odds_history(event_id, bookmaker, recorded_at DESC)
+WHERE
+    bookmaker <> 'iddaa';
+
+-- Statement level triggers on a partitioned table see the rows inserted into every partition
+CREATE TRIGGER odds_history_notify
+AFTER
+INSERT
+    ON odds_history REFERENCING NEW TABLE AS new_rows FOR EACH STATEMENT EXECUTE FUNCTION notify_odds_history_inserted();
+
+-- ====================
+-- OUTCOME DISTRIBUTION HISTORY
+-- ====================
+SELECT
+    partition_history_table('outcome_distribution_history');
+
+ALTER TABLE outcome_distribution_history
+ADD CONSTRAINT outcome_distribution_history_event_id_fkey FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_outcome_distribution_history_event ON outcome_distribution_history(event_id, recorded_at DESC);
+
+-- ====================
+-- BETTING VOLUME HISTORY
+-- ====================
+SELECT
+    partition_history_table('betting_volume_history');
+
+ALTER TABLE betting_volume_history
+ADD CONSTRAINT betting_volume_history_event_id_fkey FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_betting_volume_event_id ON betting_volume_history(event_id);
+
+CREATE INDEX IF NOT EXISTS idx_betting_volume_recorded_at ON betting_volume_history(recorded_at DESC);
+
+DROP FUNCTION IF EXISTS partition_history_table(TEXT);
+
+-- ====================
+-- RESTORE VIEWS
+-- ====================
+DO $$
+DECLARE
+    v RECORD;
+BEGIN
+    FOR v IN SELECT * FROM saved_history_views LOOP
+        IF v.is_materialized THEN
+            EXECUTE format('CREATE MATERIALIZED VIEW %I AS %s', v.view_name, rtrim(v.definition, '; ' || chr(10)));
+        ELSE
+            EXECUTE format('CREATE VIEW %I AS %s', v.view_name, rtrim(v.definition, '; ' || chr(10)));
+        END IF;
+    END LOOP;
+
+    FOR v IN SELECT * FROM saved_history_view_indexes LOOP
+        EXECUTE v.indexdef;
+    END LOOP;
+END $$;
+
+DROP TABLE saved_history_view_indexes;
+
+DROP TABLE saved_history_views;
@@ -0,0 +1 @@
+DROP SEQUENCE IF EXISTS current_odds_version_seq;
@@ -0,0 +1,4 @@
+-- Version counter of Iddaa current_odds writes
+-- Every odds sync takes the next value once its write is committed. An in-process odds cache
+-- compares the counter with the writes it applied itself to notice writes by other processes.
+CREATE SEQUENCE IF NOT EXISTS current_odds_version_seq;
@@ -0,0 +1,35 @@
+-- Every cron job execution, recorded by the job managers in cmd/cron
+-- ====================
+-- JOB RUNS
+-- ====================
+CREATE TABLE IF NOT EXISTS job_runs (
+    id BIGSERIAL PRIMARY KEY,
+    job_name VARCHAR(100) NOT NULL,
+    -- Cron process that ran the job, CRON_INSTANCE_ID or hostname-pid
+    instance_id VARCHAR(255) NOT NULL,
+    trigger VARCHAR(20) NOT NULL CHECK (trigger IN ('cron', 'startup', 'manual')),
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (
+        status IN (
+            'running',
+            'succeeded',
+            'failed',
+            'skipped',
+            'abandoned'
+        )
+    ),
+    -- Distributed lock result in production mode, NULL when the job ran without locking
+    lock_outcome VARCHAR(20) CHECK (lock_outcome IN ('acquired', 'busy', 'error')),
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP,
+    -- Items the job reported, NULL for jobs that report none
+    items_processed INTEGER,
+    error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_started ON job_runs(job_name, started_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_started ON job_runs(started_at);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_running ON job_runs(started_at)
+WHERE
+    status = 'running';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS job_triggers;
+
+DROP TABLE IF EXISTS job_controls;
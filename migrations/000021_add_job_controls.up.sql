@@ -0,0 +1,54 @@
+-- Runtime control of the cron jobs through the admin API: every cron instance applies the
+-- pause state and schedule in job_controls and claims manual triggers from job_triggers
+
+-- ====================
+-- JOB CONTROLS
+-- ====================
+-- One row per registered job, upserted by every cron instance at startup
+CREATE TABLE IF NOT EXISTS job_controls (
+    job_name VARCHAR(100) PRIMARY KEY,
+    -- Schedule the job registers with
+    default_schedule VARCHAR(100) NOT NULL,
+    -- Schedule set through the admin API, NULL runs on the default
+    schedule_override VARCHAR(100),
+    paused BOOLEAN NOT NULL DEFAULT false,
+    -- Last time a cron instance registered the job
+    registered_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ====================
+-- JOB TRIGGERS
+-- ====================
+-- Manual run requests, claimed by one cron instance each
+CREATE TABLE IF NOT EXISTS job_triggers (
+    id BIGSERIAL PRIMARY KEY,
+    job_name VARCHAR(100) NOT NULL REFERENCES job_controls(job_name) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (
+        status IN (
+            'pending',
+            'running',
+            'succeeded',
+            'failed',
+            'skipped',
+            'expired'
+        )
+    ),
+    requested_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    claimed_by VARCHAR(255),
+    claimed_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    run_id BIGINT REFERENCES job_runs(id) ON DELETE SET NULL,
+    error TEXT
+);
+
+-- At most one pending run per job, concurrent requests lose the race with a unique violation
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_triggers_one_pending ON job_triggers(job_name)
+WHERE
+    status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_job_triggers_job_requested ON job_triggers(job_name, requested_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_job_triggers_open ON job_triggers(requested_at)
+WHERE
+    status IN ('pending', 'running');
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_webhook_subscriptions_user;
+
+ALTER TABLE webhook_subscriptions DROP COLUMN IF EXISTS user_id;
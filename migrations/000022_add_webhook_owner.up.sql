@@ -0,0 +1,19 @@
+-- Webhook subscriptions belong to the user whose API key created them
+
+-- ====================
+-- SUBSCRIPTION OWNER
+-- ====================
+ALTER TABLE webhook_subscriptions
+ADD COLUMN IF NOT EXISTS user_id INTEGER REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_user ON webhook_subscriptions(user_id, created_at DESC);
+
+-- Subscriptions created before authentication have no owner to manage them and their URLs
+-- were never checked against private addresses, so they stop receiving deliveries
+UPDATE
+    webhook_subscriptions
+SET
+    is_active = false
+WHERE
+    user_id IS NULL
+    AND is_active = true;
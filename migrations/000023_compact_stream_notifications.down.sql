@@ -0,0 +1,52 @@
+-- Restore the full row notifications of 000006 and 000015
+CREATE
+OR REPLACE FUNCTION notify_odds_history_inserted() RETURNS TRIGGER AS $$ BEGIN
+PERFORM pg_notify(
+    'odds_history_inserted',
+    json_build_object(
+        'type', 'odds',
+        'event_id', n.event_id,
+        'event_slug', e.slug,
+        'sport_code', s.code,
+        'league_id', e.league_id,
+        'data', row_to_json(n)
+    ) :: text
+)
+FROM
+    new_rows n
+    JOIN events e ON e.id = n.event_id
+    LEFT JOIN sports s ON s.id = e.sport_id
+WHERE
+    n.bookmaker = 'iddaa';
+
+RETURN NULL;
+
+END;
+
+$$ LANGUAGE plpgsql;
+
+CREATE
+OR REPLACE FUNCTION notify_movement_alert_inserted() RETURNS TRIGGER AS $$ BEGIN
+PERFORM pg_notify(
+    'movement_alert_inserted',
+    json_build_object(
+        'type', 'alert',
+        'event_id', oh.event_id,
+        'event_slug', e.slug,
+        'sport_code', s.code,
+        'league_id', e.league_id,
+        'alert_type', n.alert_type,
+        'data', row_to_json(n)
+    ) :: text
+)
+FROM
+    new_rows n
+    JOIN odds_history oh ON oh.id = n.odds_history_id
+    JOIN events e ON e.id = oh.event_id
+    LEFT JOIN sports s ON s.id = e.sport_id;
+
+RETURN NULL;
+
+END;
+
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,78 @@
+-- Stream notifications carry row ids only, the API loads the rows when a client is connected
+-- Full rows made the writers join events and sports on every insert, and a payload over the
+-- 8000 byte NOTIFY limit raised an error that aborted the insert. Ids are sent in chunks of 500,
+-- well under the limit, and a failing notification no longer reaches the writer.
+
+-- ====================
+-- ODDS HISTORY
+-- ====================
+-- recorded_at bounds let the API prune odds_history partitions when it loads the rows
+CREATE
+OR REPLACE FUNCTION notify_odds_history_inserted() RETURNS TRIGGER AS $$ BEGIN
+PERFORM pg_notify(
+    'odds_history_inserted',
+    json_build_object(
+        'type', 'odds',
+        'ids', array_agg(id ORDER BY id),
+        'from', min(recorded_at),
+        'to', max(recorded_at)
+    ) :: text
+)
+FROM
+    (
+        SELECT
+            id,
+            recorded_at,
+            (row_number() OVER () - 1) / 500 AS chunk
+        FROM
+            new_rows
+        WHERE
+            bookmaker = 'iddaa'
+    ) n
+GROUP BY
+    chunk;
+
+RETURN NULL;
+
+EXCEPTION
+WHEN OTHERS THEN RAISE WARNING 'odds_history notification failed: %', SQLERRM;
+
+RETURN NULL;
+
+END;
+
+$$ LANGUAGE plpgsql;
+
+-- ====================
+-- MOVEMENT ALERTS
+-- ====================
+CREATE
+OR REPLACE FUNCTION notify_movement_alert_inserted() RETURNS TRIGGER AS $$ BEGIN
+PERFORM pg_notify(
+    'movement_alert_inserted',
+    json_build_object(
+        'type', 'alert',
+        'ids', array_agg(id ORDER BY id)
+    ) :: text
+)
+FROM
+    (
+        SELECT
+            id,
+            (row_number() OVER () - 1) / 500 AS chunk
+        FROM
+            new_rows
+    ) n
+GROUP BY
+    chunk;
+
+RETURN NULL;
+
+EXCEPTION
+WHEN OTHERS THEN RAISE WARNING 'movement_alerts notification failed: %', SQLERRM;
+
+RETURN NULL;
+
+END;
+
+$$ LANGUAGE plpgsql;
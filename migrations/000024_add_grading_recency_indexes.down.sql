@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_closing_odds_frozen_at;
+
+DROP INDEX IF EXISTS idx_outcome_settlements_settled_at;
@@ -0,0 +1,8 @@
+-- Alert grading only reads outcomes settled or closed recently
+
+-- ====================
+-- GRADING INDEXES
+-- ====================
+CREATE INDEX IF NOT EXISTS idx_outcome_settlements_settled_at ON outcome_settlements(settled_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_closing_odds_frozen_at ON closing_odds(frozen_at DESC);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS odds_history_delete_dependents ON odds_history;
+
+DROP FUNCTION IF EXISTS delete_odds_history_dependents();
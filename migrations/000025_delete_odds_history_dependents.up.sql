@@ -0,0 +1,59 @@
+-- Alerts and CLV rows lost their foreign key to odds_history when it was partitioned, so deleting
+-- an event left its alerts behind together with their results and webhook deliveries. A delete
+-- trigger on odds_history removes them with their history rows, the event cascade included.
+-- Dropped partitions do not fire it, the retention job deletes their dependents itself.
+
+-- ====================
+-- ODDS HISTORY DEPENDENTS
+-- ====================
+CREATE
+OR REPLACE FUNCTION delete_odds_history_dependents() RETURNS TRIGGER AS $$ BEGIN
+DELETE FROM
+    movement_alerts ma USING old_rows o
+WHERE
+    ma.odds_history_id = o.id;
+
+DELETE FROM
+    closing_line_values clv USING old_rows o
+WHERE
+    clv.odds_history_id = o.id;
+
+RETURN NULL;
+
+END;
+
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS odds_history_delete_dependents ON odds_history;
+
+CREATE TRIGGER odds_history_delete_dependents
+AFTER
+    DELETE ON odds_history REFERENCING OLD TABLE AS old_rows FOR EACH STATEMENT EXECUTE FUNCTION delete_odds_history_dependents();
+
+-- ====================
+-- ORPHANS
+-- ====================
+-- Left behind by events deleted before the trigger existed
+DELETE FROM
+    movement_alerts ma
+WHERE
+    NOT EXISTS (
+        SELECT
+            1
+        FROM
+            odds_history oh
+        WHERE
+            oh.id = ma.odds_history_id
+    );
+
+DELETE FROM
+    closing_line_values clv
+WHERE
+    NOT EXISTS (
+        SELECT
+            1
+        FROM
+            odds_history oh
+        WHERE
+            oh.id = clv.odds_history_id
+    );
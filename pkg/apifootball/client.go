@@ -5,14 +5,18 @@ import (
 	"crypto/md5"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/iddaa-lens/core/pkg/archive"
+	"github.com/iddaa-lens/core/pkg/logger"
 	"github.com/iddaa-lens/core/pkg/models"
 )
 
@@ -127,6 +131,8 @@ type Client struct {
 	baseURL     string
 	rateLimiter *RateLimiter
 	cache       *SimpleCache
+	// Stores every successful payload for reprocessing, nil when archiving is off
+	archive *archive.Archive
 }
 
 // Config holds configuration for the API-Football client
@@ -135,6 +141,8 @@ type Config struct {
 	Timeout        time.Duration
 	RequestsPerMin int
 	BaseURL        string
+	// ArchiveDir enables the raw payload archive
+	ArchiveDir string
 }
 
 // DefaultConfig returns a default configuration
@@ -144,6 +152,7 @@ func DefaultConfig(apiKey string) *Config {
 		Timeout:        30 * time.Second,
 		RequestsPerMin: 60, // API-Football free tier limit
 		BaseURL:        "https://v3.football.api-sports.io",
+		ArchiveDir:     os.Getenv("PAYLOAD_ARCHIVE_DIR"),
 	}
 }
 
@@ -153,7 +162,7 @@ func NewClient(config *Config) *Client {
 		config = DefaultConfig("")
 	}
 
-	return &Client{
+	client := &Client{
 		httpClient: &http.Client{
 			Timeout: config.Timeout,
 		},
@@ -162,6 +171,20 @@ func NewClient(config *Config) *Client {
 		rateLimiter: NewRateLimiter(config.RequestsPerMin),
 		cache:       NewSimpleCache(15 * time.Minute), // Cache responses for 15 minutes
 	}
+
+	if config.ArchiveDir != "" {
+		payloads, err := archive.Shared(config.ArchiveDir)
+		if err != nil {
+			logger.New("api-football-client").Error().
+				Err(err).
+				Str("archive_dir", config.ArchiveDir).
+				Msg("Payload archive disabled")
+		} else {
+			client.archive = payloads
+		}
+	}
+
+	return client
 }
 
 // RateLimiter implements simple rate limiting
@@ -346,9 +369,21 @@ func (c *Client) makeRequest(ctx context.Context, endpoint string, params map[st
 		}
 	}
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Archive the raw payload before decoding; cache hits never reach this point so nothing is stored twice
+	if c.archive != nil {
+		if _, err := c.archive.Put(archive.SourceAPIFootball, u.String(), resp.StatusCode, body, time.Now()); err != nil {
+			logger.New("api-football-client").Warn().Err(err).Str("endpoint", endpoint).Msg("Failed to archive payload")
+		}
+	}
+
 	// Parse response
 	var apiResponse APIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
+	if err := json.Unmarshal(body, &apiResponse); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
@@ -504,6 +539,10 @@ func ParamCode(code string) map[string]string {
 	return map[string]string{"code": code}
 }
 
+func ParamDate(date time.Time) map[string]string {
+	return map[string]string{"date": date.UTC().Format(time.DateOnly)}
+}
+
 // MergeParams merges multiple parameter maps
 func MergeParams(paramMaps ...map[string]string) map[string]string {
 	result := make(map[string]string)
@@ -0,0 +1,64 @@
+package apifootball
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+// Fixture and odds endpoints
+
+// GetFixtures fetches fixtures with various filter options
+func (c *Client) GetFixtures(ctx context.Context, params map[string]string) ([]models.FootballAPIFixtureData, error) {
+	// Use retry logic for rate limit handling (max 3 retries)
+	response, err := c.makeRequestWithRetry(ctx, "/fixtures", params, 3)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fixtures: %w", err)
+	}
+
+	var fixturesData []models.FootballAPIFixtureData
+	if err := json.Unmarshal(response.Response, &fixturesData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fixtures response: %w", err)
+	}
+
+	return fixturesData, nil
+}
+
+// GetFixturesByDate fetches every fixture kicking off on the given UTC day
+func (c *Client) GetFixturesByDate(ctx context.Context, date time.Time) ([]models.FootballAPIFixtureData, error) {
+	return c.GetFixtures(ctx, ParamDate(date))
+}
+
+// GetOdds fetches pre-match odds with various filter options
+func (c *Client) GetOdds(ctx context.Context, params map[string]string) ([]models.FootballAPIOddsData, error) {
+	// Use retry logic for rate limit handling (max 3 retries)
+	response, err := c.makeRequestWithRetry(ctx, "/odds", params, 3)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get odds: %w", err)
+	}
+
+	var oddsData []models.FootballAPIOddsData
+	if err := json.Unmarshal(response.Response, &oddsData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal odds response: %w", err)
+	}
+
+	return oddsData, nil
+}
+
+// GetOddsByFixture fetches the pre-match odds of every bookmaker for one fixture, nil when none are offered
+func (c *Client) GetOddsByFixture(ctx context.Context, fixtureID int) (*models.FootballAPIOddsData, error) {
+	odds, err := c.GetOdds(ctx, map[string]string{"fixture": strconv.Itoa(fixtureID)})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(odds) == 0 {
+		return nil, nil
+	}
+
+	return &odds[0], nil
+}
@@ -0,0 +1,312 @@
+// Package archive keeps every raw upstream payload on local disk so derived tables can be rebuilt later.
+//
+// Payloads are gzip compressed and stored once per content hash under objects/, while index/ holds one
+// JSON line per fetch, in a file per UTC day, recording the endpoint, sport and fetch time.
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Payload sources
+const (
+	SourceIddaa       = "iddaa"
+	SourceAPIFootball = "api-football"
+)
+
+const dayLayout = "2006-01-02"
+
+// Entry is one archived fetch
+type Entry struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Source    string    `json:"source"`
+	// Endpoint is the URL path with numeric segments replaced by {id}, e.g. /sportsbook/event/{id}
+	Endpoint string `json:"endpoint"`
+	SportID  int    `json:"sport_id,omitempty"`
+	URL      string `json:"url"`
+	Status   int    `json:"status"`
+	Hash     string `json:"hash"`
+	Size     int    `json:"size"`
+}
+
+// Filter selects index entries; zero fields match everything
+type Filter struct {
+	Source    string
+	Endpoints []string
+	SportID   int
+	From      time.Time
+	To        time.Time
+}
+
+// Archive is a content-addressed payload store rooted at a directory
+type Archive struct {
+	dir string
+	// Serializes index appends within the process
+	mu sync.Mutex
+}
+
+var (
+	sharedMu sync.Mutex
+	shared   = make(map[string]*Archive)
+)
+
+// Open creates the archive directories under dir if needed
+func Open(dir string) (*Archive, error) {
+	for _, sub := range []string{"objects", "index"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create archive directory: %w", err)
+		}
+	}
+	return &Archive{dir: dir}, nil
+}
+
+// Shared returns the process-wide archive for dir, so every client appends through the same index writer
+func Shared(dir string) (*Archive, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive directory: %w", err)
+	}
+
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+	if a, ok := shared[abs]; ok {
+		return a, nil
+	}
+	a, err := Open(abs)
+	if err != nil {
+		return nil, err
+	}
+	shared[abs] = a
+	return a, nil
+}
+
+// Dir returns the archive root
+func (a *Archive) Dir() string {
+	return a.dir
+}
+
+// Put stores a payload fetched from rawURL and indexes the fetch
+func (a *Archive) Put(source, rawURL string, status int, body []byte, fetchedAt time.Time) (Entry, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid payload URL: %w", err)
+	}
+
+	sum := sha256.Sum256(body)
+	entry := Entry{
+		FetchedAt: fetchedAt.UTC(),
+		Source:    source,
+		Endpoint:  EndpointOf(u),
+		SportID:   SportIDOf(u),
+		URL:       rawURL,
+		Status:    status,
+		Hash:      hex.EncodeToString(sum[:]),
+		Size:      len(body),
+	}
+
+	if err := a.writeObject(entry.Hash, body); err != nil {
+		return entry, err
+	}
+	if err := a.appendIndex(entry); err != nil {
+		return entry, err
+	}
+	return entry, nil
+}
+
+// Get returns the payload stored under hash
+func (a *Archive) Get(hash string) ([]byte, error) {
+	file, err := os.Open(a.objectPath(hash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open payload %s: %w", hash, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress payload %s: %w", hash, err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payload %s: %w", hash, err)
+	}
+	return data, nil
+}
+
+// Entries returns the indexed fetches matching filter, oldest first
+func (a *Archive) Entries(filter Filter) ([]Entry, error) {
+	files, err := filepath.Glob(filepath.Join(a.dir, "index", "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, path := range files {
+		day, err := time.Parse(dayLayout, strings.TrimSuffix(filepath.Base(path), ".jsonl"))
+		if err != nil {
+			continue
+		}
+		// Skip day files entirely outside the range
+		if !filter.From.IsZero() && day.Add(24*time.Hour).Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !day.Before(filter.To) {
+			continue
+		}
+
+		dayEntries, err := readIndex(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range dayEntries {
+			if filter.matches(entry) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].FetchedAt.Before(entries[j].FetchedAt) })
+	return entries, nil
+}
+
+func (f Filter) matches(entry Entry) bool {
+	if f.Source != "" && entry.Source != f.Source {
+		return false
+	}
+	if f.SportID != 0 && entry.SportID != f.SportID {
+		return false
+	}
+	if !f.From.IsZero() && entry.FetchedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !entry.FetchedAt.Before(f.To) {
+		return false
+	}
+	if len(f.Endpoints) == 0 {
+		return true
+	}
+	for _, endpoint := range f.Endpoints {
+		if entry.Endpoint == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Archive) objectPath(hash string) string {
+	return filepath.Join(a.dir, "objects", hash[:2], hash+".gz")
+}
+
+// writeObject stores the compressed payload unless the same content is already archived
+func (a *Archive) writeObject(hash string, body []byte) error {
+	path := a.objectPath(hash)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create object directory: %w", err)
+	}
+
+	// Write then rename so readers never see a partial object
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".payload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create payload file: %w", err)
+	}
+	writer := gzip.NewWriter(tmp)
+	_, writeErr := writer.Write(body)
+	closeErr := errors.Join(writer.Close(), tmp.Close())
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write payload: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save payload: %w", err)
+	}
+	return nil
+}
+
+func (a *Archive) appendIndex(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode index entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	path := filepath.Join(a.dir, "index", entry.FetchedAt.Format(dayLayout)+".jsonl")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open index: %w", err)
+	}
+	// A single append keeps lines whole when several processes share the archive
+	if _, err := file.Write(line); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to append index: %w", err)
+	}
+	return file.Close()
+}
+
+func readIndex(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open index %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		// A torn last line from a crash is skipped rather than failing the whole day
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read index %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// EndpointOf returns the URL path with numeric segments replaced by {id}
+func EndpointOf(u *url.URL) string {
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if _, err := strconv.Atoi(segment); err == nil {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// SportIDOf reads the sport from the query parameters the Iddaa endpoints use for it, 0 when absent
+func SportIDOf(u *url.URL) int {
+	query := u.Query()
+	for _, key := range []string{"st", "sportType", "SportId"} {
+		if id, err := strconv.Atoi(query.Get(key)); err == nil {
+			return id
+		}
+	}
+	return 0
+}
@@ -0,0 +1,177 @@
+package archive
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchive_PutGetDeduplicates(t *testing.T) {
+	a, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	body := []byte(`{"isSuccess":true,"data":{"1":12.5}}`)
+	fetched := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	first, err := a.Put(SourceIddaa, "https://sportsbookv2.iddaa.com/sportsbook/played-event-percentage?sportType=1", 200, body, fetched)
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	second, err := a.Put(SourceIddaa, "https://sportsbookv2.iddaa.com/sportsbook/played-event-percentage?sportType=1", 200, body, fetched.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if first.Hash != second.Hash {
+		t.Errorf("identical payloads got hashes %s and %s", first.Hash, second.Hash)
+	}
+
+	objects, _ := filepath.Glob(filepath.Join(a.Dir(), "objects", "*", "*.gz"))
+	if len(objects) != 1 {
+		t.Errorf("stored %d objects, want 1", len(objects))
+	}
+
+	got, err := a.Get(first.Hash)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if string(got) != string(body) {
+		t.Errorf("Get() = %s, want %s", got, body)
+	}
+
+	entries, err := a.Entries(Filter{})
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Entries() returned %d entries, want 2", len(entries))
+	}
+	if entries[0].SportID != 1 || entries[0].Endpoint != "/sportsbook/played-event-percentage" {
+		t.Errorf("entry = %+v, want sport 1 on /sportsbook/played-event-percentage", entries[0])
+	}
+}
+
+func TestArchive_EntriesFilter(t *testing.T) {
+	a, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	puts := []struct {
+		source string
+		url    string
+		at     time.Time
+	}{
+		{SourceIddaa, "https://sportsbookv2.iddaa.com/sportsbook/events?st=1&type=0&version=0", day.Add(23 * time.Hour)},
+		{SourceIddaa, "https://sportsbookv2.iddaa.com/sportsbook/events?st=2&type=0&version=0", day.Add(22 * time.Hour)},
+		{SourceIddaa, "https://sportsbookv2.iddaa.com/sportsbook/event/123456", day.Add(25 * time.Hour)},
+		{SourceIddaa, "https://sportsbookv2.iddaa.com/sportsbook/events?st=1&type=0&version=0", day.Add(-time.Hour)},
+		{SourceAPIFootball, "https://v3.football.api-sports.io/fixtures?date=2025-03-01", day.Add(time.Hour)},
+	}
+	for i, put := range puts {
+		if _, err := a.Put(put.source, put.url, 200, []byte{byte(i)}, put.at); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []time.Time
+	}{
+		{
+			name:   "range is ordered by fetch time across day files",
+			filter: Filter{Source: SourceIddaa, From: day, To: day.Add(48 * time.Hour)},
+			want:   []time.Time{day.Add(22 * time.Hour), day.Add(23 * time.Hour), day.Add(25 * time.Hour)},
+		},
+		{
+			name:   "sport",
+			filter: Filter{SportID: 1},
+			want:   []time.Time{day.Add(-time.Hour), day.Add(23 * time.Hour)},
+		},
+		{
+			name:   "endpoint",
+			filter: Filter{Endpoints: []string{"/sportsbook/event/{id}"}},
+			want:   []time.Time{day.Add(25 * time.Hour)},
+		},
+		{
+			name:   "source",
+			filter: Filter{Source: SourceAPIFootball},
+			want:   []time.Time{day.Add(time.Hour)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := a.Entries(tt.filter)
+			if err != nil {
+				t.Fatalf("Entries() error = %v", err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Fatalf("Entries() returned %d entries, want %d", len(entries), len(tt.want))
+			}
+			for i, entry := range entries {
+				if !entry.FetchedAt.Equal(tt.want[i]) {
+					t.Errorf("entry %d fetched at %s, want %s", i, entry.FetchedAt, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestArchive_SkipsTornIndexLine(t *testing.T) {
+	a, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if _, err := a.Put(SourceIddaa, "https://sportsbookv2.iddaa.com/sportsbook/events?st=1", 200, []byte("{}"), at); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	index := filepath.Join(a.Dir(), "index", "2025-03-01.jsonl")
+	file, err := os.OpenFile(index, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open index: %v", err)
+	}
+	_, _ = file.WriteString(`{"fetched_at":"2025-03-01T12:`)
+	_ = file.Close()
+
+	entries, err := a.Entries(Filter{})
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Entries() returned %d entries, want 1", len(entries))
+	}
+}
+
+func TestEndpointAndSportOf(t *testing.T) {
+	tests := []struct {
+		url      string
+		endpoint string
+		sport    int
+	}{
+		{"https://sportsbookv2.iddaa.com/sportsbook/events?st=1&type=0&version=123", "/sportsbook/events", 1},
+		{"https://sportsbookv2.iddaa.com/sportsbook/event/2345678", "/sportsbook/event/{id}", 0},
+		{"https://sportsbookv2.iddaa.com/sportsbook/outcome-play-percentages?sportType=2", "/sportsbook/outcome-play-percentages", 2},
+		{"https://statisticsv2.iddaa.com/broadage/getEventListCache?SportId=1&SearchDate=2025-03-01", "/broadage/getEventListCache", 1},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatalf("parse %s: %v", tt.url, err)
+		}
+		if got := EndpointOf(u); got != tt.endpoint {
+			t.Errorf("EndpointOf(%s) = %s, want %s", tt.url, got, tt.endpoint)
+		}
+		if got := SportIDOf(u); got != tt.sport {
+			t.Errorf("SportIDOf(%s) = %d, want %d", tt.url, got, tt.sport)
+		}
+	}
+}
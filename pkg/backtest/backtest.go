@@ -0,0 +1,319 @@
+// Package backtest replays stored odds, distribution and volume history through
+// pluggable strategies and simulates staking against settled results.
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// TickKind identifies what a replayed tick carries
+type TickKind int
+
+const (
+	TickOdds TickKind = iota
+	TickDistribution
+	TickVolume
+)
+
+// OutcomeKey identifies a single outcome of an event
+type OutcomeKey struct {
+	EventID      int32  `json:"event_id"`
+	MarketTypeID int32  `json:"market_type_id"`
+	Outcome      string `json:"outcome"`
+}
+
+// Event is the replay metadata of an event
+type Event struct {
+	ID       int32
+	Slug     string
+	Kickoff  time.Time
+	Sport    string
+	League   string
+	Finished bool
+}
+
+// Tick is one historical record replayed in timestamp order
+type Tick struct {
+	Kind TickKind
+	Time time.Time
+	Key  OutcomeKey
+
+	// Odds ticks
+	Odds             float64
+	ChangePercentage float64
+	Multiplier       float64
+
+	// Distribution ticks
+	BetPercentage float64
+
+	// Volume ticks (event level, only Key.EventID is set)
+	VolumePercentage float64
+	VolumeRank       int32
+}
+
+// Settlement is the graded result of an outcome and its closing price
+type Settlement struct {
+	Result      string
+	ClosingOdds float64
+}
+
+// Dataset is everything a backtest replays
+type Dataset struct {
+	From        time.Time
+	To          time.Time
+	Events      map[int32]Event
+	Ticks       []Tick
+	Settlements map[OutcomeKey]Settlement
+}
+
+// Sort orders ticks by time; distributions and volume go first on ties so odds see them
+func (d *Dataset) Sort() {
+	sort.SliceStable(d.Ticks, func(i, j int) bool {
+		if !d.Ticks[i].Time.Equal(d.Ticks[j].Time) {
+			return d.Ticks[i].Time.Before(d.Ticks[j].Time)
+		}
+		return d.Ticks[i].Kind > d.Ticks[j].Kind
+	})
+}
+
+// Signal is a strategy's decision to bet on the outcome of an odds tick
+type Signal struct {
+	// Reason names the rule that fired, e.g. "sharp_money"
+	Reason     string
+	Confidence float64
+	// Probability is the strategy's estimated win probability, used by Kelly staking
+	Probability float64
+}
+
+// Strategy decides whether to bet on an odds tick given the market state replayed so far
+type Strategy interface {
+	Name() string
+	Evaluate(state *State, tick Tick) (Signal, bool)
+}
+
+// State is the market as known at the current replay time
+type State struct {
+	now          time.Time
+	events       map[int32]Event
+	distribution map[OutcomeKey]float64
+	volume       map[int32]Tick
+	moves        map[OutcomeKey][]Tick
+}
+
+func newState(events map[int32]Event) *State {
+	return &State{
+		events:       events,
+		distribution: make(map[OutcomeKey]float64),
+		volume:       make(map[int32]Tick),
+		moves:        make(map[OutcomeKey][]Tick),
+	}
+}
+
+// Now returns the current replay time
+func (s *State) Now() time.Time {
+	return s.now
+}
+
+// Event returns the metadata of an event
+func (s *State) Event(eventID int32) (Event, bool) {
+	event, ok := s.events[eventID]
+	return event, ok
+}
+
+// BetPercentage returns the latest public bet share of an outcome
+func (s *State) BetPercentage(key OutcomeKey) (float64, bool) {
+	pct, ok := s.distribution[key]
+	return pct, ok
+}
+
+// Volume returns the latest betting volume share and rank of an event
+func (s *State) Volume(eventID int32) (float64, int32, bool) {
+	tick, ok := s.volume[eventID]
+	return tick.VolumePercentage, tick.VolumeRank, ok
+}
+
+// MovesSince counts odds moves of an outcome at or after since with at least minChange absolute change
+func (s *State) MovesSince(key OutcomeKey, since time.Time, minChange float64) int {
+	count := 0
+	moves := s.moves[key]
+	for i := len(moves) - 1; i >= 0 && !moves[i].Time.Before(since); i-- {
+		if math.Abs(moves[i].ChangePercentage) >= minChange {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *State) apply(tick Tick) {
+	s.now = tick.Time
+	switch tick.Kind {
+	case TickDistribution:
+		s.distribution[tick.Key] = tick.BetPercentage
+	case TickVolume:
+		s.volume[tick.Key.EventID] = tick
+	case TickOdds:
+		moves := append(s.moves[tick.Key], tick)
+		// Strategies only look back a few hours
+		cutoff := tick.Time.Add(-6 * time.Hour)
+		for len(moves) > 0 && moves[0].Time.Before(cutoff) {
+			moves = moves[1:]
+		}
+		s.moves[tick.Key] = moves
+	}
+}
+
+// Config controls a simulation run
+type Config struct {
+	Bankroll float64
+	// SettleAfter is how long after kickoff a bet's result is credited to the bankroll
+	SettleAfter time.Duration
+	// IncludeBets keeps the individual bets on the report
+	IncludeBets bool
+}
+
+// DefaultConfig returns a 100 unit bankroll settled two hours after kickoff
+func DefaultConfig() Config {
+	return Config{
+		Bankroll:    100,
+		SettleAfter: 2 * time.Hour,
+	}
+}
+
+// Bet is a simulated wager
+type Bet struct {
+	Key         OutcomeKey `json:"key"`
+	EventSlug   string     `json:"event_slug"`
+	Reason      string     `json:"reason"`
+	PlacedAt    time.Time  `json:"placed_at"`
+	Odds        float64    `json:"odds"`
+	Stake       float64    `json:"stake"`
+	Result      string     `json:"result"`
+	Profit      float64    `json:"profit"`
+	ClosingOdds float64    `json:"closing_odds,omitempty"`
+	// CLVPercentage is (odds / closing - 1) * 100, zero when there is no closing price
+	CLVPercentage float64 `json:"clv_percentage"`
+	Bankroll      float64 `json:"bankroll"`
+
+	settleAt time.Time
+}
+
+// endOfReplay settles every bet still open when the ticks run out
+var endOfReplay = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// Run replays the dataset through a strategy and stakes its signals
+func Run(data *Dataset, strategy Strategy, staker Staker, cfg Config) (*Report, error) {
+	if cfg.Bankroll <= 0 {
+		return nil, fmt.Errorf("bankroll must be positive, got %v", cfg.Bankroll)
+	}
+
+	state := newState(data.Events)
+	placed := make(map[OutcomeKey]bool)
+	var open []*Bet
+
+	report := newReport(data, strategy.Name(), staker.Name(), cfg.Bankroll)
+	bankroll := cfg.Bankroll
+
+	settleUntil := func(now time.Time) {
+		var settled []*Bet
+		remaining := make([]*Bet, 0, len(open))
+		for _, bet := range open {
+			if bet.settleAt.After(now) {
+				remaining = append(remaining, bet)
+			} else {
+				settled = append(settled, bet)
+			}
+		}
+		sort.SliceStable(settled, func(i, j int) bool {
+			return settled[i].settleAt.Before(settled[j].settleAt)
+		})
+
+		// Equity counts stakes still at risk at cost
+		pending := openStakes(open)
+		for _, bet := range settled {
+			pending -= bet.Stake
+			bankroll += bet.Stake + bet.Profit
+			bet.Bankroll = bankroll
+			report.record(bet, bankroll+pending)
+		}
+		open = remaining
+	}
+
+	for _, tick := range data.Ticks {
+		settleUntil(tick.Time)
+		state.apply(tick)
+
+		if tick.Kind != TickOdds || placed[tick.Key] || tick.Odds <= 1 {
+			continue
+		}
+
+		event, ok := data.Events[tick.Key.EventID]
+		if !ok || !tick.Time.Before(event.Kickoff) {
+			continue
+		}
+
+		signal, ok := strategy.Evaluate(state, tick)
+		if !ok {
+			continue
+		}
+		// Only the first signal of each outcome is acted on
+		placed[tick.Key] = true
+		report.Signals++
+
+		settlement, ok := data.Settlements[tick.Key]
+		if !ok {
+			report.Unsettled++
+			continue
+		}
+
+		stake := math.Min(staker.Stake(bankroll, tick.Odds, signal), bankroll)
+		if stake <= 0 {
+			continue
+		}
+
+		bankroll -= stake
+		open = append(open, newBet(tick, event, signal, stake, settlement, cfg.SettleAfter))
+	}
+
+	settleUntil(endOfReplay)
+	report.finish(bankroll, cfg.IncludeBets)
+
+	return report, nil
+}
+
+func newBet(tick Tick, event Event, signal Signal, stake float64, settlement Settlement, settleAfter time.Duration) *Bet {
+	bet := &Bet{
+		Key:         tick.Key,
+		EventSlug:   event.Slug,
+		Reason:      signal.Reason,
+		PlacedAt:    tick.Time,
+		Odds:        tick.Odds,
+		Stake:       stake,
+		Result:      settlement.Result,
+		ClosingOdds: settlement.ClosingOdds,
+		settleAt:    event.Kickoff.Add(settleAfter),
+	}
+
+	switch settlement.Result {
+	case "won":
+		bet.Profit = stake * (tick.Odds - 1)
+	case "lost":
+		bet.Profit = -stake
+	}
+
+	if settlement.ClosingOdds > 1 {
+		bet.CLVPercentage = (tick.Odds/settlement.ClosingOdds - 1) * 100
+	}
+
+	return bet
+}
+
+func openStakes(bets []*Bet) float64 {
+	total := 0.0
+	for _, bet := range bets {
+		total += bet.Stake
+	}
+	return total
+}
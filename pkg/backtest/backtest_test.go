@@ -0,0 +1,193 @@
+package backtest
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// alwaysStrategy bets on every odds tick
+type alwaysStrategy struct{}
+
+func (alwaysStrategy) Name() string { return "always" }
+
+func (alwaysStrategy) Evaluate(state *State, tick Tick) (Signal, bool) {
+	return Signal{Reason: "always", Probability: 0.6}, true
+}
+
+func testDataset() *Dataset {
+	kickoff := time.Date(2025, 5, 1, 18, 0, 0, 0, time.UTC)
+	won := OutcomeKey{EventID: 1, MarketTypeID: 1, Outcome: "1"}
+	lost := OutcomeKey{EventID: 2, MarketTypeID: 1, Outcome: "2"}
+	unsettled := OutcomeKey{EventID: 2, MarketTypeID: 1, Outcome: "X"}
+
+	data := &Dataset{
+		Events: map[int32]Event{
+			1: {ID: 1, Slug: "a-vs-b", Kickoff: kickoff},
+			2: {ID: 2, Slug: "c-vs-d", Kickoff: kickoff.Add(24 * time.Hour)},
+		},
+		Ticks: []Tick{
+			{Kind: TickOdds, Time: kickoff.Add(-2 * time.Hour), Key: won, Odds: 2.0},
+			{Kind: TickOdds, Time: kickoff.Add(-time.Hour), Key: won, Odds: 1.8},
+			{Kind: TickOdds, Time: kickoff.Add(20 * time.Hour), Key: lost, Odds: 3.0},
+			{Kind: TickOdds, Time: kickoff.Add(21 * time.Hour), Key: unsettled, Odds: 3.2},
+			{Kind: TickOdds, Time: kickoff.Add(30 * time.Hour), Key: lost, Odds: 2.5},
+		},
+		Settlements: map[OutcomeKey]Settlement{
+			won:  {Result: "won", ClosingOdds: 1.8},
+			lost: {Result: "lost"},
+		},
+	}
+	data.Sort()
+	return data
+}
+
+func TestRun_FlatStaking(t *testing.T) {
+	report, err := Run(testDataset(), alwaysStrategy{}, FlatStaker{Units: 10}, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if report.Bets != 2 || report.Won != 1 || report.Lost != 1 {
+		t.Fatalf("bets = %d won = %d lost = %d, want 2/1/1", report.Bets, report.Won, report.Lost)
+	}
+	if report.Signals != 3 || report.Unsettled != 1 {
+		t.Errorf("signals = %d unsettled = %d, want 3/1", report.Signals, report.Unsettled)
+	}
+	// +10 at 2.0, -10 at 3.0, the post-kickoff tick is ignored
+	if report.Profit != 0 || report.FinalBankroll != 100 {
+		t.Errorf("profit = %v final = %v, want 0/100", report.Profit, report.FinalBankroll)
+	}
+	if report.Yield != 0 || report.HitRate != 50 {
+		t.Errorf("yield = %v hit rate = %v, want 0/50", report.Yield, report.HitRate)
+	}
+	if report.MaxDrawdown != 10 {
+		t.Errorf("max drawdown = %v, want 10", report.MaxDrawdown)
+	}
+	if report.CLVBets != 1 || math.Abs(report.AvgCLVPercentage-11.1111) > 0.001 {
+		t.Errorf("clv bets = %d avg clv = %v, want 1/11.11", report.CLVBets, report.AvgCLVPercentage)
+	}
+}
+
+func TestKellyStaker(t *testing.T) {
+	tests := []struct {
+		name   string
+		staker KellyStaker
+		odds   float64
+		prob   float64
+		want   float64
+	}{
+		{"full kelly", KellyStaker{Fraction: 1}, 2.0, 0.6, 20},
+		{"quarter kelly", KellyStaker{Fraction: 0.25}, 2.0, 0.6, 5},
+		{"capped", KellyStaker{Fraction: 1, MaxFraction: 0.05}, 2.0, 0.6, 5},
+		{"no edge", KellyStaker{Fraction: 1}, 2.0, 0.5, 0},
+		{"negative edge", KellyStaker{Fraction: 1}, 2.0, 0.4, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.staker.Stake(100, tt.odds, Signal{Probability: tt.prob})
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Stake() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmartMoneyStrategy(t *testing.T) {
+	kickoff := time.Date(2025, 5, 1, 18, 0, 0, 0, time.UTC)
+	key := OutcomeKey{EventID: 1, MarketTypeID: 1, Outcome: "2"}
+	state := newState(map[int32]Event{1: {ID: 1, Kickoff: kickoff}})
+
+	// Public avoids the outcome while its odds shorten: a reverse line movement
+	state.apply(Tick{Kind: TickDistribution, Time: kickoff.Add(-3 * time.Hour), Key: key, BetPercentage: 20})
+	tick := Tick{Kind: TickOdds, Time: kickoff.Add(-time.Hour), Key: key, Odds: 2.5, ChangePercentage: -12}
+	state.apply(tick)
+
+	strategy := NewSmartMoneyStrategy()
+	signal, ok := strategy.Evaluate(state, tick)
+	if !ok || signal.Reason != SignalReverseLine {
+		t.Fatalf("Evaluate() = %+v, %v, want reverse_line", signal, ok)
+	}
+	if signal.Probability <= 1/tick.Odds {
+		t.Errorf("probability %v should exceed implied %v", signal.Probability, 1/tick.Odds)
+	}
+
+	// Only sharp money enabled: 40 (reverse) + 20 (timing) + 10 (size) = 70 > 60
+	strategy.Signals = ParseSignals("sharp_money")
+	signal, ok = strategy.Evaluate(state, tick)
+	if !ok || signal.Reason != SignalSharpMoney || math.Abs(signal.Confidence-0.7) > 1e-9 {
+		t.Fatalf("Evaluate() = %+v, %v, want sharp_money at 0.7", signal, ok)
+	}
+
+	strategy.Thresholds.MinSharpScore = 70
+	if _, ok := strategy.Evaluate(state, tick); ok {
+		t.Error("expected no signal once the sharp score threshold is raised")
+	}
+}
+
+func TestRulesStrategy(t *testing.T) {
+	kickoff := time.Date(2025, 5, 1, 18, 0, 0, 0, time.UTC)
+	key := OutcomeKey{EventID: 1, MarketTypeID: 1, Outcome: "2"}
+	state := newState(map[int32]Event{1: {ID: 1, Kickoff: kickoff}})
+
+	state.apply(Tick{Kind: TickDistribution, Time: kickoff.Add(-3 * time.Hour), Key: key, BetPercentage: 20})
+	tick := Tick{Kind: TickOdds, Time: kickoff.Add(-time.Hour), Key: key, Odds: 2.5, ChangePercentage: -12, Multiplier: 0.88}
+	state.apply(tick)
+
+	maxChange := float32(-5)
+	maxBet := float32(35)
+	rule, err := services.CompileSmartMoneyRule(generated.SmartMoneyRule{
+		Name:             "public_light",
+		MaxChangePct:     &maxChange,
+		MaxBetPct:        &maxBet,
+		AlertType:        "reverse_line",
+		ConfidenceSource: services.ConfidenceReverseStrength,
+		TitleTemplate:    "{{.Match}}",
+		MessageTemplate:  "{{.Outcome}}",
+	})
+	if err != nil {
+		t.Fatalf("CompileSmartMoneyRule() error = %v", err)
+	}
+
+	strategy := NewRulesStrategy([]*services.SmartMoneyRule{rule})
+	signal, ok := strategy.Evaluate(state, tick)
+	// Strength 20% * 12% change = 2.4
+	if !ok || signal.Reason != "public_light" || math.Abs(signal.Confidence-0.024) > 1e-6 {
+		t.Fatalf("Evaluate() = %+v, %v, want public_light at 0.024", signal, ok)
+	}
+
+	maxBet = 15
+	if _, ok := strategy.Evaluate(state, tick); ok {
+		t.Error("expected no signal once the bet percentage bound is lowered")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	report, err := Run(testDataset(), alwaysStrategy{}, FlatStaker{Units: 1}, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, []*Report{report}); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "always,flat,") {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteBetsCSV(&buf, []*Report{report}); err != nil {
+		t.Fatalf("WriteBetsCSV() error = %v", err)
+	}
+	if got := strings.Count(strings.TrimSpace(buf.String()), "\n"); got != 2 {
+		t.Errorf("bets CSV has %d rows, want 2", got)
+	}
+}
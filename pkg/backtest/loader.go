@@ -0,0 +1,163 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+// pageSize bounds each history query
+const pageSize = 50000
+
+// Load reads the replay data of events kicking off within [from, to)
+func Load(ctx context.Context, db *generated.Queries, from, to time.Time, sportCode string) (*Dataset, error) {
+	fromTime := pgtype.Timestamp{Time: from, Valid: true}
+	toTime := pgtype.Timestamp{Time: to, Valid: true}
+
+	data := &Dataset{
+		From:        from,
+		To:          to,
+		Events:      make(map[int32]Event),
+		Settlements: make(map[OutcomeKey]Settlement),
+	}
+
+	events, err := db.GetBacktestEvents(ctx, generated.GetBacktestEventsParams{
+		FromTime:  fromTime,
+		ToTime:    toTime,
+		SportCode: sportCode,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+	for _, e := range events {
+		data.Events[e.ID] = Event{
+			ID:       e.ID,
+			Slug:     e.Slug,
+			Kickoff:  e.EventDate.Time,
+			Sport:    e.SportCode,
+			League:   e.LeagueName,
+			Finished: e.Status == "finished",
+		}
+	}
+
+	for afterID := int32(0); ; {
+		rows, err := db.GetBacktestOddsHistory(ctx, generated.GetBacktestOddsHistoryParams{
+			FromTime:   fromTime,
+			ToTime:     toTime,
+			SportCode:  sportCode,
+			AfterID:    afterID,
+			LimitCount: pageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load odds history: %w", err)
+		}
+		for _, row := range rows {
+			if row.EventID == nil || row.MarketTypeID == nil {
+				continue
+			}
+			tick := Tick{
+				Kind: TickOdds,
+				Time: row.RecordedAt.Time,
+				Key:  OutcomeKey{EventID: *row.EventID, MarketTypeID: *row.MarketTypeID, Outcome: row.Outcome},
+				Odds: row.OddsValue,
+				// Same default as the smart money candidate query
+				Multiplier: 1,
+			}
+			if row.ChangePercentage != nil {
+				tick.ChangePercentage = float64(*row.ChangePercentage)
+			}
+			if row.Multiplier != nil {
+				tick.Multiplier = *row.Multiplier
+			}
+			data.Ticks = append(data.Ticks, tick)
+		}
+		if len(rows) < pageSize {
+			break
+		}
+		afterID = rows[len(rows)-1].ID
+	}
+
+	for afterID := int32(0); ; {
+		rows, err := db.GetBacktestDistributionHistory(ctx, generated.GetBacktestDistributionHistoryParams{
+			FromTime:   fromTime,
+			ToTime:     toTime,
+			SportCode:  sportCode,
+			AfterID:    afterID,
+			LimitCount: pageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load distribution history: %w", err)
+		}
+		for _, row := range rows {
+			if row.EventID == nil {
+				continue
+			}
+			// Distribution market ids share market_type_id numbering, as in GetCurrentOddsForEvents
+			data.Ticks = append(data.Ticks, Tick{
+				Kind:          TickDistribution,
+				Time:          row.RecordedAt.Time,
+				Key:           OutcomeKey{EventID: *row.EventID, MarketTypeID: row.MarketID, Outcome: row.Outcome},
+				BetPercentage: float64(row.BetPercentage),
+			})
+		}
+		if len(rows) < pageSize {
+			break
+		}
+		afterID = rows[len(rows)-1].ID
+	}
+
+	for afterID := int32(0); ; {
+		rows, err := db.GetBacktestVolumeHistory(ctx, generated.GetBacktestVolumeHistoryParams{
+			FromTime:   fromTime,
+			ToTime:     toTime,
+			SportCode:  sportCode,
+			AfterID:    afterID,
+			LimitCount: pageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load volume history: %w", err)
+		}
+		for _, row := range rows {
+			if row.EventID == nil {
+				continue
+			}
+			tick := Tick{
+				Kind:             TickVolume,
+				Time:             row.RecordedAt.Time,
+				Key:              OutcomeKey{EventID: *row.EventID},
+				VolumePercentage: float64(row.VolumePercentage),
+			}
+			if row.RankPosition != nil {
+				tick.VolumeRank = *row.RankPosition
+			}
+			data.Ticks = append(data.Ticks, tick)
+		}
+		if len(rows) < pageSize {
+			break
+		}
+		afterID = rows[len(rows)-1].ID
+	}
+
+	settlements, err := db.GetBacktestSettlements(ctx, generated.GetBacktestSettlementsParams{
+		FromTime:  fromTime,
+		ToTime:    toTime,
+		SportCode: sportCode,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settlements: %w", err)
+	}
+	for _, s := range settlements {
+		settlement := Settlement{Result: s.Result}
+		if s.ClosingValue != nil {
+			settlement.ClosingOdds = *s.ClosingValue
+		}
+		data.Settlements[OutcomeKey{EventID: s.EventID, MarketTypeID: s.MarketTypeID, Outcome: s.Outcome}] = settlement
+	}
+
+	data.Sort()
+	return data, nil
+}
@@ -0,0 +1,186 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Report summarizes a simulation run
+type Report struct {
+	Strategy         string    `json:"strategy"`
+	Staking          string    `json:"staking"`
+	From             time.Time `json:"from"`
+	To               time.Time `json:"to"`
+	Events           int       `json:"events"`
+	Ticks            int       `json:"ticks"`
+	Signals          int       `json:"signals"`
+	Unsettled        int       `json:"unsettled"`
+	Bets             int       `json:"bets"`
+	Won              int       `json:"won"`
+	Lost             int       `json:"lost"`
+	Refunded         int       `json:"refunded"`
+	StartingBankroll float64   `json:"starting_bankroll"`
+	FinalBankroll    float64   `json:"final_bankroll"`
+	Staked           float64   `json:"staked"`
+	Profit           float64   `json:"profit"`
+	// ROI is profit over the starting bankroll, Yield is profit over the total staked
+	ROI            float64 `json:"roi"`
+	Yield          float64 `json:"yield"`
+	HitRate        float64 `json:"hit_rate"`
+	AvgOdds        float64 `json:"avg_odds"`
+	MaxDrawdown    float64 `json:"max_drawdown"`
+	MaxDrawdownPct float64 `json:"max_drawdown_pct"`
+	// CLV covers bets whose outcome has a frozen closing price
+	CLVBets          int     `json:"clv_bets"`
+	AvgCLVPercentage float64 `json:"avg_clv_percentage"`
+	BeatCloseRate    float64 `json:"beat_close_rate"`
+	BetDetails       []*Bet  `json:"bets_detail,omitempty"`
+
+	bets      []*Bet
+	oddsSum   float64
+	clvSum    float64
+	beatClose int
+	peak      float64
+}
+
+func newReport(data *Dataset, strategy, staking string, bankroll float64) *Report {
+	return &Report{
+		Strategy:         strategy,
+		Staking:          staking,
+		From:             data.From,
+		To:               data.To,
+		Events:           len(data.Events),
+		Ticks:            len(data.Ticks),
+		StartingBankroll: bankroll,
+		peak:             bankroll,
+	}
+}
+
+// record adds a settled bet and updates drawdown from the equity after it
+func (r *Report) record(bet *Bet, equity float64) {
+	r.bets = append(r.bets, bet)
+	r.Bets++
+	r.Staked += bet.Stake
+	r.Profit += bet.Profit
+	r.oddsSum += bet.Odds
+
+	switch bet.Result {
+	case "won":
+		r.Won++
+	case "lost":
+		r.Lost++
+	default:
+		r.Refunded++
+	}
+
+	if bet.ClosingOdds > 1 {
+		r.CLVBets++
+		r.clvSum += bet.CLVPercentage
+		if bet.CLVPercentage > 0 {
+			r.beatClose++
+		}
+	}
+
+	if equity > r.peak {
+		r.peak = equity
+	}
+	if drawdown := r.peak - equity; drawdown > r.MaxDrawdown {
+		r.MaxDrawdown = drawdown
+		r.MaxDrawdownPct = drawdown / r.peak * 100
+	}
+}
+
+func (r *Report) finish(bankroll float64, includeBets bool) {
+	r.FinalBankroll = bankroll
+	r.ROI = r.Profit / r.StartingBankroll * 100
+	if r.Staked > 0 {
+		r.Yield = r.Profit / r.Staked * 100
+	}
+	if decided := r.Won + r.Lost; decided > 0 {
+		r.HitRate = float64(r.Won) / float64(decided) * 100
+	}
+	if r.Bets > 0 {
+		r.AvgOdds = r.oddsSum / float64(r.Bets)
+	}
+	if r.CLVBets > 0 {
+		r.AvgCLVPercentage = r.clvSum / float64(r.CLVBets)
+		r.BeatCloseRate = float64(r.beatClose) / float64(r.CLVBets) * 100
+	}
+	if includeBets {
+		r.BetDetails = r.bets
+	}
+}
+
+// WriteJSON writes reports as an indented JSON array
+func WriteJSON(w io.Writer, reports []*Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(reports)
+}
+
+// WriteCSV writes one summary row per report
+func WriteCSV(w io.Writer, reports []*Report) error {
+	writer := csv.NewWriter(w)
+	header := []string{
+		"strategy", "staking", "from", "to", "signals", "unsettled", "bets", "won", "lost", "refunded",
+		"staked", "profit", "roi", "yield", "hit_rate", "avg_odds", "max_drawdown", "max_drawdown_pct",
+		"clv_bets", "avg_clv_percentage", "beat_close_rate", "final_bankroll",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, r := range reports {
+		row := []string{
+			r.Strategy, r.Staking, r.From.Format(time.DateOnly), r.To.Format(time.DateOnly),
+			strconv.Itoa(r.Signals), strconv.Itoa(r.Unsettled), strconv.Itoa(r.Bets),
+			strconv.Itoa(r.Won), strconv.Itoa(r.Lost), strconv.Itoa(r.Refunded),
+			formatFloat(r.Staked), formatFloat(r.Profit), formatFloat(r.ROI), formatFloat(r.Yield),
+			formatFloat(r.HitRate), formatFloat(r.AvgOdds), formatFloat(r.MaxDrawdown), formatFloat(r.MaxDrawdownPct),
+			strconv.Itoa(r.CLVBets), formatFloat(r.AvgCLVPercentage), formatFloat(r.BeatCloseRate),
+			formatFloat(r.FinalBankroll),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteBetsCSV writes every bet of the given reports, one row per bet
+func WriteBetsCSV(w io.Writer, reports []*Report) error {
+	writer := csv.NewWriter(w)
+	header := []string{
+		"strategy", "staking", "event_slug", "market_type_id", "outcome", "reason", "placed_at",
+		"odds", "stake", "result", "profit", "closing_odds", "clv_percentage", "bankroll",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, r := range reports {
+		for _, bet := range r.bets {
+			row := []string{
+				r.Strategy, r.Staking, bet.EventSlug, strconv.Itoa(int(bet.Key.MarketTypeID)), bet.Key.Outcome,
+				bet.Reason, bet.PlacedAt.Format(time.RFC3339), formatFloat(bet.Odds), formatFloat(bet.Stake),
+				bet.Result, formatFloat(bet.Profit), formatFloat(bet.ClosingOdds), formatFloat(bet.CLVPercentage),
+				formatFloat(bet.Bankroll),
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 4, 64)
+}
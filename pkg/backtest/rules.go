@@ -0,0 +1,62 @@
+package backtest
+
+import (
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// RulesStrategy replays the stored smart money rules and bets on the first rule that fires
+type RulesStrategy struct {
+	// Rules in priority order, as returned by SmartMoneyTracker.LoadRules
+	Rules []*services.SmartMoneyRule
+	// Edge is the win probability uplift over the implied probability assumed at confidence 1
+	Edge float64
+}
+
+// NewRulesStrategy creates a strategy evaluating the given rules
+func NewRulesStrategy(rules []*services.SmartMoneyRule) *RulesStrategy {
+	return &RulesStrategy{
+		Rules: rules,
+		Edge:  0.05,
+	}
+}
+
+// Name returns the strategy name
+func (s *RulesStrategy) Name() string {
+	return "rules"
+}
+
+// Evaluate builds the tracker's candidate from the replay state and tries the rules in order.
+// A rule's max_alerts cap is not applied, it limits a single tracker run rather than a replay.
+func (s *RulesStrategy) Evaluate(state *State, tick Tick) (Signal, bool) {
+	event, ok := state.Event(tick.Key.EventID)
+	if !ok {
+		return Signal{}, false
+	}
+
+	candidate := services.MovementCandidate{
+		Outcome:          tick.Key.Outcome,
+		Odds:             tick.Odds,
+		ChangePercentage: tick.ChangePercentage,
+		Multiplier:       tick.Multiplier,
+		MinutesToKickoff: int32(event.Kickoff.Sub(tick.Time).Minutes()),
+		MovesLastHour:    state.MovesSince(tick.Key, tick.Time.Add(-time.Hour), 0),
+	}
+	if bet, ok := state.BetPercentage(tick.Key); ok {
+		implied := 100 / tick.Odds
+		candidate.BetPercentage = &bet
+		candidate.ImpliedProbability = &implied
+	}
+	if volume, rank, ok := state.Volume(tick.Key.EventID); ok {
+		candidate.VolumePercentage = &volume
+		candidate.VolumeRank = &rank
+	}
+
+	for _, rule := range s.Rules {
+		if rule.Matches(candidate) {
+			return edgeSignal(rule.Name, float64(rule.Confidence(candidate)), tick.Odds, s.Edge), true
+		}
+	}
+	return Signal{}, false
+}
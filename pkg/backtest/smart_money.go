@@ -0,0 +1,132 @@
+package backtest
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// Smart money signal names, matching the movement alert types
+const (
+	SignalReverseLine = "reverse_line"
+	SignalSharpMoney  = "sharp_money"
+	SignalSteamMove   = "steam_move"
+	SignalValueSpot   = "value_spot"
+)
+
+// SmartMoneyStrategy replays SmartMoneyTracker's detection rules and bets on the flagged outcome
+type SmartMoneyStrategy struct {
+	Thresholds services.SmartMoneyThresholds
+	// Signals enables a subset of rules; empty enables all of them
+	Signals map[string]bool
+	// Edge is the win probability uplift over the implied probability assumed at confidence 1,
+	// Kelly staking needs an estimate and the tracker only produces a confidence
+	Edge float64
+}
+
+// NewSmartMoneyStrategy creates a strategy with the production thresholds
+func NewSmartMoneyStrategy() *SmartMoneyStrategy {
+	return &SmartMoneyStrategy{
+		Thresholds: services.DefaultSmartMoneyThresholds(),
+		Signals:    map[string]bool{},
+		Edge:       0.05,
+	}
+}
+
+// ParseSignals parses a comma separated list of signal names
+func ParseSignals(list string) map[string]bool {
+	signals := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			signals[name] = true
+		}
+	}
+	return signals
+}
+
+// Name returns the strategy name with the enabled signals
+func (s *SmartMoneyStrategy) Name() string {
+	if len(s.Signals) == 0 {
+		return "smart_money"
+	}
+	names := make([]string, 0, len(s.Signals))
+	for name := range s.Signals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "smart_money[" + strings.Join(names, "+") + "]"
+}
+
+// Evaluate applies the rules in the tracker's order and returns the first that fires
+func (s *SmartMoneyStrategy) Evaluate(state *State, tick Tick) (Signal, bool) {
+	event, ok := state.Event(tick.Key.EventID)
+	if !ok {
+		return Signal{}, false
+	}
+
+	input := services.MovementInput{
+		ChangePercentage: tick.ChangePercentage,
+		HoursToKickoff:   event.Kickoff.Sub(tick.Time).Hours(),
+	}
+	if bet, ok := state.BetPercentage(tick.Key); ok {
+		implied := 100 / tick.Odds
+		input.BetPercentage = &bet
+		input.ImpliedProbability = &implied
+	}
+	if volume, _, ok := state.Volume(tick.Key.EventID); ok {
+		input.VolumePercentage = &volume
+	}
+
+	if s.enabled(SignalReverseLine) {
+		if strength, ok := services.ReverseLineStrength(input, s.Thresholds); ok {
+			return s.signal(SignalReverseLine, strength/100, tick.Odds), true
+		}
+	}
+
+	if s.enabled(SignalSharpMoney) {
+		if score := services.SharpMoneyScore(input); score > s.Thresholds.MinSharpScore {
+			return s.signal(SignalSharpMoney, float64(score)/100, tick.Odds), true
+		}
+	}
+
+	if s.enabled(SignalSteamMove) {
+		moves := state.MovesSince(tick.Key, tick.Time.Add(-time.Hour), s.Thresholds.SteamMinChangePct)
+		if moves >= s.Thresholds.SteamMinMoves {
+			return s.signal(SignalSteamMove, float64(services.SteamMoveConfidence(moves)), tick.Odds), true
+		}
+	}
+
+	if s.enabled(SignalValueSpot) {
+		if bias, ok := services.ValueSpotBias(input, s.Thresholds); ok {
+			return s.signal(SignalValueSpot, bias/100, tick.Odds), true
+		}
+	}
+
+	return Signal{}, false
+}
+
+func (s *SmartMoneyStrategy) enabled(signal string) bool {
+	return len(s.Signals) == 0 || s.Signals[signal]
+}
+
+func (s *SmartMoneyStrategy) signal(reason string, confidence, odds float64) Signal {
+	return edgeSignal(reason, confidence, odds, s.Edge)
+}
+
+// edgeSignal turns a rule confidence into a win probability above the implied one
+func edgeSignal(reason string, confidence, odds, edge float64) Signal {
+	if confidence > 1 {
+		confidence = 1
+	}
+	probability := (1 / odds) * (1 + edge*confidence)
+	if probability > 0.99 {
+		probability = 0.99
+	}
+	return Signal{
+		Reason:      reason,
+		Confidence:  confidence,
+		Probability: probability,
+	}
+}
@@ -0,0 +1,79 @@
+package backtest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Staker sizes a bet from the available bankroll, the offered odds and the signal
+type Staker interface {
+	Name() string
+	Stake(bankroll, odds float64, signal Signal) float64
+}
+
+// FlatStaker stakes the same amount on every bet
+type FlatStaker struct {
+	Units float64
+}
+
+// Name returns the staking plan name
+func (f FlatStaker) Name() string {
+	return "flat"
+}
+
+// Stake returns the fixed stake
+func (f FlatStaker) Stake(bankroll, odds float64, signal Signal) float64 {
+	return f.Units
+}
+
+// KellyStaker stakes a fraction of the Kelly criterion; Fraction 1 is full Kelly
+type KellyStaker struct {
+	Fraction float64
+	// MaxFraction caps a single stake as a share of the bankroll, zero means no cap
+	MaxFraction float64
+}
+
+// Name returns the staking plan name
+func (k KellyStaker) Name() string {
+	if k.Fraction == 1 {
+		return "kelly"
+	}
+	return fmt.Sprintf("kelly_%g", k.Fraction)
+}
+
+// Stake returns bankroll * fraction * (b*p - q) / b, or zero when the signal has no edge
+func (k KellyStaker) Stake(bankroll, odds float64, signal Signal) float64 {
+	f := KellyFraction(signal.Probability, odds) * k.Fraction
+	if k.MaxFraction > 0 && f > k.MaxFraction {
+		f = k.MaxFraction
+	}
+	if f <= 0 {
+		return 0
+	}
+	return bankroll * f
+}
+
+// KellyFraction returns the share of bankroll the Kelly criterion stakes at decimal odds
+func KellyFraction(probability, odds float64) float64 {
+	b := odds - 1
+	if b <= 0 || probability <= 0 || probability >= 1 {
+		return 0
+	}
+	return (b*probability - (1 - probability)) / b
+}
+
+// ParseStaker parses "flat", "kelly" or "fractional" (fractional Kelly)
+func ParseStaker(name string, flatUnits, kellyFraction, maxFraction float64) (Staker, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "flat":
+		return FlatStaker{Units: flatUnits}, nil
+	case "kelly":
+		return KellyStaker{Fraction: 1, MaxFraction: maxFraction}, nil
+	case "fractional", "fractional_kelly":
+		if kellyFraction <= 0 || kellyFraction > 1 {
+			return nil, fmt.Errorf("kelly fraction must be in (0, 1], got %v", kellyFraction)
+		}
+		return KellyStaker{Fraction: kellyFraction, MaxFraction: maxFraction}, nil
+	}
+	return nil, fmt.Errorf("unknown staking plan %q", name)
+}
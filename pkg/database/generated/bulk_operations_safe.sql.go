@@ -161,7 +161,7 @@ SELECT
 FROM
     input_data
 ORDER BY row_num  -- Ensure insertion order matches array order
-ON CONFLICT (event_id, market_type_id, outcome) DO
+ON CONFLICT (event_id, market_type_id, outcome, bookmaker) DO
 UPDATE
 SET
     odds_value = EXCLUDED.odds_value,
@@ -232,7 +232,7 @@ FROM (
         JOIN unnest($4::float8[]) WITH ORDINALITY as v(value, ord) ON e.ord = v.ord
         JOIN unnest($5::jsonb[]) WITH ORDINALITY as p(value, ord) ON e.ord = p.ord
 ) AS ordered_data
-ON CONFLICT (event_id, market_type_id, outcome) DO
+ON CONFLICT (event_id, market_type_id, outcome, bookmaker) DO
 UPDATE
 SET
     odds_value = EXCLUDED.odds_value,
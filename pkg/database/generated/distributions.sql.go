@@ -25,24 +25,27 @@ INSERT INTO outcome_distribution_history (
   market_id,
   outcome,
   bet_percentage,
-  previous_percentage
+  previous_percentage,
+  recorded_at
 )
 SELECT
   e.id,
   i.market_id,
   i.outcome,
   i.bet_percentage,
-  i.previous_percentage
+  i.previous_percentage,
+  COALESCE($6::timestamp, CURRENT_TIMESTAMP)
 FROM input_data i
 JOIN events e ON e.external_id = i.external_id
 `
 
 type BulkInsertDistributionHistoryParams struct {
-	ExternalIds         []string  `db:"external_ids" json:"external_ids"`
-	MarketIds           []int64   `db:"market_ids" json:"market_ids"`
-	Outcomes            []string  `db:"outcomes" json:"outcomes"`
-	BetPercentages      []float64 `db:"bet_percentages" json:"bet_percentages"`
-	PreviousPercentages []float64 `db:"previous_percentages" json:"previous_percentages"`
+	ExternalIds         []string         `db:"external_ids" json:"external_ids"`
+	MarketIds           []int64          `db:"market_ids" json:"market_ids"`
+	Outcomes            []string         `db:"outcomes" json:"outcomes"`
+	BetPercentages      []float64        `db:"bet_percentages" json:"bet_percentages"`
+	PreviousPercentages []float64        `db:"previous_percentages" json:"previous_percentages"`
+	RecordedAt          pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
 }
 
 // Bulk insert distribution history for changed values
@@ -53,6 +56,7 @@ func (q *Queries) BulkInsertDistributionHistory(ctx context.Context, arg BulkIns
 		arg.Outcomes,
 		arg.BetPercentages,
 		arg.PreviousPercentages,
+		arg.RecordedAt,
 	)
 	if err != nil {
 		return 0, err
@@ -74,29 +78,32 @@ INSERT INTO outcome_distributions (
   market_id,
   outcome,
   bet_percentage,
-  implied_probability
+  implied_probability,
+  last_updated
 )
 SELECT 
   e.id,
   i.market_id,
   i.outcome,
   i.bet_percentage,
-  i.implied_probability
+  i.implied_probability,
+  COALESCE($6::timestamp, CURRENT_TIMESTAMP)
 FROM input_data i
 JOIN events e ON e.external_id = i.external_id
 ON CONFLICT (event_id, market_id, outcome) DO UPDATE
 SET 
   bet_percentage = EXCLUDED.bet_percentage,
   implied_probability = EXCLUDED.implied_probability,
-  last_updated = CURRENT_TIMESTAMP
+  last_updated = EXCLUDED.last_updated
 `
 
 type BulkUpsertDistributionsParams struct {
-	ExternalIds          []string  `db:"external_ids" json:"external_ids"`
-	MarketIds            []int64   `db:"market_ids" json:"market_ids"`
-	Outcomes             []string  `db:"outcomes" json:"outcomes"`
-	BetPercentages       []float64 `db:"bet_percentages" json:"bet_percentages"`
-	ImpliedProbabilities []float64 `db:"implied_probabilities" json:"implied_probabilities"`
+	ExternalIds          []string         `db:"external_ids" json:"external_ids"`
+	MarketIds            []int64          `db:"market_ids" json:"market_ids"`
+	Outcomes             []string         `db:"outcomes" json:"outcomes"`
+	BetPercentages       []float64        `db:"bet_percentages" json:"bet_percentages"`
+	ImpliedProbabilities []float64        `db:"implied_probabilities" json:"implied_probabilities"`
+	RecordedAt           pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
 }
 
 // Bulk upsert distributions with database-side calculations
@@ -107,6 +114,7 @@ func (q *Queries) BulkUpsertDistributions(ctx context.Context, arg BulkUpsertDis
 		arg.Outcomes,
 		arg.BetPercentages,
 		arg.ImpliedProbabilities,
+		arg.RecordedAt,
 	)
 	if err != nil {
 		return 0, err
@@ -225,7 +233,8 @@ SELECT
   e.external_id
 FROM current_odds co
 JOIN events e ON e.id = co.event_id
-WHERE e.external_id = ANY($1::text[])
+WHERE co.bookmaker = 'iddaa'
+  AND e.external_id = ANY($1::text[])
   AND co.market_type_id = 1
 `
 
@@ -264,11 +273,12 @@ func (q *Queries) GetCurrentOddsForEvents(ctx context.Context, externalIds []str
 
 const getCurrentOddsForOutcome = `-- name: GetCurrentOddsForOutcome :many
 SELECT
-  co.id, co.event_id, co.market_type_id, co.outcome, co.odds_value, co.opening_value, co.highest_value, co.lowest_value, co.winning_odds, co.total_movement, co.movement_percentage, co.last_updated, co.market_params
+  co.id, co.event_id, co.market_type_id, co.outcome, co.odds_value, co.opening_value, co.highest_value, co.lowest_value, co.winning_odds, co.total_movement, co.movement_percentage, co.last_updated, co.market_params, co.is_suspended, co.suspended_at, co.bookmaker
 FROM
   current_odds co
 WHERE
-  co.event_id = $1
+  co.bookmaker = 'iddaa'
+  AND co.event_id = $1
   AND co.outcome = $2
 `
 
@@ -300,6 +310,9 @@ func (q *Queries) GetCurrentOddsForOutcome(ctx context.Context, arg GetCurrentOd
 			&i.MovementPercentage,
 			&i.LastUpdated,
 			&i.MarketParams,
+			&i.IsSuspended,
+			&i.SuspendedAt,
+			&i.Bookmaker,
 		); err != nil {
 			return nil, err
 		}
@@ -643,6 +643,106 @@ func (q *Queries) GetEventByID(ctx context.Context, id int32) (Event, error) {
 	return i, err
 }
 
+const getEventBySlug = `-- name: GetEventBySlug :one
+SELECT
+  e.id, e.external_id, e.league_id, e.home_team_id, e.away_team_id, e.slug, e.event_date, e.status, e.home_score, e.away_score, e.is_live, e.minute_of_match, e.half, e.betting_volume_percentage, e.volume_rank, e.volume_updated_at, e.bulletin_id, e.version, e.sport_id, e.bet_program, e.mbc, e.has_king_odd, e.odds_count, e.has_combine, e.created_at, e.updated_at,
+  ht.name as home_team_name,
+  ht.country as home_team_country,
+  at.name as away_team_name,
+  at.country as away_team_country,
+  l.name as league_name,
+  l.country as league_country,
+  s.name as sport_name,
+  s.code as sport_code
+FROM
+  events e
+  JOIN teams ht ON e.home_team_id = ht.id
+  JOIN teams at ON e.away_team_id = at.id
+  JOIN leagues l ON e.league_id = l.id
+  JOIN sports s ON e.sport_id = s.id
+WHERE
+  e.slug = $1::text
+`
+
+type GetEventBySlugRow struct {
+	ID                      int32            `db:"id" json:"id"`
+	ExternalID              string           `db:"external_id" json:"external_id"`
+	LeagueID                *int32           `db:"league_id" json:"league_id"`
+	HomeTeamID              *int32           `db:"home_team_id" json:"home_team_id"`
+	AwayTeamID              *int32           `db:"away_team_id" json:"away_team_id"`
+	Slug                    string           `db:"slug" json:"slug"`
+	EventDate               pgtype.Timestamp `db:"event_date" json:"event_date"`
+	Status                  string           `db:"status" json:"status"`
+	HomeScore               *int32           `db:"home_score" json:"home_score"`
+	AwayScore               *int32           `db:"away_score" json:"away_score"`
+	IsLive                  *bool            `db:"is_live" json:"is_live"`
+	MinuteOfMatch           *int32           `db:"minute_of_match" json:"minute_of_match"`
+	Half                    *int32           `db:"half" json:"half"`
+	BettingVolumePercentage *float32         `db:"betting_volume_percentage" json:"betting_volume_percentage"`
+	VolumeRank              *int32           `db:"volume_rank" json:"volume_rank"`
+	VolumeUpdatedAt         pgtype.Timestamp `db:"volume_updated_at" json:"volume_updated_at"`
+	BulletinID              *int64           `db:"bulletin_id" json:"bulletin_id"`
+	Version                 *int64           `db:"version" json:"version"`
+	SportID                 *int32           `db:"sport_id" json:"sport_id"`
+	BetProgram              *int32           `db:"bet_program" json:"bet_program"`
+	Mbc                     *int32           `db:"mbc" json:"mbc"`
+	HasKingOdd              *bool            `db:"has_king_odd" json:"has_king_odd"`
+	OddsCount               *int32           `db:"odds_count" json:"odds_count"`
+	HasCombine              *bool            `db:"has_combine" json:"has_combine"`
+	CreatedAt               pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt               pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+	HomeTeamName            string           `db:"home_team_name" json:"home_team_name"`
+	HomeTeamCountry         *string          `db:"home_team_country" json:"home_team_country"`
+	AwayTeamName            string           `db:"away_team_name" json:"away_team_name"`
+	AwayTeamCountry         *string          `db:"away_team_country" json:"away_team_country"`
+	LeagueName              string           `db:"league_name" json:"league_name"`
+	LeagueCountry           *string          `db:"league_country" json:"league_country"`
+	SportName               string           `db:"sport_name" json:"sport_name"`
+	SportCode               string           `db:"sport_code" json:"sport_code"`
+}
+
+func (q *Queries) GetEventBySlug(ctx context.Context, slug string) (GetEventBySlugRow, error) {
+	row := q.db.QueryRow(ctx, getEventBySlug, slug)
+	var i GetEventBySlugRow
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.LeagueID,
+		&i.HomeTeamID,
+		&i.AwayTeamID,
+		&i.Slug,
+		&i.EventDate,
+		&i.Status,
+		&i.HomeScore,
+		&i.AwayScore,
+		&i.IsLive,
+		&i.MinuteOfMatch,
+		&i.Half,
+		&i.BettingVolumePercentage,
+		&i.VolumeRank,
+		&i.VolumeUpdatedAt,
+		&i.BulletinID,
+		&i.Version,
+		&i.SportID,
+		&i.BetProgram,
+		&i.Mbc,
+		&i.HasKingOdd,
+		&i.OddsCount,
+		&i.HasCombine,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HomeTeamName,
+		&i.HomeTeamCountry,
+		&i.AwayTeamName,
+		&i.AwayTeamCountry,
+		&i.LeagueName,
+		&i.LeagueCountry,
+		&i.SportName,
+		&i.SportCode,
+	)
+	return i, err
+}
+
 const getEventsByTeam = `-- name: GetEventsByTeam :many
 SELECT
   e.id, e.external_id, e.league_id, e.home_team_id, e.away_team_id, e.slug, e.event_date, e.status, e.home_score, e.away_score, e.is_live, e.minute_of_match, e.half, e.betting_volume_percentage, e.volume_rank, e.volume_updated_at, e.bulletin_id, e.version, e.sport_id, e.bet_program, e.mbc, e.has_king_odd, e.odds_count, e.has_combine, e.created_at, e.updated_at,
@@ -10,6 +10,30 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AlertResult struct {
+	AlertID       int32            `db:"alert_id" json:"alert_id"`
+	EventID       int32            `db:"event_id" json:"event_id"`
+	SettlementID  int32            `db:"settlement_id" json:"settlement_id"`
+	Result        string           `db:"result" json:"result"`
+	AlertOdds     float64          `db:"alert_odds" json:"alert_odds"`
+	ClosingOdds   *float64         `db:"closing_odds" json:"closing_odds"`
+	ProfitAtAlert float64          `db:"profit_at_alert" json:"profit_at_alert"`
+	ProfitAtClose *float64         `db:"profit_at_close" json:"profit_at_close"`
+	GradedAt      pgtype.Timestamp `db:"graded_at" json:"graded_at"`
+}
+
+type ApiKey struct {
+	ID         int32            `db:"id" json:"id"`
+	UserID     int32            `db:"user_id" json:"user_id"`
+	Name       string           `db:"name" json:"name"`
+	KeyPrefix  string           `db:"key_prefix" json:"key_prefix"`
+	KeyHash    string           `db:"key_hash" json:"key_hash"`
+	LastUsedAt pgtype.Timestamp `db:"last_used_at" json:"last_used_at"`
+	ExpiresAt  pgtype.Timestamp `db:"expires_at" json:"expires_at"`
+	RevokedAt  pgtype.Timestamp `db:"revoked_at" json:"revoked_at"`
+	CreatedAt  pgtype.Timestamp `db:"created_at" json:"created_at"`
+}
+
 type AppConfig struct {
 	ID                  int32            `db:"id" json:"id"`
 	Platform            string           `db:"platform" json:"platform"`
@@ -64,6 +88,40 @@ type BigMover struct {
 	LastUpdated             pgtype.Timestamp `db:"last_updated" json:"last_updated"`
 }
 
+type Bookmaker struct {
+	ID                  int32            `db:"id" json:"id"`
+	Code                string           `db:"code" json:"code"`
+	Name                string           `db:"name" json:"name"`
+	Provider            string           `db:"provider" json:"provider"`
+	ProviderBookmakerID *int32           `db:"provider_bookmaker_id" json:"provider_bookmaker_id"`
+	IsActive            bool             `db:"is_active" json:"is_active"`
+	CreatedAt           pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt           pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+}
+
+type ClosingLineValue struct {
+	OddsHistoryID  int32            `db:"odds_history_id" json:"odds_history_id"`
+	EventID        int32            `db:"event_id" json:"event_id"`
+	ClosingOddsID  int32            `db:"closing_odds_id" json:"closing_odds_id"`
+	OddsValue      float64          `db:"odds_value" json:"odds_value"`
+	ClosingValue   float64          `db:"closing_value" json:"closing_value"`
+	ClvPercentage  float32          `db:"clv_percentage" json:"clv_percentage"`
+	ClvProbability float32          `db:"clv_probability" json:"clv_probability"`
+	ComputedAt     pgtype.Timestamp `db:"computed_at" json:"computed_at"`
+}
+
+type ClosingOdd struct {
+	ID                int32            `db:"id" json:"id"`
+	EventID           int32            `db:"event_id" json:"event_id"`
+	MarketTypeID      int32            `db:"market_type_id" json:"market_type_id"`
+	Outcome           string           `db:"outcome" json:"outcome"`
+	MarketParams      []byte           `db:"market_params" json:"market_params"`
+	OpeningValue      *float64         `db:"opening_value" json:"opening_value"`
+	ClosingValue      float64          `db:"closing_value" json:"closing_value"`
+	ClosingRecordedAt pgtype.Timestamp `db:"closing_recorded_at" json:"closing_recorded_at"`
+	FrozenAt          pgtype.Timestamp `db:"frozen_at" json:"frozen_at"`
+}
+
 type ContrarianBet struct {
 	EventID           int32            `db:"event_id" json:"event_id"`
 	EventSlug         string           `db:"event_slug" json:"event_slug"`
@@ -108,6 +166,9 @@ type CurrentOdd struct {
 	MovementPercentage *float32         `db:"movement_percentage" json:"movement_percentage"`
 	LastUpdated        pgtype.Timestamp `db:"last_updated" json:"last_updated"`
 	MarketParams       []byte           `db:"market_params" json:"market_params"`
+	IsSuspended        bool             `db:"is_suspended" json:"is_suspended"`
+	SuspendedAt        pgtype.Timestamp `db:"suspended_at" json:"suspended_at"`
+	Bookmaker          string           `db:"bookmaker" json:"bookmaker"`
 }
 
 type Event struct {
@@ -139,6 +200,44 @@ type Event struct {
 	UpdatedAt               pgtype.Timestamp `db:"updated_at" json:"updated_at"`
 }
 
+type EventResult struct {
+	EventID     int32            `db:"event_id" json:"event_id"`
+	HomeScore   int32            `db:"home_score" json:"home_score"`
+	AwayScore   int32            `db:"away_score" json:"away_score"`
+	HtHomeScore *int32           `db:"ht_home_score" json:"ht_home_score"`
+	HtAwayScore *int32           `db:"ht_away_score" json:"ht_away_score"`
+	CreatedAt   pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+	SettledAt   pgtype.Timestamp `db:"settled_at" json:"settled_at"`
+}
+
+type EventSyncVersion struct {
+	SportID        int32            `db:"sport_id" json:"sport_id"`
+	Version        int64            `db:"version" json:"version"`
+	LastSyncedAt   pgtype.Timestamp `db:"last_synced_at" json:"last_synced_at"`
+	LastFullSyncAt pgtype.Timestamp `db:"last_full_sync_at" json:"last_full_sync_at"`
+	DiffsSinceFull int32            `db:"diffs_since_full" json:"diffs_since_full"`
+	CreatedAt      pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+}
+
+type FairProbability struct {
+	ID                 int32            `db:"id" json:"id"`
+	EventID            int32            `db:"event_id" json:"event_id"`
+	MarketTypeID       int32            `db:"market_type_id" json:"market_type_id"`
+	Bookmaker          string           `db:"bookmaker" json:"bookmaker"`
+	MarketKey          string           `db:"market_key" json:"market_key"`
+	Outcome            string           `db:"outcome" json:"outcome"`
+	OddsValue          float64          `db:"odds_value" json:"odds_value"`
+	ImpliedProbability float32          `db:"implied_probability" json:"implied_probability"`
+	Multiplicative     float32          `db:"multiplicative" json:"multiplicative"`
+	Additive           *float32         `db:"additive" json:"additive"`
+	Power              *float32         `db:"power" json:"power"`
+	Shin               *float32         `db:"shin" json:"shin"`
+	FairOdds           float64          `db:"fair_odds" json:"fair_odds"`
+	CalculatedAt       pgtype.Timestamp `db:"calculated_at" json:"calculated_at"`
+}
+
 type HighVolumeEvent struct {
 	EventID                 int32            `db:"event_id" json:"event_id"`
 	EventSlug               string           `db:"event_slug" json:"event_slug"`
@@ -160,6 +259,40 @@ type HighVolumeEvent struct {
 	VolumeCategory          string           `db:"volume_category" json:"volume_category"`
 }
 
+type JobControl struct {
+	JobName          string           `db:"job_name" json:"job_name"`
+	DefaultSchedule  string           `db:"default_schedule" json:"default_schedule"`
+	ScheduleOverride *string          `db:"schedule_override" json:"schedule_override"`
+	Paused           bool             `db:"paused" json:"paused"`
+	RegisteredAt     pgtype.Timestamp `db:"registered_at" json:"registered_at"`
+	UpdatedAt        pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+}
+
+type JobRun struct {
+	ID             int64            `db:"id" json:"id"`
+	JobName        string           `db:"job_name" json:"job_name"`
+	InstanceID     string           `db:"instance_id" json:"instance_id"`
+	Trigger        string           `db:"trigger" json:"trigger"`
+	Status         string           `db:"status" json:"status"`
+	LockOutcome    *string          `db:"lock_outcome" json:"lock_outcome"`
+	StartedAt      pgtype.Timestamp `db:"started_at" json:"started_at"`
+	FinishedAt     pgtype.Timestamp `db:"finished_at" json:"finished_at"`
+	ItemsProcessed *int32           `db:"items_processed" json:"items_processed"`
+	Error          *string          `db:"error" json:"error"`
+}
+
+type JobTrigger struct {
+	ID          int64            `db:"id" json:"id"`
+	JobName     string           `db:"job_name" json:"job_name"`
+	Status      string           `db:"status" json:"status"`
+	RequestedAt pgtype.Timestamp `db:"requested_at" json:"requested_at"`
+	ClaimedBy   *string          `db:"claimed_by" json:"claimed_by"`
+	ClaimedAt   pgtype.Timestamp `db:"claimed_at" json:"claimed_at"`
+	FinishedAt  pgtype.Timestamp `db:"finished_at" json:"finished_at"`
+	RunID       *int64           `db:"run_id" json:"run_id"`
+	Error       *string          `db:"error" json:"error"`
+}
+
 type League struct {
 	ID                 int32            `db:"id" json:"id"`
 	ExternalID         string           `db:"external_id" json:"external_id"`
@@ -238,6 +371,35 @@ type LiveOpportunity struct {
 	LastUpdated             pgtype.Timestamp `db:"last_updated" json:"last_updated"`
 }
 
+type MarketMargin struct {
+	ID               int32            `db:"id" json:"id"`
+	EventID          int32            `db:"event_id" json:"event_id"`
+	MarketTypeID     int32            `db:"market_type_id" json:"market_type_id"`
+	Bookmaker        string           `db:"bookmaker" json:"bookmaker"`
+	MarketKey        string           `db:"market_key" json:"market_key"`
+	OutcomeCount     int32            `db:"outcome_count" json:"outcome_count"`
+	Booksum          float64          `db:"booksum" json:"booksum"`
+	MarginPercentage float32          `db:"margin_percentage" json:"margin_percentage"`
+	PowerK           *float64         `db:"power_k" json:"power_k"`
+	ShinZ            *float64         `db:"shin_z" json:"shin_z"`
+	CalculatedAt     pgtype.Timestamp `db:"calculated_at" json:"calculated_at"`
+	CreatedAt        pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+}
+
+type MarketMarginHistory struct {
+	ID               int64            `db:"id" json:"id"`
+	EventID          int32            `db:"event_id" json:"event_id"`
+	MarketTypeID     int32            `db:"market_type_id" json:"market_type_id"`
+	Bookmaker        string           `db:"bookmaker" json:"bookmaker"`
+	MarketKey        string           `db:"market_key" json:"market_key"`
+	OutcomeCount     int32            `db:"outcome_count" json:"outcome_count"`
+	Booksum          float64          `db:"booksum" json:"booksum"`
+	MarginPercentage float32          `db:"margin_percentage" json:"margin_percentage"`
+	PreviousMargin   *float32         `db:"previous_margin" json:"previous_margin"`
+	RecordedAt       pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
+}
+
 type MarketType struct {
 	ID                    int32            `db:"id" json:"id"`
 	Code                  string           `db:"code" json:"code"`
@@ -311,6 +473,54 @@ type MovementAlert struct {
 	Clicks           int32            `db:"clicks" json:"clicks"`
 }
 
+type OddsCandles1d struct {
+	EventID         int32            `db:"event_id" json:"event_id"`
+	MarketTypeID    int32            `db:"market_type_id" json:"market_type_id"`
+	Bookmaker       string           `db:"bookmaker" json:"bookmaker"`
+	MarketKey       string           `db:"market_key" json:"market_key"`
+	Outcome         string           `db:"outcome" json:"outcome"`
+	BucketStart     pgtype.Timestamp `db:"bucket_start" json:"bucket_start"`
+	OpenOdds        float64          `db:"open_odds" json:"open_odds"`
+	HighOdds        float64          `db:"high_odds" json:"high_odds"`
+	LowOdds         float64          `db:"low_odds" json:"low_odds"`
+	CloseOdds       float64          `db:"close_odds" json:"close_odds"`
+	TickCount       int32            `db:"tick_count" json:"tick_count"`
+	FirstRecordedAt pgtype.Timestamp `db:"first_recorded_at" json:"first_recorded_at"`
+	LastRecordedAt  pgtype.Timestamp `db:"last_recorded_at" json:"last_recorded_at"`
+}
+
+type OddsCandles1h struct {
+	EventID         int32            `db:"event_id" json:"event_id"`
+	MarketTypeID    int32            `db:"market_type_id" json:"market_type_id"`
+	Bookmaker       string           `db:"bookmaker" json:"bookmaker"`
+	MarketKey       string           `db:"market_key" json:"market_key"`
+	Outcome         string           `db:"outcome" json:"outcome"`
+	BucketStart     pgtype.Timestamp `db:"bucket_start" json:"bucket_start"`
+	OpenOdds        float64          `db:"open_odds" json:"open_odds"`
+	HighOdds        float64          `db:"high_odds" json:"high_odds"`
+	LowOdds         float64          `db:"low_odds" json:"low_odds"`
+	CloseOdds       float64          `db:"close_odds" json:"close_odds"`
+	TickCount       int32            `db:"tick_count" json:"tick_count"`
+	FirstRecordedAt pgtype.Timestamp `db:"first_recorded_at" json:"first_recorded_at"`
+	LastRecordedAt  pgtype.Timestamp `db:"last_recorded_at" json:"last_recorded_at"`
+}
+
+type OddsCandles5m struct {
+	EventID         int32            `db:"event_id" json:"event_id"`
+	MarketTypeID    int32            `db:"market_type_id" json:"market_type_id"`
+	Bookmaker       string           `db:"bookmaker" json:"bookmaker"`
+	MarketKey       string           `db:"market_key" json:"market_key"`
+	Outcome         string           `db:"outcome" json:"outcome"`
+	BucketStart     pgtype.Timestamp `db:"bucket_start" json:"bucket_start"`
+	OpenOdds        float64          `db:"open_odds" json:"open_odds"`
+	HighOdds        float64          `db:"high_odds" json:"high_odds"`
+	LowOdds         float64          `db:"low_odds" json:"low_odds"`
+	CloseOdds       float64          `db:"close_odds" json:"close_odds"`
+	TickCount       int32            `db:"tick_count" json:"tick_count"`
+	FirstRecordedAt pgtype.Timestamp `db:"first_recorded_at" json:"first_recorded_at"`
+	LastRecordedAt  pgtype.Timestamp `db:"last_recorded_at" json:"last_recorded_at"`
+}
+
 type OddsHistory struct {
 	ID                  int32            `db:"id" json:"id"`
 	EventID             *int32           `db:"event_id" json:"event_id"`
@@ -328,6 +538,11 @@ type OddsHistory struct {
 	MinutesToKickoff    *int32           `db:"minutes_to_kickoff" json:"minutes_to_kickoff"`
 	MarketParams        []byte           `db:"market_params" json:"market_params"`
 	RecordedAt          pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
+	InPlay              bool             `db:"in_play" json:"in_play"`
+	LiveHomeScore       *int32           `db:"live_home_score" json:"live_home_score"`
+	LiveAwayScore       *int32           `db:"live_away_score" json:"live_away_score"`
+	LiveMinute          *int32           `db:"live_minute" json:"live_minute"`
+	Bookmaker           string           `db:"bookmaker" json:"bookmaker"`
 }
 
 type OutcomeDistribution struct {
@@ -353,6 +568,16 @@ type OutcomeDistributionHistory struct {
 	RecordedAt         pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
 }
 
+type OutcomeSettlement struct {
+	ID           int32            `db:"id" json:"id"`
+	EventID      int32            `db:"event_id" json:"event_id"`
+	MarketTypeID int32            `db:"market_type_id" json:"market_type_id"`
+	Outcome      string           `db:"outcome" json:"outcome"`
+	MarketFamily string           `db:"market_family" json:"market_family"`
+	Result       string           `db:"result" json:"result"`
+	SettledAt    pgtype.Timestamp `db:"settled_at" json:"settled_at"`
+}
+
 type SharpMoneyMove struct {
 	EventID             int32            `db:"event_id" json:"event_id"`
 	EventSlug           string           `db:"event_slug" json:"event_slug"`
@@ -381,6 +606,58 @@ type SharpMoneyMove struct {
 	RecordedAt          pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
 }
 
+type SmartMoneyPreference struct {
+	ID                  int32            `db:"id" json:"id"`
+	UserID              int32            `db:"user_id" json:"user_id"`
+	MinChangePercentage float32          `db:"min_change_percentage" json:"min_change_percentage"`
+	MinMultiplier       float64          `db:"min_multiplier" json:"min_multiplier"`
+	MinConfidenceScore  float32          `db:"min_confidence_score" json:"min_confidence_score"`
+	BigMoverAlerts      bool             `db:"big_mover_alerts" json:"big_mover_alerts"`
+	ReverseLineAlerts   bool             `db:"reverse_line_alerts" json:"reverse_line_alerts"`
+	SharpMoneyAlerts    bool             `db:"sharp_money_alerts" json:"sharp_money_alerts"`
+	ValueSpotAlerts     bool             `db:"value_spot_alerts" json:"value_spot_alerts"`
+	PreferredSports     []int32          `db:"preferred_sports" json:"preferred_sports"`
+	PreferredLeagues    []int32          `db:"preferred_leagues" json:"preferred_leagues"`
+	MaxDailyAlerts      int32            `db:"max_daily_alerts" json:"max_daily_alerts"`
+	PushNotifications   bool             `db:"push_notifications" json:"push_notifications"`
+	QuietHoursStart     *int32           `db:"quiet_hours_start" json:"quiet_hours_start"`
+	QuietHoursEnd       *int32           `db:"quiet_hours_end" json:"quiet_hours_end"`
+	CreatedAt           pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt           pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+}
+
+type SmartMoneyRule struct {
+	ID                  int32            `db:"id" json:"id"`
+	Name                string           `db:"name" json:"name"`
+	Description         *string          `db:"description" json:"description"`
+	IsActive            bool             `db:"is_active" json:"is_active"`
+	Priority            int32            `db:"priority" json:"priority"`
+	MinChangePct        *float32         `db:"min_change_pct" json:"min_change_pct"`
+	MaxChangePct        *float32         `db:"max_change_pct" json:"max_change_pct"`
+	MinAbsChangePct     *float32         `db:"min_abs_change_pct" json:"min_abs_change_pct"`
+	MinMultiplier       *float64         `db:"min_multiplier" json:"min_multiplier"`
+	MaxMultiplier       *float64         `db:"max_multiplier" json:"max_multiplier"`
+	MinBetPct           *float32         `db:"min_bet_pct" json:"min_bet_pct"`
+	MaxBetPct           *float32         `db:"max_bet_pct" json:"max_bet_pct"`
+	MinPublicBias       *float32         `db:"min_public_bias" json:"min_public_bias"`
+	MinVolumeRank       *int32           `db:"min_volume_rank" json:"min_volume_rank"`
+	MaxVolumeRank       *int32           `db:"max_volume_rank" json:"max_volume_rank"`
+	MaxVolumePct        *float32         `db:"max_volume_pct" json:"max_volume_pct"`
+	MinMinutesToKickoff *int32           `db:"min_minutes_to_kickoff" json:"min_minutes_to_kickoff"`
+	MaxMinutesToKickoff *int32           `db:"max_minutes_to_kickoff" json:"max_minutes_to_kickoff"`
+	MinSharpScore       *int32           `db:"min_sharp_score" json:"min_sharp_score"`
+	MinMovesLastHour    *int32           `db:"min_moves_last_hour" json:"min_moves_last_hour"`
+	AlertType           string           `db:"alert_type" json:"alert_type"`
+	Severity            *string          `db:"severity" json:"severity"`
+	ConfidenceSource    string           `db:"confidence_source" json:"confidence_source"`
+	BaseConfidence      float32          `db:"base_confidence" json:"base_confidence"`
+	TitleTemplate       string           `db:"title_template" json:"title_template"`
+	MessageTemplate     string           `db:"message_template" json:"message_template"`
+	MaxAlerts           int32            `db:"max_alerts" json:"max_alerts"`
+	CreatedAt           pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt           pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+}
+
 type Sport struct {
 	ID                int32            `db:"id" json:"id"`
 	Name              string           `db:"name" json:"name"`
@@ -443,6 +720,15 @@ type TeamMapping struct {
 	UpdatedAt            pgtype.Timestamp `db:"updated_at" json:"updated_at"`
 }
 
+type User struct {
+	ID        int32            `db:"id" json:"id"`
+	Email     string           `db:"email" json:"email"`
+	Name      *string          `db:"name" json:"name"`
+	IsActive  bool             `db:"is_active" json:"is_active"`
+	CreatedAt pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+}
+
 type ValueSpot struct {
 	EventID            int32            `db:"event_id" json:"event_id"`
 	EventSlug          string           `db:"event_slug" json:"event_slug"`
@@ -471,3 +757,54 @@ type ValueSpot struct {
 	Recommendation     string           `db:"recommendation" json:"recommendation"`
 	LastUpdated        pgtype.Timestamp `db:"last_updated" json:"last_updated"`
 }
+
+type WebhookDeadLetter struct {
+	ID             int32            `db:"id" json:"id"`
+	DeliveryID     int32            `db:"delivery_id" json:"delivery_id"`
+	SubscriptionID int32            `db:"subscription_id" json:"subscription_id"`
+	AlertID        int32            `db:"alert_id" json:"alert_id"`
+	Payload        []byte           `db:"payload" json:"payload"`
+	Attempts       int32            `db:"attempts" json:"attempts"`
+	LastError      *string          `db:"last_error" json:"last_error"`
+	CreatedAt      pgtype.Timestamp `db:"created_at" json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int32            `db:"id" json:"id"`
+	SubscriptionID int32            `db:"subscription_id" json:"subscription_id"`
+	AlertID        int32            `db:"alert_id" json:"alert_id"`
+	Payload        []byte           `db:"payload" json:"payload"`
+	Status         string           `db:"status" json:"status"`
+	Attempts       int32            `db:"attempts" json:"attempts"`
+	ResponseStatus *int32           `db:"response_status" json:"response_status"`
+	LastError      *string          `db:"last_error" json:"last_error"`
+	NextAttemptAt  pgtype.Timestamp `db:"next_attempt_at" json:"next_attempt_at"`
+	DeliveredAt    pgtype.Timestamp `db:"delivered_at" json:"delivered_at"`
+	CreatedAt      pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+}
+
+type WebhookDeliveryAttempt struct {
+	ID             int32            `db:"id" json:"id"`
+	DeliveryID     int32            `db:"delivery_id" json:"delivery_id"`
+	Attempt        int32            `db:"attempt" json:"attempt"`
+	ResponseStatus *int32           `db:"response_status" json:"response_status"`
+	Error          *string          `db:"error" json:"error"`
+	DurationMs     int32            `db:"duration_ms" json:"duration_ms"`
+	AttemptedAt    pgtype.Timestamp `db:"attempted_at" json:"attempted_at"`
+}
+
+type WebhookSubscription struct {
+	ID            int32            `db:"id" json:"id"`
+	Name          string           `db:"name" json:"name"`
+	Url           string           `db:"url" json:"url"`
+	Secret        string           `db:"secret" json:"secret"`
+	AlertTypes    []string         `db:"alert_types" json:"alert_types"`
+	Severities    []string         `db:"severities" json:"severities"`
+	SportCodes    []string         `db:"sport_codes" json:"sport_codes"`
+	MinConfidence float32          `db:"min_confidence" json:"min_confidence"`
+	IsActive      bool             `db:"is_active" json:"is_active"`
+	CreatedAt     pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt     pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+	UserID        *int32           `db:"user_id" json:"user_id"`
+}
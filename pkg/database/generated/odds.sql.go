@@ -13,11 +13,12 @@ import (
 
 const batchGetCurrentOdds = `-- name: BatchGetCurrentOdds :many
 SELECT
-    id, event_id, market_type_id, outcome, odds_value, opening_value, highest_value, lowest_value, winning_odds, total_movement, movement_percentage, last_updated, market_params
+    id, event_id, market_type_id, outcome, odds_value, opening_value, highest_value, lowest_value, winning_odds, total_movement, movement_percentage, last_updated, market_params, is_suspended, suspended_at, bookmaker
 FROM
     current_odds
 WHERE
-    event_id = $1::int
+    bookmaker = 'iddaa'
+    AND event_id = $1::int
     AND market_type_id = ANY($2::int)
     AND outcome = ANY($3::text[])
 `
@@ -51,6 +52,9 @@ func (q *Queries) BatchGetCurrentOdds(ctx context.Context, arg BatchGetCurrentOd
 			&i.MovementPercentage,
 			&i.LastUpdated,
 			&i.MarketParams,
+			&i.IsSuspended,
+			&i.SuspendedAt,
+			&i.Bookmaker,
 		); err != nil {
 			return nil, err
 		}
@@ -76,7 +80,8 @@ FROM
     current_odds co
     JOIN events e ON e.id = co.event_id
 WHERE
-    (co.event_id, co.market_type_id, co.outcome) IN (
+    co.bookmaker = 'iddaa'
+    AND (co.event_id, co.market_type_id, co.outcome) IN (
         SELECT
             unnest($1::int[]),
             unnest($2::int[]),
@@ -176,24 +181,25 @@ SELECT
     significance_level,
     minutes_to_kickoff,
     market_params,
-    NOW()
+    COALESCE($13::timestamp, NOW())
 FROM
     input_data
 `
 
 type BulkInsertOddsHistoryParams struct {
-	EventIds           []int32   `db:"event_ids" json:"event_ids"`
-	MarketTypeIds      []int32   `db:"market_type_ids" json:"market_type_ids"`
-	Outcomes           []string  `db:"outcomes" json:"outcomes"`
-	OddsValues         []float64 `db:"odds_values" json:"odds_values"`
-	PreviousValues     []float64 `db:"previous_values" json:"previous_values"`
-	ChangeAmounts      []float64 `db:"change_amounts" json:"change_amounts"`
-	ChangePercentages  []float64 `db:"change_percentages" json:"change_percentages"`
-	Multipliers        []float64 `db:"multipliers" json:"multipliers"`
-	IsReverseMovements []bool    `db:"is_reverse_movements" json:"is_reverse_movements"`
-	SignificanceLevels []string  `db:"significance_levels" json:"significance_levels"`
-	MinutesToKickoffs  []int32   `db:"minutes_to_kickoffs" json:"minutes_to_kickoffs"`
-	MarketParams       [][]byte  `db:"market_params" json:"market_params"`
+	EventIds           []int32          `db:"event_ids" json:"event_ids"`
+	MarketTypeIds      []int32          `db:"market_type_ids" json:"market_type_ids"`
+	Outcomes           []string         `db:"outcomes" json:"outcomes"`
+	OddsValues         []float64        `db:"odds_values" json:"odds_values"`
+	PreviousValues     []float64        `db:"previous_values" json:"previous_values"`
+	ChangeAmounts      []float64        `db:"change_amounts" json:"change_amounts"`
+	ChangePercentages  []float64        `db:"change_percentages" json:"change_percentages"`
+	Multipliers        []float64        `db:"multipliers" json:"multipliers"`
+	IsReverseMovements []bool           `db:"is_reverse_movements" json:"is_reverse_movements"`
+	SignificanceLevels []string         `db:"significance_levels" json:"significance_levels"`
+	MinutesToKickoffs  []int32          `db:"minutes_to_kickoffs" json:"minutes_to_kickoffs"`
+	MarketParams       [][]byte         `db:"market_params" json:"market_params"`
+	RecordedAt         pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
 }
 
 func (q *Queries) BulkInsertOddsHistory(ctx context.Context, arg BulkInsertOddsHistoryParams) error {
@@ -210,6 +216,7 @@ func (q *Queries) BulkInsertOddsHistory(ctx context.Context, arg BulkInsertOddsH
 		arg.SignificanceLevels,
 		arg.MinutesToKickoffs,
 		arg.MarketParams,
+		arg.RecordedAt,
 	)
 	return err
 }
@@ -244,27 +251,33 @@ SELECT
     odds_value,
     odds_value,
     market_params,
-    NOW()
+    COALESCE($6::timestamp, NOW())
 FROM
-    input_data ON CONFLICT (event_id, market_type_id, outcome) DO
+    input_data ON CONFLICT (event_id, market_type_id, outcome, bookmaker) DO
 UPDATE
 SET
     odds_value = EXCLUDED.odds_value,
     highest_value = GREATEST(current_odds.highest_value, EXCLUDED.odds_value),
     lowest_value = LEAST(current_odds.lowest_value, EXCLUDED.odds_value),
-    last_updated = NOW()
+    last_updated = EXCLUDED.last_updated
 WHERE
     current_odds.odds_value IS DISTINCT
 FROM
     EXCLUDED.odds_value
+    -- Replayed payloads never overwrite newer prices
+    AND (
+        current_odds.last_updated IS NULL
+        OR current_odds.last_updated <= EXCLUDED.last_updated
+    )
 `
 
 type BulkUpsertCurrentOddsParams struct {
-	EventIds      []int32   `db:"event_ids" json:"event_ids"`
-	MarketTypeIds []int32   `db:"market_type_ids" json:"market_type_ids"`
-	Outcomes      []string  `db:"outcomes" json:"outcomes"`
-	OddsValues    []float64 `db:"odds_values" json:"odds_values"`
-	MarketParams  [][]byte  `db:"market_params" json:"market_params"`
+	EventIds      []int32          `db:"event_ids" json:"event_ids"`
+	MarketTypeIds []int32          `db:"market_type_ids" json:"market_type_ids"`
+	Outcomes      []string         `db:"outcomes" json:"outcomes"`
+	OddsValues    []float64        `db:"odds_values" json:"odds_values"`
+	MarketParams  [][]byte         `db:"market_params" json:"market_params"`
+	RecordedAt    pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
 }
 
 func (q *Queries) BulkUpsertCurrentOdds(ctx context.Context, arg BulkUpsertCurrentOddsParams) error {
@@ -274,6 +287,7 @@ func (q *Queries) BulkUpsertCurrentOdds(ctx context.Context, arg BulkUpsertCurre
 		arg.Outcomes,
 		arg.OddsValues,
 		arg.MarketParams,
+		arg.RecordedAt,
 	)
 	return err
 }
@@ -321,7 +335,7 @@ VALUES
             ELSE 1
         END,
         $7::jsonb
-    ) RETURNING id, event_id, market_type_id, outcome, odds_value, previous_value, winning_odds, change_amount, change_percentage, multiplier, sharp_money_indicator, is_reverse_movement, significance_level, minutes_to_kickoff, market_params, recorded_at
+    ) RETURNING id, event_id, market_type_id, outcome, odds_value, previous_value, winning_odds, change_amount, change_percentage, multiplier, sharp_money_indicator, is_reverse_movement, significance_level, minutes_to_kickoff, market_params, recorded_at, in_play, live_home_score, live_away_score, live_minute, bookmaker
 `
 
 type CreateOddsHistoryParams struct {
@@ -362,13 +376,18 @@ func (q *Queries) CreateOddsHistory(ctx context.Context, arg CreateOddsHistoryPa
 		&i.MinutesToKickoff,
 		&i.MarketParams,
 		&i.RecordedAt,
+		&i.InPlay,
+		&i.LiveHomeScore,
+		&i.LiveAwayScore,
+		&i.LiveMinute,
+		&i.Bookmaker,
 	)
 	return i, err
 }
 
 const getBigMovers = `-- name: GetBigMovers :many
 SELECT
-    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at,
+    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at, oh.in_play, oh.live_home_score, oh.live_away_score, oh.live_minute, oh.bookmaker,
     e.slug as event_slug,
     mt.code as market_code
 FROM
@@ -376,7 +395,8 @@ FROM
     JOIN events e ON oh.event_id = e.id
     JOIN market_types mt ON oh.market_type_id = mt.id
 WHERE
-    ABS(oh.change_percentage) > $1::float8
+    oh.bookmaker = 'iddaa'
+    AND ABS(oh.change_percentage) > $1::float8
     AND oh.recorded_at > $2::timestamp
 ORDER BY
     ABS(oh.change_percentage) DESC
@@ -407,6 +427,11 @@ type GetBigMoversRow struct {
 	MinutesToKickoff    *int32           `db:"minutes_to_kickoff" json:"minutes_to_kickoff"`
 	MarketParams        []byte           `db:"market_params" json:"market_params"`
 	RecordedAt          pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
+	InPlay              bool             `db:"in_play" json:"in_play"`
+	LiveHomeScore       *int32           `db:"live_home_score" json:"live_home_score"`
+	LiveAwayScore       *int32           `db:"live_away_score" json:"live_away_score"`
+	LiveMinute          *int32           `db:"live_minute" json:"live_minute"`
+	Bookmaker           string           `db:"bookmaker" json:"bookmaker"`
 	EventSlug           string           `db:"event_slug" json:"event_slug"`
 	MarketCode          string           `db:"market_code" json:"market_code"`
 }
@@ -437,6 +462,11 @@ func (q *Queries) GetBigMovers(ctx context.Context, arg GetBigMoversParams) ([]G
 			&i.MinutesToKickoff,
 			&i.MarketParams,
 			&i.RecordedAt,
+			&i.InPlay,
+			&i.LiveHomeScore,
+			&i.LiveAwayScore,
+			&i.LiveMinute,
+			&i.Bookmaker,
 			&i.EventSlug,
 			&i.MarketCode,
 		); err != nil {
@@ -452,14 +482,15 @@ func (q *Queries) GetBigMovers(ctx context.Context, arg GetBigMoversParams) ([]G
 
 const getCurrentOdds = `-- name: GetCurrentOdds :many
 SELECT
-    co.id, co.event_id, co.market_type_id, co.outcome, co.odds_value, co.opening_value, co.highest_value, co.lowest_value, co.winning_odds, co.total_movement, co.movement_percentage, co.last_updated, co.market_params,
+    co.id, co.event_id, co.market_type_id, co.outcome, co.odds_value, co.opening_value, co.highest_value, co.lowest_value, co.winning_odds, co.total_movement, co.movement_percentage, co.last_updated, co.market_params, co.is_suspended, co.suspended_at, co.bookmaker,
     mt.name as market_name,
     mt.code as market_code
 FROM
     current_odds co
     JOIN market_types mt ON co.market_type_id = mt.id
 WHERE
-    co.event_id = $1::int
+    co.bookmaker = 'iddaa'
+    AND co.event_id = $1::int
 `
 
 type GetCurrentOddsRow struct {
@@ -476,6 +507,9 @@ type GetCurrentOddsRow struct {
 	MovementPercentage *float32         `db:"movement_percentage" json:"movement_percentage"`
 	LastUpdated        pgtype.Timestamp `db:"last_updated" json:"last_updated"`
 	MarketParams       []byte           `db:"market_params" json:"market_params"`
+	IsSuspended        bool             `db:"is_suspended" json:"is_suspended"`
+	SuspendedAt        pgtype.Timestamp `db:"suspended_at" json:"suspended_at"`
+	Bookmaker          string           `db:"bookmaker" json:"bookmaker"`
 	MarketName         string           `db:"market_name" json:"market_name"`
 	MarketCode         string           `db:"market_code" json:"market_code"`
 }
@@ -503,6 +537,9 @@ func (q *Queries) GetCurrentOdds(ctx context.Context, eventID int32) ([]GetCurre
 			&i.MovementPercentage,
 			&i.LastUpdated,
 			&i.MarketParams,
+			&i.IsSuspended,
+			&i.SuspendedAt,
+			&i.Bookmaker,
 			&i.MarketName,
 			&i.MarketCode,
 		); err != nil {
@@ -518,14 +555,15 @@ func (q *Queries) GetCurrentOdds(ctx context.Context, eventID int32) ([]GetCurre
 
 const getCurrentOddsByMarket = `-- name: GetCurrentOddsByMarket :many
 SELECT
-    co.id, co.event_id, co.market_type_id, co.outcome, co.odds_value, co.opening_value, co.highest_value, co.lowest_value, co.winning_odds, co.total_movement, co.movement_percentage, co.last_updated, co.market_params,
+    co.id, co.event_id, co.market_type_id, co.outcome, co.odds_value, co.opening_value, co.highest_value, co.lowest_value, co.winning_odds, co.total_movement, co.movement_percentage, co.last_updated, co.market_params, co.is_suspended, co.suspended_at, co.bookmaker,
     mt.name as market_name,
     mt.code as market_code
 FROM
     current_odds co
     JOIN market_types mt ON co.market_type_id = mt.id
 WHERE
-    co.event_id = $1::int
+    co.bookmaker = 'iddaa'
+    AND co.event_id = $1::int
     AND co.market_type_id = $2::int
 `
 
@@ -548,6 +586,9 @@ type GetCurrentOddsByMarketRow struct {
 	MovementPercentage *float32         `db:"movement_percentage" json:"movement_percentage"`
 	LastUpdated        pgtype.Timestamp `db:"last_updated" json:"last_updated"`
 	MarketParams       []byte           `db:"market_params" json:"market_params"`
+	IsSuspended        bool             `db:"is_suspended" json:"is_suspended"`
+	SuspendedAt        pgtype.Timestamp `db:"suspended_at" json:"suspended_at"`
+	Bookmaker          string           `db:"bookmaker" json:"bookmaker"`
 	MarketName         string           `db:"market_name" json:"market_name"`
 	MarketCode         string           `db:"market_code" json:"market_code"`
 }
@@ -575,6 +616,9 @@ func (q *Queries) GetCurrentOddsByMarket(ctx context.Context, arg GetCurrentOdds
 			&i.MovementPercentage,
 			&i.LastUpdated,
 			&i.MarketParams,
+			&i.IsSuspended,
+			&i.SuspendedAt,
+			&i.Bookmaker,
 			&i.MarketName,
 			&i.MarketCode,
 		); err != nil {
@@ -590,14 +634,15 @@ func (q *Queries) GetCurrentOddsByMarket(ctx context.Context, arg GetCurrentOdds
 
 const getCurrentOddsByOutcome = `-- name: GetCurrentOddsByOutcome :one
 SELECT
-    co.id, co.event_id, co.market_type_id, co.outcome, co.odds_value, co.opening_value, co.highest_value, co.lowest_value, co.winning_odds, co.total_movement, co.movement_percentage, co.last_updated, co.market_params,
+    co.id, co.event_id, co.market_type_id, co.outcome, co.odds_value, co.opening_value, co.highest_value, co.lowest_value, co.winning_odds, co.total_movement, co.movement_percentage, co.last_updated, co.market_params, co.is_suspended, co.suspended_at, co.bookmaker,
     mt.name as market_name,
     mt.code as market_code
 FROM
     current_odds co
     JOIN market_types mt ON co.market_type_id = mt.id
 WHERE
-    co.event_id = $1::int
+    co.bookmaker = 'iddaa'
+    AND co.event_id = $1::int
     AND co.market_type_id = $2::int
     AND co.outcome = $3::text
 `
@@ -622,6 +667,9 @@ type GetCurrentOddsByOutcomeRow struct {
 	MovementPercentage *float32         `db:"movement_percentage" json:"movement_percentage"`
 	LastUpdated        pgtype.Timestamp `db:"last_updated" json:"last_updated"`
 	MarketParams       []byte           `db:"market_params" json:"market_params"`
+	IsSuspended        bool             `db:"is_suspended" json:"is_suspended"`
+	SuspendedAt        pgtype.Timestamp `db:"suspended_at" json:"suspended_at"`
+	Bookmaker          string           `db:"bookmaker" json:"bookmaker"`
 	MarketName         string           `db:"market_name" json:"market_name"`
 	MarketCode         string           `db:"market_code" json:"market_code"`
 }
@@ -643,6 +691,9 @@ func (q *Queries) GetCurrentOddsByOutcome(ctx context.Context, arg GetCurrentOdd
 		&i.MovementPercentage,
 		&i.LastUpdated,
 		&i.MarketParams,
+		&i.IsSuspended,
+		&i.SuspendedAt,
+		&i.Bookmaker,
 		&i.MarketName,
 		&i.MarketCode,
 	)
@@ -651,7 +702,7 @@ func (q *Queries) GetCurrentOddsByOutcome(ctx context.Context, arg GetCurrentOdd
 
 const getOddsHistoryByID = `-- name: GetOddsHistoryByID :one
 SELECT
-    id, event_id, market_type_id, outcome, odds_value, previous_value, winning_odds, change_amount, change_percentage, multiplier, sharp_money_indicator, is_reverse_movement, significance_level, minutes_to_kickoff, market_params, recorded_at
+    id, event_id, market_type_id, outcome, odds_value, previous_value, winning_odds, change_amount, change_percentage, multiplier, sharp_money_indicator, is_reverse_movement, significance_level, minutes_to_kickoff, market_params, recorded_at, in_play, live_home_score, live_away_score, live_minute, bookmaker
 FROM
     odds_history
 WHERE
@@ -678,20 +729,26 @@ func (q *Queries) GetOddsHistoryByID(ctx context.Context, id int64) (OddsHistory
 		&i.MinutesToKickoff,
 		&i.MarketParams,
 		&i.RecordedAt,
+		&i.InPlay,
+		&i.LiveHomeScore,
+		&i.LiveAwayScore,
+		&i.LiveMinute,
+		&i.Bookmaker,
 	)
 	return i, err
 }
 
 const getOddsMovements = `-- name: GetOddsMovements :many
 SELECT
-    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at,
+    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at, oh.in_play, oh.live_home_score, oh.live_away_score, oh.live_minute, oh.bookmaker,
     mt.name as market_name,
     mt.code as market_code
 FROM
     odds_history oh
     JOIN market_types mt ON oh.market_type_id = mt.id
 WHERE
-    oh.event_id = $1::int
+    oh.bookmaker = 'iddaa'
+    AND oh.event_id = $1::int
 ORDER BY
     oh.recorded_at DESC
 LIMIT
@@ -720,6 +777,11 @@ type GetOddsMovementsRow struct {
 	MinutesToKickoff    *int32           `db:"minutes_to_kickoff" json:"minutes_to_kickoff"`
 	MarketParams        []byte           `db:"market_params" json:"market_params"`
 	RecordedAt          pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
+	InPlay              bool             `db:"in_play" json:"in_play"`
+	LiveHomeScore       *int32           `db:"live_home_score" json:"live_home_score"`
+	LiveAwayScore       *int32           `db:"live_away_score" json:"live_away_score"`
+	LiveMinute          *int32           `db:"live_minute" json:"live_minute"`
+	Bookmaker           string           `db:"bookmaker" json:"bookmaker"`
 	MarketName          string           `db:"market_name" json:"market_name"`
 	MarketCode          string           `db:"market_code" json:"market_code"`
 }
@@ -750,6 +812,11 @@ func (q *Queries) GetOddsMovements(ctx context.Context, arg GetOddsMovementsPara
 			&i.MinutesToKickoff,
 			&i.MarketParams,
 			&i.RecordedAt,
+			&i.InPlay,
+			&i.LiveHomeScore,
+			&i.LiveAwayScore,
+			&i.LiveMinute,
+			&i.Bookmaker,
 			&i.MarketName,
 			&i.MarketCode,
 		); err != nil {
@@ -765,7 +832,7 @@ func (q *Queries) GetOddsMovements(ctx context.Context, arg GetOddsMovementsPara
 
 const getRecentOddsHistory = `-- name: GetRecentOddsHistory :many
 SELECT
-    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at,
+    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at, oh.in_play, oh.live_home_score, oh.live_away_score, oh.live_minute, oh.bookmaker,
     e.event_date,
     e.is_live,
     mt.name as market_name,
@@ -775,7 +842,8 @@ FROM
     JOIN events e ON oh.event_id = e.id
     JOIN market_types mt ON oh.market_type_id = mt.id
 WHERE
-    oh.recorded_at >= $1::timestamp
+    oh.bookmaker = 'iddaa'
+    AND oh.recorded_at >= $1::timestamp
     AND e.event_date > NOW()
     AND ABS(oh.change_percentage) >= $2::float8
 ORDER BY
@@ -807,6 +875,11 @@ type GetRecentOddsHistoryRow struct {
 	MinutesToKickoff    *int32           `db:"minutes_to_kickoff" json:"minutes_to_kickoff"`
 	MarketParams        []byte           `db:"market_params" json:"market_params"`
 	RecordedAt          pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
+	InPlay              bool             `db:"in_play" json:"in_play"`
+	LiveHomeScore       *int32           `db:"live_home_score" json:"live_home_score"`
+	LiveAwayScore       *int32           `db:"live_away_score" json:"live_away_score"`
+	LiveMinute          *int32           `db:"live_minute" json:"live_minute"`
+	Bookmaker           string           `db:"bookmaker" json:"bookmaker"`
 	EventDate           pgtype.Timestamp `db:"event_date" json:"event_date"`
 	IsLive              *bool            `db:"is_live" json:"is_live"`
 	MarketName          string           `db:"market_name" json:"market_name"`
@@ -839,6 +912,11 @@ func (q *Queries) GetRecentOddsHistory(ctx context.Context, arg GetRecentOddsHis
 			&i.MinutesToKickoff,
 			&i.MarketParams,
 			&i.RecordedAt,
+			&i.InPlay,
+			&i.LiveHomeScore,
+			&i.LiveAwayScore,
+			&i.LiveMinute,
+			&i.Bookmaker,
 			&i.EventDate,
 			&i.IsLive,
 			&i.MarketName,
@@ -884,7 +962,7 @@ VALUES
         0,
         -- First time, no movement percentage
         $9::jsonb
-    ) ON CONFLICT (event_id, market_type_id, outcome) DO
+    ) ON CONFLICT (event_id, market_type_id, outcome, bookmaker) DO
 UPDATE
 SET
     odds_value = EXCLUDED.odds_value,
@@ -903,7 +981,7 @@ SET
         ELSE 0
     END,
     market_params = EXCLUDED.market_params,
-    last_updated = CURRENT_TIMESTAMP RETURNING id, event_id, market_type_id, outcome, odds_value, opening_value, highest_value, lowest_value, winning_odds, total_movement, movement_percentage, last_updated, market_params
+    last_updated = CURRENT_TIMESTAMP RETURNING id, event_id, market_type_id, outcome, odds_value, opening_value, highest_value, lowest_value, winning_odds, total_movement, movement_percentage, last_updated, market_params, is_suspended, suspended_at, bookmaker
 `
 
 type UpsertCurrentOddsParams struct {
@@ -945,6 +1023,9 @@ func (q *Queries) UpsertCurrentOdds(ctx context.Context, arg UpsertCurrentOddsPa
 		&i.MovementPercentage,
 		&i.LastUpdated,
 		&i.MarketParams,
+		&i.IsSuspended,
+		&i.SuspendedAt,
+		&i.Bookmaker,
 	)
 	return i, err
 }
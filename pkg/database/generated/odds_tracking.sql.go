@@ -11,14 +11,57 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countSuspiciousMovements = `-- name: CountSuspiciousMovements :one
+SELECT
+    COUNT(*)::int
+FROM
+    odds_history oh
+    JOIN events e ON oh.event_id = e.id
+    JOIN leagues l ON e.league_id = l.id
+    JOIN sports s ON e.sport_id = s.id
+WHERE
+    oh.bookmaker = 'iddaa'
+    AND oh.multiplier > 0
+    AND GREATEST(oh.multiplier, 1.0 / oh.multiplier) >= $1::float8
+    AND oh.recorded_at > $2::timestamp
+    AND (
+        $3::text = ''
+        OR s.code = $3::text
+    )
+    AND (
+        $4::text = ''
+        OR l.name ILIKE '%' || $4::text || '%'
+    )
+`
+
+type CountSuspiciousMovementsParams struct {
+	MinFactor  float64          `db:"min_factor" json:"min_factor"`
+	SinceTime  pgtype.Timestamp `db:"since_time" json:"since_time"`
+	SportCode  string           `db:"sport_code" json:"sport_code"`
+	LeagueName string           `db:"league_name" json:"league_name"`
+}
+
+func (q *Queries) CountSuspiciousMovements(ctx context.Context, arg CountSuspiciousMovementsParams) (int32, error) {
+	row := q.db.QueryRow(ctx, countSuspiciousMovements,
+		arg.MinFactor,
+		arg.SinceTime,
+		arg.SportCode,
+		arg.LeagueName,
+	)
+	var column1 int32
+	err := row.Scan(&column1)
+	return column1, err
+}
+
 const getOddsChangesByMarket = `-- name: GetOddsChangesByMarket :many
 SELECT 
-    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at,
+    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at, oh.in_play, oh.live_home_score, oh.live_away_score, oh.live_minute, oh.bookmaker,
     mt.code as market_code,
     mt.name as market_name
 FROM odds_history oh
 JOIN market_types mt ON oh.market_type_id = mt.id
-WHERE oh.event_id = $1
+WHERE oh.bookmaker = 'iddaa'
+AND oh.event_id = $1
 AND oh.market_type_id = $2
 AND ABS(oh.change_percentage) > $3::float8
 ORDER BY oh.recorded_at DESC
@@ -47,6 +90,11 @@ type GetOddsChangesByMarketRow struct {
 	MinutesToKickoff    *int32           `db:"minutes_to_kickoff" json:"minutes_to_kickoff"`
 	MarketParams        []byte           `db:"market_params" json:"market_params"`
 	RecordedAt          pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
+	InPlay              bool             `db:"in_play" json:"in_play"`
+	LiveHomeScore       *int32           `db:"live_home_score" json:"live_home_score"`
+	LiveAwayScore       *int32           `db:"live_away_score" json:"live_away_score"`
+	LiveMinute          *int32           `db:"live_minute" json:"live_minute"`
+	Bookmaker           string           `db:"bookmaker" json:"bookmaker"`
 	MarketCode          string           `db:"market_code" json:"market_code"`
 	MarketName          string           `db:"market_name" json:"market_name"`
 }
@@ -78,6 +126,11 @@ func (q *Queries) GetOddsChangesByMarket(ctx context.Context, arg GetOddsChanges
 			&i.MinutesToKickoff,
 			&i.MarketParams,
 			&i.RecordedAt,
+			&i.InPlay,
+			&i.LiveHomeScore,
+			&i.LiveAwayScore,
+			&i.LiveMinute,
+			&i.Bookmaker,
 			&i.MarketCode,
 			&i.MarketName,
 		); err != nil {
@@ -93,12 +146,13 @@ func (q *Queries) GetOddsChangesByMarket(ctx context.Context, arg GetOddsChanges
 
 const getOddsHistory = `-- name: GetOddsHistory :many
 SELECT 
-    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at,
+    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at, oh.in_play, oh.live_home_score, oh.live_away_score, oh.live_minute, oh.bookmaker,
     mt.code as market_code,
     mt.name as market_name
 FROM odds_history oh
 JOIN market_types mt ON oh.market_type_id = mt.id
-WHERE oh.event_id = $1
+WHERE oh.bookmaker = 'iddaa'
+AND oh.event_id = $1
 ORDER BY oh.market_type_id, oh.outcome, oh.recorded_at DESC
 `
 
@@ -119,6 +173,11 @@ type GetOddsHistoryRow struct {
 	MinutesToKickoff    *int32           `db:"minutes_to_kickoff" json:"minutes_to_kickoff"`
 	MarketParams        []byte           `db:"market_params" json:"market_params"`
 	RecordedAt          pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
+	InPlay              bool             `db:"in_play" json:"in_play"`
+	LiveHomeScore       *int32           `db:"live_home_score" json:"live_home_score"`
+	LiveAwayScore       *int32           `db:"live_away_score" json:"live_away_score"`
+	LiveMinute          *int32           `db:"live_minute" json:"live_minute"`
+	Bookmaker           string           `db:"bookmaker" json:"bookmaker"`
 	MarketCode          string           `db:"market_code" json:"market_code"`
 	MarketName          string           `db:"market_name" json:"market_name"`
 }
@@ -150,6 +209,11 @@ func (q *Queries) GetOddsHistory(ctx context.Context, eventID *int32) ([]GetOdds
 			&i.MinutesToKickoff,
 			&i.MarketParams,
 			&i.RecordedAt,
+			&i.InPlay,
+			&i.LiveHomeScore,
+			&i.LiveAwayScore,
+			&i.LiveMinute,
+			&i.Bookmaker,
 			&i.MarketCode,
 			&i.MarketName,
 		); err != nil {
@@ -165,7 +229,7 @@ func (q *Queries) GetOddsHistory(ctx context.Context, eventID *int32) ([]GetOdds
 
 const getRecentMovements = `-- name: GetRecentMovements :many
 SELECT 
-    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at,
+    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at, oh.in_play, oh.live_home_score, oh.live_away_score, oh.live_minute, oh.bookmaker,
     e.slug as event_slug,
     e.event_date,
     e.status as event_status,
@@ -192,7 +256,8 @@ JOIN teams at ON e.away_team_id = at.id
 JOIN market_types mt ON oh.market_type_id = mt.id
 JOIN leagues l ON e.league_id = l.id
 JOIN sports s ON e.sport_id = s.id
-WHERE oh.recorded_at > $1
+WHERE oh.bookmaker = 'iddaa'
+AND oh.recorded_at > $1
 AND ABS(oh.change_percentage) > $2::float8
 ORDER BY oh.recorded_at DESC
 LIMIT $3
@@ -221,6 +286,11 @@ type GetRecentMovementsRow struct {
 	MinutesToKickoff        *int32           `db:"minutes_to_kickoff" json:"minutes_to_kickoff"`
 	MarketParams            []byte           `db:"market_params" json:"market_params"`
 	RecordedAt              pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
+	InPlay                  bool             `db:"in_play" json:"in_play"`
+	LiveHomeScore           *int32           `db:"live_home_score" json:"live_home_score"`
+	LiveAwayScore           *int32           `db:"live_away_score" json:"live_away_score"`
+	LiveMinute              *int32           `db:"live_minute" json:"live_minute"`
+	Bookmaker               string           `db:"bookmaker" json:"bookmaker"`
 	EventSlug               string           `db:"event_slug" json:"event_slug"`
 	EventDate               pgtype.Timestamp `db:"event_date" json:"event_date"`
 	EventStatus             string           `db:"event_status" json:"event_status"`
@@ -269,6 +339,11 @@ func (q *Queries) GetRecentMovements(ctx context.Context, arg GetRecentMovements
 			&i.MinutesToKickoff,
 			&i.MarketParams,
 			&i.RecordedAt,
+			&i.InPlay,
+			&i.LiveHomeScore,
+			&i.LiveAwayScore,
+			&i.LiveMinute,
+			&i.Bookmaker,
 			&i.EventSlug,
 			&i.EventDate,
 			&i.EventStatus,
@@ -300,25 +375,62 @@ func (q *Queries) GetRecentMovements(ctx context.Context, arg GetRecentMovements
 }
 
 const getSuspiciousMovements = `-- name: GetSuspiciousMovements :many
-SELECT 
-    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at,
+SELECT
+    oh.id,
+    oh.event_id,
+    oh.market_type_id,
+    oh.outcome,
+    oh.odds_value,
+    oh.previous_value,
+    oh.change_percentage,
+    oh.multiplier,
+    oh.sharp_money_indicator,
+    oh.is_reverse_movement,
+    oh.minutes_to_kickoff,
+    oh.recorded_at,
     e.slug as event_slug,
-    mt.code as market_code
-FROM odds_history oh
-JOIN events e ON oh.event_id = e.id
-JOIN market_types mt ON oh.market_type_id = mt.id
-WHERE oh.multiplier > 1.5 OR oh.multiplier < 0.67
-AND oh.recorded_at > $1
-ORDER BY CASE 
-    WHEN oh.multiplier > 1.0 THEN oh.multiplier
-    ELSE (1.0 / oh.multiplier)
-END DESC
-LIMIT $2
+    e.event_date,
+    (ht.name || ' vs ' || at.name)::text as match_name,
+    s.code as sport_code,
+    l.name as league_name,
+    mt.code as market_code,
+    mt.name as market_name,
+    GREATEST(oh.multiplier, 1.0 / oh.multiplier)::float8 as movement_factor
+FROM
+    odds_history oh
+    JOIN events e ON oh.event_id = e.id
+    JOIN teams ht ON e.home_team_id = ht.id
+    JOIN teams at ON e.away_team_id = at.id
+    JOIN leagues l ON e.league_id = l.id
+    JOIN sports s ON e.sport_id = s.id
+    JOIN market_types mt ON oh.market_type_id = mt.id
+WHERE
+    oh.bookmaker = 'iddaa'
+    AND oh.multiplier > 0
+    AND GREATEST(oh.multiplier, 1.0 / oh.multiplier) >= $1::float8
+    AND oh.recorded_at > $2::timestamp
+    AND (
+        $3::text = ''
+        OR s.code = $3::text
+    )
+    AND (
+        $4::text = ''
+        OR l.name ILIKE '%' || $4::text || '%'
+    )
+ORDER BY
+    GREATEST(oh.multiplier, 1.0 / oh.multiplier) DESC,
+    oh.id DESC
+LIMIT
+    $5::int OFFSET $6::int
 `
 
 type GetSuspiciousMovementsParams struct {
-	SinceTime  pgtype.Timestamp `db:"since_time" json:"since_time"`
-	LimitCount int64            `db:"limit_count" json:"limit_count"`
+	MinFactor   float64          `db:"min_factor" json:"min_factor"`
+	SinceTime   pgtype.Timestamp `db:"since_time" json:"since_time"`
+	SportCode   string           `db:"sport_code" json:"sport_code"`
+	LeagueName  string           `db:"league_name" json:"league_name"`
+	LimitCount  int32            `db:"limit_count" json:"limit_count"`
+	OffsetCount int32            `db:"offset_count" json:"offset_count"`
 }
 
 type GetSuspiciousMovementsRow struct {
@@ -328,23 +440,32 @@ type GetSuspiciousMovementsRow struct {
 	Outcome             string           `db:"outcome" json:"outcome"`
 	OddsValue           float64          `db:"odds_value" json:"odds_value"`
 	PreviousValue       *float64         `db:"previous_value" json:"previous_value"`
-	WinningOdds         *float64         `db:"winning_odds" json:"winning_odds"`
-	ChangeAmount        *float64         `db:"change_amount" json:"change_amount"`
 	ChangePercentage    *float32         `db:"change_percentage" json:"change_percentage"`
 	Multiplier          *float64         `db:"multiplier" json:"multiplier"`
 	SharpMoneyIndicator *float32         `db:"sharp_money_indicator" json:"sharp_money_indicator"`
 	IsReverseMovement   *bool            `db:"is_reverse_movement" json:"is_reverse_movement"`
-	SignificanceLevel   *string          `db:"significance_level" json:"significance_level"`
 	MinutesToKickoff    *int32           `db:"minutes_to_kickoff" json:"minutes_to_kickoff"`
-	MarketParams        []byte           `db:"market_params" json:"market_params"`
 	RecordedAt          pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
 	EventSlug           string           `db:"event_slug" json:"event_slug"`
+	EventDate           pgtype.Timestamp `db:"event_date" json:"event_date"`
+	MatchName           string           `db:"match_name" json:"match_name"`
+	SportCode           string           `db:"sport_code" json:"sport_code"`
+	LeagueName          string           `db:"league_name" json:"league_name"`
 	MarketCode          string           `db:"market_code" json:"market_code"`
+	MarketName          string           `db:"market_name" json:"market_name"`
+	MovementFactor      float64          `db:"movement_factor" json:"movement_factor"`
 }
 
 // Get potentially suspicious odds movements (sharp money indicators)
 func (q *Queries) GetSuspiciousMovements(ctx context.Context, arg GetSuspiciousMovementsParams) ([]GetSuspiciousMovementsRow, error) {
-	rows, err := q.db.Query(ctx, getSuspiciousMovements, arg.SinceTime, arg.LimitCount)
+	rows, err := q.db.Query(ctx, getSuspiciousMovements,
+		arg.MinFactor,
+		arg.SinceTime,
+		arg.SportCode,
+		arg.LeagueName,
+		arg.LimitCount,
+		arg.OffsetCount,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -359,18 +480,20 @@ func (q *Queries) GetSuspiciousMovements(ctx context.Context, arg GetSuspiciousM
 			&i.Outcome,
 			&i.OddsValue,
 			&i.PreviousValue,
-			&i.WinningOdds,
-			&i.ChangeAmount,
 			&i.ChangePercentage,
 			&i.Multiplier,
 			&i.SharpMoneyIndicator,
 			&i.IsReverseMovement,
-			&i.SignificanceLevel,
 			&i.MinutesToKickoff,
-			&i.MarketParams,
 			&i.RecordedAt,
 			&i.EventSlug,
+			&i.EventDate,
+			&i.MatchName,
+			&i.SportCode,
+			&i.LeagueName,
 			&i.MarketCode,
+			&i.MarketName,
+			&i.MovementFactor,
 		); err != nil {
 			return nil, err
 		}
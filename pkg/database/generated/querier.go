@@ -11,21 +11,29 @@ import (
 )
 
 type Querier interface {
+	// Closes runs still marked running long after any job timeout, their process died mid-run
+	AbandonStaleJobRuns(ctx context.Context, olderThanMinutes int32) (int64, error)
 	// Analyze correlation between volume and odds movement
-	AnalyzeVolumeOddsPattern(ctx context.Context) ([]byte, error)
+	AnalyzeVolumeOddsPattern(ctx context.Context, arg AnalyzeVolumeOddsPatternParams) ([]AnalyzeVolumeOddsPatternRow, error)
 	BatchGetCurrentOdds(ctx context.Context, arg BatchGetCurrentOddsParams) ([]CurrentOdd, error)
 	BulkCreateLeagueMappings(ctx context.Context, arg BulkCreateLeagueMappingsParams) error
 	// Helper query to get current odds for comparison
 	BulkGetCurrentOddsForComparison(ctx context.Context, arg BulkGetCurrentOddsForComparisonParams) ([]BulkGetCurrentOddsForComparisonRow, error)
 	// Bulk insert distribution history for changed values
 	BulkInsertDistributionHistory(ctx context.Context, arg BulkInsertDistributionHistoryParams) (int64, error)
+	// Same as BulkInsertOddsHistory for in-play events, stamped with the score and minute stored on the event
+	BulkInsertLiveOddsHistory(ctx context.Context, arg BulkInsertLiveOddsHistoryParams) error
 	BulkInsertOddsHistory(ctx context.Context, arg BulkInsertOddsHistoryParams) error
 	// This version ensures array ordering is preserved and validates data
 	BulkInsertOddsHistorySafe(ctx context.Context, arg BulkInsertOddsHistorySafeParams) error
 	// Bulk insert volume history records
 	BulkInsertVolumeHistory(ctx context.Context, arg BulkInsertVolumeHistoryParams) (int64, error)
+	// Suspends or resumes outcomes, returning the new state of each outcome that changed
+	BulkSetOddsSuspended(ctx context.Context, arg BulkSetOddsSuspendedParams) ([]bool, error)
 	// Bulk update event volumes with database-calculated ranks
 	BulkUpdateEventVolumes(ctx context.Context, arg BulkUpdateEventVolumesParams) (int64, error)
+	// Upserts provider prices and records a history row for every price that is new or changed
+	BulkUpsertBookmakerOdds(ctx context.Context, arg BulkUpsertBookmakerOddsParams) (int64, error)
 	BulkUpsertCurrentOdds(ctx context.Context, arg BulkUpsertCurrentOddsParams) error
 	// This version ensures array ordering is preserved by using ROW_NUMBER()
 	BulkUpsertCurrentOddsSafe(ctx context.Context, arg BulkUpsertCurrentOddsSafeParams) error
@@ -35,58 +43,170 @@ type Querier interface {
 	// Bulk upsert distributions with database-side calculations
 	BulkUpsertDistributions(ctx context.Context, arg BulkUpsertDistributionsParams) (int64, error)
 	BulkUpsertEvents(ctx context.Context, arg BulkUpsertEventsParams) ([]BulkUpsertEventsRow, error)
+	BulkUpsertFairProbabilities(ctx context.Context, arg BulkUpsertFairProbabilitiesParams) error
 	BulkUpsertLeagues(ctx context.Context, arg BulkUpsertLeaguesParams) (int64, error)
+	// Upserts market margins and records a history row for every margin that is new or moved
+	BulkUpsertMarketMargins(ctx context.Context, arg BulkUpsertMarketMarginsParams) (int64, error)
 	BulkUpsertMarketTypes(ctx context.Context, arg BulkUpsertMarketTypesParams) error
+	BulkUpsertOutcomeSettlements(ctx context.Context, arg BulkUpsertOutcomeSettlementsParams) error
 	BulkUpsertSports(ctx context.Context, arg BulkUpsertSportsParams) (int64, error)
 	BulkUpsertTeams(ctx context.Context, arg BulkUpsertTeamsParams) ([]BulkUpsertTeamsRow, error)
+	// Claims the oldest due deliveries for one dispatcher by moving their next attempt past the lease.
+	// A delivery whose dispatcher stopped before recording the result is due again once the lease ends.
+	ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]ClaimDueWebhookDeliveriesRow, error)
+	// Claims the oldest pending triggers of the given jobs for one cron instance
+	ClaimJobTriggers(ctx context.Context, arg ClaimJobTriggersParams) ([]JobTrigger, error)
+	CountBestPrices(ctx context.Context, arg CountBestPricesParams) (int32, error)
+	CountContrarianBets(ctx context.Context, arg CountContrarianBetsParams) (int32, error)
 	CountEventsFiltered(ctx context.Context, arg CountEventsFilteredParams) (int32, error)
+	CountHighVolumeEvents(ctx context.Context, arg CountHighVolumeEventsParams) (int32, error)
+	CountLeagueMarketMargins(ctx context.Context, arg CountLeagueMarketMarginsParams) (int32, error)
+	CountLiveOpportunities(ctx context.Context, arg CountLiveOpportunitiesParams) (int32, error)
+	CountSuspiciousMovements(ctx context.Context, arg CountSuspiciousMovementsParams) (int32, error)
+	CountTopVolumeEvents(ctx context.Context, arg CountTopVolumeEventsParams) (int32, error)
+	CountVolumeHistory(ctx context.Context, arg CountVolumeHistoryParams) (int32, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
 	CreateConfig(ctx context.Context, arg CreateConfigParams) (AppConfig, error)
 	CreateDistributionHistory(ctx context.Context, arg CreateDistributionHistoryParams) (OutcomeDistributionHistory, error)
 	CreateEnhancedLeagueMapping(ctx context.Context, arg CreateEnhancedLeagueMappingParams) (LeagueMapping, error)
 	CreateEnhancedTeamMapping(ctx context.Context, arg CreateEnhancedTeamMappingParams) (TeamMapping, error)
 	CreateEvent(ctx context.Context, arg CreateEventParams) (Event, error)
+	// Creates the missing monthly partitions of a history table covering from_time through to_time
+	CreateHistoryPartitions(ctx context.Context, arg CreateHistoryPartitionsParams) (int32, error)
+	CreateJobRun(ctx context.Context, arg CreateJobRunParams) (int64, error)
+	// Queues a manual run unless one is already pending for the job
+	CreateJobTrigger(ctx context.Context, jobName string) (JobTrigger, error)
 	CreateLeagueMapping(ctx context.Context, arg CreateLeagueMappingParams) (LeagueMapping, error)
 	CreateMatchEvent(ctx context.Context, arg CreateMatchEventParams) (MatchEvent, error)
 	CreateMovementAlert(ctx context.Context, arg CreateMovementAlertParams) (MovementAlert, error)
 	CreateOddsHistory(ctx context.Context, arg CreateOddsHistoryParams) (OddsHistory, error)
+	CreateSmartMoneyRule(ctx context.Context, arg CreateSmartMoneyRuleParams) (SmartMoneyRule, error)
 	CreateTeam(ctx context.Context, arg CreateTeamParams) (Team, error)
 	CreateTeamMapping(ctx context.Context, arg CreateTeamMappingParams) (TeamMapping, error)
+	// Creates a user together with default smart money preferences
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateVolumeHistory(ctx context.Context, arg CreateVolumeHistoryParams) (BettingVolumeHistory, error)
+	CreateWebhookDeliveryAttempt(ctx context.Context, arg CreateWebhookDeliveryAttemptParams) error
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
 	DeactivateExpiredAlerts(ctx context.Context) error
+	DeactivateWebhookSubscription(ctx context.Context, arg DeactivateWebhookSubscriptionParams) (int64, error)
+	DeleteDistributionHistoryRange(ctx context.Context, arg DeleteDistributionHistoryRangeParams) (int64, error)
 	DeleteLeague(ctx context.Context, id int32) error
+	// Deletes the daily candles starting in the range, before it is rolled up again
+	DeleteOddsCandles1dRange(ctx context.Context, arg DeleteOddsCandles1dRangeParams) (int64, error)
+	// Deletes the hourly candles starting in the range, before it is rolled up again
+	DeleteOddsCandles1hRange(ctx context.Context, arg DeleteOddsCandles1hRangeParams) (int64, error)
+	// Deletes the 5-minute candles starting in the range, before it is rolled up again
+	DeleteOddsCandles5mRange(ctx context.Context, arg DeleteOddsCandles5mRangeParams) (int64, error)
+	DeleteOddsHistoryRange(ctx context.Context, arg DeleteOddsHistoryRangeParams) (int64, error)
+	DeleteOldJobRuns(ctx context.Context, retentionDays int32) (int64, error)
+	DeleteOldJobTriggers(ctx context.Context, retentionDays int32) (int64, error)
+	DeleteSmartMoneyRule(ctx context.Context, id int32) (int64, error)
+	DeleteVolumeHistoryRange(ctx context.Context, arg DeleteVolumeHistoryRangeParams) (int64, error)
+	// Create pending deliveries for new alerts matching each active subscription
+	EnqueueWebhookDeliveries(ctx context.Context, sinceTime pgtype.Timestamp) (int64, error)
 	EnrichLeagueWithAPIFootball(ctx context.Context, arg EnrichLeagueWithAPIFootballParams) (League, error)
 	EnrichTeamWithAPIFootball(ctx context.Context, arg EnrichTeamWithAPIFootballParams) (Team, error)
+	// Expires triggers no cron instance claimed within an hour and fails runs whose instance stopped
+	ExpireJobTriggers(ctx context.Context) (int64, error)
+	FinishJobRun(ctx context.Context, arg FinishJobRunParams) error
+	FinishJobTrigger(ctx context.Context, arg FinishJobTriggerParams) error
+	// Freezes the last pre-kickoff price of every market/outcome for finished events
+	// Markets that never moved before kickoff close at their opening price
+	FreezeClosingOdds(ctx context.Context, eventIds []int32) (int64, error)
+	// Resolves a hashed API key to its active, unexpired owner
+	GetAPIKeyUser(ctx context.Context, keyHash string) (GetAPIKeyUserRow, error)
 	GetActiveAlerts(ctx context.Context, arg GetActiveAlertsParams) ([]GetActiveAlertsRow, error)
 	GetActiveEventsForDetailedSync(ctx context.Context, limitCount int32) ([]Event, error)
+	// CLV of alerts grouped by type and price direction at alert time
+	GetAlertCLVSummary(ctx context.Context, arg GetAlertCLVSummaryParams) ([]GetAlertCLVSummaryRow, error)
+	// Rolls graded alerts up by every dimension at once, "overall" is the grand total
+	GetAlertPerformance(ctx context.Context, arg GetAlertPerformanceParams) ([]GetAlertPerformanceRow, error)
+	// Active alerts matching the user's thresholds, alert types and followed sports/leagues
+	GetAlertsByUser(ctx context.Context, arg GetAlertsByUserParams) ([]GetAlertsByUserRow, error)
 	GetAllActiveEventsForDetailedSync(ctx context.Context) ([]Event, error)
 	// Bulk fetch all distributions for multiple events
 	GetAllDistributionsForEvents(ctx context.Context, externalIds []string) ([]GetAllDistributionsForEventsRow, error)
+	// Pre-kickoff public bet percentages, paged by id
+	GetBacktestDistributionHistory(ctx context.Context, arg GetBacktestDistributionHistoryParams) ([]GetBacktestDistributionHistoryRow, error)
+	GetBacktestEvents(ctx context.Context, arg GetBacktestEventsParams) ([]GetBacktestEventsRow, error)
+	// Pre-kickoff odds movements, paged by id
+	GetBacktestOddsHistory(ctx context.Context, arg GetBacktestOddsHistoryParams) ([]GetBacktestOddsHistoryRow, error)
+	GetBacktestSettlements(ctx context.Context, arg GetBacktestSettlementsParams) ([]GetBacktestSettlementsRow, error)
+	// Pre-kickoff betting volume snapshots, paged by id
+	GetBacktestVolumeHistory(ctx context.Context, arg GetBacktestVolumeHistoryParams) ([]GetBacktestVolumeHistoryRow, error)
+	// Upcoming outcomes where Iddaa's price deviates from the median of the other books
+	GetBestPrices(ctx context.Context, arg GetBestPricesParams) ([]GetBestPricesRow, error)
 	GetBigMovers(ctx context.Context, arg GetBigMoversParams) ([]GetBigMoversRow, error)
+	// Events with a frozen closing line and Iddaa odds history in the range
+	GetClosedEventsInHistoryRange(ctx context.Context, arg GetClosedEventsInHistoryRangeParams) ([]int32, error)
+	// Pre-kickoff odds_history snapshots of the given events with their frozen close
+	GetClosingLineSnapshots(ctx context.Context, eventIds []int32) ([]GetClosingLineSnapshotsRow, error)
+	GetClosingOddsByEvent(ctx context.Context, eventID int32) ([]GetClosingOddsByEventRow, error)
 	GetCurrentOdds(ctx context.Context, eventID int32) ([]GetCurrentOddsRow, error)
 	GetCurrentOddsByMarket(ctx context.Context, arg GetCurrentOddsByMarketParams) ([]GetCurrentOddsByMarketRow, error)
 	GetCurrentOddsByOutcome(ctx context.Context, arg GetCurrentOddsByOutcomeParams) (GetCurrentOddsByOutcomeRow, error)
+	// Iddaa current odds of the given events, same shape as ListCurrentOddsForCache
+	GetCurrentOddsForCacheByEvents(ctx context.Context, eventIds []int32) ([]GetCurrentOddsForCacheByEventsRow, error)
 	// Bulk fetch current odds for implied probability calculation
 	GetCurrentOddsForEvents(ctx context.Context, externalIds []string) ([]GetCurrentOddsForEventsRow, error)
 	GetCurrentOddsForOutcome(ctx context.Context, arg GetCurrentOddsForOutcomeParams) ([]CurrentOdd, error)
+	// Version of the latest write, 0 before the first one
+	GetCurrentOddsVersion(ctx context.Context) (int64, error)
 	GetEvent(ctx context.Context, id int32) (GetEventRow, error)
+	// Every bookmaker price for the outcomes Iddaa offers on an event
+	GetEventBookmakerOdds(ctx context.Context, eventID int32) ([]GetEventBookmakerOddsRow, error)
 	GetEventByExternalID(ctx context.Context, externalID string) (GetEventByExternalIDRow, error)
 	GetEventByExternalIDSimple(ctx context.Context, externalID string) (Event, error)
 	GetEventByID(ctx context.Context, id int32) (Event, error)
+	GetEventBySlug(ctx context.Context, slug string) (GetEventBySlugRow, error)
+	// Daily candles of an event from the given time, oldest first per outcome
+	GetEventCandles1d(ctx context.Context, arg GetEventCandles1dParams) ([]GetEventCandles1dRow, error)
+	// Hourly candles of an event from the given time, oldest first per outcome
+	GetEventCandles1h(ctx context.Context, arg GetEventCandles1hParams) ([]GetEventCandles1hRow, error)
+	// 5-minute candles of an event from the given time, oldest first per outcome
+	GetEventCandles5m(ctx context.Context, arg GetEventCandles5mParams) ([]GetEventCandles5mRow, error)
+	GetEventFairProbabilities(ctx context.Context, arg GetEventFairProbabilitiesParams) ([]GetEventFairProbabilitiesRow, error)
 	// Map external IDs to internal IDs
 	GetEventIDsByExternalIDs(ctx context.Context, externalIds []string) ([]GetEventIDsByExternalIDsRow, error)
+	GetEventMarginHistory(ctx context.Context, arg GetEventMarginHistoryParams) ([]GetEventMarginHistoryRow, error)
+	GetEventMarketMargins(ctx context.Context, arg GetEventMarketMarginsParams) ([]GetEventMarketMarginsRow, error)
+	// Results that were never settled or changed since the last settlement
+	GetEventResultsPendingSettlement(ctx context.Context, limitCount int32) ([]EventResult, error)
 	GetEventStatisticsSummary(ctx context.Context, eventID int32) (GetEventStatisticsSummaryRow, error)
+	GetEventSyncVersion(ctx context.Context, sportID int32) (EventSyncVersion, error)
 	// Bulk fetch events by external IDs
 	GetEventsByExternalIDs(ctx context.Context, externalIds []string) ([]GetEventsByExternalIDsRow, error)
 	GetEventsByTeam(ctx context.Context, arg GetEventsByTeamParams) ([]GetEventsByTeamRow, error)
-	// Find low-volume events with big movements (potential sharp money)
+	// Finished events of the last lookback_days that have odds but no frozen closing line yet. Events
+	// whose freeze stores nothing are retried until they fall out of the lookback.
+	GetEventsPendingClosingLines(ctx context.Context, arg GetEventsPendingClosingLinesParams) ([]int32, error)
+	// Alerts of outcomes settled or closed in the last window_days that are not graded yet or whose
+	// settlement or closing line changed since. Alerts are read from the last lookback_days only,
+	// which bounds the odds_history partitions probed.
+	GetGradableMovementAlerts(ctx context.Context, arg GetGradableMovementAlertsParams) ([]GetGradableMovementAlertsRow, error)
+	// Find low-volume events with big movements (potential sharp money).
+	// Volume change comes from betting_volume_history since since_time.
 	GetHiddenGems(ctx context.Context, arg GetHiddenGemsParams) ([]GetHiddenGemsRow, error)
-	// Find events with high betting volume AND significant odds movement
+	// Rolls the events GetHiddenGems finds up per sport, over all of them rather than one page
+	GetHiddenGemsBySport(ctx context.Context, arg GetHiddenGemsBySportParams) ([]GetHiddenGemsBySportRow, error)
+	// Find events with high betting volume AND significant odds movement.
+	// Volume change and best rank come from betting_volume_history since since_time.
 	GetHotMovers(ctx context.Context, arg GetHotMoversParams) ([]GetHotMoversRow, error)
+	// Rolls the events GetHotMovers finds up per sport, over all of them rather than one page
+	GetHotMoversBySport(ctx context.Context, arg GetHotMoversBySportParams) ([]GetHotMoversBySportRow, error)
+	// Iddaa outcomes provider prices are matched against
+	GetIddaaOutcomesForEvents(ctx context.Context, eventIds []int32) ([]GetIddaaOutcomesForEventsRow, error)
+	GetJobControl(ctx context.Context, jobName string) (JobControl, error)
 	GetLatestConfig(ctx context.Context, platform string) (AppConfig, error)
+	// Start of the newest 5-minute candle, the point the next rollup resumes from
+	GetLatestOddsCandle(ctx context.Context) (pgtype.Timestamp, error)
 	GetLatestOutcomeDistribution(ctx context.Context, arg GetLatestOutcomeDistributionParams) (OutcomeDistribution, error)
 	GetLeague(ctx context.Context, id int32) (League, error)
 	GetLeagueByExternalID(ctx context.Context, externalID string) (League, error)
 	GetLeagueMapping(ctx context.Context, internalLeagueID int32) (LeagueMapping, error)
+	// Margin statistics per league and market type for events kicking off since the given time
+	GetLeagueMarketMargins(ctx context.Context, arg GetLeagueMarketMarginsParams) ([]GetLeagueMarketMarginsRow, error)
 	GetLeaguesByAPIFootballID(ctx context.Context, apiFootballID *int32) ([]League, error)
 	GetLiveEvents(ctx context.Context) ([]GetLiveEventsRow, error)
 	GetMarketType(ctx context.Context, code string) (MarketType, error)
@@ -96,23 +216,45 @@ type Querier interface {
 	GetNationalTeams(ctx context.Context) ([]Team, error)
 	// Get odds changes for a specific market
 	GetOddsChangesByMarket(ctx context.Context, arg GetOddsChangesByMarketParams) ([]GetOddsChangesByMarketRow, error)
+	// Current prices of every scheduled or live event with a price change since the given time
+	GetOddsForMargins(ctx context.Context, sinceTime pgtype.Timestamp) ([]GetOddsForMarginsRow, error)
 	// Get full odds history for a specific event
 	GetOddsHistory(ctx context.Context, eventID *int32) ([]GetOddsHistoryRow, error)
 	GetOddsHistoryByID(ctx context.Context, id int64) (OddsHistory, error)
+	// Newest Iddaa odds_history id recorded in the range, 0 when empty
+	GetOddsHistoryRangeMaxID(ctx context.Context, arg GetOddsHistoryRangeMaxIDParams) (int32, error)
 	GetOddsMovements(ctx context.Context, arg GetOddsMovementsParams) ([]GetOddsMovementsRow, error)
 	GetOutcomeDistribution(ctx context.Context, arg GetOutcomeDistributionParams) (OutcomeDistribution, error)
+	GetOutcomeSettlementsByEvent(ctx context.Context, eventID int32) ([]OutcomeSettlement, error)
+	// Upcoming events whose league and both teams are mapped to API-Football
+	GetProviderOddsTargets(ctx context.Context, arg GetProviderOddsTargetsParams) ([]GetProviderOddsTargetsRow, error)
+	GetRecentAlertCLVs(ctx context.Context, arg GetRecentAlertCLVsParams) ([]GetRecentAlertCLVsRow, error)
 	// Smart Money Tracker queries
 	GetRecentBigMovers(ctx context.Context, arg GetRecentBigMoversParams) ([]GetRecentBigMoversRow, error)
 	// Get recent significant odds movements across all events
 	GetRecentMovements(ctx context.Context, arg GetRecentMovementsParams) ([]GetRecentMovementsRow, error)
 	GetRecentOddsHistory(ctx context.Context, arg GetRecentOddsHistoryParams) ([]GetRecentOddsHistoryRow, error)
+	// Prices of events as they stood before a replayed range: the last odds_history row recorded before
+	// it, or the opening price when the key had not moved yet
+	GetReplayOddsForComparison(ctx context.Context, arg GetReplayOddsForComparisonParams) ([]GetReplayOddsForComparisonRow, error)
 	// Detect TRUE reverse line movements where odds move against public betting percentages
 	GetReverseLineMovements(ctx context.Context, arg GetReverseLineMovementsParams) ([]GetReverseLineMovementsRow, error)
+	GetSettlementCandidates(ctx context.Context, eventID int32) ([]GetSettlementCandidatesRow, error)
 	// Comprehensive sharp money detection combining multiple factors
 	GetSharpMoneyIndicators(ctx context.Context, arg GetSharpMoneyIndicatorsParams) ([]GetSharpMoneyIndicatorsRow, error)
+	// Recent pre-kickoff movements with the context smart money rules evaluate, largest first.
+	// Moves in the last hour are counted over the hour before the window too.
+	GetSmartMoneyCandidates(ctx context.Context, arg GetSmartMoneyCandidatesParams) ([]GetSmartMoneyCandidatesRow, error)
+	GetSmartMoneyRule(ctx context.Context, id int32) (SmartMoneyRule, error)
+	// CLV across all pre-kickoff snapshots, the baseline alerts should beat
+	GetSnapshotCLVBaseline(ctx context.Context, arg GetSnapshotCLVBaselineParams) (GetSnapshotCLVBaselineRow, error)
 	GetSport(ctx context.Context, id int32) (Sport, error)
+	// CLV of steam moves: 3+ moves of at least 3% on one outcome within an hour
+	GetSteamMoveCLVSummary(ctx context.Context, arg GetSteamMoveCLVSummaryParams) (GetSteamMoveCLVSummaryRow, error)
 	// Detect rapid odds movements across multiple bookmakers (steam moves)
 	GetSteamMoves(ctx context.Context, arg GetSteamMovesParams) ([]GetSteamMovesRow, error)
+	GetStreamAlerts(ctx context.Context, ids []int32) ([]GetStreamAlertsRow, error)
+	GetStreamOdds(ctx context.Context, arg GetStreamOddsParams) ([]GetStreamOddsRow, error)
 	// Get potentially suspicious odds movements (sharp money indicators)
 	GetSuspiciousMovements(ctx context.Context, arg GetSuspiciousMovementsParams) ([]GetSuspiciousMovementsRow, error)
 	GetTeam(ctx context.Context, id int32) (Team, error)
@@ -123,16 +265,42 @@ type Querier interface {
 	GetTeamsByVenueCapacity(ctx context.Context, arg GetTeamsByVenueCapacityParams) ([]Team, error)
 	GetTeamsNeedingEnrichment(ctx context.Context, limitCount int64) ([]Team, error)
 	// Get current top events by betting volume
-	GetTopVolumeEvents(ctx context.Context) ([]GetTopVolumeEventsRow, error)
+	GetTopVolumeEvents(ctx context.Context, arg GetTopVolumeEventsParams) ([]GetTopVolumeEventsRow, error)
+	GetUser(ctx context.Context, id int32) (User, error)
+	GetUserSmartMoneyPreferences(ctx context.Context, userID int32) (SmartMoneyPreference, error)
 	GetValueSpots(ctx context.Context, arg GetValueSpotsParams) ([]GetValueSpotsRow, error)
-	// Get volume history for a specific event
-	GetVolumeHistory(ctx context.Context, eventID *int32) ([]GetVolumeHistoryRow, error)
+	// Get volume history for a specific event, newest first
+	GetVolumeHistory(ctx context.Context, arg GetVolumeHistoryParams) ([]GetVolumeHistoryRow, error)
+	// A subscription of one user, deactivated ones included for their delivery history
+	GetWebhookSubscription(ctx context.Context, arg GetWebhookSubscriptionParams) (WebhookSubscription, error)
+	ListAPIKeysByUser(ctx context.Context, userID int32) ([]ApiKey, error)
+	ListActiveSmartMoneyRules(ctx context.Context) ([]SmartMoneyRule, error)
+	ListBookmakers(ctx context.Context) ([]Bookmaker, error)
+	// Public-heavy outcomes from the contrarian_bets view, strongest signals first
+	ListContrarianBets(ctx context.Context, arg ListContrarianBetsParams) ([]ListContrarianBetsRow, error)
+	// Iddaa current odds of events starting from since_time on
+	ListCurrentOddsForCache(ctx context.Context, sinceTime pgtype.Timestamp) ([]ListCurrentOddsForCacheRow, error)
+	ListEventSyncVersions(ctx context.Context) ([]EventSyncVersion, error)
 	ListEventsByDate(ctx context.Context, eventDate pgtype.Timestamp) ([]ListEventsByDateRow, error)
 	ListEventsFiltered(ctx context.Context, arg ListEventsFilteredParams) ([]ListEventsFilteredRow, error)
+	// Events from the high_volume_events view, highest share of volume first
+	ListHighVolumeEvents(ctx context.Context, arg ListHighVolumeEventsParams) ([]ListHighVolumeEventsRow, error)
+	// Partitions of the given history tables with their range, oldest first
+	ListHistoryPartitions(ctx context.Context, parentTables []string) ([]ListHistoryPartitionsRow, error)
+	ListJobControls(ctx context.Context) ([]JobControl, error)
+	// Latest run of every job that ever ran, with its last success and the failures since
+	ListJobRunSummaries(ctx context.Context) ([]ListJobRunSummariesRow, error)
+	// Runs of one job, newest first, optionally of one status
+	ListJobRuns(ctx context.Context, arg ListJobRunsParams) ([]ListJobRunsRow, error)
+	// Manual triggers of one job, newest first
+	ListJobTriggers(ctx context.Context, arg ListJobTriggersParams) ([]JobTrigger, error)
 	ListLeagueMappings(ctx context.Context) ([]LeagueMapping, error)
 	ListLeagues(ctx context.Context) ([]League, error)
 	ListLeaguesForAPIEnrichment(ctx context.Context, limitCount int64) ([]League, error)
+	// In-play movements from the live_opportunities view, biggest moves first
+	ListLiveOpportunities(ctx context.Context, arg ListLiveOpportunitiesParams) ([]ListLiveOpportunitiesRow, error)
 	ListMarketTypes(ctx context.Context) ([]MarketType, error)
+	ListSmartMoneyRules(ctx context.Context) ([]SmartMoneyRule, error)
 	ListSports(ctx context.Context) ([]Sport, error)
 	ListTeamMappings(ctx context.Context) ([]TeamMapping, error)
 	ListTeamsByLeague(ctx context.Context, leagueID *int32) ([]Team, error)
@@ -140,64 +308,68 @@ type Querier interface {
 	ListUnmappedFootballLeagues(ctx context.Context) ([]League, error)
 	ListUnmappedLeagues(ctx context.Context) ([]League, error)
 	ListUnmappedTeams(ctx context.Context) ([]Team, error)
-	MarkAlertClicked(ctx context.Context, alertID int32) error
-	// COMMENTED OUT: Requires smart_money_preferences table
-	// -- name: GetAlertsByUser :many
-	// SELECT
-	//     ma.*,
-	//     oh.event_id,
-	//     oh.outcome,
-	//     e.external_id as event_external_id,
-	//     e.event_date,
-	//     ht.name as home_team_name,
-	//     at.name as away_team_name,
-	//     mt.name as market_name
-	// FROM movement_alerts ma
-	// JOIN odds_history oh ON ma.odds_history_id = oh.id
-	// JOIN events e ON oh.event_id = e.id
-	// LEFT JOIN teams ht ON e.home_team_id = ht.id
-	// LEFT JOIN teams at ON e.away_team_id = at.id
-	// JOIN market_types mt ON oh.market_type_id = mt.id
-	// JOIN smart_money_preferences smp ON smp.user_id = sqlc.arg(user_id)
-	// WHERE
-	//     ma.is_active = true
-	//     AND ma.expires_at > NOW()
-	//     AND ma.change_percentage >= smp.min_change_percentage
-	//     AND ma.multiplier >= smp.min_multiplier
-	//     AND ma.confidence_score >= smp.min_confidence_score
-	//     AND (
-	//         (ma.alert_type = 'big_mover' AND smp.big_mover_alerts = true) OR
-	//         (ma.alert_type = 'reverse_line' AND smp.reverse_line_alerts = true) OR
-	//         (ma.alert_type = 'sharp_money' AND smp.sharp_money_alerts = true) OR
-	//         (ma.alert_type = 'value_spot' AND smp.value_spot_alerts = true)
-	//     )
-	//     -- Filter by preferred sports/leagues if specified
-	//     AND (
-	//         smp.preferred_sports = '[]'::jsonb OR
-	//         e.sport_id::text = ANY(SELECT jsonb_array_elements_text(smp.preferred_sports))
-	//     )
-	// ORDER BY ma.created_at DESC
-	// LIMIT sqlc.arg(limit_count);
-	MarkAlertViewed(ctx context.Context, alertID int32) error
+	ListWebhookDeadLetters(ctx context.Context, arg ListWebhookDeadLettersParams) ([]WebhookDeadLetter, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookDeliveryAttempts(ctx context.Context, deliveryID int32) ([]WebhookDeliveryAttempt, error)
+	ListWebhookSubscriptions(ctx context.Context, userID int32) ([]WebhookSubscription, error)
+	// Records a click for the user, movement_alerts.clicks counts distinct clickers
+	MarkAlertClicked(ctx context.Context, arg MarkAlertClickedParams) error
+	// Records a view for the user, movement_alerts.views counts distinct viewers
+	MarkAlertViewed(ctx context.Context, arg MarkAlertViewedParams) error
+	MarkEventResultSettled(ctx context.Context, eventID int32) error
+	// Move an exhausted delivery to the dead-letter table
+	MarkWebhookDead(ctx context.Context, arg MarkWebhookDeadParams) error
+	MarkWebhookDelivered(ctx context.Context, arg MarkWebhookDeliveredParams) error
+	// Marks a committed write of Iddaa current odds
+	NextCurrentOddsVersion(ctx context.Context) (int64, error)
 	RefreshBigMovers(ctx context.Context) error
 	RefreshContrarianBets(ctx context.Context) error
 	RefreshHighVolumeEvents(ctx context.Context) error
 	RefreshLiveOpportunities(ctx context.Context) error
 	RefreshSharpMoneyMoves(ctx context.Context) error
 	RefreshValueSpots(ctx context.Context) error
+	// Registers the jobs of a cron instance, keeping their pause state and schedule override
+	RegisterJobControls(ctx context.Context, arg RegisterJobControlsParams) error
+	// Points the alerts of replaced rows at the replayed row of the same price closest in time, within
+	// 15 minutes. Alerts without a replayed row keep their old row and are deleted with it.
+	RelinkMovementAlertsRange(ctx context.Context, arg RelinkMovementAlertsRangeParams) (int64, error)
+	// Forces the next events sync for a sport to fetch the full bulletin
+	ResetEventSyncVersion(ctx context.Context, sportID int32) (int64, error)
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error)
+	// Rebuilds the daily candles from hourly candles from the day of since_time up to until_time
+	RollupOddsCandles1d(ctx context.Context, arg RollupOddsCandles1dParams) (int64, error)
+	// Rebuilds the hourly candles from 5-minute candles from the hour of since_time up to until_time
+	RollupOddsCandles1h(ctx context.Context, arg RollupOddsCandles1hParams) (int64, error)
+	// Rebuilds the 5-minute candles of odds_history rows recorded from the bucket of since_time up to until_time
+	RollupOddsCandles5m(ctx context.Context, arg RollupOddsCandles5mParams) (int64, error)
+	ScheduleWebhookRetry(ctx context.Context, arg ScheduleWebhookRetryParams) error
 	SearchTeams(ctx context.Context, arg SearchTeamsParams) ([]Team, error)
 	SearchTeamsByCode(ctx context.Context, arg SearchTeamsByCodeParams) ([]Team, error)
+	SetJobPaused(ctx context.Context, arg SetJobPausedParams) (JobControl, error)
+	// Sets the schedule of a job, NULL restores its default
+	SetJobScheduleOverride(ctx context.Context, arg SetJobScheduleOverrideParams) (JobControl, error)
+	// Records key usage at most once a minute to avoid a write per request
+	TouchAPIKey(ctx context.Context, id int32) error
 	UpdateEventLiveData(ctx context.Context, arg UpdateEventLiveDataParams) (Event, error)
 	UpdateEventStatus(ctx context.Context, arg UpdateEventStatusParams) (Event, error)
 	UpdateEventVolume(ctx context.Context, arg UpdateEventVolumeParams) (Event, error)
 	UpdateLeague(ctx context.Context, arg UpdateLeagueParams) (League, error)
 	UpdateLeagueApiFootballID(ctx context.Context, arg UpdateLeagueApiFootballIDParams) error
+	UpdateSmartMoneyRule(ctx context.Context, arg UpdateSmartMoneyRuleParams) (SmartMoneyRule, error)
 	UpdateSport(ctx context.Context, arg UpdateSportParams) (Sport, error)
 	UpdateTeam(ctx context.Context, arg UpdateTeamParams) (Team, error)
 	UpdateTeamApiFootballID(ctx context.Context, arg UpdateTeamApiFootballIDParams) error
+	// Stores graded alerts, re-grading replaces the previous result
+	UpsertAlertResults(ctx context.Context, arg UpsertAlertResultsParams) (int64, error)
+	UpsertBookmakers(ctx context.Context, arg UpsertBookmakersParams) error
+	// Stores the CLV of snapshots, recomputing replaces the previous value
+	UpsertClosingLineValues(ctx context.Context, arg UpsertClosingLineValuesParams) (int64, error)
 	UpsertConfig(ctx context.Context, arg UpsertConfigParams) (AppConfig, error)
 	UpsertCurrentOdds(ctx context.Context, arg UpsertCurrentOddsParams) (CurrentOdd, error)
 	UpsertEvent(ctx context.Context, arg UpsertEventParams) (Event, error)
+	UpsertEventResult(ctx context.Context, arg UpsertEventResultParams) error
+	// Records the bulletin version applied for a sport, full syncs reset the diff counter
+	UpsertEventSyncVersion(ctx context.Context, arg UpsertEventSyncVersionParams) (EventSyncVersion, error)
 	UpsertLeague(ctx context.Context, arg UpsertLeagueParams) (League, error)
 	UpsertLeagueMapping(ctx context.Context, arg UpsertLeagueMappingParams) (LeagueMapping, error)
 	UpsertMarketType(ctx context.Context, arg UpsertMarketTypeParams) (MarketType, error)
@@ -207,6 +379,9 @@ type Querier interface {
 	UpsertSport(ctx context.Context, arg UpsertSportParams) (Sport, error)
 	UpsertTeam(ctx context.Context, arg UpsertTeamParams) (Team, error)
 	UpsertTeamMapping(ctx context.Context, arg UpsertTeamMappingParams) (TeamMapping, error)
+	UpsertUserSmartMoneyPreferences(ctx context.Context, arg UpsertUserSmartMoneyPreferencesParams) (SmartMoneyPreference, error)
+	// Voids every outcome of cancelled events that have not been settled yet
+	VoidCancelledEventOutcomes(ctx context.Context) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
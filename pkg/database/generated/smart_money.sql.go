@@ -259,9 +259,145 @@ func (q *Queries) GetActiveAlerts(ctx context.Context, arg GetActiveAlertsParams
 	return items, nil
 }
 
+const getAlertsByUser = `-- name: GetAlertsByUser :many
+SELECT
+    ma.id, ma.odds_history_id, ma.alert_type, ma.severity, ma.title, ma.message, ma.change_percentage, ma.multiplier, ma.confidence_score, ma.minutes_to_kickoff, ma.created_at, ma.updated_at, ma.deleted_at, ma.expires_at, ma.is_active, ma.views, ma.clicks,
+    oh.event_id,
+    oh.outcome,
+    e.external_id as event_external_id,
+    e.slug as event_slug,
+    e.event_date,
+    ht.name as home_team_name,
+    at.name as away_team_name,
+    mt.name as market_name,
+    (uai.view_count IS NOT NULL AND uai.view_count > 0)::boolean as viewed,
+    (uai.click_count IS NOT NULL AND uai.click_count > 0)::boolean as clicked
+FROM
+    movement_alerts ma
+    JOIN odds_history oh ON ma.odds_history_id = oh.id
+    JOIN events e ON oh.event_id = e.id
+    LEFT JOIN teams ht ON e.home_team_id = ht.id
+    LEFT JOIN teams at ON e.away_team_id = at.id
+    JOIN market_types mt ON oh.market_type_id = mt.id
+    JOIN smart_money_preferences smp ON smp.user_id = $1::int
+    LEFT JOIN user_alert_interactions uai ON (
+        uai.alert_id = ma.id
+        AND uai.user_id = smp.user_id
+    )
+WHERE
+    ma.is_active = true
+    AND ma.expires_at > NOW()
+    AND ABS(ma.change_percentage) >= smp.min_change_percentage
+    AND ma.multiplier >= smp.min_multiplier
+    AND ma.confidence_score >= smp.min_confidence_score
+    AND (
+        (ma.alert_type = 'big_mover' AND smp.big_mover_alerts = true) OR
+        (ma.alert_type = 'reverse_line' AND smp.reverse_line_alerts = true) OR
+        (ma.alert_type = 'sharp_money' AND smp.sharp_money_alerts = true) OR
+        (ma.alert_type = 'value_spot' AND smp.value_spot_alerts = true)
+    )
+    -- Empty arrays follow every sport/league
+    AND (
+        cardinality(smp.preferred_sports) = 0 OR
+        e.sport_id = ANY(smp.preferred_sports)
+    )
+    AND (
+        cardinality(smp.preferred_leagues) = 0 OR
+        e.league_id = ANY(smp.preferred_leagues)
+    )
+ORDER BY
+    ma.created_at DESC
+LIMIT
+    $2::int
+`
+
+type GetAlertsByUserParams struct {
+	UserID     int32 `db:"user_id" json:"user_id"`
+	LimitCount int32 `db:"limit_count" json:"limit_count"`
+}
+
+type GetAlertsByUserRow struct {
+	ID               int32            `db:"id" json:"id"`
+	OddsHistoryID    int32            `db:"odds_history_id" json:"odds_history_id"`
+	AlertType        string           `db:"alert_type" json:"alert_type"`
+	Severity         string           `db:"severity" json:"severity"`
+	Title            string           `db:"title" json:"title"`
+	Message          string           `db:"message" json:"message"`
+	ChangePercentage float32          `db:"change_percentage" json:"change_percentage"`
+	Multiplier       float64          `db:"multiplier" json:"multiplier"`
+	ConfidenceScore  float32          `db:"confidence_score" json:"confidence_score"`
+	MinutesToKickoff *int32           `db:"minutes_to_kickoff" json:"minutes_to_kickoff"`
+	CreatedAt        pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+	DeletedAt        pgtype.Timestamp `db:"deleted_at" json:"deleted_at"`
+	ExpiresAt        pgtype.Timestamp `db:"expires_at" json:"expires_at"`
+	IsActive         bool             `db:"is_active" json:"is_active"`
+	Views            int32            `db:"views" json:"views"`
+	Clicks           int32            `db:"clicks" json:"clicks"`
+	EventID          *int32           `db:"event_id" json:"event_id"`
+	Outcome          string           `db:"outcome" json:"outcome"`
+	EventExternalID  string           `db:"event_external_id" json:"event_external_id"`
+	EventSlug        string           `db:"event_slug" json:"event_slug"`
+	EventDate        pgtype.Timestamp `db:"event_date" json:"event_date"`
+	HomeTeamName     *string          `db:"home_team_name" json:"home_team_name"`
+	AwayTeamName     *string          `db:"away_team_name" json:"away_team_name"`
+	MarketName       string           `db:"market_name" json:"market_name"`
+	Viewed           bool             `db:"viewed" json:"viewed"`
+	Clicked          bool             `db:"clicked" json:"clicked"`
+}
+
+// Active alerts matching the user's thresholds, alert types and followed sports/leagues
+func (q *Queries) GetAlertsByUser(ctx context.Context, arg GetAlertsByUserParams) ([]GetAlertsByUserRow, error) {
+	rows, err := q.db.Query(ctx, getAlertsByUser, arg.UserID, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetAlertsByUserRow{}
+	for rows.Next() {
+		var i GetAlertsByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OddsHistoryID,
+			&i.AlertType,
+			&i.Severity,
+			&i.Title,
+			&i.Message,
+			&i.ChangePercentage,
+			&i.Multiplier,
+			&i.ConfidenceScore,
+			&i.MinutesToKickoff,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ExpiresAt,
+			&i.IsActive,
+			&i.Views,
+			&i.Clicks,
+			&i.EventID,
+			&i.Outcome,
+			&i.EventExternalID,
+			&i.EventSlug,
+			&i.EventDate,
+			&i.HomeTeamName,
+			&i.AwayTeamName,
+			&i.MarketName,
+			&i.Viewed,
+			&i.Clicked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRecentBigMovers = `-- name: GetRecentBigMovers :many
 SELECT
-    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at,
+    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at, oh.in_play, oh.live_home_score, oh.live_away_score, oh.live_minute, oh.bookmaker,
     e.external_id as event_external_id,
     e.event_date,
     e.home_team_id,
@@ -278,7 +414,8 @@ FROM
     LEFT JOIN teams at ON e.away_team_id = at.id
     JOIN market_types mt ON oh.market_type_id = mt.id
 WHERE
-    (
+    oh.bookmaker = 'iddaa'
+    AND (
         ABS(oh.change_percentage) >= $1::float8
         OR oh.multiplier >= $2::float8
     )
@@ -314,6 +451,11 @@ type GetRecentBigMoversRow struct {
 	MinutesToKickoff    *int32           `db:"minutes_to_kickoff" json:"minutes_to_kickoff"`
 	MarketParams        []byte           `db:"market_params" json:"market_params"`
 	RecordedAt          pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
+	InPlay              bool             `db:"in_play" json:"in_play"`
+	LiveHomeScore       *int32           `db:"live_home_score" json:"live_home_score"`
+	LiveAwayScore       *int32           `db:"live_away_score" json:"live_away_score"`
+	LiveMinute          *int32           `db:"live_minute" json:"live_minute"`
+	Bookmaker           string           `db:"bookmaker" json:"bookmaker"`
 	EventExternalID     string           `db:"event_external_id" json:"event_external_id"`
 	EventDate           pgtype.Timestamp `db:"event_date" json:"event_date"`
 	HomeTeamID          *int32           `db:"home_team_id" json:"home_team_id"`
@@ -357,6 +499,11 @@ func (q *Queries) GetRecentBigMovers(ctx context.Context, arg GetRecentBigMovers
 			&i.MinutesToKickoff,
 			&i.MarketParams,
 			&i.RecordedAt,
+			&i.InPlay,
+			&i.LiveHomeScore,
+			&i.LiveAwayScore,
+			&i.LiveMinute,
+			&i.Bookmaker,
 			&i.EventExternalID,
 			&i.EventDate,
 			&i.HomeTeamID,
@@ -379,7 +526,7 @@ func (q *Queries) GetRecentBigMovers(ctx context.Context, arg GetRecentBigMovers
 
 const getReverseLineMovements = `-- name: GetReverseLineMovements :many
 SELECT
-    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at,
+    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at, oh.in_play, oh.live_home_score, oh.live_away_score, oh.live_minute, oh.bookmaker,
     e.external_id as event_external_id,
     e.event_date,
     e.home_team_id,
@@ -411,7 +558,8 @@ FROM
         AND oh.outcome = od.outcome
     )
 WHERE
-    oh.recorded_at >= $1
+    oh.bookmaker = 'iddaa'
+    AND oh.recorded_at >= $1
     AND e.event_date > NOW()
     AND od.bet_percentage IS NOT NULL
     -- True reverse line movements:
@@ -452,6 +600,11 @@ type GetReverseLineMovementsRow struct {
 	MinutesToKickoff        *int32           `db:"minutes_to_kickoff" json:"minutes_to_kickoff"`
 	MarketParams            []byte           `db:"market_params" json:"market_params"`
 	RecordedAt              pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
+	InPlay                  bool             `db:"in_play" json:"in_play"`
+	LiveHomeScore           *int32           `db:"live_home_score" json:"live_home_score"`
+	LiveAwayScore           *int32           `db:"live_away_score" json:"live_away_score"`
+	LiveMinute              *int32           `db:"live_minute" json:"live_minute"`
+	Bookmaker               string           `db:"bookmaker" json:"bookmaker"`
 	EventExternalID         string           `db:"event_external_id" json:"event_external_id"`
 	EventDate               pgtype.Timestamp `db:"event_date" json:"event_date"`
 	HomeTeamID              *int32           `db:"home_team_id" json:"home_team_id"`
@@ -494,6 +647,11 @@ func (q *Queries) GetReverseLineMovements(ctx context.Context, arg GetReverseLin
 			&i.MinutesToKickoff,
 			&i.MarketParams,
 			&i.RecordedAt,
+			&i.InPlay,
+			&i.LiveHomeScore,
+			&i.LiveAwayScore,
+			&i.LiveMinute,
+			&i.Bookmaker,
 			&i.EventExternalID,
 			&i.EventDate,
 			&i.HomeTeamID,
@@ -520,7 +678,7 @@ func (q *Queries) GetReverseLineMovements(ctx context.Context, arg GetReverseLin
 
 const getSharpMoneyIndicators = `-- name: GetSharpMoneyIndicators :many
 SELECT
-    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at,
+    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at, oh.in_play, oh.live_home_score, oh.live_away_score, oh.live_minute, oh.bookmaker,
     e.external_id as event_external_id,
     e.event_date,
     e.betting_volume_percentage,
@@ -576,7 +734,8 @@ FROM
         AND oh.outcome = od.outcome
     )
 WHERE
-    oh.recorded_at >= $1
+    oh.bookmaker = 'iddaa'
+    AND oh.recorded_at >= $1
     AND e.event_date > NOW()
     AND ABS(oh.change_percentage) >= 5
 ORDER BY
@@ -607,6 +766,11 @@ type GetSharpMoneyIndicatorsRow struct {
 	MinutesToKickoff        *int32           `db:"minutes_to_kickoff" json:"minutes_to_kickoff"`
 	MarketParams            []byte           `db:"market_params" json:"market_params"`
 	RecordedAt              pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
+	InPlay                  bool             `db:"in_play" json:"in_play"`
+	LiveHomeScore           *int32           `db:"live_home_score" json:"live_home_score"`
+	LiveAwayScore           *int32           `db:"live_away_score" json:"live_away_score"`
+	LiveMinute              *int32           `db:"live_minute" json:"live_minute"`
+	Bookmaker               string           `db:"bookmaker" json:"bookmaker"`
 	EventExternalID         string           `db:"event_external_id" json:"event_external_id"`
 	EventDate               pgtype.Timestamp `db:"event_date" json:"event_date"`
 	BettingVolumePercentage *float32         `db:"betting_volume_percentage" json:"betting_volume_percentage"`
@@ -647,6 +811,11 @@ func (q *Queries) GetSharpMoneyIndicators(ctx context.Context, arg GetSharpMoney
 			&i.MinutesToKickoff,
 			&i.MarketParams,
 			&i.RecordedAt,
+			&i.InPlay,
+			&i.LiveHomeScore,
+			&i.LiveAwayScore,
+			&i.LiveMinute,
+			&i.Bookmaker,
 			&i.EventExternalID,
 			&i.EventDate,
 			&i.BettingVolumePercentage,
@@ -671,7 +840,7 @@ func (q *Queries) GetSharpMoneyIndicators(ctx context.Context, arg GetSharpMoney
 
 const getSteamMoves = `-- name: GetSteamMoves :many
 SELECT
-    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at,
+    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at, oh.in_play, oh.live_home_score, oh.live_away_score, oh.live_minute, oh.bookmaker,
     e.external_id as event_external_id,
     e.event_date,
     e.betting_volume_percentage,
@@ -697,7 +866,8 @@ FROM
     LEFT JOIN teams at ON e.away_team_id = at.id
     JOIN market_types mt ON oh.market_type_id = mt.id
 WHERE
-    oh.recorded_at >= $1
+    oh.bookmaker = 'iddaa'
+    AND oh.recorded_at >= $1
     AND e.event_date > NOW()
     -- Significant movement
     AND ABS(oh.change_percentage) >= 3
@@ -705,7 +875,8 @@ WHERE
     AND oh.event_id IN (
         SELECT event_id 
         FROM odds_history 
-        WHERE recorded_at >= $1
+        WHERE bookmaker = 'iddaa'
+        AND recorded_at >= $1
         GROUP BY event_id, market_type_id, outcome
         HAVING COUNT(*) >= 3 -- At least 3 movements
     )
@@ -737,6 +908,11 @@ type GetSteamMovesRow struct {
 	MinutesToKickoff        *int32           `db:"minutes_to_kickoff" json:"minutes_to_kickoff"`
 	MarketParams            []byte           `db:"market_params" json:"market_params"`
 	RecordedAt              pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
+	InPlay                  bool             `db:"in_play" json:"in_play"`
+	LiveHomeScore           *int32           `db:"live_home_score" json:"live_home_score"`
+	LiveAwayScore           *int32           `db:"live_away_score" json:"live_away_score"`
+	LiveMinute              *int32           `db:"live_minute" json:"live_minute"`
+	Bookmaker               string           `db:"bookmaker" json:"bookmaker"`
 	EventExternalID         string           `db:"event_external_id" json:"event_external_id"`
 	EventDate               pgtype.Timestamp `db:"event_date" json:"event_date"`
 	BettingVolumePercentage *float32         `db:"betting_volume_percentage" json:"betting_volume_percentage"`
@@ -775,6 +951,11 @@ func (q *Queries) GetSteamMoves(ctx context.Context, arg GetSteamMovesParams) ([
 			&i.MinutesToKickoff,
 			&i.MarketParams,
 			&i.RecordedAt,
+			&i.InPlay,
+			&i.LiveHomeScore,
+			&i.LiveAwayScore,
+			&i.LiveMinute,
+			&i.Bookmaker,
 			&i.EventExternalID,
 			&i.EventDate,
 			&i.BettingVolumePercentage,
@@ -795,9 +976,38 @@ func (q *Queries) GetSteamMoves(ctx context.Context, arg GetSteamMovesParams) ([
 	return items, nil
 }
 
+const getUserSmartMoneyPreferences = `-- name: GetUserSmartMoneyPreferences :one
+SELECT id, user_id, min_change_percentage, min_multiplier, min_confidence_score, big_mover_alerts, reverse_line_alerts, sharp_money_alerts, value_spot_alerts, preferred_sports, preferred_leagues, max_daily_alerts, push_notifications, quiet_hours_start, quiet_hours_end, created_at, updated_at FROM smart_money_preferences WHERE user_id = $1::int
+`
+
+func (q *Queries) GetUserSmartMoneyPreferences(ctx context.Context, userID int32) (SmartMoneyPreference, error) {
+	row := q.db.QueryRow(ctx, getUserSmartMoneyPreferences, userID)
+	var i SmartMoneyPreference
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.MinChangePercentage,
+		&i.MinMultiplier,
+		&i.MinConfidenceScore,
+		&i.BigMoverAlerts,
+		&i.ReverseLineAlerts,
+		&i.SharpMoneyAlerts,
+		&i.ValueSpotAlerts,
+		&i.PreferredSports,
+		&i.PreferredLeagues,
+		&i.MaxDailyAlerts,
+		&i.PushNotifications,
+		&i.QuietHoursStart,
+		&i.QuietHoursEnd,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getValueSpots = `-- name: GetValueSpots :many
 SELECT
-    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at,
+    oh.id, oh.event_id, oh.market_type_id, oh.outcome, oh.odds_value, oh.previous_value, oh.winning_odds, oh.change_amount, oh.change_percentage, oh.multiplier, oh.sharp_money_indicator, oh.is_reverse_movement, oh.significance_level, oh.minutes_to_kickoff, oh.market_params, oh.recorded_at, oh.in_play, oh.live_home_score, oh.live_away_score, oh.live_minute, oh.bookmaker,
     od.bet_percentage,
     od.implied_probability,
     e.external_id as event_external_id,
@@ -821,7 +1031,8 @@ FROM
         AND oh.outcome = od.outcome
     )
 WHERE
-    oh.recorded_at >= $1
+    oh.bookmaker = 'iddaa'
+    AND oh.recorded_at >= $1
     AND e.event_date > NOW()
     AND od.bet_percentage > od.implied_probability + $2::float8
     AND ABS(oh.change_percentage) >= $3::float8
@@ -855,6 +1066,11 @@ type GetValueSpotsRow struct {
 	MinutesToKickoff    *int32           `db:"minutes_to_kickoff" json:"minutes_to_kickoff"`
 	MarketParams        []byte           `db:"market_params" json:"market_params"`
 	RecordedAt          pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
+	InPlay              bool             `db:"in_play" json:"in_play"`
+	LiveHomeScore       *int32           `db:"live_home_score" json:"live_home_score"`
+	LiveAwayScore       *int32           `db:"live_away_score" json:"live_away_score"`
+	LiveMinute          *int32           `db:"live_minute" json:"live_minute"`
+	Bookmaker           string           `db:"bookmaker" json:"bookmaker"`
 	BetPercentage       *float32         `db:"bet_percentage" json:"bet_percentage"`
 	ImpliedProbability  *float32         `db:"implied_probability" json:"implied_probability"`
 	EventExternalID     string           `db:"event_external_id" json:"event_external_id"`
@@ -899,6 +1115,11 @@ func (q *Queries) GetValueSpots(ctx context.Context, arg GetValueSpotsParams) ([
 			&i.MinutesToKickoff,
 			&i.MarketParams,
 			&i.RecordedAt,
+			&i.InPlay,
+			&i.LiveHomeScore,
+			&i.LiveAwayScore,
+			&i.LiveMinute,
+			&i.Bookmaker,
 			&i.BetPercentage,
 			&i.ImpliedProbability,
 			&i.EventExternalID,
@@ -922,70 +1143,202 @@ func (q *Queries) GetValueSpots(ctx context.Context, arg GetValueSpotsParams) ([
 }
 
 const markAlertClicked = `-- name: MarkAlertClicked :exec
+WITH interaction AS (
+    INSERT INTO
+        user_alert_interactions (user_id, alert_id, click_count, first_clicked_at, last_clicked_at)
+    VALUES
+        (
+            $1::int,
+            $2::int,
+            1,
+            CURRENT_TIMESTAMP,
+            CURRENT_TIMESTAMP
+        ) ON CONFLICT (user_id, alert_id) DO
+    UPDATE
+    SET
+        click_count = user_alert_interactions.click_count + 1,
+        first_clicked_at = COALESCE(user_alert_interactions.first_clicked_at, EXCLUDED.first_clicked_at),
+        last_clicked_at = EXCLUDED.last_clicked_at
+)
 UPDATE
     movement_alerts
 SET
     clicks = clicks + 1
 WHERE
-    id = $1
+    id = $2::int
+    AND NOT EXISTS (
+        SELECT
+            1
+        FROM
+            user_alert_interactions
+        WHERE
+            user_id = $1::int
+            AND alert_id = $2::int
+            AND click_count > 0
+    )
 `
 
-func (q *Queries) MarkAlertClicked(ctx context.Context, alertID int32) error {
-	_, err := q.db.Exec(ctx, markAlertClicked, alertID)
+type MarkAlertClickedParams struct {
+	UserID  int32 `db:"user_id" json:"user_id"`
+	AlertID int32 `db:"alert_id" json:"alert_id"`
+}
+
+// Records a click for the user, movement_alerts.clicks counts distinct clickers
+func (q *Queries) MarkAlertClicked(ctx context.Context, arg MarkAlertClickedParams) error {
+	_, err := q.db.Exec(ctx, markAlertClicked, arg.UserID, arg.AlertID)
 	return err
 }
 
 const markAlertViewed = `-- name: MarkAlertViewed :exec
+WITH interaction AS (
+    INSERT INTO
+        user_alert_interactions (user_id, alert_id, view_count, first_viewed_at, last_viewed_at)
+    VALUES
+        (
+            $1::int,
+            $2::int,
+            1,
+            CURRENT_TIMESTAMP,
+            CURRENT_TIMESTAMP
+        ) ON CONFLICT (user_id, alert_id) DO
+    UPDATE
+    SET
+        view_count = user_alert_interactions.view_count + 1,
+        first_viewed_at = COALESCE(user_alert_interactions.first_viewed_at, EXCLUDED.first_viewed_at),
+        last_viewed_at = EXCLUDED.last_viewed_at
+)
 UPDATE
     movement_alerts
 SET
     views = views + 1
 WHERE
-    id = $1
+    id = $2::int
+    AND NOT EXISTS (
+        SELECT
+            1
+        FROM
+            user_alert_interactions
+        WHERE
+            user_id = $1::int
+            AND alert_id = $2::int
+            AND view_count > 0
+    )
 `
 
-// COMMENTED OUT: Requires smart_money_preferences table
-// -- name: GetAlertsByUser :many
-// SELECT
-//
-//	ma.*,
-//	oh.event_id,
-//	oh.outcome,
-//	e.external_id as event_external_id,
-//	e.event_date,
-//	ht.name as home_team_name,
-//	at.name as away_team_name,
-//	mt.name as market_name
-//
-// FROM movement_alerts ma
-// JOIN odds_history oh ON ma.odds_history_id = oh.id
-// JOIN events e ON oh.event_id = e.id
-// LEFT JOIN teams ht ON e.home_team_id = ht.id
-// LEFT JOIN teams at ON e.away_team_id = at.id
-// JOIN market_types mt ON oh.market_type_id = mt.id
-// JOIN smart_money_preferences smp ON smp.user_id = sqlc.arg(user_id)
-// WHERE
-//
-//	ma.is_active = true
-//	AND ma.expires_at > NOW()
-//	AND ma.change_percentage >= smp.min_change_percentage
-//	AND ma.multiplier >= smp.min_multiplier
-//	AND ma.confidence_score >= smp.min_confidence_score
-//	AND (
-//	    (ma.alert_type = 'big_mover' AND smp.big_mover_alerts = true) OR
-//	    (ma.alert_type = 'reverse_line' AND smp.reverse_line_alerts = true) OR
-//	    (ma.alert_type = 'sharp_money' AND smp.sharp_money_alerts = true) OR
-//	    (ma.alert_type = 'value_spot' AND smp.value_spot_alerts = true)
-//	)
-//	-- Filter by preferred sports/leagues if specified
-//	AND (
-//	    smp.preferred_sports = '[]'::jsonb OR
-//	    e.sport_id::text = ANY(SELECT jsonb_array_elements_text(smp.preferred_sports))
-//	)
-//
-// ORDER BY ma.created_at DESC
-// LIMIT sqlc.arg(limit_count);
-func (q *Queries) MarkAlertViewed(ctx context.Context, alertID int32) error {
-	_, err := q.db.Exec(ctx, markAlertViewed, alertID)
+type MarkAlertViewedParams struct {
+	UserID  int32 `db:"user_id" json:"user_id"`
+	AlertID int32 `db:"alert_id" json:"alert_id"`
+}
+
+// Records a view for the user, movement_alerts.views counts distinct viewers
+func (q *Queries) MarkAlertViewed(ctx context.Context, arg MarkAlertViewedParams) error {
+	_, err := q.db.Exec(ctx, markAlertViewed, arg.UserID, arg.AlertID)
 	return err
 }
+
+const upsertUserSmartMoneyPreferences = `-- name: UpsertUserSmartMoneyPreferences :one
+INSERT INTO smart_money_preferences (
+    user_id,
+    min_change_percentage,
+    min_multiplier,
+    min_confidence_score,
+    big_mover_alerts,
+    reverse_line_alerts,
+    sharp_money_alerts,
+    value_spot_alerts,
+    preferred_sports,
+    preferred_leagues,
+    max_daily_alerts,
+    push_notifications,
+    quiet_hours_start,
+    quiet_hours_end
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13,
+    $14
+)
+ON CONFLICT (user_id) DO UPDATE SET
+    min_change_percentage = EXCLUDED.min_change_percentage,
+    min_multiplier = EXCLUDED.min_multiplier,
+    min_confidence_score = EXCLUDED.min_confidence_score,
+    big_mover_alerts = EXCLUDED.big_mover_alerts,
+    reverse_line_alerts = EXCLUDED.reverse_line_alerts,
+    sharp_money_alerts = EXCLUDED.sharp_money_alerts,
+    value_spot_alerts = EXCLUDED.value_spot_alerts,
+    preferred_sports = EXCLUDED.preferred_sports,
+    preferred_leagues = EXCLUDED.preferred_leagues,
+    max_daily_alerts = EXCLUDED.max_daily_alerts,
+    push_notifications = EXCLUDED.push_notifications,
+    quiet_hours_start = EXCLUDED.quiet_hours_start,
+    quiet_hours_end = EXCLUDED.quiet_hours_end,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING id, user_id, min_change_percentage, min_multiplier, min_confidence_score, big_mover_alerts, reverse_line_alerts, sharp_money_alerts, value_spot_alerts, preferred_sports, preferred_leagues, max_daily_alerts, push_notifications, quiet_hours_start, quiet_hours_end, created_at, updated_at
+`
+
+type UpsertUserSmartMoneyPreferencesParams struct {
+	UserID              int32   `db:"user_id" json:"user_id"`
+	MinChangePercentage float32 `db:"min_change_percentage" json:"min_change_percentage"`
+	MinMultiplier       float64 `db:"min_multiplier" json:"min_multiplier"`
+	MinConfidenceScore  float32 `db:"min_confidence_score" json:"min_confidence_score"`
+	BigMoverAlerts      bool    `db:"big_mover_alerts" json:"big_mover_alerts"`
+	ReverseLineAlerts   bool    `db:"reverse_line_alerts" json:"reverse_line_alerts"`
+	SharpMoneyAlerts    bool    `db:"sharp_money_alerts" json:"sharp_money_alerts"`
+	ValueSpotAlerts     bool    `db:"value_spot_alerts" json:"value_spot_alerts"`
+	PreferredSports     []int32 `db:"preferred_sports" json:"preferred_sports"`
+	PreferredLeagues    []int32 `db:"preferred_leagues" json:"preferred_leagues"`
+	MaxDailyAlerts      int32   `db:"max_daily_alerts" json:"max_daily_alerts"`
+	PushNotifications   bool    `db:"push_notifications" json:"push_notifications"`
+	QuietHoursStart     *int32  `db:"quiet_hours_start" json:"quiet_hours_start"`
+	QuietHoursEnd       *int32  `db:"quiet_hours_end" json:"quiet_hours_end"`
+}
+
+func (q *Queries) UpsertUserSmartMoneyPreferences(ctx context.Context, arg UpsertUserSmartMoneyPreferencesParams) (SmartMoneyPreference, error) {
+	row := q.db.QueryRow(ctx, upsertUserSmartMoneyPreferences,
+		arg.UserID,
+		arg.MinChangePercentage,
+		arg.MinMultiplier,
+		arg.MinConfidenceScore,
+		arg.BigMoverAlerts,
+		arg.ReverseLineAlerts,
+		arg.SharpMoneyAlerts,
+		arg.ValueSpotAlerts,
+		arg.PreferredSports,
+		arg.PreferredLeagues,
+		arg.MaxDailyAlerts,
+		arg.PushNotifications,
+		arg.QuietHoursStart,
+		arg.QuietHoursEnd,
+	)
+	var i SmartMoneyPreference
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.MinChangePercentage,
+		&i.MinMultiplier,
+		&i.MinConfidenceScore,
+		&i.BigMoverAlerts,
+		&i.ReverseLineAlerts,
+		&i.SharpMoneyAlerts,
+		&i.ValueSpotAlerts,
+		&i.PreferredSports,
+		&i.PreferredLeagues,
+		&i.MaxDailyAlerts,
+		&i.PushNotifications,
+		&i.QuietHoursStart,
+		&i.QuietHoursEnd,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
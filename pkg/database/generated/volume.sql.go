@@ -11,64 +11,95 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const analyzeVolumeOddsPattern = `-- name: AnalyzeVolumeOddsPattern :one
-WITH patterns AS (
+const analyzeVolumeOddsPattern = `-- name: AnalyzeVolumeOddsPattern :many
+WITH event_movements AS (
     SELECT
-        CASE
-            WHEN e.betting_volume_percentage > 5
-            AND MAX(ABS(co.movement_percentage)) > 30 THEN 'HIGH_VOLUME_HIGH_MOVEMENT'
-            WHEN e.betting_volume_percentage > 5
-            AND MAX(ABS(co.movement_percentage)) < 10 THEN 'HIGH_VOLUME_STABLE'
-            WHEN e.betting_volume_percentage < 1
-            AND MAX(ABS(co.movement_percentage)) > 30 THEN 'LOW_VOLUME_HIGH_MOVEMENT'
-            WHEN e.betting_volume_percentage < 1
-            AND MAX(ABS(co.movement_percentage)) < 10 THEN 'LOW_VOLUME_STABLE'
-            ELSE 'MODERATE'
-        END as pattern,
-        COUNT(*) as event_count,
-        AVG(e.betting_volume_percentage) as avg_volume,
-        AVG(MAX(ABS(co.movement_percentage))) as avg_movement
+        e.id,
+        e.betting_volume_percentage as volume,
+        COALESCE(MAX(ABS(co.movement_percentage)), 0) as max_movement
     FROM
         events e
+        JOIN sports s ON e.sport_id = s.id
         LEFT JOIN current_odds co ON co.event_id = e.id
+        AND co.bookmaker = 'iddaa'
     WHERE
-        e.volume_updated_at > CURRENT_TIMESTAMP - INTERVAL '24 hours'
+        e.volume_updated_at > $1::timestamp
+        AND e.betting_volume_percentage IS NOT NULL
+        AND (
+            $2::text = ''
+            OR s.code = $2::text
+        )
     GROUP BY
         e.id,
         e.betting_volume_percentage
+),
+patterns AS (
+    SELECT
+        CASE
+            WHEN volume > 5
+            AND max_movement > 30 THEN 'HIGH_VOLUME_HIGH_MOVEMENT'
+            WHEN volume > 5
+            AND max_movement < 10 THEN 'HIGH_VOLUME_STABLE'
+            WHEN volume < 1
+            AND max_movement > 30 THEN 'LOW_VOLUME_HIGH_MOVEMENT'
+            WHEN volume < 1
+            AND max_movement < 10 THEN 'LOW_VOLUME_STABLE'
+            ELSE 'MODERATE'
+        END as pattern,
+        volume,
+        max_movement
+    FROM
+        event_movements
 )
 SELECT
-    json_object_agg(
-        pattern,
-        json_build_object(
-            'count',
-            event_count,
-            'avg_volume',
-            ROUND(avg_volume :: NUMERIC, 2),
-            'avg_movement',
-            ROUND(avg_movement :: NUMERIC, 2)
-        )
-    ) as analysis
+    pattern::text as pattern,
+    COUNT(*)::int as event_count,
+    ROUND(AVG(volume)::NUMERIC, 2)::float8 as avg_volume,
+    ROUND(AVG(max_movement)::NUMERIC, 2)::float8 as avg_movement
 FROM
-    (
-        SELECT
-            pattern,
-            SUM(event_count) as event_count,
-            AVG(avg_volume) as avg_volume,
-            AVG(avg_movement) as avg_movement
-        FROM
-            patterns
-        GROUP BY
-            pattern
-    ) summary
+    patterns
+GROUP BY
+    pattern
+ORDER BY
+    event_count DESC
 `
 
+type AnalyzeVolumeOddsPatternParams struct {
+	SinceTime pgtype.Timestamp `db:"since_time" json:"since_time"`
+	SportCode string           `db:"sport_code" json:"sport_code"`
+}
+
+type AnalyzeVolumeOddsPatternRow struct {
+	Pattern     string  `db:"pattern" json:"pattern"`
+	EventCount  int32   `db:"event_count" json:"event_count"`
+	AvgVolume   float64 `db:"avg_volume" json:"avg_volume"`
+	AvgMovement float64 `db:"avg_movement" json:"avg_movement"`
+}
+
 // Analyze correlation between volume and odds movement
-func (q *Queries) AnalyzeVolumeOddsPattern(ctx context.Context) ([]byte, error) {
-	row := q.db.QueryRow(ctx, analyzeVolumeOddsPattern)
-	var analysis []byte
-	err := row.Scan(&analysis)
-	return analysis, err
+func (q *Queries) AnalyzeVolumeOddsPattern(ctx context.Context, arg AnalyzeVolumeOddsPatternParams) ([]AnalyzeVolumeOddsPatternRow, error) {
+	rows, err := q.db.Query(ctx, analyzeVolumeOddsPattern, arg.SinceTime, arg.SportCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AnalyzeVolumeOddsPatternRow{}
+	for rows.Next() {
+		var i AnalyzeVolumeOddsPatternRow
+		if err := rows.Scan(
+			&i.Pattern,
+			&i.EventCount,
+			&i.AvgVolume,
+			&i.AvgMovement,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const bulkInsertVolumeHistory = `-- name: BulkInsertVolumeHistory :execrows
@@ -77,13 +108,15 @@ INSERT INTO
         event_id,
         volume_percentage,
         rank_position,
-        total_events_tracked
+        total_events_tracked,
+        recorded_at
     )
 SELECT
     e.id,
     vd.percentage,
     vd.rank,
-    $1 :: int4
+    $1 :: int4,
+    COALESCE($4::timestamp, CURRENT_TIMESTAMP)
 FROM
     (
         SELECT
@@ -104,14 +137,15 @@ FROM
 `
 
 type BulkInsertVolumeHistoryParams struct {
-	TotalEvents int64     `db:"total_events" json:"total_events"`
-	ExternalIds []string  `db:"external_ids" json:"external_ids"`
-	Percentages []float64 `db:"percentages" json:"percentages"`
+	TotalEvents int64            `db:"total_events" json:"total_events"`
+	ExternalIds []string         `db:"external_ids" json:"external_ids"`
+	Percentages []float64        `db:"percentages" json:"percentages"`
+	RecordedAt  pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
 }
 
 // Bulk insert volume history records
 func (q *Queries) BulkInsertVolumeHistory(ctx context.Context, arg BulkInsertVolumeHistoryParams) (int64, error) {
-	result, err := q.db.Exec(ctx, bulkInsertVolumeHistory, arg.TotalEvents, arg.ExternalIds, arg.Percentages)
+	result, err := q.db.Exec(ctx, bulkInsertVolumeHistory, arg.TotalEvents, arg.ExternalIds, arg.Percentages, arg.RecordedAt)
 	if err != nil {
 		return 0, err
 	}
@@ -140,7 +174,7 @@ UPDATE
 SET
     betting_volume_percentage = rv.percentage,
     volume_rank = rv.rank,
-    volume_updated_at = NOW()
+    volume_updated_at = COALESCE($3::timestamp, NOW())
 FROM
     ranked_volumes rv
 WHERE
@@ -148,19 +182,82 @@ WHERE
 `
 
 type BulkUpdateEventVolumesParams struct {
-	ExternalIds []string  `db:"external_ids" json:"external_ids"`
-	Percentages []float64 `db:"percentages" json:"percentages"`
+	ExternalIds []string         `db:"external_ids" json:"external_ids"`
+	Percentages []float64        `db:"percentages" json:"percentages"`
+	RecordedAt  pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
 }
 
 // Bulk update event volumes with database-calculated ranks
 func (q *Queries) BulkUpdateEventVolumes(ctx context.Context, arg BulkUpdateEventVolumesParams) (int64, error) {
-	result, err := q.db.Exec(ctx, bulkUpdateEventVolumes, arg.ExternalIds, arg.Percentages)
+	result, err := q.db.Exec(ctx, bulkUpdateEventVolumes, arg.ExternalIds, arg.Percentages, arg.RecordedAt)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected(), nil
 }
 
+const countTopVolumeEvents = `-- name: CountTopVolumeEvents :one
+SELECT
+    COUNT(*)::int
+FROM
+    events e
+    JOIN leagues l ON e.league_id = l.id
+    JOIN sports s ON e.sport_id = s.id
+WHERE
+    e.volume_rank <= $1::int
+    AND e.event_date > CURRENT_TIMESTAMP
+    AND e.event_date <= $2::timestamp
+    AND (
+        $3::text = ''
+        OR s.code = $3::text
+    )
+    AND (
+        $4::text = ''
+        OR l.name ILIKE '%' || $4::text || '%'
+    )
+`
+
+type CountTopVolumeEventsParams struct {
+	MaxRank    int32            `db:"max_rank" json:"max_rank"`
+	ToTime     pgtype.Timestamp `db:"to_time" json:"to_time"`
+	SportCode  string           `db:"sport_code" json:"sport_code"`
+	LeagueName string           `db:"league_name" json:"league_name"`
+}
+
+func (q *Queries) CountTopVolumeEvents(ctx context.Context, arg CountTopVolumeEventsParams) (int32, error) {
+	row := q.db.QueryRow(ctx, countTopVolumeEvents,
+		arg.MaxRank,
+		arg.ToTime,
+		arg.SportCode,
+		arg.LeagueName,
+	)
+	var column1 int32
+	err := row.Scan(&column1)
+	return column1, err
+}
+
+const countVolumeHistory = `-- name: CountVolumeHistory :one
+SELECT
+    COUNT(*)::int
+FROM
+    betting_volume_history bvh
+WHERE
+    bvh.event_id = $1::int
+    AND bvh.recorded_at >= $2::timestamp
+`
+
+type CountVolumeHistoryParams struct {
+	EventID   int32            `db:"event_id" json:"event_id"`
+	SinceTime pgtype.Timestamp `db:"since_time" json:"since_time"`
+}
+
+func (q *Queries) CountVolumeHistory(ctx context.Context, arg CountVolumeHistoryParams) (int32, error) {
+	row := q.db.QueryRow(ctx, countVolumeHistory, arg.EventID, arg.SinceTime)
+	var column1 int32
+	err := row.Scan(&column1)
+	return column1, err
+}
+
 const createVolumeHistory = `-- name: CreateVolumeHistory :one
 INSERT INTO
     betting_volume_history (
@@ -241,49 +338,103 @@ func (q *Queries) GetEventIDsByExternalIDs(ctx context.Context, externalIds []st
 }
 
 const getHiddenGems = `-- name: GetHiddenGems :many
+WITH gems AS (
+    SELECT
+        e.id,
+        e.slug,
+        (ht.name || ' vs ' || at.name)::text as match_name,
+        s.code as sport_code,
+        s.name as sport_name,
+        l.name as league_name,
+        e.event_date,
+        e.betting_volume_percentage,
+        e.volume_rank,
+        (
+            SELECT
+                COALESCE(MAX(ABS(co.movement_percentage)), 0)
+            FROM
+                current_odds co
+            WHERE
+                co.event_id = e.id
+                AND co.bookmaker = 'iddaa'
+        )::float8 as max_movement
+    FROM
+        events e
+        JOIN teams ht ON e.home_team_id = ht.id
+        JOIN teams at ON e.away_team_id = at.id
+        JOIN leagues l ON e.league_id = l.id
+        JOIN sports s ON e.sport_id = s.id
+    WHERE
+        e.betting_volume_percentage <= $1::float8
+        AND e.betting_volume_percentage > 0
+        AND e.event_date > CURRENT_TIMESTAMP
+        AND (
+            $2::text = ''
+            OR s.code = $2::text
+        )
+)
 SELECT
-    e.slug,
-    ht.name || ' vs ' || at.name as match_name,
-    e.betting_volume_percentage,
-    COALESCE(MAX(ABS(co.movement_percentage)), 0) as max_movement
+    g.slug,
+    g.match_name,
+    g.sport_code,
+    g.sport_name,
+    g.league_name,
+    g.event_date,
+    g.betting_volume_percentage,
+    g.volume_rank,
+    COALESCE(g.betting_volume_percentage - vh.first_volume, 0)::float8 as volume_change,
+    g.max_movement
 FROM
-    events e
-    JOIN teams ht ON e.home_team_id = ht.id
-    JOIN teams at ON e.away_team_id = at.id
-    LEFT JOIN current_odds co ON co.event_id = e.id
+    gems g
+    LEFT JOIN LATERAL (
+        SELECT
+            (ARRAY_AGG(bvh.volume_percentage ORDER BY bvh.recorded_at))[1] as first_volume
+        FROM
+            betting_volume_history bvh
+        WHERE
+            bvh.event_id = g.id
+            AND bvh.recorded_at >= $3::timestamp
+    ) vh ON TRUE
 WHERE
-    e.betting_volume_percentage <= $1 :: float8
-    AND e.betting_volume_percentage > 0
-    AND e.event_date > CURRENT_TIMESTAMP
-GROUP BY
-    e.id,
-    e.slug,
-    ht.name,
-    at.name,
-    e.betting_volume_percentage
-HAVING
-    COALESCE(MAX(ABS(co.movement_percentage)), 0) >= $2 :: float8
+    g.max_movement >= $4::float8
 ORDER BY
-    MAX(ABS(co.movement_percentage)) DESC
+    g.max_movement DESC,
+    g.id
 LIMIT
-    20
+    $5::int
 `
 
 type GetHiddenGemsParams struct {
-	MaxVolume   float64 `db:"max_volume" json:"max_volume"`
-	MinMovement float64 `db:"min_movement" json:"min_movement"`
+	MaxVolume   float64          `db:"max_volume" json:"max_volume"`
+	SportCode   string           `db:"sport_code" json:"sport_code"`
+	SinceTime   pgtype.Timestamp `db:"since_time" json:"since_time"`
+	MinMovement float64          `db:"min_movement" json:"min_movement"`
+	LimitCount  int32            `db:"limit_count" json:"limit_count"`
 }
 
 type GetHiddenGemsRow struct {
-	Slug                    string      `db:"slug" json:"slug"`
-	MatchName               interface{} `db:"match_name" json:"match_name"`
-	BettingVolumePercentage *float32    `db:"betting_volume_percentage" json:"betting_volume_percentage"`
-	MaxMovement             interface{} `db:"max_movement" json:"max_movement"`
+	Slug                    string           `db:"slug" json:"slug"`
+	MatchName               string           `db:"match_name" json:"match_name"`
+	SportCode               string           `db:"sport_code" json:"sport_code"`
+	SportName               string           `db:"sport_name" json:"sport_name"`
+	LeagueName              string           `db:"league_name" json:"league_name"`
+	EventDate               pgtype.Timestamp `db:"event_date" json:"event_date"`
+	BettingVolumePercentage *float32         `db:"betting_volume_percentage" json:"betting_volume_percentage"`
+	VolumeRank              *int32           `db:"volume_rank" json:"volume_rank"`
+	VolumeChange            float64          `db:"volume_change" json:"volume_change"`
+	MaxMovement             float64          `db:"max_movement" json:"max_movement"`
 }
 
-// Find low-volume events with big movements (potential sharp money)
+// Find low-volume events with big movements (potential sharp money).
+// Volume change comes from betting_volume_history since since_time.
 func (q *Queries) GetHiddenGems(ctx context.Context, arg GetHiddenGemsParams) ([]GetHiddenGemsRow, error) {
-	rows, err := q.db.Query(ctx, getHiddenGems, arg.MaxVolume, arg.MinMovement)
+	rows, err := q.db.Query(ctx, getHiddenGems,
+		arg.MaxVolume,
+		arg.SportCode,
+		arg.SinceTime,
+		arg.MinMovement,
+		arg.LimitCount,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -294,8 +445,125 @@ func (q *Queries) GetHiddenGems(ctx context.Context, arg GetHiddenGemsParams) ([
 		if err := rows.Scan(
 			&i.Slug,
 			&i.MatchName,
+			&i.SportCode,
+			&i.SportName,
+			&i.LeagueName,
+			&i.EventDate,
 			&i.BettingVolumePercentage,
+			&i.VolumeRank,
+			&i.VolumeChange,
+			&i.MaxMovement,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHiddenGemsBySport = `-- name: GetHiddenGemsBySport :many
+WITH gems AS (
+    SELECT
+        e.id,
+        s.code as sport_code,
+        s.name as sport_name,
+        e.betting_volume_percentage,
+        (
+            SELECT
+                COALESCE(MAX(ABS(co.movement_percentage)), 0)
+            FROM
+                current_odds co
+            WHERE
+                co.event_id = e.id
+                AND co.bookmaker = 'iddaa'
+        )::float8 as max_movement
+    FROM
+        events e
+        JOIN teams ht ON e.home_team_id = ht.id
+        JOIN teams at ON e.away_team_id = at.id
+        JOIN leagues l ON e.league_id = l.id
+        JOIN sports s ON e.sport_id = s.id
+    WHERE
+        e.betting_volume_percentage <= $1::float8
+        AND e.betting_volume_percentage > 0
+        AND e.event_date > CURRENT_TIMESTAMP
+        AND (
+            $2::text = ''
+            OR s.code = $2::text
+        )
+)
+SELECT
+    g.sport_code,
+    g.sport_name,
+    COUNT(*)::int as events,
+    AVG(COALESCE(g.betting_volume_percentage, 0))::float8 as avg_volume,
+    AVG(g.max_movement)::float8 as avg_movement,
+    MAX(g.max_movement)::float8 as max_movement,
+    SUM(COALESCE(g.betting_volume_percentage - vh.first_volume, 0))::float8 as net_volume_change
+FROM
+    gems g
+    LEFT JOIN LATERAL (
+        SELECT
+            (ARRAY_AGG(bvh.volume_percentage ORDER BY bvh.recorded_at))[1] as first_volume
+        FROM
+            betting_volume_history bvh
+        WHERE
+            bvh.event_id = g.id
+            AND bvh.recorded_at >= $3::timestamp
+    ) vh ON TRUE
+WHERE
+    g.max_movement >= $4::float8
+GROUP BY
+    g.sport_code,
+    g.sport_name
+ORDER BY
+    events DESC,
+    g.sport_code
+`
+
+type GetHiddenGemsBySportParams struct {
+	MaxVolume   float64          `db:"max_volume" json:"max_volume"`
+	SportCode   string           `db:"sport_code" json:"sport_code"`
+	SinceTime   pgtype.Timestamp `db:"since_time" json:"since_time"`
+	MinMovement float64          `db:"min_movement" json:"min_movement"`
+}
+
+type GetHiddenGemsBySportRow struct {
+	SportCode       string  `db:"sport_code" json:"sport_code"`
+	SportName       string  `db:"sport_name" json:"sport_name"`
+	Events          int32   `db:"events" json:"events"`
+	AvgVolume       float64 `db:"avg_volume" json:"avg_volume"`
+	AvgMovement     float64 `db:"avg_movement" json:"avg_movement"`
+	MaxMovement     float64 `db:"max_movement" json:"max_movement"`
+	NetVolumeChange float64 `db:"net_volume_change" json:"net_volume_change"`
+}
+
+// Rolls the events GetHiddenGems finds up per sport, over all of them rather than one page
+func (q *Queries) GetHiddenGemsBySport(ctx context.Context, arg GetHiddenGemsBySportParams) ([]GetHiddenGemsBySportRow, error) {
+	rows, err := q.db.Query(ctx, getHiddenGemsBySport,
+		arg.MaxVolume,
+		arg.SportCode,
+		arg.SinceTime,
+		arg.MinMovement,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetHiddenGemsBySportRow{}
+	for rows.Next() {
+		var i GetHiddenGemsBySportRow
+		if err := rows.Scan(
+			&i.SportCode,
+			&i.SportName,
+			&i.Events,
+			&i.AvgVolume,
+			&i.AvgMovement,
 			&i.MaxMovement,
+			&i.NetVolumeChange,
 		); err != nil {
 			return nil, err
 		}
@@ -308,68 +576,122 @@ func (q *Queries) GetHiddenGems(ctx context.Context, arg GetHiddenGemsParams) ([
 }
 
 const getHotMovers = `-- name: GetHotMovers :many
+WITH movers AS (
+    SELECT
+        e.id,
+        e.slug,
+        (ht.name || ' vs ' || at.name)::text as match_name,
+        s.code as sport_code,
+        s.name as sport_name,
+        l.name as league_name,
+        e.event_date,
+        e.betting_volume_percentage,
+        e.volume_rank,
+        (
+            SELECT
+                COALESCE(MAX(ABS(co.movement_percentage)), 0)
+            FROM
+                current_odds co
+            WHERE
+                co.event_id = e.id
+                AND co.bookmaker = 'iddaa'
+        )::float8 as max_movement
+    FROM
+        events e
+        JOIN teams ht ON e.home_team_id = ht.id
+        JOIN teams at ON e.away_team_id = at.id
+        JOIN leagues l ON e.league_id = l.id
+        JOIN sports s ON e.sport_id = s.id
+    WHERE
+        e.betting_volume_percentage >= $1::float8
+        AND e.event_date > CURRENT_TIMESTAMP
+        AND (
+            $2::text = ''
+            OR s.code = $2::text
+        )
+)
 SELECT
-    e.slug,
-    ht.name || ' vs ' || at.name as match_name,
-    e.betting_volume_percentage,
-    e.volume_rank,
-    COALESCE(MAX(ABS(co.movement_percentage)), 0) as max_movement,
+    m.slug,
+    m.match_name,
+    m.sport_code,
+    m.sport_name,
+    m.league_name,
+    m.event_date,
+    m.betting_volume_percentage,
+    m.volume_rank,
+    COALESCE(m.betting_volume_percentage - vh.first_volume, 0)::float8 as volume_change,
+    vh.best_rank::int as best_rank,
+    m.max_movement,
     CASE
-        WHEN e.betting_volume_percentage > 5 THEN 'HOT'
-        WHEN e.betting_volume_percentage > 2 THEN 'POPULAR'
-        WHEN e.betting_volume_percentage > 1 THEN 'MODERATE'
+        WHEN m.betting_volume_percentage > 5 THEN 'HOT'
+        WHEN m.betting_volume_percentage > 2 THEN 'POPULAR'
+        WHEN m.betting_volume_percentage > 1 THEN 'MODERATE'
         ELSE 'COLD'
-    END as popularity_level,
+    END::text as popularity_level,
     CASE
-        WHEN e.betting_volume_percentage > 5
-        AND MAX(ABS(co.movement_percentage)) > 50 THEN 'HOT_MOVER'
-        WHEN e.betting_volume_percentage < 1
-        AND MAX(ABS(co.movement_percentage)) > 50 THEN 'HIDDEN_GEM'
-        WHEN e.betting_volume_percentage > 5
-        AND MAX(ABS(co.movement_percentage)) < 10 THEN 'STABLE_FAVORITE'
+        WHEN m.betting_volume_percentage > 5
+        AND m.max_movement > 50 THEN 'HOT_MOVER'
+        WHEN m.betting_volume_percentage < 1
+        AND m.max_movement > 50 THEN 'HIDDEN_GEM'
+        WHEN m.betting_volume_percentage > 5
+        AND m.max_movement < 10 THEN 'STABLE_FAVORITE'
         ELSE 'NORMAL'
-    END as event_type
+    END::text as event_type
 FROM
-    events e
-    JOIN teams ht ON e.home_team_id = ht.id
-    JOIN teams at ON e.away_team_id = at.id
-    LEFT JOIN current_odds co ON co.event_id = e.id
+    movers m
+    LEFT JOIN LATERAL (
+        SELECT
+            (ARRAY_AGG(bvh.volume_percentage ORDER BY bvh.recorded_at))[1] as first_volume,
+            MIN(bvh.rank_position) as best_rank
+        FROM
+            betting_volume_history bvh
+        WHERE
+            bvh.event_id = m.id
+            AND bvh.recorded_at >= $3::timestamp
+    ) vh ON TRUE
 WHERE
-    e.betting_volume_percentage >= $1 :: float8
-    AND e.event_date > CURRENT_TIMESTAMP
-GROUP BY
-    e.id,
-    e.slug,
-    ht.name,
-    at.name,
-    e.betting_volume_percentage,
-    e.volume_rank
-HAVING
-    COALESCE(MAX(ABS(co.movement_percentage)), 0) >= $2 :: float8
+    m.max_movement >= $4::float8
 ORDER BY
-    e.betting_volume_percentage DESC
+    m.betting_volume_percentage DESC,
+    m.id
 LIMIT
-    50
+    $5::int
 `
 
 type GetHotMoversParams struct {
-	MinVolume   float64 `db:"min_volume" json:"min_volume"`
-	MinMovement float64 `db:"min_movement" json:"min_movement"`
+	MinVolume   float64          `db:"min_volume" json:"min_volume"`
+	SportCode   string           `db:"sport_code" json:"sport_code"`
+	SinceTime   pgtype.Timestamp `db:"since_time" json:"since_time"`
+	MinMovement float64          `db:"min_movement" json:"min_movement"`
+	LimitCount  int32            `db:"limit_count" json:"limit_count"`
 }
 
 type GetHotMoversRow struct {
-	Slug                    string      `db:"slug" json:"slug"`
-	MatchName               interface{} `db:"match_name" json:"match_name"`
-	BettingVolumePercentage *float32    `db:"betting_volume_percentage" json:"betting_volume_percentage"`
-	VolumeRank              *int32      `db:"volume_rank" json:"volume_rank"`
-	MaxMovement             interface{} `db:"max_movement" json:"max_movement"`
-	PopularityLevel         string      `db:"popularity_level" json:"popularity_level"`
-	EventType               string      `db:"event_type" json:"event_type"`
+	Slug                    string           `db:"slug" json:"slug"`
+	MatchName               string           `db:"match_name" json:"match_name"`
+	SportCode               string           `db:"sport_code" json:"sport_code"`
+	SportName               string           `db:"sport_name" json:"sport_name"`
+	LeagueName              string           `db:"league_name" json:"league_name"`
+	EventDate               pgtype.Timestamp `db:"event_date" json:"event_date"`
+	BettingVolumePercentage *float32         `db:"betting_volume_percentage" json:"betting_volume_percentage"`
+	VolumeRank              *int32           `db:"volume_rank" json:"volume_rank"`
+	VolumeChange            float64          `db:"volume_change" json:"volume_change"`
+	BestRank                *int32           `db:"best_rank" json:"best_rank"`
+	MaxMovement             float64          `db:"max_movement" json:"max_movement"`
+	PopularityLevel         string           `db:"popularity_level" json:"popularity_level"`
+	EventType               string           `db:"event_type" json:"event_type"`
 }
 
-// Find events with high betting volume AND significant odds movement
+// Find events with high betting volume AND significant odds movement.
+// Volume change and best rank come from betting_volume_history since since_time.
 func (q *Queries) GetHotMovers(ctx context.Context, arg GetHotMoversParams) ([]GetHotMoversRow, error) {
-	rows, err := q.db.Query(ctx, getHotMovers, arg.MinVolume, arg.MinMovement)
+	rows, err := q.db.Query(ctx, getHotMovers,
+		arg.MinVolume,
+		arg.SportCode,
+		arg.SinceTime,
+		arg.MinMovement,
+		arg.LimitCount,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -380,8 +702,14 @@ func (q *Queries) GetHotMovers(ctx context.Context, arg GetHotMoversParams) ([]G
 		if err := rows.Scan(
 			&i.Slug,
 			&i.MatchName,
+			&i.SportCode,
+			&i.SportName,
+			&i.LeagueName,
+			&i.EventDate,
 			&i.BettingVolumePercentage,
 			&i.VolumeRank,
+			&i.VolumeChange,
+			&i.BestRank,
 			&i.MaxMovement,
 			&i.PopularityLevel,
 			&i.EventType,
@@ -396,49 +724,199 @@ func (q *Queries) GetHotMovers(ctx context.Context, arg GetHotMoversParams) ([]G
 	return items, nil
 }
 
+const getHotMoversBySport = `-- name: GetHotMoversBySport :many
+WITH movers AS (
+    SELECT
+        e.id,
+        s.code as sport_code,
+        s.name as sport_name,
+        e.betting_volume_percentage,
+        (
+            SELECT
+                COALESCE(MAX(ABS(co.movement_percentage)), 0)
+            FROM
+                current_odds co
+            WHERE
+                co.event_id = e.id
+                AND co.bookmaker = 'iddaa'
+        )::float8 as max_movement
+    FROM
+        events e
+        JOIN teams ht ON e.home_team_id = ht.id
+        JOIN teams at ON e.away_team_id = at.id
+        JOIN leagues l ON e.league_id = l.id
+        JOIN sports s ON e.sport_id = s.id
+    WHERE
+        e.betting_volume_percentage >= $1::float8
+        AND e.event_date > CURRENT_TIMESTAMP
+        AND (
+            $2::text = ''
+            OR s.code = $2::text
+        )
+)
+SELECT
+    m.sport_code,
+    m.sport_name,
+    COUNT(*)::int as events,
+    AVG(COALESCE(m.betting_volume_percentage, 0))::float8 as avg_volume,
+    AVG(m.max_movement)::float8 as avg_movement,
+    MAX(m.max_movement)::float8 as max_movement,
+    SUM(COALESCE(m.betting_volume_percentage - vh.first_volume, 0))::float8 as net_volume_change
+FROM
+    movers m
+    LEFT JOIN LATERAL (
+        SELECT
+            (ARRAY_AGG(bvh.volume_percentage ORDER BY bvh.recorded_at))[1] as first_volume
+        FROM
+            betting_volume_history bvh
+        WHERE
+            bvh.event_id = m.id
+            AND bvh.recorded_at >= $3::timestamp
+    ) vh ON TRUE
+WHERE
+    m.max_movement >= $4::float8
+GROUP BY
+    m.sport_code,
+    m.sport_name
+ORDER BY
+    events DESC,
+    m.sport_code
+`
+
+type GetHotMoversBySportParams struct {
+	MinVolume   float64          `db:"min_volume" json:"min_volume"`
+	SportCode   string           `db:"sport_code" json:"sport_code"`
+	SinceTime   pgtype.Timestamp `db:"since_time" json:"since_time"`
+	MinMovement float64          `db:"min_movement" json:"min_movement"`
+}
+
+type GetHotMoversBySportRow struct {
+	SportCode       string  `db:"sport_code" json:"sport_code"`
+	SportName       string  `db:"sport_name" json:"sport_name"`
+	Events          int32   `db:"events" json:"events"`
+	AvgVolume       float64 `db:"avg_volume" json:"avg_volume"`
+	AvgMovement     float64 `db:"avg_movement" json:"avg_movement"`
+	MaxMovement     float64 `db:"max_movement" json:"max_movement"`
+	NetVolumeChange float64 `db:"net_volume_change" json:"net_volume_change"`
+}
+
+// Rolls the events GetHotMovers finds up per sport, over all of them rather than one page
+func (q *Queries) GetHotMoversBySport(ctx context.Context, arg GetHotMoversBySportParams) ([]GetHotMoversBySportRow, error) {
+	rows, err := q.db.Query(ctx, getHotMoversBySport,
+		arg.MinVolume,
+		arg.SportCode,
+		arg.SinceTime,
+		arg.MinMovement,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetHotMoversBySportRow{}
+	for rows.Next() {
+		var i GetHotMoversBySportRow
+		if err := rows.Scan(
+			&i.SportCode,
+			&i.SportName,
+			&i.Events,
+			&i.AvgVolume,
+			&i.AvgMovement,
+			&i.MaxMovement,
+			&i.NetVolumeChange,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTopVolumeEvents = `-- name: GetTopVolumeEvents :many
 SELECT
     e.slug,
-    ht.name || ' vs ' || at.name as match_name,
+    (ht.name || ' vs ' || at.name)::text as match_name,
+    s.code as sport_code,
+    l.name as league_name,
     e.event_date,
     e.betting_volume_percentage,
     e.volume_rank,
-    COUNT(DISTINCT oh.id) as total_odds_changes,
-    COALESCE(MAX(ABS(co.movement_percentage)), 0) as max_movement
+    (
+        SELECT
+            COUNT(*)
+        FROM
+            odds_history oh
+        WHERE
+            oh.event_id = e.id
+            AND oh.bookmaker = 'iddaa'
+    )::int as total_odds_changes,
+    (
+        SELECT
+            COALESCE(MAX(ABS(co.movement_percentage)), 0)
+        FROM
+            current_odds co
+        WHERE
+            co.event_id = e.id
+            AND co.bookmaker = 'iddaa'
+    )::float8 as max_movement
 FROM
     events e
     JOIN teams ht ON e.home_team_id = ht.id
     JOIN teams at ON e.away_team_id = at.id
-    LEFT JOIN current_odds co ON co.event_id = e.id
-    LEFT JOIN odds_history oh ON oh.event_id = e.id
+    JOIN leagues l ON e.league_id = l.id
+    JOIN sports s ON e.sport_id = s.id
 WHERE
-    e.volume_rank <= 20
+    e.volume_rank <= $1::int
     AND e.event_date > CURRENT_TIMESTAMP
-GROUP BY
-    e.id,
-    e.slug,
-    ht.name,
-    at.name,
-    e.event_date,
-    e.betting_volume_percentage,
-    e.volume_rank
+    AND e.event_date <= $2::timestamp
+    AND (
+        $3::text = ''
+        OR s.code = $3::text
+    )
+    AND (
+        $4::text = ''
+        OR l.name ILIKE '%' || $4::text || '%'
+    )
 ORDER BY
-    e.volume_rank
+    e.volume_rank,
+    e.id
+LIMIT
+    $5::int OFFSET $6::int
 `
 
+type GetTopVolumeEventsParams struct {
+	MaxRank     int32            `db:"max_rank" json:"max_rank"`
+	ToTime      pgtype.Timestamp `db:"to_time" json:"to_time"`
+	SportCode   string           `db:"sport_code" json:"sport_code"`
+	LeagueName  string           `db:"league_name" json:"league_name"`
+	LimitCount  int32            `db:"limit_count" json:"limit_count"`
+	OffsetCount int32            `db:"offset_count" json:"offset_count"`
+}
+
 type GetTopVolumeEventsRow struct {
 	Slug                    string           `db:"slug" json:"slug"`
-	MatchName               interface{}      `db:"match_name" json:"match_name"`
+	MatchName               string           `db:"match_name" json:"match_name"`
+	SportCode               string           `db:"sport_code" json:"sport_code"`
+	LeagueName              string           `db:"league_name" json:"league_name"`
 	EventDate               pgtype.Timestamp `db:"event_date" json:"event_date"`
 	BettingVolumePercentage *float32         `db:"betting_volume_percentage" json:"betting_volume_percentage"`
 	VolumeRank              *int32           `db:"volume_rank" json:"volume_rank"`
-	TotalOddsChanges        int64            `db:"total_odds_changes" json:"total_odds_changes"`
-	MaxMovement             interface{}      `db:"max_movement" json:"max_movement"`
+	TotalOddsChanges        int32            `db:"total_odds_changes" json:"total_odds_changes"`
+	MaxMovement             float64          `db:"max_movement" json:"max_movement"`
 }
 
 // Get current top events by betting volume
-func (q *Queries) GetTopVolumeEvents(ctx context.Context) ([]GetTopVolumeEventsRow, error) {
-	rows, err := q.db.Query(ctx, getTopVolumeEvents)
+func (q *Queries) GetTopVolumeEvents(ctx context.Context, arg GetTopVolumeEventsParams) ([]GetTopVolumeEventsRow, error) {
+	rows, err := q.db.Query(ctx, getTopVolumeEvents,
+		arg.MaxRank,
+		arg.ToTime,
+		arg.SportCode,
+		arg.LeagueName,
+		arg.LimitCount,
+		arg.OffsetCount,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -449,6 +927,8 @@ func (q *Queries) GetTopVolumeEvents(ctx context.Context) ([]GetTopVolumeEventsR
 		if err := rows.Scan(
 			&i.Slug,
 			&i.MatchName,
+			&i.SportCode,
+			&i.LeagueName,
 			&i.EventDate,
 			&i.BettingVolumePercentage,
 			&i.VolumeRank,
@@ -467,25 +947,46 @@ func (q *Queries) GetTopVolumeEvents(ctx context.Context) ([]GetTopVolumeEventsR
 
 const getVolumeHistory = `-- name: GetVolumeHistory :many
 SELECT
-    bvh.id, bvh.event_id, bvh.volume_percentage, bvh.rank_position, bvh.total_events_tracked, bvh.recorded_at,
-    LAG(bvh.volume_percentage) OVER (
-        ORDER BY
-            bvh.recorded_at
-    ) as previous_volume,
-    bvh.volume_percentage - LAG(bvh.volume_percentage) OVER (
-        ORDER BY
-            bvh.recorded_at
-    ) as volume_change
+    h.id,
+    h.event_id,
+    h.volume_percentage,
+    h.rank_position,
+    h.total_events_tracked,
+    h.recorded_at,
+    h.previous_volume,
+    h.volume_change
 FROM
-    betting_volume_history bvh
+    (
+        SELECT
+            bvh.*,
+            LAG(bvh.volume_percentage) OVER (
+                ORDER BY
+                    bvh.recorded_at
+            ) as previous_volume,
+            bvh.volume_percentage - LAG(bvh.volume_percentage) OVER (
+                ORDER BY
+                    bvh.recorded_at
+            ) as volume_change
+        FROM
+            betting_volume_history bvh
+        WHERE
+            bvh.event_id = $1::int
+    ) h
 WHERE
-    bvh.event_id = $1
+    h.recorded_at >= $2::timestamp
 ORDER BY
-    bvh.recorded_at DESC
+    h.recorded_at DESC
 LIMIT
-    100
+    $3::int OFFSET $4::int
 `
 
+type GetVolumeHistoryParams struct {
+	EventID     int32            `db:"event_id" json:"event_id"`
+	SinceTime   pgtype.Timestamp `db:"since_time" json:"since_time"`
+	LimitCount  int32            `db:"limit_count" json:"limit_count"`
+	OffsetCount int32            `db:"offset_count" json:"offset_count"`
+}
+
 type GetVolumeHistoryRow struct {
 	ID                 int32            `db:"id" json:"id"`
 	EventID            *int32           `db:"event_id" json:"event_id"`
@@ -493,13 +994,18 @@ type GetVolumeHistoryRow struct {
 	RankPosition       *int32           `db:"rank_position" json:"rank_position"`
 	TotalEventsTracked *int32           `db:"total_events_tracked" json:"total_events_tracked"`
 	RecordedAt         pgtype.Timestamp `db:"recorded_at" json:"recorded_at"`
-	PreviousVolume     interface{}      `db:"previous_volume" json:"previous_volume"`
-	VolumeChange       int32            `db:"volume_change" json:"volume_change"`
+	PreviousVolume     *float32         `db:"previous_volume" json:"previous_volume"`
+	VolumeChange       *float32         `db:"volume_change" json:"volume_change"`
 }
 
-// Get volume history for a specific event
-func (q *Queries) GetVolumeHistory(ctx context.Context, eventID *int32) ([]GetVolumeHistoryRow, error) {
-	rows, err := q.db.Query(ctx, getVolumeHistory, eventID)
+// Get volume history for a specific event, newest first
+func (q *Queries) GetVolumeHistory(ctx context.Context, arg GetVolumeHistoryParams) ([]GetVolumeHistoryRow, error) {
+	rows, err := q.db.Query(ctx, getVolumeHistory,
+		arg.EventID,
+		arg.SinceTime,
+		arg.LimitCount,
+		arg.OffsetCount,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,177 @@
+-- Alert performance queries
+-- name: GetGradableMovementAlerts :many
+-- Alerts of outcomes settled or closed in the last window_days that are not graded yet or whose
+-- settlement or closing line changed since. Alerts are read from the last lookback_days only,
+-- which bounds the odds_history partitions probed.
+WITH recent_events AS (
+    SELECT
+        event_id
+    FROM
+        outcome_settlements
+    WHERE
+        settled_at >= CURRENT_TIMESTAMP - make_interval(days => sqlc.arg(window_days)::int)
+    UNION
+    SELECT
+        event_id
+    FROM
+        closing_odds
+    WHERE
+        frozen_at >= CURRENT_TIMESTAMP - make_interval(days => sqlc.arg(window_days)::int)
+)
+SELECT
+    ma.id as alert_id,
+    os.event_id,
+    os.id as settlement_id,
+    os.result,
+    oh.odds_value as alert_odds,
+    co.closing_value as closing_odds
+FROM
+    movement_alerts ma
+    JOIN odds_history oh ON oh.id = ma.odds_history_id
+    AND oh.recorded_at >= CURRENT_TIMESTAMP - make_interval(days => sqlc.arg(lookback_days)::int)
+    JOIN recent_events re ON re.event_id = oh.event_id
+    JOIN outcome_settlements os ON os.event_id = oh.event_id
+    AND os.market_type_id = oh.market_type_id
+    AND os.outcome = oh.outcome
+    LEFT JOIN closing_odds co ON co.event_id = oh.event_id
+    AND co.market_type_id = oh.market_type_id
+    AND co.outcome = oh.outcome
+    LEFT JOIN alert_results ar ON ar.alert_id = ma.id
+WHERE
+    ma.created_at >= CURRENT_TIMESTAMP - make_interval(days => sqlc.arg(lookback_days)::int)
+    AND (
+        ar.alert_id IS NULL
+        OR os.settled_at > ar.graded_at
+        OR (
+            ar.closing_odds IS NULL
+            AND co.closing_value IS NOT NULL
+        )
+    );
+
+-- name: UpsertAlertResults :execrows
+-- Stores graded alerts, re-grading replaces the previous result
+INSERT INTO
+    alert_results (
+        alert_id,
+        event_id,
+        settlement_id,
+        result,
+        alert_odds,
+        closing_odds,
+        profit_at_alert,
+        profit_at_close
+    )
+SELECT
+    unnest(sqlc.arg(alert_ids)::int[]),
+    unnest(sqlc.arg(event_ids)::int[]),
+    unnest(sqlc.arg(settlement_ids)::int[]),
+    unnest(sqlc.arg(results)::text[]),
+    unnest(sqlc.arg(alert_odds)::float8[]),
+    unnest(sqlc.arg(closing_odds)::float8[]),
+    unnest(sqlc.arg(profits_at_alert)::float8[]),
+    unnest(sqlc.arg(profits_at_close)::float8[]) ON CONFLICT (alert_id) DO
+UPDATE
+SET
+    settlement_id = EXCLUDED.settlement_id,
+    result = EXCLUDED.result,
+    closing_odds = EXCLUDED.closing_odds,
+    profit_at_alert = EXCLUDED.profit_at_alert,
+    profit_at_close = EXCLUDED.profit_at_close,
+    graded_at = CURRENT_TIMESTAMP;
+
+-- name: GetAlertPerformance :many
+-- Rolls graded alerts up by every dimension at once, "overall" is the grand total
+WITH graded AS (
+    SELECT
+        ma.alert_type,
+        ma.severity,
+        COALESCE(s.code, 'unknown') as sport,
+        COALESCE(l.name, 'unknown') as league,
+        CASE
+            WHEN ma.minutes_to_kickoff IS NULL THEN 'unknown'
+            WHEN ma.minutes_to_kickoff < 0 THEN 'in_play'
+            WHEN ma.minutes_to_kickoff < 60 THEN '0-1h'
+            WHEN ma.minutes_to_kickoff < 180 THEN '1-3h'
+            WHEN ma.minutes_to_kickoff < 720 THEN '3-12h'
+            WHEN ma.minutes_to_kickoff < 1440 THEN '12-24h'
+            ELSE '24h+'
+        END as minutes_bucket,
+        (
+            FLOOR(LEAST(ma.confidence_score, 0.99) * 10) / 10
+        )::numeric(2, 1)::text as confidence_bucket,
+        ar.result,
+        ar.alert_odds,
+        ar.closing_odds,
+        ar.profit_at_alert,
+        ar.profit_at_close
+    FROM
+        alert_results ar
+        JOIN movement_alerts ma ON ar.alert_id = ma.id
+        JOIN events e ON ar.event_id = e.id
+        LEFT JOIN sports s ON e.sport_id = s.id
+        LEFT JOIN leagues l ON e.league_id = l.id
+    WHERE
+        ma.created_at >= sqlc.arg(since_time)::timestamp
+        AND (
+            sqlc.arg(sport_code)::text = ''
+            OR s.code = sqlc.arg(sport_code)::text
+        )
+        AND (
+            sqlc.arg(alert_type)::text = ''
+            OR ma.alert_type = sqlc.arg(alert_type)::text
+        )
+)
+SELECT
+    (
+        CASE
+            WHEN GROUPING(alert_type) = 0 THEN 'alert_type'
+            WHEN GROUPING(severity) = 0 THEN 'severity'
+            WHEN GROUPING(sport) = 0 THEN 'sport'
+            WHEN GROUPING(league) = 0 THEN 'league'
+            WHEN GROUPING(minutes_bucket) = 0 THEN 'minutes_to_kickoff'
+            WHEN GROUPING(confidence_bucket) = 0 THEN 'confidence'
+            ELSE 'overall'
+        END
+    )::text as dimension,
+    COALESCE(
+        alert_type,
+        severity,
+        sport,
+        league,
+        minutes_bucket,
+        confidence_bucket,
+        'all'
+    )::text as bucket,
+    COUNT(*)::int as alerts,
+    COUNT(*) FILTER (
+        WHERE
+            result = 'won'
+    )::int as won,
+    COUNT(*) FILTER (
+        WHERE
+            result = 'lost'
+    )::int as lost,
+    COUNT(*) FILTER (
+        WHERE
+            result IN ('void', 'push')
+    )::int as refunded,
+    COALESCE(SUM(profit_at_alert), 0)::float8 as profit_at_alert,
+    COUNT(profit_at_close)::int as with_closing_odds,
+    COALESCE(SUM(profit_at_close), 0)::float8 as profit_at_close,
+    COALESCE(AVG(alert_odds), 0)::float8 as avg_alert_odds,
+    COALESCE(AVG(closing_odds), 0)::float8 as avg_closing_odds
+FROM
+    graded
+GROUP BY
+    GROUPING SETS (
+        (),
+        (alert_type),
+        (severity),
+        (sport),
+        (league),
+        (minutes_bucket),
+        (confidence_bucket)
+    )
+ORDER BY
+    dimension,
+    alerts DESC;
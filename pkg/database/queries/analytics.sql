@@ -0,0 +1,260 @@
+-- name: ListContrarianBets :many
+-- Public-heavy outcomes from the contrarian_bets view, strongest signals first
+SELECT
+    cb.event_id,
+    cb.event_slug,
+    cb.sport_name,
+    cb.sport_slug,
+    cb.league_name,
+    cb.country,
+    cb.home_team,
+    cb.away_team,
+    cb.event_date,
+    cb.market_name,
+    cb.market_slug,
+    cb.public_choice,
+    cb.public_percentage,
+    cb.current_odds,
+    cb.opening_odds,
+    cb.odds_movement,
+    cb.overbet_percentage,
+    cb.signal_strength,
+    cb.contrarian_play::text as contrarian_play,
+    cb.last_refreshed::timestamp as last_refreshed
+FROM
+    contrarian_bets cb
+WHERE
+    cb.event_date >= sqlc.arg(from_time)::timestamp
+    AND cb.event_date <= sqlc.arg(to_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR cb.sport_slug IN (
+            SELECT
+                slug
+            FROM
+                sports
+            WHERE
+                code = sqlc.arg(sport_code)::text
+                OR slug = sqlc.arg(sport_code)::text
+        )
+    )
+    AND (
+        sqlc.arg(league_name)::text = ''
+        OR cb.league_name ILIKE '%' || sqlc.arg(league_name)::text || '%'
+    )
+    AND (
+        CASE
+            cb.signal_strength
+            WHEN 'EXTREME_CONTRARIAN' THEN 4
+            WHEN 'STRONG_CONTRARIAN' THEN 3
+            WHEN 'MODERATE_CONTRARIAN' THEN 2
+            ELSE 1
+        END
+    ) >= sqlc.arg(min_strength)::int
+ORDER BY
+    cb.public_percentage DESC,
+    cb.overbet_percentage DESC,
+    cb.event_id,
+    cb.market_slug,
+    cb.public_choice
+LIMIT
+    sqlc.arg(limit_count)::int OFFSET sqlc.arg(offset_count)::int;
+
+-- name: CountContrarianBets :one
+SELECT
+    COUNT(*)::int
+FROM
+    contrarian_bets cb
+WHERE
+    cb.event_date >= sqlc.arg(from_time)::timestamp
+    AND cb.event_date <= sqlc.arg(to_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR cb.sport_slug IN (
+            SELECT
+                slug
+            FROM
+                sports
+            WHERE
+                code = sqlc.arg(sport_code)::text
+                OR slug = sqlc.arg(sport_code)::text
+        )
+    )
+    AND (
+        sqlc.arg(league_name)::text = ''
+        OR cb.league_name ILIKE '%' || sqlc.arg(league_name)::text || '%'
+    )
+    AND (
+        CASE
+            cb.signal_strength
+            WHEN 'EXTREME_CONTRARIAN' THEN 4
+            WHEN 'STRONG_CONTRARIAN' THEN 3
+            WHEN 'MODERATE_CONTRARIAN' THEN 2
+            ELSE 1
+        END
+    ) >= sqlc.arg(min_strength)::int;
+
+-- name: ListLiveOpportunities :many
+-- In-play movements from the live_opportunities view, biggest moves first
+SELECT
+    lo.event_id,
+    lo.event_slug,
+    lo.sport_name,
+    lo.sport_slug,
+    lo.league_name,
+    lo.home_team,
+    lo.away_team,
+    lo.home_score,
+    lo.away_score,
+    lo.minute_of_match,
+    lo.half,
+    lo.market_name,
+    lo.market_slug,
+    lo.outcome,
+    lo.current_odds,
+    lo.pre_match_odds,
+    lo.total_movement,
+    lo.current_backing,
+    lo.betting_volume_percentage,
+    lo.opportunity_type,
+    lo.last_updated
+FROM
+    live_opportunities lo
+WHERE
+    lo.last_updated >= sqlc.arg(since_time)::timestamp
+    AND ABS(COALESCE(lo.total_movement, 0)) >= sqlc.arg(min_movement)::float8
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR lo.sport_slug IN (
+            SELECT
+                slug
+            FROM
+                sports
+            WHERE
+                code = sqlc.arg(sport_code)::text
+                OR slug = sqlc.arg(sport_code)::text
+        )
+    )
+    AND (
+        sqlc.arg(league_name)::text = ''
+        OR lo.league_name ILIKE '%' || sqlc.arg(league_name)::text || '%'
+    )
+ORDER BY
+    ABS(COALESCE(lo.total_movement, 0)) DESC,
+    lo.event_id,
+    lo.market_slug,
+    lo.outcome
+LIMIT
+    sqlc.arg(limit_count)::int OFFSET sqlc.arg(offset_count)::int;
+
+-- name: CountLiveOpportunities :one
+SELECT
+    COUNT(*)::int
+FROM
+    live_opportunities lo
+WHERE
+    lo.last_updated >= sqlc.arg(since_time)::timestamp
+    AND ABS(COALESCE(lo.total_movement, 0)) >= sqlc.arg(min_movement)::float8
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR lo.sport_slug IN (
+            SELECT
+                slug
+            FROM
+                sports
+            WHERE
+                code = sqlc.arg(sport_code)::text
+                OR slug = sqlc.arg(sport_code)::text
+        )
+    )
+    AND (
+        sqlc.arg(league_name)::text = ''
+        OR lo.league_name ILIKE '%' || sqlc.arg(league_name)::text || '%'
+    );
+
+-- name: ListHighVolumeEvents :many
+-- Events from the high_volume_events view, highest share of volume first
+SELECT
+    hv.event_id,
+    hv.event_slug,
+    hv.sport_name,
+    hv.sport_slug,
+    hv.league_name,
+    hv.home_team,
+    hv.away_team,
+    hv.event_date,
+    hv.status,
+    hv.betting_volume_percentage,
+    hv.volume_rank,
+    hv.recent_volume_change::float8 as recent_volume_change,
+    hv.volume_updated_at,
+    hv.volume_category
+FROM
+    high_volume_events hv
+WHERE
+    hv.event_date >= sqlc.arg(from_time)::timestamp
+    AND hv.event_date <= sqlc.arg(to_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR hv.sport_slug IN (
+            SELECT
+                slug
+            FROM
+                sports
+            WHERE
+                code = sqlc.arg(sport_code)::text
+                OR slug = sqlc.arg(sport_code)::text
+        )
+    )
+    AND (
+        sqlc.arg(league_name)::text = ''
+        OR hv.league_name ILIKE '%' || sqlc.arg(league_name)::text || '%'
+    )
+    AND (
+        CASE
+            hv.volume_category
+            WHEN 'TOP_5_VOLUME' THEN 4
+            WHEN 'TOP_10_VOLUME' THEN 3
+            WHEN 'HIGH_VOLUME' THEN 2
+            ELSE 1
+        END
+    ) >= sqlc.arg(min_strength)::int
+ORDER BY
+    hv.betting_volume_percentage DESC NULLS LAST,
+    hv.event_id
+LIMIT
+    sqlc.arg(limit_count)::int OFFSET sqlc.arg(offset_count)::int;
+
+-- name: CountHighVolumeEvents :one
+SELECT
+    COUNT(*)::int
+FROM
+    high_volume_events hv
+WHERE
+    hv.event_date >= sqlc.arg(from_time)::timestamp
+    AND hv.event_date <= sqlc.arg(to_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR hv.sport_slug IN (
+            SELECT
+                slug
+            FROM
+                sports
+            WHERE
+                code = sqlc.arg(sport_code)::text
+                OR slug = sqlc.arg(sport_code)::text
+        )
+    )
+    AND (
+        sqlc.arg(league_name)::text = ''
+        OR hv.league_name ILIKE '%' || sqlc.arg(league_name)::text || '%'
+    )
+    AND (
+        CASE
+            hv.volume_category
+            WHEN 'TOP_5_VOLUME' THEN 4
+            WHEN 'TOP_10_VOLUME' THEN 3
+            WHEN 'HIGH_VOLUME' THEN 2
+            ELSE 1
+        END
+    ) >= sqlc.arg(min_strength)::int;
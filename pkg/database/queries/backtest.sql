@@ -0,0 +1,128 @@
+-- Backtest replay queries
+-- All queries cover events kicking off within [from_time, to_time)
+-- name: GetBacktestEvents :many
+SELECT
+    e.id,
+    e.slug,
+    e.event_date,
+    e.status,
+    COALESCE(s.code, '')::text as sport_code,
+    COALESCE(l.name, '')::text as league_name
+FROM
+    events e
+    LEFT JOIN sports s ON e.sport_id = s.id
+    LEFT JOIN leagues l ON e.league_id = l.id
+WHERE
+    e.event_date >= sqlc.arg(from_time)::timestamp
+    AND e.event_date < sqlc.arg(to_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    )
+ORDER BY
+    e.event_date;
+
+-- name: GetBacktestOddsHistory :many
+-- Pre-kickoff odds movements, paged by id
+SELECT
+    oh.id,
+    oh.event_id,
+    oh.market_type_id,
+    oh.outcome,
+    oh.odds_value,
+    oh.change_percentage,
+    oh.multiplier,
+    oh.recorded_at
+FROM
+    odds_history oh
+    JOIN events e ON oh.event_id = e.id
+    LEFT JOIN sports s ON e.sport_id = s.id
+WHERE
+    oh.bookmaker = 'iddaa'
+    AND e.event_date >= sqlc.arg(from_time)::timestamp
+    AND e.event_date < sqlc.arg(to_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    )
+    AND oh.recorded_at < e.event_date
+    AND oh.id > sqlc.arg(after_id)::int
+ORDER BY
+    oh.id
+LIMIT
+    sqlc.arg(limit_count)::int;
+
+-- name: GetBacktestDistributionHistory :many
+-- Pre-kickoff public bet percentages, paged by id
+SELECT
+    odh.id,
+    odh.event_id,
+    odh.market_id,
+    odh.outcome,
+    odh.bet_percentage,
+    odh.recorded_at
+FROM
+    outcome_distribution_history odh
+    JOIN events e ON odh.event_id = e.id
+    LEFT JOIN sports s ON e.sport_id = s.id
+WHERE
+    e.event_date >= sqlc.arg(from_time)::timestamp
+    AND e.event_date < sqlc.arg(to_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    )
+    AND odh.recorded_at < e.event_date
+    AND odh.id > sqlc.arg(after_id)::int
+ORDER BY
+    odh.id
+LIMIT
+    sqlc.arg(limit_count)::int;
+
+-- name: GetBacktestVolumeHistory :many
+-- Pre-kickoff betting volume snapshots, paged by id
+SELECT
+    bvh.id,
+    bvh.event_id,
+    bvh.volume_percentage,
+    bvh.rank_position,
+    bvh.recorded_at
+FROM
+    betting_volume_history bvh
+    JOIN events e ON bvh.event_id = e.id
+    LEFT JOIN sports s ON e.sport_id = s.id
+WHERE
+    e.event_date >= sqlc.arg(from_time)::timestamp
+    AND e.event_date < sqlc.arg(to_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    )
+    AND bvh.recorded_at < e.event_date
+    AND bvh.id > sqlc.arg(after_id)::int
+ORDER BY
+    bvh.id
+LIMIT
+    sqlc.arg(limit_count)::int;
+
+-- name: GetBacktestSettlements :many
+SELECT
+    os.event_id,
+    os.market_type_id,
+    os.outcome,
+    os.result,
+    co.closing_value
+FROM
+    outcome_settlements os
+    JOIN events e ON os.event_id = e.id
+    LEFT JOIN sports s ON e.sport_id = s.id
+    LEFT JOIN closing_odds co ON co.event_id = os.event_id
+    AND co.market_type_id = os.market_type_id
+    AND co.outcome = os.outcome
+WHERE
+    e.event_date >= sqlc.arg(from_time)::timestamp
+    AND e.event_date < sqlc.arg(to_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    );
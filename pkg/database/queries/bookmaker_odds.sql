@@ -0,0 +1,369 @@
+-- Odds from bookmakers other than Iddaa, written by odds providers
+-- name: UpsertBookmakers :exec
+WITH input_data AS (
+    SELECT
+        unnest(sqlc.arg(codes)::text[]) as code,
+        unnest(sqlc.arg(names)::text[]) as name,
+        unnest(sqlc.arg(provider_bookmaker_ids)::int[]) as provider_bookmaker_id
+)
+INSERT INTO
+    bookmakers (code, name, provider, provider_bookmaker_id)
+SELECT
+    code,
+    name,
+    sqlc.arg(provider)::text,
+    provider_bookmaker_id
+FROM
+    input_data ON CONFLICT (code) DO
+UPDATE
+SET
+    name = EXCLUDED.name,
+    provider_bookmaker_id = EXCLUDED.provider_bookmaker_id
+WHERE
+    bookmakers.provider = EXCLUDED.provider;
+
+-- name: ListBookmakers :many
+SELECT
+    *
+FROM
+    bookmakers
+WHERE
+    is_active
+ORDER BY
+    code;
+
+-- name: GetProviderOddsTargets :many
+-- Upcoming events whose league and both teams are mapped to API-Football
+SELECT
+    e.id,
+    e.event_date,
+    lm.football_api_league_id,
+    htm.football_api_team_id as home_api_team_id,
+    atm.football_api_team_id as away_api_team_id
+FROM
+    events e
+    JOIN league_mappings lm ON lm.internal_league_id = e.league_id
+    JOIN team_mappings htm ON htm.internal_team_id = e.home_team_id
+    JOIN team_mappings atm ON atm.internal_team_id = e.away_team_id
+WHERE
+    e.status = 'scheduled'
+    AND e.event_date > NOW()
+    AND e.event_date <= sqlc.arg(to_time)::timestamp
+    AND EXISTS (
+        SELECT
+            1
+        FROM
+            current_odds co
+        WHERE
+            co.event_id = e.id
+            AND co.bookmaker = 'iddaa'
+    )
+ORDER BY
+    e.event_date
+LIMIT
+    sqlc.arg(limit_count)::int;
+
+-- name: GetIddaaOutcomesForEvents :many
+-- Iddaa outcomes provider prices are matched against
+SELECT
+    co.event_id,
+    co.market_type_id,
+    mt.name as market_name,
+    co.outcome,
+    co.market_params
+FROM
+    current_odds co
+    JOIN market_types mt ON co.market_type_id = mt.id
+WHERE
+    co.bookmaker = 'iddaa'
+    AND co.event_id = ANY(sqlc.arg(event_ids)::int[]);
+
+-- name: BulkUpsertBookmakerOdds :execrows
+-- Upserts provider prices and records a history row for every price that is new or changed
+WITH input_data AS (
+    SELECT
+        unnest(sqlc.arg(event_ids)::int[]) as event_id,
+        unnest(sqlc.arg(market_type_ids)::int[]) as market_type_id,
+        unnest(sqlc.arg(outcomes)::text[]) as outcome,
+        unnest(sqlc.arg(bookmakers)::text[]) as bookmaker,
+        unnest(sqlc.arg(odds_values)::float8[]) as odds_value,
+        unnest(sqlc.arg(market_params)::jsonb[]) as market_params
+),
+previous AS (
+    SELECT
+        co.event_id,
+        co.market_type_id,
+        co.outcome,
+        co.bookmaker,
+        co.odds_value
+    FROM
+        current_odds co
+        JOIN input_data i ON co.event_id = i.event_id
+        AND co.market_type_id = i.market_type_id
+        AND co.outcome = i.outcome
+        AND co.bookmaker = i.bookmaker
+),
+upserted AS (
+    INSERT INTO
+        current_odds (
+            event_id,
+            market_type_id,
+            outcome,
+            bookmaker,
+            odds_value,
+            opening_value,
+            highest_value,
+            lowest_value,
+            total_movement,
+            movement_percentage,
+            market_params,
+            last_updated
+        )
+    SELECT
+        event_id,
+        market_type_id,
+        outcome,
+        bookmaker,
+        odds_value,
+        odds_value,
+        odds_value,
+        odds_value,
+        0,
+        0,
+        market_params,
+        NOW()
+    FROM
+        input_data ON CONFLICT (event_id, market_type_id, outcome, bookmaker) DO
+    UPDATE
+    SET
+        odds_value = EXCLUDED.odds_value,
+        highest_value = GREATEST(current_odds.highest_value, EXCLUDED.odds_value),
+        lowest_value = LEAST(current_odds.lowest_value, EXCLUDED.odds_value),
+        total_movement = EXCLUDED.odds_value - current_odds.opening_value,
+        movement_percentage = CASE
+            WHEN current_odds.opening_value > 0 THEN (
+                (EXCLUDED.odds_value - current_odds.opening_value) / current_odds.opening_value * 100
+            )::REAL
+            ELSE 0
+        END,
+        market_params = EXCLUDED.market_params,
+        -- Refreshed on every sync so stale books drop out of comparisons
+        last_updated = EXCLUDED.last_updated RETURNING event_id,
+        market_type_id,
+        outcome,
+        bookmaker,
+        odds_value,
+        market_params,
+        last_updated
+)
+INSERT INTO
+    odds_history (
+        event_id,
+        market_type_id,
+        outcome,
+        bookmaker,
+        odds_value,
+        previous_value,
+        change_amount,
+        change_percentage,
+        multiplier,
+        market_params,
+        recorded_at
+    )
+SELECT
+    u.event_id,
+    u.market_type_id,
+    u.outcome,
+    u.bookmaker,
+    u.odds_value,
+    p.odds_value,
+    COALESCE(u.odds_value - p.odds_value, 0),
+    CASE
+        WHEN p.odds_value > 0 THEN ((u.odds_value - p.odds_value) / p.odds_value * 100)::REAL
+        ELSE 0
+    END,
+    CASE
+        WHEN p.odds_value > 0 THEN u.odds_value / p.odds_value
+        ELSE 1
+    END,
+    u.market_params,
+    u.last_updated
+FROM
+    upserted u
+    LEFT JOIN previous p ON p.event_id = u.event_id
+    AND p.market_type_id = u.market_type_id
+    AND p.outcome = u.outcome
+    AND p.bookmaker = u.bookmaker
+WHERE
+    p.odds_value IS DISTINCT
+FROM
+    u.odds_value;
+
+-- name: GetEventBookmakerOdds :many
+-- Every bookmaker price for the outcomes Iddaa offers on an event
+SELECT
+    co.market_type_id,
+    mt.code as market_code,
+    mt.name as market_name,
+    co.outcome,
+    co.market_params,
+    co.bookmaker,
+    b.name as bookmaker_name,
+    co.odds_value,
+    co.is_suspended,
+    co.last_updated
+FROM
+    current_odds co
+    JOIN market_types mt ON co.market_type_id = mt.id
+    JOIN bookmakers b ON b.code = co.bookmaker
+WHERE
+    co.event_id = sqlc.arg(event_id)::int
+    AND b.is_active
+    AND EXISTS (
+        SELECT
+            1
+        FROM
+            current_odds iddaa
+        WHERE
+            iddaa.event_id = co.event_id
+            AND iddaa.market_type_id = co.market_type_id
+            AND iddaa.outcome = co.outcome
+            AND iddaa.bookmaker = 'iddaa'
+    )
+ORDER BY
+    co.market_type_id,
+    co.outcome,
+    co.odds_value DESC;
+
+-- name: GetBestPrices :many
+-- Upcoming outcomes where Iddaa's price deviates from the median of the other books
+WITH market AS (
+    SELECT
+        co.event_id,
+        co.market_type_id,
+        co.outcome,
+        COUNT(*)::int as bookmaker_count,
+        percentile_cont(0.5) WITHIN GROUP (
+            ORDER BY
+                co.odds_value
+        )::float8 as median_odds,
+        MAX(co.odds_value)::float8 as best_odds,
+        (
+            ARRAY_AGG(
+                co.bookmaker
+                ORDER BY
+                    co.odds_value DESC
+            )
+        ) [1]::text as best_bookmaker
+    FROM
+        current_odds co
+        JOIN bookmakers b ON b.code = co.bookmaker
+    WHERE
+        co.bookmaker <> 'iddaa'
+        AND b.is_active
+        AND NOT co.is_suspended
+        AND co.last_updated >= sqlc.arg(since_time)::timestamp
+    GROUP BY
+        co.event_id,
+        co.market_type_id,
+        co.outcome
+)
+SELECT
+    e.slug as event_slug,
+    (ht.name || ' vs ' || at.name)::text as match_name,
+    s.code as sport_code,
+    l.name as league_name,
+    e.event_date,
+    mt.code as market_code,
+    mt.name as market_name,
+    co.market_params,
+    co.outcome,
+    co.odds_value as iddaa_odds,
+    m.median_odds,
+    m.best_odds,
+    m.best_bookmaker,
+    m.bookmaker_count,
+    ((co.odds_value / m.median_odds - 1) * 100)::float8 as deviation_percentage
+FROM
+    current_odds co
+    JOIN market m ON m.event_id = co.event_id
+    AND m.market_type_id = co.market_type_id
+    AND m.outcome = co.outcome
+    JOIN events e ON co.event_id = e.id
+    JOIN teams ht ON e.home_team_id = ht.id
+    JOIN teams at ON e.away_team_id = at.id
+    JOIN leagues l ON e.league_id = l.id
+    JOIN sports s ON e.sport_id = s.id
+    JOIN market_types mt ON co.market_type_id = mt.id
+WHERE
+    co.bookmaker = 'iddaa'
+    AND NOT co.is_suspended
+    AND e.status = 'scheduled'
+    AND e.event_date > NOW()
+    AND m.bookmaker_count >= sqlc.arg(min_bookmakers)::int
+    AND m.median_odds > 0
+    AND ABS(co.odds_value / m.median_odds - 1) * 100 >= sqlc.arg(min_deviation)::float8
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    )
+    AND (
+        sqlc.arg(league_name)::text = ''
+        OR l.name ILIKE '%' || sqlc.arg(league_name)::text || '%'
+    )
+ORDER BY
+    ABS(co.odds_value / m.median_odds - 1) DESC,
+    co.id
+LIMIT
+    sqlc.arg(limit_count)::int OFFSET sqlc.arg(offset_count)::int;
+
+-- name: CountBestPrices :one
+WITH market AS (
+    SELECT
+        co.event_id,
+        co.market_type_id,
+        co.outcome,
+        COUNT(*)::int as bookmaker_count,
+        percentile_cont(0.5) WITHIN GROUP (
+            ORDER BY
+                co.odds_value
+        )::float8 as median_odds
+    FROM
+        current_odds co
+        JOIN bookmakers b ON b.code = co.bookmaker
+    WHERE
+        co.bookmaker <> 'iddaa'
+        AND b.is_active
+        AND NOT co.is_suspended
+        AND co.last_updated >= sqlc.arg(since_time)::timestamp
+    GROUP BY
+        co.event_id,
+        co.market_type_id,
+        co.outcome
+)
+SELECT
+    COUNT(*)::int
+FROM
+    current_odds co
+    JOIN market m ON m.event_id = co.event_id
+    AND m.market_type_id = co.market_type_id
+    AND m.outcome = co.outcome
+    JOIN events e ON co.event_id = e.id
+    JOIN leagues l ON e.league_id = l.id
+    JOIN sports s ON e.sport_id = s.id
+WHERE
+    co.bookmaker = 'iddaa'
+    AND NOT co.is_suspended
+    AND e.status = 'scheduled'
+    AND e.event_date > NOW()
+    AND m.bookmaker_count >= sqlc.arg(min_bookmakers)::int
+    AND m.median_odds > 0
+    AND ABS(co.odds_value / m.median_odds - 1) * 100 >= sqlc.arg(min_deviation)::float8
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    )
+    AND (
+        sqlc.arg(league_name)::text = ''
+        OR l.name ILIKE '%' || sqlc.arg(league_name)::text || '%'
+    );
@@ -0,0 +1,353 @@
+-- OHLC odds candles rolled up from odds_history
+-- name: GetLatestOddsCandle :one
+-- Start of the newest 5-minute candle, the point the next rollup resumes from
+SELECT
+    MAX(bucket_start)::timestamp as latest_bucket
+FROM
+    odds_candles_5m;
+
+-- name: RollupOddsCandles5m :execrows
+-- Rebuilds the 5-minute candles of odds_history rows recorded from the bucket of since_time up to until_time
+WITH ticks AS (
+    SELECT
+        oh.id,
+        oh.event_id,
+        oh.market_type_id,
+        oh.bookmaker,
+        CASE
+            WHEN jsonb_typeof(oh.market_params -> 'values') = 'array' THEN array_to_string(
+                ARRAY(
+                    SELECT
+                        jsonb_array_elements_text(oh.market_params -> 'values')
+                ),
+                '|'
+            )
+            ELSE ''
+        END as market_key,
+        oh.outcome,
+        oh.odds_value,
+        oh.recorded_at,
+        date_bin(
+            '5 minutes',
+            oh.recorded_at,
+            TIMESTAMP '2000-01-01'
+        ) as bucket_start
+    FROM
+        odds_history oh
+    WHERE
+        oh.recorded_at >= date_bin(
+            '5 minutes',
+            sqlc.arg(since_time)::timestamp,
+            TIMESTAMP '2000-01-01'
+        )
+        AND oh.recorded_at < sqlc.arg(until_time)::timestamp
+        AND oh.event_id IS NOT NULL
+        AND oh.market_type_id IS NOT NULL
+        AND oh.odds_value > 0
+)
+INSERT INTO
+    odds_candles_5m (
+            event_id,
+            market_type_id,
+            bookmaker,
+            market_key,
+            outcome,
+            bucket_start,
+            open_odds,
+            high_odds,
+            low_odds,
+            close_odds,
+            tick_count,
+            first_recorded_at,
+            last_recorded_at
+    )
+SELECT
+    event_id,
+    market_type_id,
+    bookmaker,
+    market_key,
+    outcome,
+    bucket_start,
+    (array_agg(odds_value ORDER BY recorded_at, id))[1] as open_odds,
+    MAX(odds_value) as high_odds,
+    MIN(odds_value) as low_odds,
+    (array_agg(odds_value ORDER BY recorded_at DESC, id DESC))[1] as close_odds,
+    COUNT(*)::int as tick_count,
+    MIN(recorded_at) as first_recorded_at,
+    MAX(recorded_at) as last_recorded_at
+FROM
+    ticks
+GROUP BY
+    event_id,
+    market_type_id,
+    bookmaker,
+    market_key,
+    outcome,
+    bucket_start ON CONFLICT (
+        event_id,
+        bookmaker,
+        market_type_id,
+        market_key,
+        outcome,
+        bucket_start
+    ) DO
+UPDATE
+SET
+    open_odds = EXCLUDED.open_odds,
+    high_odds = EXCLUDED.high_odds,
+    low_odds = EXCLUDED.low_odds,
+    close_odds = EXCLUDED.close_odds,
+    tick_count = EXCLUDED.tick_count,
+    first_recorded_at = EXCLUDED.first_recorded_at,
+    last_recorded_at = EXCLUDED.last_recorded_at;
+
+-- name: RollupOddsCandles1h :execrows
+-- Rebuilds the hourly candles from 5-minute candles from the hour of since_time up to until_time
+INSERT INTO
+    odds_candles_1h (
+            event_id,
+            market_type_id,
+            bookmaker,
+            market_key,
+            outcome,
+            bucket_start,
+            open_odds,
+            high_odds,
+            low_odds,
+            close_odds,
+            tick_count,
+            first_recorded_at,
+            last_recorded_at
+    )
+SELECT
+    event_id,
+    market_type_id,
+    bookmaker,
+    market_key,
+    outcome,
+    date_trunc('hour', bucket_start) as bucket_start,
+    (array_agg(open_odds ORDER BY bucket_start))[1] as open_odds,
+    MAX(high_odds) as high_odds,
+    MIN(low_odds) as low_odds,
+    (array_agg(close_odds ORDER BY bucket_start DESC))[1] as close_odds,
+    SUM(tick_count)::int as tick_count,
+    MIN(first_recorded_at) as first_recorded_at,
+    MAX(last_recorded_at) as last_recorded_at
+FROM
+    odds_candles_5m
+WHERE
+    bucket_start >= date_trunc('hour', sqlc.arg(since_time)::timestamp)
+    AND bucket_start < sqlc.arg(until_time)::timestamp
+GROUP BY
+    event_id,
+    market_type_id,
+    bookmaker,
+    market_key,
+    outcome,
+    date_trunc('hour', bucket_start) ON CONFLICT (
+        event_id,
+        bookmaker,
+        market_type_id,
+        market_key,
+        outcome,
+        bucket_start
+    ) DO
+UPDATE
+SET
+    open_odds = EXCLUDED.open_odds,
+    high_odds = EXCLUDED.high_odds,
+    low_odds = EXCLUDED.low_odds,
+    close_odds = EXCLUDED.close_odds,
+    tick_count = EXCLUDED.tick_count,
+    first_recorded_at = EXCLUDED.first_recorded_at,
+    last_recorded_at = EXCLUDED.last_recorded_at;
+
+-- name: RollupOddsCandles1d :execrows
+-- Rebuilds the daily candles from hourly candles from the day of since_time up to until_time
+INSERT INTO
+    odds_candles_1d (
+            event_id,
+            market_type_id,
+            bookmaker,
+            market_key,
+            outcome,
+            bucket_start,
+            open_odds,
+            high_odds,
+            low_odds,
+            close_odds,
+            tick_count,
+            first_recorded_at,
+            last_recorded_at
+    )
+SELECT
+    event_id,
+    market_type_id,
+    bookmaker,
+    market_key,
+    outcome,
+    date_trunc('day', bucket_start) as bucket_start,
+    (array_agg(open_odds ORDER BY bucket_start))[1] as open_odds,
+    MAX(high_odds) as high_odds,
+    MIN(low_odds) as low_odds,
+    (array_agg(close_odds ORDER BY bucket_start DESC))[1] as close_odds,
+    SUM(tick_count)::int as tick_count,
+    MIN(first_recorded_at) as first_recorded_at,
+    MAX(last_recorded_at) as last_recorded_at
+FROM
+    odds_candles_1h
+WHERE
+    bucket_start >= date_trunc('day', sqlc.arg(since_time)::timestamp)
+    AND bucket_start < sqlc.arg(until_time)::timestamp
+GROUP BY
+    event_id,
+    market_type_id,
+    bookmaker,
+    market_key,
+    outcome,
+    date_trunc('day', bucket_start) ON CONFLICT (
+        event_id,
+        bookmaker,
+        market_type_id,
+        market_key,
+        outcome,
+        bucket_start
+    ) DO
+UPDATE
+SET
+    open_odds = EXCLUDED.open_odds,
+    high_odds = EXCLUDED.high_odds,
+    low_odds = EXCLUDED.low_odds,
+    close_odds = EXCLUDED.close_odds,
+    tick_count = EXCLUDED.tick_count,
+    first_recorded_at = EXCLUDED.first_recorded_at,
+    last_recorded_at = EXCLUDED.last_recorded_at;
+
+-- name: DeleteOddsCandles5mRange :execrows
+-- Deletes the 5-minute candles starting in the range, before it is rolled up again
+DELETE FROM
+    odds_candles_5m
+WHERE
+    bucket_start >= sqlc.arg(from_time)::timestamp
+    AND bucket_start < sqlc.arg(to_time)::timestamp;
+
+-- name: DeleteOddsCandles1hRange :execrows
+-- Deletes the hourly candles starting in the range, before it is rolled up again
+DELETE FROM
+    odds_candles_1h
+WHERE
+    bucket_start >= sqlc.arg(from_time)::timestamp
+    AND bucket_start < sqlc.arg(to_time)::timestamp;
+
+-- name: DeleteOddsCandles1dRange :execrows
+-- Deletes the daily candles starting in the range, before it is rolled up again
+DELETE FROM
+    odds_candles_1d
+WHERE
+    bucket_start >= sqlc.arg(from_time)::timestamp
+    AND bucket_start < sqlc.arg(to_time)::timestamp;
+
+-- name: GetEventCandles5m :many
+-- 5-minute candles of an event from the given time, oldest first per outcome
+SELECT
+    c.market_type_id,
+    mt.code as market_code,
+    mt.name as market_name,
+    c.market_key,
+    c.outcome,
+    c.bucket_start,
+    c.open_odds,
+    c.high_odds,
+    c.low_odds,
+    c.close_odds,
+    c.tick_count
+FROM
+    odds_candles_5m c
+    JOIN market_types mt ON mt.id = c.market_type_id
+WHERE
+    c.event_id = sqlc.arg(event_id)::int
+    AND c.bookmaker = sqlc.arg(bookmaker)::text
+    AND c.bucket_start >= sqlc.arg(since_time)::timestamp
+    AND (
+        sqlc.narg(market_type_id)::int IS NULL
+        OR c.market_type_id = sqlc.narg(market_type_id)::int
+    )
+    AND (
+        sqlc.narg(outcome)::text IS NULL
+        OR c.outcome = sqlc.narg(outcome)::text
+    )
+ORDER BY
+    c.market_type_id,
+    c.market_key,
+    c.outcome,
+    c.bucket_start;
+
+-- name: GetEventCandles1h :many
+-- Hourly candles of an event from the given time, oldest first per outcome
+SELECT
+    c.market_type_id,
+    mt.code as market_code,
+    mt.name as market_name,
+    c.market_key,
+    c.outcome,
+    c.bucket_start,
+    c.open_odds,
+    c.high_odds,
+    c.low_odds,
+    c.close_odds,
+    c.tick_count
+FROM
+    odds_candles_1h c
+    JOIN market_types mt ON mt.id = c.market_type_id
+WHERE
+    c.event_id = sqlc.arg(event_id)::int
+    AND c.bookmaker = sqlc.arg(bookmaker)::text
+    AND c.bucket_start >= sqlc.arg(since_time)::timestamp
+    AND (
+        sqlc.narg(market_type_id)::int IS NULL
+        OR c.market_type_id = sqlc.narg(market_type_id)::int
+    )
+    AND (
+        sqlc.narg(outcome)::text IS NULL
+        OR c.outcome = sqlc.narg(outcome)::text
+    )
+ORDER BY
+    c.market_type_id,
+    c.market_key,
+    c.outcome,
+    c.bucket_start;
+
+-- name: GetEventCandles1d :many
+-- Daily candles of an event from the given time, oldest first per outcome
+SELECT
+    c.market_type_id,
+    mt.code as market_code,
+    mt.name as market_name,
+    c.market_key,
+    c.outcome,
+    c.bucket_start,
+    c.open_odds,
+    c.high_odds,
+    c.low_odds,
+    c.close_odds,
+    c.tick_count
+FROM
+    odds_candles_1d c
+    JOIN market_types mt ON mt.id = c.market_type_id
+WHERE
+    c.event_id = sqlc.arg(event_id)::int
+    AND c.bookmaker = sqlc.arg(bookmaker)::text
+    AND c.bucket_start >= sqlc.arg(since_time)::timestamp
+    AND (
+        sqlc.narg(market_type_id)::int IS NULL
+        OR c.market_type_id = sqlc.narg(market_type_id)::int
+    )
+    AND (
+        sqlc.narg(outcome)::text IS NULL
+        OR c.outcome = sqlc.narg(outcome)::text
+    )
+ORDER BY
+    c.market_type_id,
+    c.market_key,
+    c.outcome,
+    c.bucket_start;
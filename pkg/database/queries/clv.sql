@@ -0,0 +1,300 @@
+-- Closing line value queries
+-- name: FreezeClosingOdds :execrows
+-- Freezes the last pre-kickoff price of every market/outcome for finished events
+-- Markets that never moved before kickoff close at their opening price
+INSERT INTO
+    closing_odds (
+        event_id,
+        market_type_id,
+        outcome,
+        market_params,
+        opening_value,
+        closing_value,
+        closing_recorded_at
+    )
+SELECT
+    candidate.event_id,
+    candidate.market_type_id,
+    candidate.outcome,
+    candidate.market_params,
+    candidate.opening_value,
+    candidate.closing_value,
+    candidate.closing_recorded_at
+FROM
+    (
+        SELECT
+            co.event_id,
+            co.market_type_id,
+            co.outcome,
+            co.market_params,
+            co.opening_value,
+            COALESCE(
+                last_pre.odds_value,
+                CASE
+                    WHEN co.last_updated <= e.event_date THEN co.odds_value
+                    ELSE co.opening_value
+                END
+            ) as closing_value,
+            COALESCE(
+                last_pre.recorded_at,
+                LEAST(co.last_updated, e.event_date)
+            ) as closing_recorded_at
+        FROM
+            current_odds co
+            JOIN events e ON co.event_id = e.id
+            LEFT JOIN LATERAL (
+                SELECT
+                    oh.odds_value,
+                    oh.recorded_at
+                FROM
+                    odds_history oh
+                WHERE
+                    oh.event_id = co.event_id
+                    AND oh.market_type_id = co.market_type_id
+                    AND oh.outcome = co.outcome
+                    AND oh.bookmaker = co.bookmaker
+                    AND oh.recorded_at < e.event_date
+                ORDER BY
+                    oh.recorded_at DESC
+                LIMIT
+                    1
+            ) last_pre ON true
+        WHERE
+            e.id = ANY(sqlc.arg(event_ids)::int[])
+            AND e.status = 'finished'
+            AND co.bookmaker = 'iddaa'
+    ) candidate
+WHERE
+    candidate.closing_value > 0
+    AND candidate.closing_recorded_at IS NOT NULL ON CONFLICT (event_id, market_type_id, outcome) DO NOTHING;
+
+-- name: GetClosingLineSnapshots :many
+-- Pre-kickoff odds_history snapshots of the given events with their frozen close
+SELECT
+    oh.id as odds_history_id,
+    cl.event_id,
+    cl.id as closing_odds_id,
+    oh.odds_value,
+    cl.closing_value
+FROM
+    closing_odds cl
+    JOIN events e ON cl.event_id = e.id
+    JOIN odds_history oh ON (
+        oh.event_id = cl.event_id
+        AND oh.market_type_id = cl.market_type_id
+        AND oh.outcome = cl.outcome
+        AND oh.bookmaker = 'iddaa'
+    )
+WHERE
+    cl.event_id = ANY(sqlc.arg(event_ids)::int[])
+    AND oh.recorded_at < e.event_date
+    AND oh.odds_value > 0;
+
+-- name: UpsertClosingLineValues :execrows
+-- Stores the CLV of snapshots, recomputing replaces the previous value
+INSERT INTO
+    closing_line_values (
+        odds_history_id,
+        event_id,
+        closing_odds_id,
+        odds_value,
+        closing_value,
+        clv_percentage,
+        clv_probability
+    )
+SELECT
+    unnest(sqlc.arg(odds_history_ids)::int[]),
+    unnest(sqlc.arg(event_ids)::int[]),
+    unnest(sqlc.arg(closing_odds_ids)::int[]),
+    unnest(sqlc.arg(odds_values)::float8[]),
+    unnest(sqlc.arg(closing_values)::float8[]),
+    unnest(sqlc.arg(clv_percentages)::real[]),
+    unnest(sqlc.arg(clv_probabilities)::real[]) ON CONFLICT (odds_history_id) DO
+UPDATE
+SET
+    closing_odds_id = EXCLUDED.closing_odds_id,
+    closing_value = EXCLUDED.closing_value,
+    clv_percentage = EXCLUDED.clv_percentage,
+    clv_probability = EXCLUDED.clv_probability,
+    computed_at = CURRENT_TIMESTAMP;
+
+-- name: GetEventsPendingClosingLines :many
+-- Finished events of the last lookback_days that have odds but no frozen closing line yet. Events
+-- whose freeze stores nothing are retried until they fall out of the lookback.
+SELECT
+    e.id
+FROM
+    events e
+WHERE
+    e.status = 'finished'
+    AND e.event_date >= CURRENT_TIMESTAMP - make_interval(days => sqlc.arg(lookback_days)::int)
+    AND EXISTS (
+        SELECT
+            1
+        FROM
+            current_odds co
+        WHERE
+            co.event_id = e.id
+            AND co.bookmaker = 'iddaa'
+    )
+    AND NOT EXISTS (
+        SELECT
+            1
+        FROM
+            closing_odds cl
+        WHERE
+            cl.event_id = e.id
+    )
+ORDER BY
+    e.event_date DESC
+LIMIT
+    sqlc.arg(limit_count)::int;
+
+-- name: GetClosingOddsByEvent :many
+SELECT
+    cl.*,
+    mt.name as market_name
+FROM
+    closing_odds cl
+    JOIN market_types mt ON cl.market_type_id = mt.id
+WHERE
+    cl.event_id = sqlc.arg(event_id)::int
+ORDER BY
+    cl.market_type_id,
+    cl.outcome;
+
+-- name: GetAlertCLVSummary :many
+-- CLV of alerts grouped by type and price direction at alert time
+SELECT
+    ma.alert_type,
+    (
+        CASE
+            WHEN ma.change_percentage < 0 THEN 'shortening'
+            ELSE 'drifting'
+        END
+    )::text as direction,
+    COUNT(*)::int as alerts,
+    COUNT(*) FILTER (
+        WHERE
+            clv.clv_percentage > 0
+    )::int as beat_close,
+    AVG(clv.clv_percentage)::float8 as avg_clv_percentage,
+    AVG(clv.clv_probability)::float8 as avg_clv_probability,
+    (PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY clv.clv_percentage))::float8 as median_clv_percentage
+FROM
+    movement_alerts ma
+    JOIN closing_line_values clv ON clv.odds_history_id = ma.odds_history_id
+    JOIN events e ON clv.event_id = e.id
+    LEFT JOIN sports s ON e.sport_id = s.id
+WHERE
+    ma.created_at >= sqlc.arg(since_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    )
+GROUP BY
+    ma.alert_type,
+    direction
+ORDER BY
+    ma.alert_type,
+    direction;
+
+-- name: GetSteamMoveCLVSummary :one
+-- CLV of steam moves: 3+ moves of at least 3% on one outcome within an hour
+SELECT
+    COUNT(*)::int as moves,
+    COUNT(*) FILTER (
+        WHERE
+            steam.clv_percentage > 0
+    )::int as beat_close,
+    COALESCE(AVG(steam.clv_percentage), 0)::float8 as avg_clv_percentage,
+    COALESCE(AVG(steam.clv_probability), 0)::float8 as avg_clv_probability
+FROM
+    (
+        SELECT
+            clv.clv_percentage,
+            clv.clv_probability,
+            oh.change_percentage,
+            COUNT(*) OVER (
+                PARTITION BY oh.event_id,
+                oh.market_type_id,
+                oh.outcome
+                ORDER BY
+                    oh.recorded_at RANGE BETWEEN INTERVAL '1 hour' PRECEDING
+                    AND CURRENT ROW
+            ) as movements_last_hour
+        FROM
+            closing_line_values clv
+            JOIN odds_history oh ON clv.odds_history_id = oh.id
+            JOIN events e ON clv.event_id = e.id
+            LEFT JOIN sports s ON e.sport_id = s.id
+        WHERE
+            oh.recorded_at >= sqlc.arg(since_time)::timestamp
+            AND ABS(oh.change_percentage) >= 3
+            AND (
+                sqlc.arg(sport_code)::text = ''
+                OR s.code = sqlc.arg(sport_code)::text
+            )
+    ) steam
+WHERE
+    steam.movements_last_hour >= 3;
+
+-- name: GetSnapshotCLVBaseline :one
+-- CLV across all pre-kickoff snapshots, the baseline alerts should beat
+SELECT
+    COUNT(*)::int as snapshots,
+    COUNT(*) FILTER (
+        WHERE
+            clv.clv_percentage > 0
+    )::int as beat_close,
+    COALESCE(AVG(clv.clv_percentage), 0)::float8 as avg_clv_percentage,
+    COALESCE(AVG(clv.clv_probability), 0)::float8 as avg_clv_probability
+FROM
+    closing_line_values clv
+    JOIN odds_history oh ON clv.odds_history_id = oh.id
+    JOIN events e ON clv.event_id = e.id
+    LEFT JOIN sports s ON e.sport_id = s.id
+WHERE
+    oh.recorded_at >= sqlc.arg(since_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    );
+
+-- name: GetRecentAlertCLVs :many
+SELECT
+    ma.id as alert_id,
+    ma.alert_type,
+    ma.severity,
+    ma.title,
+    ma.change_percentage,
+    ma.confidence_score,
+    ma.created_at,
+    e.slug as event_slug,
+    oh.outcome,
+    mt.name as market_name,
+    clv.odds_value,
+    clv.closing_value,
+    clv.clv_percentage,
+    clv.clv_probability
+FROM
+    movement_alerts ma
+    JOIN closing_line_values clv ON clv.odds_history_id = ma.odds_history_id
+    JOIN odds_history oh ON ma.odds_history_id = oh.id
+    JOIN events e ON clv.event_id = e.id
+    JOIN market_types mt ON oh.market_type_id = mt.id
+    LEFT JOIN sports s ON e.sport_id = s.id
+WHERE
+    ma.created_at >= sqlc.arg(since_time)::timestamp
+    AND (
+        sqlc.arg(alert_type)::text = ''
+        OR ma.alert_type = sqlc.arg(alert_type)::text
+    )
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    )
+ORDER BY
+    ma.created_at DESC
+LIMIT
+    sqlc.arg(limit_count)::int;
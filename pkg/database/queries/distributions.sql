@@ -55,7 +55,8 @@ SELECT
 FROM
   current_odds co
 WHERE
-  co.event_id = sqlc.arg(event_id)
+  co.bookmaker = 'iddaa'
+  AND co.event_id = sqlc.arg(event_id)
   AND co.outcome = sqlc.arg(outcome);
 
 -- name: RefreshContrarianBets :exec
@@ -120,21 +121,23 @@ INSERT INTO outcome_distributions (
   market_id,
   outcome,
   bet_percentage,
-  implied_probability
+  implied_probability,
+  last_updated
 )
 SELECT 
   e.id,
   i.market_id,
   i.outcome,
   i.bet_percentage,
-  i.implied_probability
+  i.implied_probability,
+  COALESCE(sqlc.narg(recorded_at)::timestamp, CURRENT_TIMESTAMP)
 FROM input_data i
 JOIN events e ON e.external_id = i.external_id
 ON CONFLICT (event_id, market_id, outcome) DO UPDATE
 SET 
   bet_percentage = EXCLUDED.bet_percentage,
   implied_probability = EXCLUDED.implied_probability,
-  last_updated = CURRENT_TIMESTAMP;
+  last_updated = EXCLUDED.last_updated;
 
 -- name: BulkInsertDistributionHistory :execrows
 -- Bulk insert distribution history for changed values
@@ -151,14 +154,16 @@ INSERT INTO outcome_distribution_history (
   market_id,
   outcome,
   bet_percentage,
-  previous_percentage
+  previous_percentage,
+  recorded_at
 )
 SELECT
   e.id,
   i.market_id,
   i.outcome,
   i.bet_percentage,
-  i.previous_percentage
+  i.previous_percentage,
+  COALESCE(sqlc.narg(recorded_at)::timestamp, CURRENT_TIMESTAMP)
 FROM input_data i
 JOIN events e ON e.external_id = i.external_id;
 
@@ -171,5 +176,6 @@ SELECT
   e.external_id
 FROM current_odds co
 JOIN events e ON e.id = co.event_id
-WHERE e.external_id = ANY(sqlc.arg(external_ids)::text[])
+WHERE co.bookmaker = 'iddaa'
+  AND e.external_id = ANY(sqlc.arg(external_ids)::text[])
   AND co.market_type_id = 1; -- Match Result market for simplicity
\ No newline at end of file
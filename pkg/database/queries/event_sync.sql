@@ -0,0 +1,58 @@
+-- name: GetEventSyncVersion :one
+SELECT
+    *
+FROM
+    event_sync_versions
+WHERE
+    sport_id = sqlc.arg(sport_id);
+
+-- name: ListEventSyncVersions :many
+SELECT
+    *
+FROM
+    event_sync_versions
+ORDER BY
+    sport_id;
+
+-- name: UpsertEventSyncVersion :one
+-- Records the bulletin version applied for a sport, full syncs reset the diff counter
+INSERT INTO
+    event_sync_versions (
+        sport_id,
+        version,
+        last_synced_at,
+        last_full_sync_at,
+        diffs_since_full
+    )
+VALUES
+    (
+        sqlc.arg(sport_id),
+        sqlc.arg(version),
+        CURRENT_TIMESTAMP,
+        CASE
+            WHEN sqlc.arg(is_full)::boolean THEN CURRENT_TIMESTAMP
+        END,
+        CASE
+            WHEN sqlc.arg(is_full)::boolean THEN 0
+            ELSE 1
+        END
+    ) ON CONFLICT (sport_id) DO
+UPDATE
+SET
+    version = EXCLUDED.version,
+    last_synced_at = EXCLUDED.last_synced_at,
+    last_full_sync_at = CASE
+        WHEN sqlc.arg(is_full)::boolean THEN EXCLUDED.last_full_sync_at
+        ELSE event_sync_versions.last_full_sync_at
+    END,
+    diffs_since_full = CASE
+        WHEN sqlc.arg(is_full)::boolean THEN 0
+        ELSE event_sync_versions.diffs_since_full + 1
+    END RETURNING *;
+
+-- name: ResetEventSyncVersion :execrows
+-- Forces the next events sync for a sport to fetch the full bulletin
+DELETE FROM
+    event_sync_versions
+WHERE
+    sport_id = sqlc.arg(sport_id);
@@ -42,6 +42,26 @@ FROM
 WHERE
   external_id = sqlc.arg(external_id)::text;
 
+-- name: GetEventBySlug :one
+SELECT
+  e.*,
+  ht.name as home_team_name,
+  ht.country as home_team_country,
+  at.name as away_team_name,
+  at.country as away_team_country,
+  l.name as league_name,
+  l.country as league_country,
+  s.name as sport_name,
+  s.code as sport_code
+FROM
+  events e
+  JOIN teams ht ON e.home_team_id = ht.id
+  JOIN teams at ON e.away_team_id = at.id
+  JOIN leagues l ON e.league_id = l.id
+  JOIN sports s ON e.sport_id = s.id
+WHERE
+  e.slug = sqlc.arg(slug)::text;
+
 -- name: ListEventsByDate :many
 SELECT
   e.*,
@@ -0,0 +1,151 @@
+-- Cron job controls and manual triggers
+-- name: RegisterJobControls :exec
+-- Registers the jobs of a cron instance, keeping their pause state and schedule override
+INSERT INTO
+    job_controls (job_name, default_schedule)
+SELECT
+    job_name,
+    default_schedule
+FROM
+    unnest(
+        sqlc.arg(job_names)::text[],
+        sqlc.arg(default_schedules)::text[]
+    ) AS t(job_name, default_schedule) ON CONFLICT (job_name) DO
+UPDATE
+SET
+    default_schedule = EXCLUDED.default_schedule,
+    registered_at = CURRENT_TIMESTAMP;
+
+-- name: ListJobControls :many
+SELECT
+    *
+FROM
+    job_controls
+ORDER BY
+    job_name;
+
+-- name: GetJobControl :one
+SELECT
+    *
+FROM
+    job_controls
+WHERE
+    job_name = sqlc.arg(job_name)::text;
+
+-- name: SetJobPaused :one
+UPDATE
+    job_controls
+SET
+    paused = sqlc.arg(paused)::boolean,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    job_name = sqlc.arg(job_name)::text RETURNING *;
+
+-- name: SetJobScheduleOverride :one
+-- Sets the schedule of a job, NULL restores its default
+UPDATE
+    job_controls
+SET
+    schedule_override = sqlc.narg(schedule_override)::text,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    job_name = sqlc.arg(job_name)::text RETURNING *;
+
+-- name: CreateJobTrigger :one
+-- Queues a manual run unless one is already pending for the job
+INSERT INTO
+    job_triggers (job_name)
+SELECT
+    sqlc.arg(job_name)::text
+WHERE
+    NOT EXISTS (
+        SELECT
+            1
+        FROM
+            job_triggers
+        WHERE
+            job_name = sqlc.arg(job_name)::text
+            AND status = 'pending'
+    ) RETURNING *;
+
+-- name: ClaimJobTriggers :many
+-- Claims the oldest pending triggers of the given jobs for one cron instance
+UPDATE
+    job_triggers
+SET
+    status = 'running',
+    claimed_by = sqlc.arg(instance_id)::text,
+    claimed_at = CURRENT_TIMESTAMP
+WHERE
+    id IN (
+        SELECT
+            id
+        FROM
+            job_triggers
+        WHERE
+            status = 'pending'
+            AND job_name = ANY(sqlc.arg(job_names)::text[])
+        ORDER BY
+            requested_at,
+            id
+        LIMIT
+            sqlc.arg(limit_count)::int FOR
+        UPDATE
+            SKIP LOCKED
+    ) RETURNING *;
+
+-- name: FinishJobTrigger :exec
+UPDATE
+    job_triggers
+SET
+    status = sqlc.arg(status)::text,
+    run_id = sqlc.narg(run_id)::bigint,
+    error = sqlc.narg(error)::text,
+    finished_at = CURRENT_TIMESTAMP
+WHERE
+    id = sqlc.arg(id)::bigint;
+
+-- name: ExpireJobTriggers :execrows
+-- Expires triggers no cron instance claimed within an hour and fails runs whose instance stopped
+UPDATE
+    job_triggers
+SET
+    status = CASE
+        WHEN status = 'pending' THEN 'expired'
+        ELSE 'failed'
+    END,
+    error = CASE
+        WHEN status = 'pending' THEN 'no cron instance claimed the trigger'
+        ELSE 'run did not finish, its process stopped'
+    END,
+    finished_at = CURRENT_TIMESTAMP
+WHERE
+    (
+        status = 'pending'
+        AND requested_at < CURRENT_TIMESTAMP - INTERVAL '1 hour'
+    )
+    OR (
+        status = 'running'
+        AND claimed_at < CURRENT_TIMESTAMP - INTERVAL '2 hours'
+    );
+
+-- name: ListJobTriggers :many
+-- Manual triggers of one job, newest first
+SELECT
+    *
+FROM
+    job_triggers
+WHERE
+    job_name = sqlc.arg(job_name)::text
+ORDER BY
+    requested_at DESC,
+    id DESC
+LIMIT
+    sqlc.arg(limit_count)::int;
+
+-- name: DeleteOldJobTriggers :execrows
+DELETE FROM
+    job_triggers
+WHERE
+    status NOT IN ('pending', 'running')
+    AND requested_at < CURRENT_TIMESTAMP - sqlc.arg(retention_days)::int * INTERVAL '1 day';
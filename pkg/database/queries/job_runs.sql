@@ -0,0 +1,150 @@
+-- Cron job run history
+-- name: CreateJobRun :one
+INSERT INTO
+    job_runs (job_name, instance_id, trigger)
+VALUES
+    (
+        sqlc.arg(job_name)::text,
+        sqlc.arg(instance_id)::text,
+        sqlc.arg(trigger)::text
+    ) RETURNING id;
+
+-- name: FinishJobRun :exec
+UPDATE
+    job_runs
+SET
+    status = sqlc.arg(status)::text,
+    lock_outcome = sqlc.narg(lock_outcome)::text,
+    items_processed = sqlc.narg(items_processed)::int,
+    error = sqlc.narg(error)::text,
+    finished_at = CURRENT_TIMESTAMP
+WHERE
+    id = sqlc.arg(id)::bigint;
+
+-- name: AbandonStaleJobRuns :execrows
+-- Closes runs still marked running long after any job timeout, their process died mid-run
+UPDATE
+    job_runs
+SET
+    status = 'abandoned',
+    finished_at = CURRENT_TIMESTAMP,
+    error = 'run did not finish, its process stopped'
+WHERE
+    status = 'running'
+    AND started_at < CURRENT_TIMESTAMP - sqlc.arg(older_than_minutes)::int * INTERVAL '1 minute';
+
+-- name: DeleteOldJobRuns :execrows
+DELETE FROM
+    job_runs
+WHERE
+    started_at < CURRENT_TIMESTAMP - sqlc.arg(retention_days)::int * INTERVAL '1 day';
+
+-- name: ListJobRunSummaries :many
+-- Latest run of every job that ever ran, with its last success and the failures since
+WITH latest AS (
+    SELECT
+        DISTINCT ON (job_name) id,
+        job_name,
+        instance_id,
+        trigger,
+        status,
+        lock_outcome,
+        started_at,
+        finished_at,
+        items_processed,
+        error
+    FROM
+        job_runs
+    ORDER BY
+        job_name,
+        started_at DESC,
+        id DESC
+),
+last_success AS (
+    SELECT
+        job_name,
+        MAX(started_at) as last_success_at
+    FROM
+        job_runs
+    WHERE
+        status = 'succeeded'
+    GROUP BY
+        job_name
+),
+recent AS (
+    SELECT
+        job_name,
+        COUNT(*) as runs_24h,
+        COUNT(*) FILTER (
+            WHERE
+                status = 'failed'
+        ) as failures_24h
+    FROM
+        job_runs
+    WHERE
+        started_at >= CURRENT_TIMESTAMP - INTERVAL '24 hours'
+    GROUP BY
+        job_name
+)
+SELECT
+    l.job_name,
+    l.id as last_run_id,
+    l.instance_id,
+    l.trigger,
+    l.status,
+    l.lock_outcome,
+    l.started_at,
+    l.finished_at,
+    (EXTRACT(EPOCH FROM (l.finished_at - l.started_at)) * 1000)::bigint as duration_ms,
+    l.items_processed,
+    l.error,
+    ls.last_success_at,
+    COALESCE(r.runs_24h, 0)::int as runs_24h,
+    COALESCE(r.failures_24h, 0)::int as failures_24h,
+    fs.failures_since_success,
+    fs.failing_since
+FROM
+    latest l
+    LEFT JOIN last_success ls ON ls.job_name = l.job_name
+    LEFT JOIN recent r ON r.job_name = l.job_name
+    CROSS JOIN LATERAL (
+        SELECT
+            COUNT(*)::int as failures_since_success,
+            MIN(f.started_at) as failing_since
+        FROM
+            job_runs f
+        WHERE
+            f.job_name = l.job_name
+            AND f.status = 'failed'
+            AND f.started_at > COALESCE(ls.last_success_at, '-infinity'::timestamp)
+    ) fs
+ORDER BY
+    l.job_name;
+
+-- name: ListJobRuns :many
+-- Runs of one job, newest first, optionally of one status
+SELECT
+    id,
+    job_name,
+    instance_id,
+    trigger,
+    status,
+    lock_outcome,
+    started_at,
+    finished_at,
+    (EXTRACT(EPOCH FROM (finished_at - started_at)) * 1000)::bigint as duration_ms,
+    items_processed,
+    error
+FROM
+    job_runs
+WHERE
+    job_name = sqlc.arg(job_name)::text
+    AND (
+        sqlc.narg(status)::text IS NULL
+        OR status = sqlc.narg(status)::text
+    )
+ORDER BY
+    started_at DESC,
+    id DESC
+LIMIT
+    sqlc.arg(limit_count)::int;
@@ -0,0 +1,83 @@
+-- name: BulkInsertLiveOddsHistory :exec
+-- Same as BulkInsertOddsHistory for in-play events, stamped with the score and minute stored on the event
+WITH input_data AS (
+    SELECT
+        unnest(sqlc.arg(event_ids)::int[]) as event_id,
+        unnest(sqlc.arg(market_type_ids)::int[]) as market_type_id,
+        unnest(sqlc.arg(outcomes)::text[]) as outcome,
+        unnest(sqlc.arg(odds_values)::float8[]) as odds_value,
+        unnest(sqlc.arg(previous_values)::float8[]) as previous_value,
+        unnest(sqlc.arg(change_amounts)::float8[]) as change_amount,
+        unnest(sqlc.arg(change_percentages)::float8[]) as change_percentage,
+        unnest(sqlc.arg(multipliers)::float8[]) as multiplier,
+        unnest(sqlc.arg(is_reverse_movements)::boolean[]) as is_reverse_movement,
+        unnest(sqlc.arg(significance_levels)::text[]) as significance_level,
+        unnest(sqlc.arg(minutes_to_kickoffs)::int[]) as minutes_to_kickoff,
+        unnest(sqlc.arg(market_params)::jsonb[]) as market_params
+)
+INSERT INTO
+    odds_history (
+        event_id,
+        market_type_id,
+        outcome,
+        odds_value,
+        previous_value,
+        change_amount,
+        change_percentage,
+        multiplier,
+        is_reverse_movement,
+        significance_level,
+        minutes_to_kickoff,
+        market_params,
+        recorded_at,
+        in_play,
+        live_home_score,
+        live_away_score,
+        live_minute
+    )
+SELECT
+    i.event_id,
+    i.market_type_id,
+    i.outcome,
+    i.odds_value,
+    i.previous_value,
+    i.change_amount,
+    i.change_percentage,
+    i.multiplier,
+    i.is_reverse_movement,
+    i.significance_level,
+    i.minutes_to_kickoff,
+    i.market_params,
+    COALESCE(sqlc.narg(recorded_at)::timestamp, NOW()),
+    TRUE,
+    e.home_score,
+    e.away_score,
+    e.minute_of_match
+FROM
+    input_data i
+    JOIN events e ON e.id = i.event_id;
+
+-- name: BulkSetOddsSuspended :many
+-- Suspends or resumes outcomes, returning the new state of each outcome that changed
+WITH input_data AS (
+    SELECT
+        unnest(sqlc.arg(event_ids)::int[]) as event_id,
+        unnest(sqlc.arg(market_type_ids)::int[]) as market_type_id,
+        unnest(sqlc.arg(outcomes)::text[]) as outcome,
+        unnest(sqlc.arg(suspended)::boolean[]) as suspended
+)
+UPDATE
+    current_odds co
+SET
+    is_suspended = i.suspended,
+    suspended_at = CASE
+        WHEN i.suspended THEN COALESCE(sqlc.narg(recorded_at)::timestamp, NOW())
+    END
+FROM
+    input_data i
+WHERE
+    co.event_id = i.event_id
+    AND co.market_type_id = i.market_type_id
+    AND co.outcome = i.outcome
+    AND co.bookmaker = 'iddaa'
+    AND co.is_suspended <> i.suspended RETURNING co.is_suspended;
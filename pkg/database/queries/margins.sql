@@ -0,0 +1,338 @@
+-- Bookmaker margins and fair probabilities per market
+-- name: GetOddsForMargins :many
+-- Current prices of every scheduled or live event with a price change since the given time
+SELECT
+    co.event_id,
+    co.market_type_id,
+    co.bookmaker,
+    co.outcome,
+    co.odds_value,
+    co.market_params,
+    co.is_suspended
+FROM
+    current_odds co
+    JOIN bookmakers b ON b.code = co.bookmaker
+WHERE
+    b.is_active
+    AND co.event_id IN (
+        SELECT
+            changed.event_id
+        FROM
+            current_odds changed
+            JOIN events e ON e.id = changed.event_id
+        WHERE
+            changed.last_updated >= sqlc.arg(since_time)::timestamp
+            AND e.status IN ('scheduled', 'live')
+    )
+ORDER BY
+    co.event_id,
+    co.market_type_id,
+    co.bookmaker;
+
+-- name: BulkUpsertMarketMargins :execrows
+-- Upserts market margins and records a history row for every margin that is new or moved
+WITH input_data AS (
+    SELECT
+        unnest(sqlc.arg(event_ids)::int[]) as event_id,
+        unnest(sqlc.arg(market_type_ids)::int[]) as market_type_id,
+        unnest(sqlc.arg(bookmakers)::text[]) as bookmaker,
+        unnest(sqlc.arg(market_keys)::text[]) as market_key,
+        unnest(sqlc.arg(outcome_counts)::int[]) as outcome_count,
+        unnest(sqlc.arg(booksums)::float8[]) as booksum,
+        unnest(sqlc.arg(margin_percentages)::float8[]) as margin_percentage,
+        unnest(sqlc.arg(power_ks)::float8[]) as power_k,
+        unnest(sqlc.arg(shin_zs)::float8[]) as shin_z
+),
+previous AS (
+    SELECT
+        mm.event_id,
+        mm.market_type_id,
+        mm.bookmaker,
+        mm.market_key,
+        mm.margin_percentage
+    FROM
+        market_margins mm
+        JOIN input_data i ON mm.event_id = i.event_id
+        AND mm.market_type_id = i.market_type_id
+        AND mm.bookmaker = i.bookmaker
+        AND mm.market_key = i.market_key
+),
+upserted AS (
+    INSERT INTO
+        market_margins (
+            event_id,
+            market_type_id,
+            bookmaker,
+            market_key,
+            outcome_count,
+            booksum,
+            margin_percentage,
+            power_k,
+            shin_z,
+            calculated_at
+        )
+    SELECT
+        event_id,
+        market_type_id,
+        bookmaker,
+        market_key,
+        outcome_count,
+        booksum,
+        margin_percentage,
+        -- NaN marks a method without a solution
+        NULLIF(power_k, 'NaN'::float8),
+        NULLIF(shin_z, 'NaN'::float8),
+        COALESCE(sqlc.narg(recorded_at)::timestamp, NOW())
+    FROM
+        input_data ON CONFLICT (event_id, market_type_id, bookmaker, market_key) DO
+    UPDATE
+    SET
+        outcome_count = EXCLUDED.outcome_count,
+        booksum = EXCLUDED.booksum,
+        margin_percentage = EXCLUDED.margin_percentage,
+        power_k = EXCLUDED.power_k,
+        shin_z = EXCLUDED.shin_z,
+        calculated_at = EXCLUDED.calculated_at RETURNING event_id,
+        market_type_id,
+        bookmaker,
+        market_key,
+        outcome_count,
+        booksum,
+        margin_percentage,
+        calculated_at
+)
+INSERT INTO
+    market_margin_history (
+        event_id,
+        market_type_id,
+        bookmaker,
+        market_key,
+        outcome_count,
+        booksum,
+        margin_percentage,
+        previous_margin,
+        recorded_at
+    )
+SELECT
+    u.event_id,
+    u.market_type_id,
+    u.bookmaker,
+    u.market_key,
+    u.outcome_count,
+    u.booksum,
+    u.margin_percentage,
+    p.margin_percentage,
+    u.calculated_at
+FROM
+    upserted u
+    LEFT JOIN previous p ON p.event_id = u.event_id
+    AND p.market_type_id = u.market_type_id
+    AND p.bookmaker = u.bookmaker
+    AND p.market_key = u.market_key
+WHERE
+    p.margin_percentage IS NULL
+    OR ABS(p.margin_percentage - u.margin_percentage) >= 0.01;
+
+-- name: BulkUpsertFairProbabilities :exec
+WITH input_data AS (
+    SELECT
+        unnest(sqlc.arg(event_ids)::int[]) as event_id,
+        unnest(sqlc.arg(market_type_ids)::int[]) as market_type_id,
+        unnest(sqlc.arg(bookmakers)::text[]) as bookmaker,
+        unnest(sqlc.arg(market_keys)::text[]) as market_key,
+        unnest(sqlc.arg(outcomes)::text[]) as outcome,
+        unnest(sqlc.arg(odds_values)::float8[]) as odds_value,
+        unnest(sqlc.arg(implied_probabilities)::float8[]) as implied_probability,
+        unnest(sqlc.arg(multiplicative)::float8[]) as multiplicative,
+        unnest(sqlc.arg(additive)::float8[]) as additive,
+        unnest(sqlc.arg(power)::float8[]) as power,
+        unnest(sqlc.arg(shin)::float8[]) as shin,
+        unnest(sqlc.arg(fair_odds)::float8[]) as fair_odds
+)
+INSERT INTO
+    fair_probabilities (
+        event_id,
+        market_type_id,
+        bookmaker,
+        market_key,
+        outcome,
+        odds_value,
+        implied_probability,
+        multiplicative,
+        additive,
+        power,
+        shin,
+        fair_odds,
+        calculated_at
+    )
+SELECT
+    event_id,
+    market_type_id,
+    bookmaker,
+    market_key,
+    outcome,
+    odds_value,
+    implied_probability,
+    multiplicative,
+    NULLIF(additive, 'NaN'::float8),
+    NULLIF(power, 'NaN'::float8),
+    NULLIF(shin, 'NaN'::float8),
+    fair_odds,
+    COALESCE(sqlc.narg(recorded_at)::timestamp, NOW())
+FROM
+    input_data ON CONFLICT (event_id, market_type_id, outcome, bookmaker) DO
+UPDATE
+SET
+    market_key = EXCLUDED.market_key,
+    odds_value = EXCLUDED.odds_value,
+    implied_probability = EXCLUDED.implied_probability,
+    multiplicative = EXCLUDED.multiplicative,
+    additive = EXCLUDED.additive,
+    power = EXCLUDED.power,
+    shin = EXCLUDED.shin,
+    fair_odds = EXCLUDED.fair_odds,
+    calculated_at = EXCLUDED.calculated_at;
+
+-- name: GetEventMarketMargins :many
+SELECT
+    mm.market_type_id,
+    mt.code as market_code,
+    mt.name as market_name,
+    mm.market_key,
+    mm.outcome_count,
+    mm.booksum,
+    mm.margin_percentage,
+    mm.power_k,
+    mm.shin_z,
+    mm.calculated_at
+FROM
+    market_margins mm
+    JOIN market_types mt ON mm.market_type_id = mt.id
+WHERE
+    mm.event_id = sqlc.arg(event_id)::int
+    AND mm.bookmaker = sqlc.arg(bookmaker)::text
+ORDER BY
+    mm.market_type_id,
+    mm.market_key;
+
+-- name: GetEventFairProbabilities :many
+SELECT
+    fp.market_type_id,
+    fp.market_key,
+    fp.outcome,
+    fp.odds_value,
+    fp.implied_probability,
+    fp.multiplicative,
+    fp.additive,
+    fp.power,
+    fp.shin,
+    fp.fair_odds
+FROM
+    fair_probabilities fp
+WHERE
+    fp.event_id = sqlc.arg(event_id)::int
+    AND fp.bookmaker = sqlc.arg(bookmaker)::text
+ORDER BY
+    fp.market_type_id,
+    fp.market_key,
+    fp.odds_value;
+
+-- name: GetEventMarginHistory :many
+SELECT
+    mh.market_type_id,
+    mh.market_key,
+    mh.margin_percentage,
+    mh.recorded_at
+FROM
+    market_margin_history mh
+WHERE
+    mh.event_id = sqlc.arg(event_id)::int
+    AND mh.bookmaker = sqlc.arg(bookmaker)::text
+ORDER BY
+    mh.market_type_id,
+    mh.market_key,
+    mh.recorded_at;
+
+-- name: GetLeagueMarketMargins :many
+-- Margin statistics per league and market type for events kicking off since the given time
+SELECT
+    l.name as league_name,
+    s.code as sport_code,
+    mt.code as market_code,
+    mt.name as market_name,
+    COUNT(DISTINCT mm.event_id)::int as event_count,
+    COUNT(*)::int as market_count,
+    AVG(mm.margin_percentage)::float8 as avg_margin,
+    percentile_cont(0.5) WITHIN GROUP (
+        ORDER BY
+            mm.margin_percentage
+    )::float8 as median_margin,
+    MIN(mm.margin_percentage)::float8 as min_margin,
+    MAX(mm.margin_percentage)::float8 as max_margin
+FROM
+    market_margins mm
+    JOIN events e ON mm.event_id = e.id
+    JOIN leagues l ON e.league_id = l.id
+    JOIN sports s ON e.sport_id = s.id
+    JOIN market_types mt ON mm.market_type_id = mt.id
+WHERE
+    mm.bookmaker = sqlc.arg(bookmaker)::text
+    AND e.event_date >= sqlc.arg(since_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    )
+    AND (
+        sqlc.arg(league_name)::text = ''
+        OR l.name ILIKE '%' || sqlc.arg(league_name)::text || '%'
+    )
+    AND (
+        sqlc.arg(market_code)::text = ''
+        OR mt.code = sqlc.arg(market_code)::text
+    )
+GROUP BY
+    l.id,
+    l.name,
+    s.code,
+    mt.id,
+    mt.code,
+    mt.name
+ORDER BY
+    COUNT(*) DESC,
+    l.name,
+    mt.code
+LIMIT
+    sqlc.arg(limit_count)::int OFFSET sqlc.arg(offset_count)::int;
+
+-- name: CountLeagueMarketMargins :one
+SELECT
+    COUNT(*)::int
+FROM
+    (
+        SELECT
+            1
+        FROM
+            market_margins mm
+            JOIN events e ON mm.event_id = e.id
+            JOIN leagues l ON e.league_id = l.id
+            JOIN sports s ON e.sport_id = s.id
+            JOIN market_types mt ON mm.market_type_id = mt.id
+        WHERE
+            mm.bookmaker = sqlc.arg(bookmaker)::text
+            AND e.event_date >= sqlc.arg(since_time)::timestamp
+            AND (
+                sqlc.arg(sport_code)::text = ''
+                OR s.code = sqlc.arg(sport_code)::text
+            )
+            AND (
+                sqlc.arg(league_name)::text = ''
+                OR l.name ILIKE '%' || sqlc.arg(league_name)::text || '%'
+            )
+            AND (
+                sqlc.arg(market_code)::text = ''
+                OR mt.code = sqlc.arg(market_code)::text
+            )
+        GROUP BY
+            l.id,
+            mt.id
+    ) grouped;
@@ -7,7 +7,8 @@ FROM
     current_odds co
     JOIN market_types mt ON co.market_type_id = mt.id
 WHERE
-    co.event_id = sqlc.arg(event_id)::int;
+    co.bookmaker = 'iddaa'
+    AND co.event_id = sqlc.arg(event_id)::int;
 
 -- name: GetCurrentOddsByMarket :many
 SELECT
@@ -18,7 +19,8 @@ FROM
     current_odds co
     JOIN market_types mt ON co.market_type_id = mt.id
 WHERE
-    co.event_id = sqlc.arg(event_id)::int
+    co.bookmaker = 'iddaa'
+    AND co.event_id = sqlc.arg(event_id)::int
     AND co.market_type_id = sqlc.arg(market_type_id)::int;
 
 -- name: GetCurrentOddsByOutcome :one
@@ -30,7 +32,8 @@ FROM
     current_odds co
     JOIN market_types mt ON co.market_type_id = mt.id
 WHERE
-    co.event_id = sqlc.arg(event_id)::int
+    co.bookmaker = 'iddaa'
+    AND co.event_id = sqlc.arg(event_id)::int
     AND co.market_type_id = sqlc.arg(market_type_id)::int
     AND co.outcome = sqlc.arg(outcome)::text;
 
@@ -64,7 +67,7 @@ VALUES
         0,
         -- First time, no movement percentage
         sqlc.arg(market_params)::jsonb
-    ) ON CONFLICT (event_id, market_type_id, outcome) DO
+    ) ON CONFLICT (event_id, market_type_id, outcome, bookmaker) DO
 UPDATE
 SET
     odds_value = EXCLUDED.odds_value,
@@ -139,7 +142,8 @@ FROM
     odds_history oh
     JOIN market_types mt ON oh.market_type_id = mt.id
 WHERE
-    oh.event_id = sqlc.arg(event_id)::int
+    oh.bookmaker = 'iddaa'
+    AND oh.event_id = sqlc.arg(event_id)::int
 ORDER BY
     oh.recorded_at DESC
 LIMIT
@@ -163,7 +167,8 @@ FROM
     JOIN events e ON oh.event_id = e.id
     JOIN market_types mt ON oh.market_type_id = mt.id
 WHERE
-    ABS(oh.change_percentage) > sqlc.arg(min_change_pct)::float8
+    oh.bookmaker = 'iddaa'
+    AND ABS(oh.change_percentage) > sqlc.arg(min_change_pct)::float8
     AND oh.recorded_at > sqlc.arg(since_time)::timestamp
 ORDER BY
     ABS(oh.change_percentage) DESC
@@ -182,7 +187,8 @@ FROM
     JOIN events e ON oh.event_id = e.id
     JOIN market_types mt ON oh.market_type_id = mt.id
 WHERE
-    oh.recorded_at >= sqlc.arg(since_time)::timestamp
+    oh.bookmaker = 'iddaa'
+    AND oh.recorded_at >= sqlc.arg(since_time)::timestamp
     AND e.event_date > NOW()
     AND ABS(oh.change_percentage) >= sqlc.arg(min_change_pct)::float8
 ORDER BY
@@ -196,7 +202,8 @@ SELECT
 FROM
     current_odds
 WHERE
-    event_id = sqlc.arg(event_id)::int
+    bookmaker = 'iddaa'
+    AND event_id = sqlc.arg(event_id)::int
     AND market_type_id = ANY(sqlc.arg(market_type_ids)::int)
     AND outcome = ANY(sqlc.arg(outcomes)::text[]);
 
@@ -230,19 +237,24 @@ SELECT
     odds_value,
     odds_value,
     market_params,
-    NOW()
+    COALESCE(sqlc.narg(recorded_at)::timestamp, NOW())
 FROM
-    input_data ON CONFLICT (event_id, market_type_id, outcome) DO
+    input_data ON CONFLICT (event_id, market_type_id, outcome, bookmaker) DO
 UPDATE
 SET
     odds_value = EXCLUDED.odds_value,
     highest_value = GREATEST(current_odds.highest_value, EXCLUDED.odds_value),
     lowest_value = LEAST(current_odds.lowest_value, EXCLUDED.odds_value),
-    last_updated = NOW()
+    last_updated = EXCLUDED.last_updated
 WHERE
     current_odds.odds_value IS DISTINCT
 FROM
-    EXCLUDED.odds_value;
+    EXCLUDED.odds_value
+    -- Replayed payloads never overwrite newer prices
+    AND (
+        current_odds.last_updated IS NULL
+        OR current_odds.last_updated <= EXCLUDED.last_updated
+    );
 
 -- name: BulkInsertOddsHistory :exec
 WITH input_data AS (
@@ -289,7 +301,7 @@ SELECT
     significance_level,
     minutes_to_kickoff,
     market_params,
-    NOW()
+    COALESCE(sqlc.narg(recorded_at)::timestamp, NOW())
 FROM
     input_data;
 
@@ -308,7 +320,8 @@ FROM
     current_odds co
     JOIN events e ON e.id = co.event_id
 WHERE
-    (co.event_id, co.market_type_id, co.outcome) IN (
+    co.bookmaker = 'iddaa'
+    AND (co.event_id, co.market_type_id, co.outcome) IN (
         SELECT
             unnest(sqlc.arg(event_ids)::int[]),
             unnest(sqlc.arg(market_type_ids)::int[]),
@@ -0,0 +1,47 @@
+-- Current odds cache queries
+-- name: NextCurrentOddsVersion :one
+-- Marks a committed write of Iddaa current odds
+SELECT
+    nextval('current_odds_version_seq')::bigint as version;
+
+-- name: GetCurrentOddsVersion :one
+-- Version of the latest write, 0 before the first one
+SELECT
+    (
+        CASE
+            WHEN is_called THEN last_value
+            ELSE 0
+        END
+    )::bigint as version
+FROM
+    current_odds_version_seq;
+
+-- name: ListCurrentOddsForCache :many
+-- Iddaa current odds of events starting from since_time on
+SELECT
+    co.event_id,
+    co.market_type_id,
+    co.outcome,
+    co.odds_value,
+    e.event_date
+FROM
+    current_odds co
+    JOIN events e ON e.id = co.event_id
+WHERE
+    co.bookmaker = 'iddaa'
+    AND e.event_date >= sqlc.arg(since_time)::timestamp;
+
+-- name: GetCurrentOddsForCacheByEvents :many
+-- Iddaa current odds of the given events, same shape as ListCurrentOddsForCache
+SELECT
+    co.event_id,
+    co.market_type_id,
+    co.outcome,
+    co.odds_value,
+    e.event_date
+FROM
+    current_odds co
+    JOIN events e ON e.id = co.event_id
+WHERE
+    co.bookmaker = 'iddaa'
+    AND co.event_id = ANY(sqlc.arg(event_ids)::int[]);
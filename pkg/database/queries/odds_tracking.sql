@@ -6,7 +6,8 @@ SELECT
     mt.name as market_name
 FROM odds_history oh
 JOIN market_types mt ON oh.market_type_id = mt.id
-WHERE oh.event_id = sqlc.arg(event_id)
+WHERE oh.bookmaker = 'iddaa'
+AND oh.event_id = sqlc.arg(event_id)
 ORDER BY oh.market_type_id, oh.outcome, oh.recorded_at DESC;
 
 -- name: GetOddsChangesByMarket :many
@@ -17,7 +18,8 @@ SELECT
     mt.name as market_name
 FROM odds_history oh
 JOIN market_types mt ON oh.market_type_id = mt.id
-WHERE oh.event_id = sqlc.arg(event_id)
+WHERE oh.bookmaker = 'iddaa'
+AND oh.event_id = sqlc.arg(event_id)
 AND oh.market_type_id = sqlc.arg(market_type_id)
 AND ABS(oh.change_percentage) > sqlc.arg(min_change_percentage)::float8
 ORDER BY oh.recorded_at DESC;
@@ -52,24 +54,80 @@ JOIN teams at ON e.away_team_id = at.id
 JOIN market_types mt ON oh.market_type_id = mt.id
 JOIN leagues l ON e.league_id = l.id
 JOIN sports s ON e.sport_id = s.id
-WHERE oh.recorded_at > sqlc.arg(since_time)
+WHERE oh.bookmaker = 'iddaa'
+AND oh.recorded_at > sqlc.arg(since_time)
 AND ABS(oh.change_percentage) > sqlc.arg(min_change_percentage)::float8
 ORDER BY oh.recorded_at DESC
 LIMIT sqlc.arg(limit_count);
 
 -- name: GetSuspiciousMovements :many
 -- Get potentially suspicious odds movements (sharp money indicators)
-SELECT 
-    oh.*,
+SELECT
+    oh.id,
+    oh.event_id,
+    oh.market_type_id,
+    oh.outcome,
+    oh.odds_value,
+    oh.previous_value,
+    oh.change_percentage,
+    oh.multiplier,
+    oh.sharp_money_indicator,
+    oh.is_reverse_movement,
+    oh.minutes_to_kickoff,
+    oh.recorded_at,
     e.slug as event_slug,
-    mt.code as market_code
-FROM odds_history oh
-JOIN events e ON oh.event_id = e.id
-JOIN market_types mt ON oh.market_type_id = mt.id
-WHERE oh.multiplier > 1.5 OR oh.multiplier < 0.67
-AND oh.recorded_at > sqlc.arg(since_time)
-ORDER BY CASE 
-    WHEN oh.multiplier > 1.0 THEN oh.multiplier
-    ELSE (1.0 / oh.multiplier)
-END DESC
-LIMIT sqlc.arg(limit_count);
\ No newline at end of file
+    e.event_date,
+    (ht.name || ' vs ' || at.name)::text as match_name,
+    s.code as sport_code,
+    l.name as league_name,
+    mt.code as market_code,
+    mt.name as market_name,
+    GREATEST(oh.multiplier, 1.0 / oh.multiplier)::float8 as movement_factor
+FROM
+    odds_history oh
+    JOIN events e ON oh.event_id = e.id
+    JOIN teams ht ON e.home_team_id = ht.id
+    JOIN teams at ON e.away_team_id = at.id
+    JOIN leagues l ON e.league_id = l.id
+    JOIN sports s ON e.sport_id = s.id
+    JOIN market_types mt ON oh.market_type_id = mt.id
+WHERE
+    oh.bookmaker = 'iddaa'
+    AND oh.multiplier > 0
+    AND GREATEST(oh.multiplier, 1.0 / oh.multiplier) >= sqlc.arg(min_factor)::float8
+    AND oh.recorded_at > sqlc.arg(since_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    )
+    AND (
+        sqlc.arg(league_name)::text = ''
+        OR l.name ILIKE '%' || sqlc.arg(league_name)::text || '%'
+    )
+ORDER BY
+    GREATEST(oh.multiplier, 1.0 / oh.multiplier) DESC,
+    oh.id DESC
+LIMIT
+    sqlc.arg(limit_count)::int OFFSET sqlc.arg(offset_count)::int;
+
+-- name: CountSuspiciousMovements :one
+SELECT
+    COUNT(*)::int
+FROM
+    odds_history oh
+    JOIN events e ON oh.event_id = e.id
+    JOIN leagues l ON e.league_id = l.id
+    JOIN sports s ON e.sport_id = s.id
+WHERE
+    oh.bookmaker = 'iddaa'
+    AND oh.multiplier > 0
+    AND GREATEST(oh.multiplier, 1.0 / oh.multiplier) >= sqlc.arg(min_factor)::float8
+    AND oh.recorded_at > sqlc.arg(since_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    )
+    AND (
+        sqlc.arg(league_name)::text = ''
+        OR l.name ILIKE '%' || sqlc.arg(league_name)::text || '%'
+    );
\ No newline at end of file
@@ -0,0 +1,36 @@
+-- Monthly partitions of the history tables
+-- name: CreateHistoryPartitions :one
+-- Creates the missing monthly partitions of a history table covering from_time through to_time
+SELECT
+    create_history_partitions(
+        sqlc.arg(parent_table)::text,
+        sqlc.arg(from_time)::timestamp,
+        sqlc.arg(to_time)::timestamp
+    )::int as created;
+
+-- name: ListHistoryPartitions :many
+-- Partitions of the given history tables with their range, oldest first
+SELECT
+    parent.relname::text as parent_table,
+    child.relname::text as partition_name,
+    substring(
+        pg_get_expr(child.relpartbound, child.oid)
+        FROM
+            'FROM \(''([^'']+)''\)'
+    )::timestamp as range_start,
+    substring(
+        pg_get_expr(child.relpartbound, child.oid)
+        FROM
+            'TO \(''([^'']+)''\)'
+    )::timestamp as range_end
+FROM
+    pg_inherits i
+    JOIN pg_class parent ON parent.oid = i.inhparent
+    JOIN pg_class child ON child.oid = i.inhrelid
+    JOIN pg_namespace n ON n.oid = parent.relnamespace
+WHERE
+    n.nspname = current_schema()
+    AND parent.relname = ANY(sqlc.arg(parent_tables)::text[])
+ORDER BY
+    parent.relname,
+    range_start;
@@ -0,0 +1,141 @@
+-- Archive reprocessing queries
+-- Distribution and volume history recorded within [from_time, to_time) is cleared before the range
+-- is replayed. Odds history is replaced once the replay wrote the new rows: alerts move over to
+-- them, then the rows up to max_id are deleted and the odds_history delete trigger removes the
+-- alerts and CLV rows still referencing them.
+-- name: GetOddsHistoryRangeMaxID :one
+-- Newest Iddaa odds_history id recorded in the range, 0 when empty
+SELECT
+    COALESCE(MAX(id), 0)::int AS max_id
+FROM
+    odds_history
+WHERE
+    bookmaker = 'iddaa'
+    AND recorded_at >= sqlc.arg(from_time)::timestamp
+    AND recorded_at < sqlc.arg(to_time)::timestamp;
+
+-- name: RelinkMovementAlertsRange :execrows
+-- Points the alerts of replaced rows at the replayed row of the same price closest in time, within
+-- 15 minutes. Alerts without a replayed row keep their old row and are deleted with it.
+WITH matches AS (
+    SELECT
+        DISTINCT ON (replayed.id, ma.alert_type) ma.id AS alert_id,
+        replayed.id AS odds_history_id
+    FROM
+        movement_alerts ma
+        JOIN odds_history old ON old.id = ma.odds_history_id
+        JOIN LATERAL (
+            SELECT
+                oh.id
+            FROM
+                odds_history oh
+            WHERE
+                oh.event_id = old.event_id
+                AND oh.market_type_id = old.market_type_id
+                AND oh.outcome = old.outcome
+                AND oh.bookmaker = 'iddaa'
+                AND oh.odds_value = old.odds_value
+                AND oh.id > sqlc.arg(max_id)::int
+                AND oh.recorded_at >= GREATEST(
+                    old.recorded_at - INTERVAL '15 minutes',
+                    sqlc.arg(from_time)::timestamp
+                )
+                AND oh.recorded_at < LEAST(
+                    old.recorded_at + INTERVAL '15 minutes',
+                    sqlc.arg(to_time)::timestamp
+                )
+            ORDER BY
+                ABS(EXTRACT(EPOCH FROM oh.recorded_at - old.recorded_at))
+            LIMIT
+                1
+        ) replayed ON TRUE
+    WHERE
+        old.bookmaker = 'iddaa'
+        AND old.id <= sqlc.arg(max_id)::int
+        AND old.recorded_at >= sqlc.arg(from_time)::timestamp
+        AND old.recorded_at < sqlc.arg(to_time)::timestamp
+    ORDER BY
+        replayed.id,
+        ma.alert_type,
+        ma.created_at
+)
+UPDATE
+    movement_alerts ma
+SET
+    odds_history_id = m.odds_history_id,
+    updated_at = CURRENT_TIMESTAMP
+FROM
+    matches m
+WHERE
+    ma.id = m.alert_id;
+
+-- name: DeleteOddsHistoryRange :execrows
+DELETE FROM odds_history
+WHERE
+    bookmaker = 'iddaa'
+    AND recorded_at >= sqlc.arg(from_time)::timestamp
+    AND recorded_at < sqlc.arg(to_time)::timestamp
+    AND id <= sqlc.arg(max_id)::int;
+
+-- name: GetClosedEventsInHistoryRange :many
+-- Events with a frozen closing line and Iddaa odds history in the range
+SELECT
+    DISTINCT oh.event_id::int AS event_id
+FROM
+    odds_history oh
+WHERE
+    oh.bookmaker = 'iddaa'
+    AND oh.recorded_at >= sqlc.arg(from_time)::timestamp
+    AND oh.recorded_at < sqlc.arg(to_time)::timestamp
+    AND EXISTS (
+        SELECT
+            1
+        FROM
+            closing_odds cl
+        WHERE
+            cl.event_id = oh.event_id
+    );
+
+-- name: DeleteDistributionHistoryRange :execrows
+DELETE FROM outcome_distribution_history
+WHERE
+    recorded_at >= sqlc.arg(from_time)::timestamp
+    AND recorded_at < sqlc.arg(to_time)::timestamp;
+
+-- name: DeleteVolumeHistoryRange :execrows
+DELETE FROM betting_volume_history
+WHERE
+    recorded_at >= sqlc.arg(from_time)::timestamp
+    AND recorded_at < sqlc.arg(to_time)::timestamp;
+
+-- name: GetReplayOddsForComparison :many
+-- Prices of events as they stood before a replayed range: the last odds_history row recorded before
+-- it, or the opening price when the key had not moved yet
+SELECT
+    co.event_id,
+    co.market_type_id,
+    co.outcome,
+    COALESCE(last_change.odds_value, co.opening_value, co.odds_value)::float8 AS odds_value,
+    e.event_date
+FROM
+    current_odds co
+    JOIN events e ON e.id = co.event_id
+    LEFT JOIN LATERAL (
+        SELECT
+            oh.odds_value
+        FROM
+            odds_history oh
+        WHERE
+            oh.event_id = co.event_id
+            AND oh.market_type_id = co.market_type_id
+            AND oh.outcome = co.outcome
+            AND oh.bookmaker = 'iddaa'
+            AND oh.recorded_at < sqlc.arg(before_time)::timestamp
+        ORDER BY
+            oh.recorded_at DESC
+        LIMIT
+            1
+    ) last_change ON TRUE
+WHERE
+    co.bookmaker = 'iddaa'
+    AND co.event_id = ANY(sqlc.arg(event_ids)::int[]);
@@ -0,0 +1,142 @@
+-- Settlement queries
+-- name: UpsertEventResult :exec
+INSERT INTO
+    event_results (
+        event_id,
+        home_score,
+        away_score,
+        ht_home_score,
+        ht_away_score
+    )
+VALUES
+    (
+        sqlc.arg(event_id),
+        sqlc.arg(home_score),
+        sqlc.arg(away_score),
+        sqlc.narg(ht_home_score),
+        sqlc.narg(ht_away_score)
+    ) ON CONFLICT (event_id) DO
+UPDATE
+SET
+    home_score = EXCLUDED.home_score,
+    away_score = EXCLUDED.away_score,
+    ht_home_score = EXCLUDED.ht_home_score,
+    ht_away_score = EXCLUDED.ht_away_score,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    (
+        event_results.home_score,
+        event_results.away_score,
+        event_results.ht_home_score,
+        event_results.ht_away_score
+    ) IS DISTINCT FROM (
+        EXCLUDED.home_score,
+        EXCLUDED.away_score,
+        EXCLUDED.ht_home_score,
+        EXCLUDED.ht_away_score
+    );
+
+-- name: GetEventResultsPendingSettlement :many
+-- Results that were never settled or changed since the last settlement
+SELECT
+    r.*
+FROM
+    event_results r
+WHERE
+    r.settled_at IS NULL
+    OR r.settled_at < r.updated_at
+ORDER BY
+    r.updated_at ASC
+LIMIT
+    sqlc.arg(limit_count)::int;
+
+-- name: MarkEventResultSettled :exec
+UPDATE
+    event_results
+SET
+    settled_at = CURRENT_TIMESTAMP
+WHERE
+    event_id = sqlc.arg(event_id);
+
+-- name: GetSettlementCandidates :many
+SELECT
+    co.market_type_id,
+    mt.name as market_name,
+    co.outcome,
+    co.market_params
+FROM
+    current_odds co
+    JOIN market_types mt ON co.market_type_id = mt.id
+WHERE
+    co.bookmaker = 'iddaa'
+    AND co.event_id = sqlc.arg(event_id)::int;
+
+-- name: BulkUpsertOutcomeSettlements :exec
+WITH input_data AS (
+    SELECT
+        unnest(sqlc.arg(event_ids)::int[]) as event_id,
+        unnest(sqlc.arg(market_type_ids)::int[]) as market_type_id,
+        unnest(sqlc.arg(outcomes)::text[]) as outcome,
+        unnest(sqlc.arg(market_families)::text[]) as market_family,
+        unnest(sqlc.arg(results)::text[]) as result
+)
+INSERT INTO
+    outcome_settlements (
+        event_id,
+        market_type_id,
+        outcome,
+        market_family,
+        result
+    )
+SELECT
+    *
+FROM
+    input_data ON CONFLICT (event_id, market_type_id, outcome) DO
+UPDATE
+SET
+    market_family = EXCLUDED.market_family,
+    result = EXCLUDED.result,
+    settled_at = CURRENT_TIMESTAMP;
+
+-- name: VoidCancelledEventOutcomes :execrows
+-- Voids every outcome of cancelled events that have not been settled yet
+INSERT INTO
+    outcome_settlements (
+        event_id,
+        market_type_id,
+        outcome,
+        market_family,
+        result
+    )
+SELECT
+    co.event_id,
+    co.market_type_id,
+    co.outcome,
+    'cancelled',
+    'void'
+FROM
+    current_odds co
+    JOIN events e ON co.event_id = e.id
+WHERE
+    e.status = 'cancelled'
+    AND co.bookmaker = 'iddaa'
+    AND co.market_type_id IS NOT NULL
+    AND NOT EXISTS (
+        SELECT
+            1
+        FROM
+            outcome_settlements os
+        WHERE
+            os.event_id = e.id
+    ) ON CONFLICT (event_id, market_type_id, outcome) DO NOTHING;
+
+-- name: GetOutcomeSettlementsByEvent :many
+SELECT
+    *
+FROM
+    outcome_settlements
+WHERE
+    event_id = sqlc.arg(event_id)
+ORDER BY
+    market_type_id,
+    outcome;
@@ -18,7 +18,8 @@ FROM
     LEFT JOIN teams at ON e.away_team_id = at.id
     JOIN market_types mt ON oh.market_type_id = mt.id
 WHERE
-    (
+    oh.bookmaker = 'iddaa'
+    AND (
         ABS(oh.change_percentage) >= sqlc.arg(min_change_pct)::float8
         OR oh.multiplier >= sqlc.arg(min_multiplier)::float8
     )
@@ -64,7 +65,8 @@ FROM
         AND oh.outcome = od.outcome
     )
 WHERE
-    oh.recorded_at >= sqlc.arg(since_time)
+    oh.bookmaker = 'iddaa'
+    AND oh.recorded_at >= sqlc.arg(since_time)
     AND e.event_date > NOW()
     AND od.bet_percentage IS NOT NULL
     -- True reverse line movements:
@@ -108,7 +110,8 @@ FROM
         AND oh.outcome = od.outcome
     )
 WHERE
-    oh.recorded_at >= sqlc.arg(since_time)
+    oh.bookmaker = 'iddaa'
+    AND oh.recorded_at >= sqlc.arg(since_time)
     AND e.event_date > NOW()
     AND od.bet_percentage > od.implied_probability + sqlc.arg(min_bias_pct)::float8
     AND ABS(oh.change_percentage) >= sqlc.arg(min_movement_pct)::float8
@@ -215,58 +218,129 @@ ORDER BY
 LIMIT
     sqlc.arg(limit_count);
 
--- COMMENTED OUT: Requires smart_money_preferences table
--- -- name: GetAlertsByUser :many
--- SELECT 
---     ma.*,
---     oh.event_id,
---     oh.outcome,
---     e.external_id as event_external_id,
---     e.event_date,
---     ht.name as home_team_name,
---     at.name as away_team_name,
---     mt.name as market_name
--- FROM movement_alerts ma
--- JOIN odds_history oh ON ma.odds_history_id = oh.id
--- JOIN events e ON oh.event_id = e.id
--- LEFT JOIN teams ht ON e.home_team_id = ht.id
--- LEFT JOIN teams at ON e.away_team_id = at.id
--- JOIN market_types mt ON oh.market_type_id = mt.id
--- JOIN smart_money_preferences smp ON smp.user_id = sqlc.arg(user_id)
--- WHERE 
---     ma.is_active = true
---     AND ma.expires_at > NOW()
---     AND ma.change_percentage >= smp.min_change_percentage
---     AND ma.multiplier >= smp.min_multiplier
---     AND ma.confidence_score >= smp.min_confidence_score
---     AND (
---         (ma.alert_type = 'big_mover' AND smp.big_mover_alerts = true) OR
---         (ma.alert_type = 'reverse_line' AND smp.reverse_line_alerts = true) OR
---         (ma.alert_type = 'sharp_money' AND smp.sharp_money_alerts = true) OR
---         (ma.alert_type = 'value_spot' AND smp.value_spot_alerts = true)
---     )
---     -- Filter by preferred sports/leagues if specified
---     AND (
---         smp.preferred_sports = '[]'::jsonb OR 
---         e.sport_id::text = ANY(SELECT jsonb_array_elements_text(smp.preferred_sports))
---     )
--- ORDER BY ma.created_at DESC
--- LIMIT sqlc.arg(limit_count);
+-- name: GetAlertsByUser :many
+-- Active alerts matching the user's thresholds, alert types and followed sports/leagues
+SELECT
+    ma.*,
+    oh.event_id,
+    oh.outcome,
+    e.external_id as event_external_id,
+    e.slug as event_slug,
+    e.event_date,
+    ht.name as home_team_name,
+    at.name as away_team_name,
+    mt.name as market_name,
+    (uai.view_count IS NOT NULL AND uai.view_count > 0)::boolean as viewed,
+    (uai.click_count IS NOT NULL AND uai.click_count > 0)::boolean as clicked
+FROM
+    movement_alerts ma
+    JOIN odds_history oh ON ma.odds_history_id = oh.id
+    JOIN events e ON oh.event_id = e.id
+    LEFT JOIN teams ht ON e.home_team_id = ht.id
+    LEFT JOIN teams at ON e.away_team_id = at.id
+    JOIN market_types mt ON oh.market_type_id = mt.id
+    JOIN smart_money_preferences smp ON smp.user_id = sqlc.arg(user_id)::int
+    LEFT JOIN user_alert_interactions uai ON (
+        uai.alert_id = ma.id
+        AND uai.user_id = smp.user_id
+    )
+WHERE
+    ma.is_active = true
+    AND ma.expires_at > NOW()
+    AND ABS(ma.change_percentage) >= smp.min_change_percentage
+    AND ma.multiplier >= smp.min_multiplier
+    AND ma.confidence_score >= smp.min_confidence_score
+    AND (
+        (ma.alert_type = 'big_mover' AND smp.big_mover_alerts = true) OR
+        (ma.alert_type = 'reverse_line' AND smp.reverse_line_alerts = true) OR
+        (ma.alert_type = 'sharp_money' AND smp.sharp_money_alerts = true) OR
+        (ma.alert_type = 'value_spot' AND smp.value_spot_alerts = true)
+    )
+    -- Empty arrays follow every sport/league
+    AND (
+        cardinality(smp.preferred_sports) = 0 OR
+        e.sport_id = ANY(smp.preferred_sports)
+    )
+    AND (
+        cardinality(smp.preferred_leagues) = 0 OR
+        e.league_id = ANY(smp.preferred_leagues)
+    )
+ORDER BY
+    ma.created_at DESC
+LIMIT
+    sqlc.arg(limit_count)::int;
+
 -- name: MarkAlertViewed :exec
+-- Records a view for the user, movement_alerts.views counts distinct viewers
+WITH interaction AS (
+    INSERT INTO
+        user_alert_interactions (user_id, alert_id, view_count, first_viewed_at, last_viewed_at)
+    VALUES
+        (
+            sqlc.arg(user_id)::int,
+            sqlc.arg(alert_id)::int,
+            1,
+            CURRENT_TIMESTAMP,
+            CURRENT_TIMESTAMP
+        ) ON CONFLICT (user_id, alert_id) DO
+    UPDATE
+    SET
+        view_count = user_alert_interactions.view_count + 1,
+        first_viewed_at = COALESCE(user_alert_interactions.first_viewed_at, EXCLUDED.first_viewed_at),
+        last_viewed_at = EXCLUDED.last_viewed_at
+)
 UPDATE
     movement_alerts
 SET
     views = views + 1
 WHERE
-    id = sqlc.arg(alert_id);
+    id = sqlc.arg(alert_id)::int
+    AND NOT EXISTS (
+        SELECT
+            1
+        FROM
+            user_alert_interactions
+        WHERE
+            user_id = sqlc.arg(user_id)::int
+            AND alert_id = sqlc.arg(alert_id)::int
+            AND view_count > 0
+    );
 
 -- name: MarkAlertClicked :exec
+-- Records a click for the user, movement_alerts.clicks counts distinct clickers
+WITH interaction AS (
+    INSERT INTO
+        user_alert_interactions (user_id, alert_id, click_count, first_clicked_at, last_clicked_at)
+    VALUES
+        (
+            sqlc.arg(user_id)::int,
+            sqlc.arg(alert_id)::int,
+            1,
+            CURRENT_TIMESTAMP,
+            CURRENT_TIMESTAMP
+        ) ON CONFLICT (user_id, alert_id) DO
+    UPDATE
+    SET
+        click_count = user_alert_interactions.click_count + 1,
+        first_clicked_at = COALESCE(user_alert_interactions.first_clicked_at, EXCLUDED.first_clicked_at),
+        last_clicked_at = EXCLUDED.last_clicked_at
+)
 UPDATE
     movement_alerts
 SET
     clicks = clicks + 1
 WHERE
-    id = sqlc.arg(alert_id);
+    id = sqlc.arg(alert_id)::int
+    AND NOT EXISTS (
+        SELECT
+            1
+        FROM
+            user_alert_interactions
+        WHERE
+            user_id = sqlc.arg(user_id)::int
+            AND alert_id = sqlc.arg(alert_id)::int
+            AND click_count > 0
+    );
 
 -- name: DeactivateExpiredAlerts :exec
 UPDATE
@@ -306,7 +380,8 @@ FROM
     LEFT JOIN teams at ON e.away_team_id = at.id
     JOIN market_types mt ON oh.market_type_id = mt.id
 WHERE
-    oh.recorded_at >= sqlc.arg(since_time)
+    oh.bookmaker = 'iddaa'
+    AND oh.recorded_at >= sqlc.arg(since_time)
     AND e.event_date > NOW()
     -- Significant movement
     AND ABS(oh.change_percentage) >= 3
@@ -314,7 +389,8 @@ WHERE
     AND oh.event_id IN (
         SELECT event_id 
         FROM odds_history 
-        WHERE recorded_at >= sqlc.arg(since_time)
+        WHERE bookmaker = 'iddaa'
+        AND recorded_at >= sqlc.arg(since_time)
         GROUP BY event_id, market_type_id, outcome
         HAVING COUNT(*) >= 3 -- At least 3 movements
     )
@@ -382,7 +458,8 @@ FROM
         AND oh.outcome = od.outcome
     )
 WHERE
-    oh.recorded_at >= sqlc.arg(since_time)
+    oh.bookmaker = 'iddaa'
+    AND oh.recorded_at >= sqlc.arg(since_time)
     AND e.event_date > NOW()
     AND ABS(oh.change_percentage) >= 5
 ORDER BY
@@ -390,54 +467,54 @@ ORDER BY
 LIMIT
     sqlc.arg(limit_count);
 
--- COMMENTED OUT: Requires smart_money_preferences table
--- -- name: GetUserSmartMoneyPreferences :one
--- SELECT * FROM smart_money_preferences WHERE user_id = sqlc.arg(user_id);
--- -- name: UpsertUserSmartMoneyPreferences :one
--- INSERT INTO smart_money_preferences (
---     user_id,
---     min_change_percentage,
---     min_multiplier,
---     min_confidence_score,
---     big_mover_alerts,
---     reverse_line_alerts,
---     sharp_money_alerts,
---     value_spot_alerts,
---     preferred_sports,
---     preferred_leagues,
---     max_daily_alerts,
---     push_notifications,
---     quiet_hours_start,
---     quiet_hours_end
--- ) VALUES (
---     sqlc.arg(user_id),
---     sqlc.arg(min_change_percentage),
---     sqlc.arg(min_multiplier),
---     sqlc.arg(min_confidence_score),
---     sqlc.arg(big_mover_alerts),
---     sqlc.arg(reverse_line_alerts),
---     sqlc.arg(sharp_money_alerts),
---     sqlc.arg(value_spot_alerts),
---     sqlc.arg(preferred_sports),
---     sqlc.arg(preferred_leagues),
---     sqlc.arg(max_daily_alerts),
---     sqlc.arg(push_notifications),
---     sqlc.arg(quiet_hours_start),
---     sqlc.arg(quiet_hours_end)
--- )
--- ON CONFLICT (user_id) DO UPDATE SET
---     min_change_percentage = EXCLUDED.min_change_percentage,
---     min_multiplier = EXCLUDED.min_multiplier,
---     min_confidence_score = EXCLUDED.min_confidence_score,
---     big_mover_alerts = EXCLUDED.big_mover_alerts,
---     reverse_line_alerts = EXCLUDED.reverse_line_alerts,
---     sharp_money_alerts = EXCLUDED.sharp_money_alerts,
---     value_spot_alerts = EXCLUDED.value_spot_alerts,
---     preferred_sports = EXCLUDED.preferred_sports,
---     preferred_leagues = EXCLUDED.preferred_leagues,
---     max_daily_alerts = EXCLUDED.max_daily_alerts,
---     push_notifications = EXCLUDED.push_notifications,
---     quiet_hours_start = EXCLUDED.quiet_hours_start,
---     quiet_hours_end = EXCLUDED.quiet_hours_end,
---     updated_at = CURRENT_TIMESTAMP
--- RETURNING *;
\ No newline at end of file
+-- name: GetUserSmartMoneyPreferences :one
+SELECT * FROM smart_money_preferences WHERE user_id = sqlc.arg(user_id)::int;
+
+-- name: UpsertUserSmartMoneyPreferences :one
+INSERT INTO smart_money_preferences (
+    user_id,
+    min_change_percentage,
+    min_multiplier,
+    min_confidence_score,
+    big_mover_alerts,
+    reverse_line_alerts,
+    sharp_money_alerts,
+    value_spot_alerts,
+    preferred_sports,
+    preferred_leagues,
+    max_daily_alerts,
+    push_notifications,
+    quiet_hours_start,
+    quiet_hours_end
+) VALUES (
+    sqlc.arg(user_id),
+    sqlc.arg(min_change_percentage),
+    sqlc.arg(min_multiplier),
+    sqlc.arg(min_confidence_score),
+    sqlc.arg(big_mover_alerts),
+    sqlc.arg(reverse_line_alerts),
+    sqlc.arg(sharp_money_alerts),
+    sqlc.arg(value_spot_alerts),
+    sqlc.arg(preferred_sports),
+    sqlc.arg(preferred_leagues),
+    sqlc.arg(max_daily_alerts),
+    sqlc.arg(push_notifications),
+    sqlc.narg(quiet_hours_start),
+    sqlc.narg(quiet_hours_end)
+)
+ON CONFLICT (user_id) DO UPDATE SET
+    min_change_percentage = EXCLUDED.min_change_percentage,
+    min_multiplier = EXCLUDED.min_multiplier,
+    min_confidence_score = EXCLUDED.min_confidence_score,
+    big_mover_alerts = EXCLUDED.big_mover_alerts,
+    reverse_line_alerts = EXCLUDED.reverse_line_alerts,
+    sharp_money_alerts = EXCLUDED.sharp_money_alerts,
+    value_spot_alerts = EXCLUDED.value_spot_alerts,
+    preferred_sports = EXCLUDED.preferred_sports,
+    preferred_leagues = EXCLUDED.preferred_leagues,
+    max_daily_alerts = EXCLUDED.max_daily_alerts,
+    push_notifications = EXCLUDED.push_notifications,
+    quiet_hours_start = EXCLUDED.quiet_hours_start,
+    quiet_hours_end = EXCLUDED.quiet_hours_end,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
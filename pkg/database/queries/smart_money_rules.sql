@@ -0,0 +1,199 @@
+-- Smart money rule queries
+-- name: ListSmartMoneyRules :many
+SELECT
+    *
+FROM
+    smart_money_rules
+ORDER BY
+    priority,
+    id;
+
+-- name: ListActiveSmartMoneyRules :many
+SELECT
+    *
+FROM
+    smart_money_rules
+WHERE
+    is_active = true
+ORDER BY
+    priority,
+    id;
+
+-- name: GetSmartMoneyRule :one
+SELECT
+    *
+FROM
+    smart_money_rules
+WHERE
+    id = sqlc.arg(id);
+
+-- name: CreateSmartMoneyRule :one
+INSERT INTO
+    smart_money_rules (
+        name,
+        description,
+        is_active,
+        priority,
+        min_change_pct,
+        max_change_pct,
+        min_abs_change_pct,
+        min_multiplier,
+        max_multiplier,
+        min_bet_pct,
+        max_bet_pct,
+        min_public_bias,
+        min_volume_rank,
+        max_volume_rank,
+        max_volume_pct,
+        min_minutes_to_kickoff,
+        max_minutes_to_kickoff,
+        min_sharp_score,
+        min_moves_last_hour,
+        alert_type,
+        severity,
+        confidence_source,
+        base_confidence,
+        title_template,
+        message_template,
+        max_alerts
+    )
+VALUES
+    (
+        sqlc.arg(name),
+        sqlc.narg(description),
+        sqlc.arg(is_active),
+        sqlc.arg(priority),
+        sqlc.narg(min_change_pct),
+        sqlc.narg(max_change_pct),
+        sqlc.narg(min_abs_change_pct),
+        sqlc.narg(min_multiplier),
+        sqlc.narg(max_multiplier),
+        sqlc.narg(min_bet_pct),
+        sqlc.narg(max_bet_pct),
+        sqlc.narg(min_public_bias),
+        sqlc.narg(min_volume_rank),
+        sqlc.narg(max_volume_rank),
+        sqlc.narg(max_volume_pct),
+        sqlc.narg(min_minutes_to_kickoff),
+        sqlc.narg(max_minutes_to_kickoff),
+        sqlc.narg(min_sharp_score),
+        sqlc.narg(min_moves_last_hour),
+        sqlc.arg(alert_type),
+        sqlc.narg(severity),
+        sqlc.arg(confidence_source),
+        sqlc.arg(base_confidence),
+        sqlc.arg(title_template),
+        sqlc.arg(message_template),
+        sqlc.arg(max_alerts)
+    ) RETURNING *;
+
+-- name: UpdateSmartMoneyRule :one
+UPDATE
+    smart_money_rules
+SET
+    name = sqlc.arg(name),
+    description = sqlc.narg(description),
+    is_active = sqlc.arg(is_active),
+    priority = sqlc.arg(priority),
+    min_change_pct = sqlc.narg(min_change_pct),
+    max_change_pct = sqlc.narg(max_change_pct),
+    min_abs_change_pct = sqlc.narg(min_abs_change_pct),
+    min_multiplier = sqlc.narg(min_multiplier),
+    max_multiplier = sqlc.narg(max_multiplier),
+    min_bet_pct = sqlc.narg(min_bet_pct),
+    max_bet_pct = sqlc.narg(max_bet_pct),
+    min_public_bias = sqlc.narg(min_public_bias),
+    min_volume_rank = sqlc.narg(min_volume_rank),
+    max_volume_rank = sqlc.narg(max_volume_rank),
+    max_volume_pct = sqlc.narg(max_volume_pct),
+    min_minutes_to_kickoff = sqlc.narg(min_minutes_to_kickoff),
+    max_minutes_to_kickoff = sqlc.narg(max_minutes_to_kickoff),
+    min_sharp_score = sqlc.narg(min_sharp_score),
+    min_moves_last_hour = sqlc.narg(min_moves_last_hour),
+    alert_type = sqlc.arg(alert_type),
+    severity = sqlc.narg(severity),
+    confidence_source = sqlc.arg(confidence_source),
+    base_confidence = sqlc.arg(base_confidence),
+    title_template = sqlc.arg(title_template),
+    message_template = sqlc.arg(message_template),
+    max_alerts = sqlc.arg(max_alerts)
+WHERE
+    id = sqlc.arg(id) RETURNING *;
+
+-- name: DeleteSmartMoneyRule :execrows
+DELETE FROM
+    smart_money_rules
+WHERE
+    id = sqlc.arg(id);
+
+-- name: GetSmartMoneyCandidates :many
+-- Recent pre-kickoff movements with the context smart money rules evaluate, largest first.
+-- Moves in the last hour are counted over the hour before the window too.
+WITH moves AS (
+    SELECT
+        oh.id,
+        oh.event_id,
+        oh.market_type_id,
+        oh.outcome,
+        oh.odds_value,
+        oh.change_percentage,
+        oh.multiplier,
+        oh.minutes_to_kickoff,
+        oh.recorded_at,
+        COUNT(*) OVER (
+            PARTITION BY oh.event_id,
+            oh.market_type_id,
+            oh.outcome
+            ORDER BY
+                oh.recorded_at RANGE BETWEEN INTERVAL '1 hour' PRECEDING
+                AND CURRENT ROW
+        ) AS moves_last_hour
+    FROM
+        odds_history oh
+        JOIN events e ON oh.event_id = e.id
+    WHERE
+        oh.bookmaker = 'iddaa'
+        AND oh.recorded_at >= sqlc.arg(since_time)::timestamp - INTERVAL '1 hour'
+        AND e.event_date > NOW()
+)
+SELECT
+    m.id,
+    m.outcome,
+    m.odds_value,
+    COALESCE(m.change_percentage, 0)::float8 as change_percentage,
+    COALESCE(m.multiplier, 1)::float8 as multiplier,
+    COALESCE(
+        m.minutes_to_kickoff,
+        EXTRACT(
+            EPOCH
+            FROM
+                (e.event_date - m.recorded_at)
+        ) / 60
+    )::int as minutes_to_kickoff,
+    e.betting_volume_percentage,
+    e.volume_rank,
+    ht.name as home_team_name,
+    at.name as away_team_name,
+    mt.name as market_name,
+    od.bet_percentage,
+    od.implied_probability,
+    m.moves_last_hour::int as moves_last_hour
+FROM
+    moves m
+    JOIN events e ON m.event_id = e.id
+    LEFT JOIN teams ht ON e.home_team_id = ht.id
+    LEFT JOIN teams at ON e.away_team_id = at.id
+    JOIN market_types mt ON m.market_type_id = mt.id
+    LEFT JOIN outcome_distributions od ON (
+        m.event_id = od.event_id
+        AND COALESCE(od.market_type_id, od.market_id) = m.market_type_id
+        AND m.outcome = od.outcome
+    )
+WHERE
+    m.recorded_at >= sqlc.arg(since_time)::timestamp
+    AND ABS(COALESCE(m.change_percentage, 0)) >= sqlc.arg(min_abs_change)::float8
+ORDER BY
+    ABS(COALESCE(m.change_percentage, 0)) DESC,
+    m.recorded_at DESC
+LIMIT
+    sqlc.arg(limit_count)::int;
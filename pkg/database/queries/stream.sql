@@ -0,0 +1,38 @@
+-- Rows announced by the stream notifications of migration 000023
+-- name: GetStreamOdds :many
+SELECT
+    oh.id,
+    oh.event_id,
+    e.slug AS event_slug,
+    COALESCE(s.code, '')::text AS sport_code,
+    e.league_id,
+    row_to_json(oh)::jsonb AS data
+FROM
+    odds_history oh
+    JOIN events e ON e.id = oh.event_id
+    LEFT JOIN sports s ON s.id = e.sport_id
+WHERE
+    oh.id = ANY(sqlc.arg(ids)::int[])
+    AND oh.recorded_at BETWEEN sqlc.arg(from_time)::timestamp
+    AND sqlc.arg(to_time)::timestamp
+ORDER BY
+    oh.id;
+
+-- name: GetStreamAlerts :many
+SELECT
+    ma.id,
+    oh.event_id,
+    e.slug AS event_slug,
+    COALESCE(s.code, '')::text AS sport_code,
+    e.league_id,
+    ma.alert_type,
+    row_to_json(ma)::jsonb AS data
+FROM
+    movement_alerts ma
+    JOIN odds_history oh ON oh.id = ma.odds_history_id
+    JOIN events e ON e.id = oh.event_id
+    LEFT JOIN sports s ON s.id = e.sport_id
+WHERE
+    ma.id = ANY(sqlc.arg(ids)::int[])
+ORDER BY
+    ma.id;
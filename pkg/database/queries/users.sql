@@ -0,0 +1,93 @@
+-- Users and API key queries
+-- name: CreateUser :one
+-- Creates a user together with default smart money preferences
+WITH new_user AS (
+    INSERT INTO
+        users (email, name)
+    VALUES
+        (sqlc.arg(email)::text, sqlc.narg(name)::text) RETURNING *
+),
+default_preferences AS (
+    INSERT INTO
+        smart_money_preferences (user_id)
+    SELECT
+        id
+    FROM
+        new_user
+)
+SELECT
+    *
+FROM
+    new_user;
+
+-- name: GetUser :one
+SELECT
+    *
+FROM
+    users
+WHERE
+    id = sqlc.arg(id);
+
+-- name: CreateAPIKey :one
+INSERT INTO
+    api_keys (user_id, name, key_prefix, key_hash, expires_at)
+VALUES
+    (
+        sqlc.arg(user_id),
+        sqlc.arg(name),
+        sqlc.arg(key_prefix),
+        sqlc.arg(key_hash),
+        sqlc.narg(expires_at)
+    ) RETURNING *;
+
+-- name: GetAPIKeyUser :one
+-- Resolves a hashed API key to its active, unexpired owner
+SELECT
+    k.id as api_key_id,
+    u.id as user_id,
+    u.email,
+    u.name
+FROM
+    api_keys k
+    JOIN users u ON k.user_id = u.id
+WHERE
+    k.key_hash = sqlc.arg(key_hash)
+    AND k.revoked_at IS NULL
+    AND (
+        k.expires_at IS NULL
+        OR k.expires_at > NOW()
+    )
+    AND u.is_active = true;
+
+-- name: TouchAPIKey :exec
+-- Records key usage at most once a minute to avoid a write per request
+UPDATE
+    api_keys
+SET
+    last_used_at = CURRENT_TIMESTAMP
+WHERE
+    id = sqlc.arg(id)
+    AND (
+        last_used_at IS NULL
+        OR last_used_at < NOW() - INTERVAL '1 minute'
+    );
+
+-- name: ListAPIKeysByUser :many
+SELECT
+    *
+FROM
+    api_keys
+WHERE
+    user_id = sqlc.arg(user_id)
+ORDER BY
+    created_at DESC;
+
+-- name: RevokeAPIKey :execrows
+UPDATE
+    api_keys
+SET
+    revoked_at = CURRENT_TIMESTAMP
+WHERE
+    id = sqlc.arg(id)
+    AND user_id = sqlc.arg(user_id)
+    AND revoked_at IS NULL;
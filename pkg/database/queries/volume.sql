@@ -25,181 +25,444 @@ VALUES
     ) RETURNING *;
 
 -- name: GetHotMovers :many
--- Find events with high betting volume AND significant odds movement
+-- Find events with high betting volume AND significant odds movement.
+-- Volume change and best rank come from betting_volume_history since since_time.
+WITH movers AS (
+    SELECT
+        e.id,
+        e.slug,
+        (ht.name || ' vs ' || at.name)::text as match_name,
+        s.code as sport_code,
+        s.name as sport_name,
+        l.name as league_name,
+        e.event_date,
+        e.betting_volume_percentage,
+        e.volume_rank,
+        (
+            SELECT
+                COALESCE(MAX(ABS(co.movement_percentage)), 0)
+            FROM
+                current_odds co
+            WHERE
+                co.event_id = e.id
+                AND co.bookmaker = 'iddaa'
+        )::float8 as max_movement
+    FROM
+        events e
+        JOIN teams ht ON e.home_team_id = ht.id
+        JOIN teams at ON e.away_team_id = at.id
+        JOIN leagues l ON e.league_id = l.id
+        JOIN sports s ON e.sport_id = s.id
+    WHERE
+        e.betting_volume_percentage >= sqlc.arg(min_volume)::float8
+        AND e.event_date > CURRENT_TIMESTAMP
+        AND (
+            sqlc.arg(sport_code)::text = ''
+            OR s.code = sqlc.arg(sport_code)::text
+        )
+)
 SELECT
-    e.slug,
-    ht.name || ' vs ' || at.name as match_name,
-    e.betting_volume_percentage,
-    e.volume_rank,
-    COALESCE(MAX(ABS(co.movement_percentage)), 0) as max_movement,
+    m.slug,
+    m.match_name,
+    m.sport_code,
+    m.sport_name,
+    m.league_name,
+    m.event_date,
+    m.betting_volume_percentage,
+    m.volume_rank,
+    COALESCE(m.betting_volume_percentage - vh.first_volume, 0)::float8 as volume_change,
+    vh.best_rank::int as best_rank,
+    m.max_movement,
     CASE
-        WHEN e.betting_volume_percentage > 5 THEN 'HOT'
-        WHEN e.betting_volume_percentage > 2 THEN 'POPULAR'
-        WHEN e.betting_volume_percentage > 1 THEN 'MODERATE'
+        WHEN m.betting_volume_percentage > 5 THEN 'HOT'
+        WHEN m.betting_volume_percentage > 2 THEN 'POPULAR'
+        WHEN m.betting_volume_percentage > 1 THEN 'MODERATE'
         ELSE 'COLD'
-    END as popularity_level,
+    END::text as popularity_level,
     CASE
-        WHEN e.betting_volume_percentage > 5
-        AND MAX(ABS(co.movement_percentage)) > 50 THEN 'HOT_MOVER'
-        WHEN e.betting_volume_percentage < 1
-        AND MAX(ABS(co.movement_percentage)) > 50 THEN 'HIDDEN_GEM'
-        WHEN e.betting_volume_percentage > 5
-        AND MAX(ABS(co.movement_percentage)) < 10 THEN 'STABLE_FAVORITE'
+        WHEN m.betting_volume_percentage > 5
+        AND m.max_movement > 50 THEN 'HOT_MOVER'
+        WHEN m.betting_volume_percentage < 1
+        AND m.max_movement > 50 THEN 'HIDDEN_GEM'
+        WHEN m.betting_volume_percentage > 5
+        AND m.max_movement < 10 THEN 'STABLE_FAVORITE'
         ELSE 'NORMAL'
-    END as event_type
+    END::text as event_type
 FROM
-    events e
-    JOIN teams ht ON e.home_team_id = ht.id
-    JOIN teams at ON e.away_team_id = at.id
-    LEFT JOIN current_odds co ON co.event_id = e.id
+    movers m
+    LEFT JOIN LATERAL (
+        SELECT
+            (ARRAY_AGG(bvh.volume_percentage ORDER BY bvh.recorded_at))[1] as first_volume,
+            MIN(bvh.rank_position) as best_rank
+        FROM
+            betting_volume_history bvh
+        WHERE
+            bvh.event_id = m.id
+            AND bvh.recorded_at >= sqlc.arg(since_time)::timestamp
+    ) vh ON TRUE
 WHERE
-    e.betting_volume_percentage >= sqlc.arg(min_volume) :: float8
-    AND e.event_date > CURRENT_TIMESTAMP
-GROUP BY
-    e.id,
-    e.slug,
-    ht.name,
-    at.name,
-    e.betting_volume_percentage,
-    e.volume_rank
-HAVING
-    COALESCE(MAX(ABS(co.movement_percentage)), 0) >= sqlc.arg(min_movement) :: float8
+    m.max_movement >= sqlc.arg(min_movement)::float8
 ORDER BY
-    e.betting_volume_percentage DESC
+    m.betting_volume_percentage DESC,
+    m.id
 LIMIT
-    50;
+    sqlc.arg(limit_count)::int;
 
 -- name: GetHiddenGems :many
--- Find low-volume events with big movements (potential sharp money)
+-- Find low-volume events with big movements (potential sharp money).
+-- Volume change comes from betting_volume_history since since_time.
+WITH gems AS (
+    SELECT
+        e.id,
+        e.slug,
+        (ht.name || ' vs ' || at.name)::text as match_name,
+        s.code as sport_code,
+        s.name as sport_name,
+        l.name as league_name,
+        e.event_date,
+        e.betting_volume_percentage,
+        e.volume_rank,
+        (
+            SELECT
+                COALESCE(MAX(ABS(co.movement_percentage)), 0)
+            FROM
+                current_odds co
+            WHERE
+                co.event_id = e.id
+                AND co.bookmaker = 'iddaa'
+        )::float8 as max_movement
+    FROM
+        events e
+        JOIN teams ht ON e.home_team_id = ht.id
+        JOIN teams at ON e.away_team_id = at.id
+        JOIN leagues l ON e.league_id = l.id
+        JOIN sports s ON e.sport_id = s.id
+    WHERE
+        e.betting_volume_percentage <= sqlc.arg(max_volume)::float8
+        AND e.betting_volume_percentage > 0
+        AND e.event_date > CURRENT_TIMESTAMP
+        AND (
+            sqlc.arg(sport_code)::text = ''
+            OR s.code = sqlc.arg(sport_code)::text
+        )
+)
 SELECT
-    e.slug,
-    ht.name || ' vs ' || at.name as match_name,
-    e.betting_volume_percentage,
-    COALESCE(MAX(ABS(co.movement_percentage)), 0) as max_movement
+    g.slug,
+    g.match_name,
+    g.sport_code,
+    g.sport_name,
+    g.league_name,
+    g.event_date,
+    g.betting_volume_percentage,
+    g.volume_rank,
+    COALESCE(g.betting_volume_percentage - vh.first_volume, 0)::float8 as volume_change,
+    g.max_movement
 FROM
-    events e
-    JOIN teams ht ON e.home_team_id = ht.id
-    JOIN teams at ON e.away_team_id = at.id
-    LEFT JOIN current_odds co ON co.event_id = e.id
+    gems g
+    LEFT JOIN LATERAL (
+        SELECT
+            (ARRAY_AGG(bvh.volume_percentage ORDER BY bvh.recorded_at))[1] as first_volume
+        FROM
+            betting_volume_history bvh
+        WHERE
+            bvh.event_id = g.id
+            AND bvh.recorded_at >= sqlc.arg(since_time)::timestamp
+    ) vh ON TRUE
 WHERE
-    e.betting_volume_percentage <= sqlc.arg(max_volume) :: float8
-    AND e.betting_volume_percentage > 0
-    AND e.event_date > CURRENT_TIMESTAMP
-GROUP BY
-    e.id,
-    e.slug,
-    ht.name,
-    at.name,
-    e.betting_volume_percentage
-HAVING
-    COALESCE(MAX(ABS(co.movement_percentage)), 0) >= sqlc.arg(min_movement) :: float8
+    g.max_movement >= sqlc.arg(min_movement)::float8
 ORDER BY
-    MAX(ABS(co.movement_percentage)) DESC
+    g.max_movement DESC,
+    g.id
 LIMIT
-    20;
+    sqlc.arg(limit_count)::int;
+
+-- name: GetHotMoversBySport :many
+-- Rolls the events GetHotMovers finds up per sport, over all of them rather than one page
+WITH movers AS (
+    SELECT
+        e.id,
+        s.code as sport_code,
+        s.name as sport_name,
+        e.betting_volume_percentage,
+        (
+            SELECT
+                COALESCE(MAX(ABS(co.movement_percentage)), 0)
+            FROM
+                current_odds co
+            WHERE
+                co.event_id = e.id
+                AND co.bookmaker = 'iddaa'
+        )::float8 as max_movement
+    FROM
+        events e
+        JOIN teams ht ON e.home_team_id = ht.id
+        JOIN teams at ON e.away_team_id = at.id
+        JOIN leagues l ON e.league_id = l.id
+        JOIN sports s ON e.sport_id = s.id
+    WHERE
+        e.betting_volume_percentage >= sqlc.arg(min_volume)::float8
+        AND e.event_date > CURRENT_TIMESTAMP
+        AND (
+            sqlc.arg(sport_code)::text = ''
+            OR s.code = sqlc.arg(sport_code)::text
+        )
+)
+SELECT
+    m.sport_code,
+    m.sport_name,
+    COUNT(*)::int as events,
+    AVG(COALESCE(m.betting_volume_percentage, 0))::float8 as avg_volume,
+    AVG(m.max_movement)::float8 as avg_movement,
+    MAX(m.max_movement)::float8 as max_movement,
+    SUM(COALESCE(m.betting_volume_percentage - vh.first_volume, 0))::float8 as net_volume_change
+FROM
+    movers m
+    LEFT JOIN LATERAL (
+        SELECT
+            (ARRAY_AGG(bvh.volume_percentage ORDER BY bvh.recorded_at))[1] as first_volume
+        FROM
+            betting_volume_history bvh
+        WHERE
+            bvh.event_id = m.id
+            AND bvh.recorded_at >= sqlc.arg(since_time)::timestamp
+    ) vh ON TRUE
+WHERE
+    m.max_movement >= sqlc.arg(min_movement)::float8
+GROUP BY
+    m.sport_code,
+    m.sport_name
+ORDER BY
+    events DESC,
+    m.sport_code;
+
+-- name: GetHiddenGemsBySport :many
+-- Rolls the events GetHiddenGems finds up per sport, over all of them rather than one page
+WITH gems AS (
+    SELECT
+        e.id,
+        s.code as sport_code,
+        s.name as sport_name,
+        e.betting_volume_percentage,
+        (
+            SELECT
+                COALESCE(MAX(ABS(co.movement_percentage)), 0)
+            FROM
+                current_odds co
+            WHERE
+                co.event_id = e.id
+                AND co.bookmaker = 'iddaa'
+        )::float8 as max_movement
+    FROM
+        events e
+        JOIN teams ht ON e.home_team_id = ht.id
+        JOIN teams at ON e.away_team_id = at.id
+        JOIN leagues l ON e.league_id = l.id
+        JOIN sports s ON e.sport_id = s.id
+    WHERE
+        e.betting_volume_percentage <= sqlc.arg(max_volume)::float8
+        AND e.betting_volume_percentage > 0
+        AND e.event_date > CURRENT_TIMESTAMP
+        AND (
+            sqlc.arg(sport_code)::text = ''
+            OR s.code = sqlc.arg(sport_code)::text
+        )
+)
+SELECT
+    g.sport_code,
+    g.sport_name,
+    COUNT(*)::int as events,
+    AVG(COALESCE(g.betting_volume_percentage, 0))::float8 as avg_volume,
+    AVG(g.max_movement)::float8 as avg_movement,
+    MAX(g.max_movement)::float8 as max_movement,
+    SUM(COALESCE(g.betting_volume_percentage - vh.first_volume, 0))::float8 as net_volume_change
+FROM
+    gems g
+    LEFT JOIN LATERAL (
+        SELECT
+            (ARRAY_AGG(bvh.volume_percentage ORDER BY bvh.recorded_at))[1] as first_volume
+        FROM
+            betting_volume_history bvh
+        WHERE
+            bvh.event_id = g.id
+            AND bvh.recorded_at >= sqlc.arg(since_time)::timestamp
+    ) vh ON TRUE
+WHERE
+    g.max_movement >= sqlc.arg(min_movement)::float8
+GROUP BY
+    g.sport_code,
+    g.sport_name
+ORDER BY
+    events DESC,
+    g.sport_code;
 
 -- name: GetVolumeHistory :many
--- Get volume history for a specific event
+-- Get volume history for a specific event, newest first
 SELECT
-    bvh.*,
-    LAG(bvh.volume_percentage) OVER (
-        ORDER BY
-            bvh.recorded_at
-    ) as previous_volume,
-    bvh.volume_percentage - LAG(bvh.volume_percentage) OVER (
-        ORDER BY
-            bvh.recorded_at
-    ) as volume_change
+    h.id,
+    h.event_id,
+    h.volume_percentage,
+    h.rank_position,
+    h.total_events_tracked,
+    h.recorded_at,
+    h.previous_volume,
+    h.volume_change
 FROM
-    betting_volume_history bvh
+    (
+        SELECT
+            bvh.*,
+            LAG(bvh.volume_percentage) OVER (
+                ORDER BY
+                    bvh.recorded_at
+            ) as previous_volume,
+            bvh.volume_percentage - LAG(bvh.volume_percentage) OVER (
+                ORDER BY
+                    bvh.recorded_at
+            ) as volume_change
+        FROM
+            betting_volume_history bvh
+        WHERE
+            bvh.event_id = sqlc.arg(event_id)::int
+    ) h
 WHERE
-    bvh.event_id = sqlc.arg(event_id)
+    h.recorded_at >= sqlc.arg(since_time)::timestamp
 ORDER BY
-    bvh.recorded_at DESC
+    h.recorded_at DESC
 LIMIT
-    100;
+    sqlc.arg(limit_count)::int OFFSET sqlc.arg(offset_count)::int;
+
+-- name: CountVolumeHistory :one
+SELECT
+    COUNT(*)::int
+FROM
+    betting_volume_history bvh
+WHERE
+    bvh.event_id = sqlc.arg(event_id)::int
+    AND bvh.recorded_at >= sqlc.arg(since_time)::timestamp;
 
 -- name: GetTopVolumeEvents :many
 -- Get current top events by betting volume
 SELECT
     e.slug,
-    ht.name || ' vs ' || at.name as match_name,
+    (ht.name || ' vs ' || at.name)::text as match_name,
+    s.code as sport_code,
+    l.name as league_name,
     e.event_date,
     e.betting_volume_percentage,
     e.volume_rank,
-    COUNT(DISTINCT oh.id) as total_odds_changes,
-    COALESCE(MAX(ABS(co.movement_percentage)), 0) as max_movement
+    (
+        SELECT
+            COUNT(*)
+        FROM
+            odds_history oh
+        WHERE
+            oh.event_id = e.id
+            AND oh.bookmaker = 'iddaa'
+    )::int as total_odds_changes,
+    (
+        SELECT
+            COALESCE(MAX(ABS(co.movement_percentage)), 0)
+        FROM
+            current_odds co
+        WHERE
+            co.event_id = e.id
+            AND co.bookmaker = 'iddaa'
+    )::float8 as max_movement
 FROM
     events e
     JOIN teams ht ON e.home_team_id = ht.id
     JOIN teams at ON e.away_team_id = at.id
-    LEFT JOIN current_odds co ON co.event_id = e.id
-    LEFT JOIN odds_history oh ON oh.event_id = e.id
+    JOIN leagues l ON e.league_id = l.id
+    JOIN sports s ON e.sport_id = s.id
 WHERE
-    e.volume_rank <= 20
+    e.volume_rank <= sqlc.arg(max_rank)::int
     AND e.event_date > CURRENT_TIMESTAMP
-GROUP BY
-    e.id,
-    e.slug,
-    ht.name,
-    at.name,
-    e.event_date,
-    e.betting_volume_percentage,
-    e.volume_rank
+    AND e.event_date <= sqlc.arg(to_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    )
+    AND (
+        sqlc.arg(league_name)::text = ''
+        OR l.name ILIKE '%' || sqlc.arg(league_name)::text || '%'
+    )
 ORDER BY
-    e.volume_rank;
+    e.volume_rank,
+    e.id
+LIMIT
+    sqlc.arg(limit_count)::int OFFSET sqlc.arg(offset_count)::int;
 
--- name: AnalyzeVolumeOddsPattern :one
+-- name: CountTopVolumeEvents :one
+SELECT
+    COUNT(*)::int
+FROM
+    events e
+    JOIN leagues l ON e.league_id = l.id
+    JOIN sports s ON e.sport_id = s.id
+WHERE
+    e.volume_rank <= sqlc.arg(max_rank)::int
+    AND e.event_date > CURRENT_TIMESTAMP
+    AND e.event_date <= sqlc.arg(to_time)::timestamp
+    AND (
+        sqlc.arg(sport_code)::text = ''
+        OR s.code = sqlc.arg(sport_code)::text
+    )
+    AND (
+        sqlc.arg(league_name)::text = ''
+        OR l.name ILIKE '%' || sqlc.arg(league_name)::text || '%'
+    );
+
+-- name: AnalyzeVolumeOddsPattern :many
 -- Analyze correlation between volume and odds movement
-WITH patterns AS (
+WITH event_movements AS (
     SELECT
-        CASE
-            WHEN e.betting_volume_percentage > 5
-            AND MAX(ABS(co.movement_percentage)) > 30 THEN 'HIGH_VOLUME_HIGH_MOVEMENT'
-            WHEN e.betting_volume_percentage > 5
-            AND MAX(ABS(co.movement_percentage)) < 10 THEN 'HIGH_VOLUME_STABLE'
-            WHEN e.betting_volume_percentage < 1
-            AND MAX(ABS(co.movement_percentage)) > 30 THEN 'LOW_VOLUME_HIGH_MOVEMENT'
-            WHEN e.betting_volume_percentage < 1
-            AND MAX(ABS(co.movement_percentage)) < 10 THEN 'LOW_VOLUME_STABLE'
-            ELSE 'MODERATE'
-        END as pattern,
-        COUNT(*) as event_count,
-        AVG(e.betting_volume_percentage) as avg_volume,
-        AVG(MAX(ABS(co.movement_percentage))) as avg_movement
+        e.id,
+        e.betting_volume_percentage as volume,
+        COALESCE(MAX(ABS(co.movement_percentage)), 0) as max_movement
     FROM
         events e
+        JOIN sports s ON e.sport_id = s.id
         LEFT JOIN current_odds co ON co.event_id = e.id
+        AND co.bookmaker = 'iddaa'
     WHERE
-        e.volume_updated_at > CURRENT_TIMESTAMP - INTERVAL '24 hours'
+        e.volume_updated_at > sqlc.arg(since_time)::timestamp
+        AND e.betting_volume_percentage IS NOT NULL
+        AND (
+            sqlc.arg(sport_code)::text = ''
+            OR s.code = sqlc.arg(sport_code)::text
+        )
     GROUP BY
         e.id,
         e.betting_volume_percentage
+),
+patterns AS (
+    SELECT
+        CASE
+            WHEN volume > 5
+            AND max_movement > 30 THEN 'HIGH_VOLUME_HIGH_MOVEMENT'
+            WHEN volume > 5
+            AND max_movement < 10 THEN 'HIGH_VOLUME_STABLE'
+            WHEN volume < 1
+            AND max_movement > 30 THEN 'LOW_VOLUME_HIGH_MOVEMENT'
+            WHEN volume < 1
+            AND max_movement < 10 THEN 'LOW_VOLUME_STABLE'
+            ELSE 'MODERATE'
+        END as pattern,
+        volume,
+        max_movement
+    FROM
+        event_movements
 )
 SELECT
-    json_object_agg(
-        pattern,
-        json_build_object(
-            'count',
-            event_count,
-            'avg_volume',
-            ROUND(avg_volume :: NUMERIC, 2),
-            'avg_movement',
-            ROUND(avg_movement :: NUMERIC, 2)
-        )
-    ) as analysis
+    pattern::text as pattern,
+    COUNT(*)::int as event_count,
+    ROUND(AVG(volume)::NUMERIC, 2)::float8 as avg_volume,
+    ROUND(AVG(max_movement)::NUMERIC, 2)::float8 as avg_movement
 FROM
-    (
-        SELECT
-            pattern,
-            SUM(event_count) as event_count,
-            AVG(avg_volume) as avg_volume,
-            AVG(avg_movement) as avg_movement
-        FROM
-            patterns
-        GROUP BY
-            pattern
-    ) summary;
+    patterns
+GROUP BY
+    pattern
+ORDER BY
+    event_count DESC;
 
 -- name: BulkUpdateEventVolumes :execrows
 -- Bulk update event volumes with database-calculated ranks
@@ -224,7 +487,7 @@ UPDATE
 SET
     betting_volume_percentage = rv.percentage,
     volume_rank = rv.rank,
-    volume_updated_at = NOW()
+    volume_updated_at = COALESCE(sqlc.narg(recorded_at)::timestamp, NOW())
 FROM
     ranked_volumes rv
 WHERE
@@ -237,13 +500,15 @@ INSERT INTO
         event_id,
         volume_percentage,
         rank_position,
-        total_events_tracked
+        total_events_tracked,
+        recorded_at
     )
 SELECT
     e.id,
     vd.percentage,
     vd.rank,
-    sqlc.arg(total_events) :: int4
+    sqlc.arg(total_events) :: int4,
+    COALESCE(sqlc.narg(recorded_at)::timestamp, CURRENT_TIMESTAMP)
 FROM
     (
         SELECT
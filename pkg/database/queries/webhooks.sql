@@ -0,0 +1,287 @@
+-- Webhook delivery queries
+-- name: CreateWebhookSubscription :one
+INSERT INTO
+    webhook_subscriptions (
+        user_id,
+        name,
+        url,
+        secret,
+        alert_types,
+        severities,
+        sport_codes,
+        min_confidence
+    )
+VALUES
+    (
+        sqlc.arg(user_id)::int,
+        sqlc.arg(name)::text,
+        sqlc.arg(url)::text,
+        sqlc.arg(secret)::text,
+        sqlc.arg(alert_types)::text[],
+        sqlc.arg(severities)::text[],
+        sqlc.arg(sport_codes)::text[],
+        sqlc.arg(min_confidence)::float8
+    ) RETURNING *;
+
+-- name: ListWebhookSubscriptions :many
+SELECT
+    *
+FROM
+    webhook_subscriptions
+WHERE
+    user_id = sqlc.arg(user_id)::int
+    AND is_active = true
+ORDER BY
+    created_at DESC;
+
+-- name: GetWebhookSubscription :one
+-- A subscription of one user, deactivated ones included for their delivery history
+SELECT
+    *
+FROM
+    webhook_subscriptions
+WHERE
+    id = sqlc.arg(id)::int
+    AND user_id = sqlc.arg(user_id)::int;
+
+-- name: DeactivateWebhookSubscription :execrows
+UPDATE
+    webhook_subscriptions
+SET
+    is_active = false
+WHERE
+    id = sqlc.arg(id)::int
+    AND user_id = sqlc.arg(user_id)::int;
+
+-- name: EnqueueWebhookDeliveries :execrows
+-- Create pending deliveries for new alerts matching each active subscription
+INSERT INTO
+    webhook_deliveries (subscription_id, alert_id, payload)
+SELECT
+    ws.id,
+    ma.id,
+    json_build_object(
+        'event', 'movement_alert',
+        'alert', json_build_object(
+            'id', ma.id,
+            'alert_type', ma.alert_type,
+            'severity', ma.severity,
+            'title', ma.title,
+            'message', ma.message,
+            'change_percentage', ma.change_percentage,
+            'multiplier', ma.multiplier,
+            'confidence_score', ma.confidence_score,
+            'minutes_to_kickoff', ma.minutes_to_kickoff,
+            'created_at', ma.created_at
+        ),
+        'match', json_build_object(
+            'slug', e.slug,
+            'home_team', ht.name,
+            'away_team', at.name,
+            'league', l.name,
+            'sport', s.code,
+            'event_date', e.event_date
+        ),
+        'odds', json_build_object(
+            'market', mt.name,
+            'outcome', oh.outcome,
+            'odds_value', oh.odds_value,
+            'previous_value', oh.previous_value,
+            'recorded_at', oh.recorded_at
+        )
+    ) :: jsonb
+FROM
+    movement_alerts ma
+    JOIN odds_history oh ON ma.odds_history_id = oh.id
+    JOIN events e ON oh.event_id = e.id
+    JOIN teams ht ON e.home_team_id = ht.id
+    JOIN teams at ON e.away_team_id = at.id
+    JOIN leagues l ON e.league_id = l.id
+    JOIN sports s ON e.sport_id = s.id
+    JOIN market_types mt ON oh.market_type_id = mt.id
+    JOIN webhook_subscriptions ws ON ws.is_active = true
+    AND ma.created_at >= ws.created_at
+    AND ma.confidence_score >= ws.min_confidence
+    AND (
+        cardinality(ws.alert_types) = 0
+        OR ma.alert_type = ANY(ws.alert_types)
+    )
+    AND (
+        cardinality(ws.severities) = 0
+        OR ma.severity = ANY(ws.severities)
+    )
+    AND (
+        cardinality(ws.sport_codes) = 0
+        OR s.code = ANY(ws.sport_codes)
+    )
+WHERE
+    ma.is_active = true
+    AND ma.created_at >= sqlc.arg(since_time)::timestamp ON CONFLICT (subscription_id, alert_id) DO NOTHING;
+
+-- name: ClaimDueWebhookDeliveries :many
+-- Claims the oldest due deliveries for one dispatcher by moving their next attempt past the lease.
+-- A delivery whose dispatcher stopped before recording the result is due again once the lease ends.
+WITH due AS (
+    SELECT
+        wd.id
+    FROM
+        webhook_deliveries wd
+        JOIN webhook_subscriptions ws ON wd.subscription_id = ws.id
+    WHERE
+        wd.status IN ('pending', 'retrying')
+        AND wd.next_attempt_at <= NOW()
+        AND ws.is_active = true
+    ORDER BY
+        wd.next_attempt_at ASC
+    LIMIT
+        sqlc.arg(limit_count)::int FOR
+    UPDATE
+        OF wd SKIP LOCKED
+)
+UPDATE
+    webhook_deliveries wd
+SET
+    next_attempt_at = NOW() + make_interval(secs => sqlc.arg(lease_seconds)::int)
+FROM
+    due,
+    webhook_subscriptions ws
+WHERE
+    wd.id = due.id
+    AND ws.id = wd.subscription_id RETURNING wd.id,
+    wd.subscription_id,
+    wd.alert_id,
+    wd.payload,
+    wd.attempts,
+    ws.url,
+    ws.secret;
+
+-- name: CreateWebhookDeliveryAttempt :exec
+INSERT INTO
+    webhook_delivery_attempts (
+        delivery_id,
+        attempt,
+        response_status,
+        error,
+        duration_ms
+    )
+VALUES
+    (
+        sqlc.arg(delivery_id)::int,
+        sqlc.arg(attempt)::int,
+        sqlc.narg(response_status)::int,
+        sqlc.narg(error)::text,
+        sqlc.arg(duration_ms)::int
+    );
+
+-- name: MarkWebhookDelivered :exec
+UPDATE
+    webhook_deliveries
+SET
+    status = 'delivered',
+    attempts = sqlc.arg(attempts)::int,
+    response_status = sqlc.narg(response_status)::int,
+    last_error = NULL,
+    delivered_at = NOW()
+WHERE
+    id = sqlc.arg(id)::int;
+
+-- name: ScheduleWebhookRetry :exec
+UPDATE
+    webhook_deliveries
+SET
+    status = 'retrying',
+    attempts = sqlc.arg(attempts)::int,
+    response_status = sqlc.narg(response_status)::int,
+    last_error = sqlc.narg(last_error)::text,
+    next_attempt_at = sqlc.arg(next_attempt_at)::timestamp
+WHERE
+    id = sqlc.arg(id)::int;
+
+-- name: MarkWebhookDead :exec
+-- Move an exhausted delivery to the dead-letter table
+WITH dead AS (
+    UPDATE
+        webhook_deliveries
+    SET
+        status = 'dead',
+        attempts = sqlc.arg(attempts)::int,
+        response_status = sqlc.narg(response_status)::int,
+        last_error = sqlc.narg(last_error)::text
+    WHERE
+        id = sqlc.arg(id)::int RETURNING id,
+        subscription_id,
+        alert_id,
+        payload,
+        attempts,
+        last_error
+)
+INSERT INTO
+    webhook_dead_letters (
+        delivery_id,
+        subscription_id,
+        alert_id,
+        payload,
+        attempts,
+        last_error
+    )
+SELECT
+    id,
+    subscription_id,
+    alert_id,
+    payload,
+    attempts,
+    last_error
+FROM
+    dead ON CONFLICT (delivery_id) DO NOTHING;
+
+-- name: ListWebhookDeliveries :many
+SELECT
+    *
+FROM
+    webhook_deliveries
+WHERE
+    subscription_id = sqlc.arg(subscription_id)::int
+    AND EXISTS (
+        SELECT
+            1
+        FROM
+            webhook_subscriptions ws
+        WHERE
+            ws.id = webhook_deliveries.subscription_id
+            AND ws.user_id = sqlc.arg(user_id)::int
+    )
+ORDER BY
+    created_at DESC
+LIMIT
+    sqlc.arg(limit_count)::int;
+
+-- name: ListWebhookDeliveryAttempts :many
+SELECT
+    *
+FROM
+    webhook_delivery_attempts
+WHERE
+    delivery_id = sqlc.arg(delivery_id)::int
+ORDER BY
+    attempt ASC;
+
+-- name: ListWebhookDeadLetters :many
+SELECT
+    *
+FROM
+    webhook_dead_letters
+WHERE
+    subscription_id = sqlc.arg(subscription_id)::int
+    AND EXISTS (
+        SELECT
+            1
+        FROM
+            webhook_subscriptions ws
+        WHERE
+            ws.id = webhook_dead_letters.subscription_id
+            AND ws.user_id = sqlc.arg(user_id)::int
+    )
+ORDER BY
+    created_at DESC
+LIMIT
+    sqlc.arg(limit_count)::int;
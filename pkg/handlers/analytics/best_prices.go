@@ -0,0 +1,111 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+// deviationByStrength is the minimum deviation (%) of Iddaa's price from the market median per strength level
+var deviationByStrength = map[int]float64{0: 5, 1: 5, 2: 10, 3: 15, 4: 25}
+
+// BestPrice is an upcoming outcome whose Iddaa price is out of line with other bookmakers
+type BestPrice struct {
+	EventSlug      string    `json:"event_slug"`
+	Match          string    `json:"match"`
+	Sport          string    `json:"sport"`
+	League         string    `json:"league"`
+	EventDate      time.Time `json:"event_date"`
+	MarketCode     string    `json:"market_code"`
+	MarketName     string    `json:"market_name"`
+	Outcome        string    `json:"outcome"`
+	IddaaOdds      float64   `json:"iddaa_odds"`
+	MarketOdds     float64   `json:"market_odds"`
+	BestOdds       float64   `json:"best_odds"`
+	BestBookmaker  string    `json:"best_bookmaker"`
+	BookmakerCount int32     `json:"bookmaker_count"`
+	Deviation      float64   `json:"deviation_percentage"`
+	// IddaaIsBest is set when Iddaa pays more than every other bookmaker
+	IddaaIsBest bool `json:"iddaa_is_best"`
+}
+
+// BestPrices handles GET /api/analytics/best-prices
+// Filters: sport, league, strength (minimum deviation from the market median), min_deviation (overrides strength),
+// min_bookmakers (default 2), hours since other bookmakers' prices last updated (default 2)
+func (h *Handler) BestPrices(w http.ResponseWriter, r *http.Request) {
+	params := parseListParams(r, 2, 24)
+
+	minDeviation := deviationByStrength[params.Strength]
+	if parsed, err := strconv.ParseFloat(r.URL.Query().Get("min_deviation"), 64); err == nil && parsed >= 0 {
+		minDeviation = parsed
+	}
+
+	minBookmakers := 2
+	if parsed, err := strconv.Atoi(r.URL.Query().Get("min_bookmakers")); err == nil && parsed >= 1 && parsed <= 50 {
+		minBookmakers = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	total, err := h.queries.CountBestPrices(ctx, generated.CountBestPricesParams{
+		SinceTime:     params.since(),
+		MinBookmakers: int32(minBookmakers),
+		MinDeviation:  minDeviation,
+		SportCode:     params.Sport,
+		LeagueName:    params.League,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to count best prices")
+		http.Error(w, "Failed to retrieve best prices", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := h.queries.GetBestPrices(ctx, generated.GetBestPricesParams{
+		SinceTime:     params.since(),
+		MinBookmakers: int32(minBookmakers),
+		MinDeviation:  minDeviation,
+		SportCode:     params.Sport,
+		LeagueName:    params.League,
+		LimitCount:    int32(params.PerPage),
+		OffsetCount:   params.offset(),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list best prices")
+		http.Error(w, "Failed to retrieve best prices", http.StatusInternalServerError)
+		return
+	}
+
+	prices := make([]BestPrice, 0, len(rows))
+	for _, row := range rows {
+		var marketParams models.MarketParams
+		if len(row.MarketParams) > 0 {
+			_ = json.Unmarshal(row.MarketParams, &marketParams)
+		}
+
+		prices = append(prices, BestPrice{
+			EventSlug:      row.EventSlug,
+			Match:          row.MatchName,
+			Sport:          row.SportCode,
+			League:         row.LeagueName,
+			EventDate:      row.EventDate.Time,
+			MarketCode:     row.MarketCode,
+			MarketName:     models.FormatMarketName(row.MarketName, marketParams),
+			Outcome:        row.Outcome,
+			IddaaOdds:      row.IddaaOdds,
+			MarketOdds:     row.MedianOdds,
+			BestOdds:       row.BestOdds,
+			BestBookmaker:  row.BestBookmaker,
+			BookmakerCount: row.BookmakerCount,
+			Deviation:      row.DeviationPercentage,
+			IddaaIsBest:    row.IddaaOdds > row.BestOdds,
+		})
+	}
+
+	h.writePage(w, prices, params, total)
+}
@@ -0,0 +1,257 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/models/api"
+)
+
+// CLVResponse compares how alerts and steam moves priced against the close
+type CLVResponse struct {
+	Since        time.Time         `json:"since"`
+	Baseline     CLVBucket         `json:"baseline"`
+	SteamMoves   CLVBucket         `json:"steam_moves"`
+	Alerts       []AlertCLVSummary `json:"alerts"`
+	RecentAlerts []AlertCLV        `json:"recent_alerts"`
+}
+
+// CLVBucket aggregates closing line value over a set of prices
+type CLVBucket struct {
+	Count             int     `json:"count"`
+	BeatClose         int     `json:"beat_close"`
+	BeatCloseRate     float64 `json:"beat_close_rate"`
+	AvgCLVPercentage  float64 `json:"avg_clv_percentage"`
+	AvgCLVProbability float64 `json:"avg_clv_probability"`
+}
+
+// AlertCLVSummary aggregates CLV for one alert type and price direction
+type AlertCLVSummary struct {
+	AlertType           string  `json:"alert_type"`
+	Direction           string  `json:"direction"`
+	MedianCLVPercentage float64 `json:"median_clv_percentage"`
+	CLVBucket
+}
+
+// AlertCLV is the closing line value of a single alert
+type AlertCLV struct {
+	AlertID          int32     `json:"alert_id"`
+	AlertType        string    `json:"alert_type"`
+	Severity         string    `json:"severity"`
+	Title            string    `json:"title"`
+	EventSlug        string    `json:"event_slug"`
+	MarketName       string    `json:"market_name"`
+	Outcome          string    `json:"outcome"`
+	ChangePercentage float32   `json:"change_percentage"`
+	ConfidenceScore  float32   `json:"confidence_score"`
+	AlertOdds        float64   `json:"alert_odds"`
+	ClosingOdds      float64   `json:"closing_odds"`
+	CLVPercentage    float32   `json:"clv_percentage"`
+	CLVProbability   float32   `json:"clv_probability"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// ClosingLine is a frozen closing price for one outcome
+type ClosingLine struct {
+	MarketTypeID int32     `json:"market_type_id"`
+	MarketName   string    `json:"market_name"`
+	Outcome      string    `json:"outcome"`
+	OpeningOdds  *float64  `json:"opening_odds,omitempty"`
+	ClosingOdds  float64   `json:"closing_odds"`
+	RecordedAt   time.Time `json:"recorded_at"`
+}
+
+// CLV handles GET /api/analytics/clv
+// With ?event={slug} it returns the frozen closing lines of that event instead
+func (h *Handler) CLV(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	if slug := r.URL.Query().Get("event"); slug != "" {
+		h.eventClosingLines(ctx, w, slug)
+		return
+	}
+
+	days := 30
+	if d := r.URL.Query().Get("days"); d != "" {
+		if parsed, err := strconv.Atoi(d); err == nil && parsed >= 1 && parsed <= 365 {
+			days = parsed
+		}
+	}
+
+	limit := 50
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed >= 0 && parsed <= 500 {
+			limit = parsed
+		}
+	}
+
+	sportCode := r.URL.Query().Get("sport")
+	alertType := r.URL.Query().Get("alert_type")
+	since := time.Now().AddDate(0, 0, -days)
+	sinceTime := pgtype.Timestamp{Time: since, Valid: true}
+
+	baseline, err := h.queries.GetSnapshotCLVBaseline(ctx, generated.GetSnapshotCLVBaselineParams{
+		SinceTime: sinceTime,
+		SportCode: sportCode,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to get CLV baseline")
+		http.Error(w, "Failed to retrieve CLV", http.StatusInternalServerError)
+		return
+	}
+
+	steam, err := h.queries.GetSteamMoveCLVSummary(ctx, generated.GetSteamMoveCLVSummaryParams{
+		SinceTime: sinceTime,
+		SportCode: sportCode,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to get steam move CLV")
+		http.Error(w, "Failed to retrieve CLV", http.StatusInternalServerError)
+		return
+	}
+
+	summaries, err := h.queries.GetAlertCLVSummary(ctx, generated.GetAlertCLVSummaryParams{
+		SinceTime: sinceTime,
+		SportCode: sportCode,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to get alert CLV summary")
+		http.Error(w, "Failed to retrieve CLV", http.StatusInternalServerError)
+		return
+	}
+
+	response := CLVResponse{
+		Since:        since,
+		Baseline:     newCLVBucket(baseline.Snapshots, baseline.BeatClose, baseline.AvgClvPercentage, baseline.AvgClvProbability),
+		SteamMoves:   newCLVBucket(steam.Moves, steam.BeatClose, steam.AvgClvPercentage, steam.AvgClvProbability),
+		Alerts:       make([]AlertCLVSummary, 0, len(summaries)),
+		RecentAlerts: []AlertCLV{},
+	}
+
+	for _, s := range summaries {
+		if alertType != "" && s.AlertType != alertType {
+			continue
+		}
+		response.Alerts = append(response.Alerts, AlertCLVSummary{
+			AlertType:           s.AlertType,
+			Direction:           s.Direction,
+			MedianCLVPercentage: s.MedianClvPercentage,
+			CLVBucket:           newCLVBucket(s.Alerts, s.BeatClose, s.AvgClvPercentage, s.AvgClvProbability),
+		})
+	}
+
+	if limit > 0 {
+		recent, err := h.queries.GetRecentAlertCLVs(ctx, generated.GetRecentAlertCLVsParams{
+			SinceTime:  sinceTime,
+			AlertType:  alertType,
+			SportCode:  sportCode,
+			LimitCount: int32(limit),
+		})
+		if err != nil {
+			h.logger.Error().Err(err).Msg("Failed to get recent alert CLVs")
+			http.Error(w, "Failed to retrieve CLV", http.StatusInternalServerError)
+			return
+		}
+
+		for _, a := range recent {
+			response.RecentAlerts = append(response.RecentAlerts, AlertCLV{
+				AlertID:          a.AlertID,
+				AlertType:        a.AlertType,
+				Severity:         a.Severity,
+				Title:            a.Title,
+				EventSlug:        a.EventSlug,
+				MarketName:       a.MarketName,
+				Outcome:          a.Outcome,
+				ChangePercentage: a.ChangePercentage,
+				ConfidenceScore:  a.ConfidenceScore,
+				AlertOdds:        a.OddsValue,
+				ClosingOdds:      a.ClosingValue,
+				CLVPercentage:    a.ClvPercentage,
+				CLVProbability:   a.ClvProbability,
+				CreatedAt:        a.CreatedAt.Time,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.Response{
+		Success: true,
+		Data:    response,
+		Meta: map[string]any{
+			"days":       days,
+			"sport":      sportCode,
+			"alert_type": alertType,
+		},
+	}); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) eventClosingLines(ctx context.Context, w http.ResponseWriter, slug string) {
+	event, err := h.queries.GetEventBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to get event")
+		http.Error(w, "Failed to retrieve event", http.StatusInternalServerError)
+		return
+	}
+
+	closing, err := h.queries.GetClosingOddsByEvent(ctx, event.ID)
+	if err != nil {
+		h.logger.Error().Err(err).Int32("event_id", event.ID).Msg("Failed to get closing odds")
+		http.Error(w, "Failed to retrieve closing lines", http.StatusInternalServerError)
+		return
+	}
+
+	lines := make([]ClosingLine, 0, len(closing))
+	for _, c := range closing {
+		lines = append(lines, ClosingLine{
+			MarketTypeID: c.MarketTypeID,
+			MarketName:   c.MarketName,
+			Outcome:      c.Outcome,
+			OpeningOdds:  c.OpeningValue,
+			ClosingOdds:  c.ClosingValue,
+			RecordedAt:   c.ClosingRecordedAt.Time,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.Response{
+		Success: true,
+		Data:    lines,
+		Meta: map[string]any{
+			"event":  slug,
+			"status": event.Status,
+			"total":  len(lines),
+		},
+	}); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func newCLVBucket(count, beatClose int32, avgPercentage, avgProbability float64) CLVBucket {
+	bucket := CLVBucket{
+		Count:             int(count),
+		BeatClose:         int(beatClose),
+		AvgCLVPercentage:  avgPercentage,
+		AvgCLVProbability: avgProbability,
+	}
+	if count > 0 {
+		bucket.BeatCloseRate = float64(beatClose) / float64(count) * 100
+	}
+	return bucket
+}
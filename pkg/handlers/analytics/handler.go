@@ -0,0 +1,120 @@
+package analytics
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/models/api"
+)
+
+// Handler handles analytics endpoints
+type Handler struct {
+	queries *generated.Queries
+	logger  *logger.Logger
+}
+
+// NewHandler creates a new analytics handler
+func NewHandler(queries *generated.Queries, log *logger.Logger) *Handler {
+	return &Handler{
+		queries: queries,
+		logger:  log,
+	}
+}
+
+// strengthLevels maps the strength filter to a level, higher is stronger
+var strengthLevels = map[string]int{
+	"mild":     1,
+	"moderate": 2,
+	"strong":   3,
+	"extreme":  4,
+}
+
+// listParams holds the filters shared by the paginated analytics endpoints
+type listParams struct {
+	Sport    string
+	League   string
+	Strength int
+	Hours    int
+	Page     int
+	PerPage  int
+}
+
+// parseListParams reads sport, league, strength, hours, page and per_page, falling back to defaults
+func parseListParams(r *http.Request, defaultHours, maxHours int) listParams {
+	query := r.URL.Query()
+	params := listParams{
+		Sport:    query.Get("sport"),
+		League:   query.Get("league"),
+		Strength: strengthLevels[strings.ToLower(query.Get("strength"))],
+		Hours:    defaultHours,
+		Page:     1,
+		PerPage:  20,
+	}
+
+	if hours, err := strconv.Atoi(query.Get("hours")); err == nil && hours >= 1 && hours <= maxHours {
+		params.Hours = hours
+	}
+	if page, err := strconv.Atoi(query.Get("page")); err == nil && page >= 1 {
+		params.Page = page
+	}
+	if perPage, err := strconv.Atoi(query.Get("per_page")); err == nil && perPage >= 1 && perPage <= 100 {
+		params.PerPage = perPage
+	}
+
+	return params
+}
+
+func (p listParams) offset() int32 {
+	return int32((p.Page - 1) * p.PerPage)
+}
+
+// window returns now and now plus the hours filter, for endpoints looking ahead
+func (p listParams) window() (pgtype.Timestamp, pgtype.Timestamp) {
+	now := time.Now()
+	return pgtype.Timestamp{Time: now, Valid: true},
+		pgtype.Timestamp{Time: now.Add(time.Duration(p.Hours) * time.Hour), Valid: true}
+}
+
+// since returns now minus the hours filter, for endpoints looking back
+func (p listParams) since() pgtype.Timestamp {
+	return pgtype.Timestamp{Time: time.Now().Add(-time.Duration(p.Hours) * time.Hour), Valid: true}
+}
+
+func (p listParams) pagination(total int32) api.PaginationInfo {
+	totalPages := (int(total) + p.PerPage - 1) / p.PerPage
+	return api.PaginationInfo{
+		Page:        p.Page,
+		PerPage:     p.PerPage,
+		Total:       int(total),
+		TotalPages:  totalPages,
+		HasNext:     p.Page < totalPages,
+		HasPrevious: p.Page > 1,
+	}
+}
+
+// writePage encodes a page of results with its pagination metadata
+func (h *Handler) writePage(w http.ResponseWriter, data any, params listParams, total int32) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.Response{
+		Success: true,
+		Data:    data,
+		Meta:    params.pagination(total),
+	}); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func timePtr(ts pgtype.Timestamp) *time.Time {
+	if !ts.Valid {
+		return nil
+	}
+	return &ts.Time
+}
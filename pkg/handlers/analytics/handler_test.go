@@ -0,0 +1,138 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/models/api"
+)
+
+// analyticsDB records the arguments of every query by name. Counts return total, events are found
+// with eventID unless eventErr is set, and lists return no rows.
+type analyticsDB struct {
+	total    int32
+	eventID  int32
+	eventErr error
+	queries  map[string][]any
+}
+
+func newAnalyticsDB() *analyticsDB {
+	return &analyticsDB{queries: map[string][]any{}}
+}
+
+func (d *analyticsDB) record(sql string, args []any) string {
+	name := strings.Fields(strings.TrimPrefix(sql, "-- name: "))[0]
+	d.queries[name] = args
+	return name
+}
+
+func (d *analyticsDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("unexpected Exec")
+}
+
+func (d *analyticsDB) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	d.record(sql, args)
+	return emptyRows{}, nil
+}
+
+func (d *analyticsDB) QueryRow(_ context.Context, sql string, args ...interface{}) pgx.Row {
+	switch name := d.record(sql, args); {
+	case name == "GetEventBySlug":
+		return int32Row{value: d.eventID, err: d.eventErr}
+	case strings.HasPrefix(name, "Count"):
+		return int32Row{value: d.total}
+	default:
+		return int32Row{err: errors.New("unexpected QueryRow " + name)}
+	}
+}
+
+// int32Row scans a count, or an event that only has an id
+type int32Row struct {
+	value int32
+	err   error
+}
+
+func (r int32Row) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*int32) = r.value
+	return nil
+}
+
+// emptyRows is a result without rows
+type emptyRows struct {
+	pgx.Rows
+}
+
+func (emptyRows) Next() bool { return false }
+func (emptyRows) Err() error { return nil }
+func (emptyRows) Close()     {}
+
+func serveAnalytics(db *analyticsDB, handle func(*Handler, http.ResponseWriter, *http.Request), target string) *httptest.ResponseRecorder {
+	handler := NewHandler(generated.New(db), logger.New("analytics-test"))
+	recorder := httptest.NewRecorder()
+	handle(handler, recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder
+}
+
+// decodePagination reads the pagination metadata of a page response
+func decodePagination(t *testing.T, recorder *httptest.ResponseRecorder) api.PaginationInfo {
+	t.Helper()
+	var response struct {
+		Success bool               `json:"success"`
+		Meta    api.PaginationInfo `json:"meta"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !response.Success {
+		t.Fatal("response is not successful")
+	}
+	return response.Meta
+}
+
+func TestParseListParams(t *testing.T) {
+	tests := []struct {
+		query string
+		want  listParams
+	}{
+		{query: "", want: listParams{Hours: 48, Page: 1, PerPage: 20}},
+		{query: "?sport=FOOTBALL&league=Premier&strength=Strong", want: listParams{Sport: "FOOTBALL", League: "Premier", Strength: 3, Hours: 48, Page: 1, PerPage: 20}},
+		{query: "?strength=huge", want: listParams{Hours: 48, Page: 1, PerPage: 20}},
+		{query: "?hours=12&page=3&per_page=50", want: listParams{Hours: 12, Page: 3, PerPage: 50}},
+		{query: "?hours=169&page=0&per_page=101", want: listParams{Hours: 48, Page: 1, PerPage: 20}},
+		{query: "?hours=0&page=-1&per_page=0", want: listParams{Hours: 48, Page: 1, PerPage: 20}},
+		{query: "?hours=abc&page=two&per_page=x", want: listParams{Hours: 48, Page: 1, PerPage: 20}},
+	}
+
+	for _, tt := range tests {
+		got := parseListParams(httptest.NewRequest(http.MethodGet, "/api/analytics/contrarian-bets"+tt.query, nil), 48, 168)
+		if got != tt.want {
+			t.Errorf("parseListParams(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestListParamsPagination(t *testing.T) {
+	params := listParams{Page: 2, PerPage: 20}
+	if offset := params.offset(); offset != 20 {
+		t.Errorf("offset = %d, want 20", offset)
+	}
+
+	got := params.pagination(45)
+	want := api.PaginationInfo{Page: 2, PerPage: 20, Total: 45, TotalPages: 3, HasNext: true, HasPrevious: true}
+	if got != want {
+		t.Errorf("pagination = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// LeagueMarketMargin summarises a bookmaker's margin on one market type across a league's events
+type LeagueMarketMargin struct {
+	League       string  `json:"league"`
+	Sport        string  `json:"sport"`
+	MarketCode   string  `json:"market_code"`
+	MarketName   string  `json:"market_name"`
+	EventCount   int32   `json:"event_count"`
+	MarketCount  int32   `json:"market_count"`
+	AvgMargin    float64 `json:"avg_margin"`
+	MedianMargin float64 `json:"median_margin"`
+	MinMargin    float64 `json:"min_margin"`
+	MaxMargin    float64 `json:"max_margin"`
+}
+
+// Margins handles GET /api/analytics/margins
+// Filters: sport, league, market (market type code), bookmaker (default iddaa),
+// hours since margins were last calculated (default 168)
+func (h *Handler) Margins(w http.ResponseWriter, r *http.Request) {
+	params := parseListParams(r, 168, 720)
+
+	bookmaker := r.URL.Query().Get("bookmaker")
+	if bookmaker == "" {
+		bookmaker = services.IddaaBookmaker
+	}
+	marketCode := r.URL.Query().Get("market")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	total, err := h.queries.CountLeagueMarketMargins(ctx, generated.CountLeagueMarketMarginsParams{
+		Bookmaker:  bookmaker,
+		SinceTime:  params.since(),
+		SportCode:  params.Sport,
+		LeagueName: params.League,
+		MarketCode: marketCode,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to count league margins")
+		http.Error(w, "Failed to retrieve margins", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := h.queries.GetLeagueMarketMargins(ctx, generated.GetLeagueMarketMarginsParams{
+		Bookmaker:   bookmaker,
+		SinceTime:   params.since(),
+		SportCode:   params.Sport,
+		LeagueName:  params.League,
+		MarketCode:  marketCode,
+		LimitCount:  int32(params.PerPage),
+		OffsetCount: params.offset(),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list league margins")
+		http.Error(w, "Failed to retrieve margins", http.StatusInternalServerError)
+		return
+	}
+
+	margins := make([]LeagueMarketMargin, 0, len(rows))
+	for _, row := range rows {
+		margins = append(margins, LeagueMarketMargin{
+			League:       row.LeagueName,
+			Sport:        row.SportCode,
+			MarketCode:   row.MarketCode,
+			MarketName:   row.MarketName,
+			EventCount:   row.EventCount,
+			MarketCount:  row.MarketCount,
+			AvgMargin:    row.AvgMargin,
+			MedianMargin: row.MedianMargin,
+			MinMargin:    row.MinMargin,
+			MaxMargin:    row.MaxMargin,
+		})
+	}
+
+	h.writePage(w, margins, params, total)
+}
@@ -0,0 +1,363 @@
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+// liveMovementByStrength is the minimum absolute in-play movement (%) per strength level
+var liveMovementByStrength = map[int]float64{0: 10, 1: 10, 2: 15, 3: 25, 4: 40}
+
+// suspiciousFactorByStrength is the minimum odds multiplier (either direction) per strength level
+var suspiciousFactorByStrength = map[int]float64{0: 1.5, 1: 1.5, 2: 2, 3: 3, 4: 5}
+
+// ContrarianBet is an outcome the public overbets relative to its odds
+type ContrarianBet struct {
+	EventID           int32     `json:"event_id"`
+	EventSlug         string    `json:"event_slug"`
+	Sport             string    `json:"sport"`
+	SportSlug         string    `json:"sport_slug"`
+	League            string    `json:"league"`
+	Country           *string   `json:"country,omitempty"`
+	HomeTeam          string    `json:"home_team"`
+	AwayTeam          string    `json:"away_team"`
+	EventDate         time.Time `json:"event_date"`
+	HoursToKickoff    float64   `json:"hours_to_kickoff"`
+	MarketName        string    `json:"market_name"`
+	MarketSlug        string    `json:"market_slug"`
+	PublicChoice      string    `json:"public_choice"`
+	PublicPercentage  float32   `json:"public_percentage"`
+	CurrentOdds       float64   `json:"current_odds"`
+	OpeningOdds       *float64  `json:"opening_odds,omitempty"`
+	OddsMovement      *float32  `json:"odds_movement,omitempty"`
+	OverbetPercentage float32   `json:"overbet_percentage"`
+	SignalStrength    string    `json:"signal_strength"`
+	ContrarianPlay    string    `json:"contrarian_play"`
+	RefreshedAt       time.Time `json:"refreshed_at"`
+}
+
+// LiveOpportunity is an in-play outcome whose odds moved sharply
+type LiveOpportunity struct {
+	EventID                 int32     `json:"event_id"`
+	EventSlug               string    `json:"event_slug"`
+	Sport                   string    `json:"sport"`
+	SportSlug               string    `json:"sport_slug"`
+	League                  string    `json:"league"`
+	HomeTeam                string    `json:"home_team"`
+	AwayTeam                string    `json:"away_team"`
+	HomeScore               *int32    `json:"home_score,omitempty"`
+	AwayScore               *int32    `json:"away_score,omitempty"`
+	MinuteOfMatch           *int32    `json:"minute_of_match,omitempty"`
+	Half                    *int32    `json:"half,omitempty"`
+	MarketName              string    `json:"market_name"`
+	MarketSlug              string    `json:"market_slug"`
+	Outcome                 string    `json:"outcome"`
+	CurrentOdds             float64   `json:"current_odds"`
+	PreMatchOdds            *float64  `json:"pre_match_odds,omitempty"`
+	TotalMovement           *float32  `json:"total_movement,omitempty"`
+	CurrentBacking          float32   `json:"current_backing"`
+	BettingVolumePercentage *float32  `json:"betting_volume_percentage,omitempty"`
+	OpportunityType         string    `json:"opportunity_type"`
+	LastUpdated             time.Time `json:"last_updated"`
+}
+
+// HighVolumeEvent is an upcoming or live event drawing a large share of betting volume
+type HighVolumeEvent struct {
+	EventID                 int32      `json:"event_id"`
+	EventSlug               string     `json:"event_slug"`
+	Sport                   string     `json:"sport"`
+	SportSlug               string     `json:"sport_slug"`
+	League                  string     `json:"league"`
+	HomeTeam                string     `json:"home_team"`
+	AwayTeam                string     `json:"away_team"`
+	EventDate               time.Time  `json:"event_date"`
+	Status                  string     `json:"status"`
+	BettingVolumePercentage *float32   `json:"betting_volume_percentage,omitempty"`
+	VolumeRank              *int32     `json:"volume_rank,omitempty"`
+	RecentVolumeChange      float64    `json:"recent_volume_change"`
+	VolumeUpdatedAt         *time.Time `json:"volume_updated_at,omitempty"`
+	VolumeCategory          string     `json:"volume_category"`
+}
+
+// SuspiciousMovement is a single odds change far larger than usual
+type SuspiciousMovement struct {
+	OddsHistoryID       int32     `json:"odds_history_id"`
+	EventSlug           string    `json:"event_slug"`
+	Match               string    `json:"match"`
+	Sport               string    `json:"sport"`
+	League              string    `json:"league"`
+	EventDate           time.Time `json:"event_date"`
+	MarketCode          string    `json:"market_code"`
+	MarketName          string    `json:"market_name"`
+	Outcome             string    `json:"outcome"`
+	Odds                float64   `json:"odds"`
+	PreviousOdds        *float64  `json:"previous_odds,omitempty"`
+	ChangePercentage    *float32  `json:"change_percentage,omitempty"`
+	Multiplier          *float64  `json:"multiplier,omitempty"`
+	MovementFactor      float64   `json:"movement_factor"`
+	SharpMoneyIndicator *float32  `json:"sharp_money_indicator,omitempty"`
+	IsReverseMovement   *bool     `json:"is_reverse_movement,omitempty"`
+	MinutesToKickoff    *int32    `json:"minutes_to_kickoff,omitempty"`
+	RecordedAt          time.Time `json:"recorded_at"`
+}
+
+// ContrarianBets handles GET /api/analytics/contrarian-bets
+// Filters: sport, league, strength (mild|moderate|strong|extreme), hours until kickoff (default 48)
+func (h *Handler) ContrarianBets(w http.ResponseWriter, r *http.Request) {
+	params := parseListParams(r, 48, 168)
+	from, to := params.window()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	total, err := h.queries.CountContrarianBets(ctx, generated.CountContrarianBetsParams{
+		FromTime:    from,
+		ToTime:      to,
+		SportCode:   params.Sport,
+		LeagueName:  params.League,
+		MinStrength: int32(params.Strength),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to count contrarian bets")
+		http.Error(w, "Failed to retrieve contrarian bets", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := h.queries.ListContrarianBets(ctx, generated.ListContrarianBetsParams{
+		FromTime:    from,
+		ToTime:      to,
+		SportCode:   params.Sport,
+		LeagueName:  params.League,
+		MinStrength: int32(params.Strength),
+		LimitCount:  int32(params.PerPage),
+		OffsetCount: params.offset(),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list contrarian bets")
+		http.Error(w, "Failed to retrieve contrarian bets", http.StatusInternalServerError)
+		return
+	}
+
+	bets := make([]ContrarianBet, 0, len(rows))
+	for _, row := range rows {
+		bets = append(bets, ContrarianBet{
+			EventID:           row.EventID,
+			EventSlug:         row.EventSlug,
+			Sport:             row.SportName,
+			SportSlug:         row.SportSlug,
+			League:            row.LeagueName,
+			Country:           row.Country,
+			HomeTeam:          row.HomeTeam,
+			AwayTeam:          row.AwayTeam,
+			EventDate:         row.EventDate.Time,
+			HoursToKickoff:    time.Until(row.EventDate.Time).Hours(),
+			MarketName:        row.MarketName,
+			MarketSlug:        row.MarketSlug,
+			PublicChoice:      row.PublicChoice,
+			PublicPercentage:  row.PublicPercentage,
+			CurrentOdds:       row.CurrentOdds,
+			OpeningOdds:       row.OpeningOdds,
+			OddsMovement:      row.OddsMovement,
+			OverbetPercentage: row.OverbetPercentage,
+			SignalStrength:    row.SignalStrength,
+			ContrarianPlay:    row.ContrarianPlay,
+			RefreshedAt:       row.LastRefreshed.Time,
+		})
+	}
+
+	h.writePage(w, bets, params, total)
+}
+
+// LiveOpportunities handles GET /api/analytics/live-opportunities
+// Filters: sport, league, strength (minimum in-play movement), hours since the odds last updated (default 1)
+func (h *Handler) LiveOpportunities(w http.ResponseWriter, r *http.Request) {
+	params := parseListParams(r, 1, 24)
+	since := params.since()
+	minMovement := liveMovementByStrength[params.Strength]
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	total, err := h.queries.CountLiveOpportunities(ctx, generated.CountLiveOpportunitiesParams{
+		SinceTime:   since,
+		MinMovement: minMovement,
+		SportCode:   params.Sport,
+		LeagueName:  params.League,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to count live opportunities")
+		http.Error(w, "Failed to retrieve live opportunities", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := h.queries.ListLiveOpportunities(ctx, generated.ListLiveOpportunitiesParams{
+		SinceTime:   since,
+		MinMovement: minMovement,
+		SportCode:   params.Sport,
+		LeagueName:  params.League,
+		LimitCount:  int32(params.PerPage),
+		OffsetCount: params.offset(),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list live opportunities")
+		http.Error(w, "Failed to retrieve live opportunities", http.StatusInternalServerError)
+		return
+	}
+
+	opportunities := make([]LiveOpportunity, 0, len(rows))
+	for _, row := range rows {
+		opportunities = append(opportunities, LiveOpportunity{
+			EventID:                 row.EventID,
+			EventSlug:               row.EventSlug,
+			Sport:                   row.SportName,
+			SportSlug:               row.SportSlug,
+			League:                  row.LeagueName,
+			HomeTeam:                row.HomeTeam,
+			AwayTeam:                row.AwayTeam,
+			HomeScore:               row.HomeScore,
+			AwayScore:               row.AwayScore,
+			MinuteOfMatch:           row.MinuteOfMatch,
+			Half:                    row.Half,
+			MarketName:              row.MarketName,
+			MarketSlug:              row.MarketSlug,
+			Outcome:                 row.Outcome,
+			CurrentOdds:             row.CurrentOdds,
+			PreMatchOdds:            row.PreMatchOdds,
+			TotalMovement:           row.TotalMovement,
+			CurrentBacking:          row.CurrentBacking,
+			BettingVolumePercentage: row.BettingVolumePercentage,
+			OpportunityType:         row.OpportunityType,
+			LastUpdated:             row.LastUpdated.Time,
+		})
+	}
+
+	h.writePage(w, opportunities, params, total)
+}
+
+// HighVolumeEvents handles GET /api/analytics/high-volume-events
+// Filters: sport, league, strength (volume category), hours until kickoff (default 48)
+func (h *Handler) HighVolumeEvents(w http.ResponseWriter, r *http.Request) {
+	params := parseListParams(r, 48, 168)
+	from, to := params.window()
+	// Live events stay in the view after kickoff
+	from.Time = from.Time.Add(-3 * time.Hour)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	total, err := h.queries.CountHighVolumeEvents(ctx, generated.CountHighVolumeEventsParams{
+		FromTime:    from,
+		ToTime:      to,
+		SportCode:   params.Sport,
+		LeagueName:  params.League,
+		MinStrength: int32(params.Strength),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to count high volume events")
+		http.Error(w, "Failed to retrieve high volume events", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := h.queries.ListHighVolumeEvents(ctx, generated.ListHighVolumeEventsParams{
+		FromTime:    from,
+		ToTime:      to,
+		SportCode:   params.Sport,
+		LeagueName:  params.League,
+		MinStrength: int32(params.Strength),
+		LimitCount:  int32(params.PerPage),
+		OffsetCount: params.offset(),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list high volume events")
+		http.Error(w, "Failed to retrieve high volume events", http.StatusInternalServerError)
+		return
+	}
+
+	events := make([]HighVolumeEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, HighVolumeEvent{
+			EventID:                 row.EventID,
+			EventSlug:               row.EventSlug,
+			Sport:                   row.SportName,
+			SportSlug:               row.SportSlug,
+			League:                  row.LeagueName,
+			HomeTeam:                row.HomeTeam,
+			AwayTeam:                row.AwayTeam,
+			EventDate:               row.EventDate.Time,
+			Status:                  row.Status,
+			BettingVolumePercentage: row.BettingVolumePercentage,
+			VolumeRank:              row.VolumeRank,
+			RecentVolumeChange:      row.RecentVolumeChange,
+			VolumeUpdatedAt:         timePtr(row.VolumeUpdatedAt),
+			VolumeCategory:          row.VolumeCategory,
+		})
+	}
+
+	h.writePage(w, events, params, total)
+}
+
+// SuspiciousMovements handles GET /api/analytics/suspicious-movements
+// Filters: sport, league, strength (minimum odds multiplier), hours back (default 24)
+func (h *Handler) SuspiciousMovements(w http.ResponseWriter, r *http.Request) {
+	params := parseListParams(r, 24, 168)
+	since := params.since()
+	minFactor := suspiciousFactorByStrength[params.Strength]
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	total, err := h.queries.CountSuspiciousMovements(ctx, generated.CountSuspiciousMovementsParams{
+		MinFactor:  minFactor,
+		SinceTime:  since,
+		SportCode:  params.Sport,
+		LeagueName: params.League,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to count suspicious movements")
+		http.Error(w, "Failed to retrieve suspicious movements", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := h.queries.GetSuspiciousMovements(ctx, generated.GetSuspiciousMovementsParams{
+		MinFactor:   minFactor,
+		SinceTime:   since,
+		SportCode:   params.Sport,
+		LeagueName:  params.League,
+		LimitCount:  int32(params.PerPage),
+		OffsetCount: params.offset(),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to get suspicious movements")
+		http.Error(w, "Failed to retrieve suspicious movements", http.StatusInternalServerError)
+		return
+	}
+
+	movements := make([]SuspiciousMovement, 0, len(rows))
+	for _, row := range rows {
+		movements = append(movements, SuspiciousMovement{
+			OddsHistoryID:       row.ID,
+			EventSlug:           row.EventSlug,
+			Match:               row.MatchName,
+			Sport:               row.SportCode,
+			League:              row.LeagueName,
+			EventDate:           row.EventDate.Time,
+			MarketCode:          row.MarketCode,
+			MarketName:          row.MarketName,
+			Outcome:             row.Outcome,
+			Odds:                row.OddsValue,
+			PreviousOdds:        row.PreviousValue,
+			ChangePercentage:    row.ChangePercentage,
+			Multiplier:          row.Multiplier,
+			MovementFactor:      row.MovementFactor,
+			SharpMoneyIndicator: row.SharpMoneyIndicator,
+			IsReverseMovement:   row.IsReverseMovement,
+			MinutesToKickoff:    row.MinutesToKickoff,
+			RecordedAt:          row.RecordedAt.Time,
+		})
+	}
+
+	h.writePage(w, movements, params, total)
+}
@@ -0,0 +1,157 @@
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+func TestSuspiciousMovements_AppliesFilters(t *testing.T) {
+	db := newAnalyticsDB()
+	db.total = 45
+	recorder := serveAnalytics(db, (*Handler).SuspiciousMovements,
+		"/api/analytics/suspicious-movements?sport=FOOTBALL&league=Premier&strength=strong&hours=6&page=2&per_page=20")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", recorder.Code, recorder.Body)
+	}
+
+	// min_factor, since_time, sport_code, league_name, limit_count, offset_count
+	args := db.queries["GetSuspiciousMovements"]
+	if len(args) != 6 {
+		t.Fatalf("GetSuspiciousMovements args = %v", args)
+	}
+	if args[0] != 3.0 || args[2] != "FOOTBALL" || args[3] != "Premier" || args[4] != int32(20) || args[5] != int32(20) {
+		t.Errorf("GetSuspiciousMovements args = %v, want factor 3, FOOTBALL, Premier, limit 20, offset 20", args)
+	}
+	since := args[1].(pgtype.Timestamp).Time
+	if ago := time.Since(since); ago < 6*time.Hour-time.Minute || ago > 6*time.Hour+time.Minute {
+		t.Errorf("since = %v ago, want 6h", ago)
+	}
+	if count := db.queries["CountSuspiciousMovements"]; len(count) != 4 || count[0] != 3.0 {
+		t.Errorf("CountSuspiciousMovements args = %v, want the same filters", count)
+	}
+
+	if meta := decodePagination(t, recorder); meta.Total != 45 || meta.TotalPages != 3 || !meta.HasNext {
+		t.Errorf("pagination = %+v, want 45 results over 3 pages", meta)
+	}
+}
+
+func TestSuspiciousMovements_DefaultsInvalidFilters(t *testing.T) {
+	db := newAnalyticsDB()
+	recorder := serveAnalytics(db, (*Handler).SuspiciousMovements,
+		"/api/analytics/suspicious-movements?strength=huge&hours=500&page=0&per_page=1000")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", recorder.Code, recorder.Body)
+	}
+
+	args := db.queries["GetSuspiciousMovements"]
+	if args[0] != 1.5 || args[4] != int32(20) || args[5] != int32(0) {
+		t.Errorf("GetSuspiciousMovements args = %v, want factor 1.5, limit 20, offset 0", args)
+	}
+	if ago := time.Since(args[1].(pgtype.Timestamp).Time); ago > 24*time.Hour+time.Minute {
+		t.Errorf("since = %v ago, want the 24h default", ago)
+	}
+}
+
+func TestLiveOpportunities_MovementByStrength(t *testing.T) {
+	for strength, want := range map[string]float64{"": 10, "moderate": 15, "extreme": 40} {
+		db := newAnalyticsDB()
+		serveAnalytics(db, (*Handler).LiveOpportunities, "/api/analytics/live-opportunities?strength="+strength)
+		// since_time, min_movement, ...
+		if args := db.queries["ListLiveOpportunities"]; len(args) < 2 || args[1] != want {
+			t.Errorf("strength %q: ListLiveOpportunities args = %v, want min movement %v", strength, args, want)
+		}
+	}
+}
+
+// TestGetSuspiciousMovements_OnlyWithinWindow guards the precedence of the movement and time filters,
+// a large movement recorded before since_time must not be returned. It needs a migrated database:
+//
+//	TEST_DATABASE_URL=postgres://... go test ./pkg/handlers/analytics -run GetSuspiciousMovements
+//
+// Rows are written for an existing event and market type inside a transaction that is rolled back.
+func TestGetSuspiciousMovements_OnlyWithinWindow(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer pool.Close()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var eventID, marketTypeID int32
+	err = tx.QueryRow(ctx, `
+		SELECT e.id, (SELECT id FROM market_types ORDER BY id LIMIT 1)
+		FROM events e
+		JOIN teams ht ON e.home_team_id = ht.id
+		JOIN teams at ON e.away_team_id = at.id
+		JOIN leagues l ON e.league_id = l.id
+		JOIN sports s ON e.sport_id = s.id
+		LIMIT 1`).Scan(&eventID, &marketTypeID)
+	if err != nil {
+		t.Skipf("no event and market type to attach odds history to: %v", err)
+	}
+
+	insert := func(multiplier float64, age string) int32 {
+		var id int32
+		err := tx.QueryRow(ctx, `
+			INSERT INTO odds_history (event_id, market_type_id, outcome, odds_value, previous_value, multiplier, recorded_at)
+			VALUES ($1, $2, 'precedence-test', $3, 1.0, $3, NOW()::timestamp - $4::interval)
+			RETURNING id`, eventID, marketTypeID, multiplier, age).Scan(&id)
+		if err != nil {
+			t.Fatalf("failed to insert odds history: %v", err)
+		}
+		return id
+	}
+	// Factors far above real movements so the rows lead the ordering
+	before := insert(500, "2 hours")
+	inside := insert(400, "5 minutes")
+	shortened := insert(1.0/300, "5 minutes")
+	small := insert(1.01, "5 minutes")
+
+	var since pgtype.Timestamp
+	if err := tx.QueryRow(ctx, `SELECT NOW()::timestamp - INTERVAL '1 hour'`).Scan(&since); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := generated.New(tx).GetSuspiciousMovements(ctx, generated.GetSuspiciousMovementsParams{
+		MinFactor:   2,
+		SinceTime:   since,
+		LimitCount:  10,
+		OffsetCount: 0,
+	})
+	if err != nil {
+		t.Fatalf("GetSuspiciousMovements error = %v", err)
+	}
+
+	found := make(map[int32]bool)
+	for _, row := range rows {
+		found[row.ID] = true
+	}
+	if found[before] {
+		t.Error("returned a movement recorded before since_time")
+	}
+	if found[small] {
+		t.Error("returned a movement below the minimum factor")
+	}
+	if !found[inside] || !found[shortened] {
+		t.Errorf("expected both large movements within the window, drifting %v, shortening %v", found[inside], found[shortened])
+	}
+}
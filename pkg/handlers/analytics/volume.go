@@ -0,0 +1,190 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/models/api"
+)
+
+// TopVolumeEvent is an upcoming event ranked by its share of betting volume
+type TopVolumeEvent struct {
+	EventSlug               string    `json:"event_slug"`
+	Match                   string    `json:"match"`
+	Sport                   string    `json:"sport"`
+	League                  string    `json:"league"`
+	EventDate               time.Time `json:"event_date"`
+	BettingVolumePercentage *float32  `json:"betting_volume_percentage,omitempty"`
+	VolumeRank              *int32    `json:"volume_rank,omitempty"`
+	TotalOddsChanges        int32     `json:"total_odds_changes"`
+	MaxMovement             float64   `json:"max_movement"`
+}
+
+// VolumeSnapshot is one recorded volume reading for an event
+type VolumeSnapshot struct {
+	VolumePercentage   float32   `json:"volume_percentage"`
+	RankPosition       *int32    `json:"rank_position,omitempty"`
+	TotalEventsTracked *int32    `json:"total_events_tracked,omitempty"`
+	PreviousVolume     *float32  `json:"previous_volume,omitempty"`
+	VolumeChange       *float32  `json:"volume_change,omitempty"`
+	RecordedAt         time.Time `json:"recorded_at"`
+}
+
+// TopVolumeEvents handles GET /api/analytics/top-volume-events
+// Filters: sport, league, max_rank (default 20), hours until kickoff (default 48)
+func (h *Handler) TopVolumeEvents(w http.ResponseWriter, r *http.Request) {
+	params := parseListParams(r, 48, 168)
+	_, to := params.window()
+
+	maxRank := int32(20)
+	if rank, err := strconv.Atoi(r.URL.Query().Get("max_rank")); err == nil && rank >= 1 && rank <= 1000 {
+		maxRank = int32(rank)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	total, err := h.queries.CountTopVolumeEvents(ctx, generated.CountTopVolumeEventsParams{
+		MaxRank:    maxRank,
+		ToTime:     to,
+		SportCode:  params.Sport,
+		LeagueName: params.League,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to count top volume events")
+		http.Error(w, "Failed to retrieve top volume events", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := h.queries.GetTopVolumeEvents(ctx, generated.GetTopVolumeEventsParams{
+		MaxRank:     maxRank,
+		ToTime:      to,
+		SportCode:   params.Sport,
+		LeagueName:  params.League,
+		LimitCount:  int32(params.PerPage),
+		OffsetCount: params.offset(),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to get top volume events")
+		http.Error(w, "Failed to retrieve top volume events", http.StatusInternalServerError)
+		return
+	}
+
+	events := make([]TopVolumeEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, TopVolumeEvent{
+			EventSlug:               row.Slug,
+			Match:                   row.MatchName,
+			Sport:                   row.SportCode,
+			League:                  row.LeagueName,
+			EventDate:               row.EventDate.Time,
+			BettingVolumePercentage: row.BettingVolumePercentage,
+			VolumeRank:              row.VolumeRank,
+			TotalOddsChanges:        row.TotalOddsChanges,
+			MaxMovement:             row.MaxMovement,
+		})
+	}
+
+	h.writePage(w, events, params, total)
+}
+
+// VolumeHistory handles GET /api/analytics/volume-history?event={slug}
+// Returns the event's volume readings newest first, hours back defaults to 72
+func (h *Handler) VolumeHistory(w http.ResponseWriter, r *http.Request) {
+	slug := r.URL.Query().Get("event")
+	if slug == "" {
+		http.Error(w, "event parameter is required", http.StatusBadRequest)
+		return
+	}
+	params := parseListParams(r, 72, 720)
+	since := params.since()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	event, err := h.queries.GetEventBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to get event")
+		http.Error(w, "Failed to retrieve event", http.StatusInternalServerError)
+		return
+	}
+
+	total, err := h.queries.CountVolumeHistory(ctx, generated.CountVolumeHistoryParams{
+		EventID:   event.ID,
+		SinceTime: since,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to count volume history")
+		http.Error(w, "Failed to retrieve volume history", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := h.queries.GetVolumeHistory(ctx, generated.GetVolumeHistoryParams{
+		EventID:     event.ID,
+		SinceTime:   since,
+		LimitCount:  int32(params.PerPage),
+		OffsetCount: params.offset(),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to get volume history")
+		http.Error(w, "Failed to retrieve volume history", http.StatusInternalServerError)
+		return
+	}
+
+	history := make([]VolumeSnapshot, 0, len(rows))
+	for _, row := range rows {
+		history = append(history, VolumeSnapshot{
+			VolumePercentage:   row.VolumePercentage,
+			RankPosition:       row.RankPosition,
+			TotalEventsTracked: row.TotalEventsTracked,
+			PreviousVolume:     row.PreviousVolume,
+			VolumeChange:       row.VolumeChange,
+			RecordedAt:         row.RecordedAt.Time,
+		})
+	}
+
+	h.writePage(w, history, params, total)
+}
+
+// VolumePatterns handles GET /api/analytics/volume-patterns
+// Groups recently ranked events by how their volume relates to odds movement
+func (h *Handler) VolumePatterns(w http.ResponseWriter, r *http.Request) {
+	params := parseListParams(r, 24, 168)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	patterns, err := h.queries.AnalyzeVolumeOddsPattern(ctx, generated.AnalyzeVolumeOddsPatternParams{
+		SinceTime: params.since(),
+		SportCode: params.Sport,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to analyze volume patterns")
+		http.Error(w, "Failed to analyze volume patterns", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.Response{
+		Success: true,
+		Data:    patterns,
+		Meta: map[string]any{
+			"hours": params.Hours,
+			"sport": params.Sport,
+		},
+	}); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,73 @@
+package analytics
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestVolumeHistory_RequiresEvent(t *testing.T) {
+	db := newAnalyticsDB()
+	if code := serveAnalytics(db, (*Handler).VolumeHistory, "/api/analytics/volume-history").Code; code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", code)
+	}
+	if len(db.queries) != 0 {
+		t.Errorf("ran queries %v without an event", db.queries)
+	}
+}
+
+func TestVolumeHistory_UnknownEvent(t *testing.T) {
+	db := newAnalyticsDB()
+	db.eventErr = pgx.ErrNoRows
+	if code := serveAnalytics(db, (*Handler).VolumeHistory, "/api/analytics/volume-history?event=missing").Code; code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", code)
+	}
+}
+
+func TestVolumeHistory_PagesEventHistory(t *testing.T) {
+	db := newAnalyticsDB()
+	db.eventID = 42
+	db.total = 7
+	recorder := serveAnalytics(db, (*Handler).VolumeHistory, "/api/analytics/volume-history?event=team-a-vs-team-b&hours=720&page=2&per_page=5")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", recorder.Code, recorder.Body)
+	}
+
+	// event_id, since_time, limit_count, offset_count
+	args := db.queries["GetVolumeHistory"]
+	if len(args) != 4 || args[0] != int32(42) || args[2] != int32(5) || args[3] != int32(5) {
+		t.Errorf("GetVolumeHistory args = %v, want event 42, limit 5, offset 5", args)
+	}
+	if meta := decodePagination(t, recorder); meta.Total != 7 || meta.TotalPages != 2 || meta.HasNext {
+		t.Errorf("pagination = %+v, want the last of 2 pages", meta)
+	}
+}
+
+func TestTopVolumeEvents_MaxRank(t *testing.T) {
+	tests := []struct {
+		query string
+		want  int32
+	}{
+		{query: "", want: 20},
+		{query: "?max_rank=50", want: 50},
+		{query: "?max_rank=0", want: 20},
+		{query: "?max_rank=1001", want: 20},
+		{query: "?max_rank=ten", want: 20},
+	}
+
+	for _, tt := range tests {
+		db := newAnalyticsDB()
+		recorder := serveAnalytics(db, (*Handler).TopVolumeEvents, "/api/analytics/top-volume-events"+tt.query)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%q: status = %d, want 200", tt.query, recorder.Code)
+		}
+		// max_rank, to_time, ...
+		if args := db.queries["GetTopVolumeEvents"]; len(args) == 0 || args[0] != tt.want {
+			t.Errorf("%q: GetTopVolumeEvents args = %v, want max rank %d", tt.query, args, tt.want)
+		}
+		if args := db.queries["CountTopVolumeEvents"]; len(args) == 0 || args[0] != tt.want {
+			t.Errorf("%q: CountTopVolumeEvents args = %v, want max rank %d", tt.query, args, tt.want)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/models"
+	"github.com/iddaa-lens/core/pkg/models/api"
+)
+
+// Detail handles the /api/events/{slug} endpoint
+func (h *Handler) Detail(w http.ResponseWriter, r *http.Request) {
+	slug := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/events/"), "/")
+	if slug == "" || strings.Contains(slug, "/") {
+		http.Error(w, "Invalid event slug", http.StatusBadRequest)
+		return
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	event, err := h.queries.GetEventBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error().
+			Err(err).
+			Str("action", "get_event_failed").
+			Str("slug", slug).
+			Msg("Failed to get event")
+
+		http.Error(w, "Failed to get event", http.StatusInternalServerError)
+		return
+	}
+
+	currentOdds, err := h.queries.GetCurrentOdds(ctx, event.ID)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("action", "get_current_odds_failed").
+			Str("slug", slug).
+			Msg("Failed to get current odds")
+
+		http.Error(w, "Failed to get current odds", http.StatusInternalServerError)
+		return
+	}
+
+	distributions, err := h.queries.GetAllDistributionsForEvents(ctx, []string{event.ExternalID})
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("action", "get_distributions_failed").
+			Str("slug", slug).
+			Msg("Failed to get outcome distributions")
+
+		http.Error(w, "Failed to get outcome distributions", http.StatusInternalServerError)
+		return
+	}
+
+	statistics, err := h.queries.GetMatchStatistics(ctx, &event.ID)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("action", "get_statistics_failed").
+			Str("slug", slug).
+			Msg("Failed to get match statistics")
+
+		http.Error(w, "Failed to get match statistics", http.StatusInternalServerError)
+		return
+	}
+
+	matchEvents, err := h.queries.GetMatchEvents(ctx, &event.ID)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("action", "get_match_events_failed").
+			Str("slug", slug).
+			Msg("Failed to get match events")
+
+		http.Error(w, "Failed to get match events", http.StatusInternalServerError)
+		return
+	}
+
+	response := api.EventDetailResponse{
+		EventResponse: convertEventDetail(event),
+		Markets:       groupCurrentOdds(currentOdds),
+		Distributions: make([]api.EventDistributionResponse, 0, len(distributions)),
+		Statistics:    make([]api.MatchStatisticResponse, 0, len(statistics)),
+		MatchEvents:   make([]api.MatchEventResponse, 0, len(matchEvents)),
+	}
+
+	if event.VolumeUpdatedAt.Valid {
+		volumeUpdatedAt := event.VolumeUpdatedAt.Time
+		response.VolumeUpdatedAt = &volumeUpdatedAt
+	}
+
+	for _, d := range distributions {
+		response.Distributions = append(response.Distributions, api.EventDistributionResponse{
+			MarketID:           d.MarketID,
+			MarketTypeID:       d.MarketTypeID,
+			Outcome:            d.Outcome,
+			BetPercentage:      float64(d.BetPercentage),
+			ImpliedProbability: float32Ptr(d.ImpliedProbability),
+			ValueIndicator:     float32Ptr(d.ValueIndicator),
+			LastUpdated:        d.LastUpdated.Time,
+		})
+	}
+
+	for _, s := range statistics {
+		response.Statistics = append(response.Statistics, api.MatchStatisticResponse{
+			IsHome:        s.IsHome,
+			Shots:         s.Shots,
+			ShotsOnTarget: s.ShotsOnTarget,
+			Possession:    s.Possession,
+			Corners:       s.Corners,
+			YellowCards:   s.YellowCards,
+			RedCards:      s.RedCards,
+			Fouls:         s.Fouls,
+			Offsides:      s.Offsides,
+			FreeKicks:     s.FreeKicks,
+			ThrowIns:      s.ThrowIns,
+			GoalKicks:     s.GoalKicks,
+			Saves:         s.Saves,
+		})
+	}
+
+	for _, e := range matchEvents {
+		response.MatchEvents = append(response.MatchEvents, api.MatchEventResponse{
+			Minute:      e.Minute,
+			EventType:   e.EventType,
+			Team:        e.Team,
+			Player:      e.Player,
+			Description: e.Description,
+			IsHome:      e.IsHome,
+		})
+	}
+
+	h.logger.Info().
+		Str("action", "event_detail_response").
+		Str("slug", slug).
+		Int("markets", len(response.Markets)).
+		Int("distributions", len(response.Distributions)).
+		Int("match_events", len(response.MatchEvents)).
+		Msg("Returning event detail")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// convertEventDetail converts the event row to the shared event response format
+func convertEventDetail(event generated.GetEventBySlugRow) api.EventResponse {
+	var bettingVolumePercentage *float64
+	if event.BettingVolumePercentage != nil {
+		volume := float64(*event.BettingVolumePercentage)
+		bettingVolumePercentage = &volume
+	}
+
+	return api.EventResponse{
+		ID:                      event.ID,
+		ExternalID:              event.ExternalID,
+		Slug:                    event.Slug,
+		EventDate:               event.EventDate.Time,
+		Status:                  event.Status,
+		HomeScore:               event.HomeScore,
+		AwayScore:               event.AwayScore,
+		IsLive:                  event.IsLive != nil && *event.IsLive,
+		MinuteOfMatch:           event.MinuteOfMatch,
+		Half:                    event.Half,
+		BettingVolumePercentage: bettingVolumePercentage,
+		VolumeRank:              event.VolumeRank,
+		HasKingOdd:              event.HasKingOdd != nil && *event.HasKingOdd,
+		OddsCount:               event.OddsCount,
+		HasCombine:              event.HasCombine != nil && *event.HasCombine,
+		HomeTeam:                event.HomeTeamName,
+		HomeTeamCountry:         stringValue(event.HomeTeamCountry),
+		AwayTeam:                event.AwayTeamName,
+		AwayTeamCountry:         stringValue(event.AwayTeamCountry),
+		League:                  event.LeagueName,
+		LeagueCountry:           stringValue(event.LeagueCountry),
+		Sport:                   event.SportName,
+		SportCode:               event.SportCode,
+		Match:                   fmt.Sprintf("%s vs %s", event.HomeTeamName, event.AwayTeamName),
+	}
+}
+
+// groupCurrentOdds groups current odds by market type and market parameters
+func groupCurrentOdds(odds []generated.GetCurrentOddsRow) []api.EventMarketResponse {
+	markets := []api.EventMarketResponse{}
+	index := make(map[string]int)
+
+	for _, odd := range odds {
+		if odd.MarketTypeID == nil {
+			continue
+		}
+
+		var params models.MarketParams
+		if len(odd.MarketParams) > 0 {
+			_ = json.Unmarshal(odd.MarketParams, &params)
+		}
+		if params.Values == nil {
+			params.Values = []string{}
+		}
+
+		key := fmt.Sprintf("%d|%s", *odd.MarketTypeID, strings.Join(params.Values, ","))
+		i, ok := index[key]
+		if !ok {
+			i = len(markets)
+			index[key] = i
+			markets = append(markets, api.EventMarketResponse{
+				MarketTypeID: *odd.MarketTypeID,
+				MarketCode:   odd.MarketCode,
+				MarketName:   models.FormatMarketName(odd.MarketName, params),
+				MarketParams: params.Values,
+				Outcomes:     []api.EventOutcomeResponse{},
+			})
+		}
+
+		markets[i].Outcomes = append(markets[i].Outcomes, api.EventOutcomeResponse{
+			Outcome:            odd.Outcome,
+			OddsValue:          odd.OddsValue,
+			OpeningValue:       odd.OpeningValue,
+			HighestValue:       odd.HighestValue,
+			LowestValue:        odd.LowestValue,
+			MovementPercentage: float32Ptr(odd.MovementPercentage),
+			LastUpdated:        odd.LastUpdated.Time,
+		})
+	}
+
+	// Keep a stable order so clients can diff responses
+	sort.SliceStable(markets, func(i, j int) bool {
+		if markets[i].MarketTypeID != markets[j].MarketTypeID {
+			return markets[i].MarketTypeID < markets[j].MarketTypeID
+		}
+		return strings.Join(markets[i].MarketParams, ",") < strings.Join(markets[j].MarketParams, ",")
+	})
+
+	return markets
+}
+
+// float32Ptr converts a nullable float32 into a nullable float64
+func float32Ptr(v *float32) *float64 {
+	if v == nil {
+		return nil
+	}
+	val := float64(*v)
+	return &val
+}
+
+// stringValue dereferences a nullable string
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
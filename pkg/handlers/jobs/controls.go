@@ -0,0 +1,236 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/robfig/cron/v3"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/models/api"
+)
+
+// ControlResponse is the desired runtime state of a job. Cron instances apply it within
+// JOB_CONTROL_INTERVAL seconds.
+type ControlResponse struct {
+	Name             string    `json:"name"`
+	Schedule         string    `json:"schedule"`
+	DefaultSchedule  string    `json:"default_schedule"`
+	ScheduleOverride *string   `json:"schedule_override,omitempty"`
+	Paused           bool      `json:"paused"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// TriggerResponse represents one manual run request
+type TriggerResponse struct {
+	ID          int64      `json:"id"`
+	JobName     string     `json:"job_name"`
+	Status      string     `json:"status"`
+	RequestedAt time.Time  `json:"requested_at"`
+	ClaimedBy   *string    `json:"claimed_by,omitempty"`
+	ClaimedAt   *time.Time `json:"claimed_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	RunID       *int64     `json:"run_id,omitempty"`
+	Error       *string    `json:"error,omitempty"`
+}
+
+// ScheduleRequest is the body of PUT /api/admin/jobs/{name}/schedule
+type ScheduleRequest struct {
+	Schedule string `json:"schedule"`
+}
+
+// Trigger handles POST /api/admin/jobs/{name}/trigger
+func (h *Handler) Trigger(w http.ResponseWriter, r *http.Request) {
+	name, _ := jobPath(r)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if _, err := h.queries.GetJobControl(ctx, name); err != nil {
+		h.controlError(w, err, name, "Failed to get job")
+		return
+	}
+
+	trigger, err := h.queries.CreateJobTrigger(ctx, name)
+	if err != nil {
+		// No row when a pending run exists, a unique violation when one was queued concurrently
+		if errors.Is(err, pgx.ErrNoRows) || isUniqueViolation(err) {
+			http.Error(w, "A run of this job is already pending", http.StatusConflict)
+			return
+		}
+		h.logger.Error().Err(err).Str("job_name", name).Msg("Failed to create job trigger")
+		http.Error(w, "Failed to trigger job", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info().
+		Int64("trigger_id", trigger.ID).
+		Str("job_name", name).
+		Msg("Job run triggered")
+
+	h.writeJSONStatus(w, http.StatusAccepted, api.Response{
+		Success: true,
+		Data:    toTriggerResponse(trigger),
+	})
+}
+
+// Triggers handles GET /api/admin/jobs/{name}/triggers
+func (h *Handler) Triggers(w http.ResponseWriter, r *http.Request) {
+	name, _ := jobPath(r)
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed >= 1 && parsed <= 500 {
+			limit = parsed
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	triggers, err := h.queries.ListJobTriggers(ctx, generated.ListJobTriggersParams{
+		JobName:    name,
+		LimitCount: int32(limit),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Str("job_name", name).Msg("Failed to list job triggers")
+		http.Error(w, "Failed to list job triggers", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]TriggerResponse, 0, len(triggers))
+	for _, trigger := range triggers {
+		response = append(response, toTriggerResponse(trigger))
+	}
+
+	h.writeJSON(w, api.Response{
+		Success: true,
+		Data:    response,
+		Meta: map[string]any{
+			"job_name": name,
+			"total":    len(response),
+		},
+	})
+}
+
+// SetPaused handles POST /api/admin/jobs/{name}/pause and /resume
+func (h *Handler) SetPaused(w http.ResponseWriter, r *http.Request) {
+	name, action := jobPath(r)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	control, err := h.queries.SetJobPaused(ctx, generated.SetJobPausedParams{
+		JobName: name,
+		Paused:  action == "pause",
+	})
+	if err != nil {
+		h.controlError(w, err, name, "Failed to update job")
+		return
+	}
+
+	h.logger.Info().
+		Str("job_name", name).
+		Bool("paused", control.Paused).
+		Msg("Job pause state updated")
+
+	h.writeJSON(w, api.Response{
+		Success: true,
+		Data:    toControlResponse(control),
+	})
+}
+
+// Schedule handles PUT /api/admin/jobs/{name}/schedule, DELETE restores the default schedule
+func (h *Handler) Schedule(w http.ResponseWriter, r *http.Request) {
+	name, _ := jobPath(r)
+
+	var schedule *string
+	if r.Method == "PUT" {
+		var req ScheduleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if _, err := cron.ParseStandard(req.Schedule); err != nil {
+			http.Error(w, "Invalid schedule: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		schedule = &req.Schedule
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	control, err := h.queries.SetJobScheduleOverride(ctx, generated.SetJobScheduleOverrideParams{
+		JobName:          name,
+		ScheduleOverride: schedule,
+	})
+	if err != nil {
+		h.controlError(w, err, name, "Failed to update job")
+		return
+	}
+
+	h.logger.Info().
+		Str("job_name", name).
+		Str("schedule", effectiveSchedule(control)).
+		Msg("Job schedule updated")
+
+	h.writeJSON(w, api.Response{
+		Success: true,
+		Data:    toControlResponse(control),
+	})
+}
+
+// controlError answers 404 for jobs no cron instance registered
+func (h *Handler) controlError(w http.ResponseWriter, err error, name, message string) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+	h.logger.Error().Err(err).Str("job_name", name).Msg(message)
+	http.Error(w, message, http.StatusInternalServerError)
+}
+
+func effectiveSchedule(control generated.JobControl) string {
+	if control.ScheduleOverride != nil {
+		return *control.ScheduleOverride
+	}
+	return control.DefaultSchedule
+}
+
+func toControlResponse(control generated.JobControl) ControlResponse {
+	return ControlResponse{
+		Name:             control.JobName,
+		Schedule:         effectiveSchedule(control),
+		DefaultSchedule:  control.DefaultSchedule,
+		ScheduleOverride: control.ScheduleOverride,
+		Paused:           control.Paused,
+		UpdatedAt:        control.UpdatedAt.Time,
+	}
+}
+
+func toTriggerResponse(trigger generated.JobTrigger) TriggerResponse {
+	return TriggerResponse{
+		ID:          trigger.ID,
+		JobName:     trigger.JobName,
+		Status:      trigger.Status,
+		RequestedAt: trigger.RequestedAt.Time,
+		ClaimedBy:   trigger.ClaimedBy,
+		ClaimedAt:   timePtr(trigger.ClaimedAt),
+		FinishedAt:  timePtr(trigger.FinishedAt),
+		RunID:       trigger.RunID,
+		Error:       trigger.Error,
+	}
+}
+
+// isUniqueViolation reports whether err is a second pending trigger of a job
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,271 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/models/api"
+)
+
+var validStatuses = map[string]bool{"running": true, "succeeded": true, "failed": true, "skipped": true, "abandoned": true}
+
+// Handler handles the cron job administration endpoints
+type Handler struct {
+	queries *generated.Queries
+	logger  *logger.Logger
+}
+
+// NewHandler creates a new jobs handler
+func NewHandler(queries *generated.Queries, log *logger.Logger) *Handler {
+	return &Handler{
+		queries: queries,
+		logger:  log,
+	}
+}
+
+// RunResponse represents one recorded job run
+type RunResponse struct {
+	ID             int64      `json:"id"`
+	JobName        string     `json:"job_name"`
+	InstanceID     string     `json:"instance_id"`
+	Trigger        string     `json:"trigger"`
+	Status         string     `json:"status"`
+	LockOutcome    *string    `json:"lock_outcome,omitempty"`
+	StartedAt      time.Time  `json:"started_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+	DurationMs     *int64     `json:"duration_ms,omitempty"`
+	ItemsProcessed *int32     `json:"items_processed,omitempty"`
+	Error          *string    `json:"error,omitempty"`
+}
+
+// JobResponse summarizes a job by its controls and latest run
+type JobResponse struct {
+	Name            string       `json:"name"`
+	Schedule        string       `json:"schedule,omitempty"`
+	DefaultSchedule string       `json:"default_schedule,omitempty"`
+	Paused          bool         `json:"paused"`
+	LastRun         *RunResponse `json:"last_run,omitempty"`
+	// Failing is set while the latest run failed
+	Failing              bool       `json:"failing"`
+	FailingSince         *time.Time `json:"failing_since,omitempty"`
+	FailuresSinceSuccess int32      `json:"failures_since_success"`
+	LastSuccessAt        *time.Time `json:"last_success_at,omitempty"`
+	Runs24h              int32      `json:"runs_24h"`
+	Failures24h          int32      `json:"failures_24h"`
+}
+
+// List handles GET /api/admin/jobs
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	summaries, err := h.queries.ListJobRunSummaries(ctx)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list job run summaries")
+		http.Error(w, "Failed to list jobs", http.StatusInternalServerError)
+		return
+	}
+	controls, err := h.queries.ListJobControls(ctx)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list job controls")
+		http.Error(w, "Failed to list jobs", http.StatusInternalServerError)
+		return
+	}
+
+	// Registered jobs are listed before their first run, and jobs with runs before registration
+	byName := make(map[string]*JobResponse, len(controls))
+	for _, control := range controls {
+		byName[control.JobName] = &JobResponse{
+			Name:            control.JobName,
+			Schedule:        effectiveSchedule(control),
+			DefaultSchedule: control.DefaultSchedule,
+			Paused:          control.Paused,
+		}
+	}
+
+	failing := 0
+	for _, s := range summaries {
+		job, ok := byName[s.JobName]
+		if !ok {
+			job = &JobResponse{Name: s.JobName}
+			byName[s.JobName] = job
+		}
+		job.LastRun = &RunResponse{
+			ID:             s.LastRunID,
+			JobName:        s.JobName,
+			InstanceID:     s.InstanceID,
+			Trigger:        s.Trigger,
+			Status:         s.Status,
+			LockOutcome:    s.LockOutcome,
+			StartedAt:      s.StartedAt.Time,
+			FinishedAt:     timePtr(s.FinishedAt),
+			DurationMs:     s.DurationMs,
+			ItemsProcessed: s.ItemsProcessed,
+			Error:          s.Error,
+		}
+		job.Failing = s.Status == "failed"
+		job.FailuresSinceSuccess = s.FailuresSinceSuccess
+		job.LastSuccessAt = timePtr(s.LastSuccessAt)
+		job.Runs24h = s.Runs24h
+		job.Failures24h = s.Failures24h
+		if job.Failing {
+			job.FailingSince = timePtr(s.FailingSince)
+			failing++
+		}
+	}
+
+	response := make([]JobResponse, 0, len(byName))
+	for _, job := range byName {
+		response = append(response, *job)
+	}
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].Name < response[j].Name
+	})
+
+	h.writeJSON(w, api.Response{
+		Success: true,
+		Data:    response,
+		Meta: map[string]any{
+			"total":   len(response),
+			"failing": failing,
+		},
+	})
+}
+
+// Runs handles GET /api/admin/jobs/{name}/runs
+func (h *Handler) Runs(w http.ResponseWriter, r *http.Request) {
+	name, _ := jobPath(r)
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed >= 1 && parsed <= 500 {
+			limit = parsed
+		}
+	}
+
+	var status *string
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		if !validStatuses[statusStr] {
+			http.Error(w, "Invalid status, expected running, succeeded, failed, skipped or abandoned", http.StatusBadRequest)
+			return
+		}
+		status = &statusStr
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	runs, err := h.queries.ListJobRuns(ctx, generated.ListJobRunsParams{
+		JobName:    name,
+		Status:     status,
+		LimitCount: int32(limit),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Str("job_name", name).Msg("Failed to list job runs")
+		http.Error(w, "Failed to list job runs", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]RunResponse, 0, len(runs))
+	for _, run := range runs {
+		response = append(response, RunResponse{
+			ID:             run.ID,
+			JobName:        run.JobName,
+			InstanceID:     run.InstanceID,
+			Trigger:        run.Trigger,
+			Status:         run.Status,
+			LockOutcome:    run.LockOutcome,
+			StartedAt:      run.StartedAt.Time,
+			FinishedAt:     timePtr(run.FinishedAt),
+			DurationMs:     run.DurationMs,
+			ItemsProcessed: run.ItemsProcessed,
+			Error:          run.Error,
+		})
+	}
+
+	h.writeJSON(w, api.Response{
+		Success: true,
+		Data:    response,
+		Meta: map[string]any{
+			"job_name": name,
+			"total":    len(response),
+		},
+	})
+}
+
+// Route dispatches /api/admin/jobs/{name}/{action}
+func (h *Handler) Route(w http.ResponseWriter, r *http.Request) {
+	name, action := jobPath(r)
+	if name == "" {
+		http.Error(w, "Invalid job name", http.StatusBadRequest)
+		return
+	}
+
+	var handler http.HandlerFunc
+	var methods []string
+	switch action {
+	case "runs":
+		handler, methods = h.Runs, []string{"GET"}
+	case "triggers":
+		handler, methods = h.Triggers, []string{"GET"}
+	case "trigger":
+		handler, methods = h.Trigger, []string{"POST"}
+	case "pause", "resume":
+		handler, methods = h.SetPaused, []string{"POST"}
+	case "schedule":
+		handler, methods = h.Schedule, []string{"PUT", "DELETE"}
+	default:
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+
+	for _, method := range methods {
+		if r.Method == method {
+			handler(w, r)
+			return
+		}
+	}
+	http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+}
+
+// jobPath splits /api/admin/jobs/{name}/{action}, name is empty when the path has another shape
+func jobPath(r *http.Request) (name, action string) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/admin/jobs/"), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, response api.Response) {
+	h.writeJSONStatus(w, http.StatusOK, response)
+}
+
+func (h *Handler) writeJSONStatus(w http.ResponseWriter, status int, response api.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+	}
+}
+
+func timePtr(t pgtype.Timestamp) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,203 @@
+package odds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/models"
+	"github.com/iddaa-lens/core/pkg/models/api"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// candleWindows holds the default and maximum hours of candles returned per interval
+var candleWindows = map[string][2]int{
+	"5m": {24, 168},
+	"1h": {168, 720},
+	"1d": {720, 8760},
+}
+
+// Candles handles the /api/events/{slug}/candles endpoint
+func (h *Handler) Candles(w http.ResponseWriter, r *http.Request) {
+	slug := strings.TrimPrefix(r.URL.Path, "/api/events/")
+	slug = strings.TrimSuffix(slug, "/candles")
+	if slug == "" || strings.Contains(slug, "/") {
+		http.Error(w, "Invalid event slug", http.StatusBadRequest)
+		return
+	}
+
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		interval = "1h"
+	}
+	window, ok := candleWindows[interval]
+	if !ok {
+		http.Error(w, "Invalid interval, expected 5m, 1h or 1d", http.StatusBadRequest)
+		return
+	}
+
+	// hours limits candles to the most recent window of the interval
+	hours := window[0]
+	if hoursStr := r.URL.Query().Get("hours"); hoursStr != "" {
+		if parsed, err := strconv.Atoi(hoursStr); err == nil && parsed > 0 && parsed <= window[1] {
+			hours = parsed
+		}
+	}
+
+	var marketTypeID *int32
+	if marketStr := r.URL.Query().Get("market"); marketStr != "" {
+		if parsed, err := strconv.Atoi(marketStr); err == nil && parsed > 0 {
+			id := int32(parsed)
+			marketTypeID = &id
+		}
+	}
+
+	var outcome *string
+	if outcomeStr := r.URL.Query().Get("outcome"); outcomeStr != "" {
+		outcome = &outcomeStr
+	}
+
+	bookmaker := r.URL.Query().Get("bookmaker")
+	if bookmaker == "" {
+		bookmaker = services.IddaaBookmaker
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	event, err := h.queries.GetEventBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to get event")
+		http.Error(w, "Failed to get event", http.StatusInternalServerError)
+		return
+	}
+
+	candles, err := h.eventCandles(ctx, interval, generated.GetEventCandles5mParams{
+		EventID:      event.ID,
+		Bookmaker:    bookmaker,
+		SinceTime:    pgtype.Timestamp{Time: time.Now().Add(-time.Duration(hours) * time.Hour), Valid: true},
+		MarketTypeID: marketTypeID,
+		Outcome:      outcome,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Str("slug", slug).Str("interval", interval).Msg("Failed to query odds candles")
+		http.Error(w, "Failed to get odds candles", http.StatusInternalServerError)
+		return
+	}
+
+	response := api.OddsCandlesResponse{
+		EventSlug: event.Slug,
+		Match:     fmt.Sprintf("%s vs %s", event.HomeTeamName, event.AwayTeamName),
+		League:    event.LeagueName,
+		Sport:     event.SportName,
+		EventTime: event.EventDate.Time,
+		Status:    event.Status,
+		Bookmaker: bookmaker,
+		Interval:  interval,
+		Markets:   buildCandleMarkets(candles),
+	}
+
+	h.logger.Info().
+		Str("slug", slug).
+		Str("interval", interval).
+		Int("markets", len(response.Markets)).
+		Msg("Returning odds candles")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// eventCandles reads the rollup table of the interval, the hourly and daily rows share the 5-minute row shape
+func (h *Handler) eventCandles(ctx context.Context, interval string, arg generated.GetEventCandles5mParams) ([]generated.GetEventCandles5mRow, error) {
+	switch interval {
+	case "1h":
+		rows, err := h.queries.GetEventCandles1h(ctx, generated.GetEventCandles1hParams(arg))
+		if err != nil {
+			return nil, err
+		}
+		candles := make([]generated.GetEventCandles5mRow, 0, len(rows))
+		for _, row := range rows {
+			candles = append(candles, generated.GetEventCandles5mRow(row))
+		}
+		return candles, nil
+	case "1d":
+		rows, err := h.queries.GetEventCandles1d(ctx, generated.GetEventCandles1dParams(arg))
+		if err != nil {
+			return nil, err
+		}
+		candles := make([]generated.GetEventCandles5mRow, 0, len(rows))
+		for _, row := range rows {
+			candles = append(candles, generated.GetEventCandles5mRow(row))
+		}
+		return candles, nil
+	default:
+		return h.queries.GetEventCandles5m(ctx, arg)
+	}
+}
+
+// buildCandleMarkets groups candles by market and outcome, rows arrive ordered by market, outcome and time
+func buildCandleMarkets(rows []generated.GetEventCandles5mRow) []api.OddsCandleMarket {
+	markets := []api.OddsCandleMarket{}
+	marketIndex := make(map[string]int)
+	outcomeIndex := make(map[string]int)
+
+	for _, row := range rows {
+		marketKey := fmt.Sprintf("%d|%s", row.MarketTypeID, row.MarketKey)
+		mi, ok := marketIndex[marketKey]
+		if !ok {
+			params := []string{}
+			if row.MarketKey != "" {
+				params = strings.Split(row.MarketKey, "|")
+			}
+
+			mi = len(markets)
+			marketIndex[marketKey] = mi
+			markets = append(markets, api.OddsCandleMarket{
+				MarketTypeID: row.MarketTypeID,
+				MarketCode:   row.MarketCode,
+				MarketName:   models.FormatMarketName(row.MarketName, models.MarketParams{Values: params}),
+				MarketParams: params,
+				Outcomes:     []api.OddsCandleOutcome{},
+			})
+		}
+
+		outcomeKey := marketKey + "|" + row.Outcome
+		oi, ok := outcomeIndex[outcomeKey]
+		if !ok {
+			oi = len(markets[mi].Outcomes)
+			outcomeIndex[outcomeKey] = oi
+			markets[mi].Outcomes = append(markets[mi].Outcomes, api.OddsCandleOutcome{
+				Outcome: row.Outcome,
+				Candles: []api.OddsCandle{},
+			})
+		}
+
+		markets[mi].Outcomes[oi].Candles = append(markets[mi].Outcomes[oi].Candles, api.OddsCandle{
+			Timestamp: row.BucketStart.Time,
+			Open:      row.OpenOdds,
+			High:      row.HighOdds,
+			Low:       row.LowOdds,
+			Close:     row.CloseOdds,
+			Ticks:     row.TickCount,
+		})
+	}
+
+	return markets
+}
@@ -0,0 +1,143 @@
+package odds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/iddaa-lens/core/pkg/models"
+	"github.com/iddaa-lens/core/pkg/models/api"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// Compare handles the /api/events/{slug}/odds/compare endpoint
+func (h *Handler) Compare(w http.ResponseWriter, r *http.Request) {
+	slug := strings.TrimPrefix(r.URL.Path, "/api/events/")
+	slug = strings.TrimSuffix(slug, "/odds/compare")
+	if slug == "" || strings.Contains(slug, "/") {
+		http.Error(w, "Invalid event slug", http.StatusBadRequest)
+		return
+	}
+
+	// min_deviation is the percentage from the market median that flags Iddaa's price as out of line
+	minDeviation := 5.0
+	if deviationStr := r.URL.Query().Get("min_deviation"); deviationStr != "" {
+		if parsed, err := strconv.ParseFloat(deviationStr, 64); err == nil && parsed >= 0 {
+			minDeviation = parsed
+		}
+	}
+
+	minBookmakers := 2
+	if bookmakersStr := r.URL.Query().Get("min_bookmakers"); bookmakersStr != "" {
+		if parsed, err := strconv.Atoi(bookmakersStr); err == nil && parsed >= 1 && parsed <= 50 {
+			minBookmakers = parsed
+		}
+	}
+
+	outOfLineOnly := r.URL.Query().Get("out_of_line") == "true"
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	event, err := h.queries.GetEventBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to get event")
+		http.Error(w, "Failed to get event", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := h.queries.GetEventBookmakerOdds(ctx, event.ID)
+	if err != nil {
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to query bookmaker odds")
+		http.Error(w, "Failed to get odds comparison", http.StatusInternalServerError)
+		return
+	}
+
+	comparisons := services.ComparePrices(rows, minDeviation, minBookmakers)
+
+	response := api.OddsComparisonResponse{
+		EventSlug: event.Slug,
+		Match:     fmt.Sprintf("%s vs %s", event.HomeTeamName, event.AwayTeamName),
+		League:    event.LeagueName,
+		Sport:     event.SportName,
+		EventTime: event.EventDate.Time,
+		Status:    event.Status,
+		Markets:   buildComparisonMarkets(comparisons, outOfLineOnly),
+	}
+
+	h.logger.Info().
+		Str("slug", slug).
+		Int("markets", len(response.Markets)).
+		Msg("Returning odds comparison")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// buildComparisonMarkets groups outcome comparisons by market, keeping the query order
+func buildComparisonMarkets(comparisons []services.PriceComparison, outOfLineOnly bool) []api.OddsComparisonMarket {
+	markets := []api.OddsComparisonMarket{}
+	marketIndex := make(map[int32]int)
+
+	for _, c := range comparisons {
+		if outOfLineOnly && !c.OutOfLine {
+			continue
+		}
+
+		mi, ok := marketIndex[c.MarketTypeID]
+		if !ok {
+			mi = len(markets)
+			marketIndex[c.MarketTypeID] = mi
+			markets = append(markets, api.OddsComparisonMarket{
+				MarketTypeID: c.MarketTypeID,
+				MarketCode:   c.MarketCode,
+				MarketName:   models.FormatMarketName(c.MarketName, models.MarketParams{Values: c.MarketParams}),
+				MarketParams: c.MarketParams,
+				Outcomes:     []api.OddsComparisonOutcome{},
+			})
+		}
+
+		outcome := api.OddsComparisonOutcome{
+			Outcome:        c.Outcome,
+			IddaaOdds:      c.IddaaOdds,
+			BestOdds:       c.BestOdds,
+			BestBookmaker:  c.BestBookmaker,
+			BookmakerCount: c.BookmakerCount,
+			OutOfLine:      c.OutOfLine,
+			Prices:         make([]api.BookmakerPriceEntry, 0, len(c.Prices)),
+		}
+		if c.BookmakerCount > 0 {
+			marketOdds := c.MarketOdds
+			deviation := c.Deviation
+			outcome.MarketOdds = &marketOdds
+			outcome.Deviation = &deviation
+		}
+		for _, p := range c.Prices {
+			outcome.Prices = append(outcome.Prices, api.BookmakerPriceEntry{
+				Bookmaker:   p.Bookmaker,
+				Name:        p.Name,
+				OddsValue:   p.Odds,
+				LastUpdated: p.LastUpdated,
+			})
+		}
+
+		markets[mi].Outcomes = append(markets[mi].Outcomes, outcome)
+	}
+
+	return markets
+}
@@ -0,0 +1,189 @@
+package odds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/models"
+	"github.com/iddaa-lens/core/pkg/models/api"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// Margins handles the /api/events/{slug}/odds/margins endpoint
+func (h *Handler) Margins(w http.ResponseWriter, r *http.Request) {
+	slug := strings.TrimPrefix(r.URL.Path, "/api/events/")
+	slug = strings.TrimSuffix(slug, "/odds/margins")
+	if slug == "" || strings.Contains(slug, "/") {
+		http.Error(w, "Invalid event slug", http.StatusBadRequest)
+		return
+	}
+
+	bookmaker := r.URL.Query().Get("bookmaker")
+	if bookmaker == "" {
+		bookmaker = services.IddaaBookmaker
+	}
+
+	// method picks the fair probability reported per outcome, all methods are always included
+	method := services.DevigMethod(r.URL.Query().Get("method"))
+	switch method {
+	case services.DevigMultiplicative, services.DevigAdditive, services.DevigPower, services.DevigShin:
+	case "":
+		method = services.DevigShin
+	default:
+		http.Error(w, "Invalid method, expected multiplicative, additive, power or shin", http.StatusBadRequest)
+		return
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	event, err := h.queries.GetEventBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to get event")
+		http.Error(w, "Failed to get event", http.StatusInternalServerError)
+		return
+	}
+
+	margins, err := h.queries.GetEventMarketMargins(ctx, generated.GetEventMarketMarginsParams{
+		EventID:   event.ID,
+		Bookmaker: bookmaker,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to query market margins")
+		http.Error(w, "Failed to get margins", http.StatusInternalServerError)
+		return
+	}
+
+	probabilities, err := h.queries.GetEventFairProbabilities(ctx, generated.GetEventFairProbabilitiesParams{
+		EventID:   event.ID,
+		Bookmaker: bookmaker,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to query fair probabilities")
+		http.Error(w, "Failed to get margins", http.StatusInternalServerError)
+		return
+	}
+
+	history, err := h.queries.GetEventMarginHistory(ctx, generated.GetEventMarginHistoryParams{
+		EventID:   event.ID,
+		Bookmaker: bookmaker,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to query margin history")
+		http.Error(w, "Failed to get margins", http.StatusInternalServerError)
+		return
+	}
+
+	response := api.OddsMarginsResponse{
+		EventSlug: event.Slug,
+		Match:     fmt.Sprintf("%s vs %s", event.HomeTeamName, event.AwayTeamName),
+		League:    event.LeagueName,
+		Sport:     event.SportName,
+		EventTime: event.EventDate.Time,
+		Status:    event.Status,
+		Bookmaker: bookmaker,
+		Method:    string(method),
+		Markets:   buildMarginMarkets(margins, probabilities, history, method),
+	}
+
+	h.logger.Info().
+		Str("slug", slug).
+		Str("bookmaker", bookmaker).
+		Int("markets", len(response.Markets)).
+		Msg("Returning odds margins")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// buildMarginMarkets attaches outcomes and history to their market by market type and market key
+func buildMarginMarkets(margins []generated.GetEventMarketMarginsRow, probabilities []generated.GetEventFairProbabilitiesRow,
+	history []generated.GetEventMarginHistoryRow, method services.DevigMethod) []api.OddsMarginMarket {
+	markets := make([]api.OddsMarginMarket, 0, len(margins))
+	marketIndex := make(map[string]int, len(margins))
+
+	for _, m := range margins {
+		params := []string{}
+		if m.MarketKey != "" {
+			params = strings.Split(m.MarketKey, "|")
+		}
+
+		marketIndex[fmt.Sprintf("%d|%s", m.MarketTypeID, m.MarketKey)] = len(markets)
+		markets = append(markets, api.OddsMarginMarket{
+			MarketTypeID:     m.MarketTypeID,
+			MarketCode:       m.MarketCode,
+			MarketName:       models.FormatMarketName(m.MarketName, models.MarketParams{Values: params}),
+			MarketParams:     params,
+			OutcomeCount:     m.OutcomeCount,
+			Booksum:          m.Booksum,
+			MarginPercentage: m.MarginPercentage,
+			PowerK:           m.PowerK,
+			ShinZ:            m.ShinZ,
+			CalculatedAt:     m.CalculatedAt.Time,
+			Outcomes:         []api.FairProbabilityEntry{},
+			History:          []api.MarginHistoryPoint{},
+		})
+	}
+
+	for _, p := range probabilities {
+		mi, ok := marketIndex[fmt.Sprintf("%d|%s", p.MarketTypeID, p.MarketKey)]
+		if !ok {
+			continue
+		}
+
+		entry := api.FairProbabilityEntry{
+			Outcome:            p.Outcome,
+			OddsValue:          p.OddsValue,
+			ImpliedProbability: p.ImpliedProbability,
+			Multiplicative:     p.Multiplicative,
+			Additive:           p.Additive,
+			Power:              p.Power,
+			Shin:               p.Shin,
+		}
+		switch method {
+		case services.DevigMultiplicative:
+			entry.FairProbability = &p.Multiplicative
+		case services.DevigAdditive:
+			entry.FairProbability = p.Additive
+		case services.DevigPower:
+			entry.FairProbability = p.Power
+		case services.DevigShin:
+			entry.FairProbability = p.Shin
+		}
+		if entry.FairProbability != nil && *entry.FairProbability > 0 {
+			fairOdds := 100 / float64(*entry.FairProbability)
+			entry.FairOdds = &fairOdds
+		}
+
+		markets[mi].Outcomes = append(markets[mi].Outcomes, entry)
+	}
+
+	for _, point := range history {
+		mi, ok := marketIndex[fmt.Sprintf("%d|%s", point.MarketTypeID, point.MarketKey)]
+		if !ok {
+			continue
+		}
+		markets[mi].History = append(markets[mi].History, api.MarginHistoryPoint{
+			Timestamp:        point.RecordedAt.Time,
+			MarginPercentage: point.MarginPercentage,
+		})
+	}
+
+	return markets
+}
@@ -0,0 +1,236 @@
+package odds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/models"
+	"github.com/iddaa-lens/core/pkg/models/api"
+)
+
+// Timeline handles the /api/events/{slug}/odds/timeline endpoint
+func (h *Handler) Timeline(w http.ResponseWriter, r *http.Request) {
+	slug := strings.TrimPrefix(r.URL.Path, "/api/events/")
+	slug = strings.TrimSuffix(slug, "/odds/timeline")
+	if slug == "" || strings.Contains(slug, "/") {
+		http.Error(w, "Invalid event slug", http.StatusBadRequest)
+		return
+	}
+
+	// Parse query parameters with simple defaults
+	var marketTypeID *int32
+	if marketStr := r.URL.Query().Get("market"); marketStr != "" {
+		if parsed, err := strconv.Atoi(marketStr); err == nil && parsed > 0 {
+			id := int32(parsed)
+			marketTypeID = &id
+		}
+	}
+
+	outcome := r.URL.Query().Get("outcome")
+
+	// points downsamples each outcome series, 0 returns the full series
+	points := 0
+	if pointsStr := r.URL.Query().Get("points"); pointsStr != "" {
+		if parsed, err := strconv.Atoi(pointsStr); err == nil && parsed >= 2 && parsed <= 1000 {
+			points = parsed
+		}
+	}
+
+	// min_change only applies together with a market filter
+	minChange := 0.0
+	if minChangeStr := r.URL.Query().Get("min_change"); minChangeStr != "" {
+		if parsed, err := strconv.ParseFloat(minChangeStr, 64); err == nil && parsed > 0 {
+			minChange = parsed
+		}
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	event, err := h.queries.GetEventBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to get event")
+		http.Error(w, "Failed to get event", http.StatusInternalServerError)
+		return
+	}
+
+	var history []generated.GetOddsHistoryRow
+	if marketTypeID != nil && minChange > 0 {
+		changes, err := h.queries.GetOddsChangesByMarket(ctx, generated.GetOddsChangesByMarketParams{
+			EventID:             &event.ID,
+			MarketTypeID:        marketTypeID,
+			MinChangePercentage: minChange,
+		})
+		if err != nil {
+			h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to query odds changes")
+			http.Error(w, "Failed to get odds timeline", http.StatusInternalServerError)
+			return
+		}
+		history = make([]generated.GetOddsHistoryRow, 0, len(changes))
+		for _, c := range changes {
+			history = append(history, generated.GetOddsHistoryRow(c))
+		}
+	} else {
+		history, err = h.queries.GetOddsHistory(ctx, &event.ID)
+		if err != nil {
+			h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to query odds history")
+			http.Error(w, "Failed to get odds timeline", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	response := api.OddsTimelineResponse{
+		EventSlug: event.Slug,
+		Match:     fmt.Sprintf("%s vs %s", event.HomeTeamName, event.AwayTeamName),
+		League:    event.LeagueName,
+		Sport:     event.SportName,
+		EventTime: event.EventDate.Time,
+		Status:    event.Status,
+		Markets:   buildTimelineMarkets(history, marketTypeID, outcome, points),
+	}
+
+	h.logger.Info().
+		Str("slug", slug).
+		Int("markets", len(response.Markets)).
+		Int("points", points).
+		Msg("Returning odds timeline")
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// buildTimelineMarkets groups history rows by market and outcome into ascending series
+func buildTimelineMarkets(history []generated.GetOddsHistoryRow, marketTypeID *int32, outcome string, points int) []api.OddsTimelineMarket {
+	markets := []api.OddsTimelineMarket{}
+	marketIndex := make(map[string]int)
+	outcomeIndex := make(map[string]int)
+
+	for _, row := range history {
+		if row.MarketTypeID == nil {
+			continue
+		}
+		if marketTypeID != nil && *row.MarketTypeID != *marketTypeID {
+			continue
+		}
+		if outcome != "" && row.Outcome != outcome {
+			continue
+		}
+
+		var params models.MarketParams
+		if len(row.MarketParams) > 0 {
+			_ = json.Unmarshal(row.MarketParams, &params)
+		}
+		if params.Values == nil {
+			params.Values = []string{}
+		}
+
+		marketKey := fmt.Sprintf("%d|%s", *row.MarketTypeID, strings.Join(params.Values, ","))
+		mi, ok := marketIndex[marketKey]
+		if !ok {
+			mi = len(markets)
+			marketIndex[marketKey] = mi
+			markets = append(markets, api.OddsTimelineMarket{
+				MarketTypeID: *row.MarketTypeID,
+				MarketCode:   row.MarketCode,
+				MarketName:   models.FormatMarketName(row.MarketName, params),
+				MarketParams: params.Values,
+				Outcomes:     []api.OddsTimelineOutcome{},
+			})
+		}
+
+		outcomeKey := marketKey + "|" + row.Outcome
+		oi, ok := outcomeIndex[outcomeKey]
+		if !ok {
+			oi = len(markets[mi].Outcomes)
+			outcomeIndex[outcomeKey] = oi
+			markets[mi].Outcomes = append(markets[mi].Outcomes, api.OddsTimelineOutcome{
+				Outcome: row.Outcome,
+				Points:  []api.OddsTimelinePoint{},
+			})
+		}
+
+		point := api.OddsTimelinePoint{
+			Timestamp:        row.RecordedAt.Time,
+			OddsValue:        row.OddsValue,
+			PreviousValue:    row.PreviousValue,
+			MinutesToKickoff: row.MinutesToKickoff,
+		}
+		if row.ChangePercentage != nil {
+			val := float64(*row.ChangePercentage)
+			point.ChangePercentage = &val
+		}
+
+		markets[mi].Outcomes[oi].Points = append(markets[mi].Outcomes[oi].Points, point)
+	}
+
+	for mi := range markets {
+		for oi := range markets[mi].Outcomes {
+			series := &markets[mi].Outcomes[oi]
+
+			// History is returned newest first, charts want oldest first
+			sort.SliceStable(series.Points, func(i, j int) bool {
+				return series.Points[i].Timestamp.Before(series.Points[j].Timestamp)
+			})
+
+			first := series.Points[0]
+			last := series.Points[len(series.Points)-1]
+
+			series.OpeningOdds = first.OddsValue
+			if first.PreviousValue != nil {
+				series.OpeningOdds = *first.PreviousValue
+			}
+			series.CurrentOdds = last.OddsValue
+			series.HighestOdds = series.OpeningOdds
+			series.LowestOdds = series.OpeningOdds
+			for _, p := range series.Points {
+				if p.OddsValue > series.HighestOdds {
+					series.HighestOdds = p.OddsValue
+				}
+				if p.OddsValue < series.LowestOdds {
+					series.LowestOdds = p.OddsValue
+				}
+			}
+			if series.OpeningOdds > 0 {
+				series.ChangePercentage = (series.CurrentOdds - series.OpeningOdds) / series.OpeningOdds * 100
+			}
+
+			series.TotalPoints = len(series.Points)
+			series.Points = downsamplePoints(series.Points, points)
+		}
+	}
+
+	return markets
+}
+
+// downsamplePoints picks n evenly spaced points, always keeping the first and last
+func downsamplePoints(points []api.OddsTimelinePoint, n int) []api.OddsTimelinePoint {
+	if n < 2 || len(points) <= n {
+		return points
+	}
+
+	sampled := make([]api.OddsTimelinePoint, 0, n)
+	step := float64(len(points)-1) / float64(n-1)
+	for i := 0; i < n; i++ {
+		sampled = append(sampled, points[int(float64(i)*step+0.5)])
+	}
+
+	return sampled
+}
@@ -0,0 +1,141 @@
+package odds
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+)
+
+// timelineDB records the queries the handler runs. Events are found with eventID unless
+// eventErr is set, history queries return no rows.
+type timelineDB struct {
+	eventID  int32
+	eventErr error
+	queries  map[string][]any
+}
+
+func (d *timelineDB) record(sql string, args []any) string {
+	name := strings.Fields(strings.TrimPrefix(sql, "-- name: "))[0]
+	d.queries[name] = args
+	return name
+}
+
+func (d *timelineDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("unexpected Exec")
+}
+
+func (d *timelineDB) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	d.record(sql, args)
+	return emptyRows{}, nil
+}
+
+func (d *timelineDB) QueryRow(_ context.Context, sql string, args ...interface{}) pgx.Row {
+	if name := d.record(sql, args); name != "GetEventBySlug" {
+		return eventRow{err: errors.New("unexpected QueryRow " + name)}
+	}
+	return eventRow{id: d.eventID, err: d.eventErr}
+}
+
+// eventRow scans an event that only has an id
+type eventRow struct {
+	id  int32
+	err error
+}
+
+func (r eventRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*int32) = r.id
+	return nil
+}
+
+// emptyRows is a result without rows
+type emptyRows struct {
+	pgx.Rows
+}
+
+func (emptyRows) Next() bool { return false }
+func (emptyRows) Err() error { return nil }
+func (emptyRows) Close()     {}
+
+func serveTimeline(db *timelineDB, target string) *httptest.ResponseRecorder {
+	handler := NewHandler(generated.New(db), logger.New("odds-test"))
+	recorder := httptest.NewRecorder()
+	handler.Timeline(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder
+}
+
+func TestTimeline_RejectsInvalidSlug(t *testing.T) {
+	for _, target := range []string{
+		"/api/events//odds/timeline",
+		"/api/events/a/b/odds/timeline",
+	} {
+		db := &timelineDB{queries: map[string][]any{}}
+		if code := serveTimeline(db, target).Code; code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", target, code)
+		}
+		if len(db.queries) != 0 {
+			t.Errorf("%s: ran queries %v for an invalid slug", target, db.queries)
+		}
+	}
+}
+
+func TestTimeline_UnknownEvent(t *testing.T) {
+	db := &timelineDB{eventErr: pgx.ErrNoRows, queries: map[string][]any{}}
+	if code := serveTimeline(db, "/api/events/missing/odds/timeline").Code; code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", code)
+	}
+}
+
+func TestTimeline_ChoosesHistoryQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantQuery string
+		wantArgs  []any
+	}{
+		{name: "no filters", query: "", wantQuery: "GetOddsHistory"},
+		{name: "market and min change", query: "?market=5&min_change=10", wantQuery: "GetOddsChangesByMarket", wantArgs: []any{int32(5), 10.0}},
+		{name: "min change without market", query: "?min_change=10", wantQuery: "GetOddsHistory"},
+		{name: "invalid market", query: "?market=abc&min_change=10", wantQuery: "GetOddsHistory"},
+		{name: "non positive market", query: "?market=-3&min_change=10", wantQuery: "GetOddsHistory"},
+		{name: "negative min change", query: "?market=5&min_change=-1", wantQuery: "GetOddsHistory"},
+		{name: "invalid points ignored", query: "?points=1", wantQuery: "GetOddsHistory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &timelineDB{eventID: 42, queries: map[string][]any{}}
+			recorder := serveTimeline(db, "/api/events/team-a-vs-team-b/odds/timeline"+tt.query)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", recorder.Code, recorder.Body)
+			}
+
+			args, ok := db.queries[tt.wantQuery]
+			if !ok {
+				t.Fatalf("queries = %v, want %s", db.queries, tt.wantQuery)
+			}
+			if eventID := args[0].(*int32); eventID == nil || *eventID != 42 {
+				t.Errorf("event id = %v, want 42", eventID)
+			}
+			if tt.wantArgs != nil {
+				if marketTypeID := args[1].(*int32); marketTypeID == nil || *marketTypeID != tt.wantArgs[0] {
+					t.Errorf("market type id = %v, want %v", marketTypeID, tt.wantArgs[0])
+				}
+				if minChange := args[2].(float64); minChange != tt.wantArgs[1] {
+					t.Errorf("min change = %v, want %v", minChange, tt.wantArgs[1])
+				}
+			}
+		})
+	}
+}
@@ -2,16 +2,19 @@ package smart_money
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"path"
 	"strconv"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/iddaa-lens/core/pkg/database/generated"
 	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/middleware"
 	"github.com/iddaa-lens/core/pkg/models/api"
 	"github.com/iddaa-lens/core/pkg/services"
 )
@@ -439,6 +442,12 @@ func (h *Handler) GetValueSpots(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) MarkAlertViewed(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	user, ok := middleware.UserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	// Extract alert ID from URL path
 	alertIDStr := path.Base(path.Dir(r.URL.Path))
 	alertID, err := strconv.ParseInt(alertIDStr, 10, 32)
@@ -447,9 +456,16 @@ func (h *Handler) MarkAlertViewed(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Mark alert as viewed in database
-	if err := h.queries.MarkAlertViewed(ctx, int32(alertID)); err != nil {
-		h.logger.Error().Err(err).Int64("alert_id", alertID).Msg("Failed to mark alert as viewed")
+	// Mark alert as viewed for the authenticated user
+	if err := h.queries.MarkAlertViewed(ctx, generated.MarkAlertViewedParams{
+		UserID:  user.ID,
+		AlertID: int32(alertID),
+	}); err != nil {
+		if isForeignKeyViolation(err) {
+			http.Error(w, "Alert not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error().Err(err).Int64("alert_id", alertID).Int32("user_id", user.ID).Msg("Failed to mark alert as viewed")
 		http.Error(w, "Failed to mark alert as viewed", http.StatusInternalServerError)
 		return
 	}
@@ -468,6 +484,12 @@ func (h *Handler) MarkAlertViewed(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) MarkAlertClicked(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	user, ok := middleware.UserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	// Extract alert ID from URL path
 	alertIDStr := path.Base(path.Dir(r.URL.Path))
 	alertID, err := strconv.ParseInt(alertIDStr, 10, 32)
@@ -476,9 +498,16 @@ func (h *Handler) MarkAlertClicked(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Mark alert as clicked in database
-	if err := h.queries.MarkAlertClicked(ctx, int32(alertID)); err != nil {
-		h.logger.Error().Err(err).Int64("alert_id", alertID).Msg("Failed to mark alert as clicked")
+	// Mark alert as clicked for the authenticated user
+	if err := h.queries.MarkAlertClicked(ctx, generated.MarkAlertClickedParams{
+		UserID:  user.ID,
+		AlertID: int32(alertID),
+	}); err != nil {
+		if isForeignKeyViolation(err) {
+			http.Error(w, "Alert not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error().Err(err).Int64("alert_id", alertID).Int32("user_id", user.ID).Msg("Failed to mark alert as clicked")
 		http.Error(w, "Failed to mark alert as clicked", http.StatusInternalServerError)
 		return
 	}
@@ -624,3 +653,9 @@ func (h *Handler) GetDashboard(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
+
+// isForeignKeyViolation reports whether err references a missing alert
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
@@ -0,0 +1,124 @@
+package smart_money
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/models/api"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// PerformanceResponse reports how graded alerts performed, rolled up per dimension
+type PerformanceResponse struct {
+	Since   time.Time         `json:"since"`
+	Overall PerformanceBucket `json:"overall"`
+	// Dimensions is keyed by alert_type, severity, sport, league, minutes_to_kickoff and confidence
+	Dimensions map[string][]PerformanceBucket `json:"dimensions"`
+}
+
+// PerformanceBucket is the hit rate and return of a group of alerts, for a 1 unit stake per alert
+type PerformanceBucket struct {
+	Bucket          string  `json:"bucket"`
+	Alerts          int     `json:"alerts"`
+	Won             int     `json:"won"`
+	Lost            int     `json:"lost"`
+	Refunded        int     `json:"refunded"`
+	HitRate         float64 `json:"hit_rate"`
+	ProfitAtAlert   float64 `json:"profit_at_alert"`
+	ROIAtAlert      float64 `json:"roi_at_alert"`
+	WithClosingOdds int     `json:"with_closing_odds"`
+	ProfitAtClose   float64 `json:"profit_at_close"`
+	ROIAtClose      float64 `json:"roi_at_close"`
+	AvgAlertOdds    float64 `json:"avg_alert_odds"`
+	AvgClosingOdds  float64 `json:"avg_closing_odds"`
+}
+
+// GetPerformance handles GET /api/smart-money/performance
+func (h *Handler) GetPerformance(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	days := 30
+	if d := r.URL.Query().Get("days"); d != "" {
+		if parsed, err := strconv.Atoi(d); err == nil && parsed >= 1 && parsed <= 365 {
+			days = parsed
+		}
+	}
+
+	sportCode := r.URL.Query().Get("sport")
+	alertType := r.URL.Query().Get("alert_type")
+	since := time.Now().AddDate(0, 0, -days)
+
+	rows, err := h.queries.GetAlertPerformance(ctx, generated.GetAlertPerformanceParams{
+		SinceTime: pgtype.Timestamp{Time: since, Valid: true},
+		SportCode: sportCode,
+		AlertType: alertType,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to get alert performance")
+		http.Error(w, "Failed to retrieve alert performance", http.StatusInternalServerError)
+		return
+	}
+
+	response := PerformanceResponse{
+		Since:      since,
+		Overall:    PerformanceBucket{Bucket: "all"},
+		Dimensions: map[string][]PerformanceBucket{},
+	}
+
+	for _, row := range rows {
+		bucket := newPerformanceBucket(row)
+		if row.Dimension == "overall" {
+			response.Overall = bucket
+			continue
+		}
+		response.Dimensions[row.Dimension] = append(response.Dimensions[row.Dimension], bucket)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.Response{
+		Success: true,
+		Data:    response,
+		Meta: map[string]any{
+			"days":       days,
+			"sport":      sportCode,
+			"alert_type": alertType,
+		},
+	}); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func newPerformanceBucket(row generated.GetAlertPerformanceRow) PerformanceBucket {
+	performance := services.AlertPerformance{
+		Alerts:          int(row.Alerts),
+		Won:             int(row.Won),
+		Lost:            int(row.Lost),
+		Refunded:        int(row.Refunded),
+		ProfitAtAlert:   row.ProfitAtAlert,
+		WithClosingOdds: int(row.WithClosingOdds),
+		ProfitAtClose:   row.ProfitAtClose,
+	}
+	return PerformanceBucket{
+		Bucket:          row.Bucket,
+		Alerts:          performance.Alerts,
+		Won:             performance.Won,
+		Lost:            performance.Lost,
+		Refunded:        performance.Refunded,
+		HitRate:         performance.HitRate(),
+		ProfitAtAlert:   performance.ProfitAtAlert,
+		ROIAtAlert:      performance.ROIAtAlert(),
+		WithClosingOdds: performance.WithClosingOdds,
+		ProfitAtClose:   performance.ProfitAtClose,
+		ROIAtClose:      performance.ROIAtClose(),
+		AvgAlertOdds:    row.AvgAlertOdds,
+		AvgClosingOdds:  row.AvgClosingOdds,
+	}
+}
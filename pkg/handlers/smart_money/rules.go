@@ -0,0 +1,306 @@
+package smart_money
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/models/api"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+var alertTypePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+var validSeverities = map[string]bool{"low": true, "medium": true, "high": true, "critical": true}
+
+// Rules handles GET and POST /api/admin/smart-money/rules
+func (h *Handler) Rules(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case "GET":
+		rules, err := h.queries.ListSmartMoneyRules(ctx)
+		if err != nil {
+			h.logger.Error().Err(err).Msg("Failed to list smart money rules")
+			http.Error(w, "Failed to list rules", http.StatusInternalServerError)
+			return
+		}
+		h.writeJSON(w, http.StatusOK, api.Response{
+			Success: true,
+			Data:    rules,
+			Meta: map[string]any{
+				"total": len(rules),
+			},
+		})
+
+	case "POST":
+		// Omitted fields keep the table defaults
+		params := generated.CreateSmartMoneyRuleParams{
+			IsActive:         true,
+			Priority:         100,
+			ConfidenceSource: services.ConfidenceFixed,
+			BaseConfidence:   0.5,
+			MaxAlerts:        50,
+		}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := validateRule(toRule(params)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		rule, err := h.queries.CreateSmartMoneyRule(ctx, params)
+		if err != nil {
+			if isUniqueViolation(err) {
+				http.Error(w, "Rule name already exists", http.StatusConflict)
+				return
+			}
+			h.logger.Error().Err(err).Msg("Failed to create smart money rule")
+			http.Error(w, "Failed to create rule", http.StatusInternalServerError)
+			return
+		}
+
+		h.logger.Info().
+			Int32("rule_id", rule.ID).
+			Str("rule", rule.Name).
+			Msg("Smart money rule created")
+
+		h.writeJSON(w, http.StatusCreated, api.Response{
+			Success: true,
+			Data:    rule,
+		})
+
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Rule handles GET, PUT and DELETE /api/admin/smart-money/rules/{id}.
+// PUT updates only the fields present in the body.
+func (h *Handler) Rule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(path.Base(r.URL.Path), 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid rule ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if r.Method == "DELETE" {
+		affected, err := h.queries.DeleteSmartMoneyRule(ctx, int32(id))
+		if err != nil {
+			h.logger.Error().Err(err).Int64("rule_id", id).Msg("Failed to delete smart money rule")
+			http.Error(w, "Failed to delete rule", http.StatusInternalServerError)
+			return
+		}
+		if affected == 0 {
+			http.Error(w, "Rule not found", http.StatusNotFound)
+			return
+		}
+		h.writeJSON(w, http.StatusOK, api.Response{
+			Success: true,
+			Message: "Rule deleted",
+		})
+		return
+	}
+
+	if r.Method != "GET" && r.Method != "PUT" {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rule, err := h.queries.GetSmartMoneyRule(ctx, int32(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Rule not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error().Err(err).Int64("rule_id", id).Msg("Failed to get smart money rule")
+		http.Error(w, "Failed to get rule", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method == "PUT" {
+		params := toUpdateParams(rule)
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		params.ID = rule.ID
+		if err := validateRule(updateToRule(params)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		rule, err = h.queries.UpdateSmartMoneyRule(ctx, params)
+		if err != nil {
+			if isUniqueViolation(err) {
+				http.Error(w, "Rule name already exists", http.StatusConflict)
+				return
+			}
+			h.logger.Error().Err(err).Int64("rule_id", id).Msg("Failed to update smart money rule")
+			http.Error(w, "Failed to update rule", http.StatusInternalServerError)
+			return
+		}
+
+		h.logger.Info().
+			Int32("rule_id", rule.ID).
+			Str("rule", rule.Name).
+			Bool("active", rule.IsActive).
+			Msg("Smart money rule updated")
+	}
+
+	h.writeJSON(w, http.StatusOK, api.Response{
+		Success: true,
+		Data:    rule,
+	})
+}
+
+// validateRule rejects rules the tracker would skip or the database would refuse
+func validateRule(rule generated.SmartMoneyRule) error {
+	if rule.Name == "" {
+		return errors.New("name is required")
+	}
+	if !alertTypePattern.MatchString(rule.AlertType) {
+		return errors.New("alert_type must be lowercase letters, digits and underscores")
+	}
+	if rule.Severity != nil && !validSeverities[*rule.Severity] {
+		return errors.New("invalid severity: " + *rule.Severity)
+	}
+	if rule.BaseConfidence < 0 || rule.BaseConfidence > 1 {
+		return errors.New("base_confidence must be between 0 and 1")
+	}
+	if rule.MaxAlerts <= 0 {
+		return errors.New("max_alerts must be positive")
+	}
+	if _, err := services.CompileSmartMoneyRule(rule); err != nil {
+		return err
+	}
+	return nil
+}
+
+// toRule maps create params onto a rule for validation
+func toRule(p generated.CreateSmartMoneyRuleParams) generated.SmartMoneyRule {
+	return generated.SmartMoneyRule{
+		Name:                p.Name,
+		Description:         p.Description,
+		IsActive:            p.IsActive,
+		Priority:            p.Priority,
+		MinChangePct:        p.MinChangePct,
+		MaxChangePct:        p.MaxChangePct,
+		MinAbsChangePct:     p.MinAbsChangePct,
+		MinMultiplier:       p.MinMultiplier,
+		MaxMultiplier:       p.MaxMultiplier,
+		MinBetPct:           p.MinBetPct,
+		MaxBetPct:           p.MaxBetPct,
+		MinPublicBias:       p.MinPublicBias,
+		MinVolumeRank:       p.MinVolumeRank,
+		MaxVolumeRank:       p.MaxVolumeRank,
+		MaxVolumePct:        p.MaxVolumePct,
+		MinMinutesToKickoff: p.MinMinutesToKickoff,
+		MaxMinutesToKickoff: p.MaxMinutesToKickoff,
+		MinSharpScore:       p.MinSharpScore,
+		MinMovesLastHour:    p.MinMovesLastHour,
+		AlertType:           p.AlertType,
+		Severity:            p.Severity,
+		ConfidenceSource:    p.ConfidenceSource,
+		BaseConfidence:      p.BaseConfidence,
+		TitleTemplate:       p.TitleTemplate,
+		MessageTemplate:     p.MessageTemplate,
+		MaxAlerts:           p.MaxAlerts,
+	}
+}
+
+// toUpdateParams prefills an update with the stored rule
+func toUpdateParams(rule generated.SmartMoneyRule) generated.UpdateSmartMoneyRuleParams {
+	return generated.UpdateSmartMoneyRuleParams{
+		Name:                rule.Name,
+		Description:         rule.Description,
+		IsActive:            rule.IsActive,
+		Priority:            rule.Priority,
+		MinChangePct:        rule.MinChangePct,
+		MaxChangePct:        rule.MaxChangePct,
+		MinAbsChangePct:     rule.MinAbsChangePct,
+		MinMultiplier:       rule.MinMultiplier,
+		MaxMultiplier:       rule.MaxMultiplier,
+		MinBetPct:           rule.MinBetPct,
+		MaxBetPct:           rule.MaxBetPct,
+		MinPublicBias:       rule.MinPublicBias,
+		MinVolumeRank:       rule.MinVolumeRank,
+		MaxVolumeRank:       rule.MaxVolumeRank,
+		MaxVolumePct:        rule.MaxVolumePct,
+		MinMinutesToKickoff: rule.MinMinutesToKickoff,
+		MaxMinutesToKickoff: rule.MaxMinutesToKickoff,
+		MinSharpScore:       rule.MinSharpScore,
+		MinMovesLastHour:    rule.MinMovesLastHour,
+		AlertType:           rule.AlertType,
+		Severity:            rule.Severity,
+		ConfidenceSource:    rule.ConfidenceSource,
+		BaseConfidence:      rule.BaseConfidence,
+		TitleTemplate:       rule.TitleTemplate,
+		MessageTemplate:     rule.MessageTemplate,
+		MaxAlerts:           rule.MaxAlerts,
+		ID:                  rule.ID,
+	}
+}
+
+// updateToRule maps update params onto a rule for validation
+func updateToRule(p generated.UpdateSmartMoneyRuleParams) generated.SmartMoneyRule {
+	rule := toRule(generated.CreateSmartMoneyRuleParams{
+		Name:                p.Name,
+		Description:         p.Description,
+		IsActive:            p.IsActive,
+		Priority:            p.Priority,
+		MinChangePct:        p.MinChangePct,
+		MaxChangePct:        p.MaxChangePct,
+		MinAbsChangePct:     p.MinAbsChangePct,
+		MinMultiplier:       p.MinMultiplier,
+		MaxMultiplier:       p.MaxMultiplier,
+		MinBetPct:           p.MinBetPct,
+		MaxBetPct:           p.MaxBetPct,
+		MinPublicBias:       p.MinPublicBias,
+		MinVolumeRank:       p.MinVolumeRank,
+		MaxVolumeRank:       p.MaxVolumeRank,
+		MaxVolumePct:        p.MaxVolumePct,
+		MinMinutesToKickoff: p.MinMinutesToKickoff,
+		MaxMinutesToKickoff: p.MaxMinutesToKickoff,
+		MinSharpScore:       p.MinSharpScore,
+		MinMovesLastHour:    p.MinMovesLastHour,
+		AlertType:           p.AlertType,
+		Severity:            p.Severity,
+		ConfidenceSource:    p.ConfidenceSource,
+		BaseConfidence:      p.BaseConfidence,
+		TitleTemplate:       p.TitleTemplate,
+		MessageTemplate:     p.MessageTemplate,
+		MaxAlerts:           p.MaxAlerts,
+	})
+	rule.ID = p.ID
+	return rule
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, response api.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+	}
+}
+
+// isUniqueViolation reports whether err is a duplicate rule name
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,116 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// heartbeatInterval keeps idle connections open through proxies
+const heartbeatInterval = 15 * time.Second
+
+// Handler serves server-sent events for odds changes and movement alerts
+type Handler struct {
+	broker *services.StreamBroker
+	logger *logger.Logger
+}
+
+// NewHandler creates a new stream handler
+func NewHandler(broker *services.StreamBroker, log *logger.Logger) *Handler {
+	return &Handler{
+		broker: broker,
+		logger: log,
+	}
+}
+
+// Stream handles the /api/stream endpoint
+// Query parameters: types (odds,alert), sport, league, event, alert_type (comma separated)
+func (h *Handler) Stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	filter := services.StreamFilter{
+		Types:      parseList(r.URL.Query().Get("types")),
+		SportCode:  r.URL.Query().Get("sport"),
+		EventSlug:  r.URL.Query().Get("event"),
+		AlertTypes: parseList(r.URL.Query().Get("alert_type")),
+	}
+	if leagueStr := r.URL.Query().Get("league"); leagueStr != "" {
+		if parsed, err := strconv.Atoi(leagueStr); err == nil && parsed > 0 {
+			filter.LeagueID = int32(parsed)
+		}
+	}
+
+	sub := h.broker.Subscribe(filter)
+	defer h.broker.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	h.logger.Info().
+		Str("action", "stream_connected").
+		Str("sport", filter.SportCode).
+		Int32("league", filter.LeagueID).
+		Str("event", filter.EventSlug).
+		Int("subscribers", h.broker.SubscriberCount()).
+		Msg("Stream client connected")
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			h.logger.Info().
+				Str("action", "stream_disconnected").
+				Int("dropped", sub.Dropped()).
+				Msg("Stream client disconnected")
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case msg, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			payload, err := json.Marshal(msg)
+			if err != nil {
+				h.logger.Error().Err(err).Msg("Failed to encode stream message")
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.Type, payload); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// parseList splits a comma separated query value into a set
+func parseList(value string) map[string]bool {
+	if value == "" {
+		return nil
+	}
+	set := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			set[trimmed] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,556 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/mail"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/middleware"
+	"github.com/iddaa-lens/core/pkg/models/api"
+)
+
+// Handler handles user, API key and preference endpoints
+type Handler struct {
+	queries *generated.Queries
+	logger  *logger.Logger
+}
+
+// NewHandler creates a new users handler
+func NewHandler(queries *generated.Queries, log *logger.Logger) *Handler {
+	return &Handler{
+		queries: queries,
+		logger:  log,
+	}
+}
+
+// CreateUserRequest is the body of POST /api/users
+type CreateUserRequest struct {
+	Email string  `json:"email"`
+	Name  *string `json:"name"`
+}
+
+// CreateAPIKeyRequest is the body of POST /api/me/api-keys
+type CreateAPIKeyRequest struct {
+	Name          string `json:"name"`
+	ExpiresInDays int    `json:"expires_in_days"`
+}
+
+// UserResponse represents a user
+type UserResponse struct {
+	ID        int32     `json:"id"`
+	Email     string    `json:"email"`
+	Name      *string   `json:"name,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// APIKeyResponse represents an API key; Key is only set on creation
+type APIKeyResponse struct {
+	ID         int32      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Key        string     `json:"key,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// PreferencesRequest is the body of PUT /api/me/preferences
+type PreferencesRequest struct {
+	MinChangePercentage float64  `json:"min_change_percentage"`
+	MinMultiplier       float64  `json:"min_multiplier"`
+	MinConfidenceScore  float64  `json:"min_confidence_score"`
+	AlertTypes          []string `json:"alert_types"`
+	FollowedSports      []int32  `json:"followed_sports"`
+	FollowedLeagues     []int32  `json:"followed_leagues"`
+	MaxDailyAlerts      int32    `json:"max_daily_alerts"`
+	PushNotifications   bool     `json:"push_notifications"`
+	QuietHoursStart     *int32   `json:"quiet_hours_start"`
+	QuietHoursEnd       *int32   `json:"quiet_hours_end"`
+}
+
+// PreferencesResponse represents a user's smart money preferences
+type PreferencesResponse struct {
+	MinChangePercentage float64   `json:"min_change_percentage"`
+	MinMultiplier       float64   `json:"min_multiplier"`
+	MinConfidenceScore  float64   `json:"min_confidence_score"`
+	AlertTypes          []string  `json:"alert_types"`
+	FollowedSports      []int32   `json:"followed_sports"`
+	FollowedLeagues     []int32   `json:"followed_leagues"`
+	MaxDailyAlerts      int32     `json:"max_daily_alerts"`
+	PushNotifications   bool      `json:"push_notifications"`
+	QuietHoursStart     *int32    `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd       *int32    `json:"quiet_hours_end,omitempty"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// UserAlertResponse represents an alert in the user's personal feed
+type UserAlertResponse struct {
+	ID               int32     `json:"id"`
+	AlertType        string    `json:"alert_type"`
+	Severity         string    `json:"severity"`
+	Title            string    `json:"title"`
+	Message          string    `json:"message"`
+	ChangePercentage float32   `json:"change_percentage"`
+	Multiplier       float64   `json:"multiplier"`
+	ConfidenceScore  float32   `json:"confidence_score"`
+	MinutesToKickoff *int32    `json:"minutes_to_kickoff,omitempty"`
+	EventSlug        string    `json:"event_slug"`
+	EventDate        time.Time `json:"event_date"`
+	HomeTeam         string    `json:"home_team"`
+	AwayTeam         string    `json:"away_team"`
+	MarketName       string    `json:"market_name"`
+	Outcome          string    `json:"outcome"`
+	Viewed           bool      `json:"viewed"`
+	Clicked          bool      `json:"clicked"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+var alertTypes = []string{"big_mover", "reverse_line", "sharp_money", "value_spot"}
+
+// Create handles POST /api/users (admin only) and returns the user's first API key
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if _, err := mail.ParseAddress(req.Email); err != nil {
+		http.Error(w, "Invalid email", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	user, err := h.queries.CreateUser(ctx, generated.CreateUserParams{
+		Email: strings.ToLower(strings.TrimSpace(req.Email)),
+		Name:  req.Name,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			http.Error(w, "User already exists", http.StatusConflict)
+			return
+		}
+		h.logger.Error().Err(err).Msg("Failed to create user")
+		http.Error(w, "Failed to create user", http.StatusInternalServerError)
+		return
+	}
+
+	key, err := h.issueAPIKey(ctx, user.ID, "default", 0)
+	if err != nil {
+		h.logger.Error().Err(err).Int32("user_id", user.ID).Msg("Failed to create API key")
+		http.Error(w, "Failed to create API key", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info().Int32("user_id", user.ID).Msg("User created")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(api.Response{
+		Success: true,
+		Data: map[string]any{
+			"user": UserResponse{
+				ID:        user.ID,
+				Email:     user.Email,
+				Name:      user.Name,
+				CreatedAt: user.CreatedAt.Time,
+			},
+			"api_key": key,
+		},
+		Message: "Store the API key now, it will not be shown again",
+	}); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+	}
+}
+
+// Me handles GET /api/me
+func (h *Handler) Me(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	h.writeJSON(w, api.Response{
+		Success: true,
+		Data: UserResponse{
+			ID:    user.ID,
+			Email: user.Email,
+			Name:  user.Name,
+		},
+	})
+}
+
+// APIKeys handles GET and POST /api/me/api-keys
+func (h *Handler) APIKeys(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case "GET":
+		keys, err := h.queries.ListAPIKeysByUser(ctx, user.ID)
+		if err != nil {
+			h.logger.Error().Err(err).Int32("user_id", user.ID).Msg("Failed to list API keys")
+			http.Error(w, "Failed to list API keys", http.StatusInternalServerError)
+			return
+		}
+		response := make([]APIKeyResponse, 0, len(keys))
+		for _, k := range keys {
+			response = append(response, toAPIKeyResponse(k, ""))
+		}
+		h.writeJSON(w, api.Response{Success: true, Data: response})
+
+	case "POST":
+		var req CreateAPIKeyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Name == "" {
+			req.Name = "default"
+		}
+		if req.ExpiresInDays < 0 {
+			http.Error(w, "expires_in_days must not be negative", http.StatusBadRequest)
+			return
+		}
+
+		key, err := h.issueAPIKey(ctx, user.ID, req.Name, req.ExpiresInDays)
+		if err != nil {
+			h.logger.Error().Err(err).Int32("user_id", user.ID).Msg("Failed to create API key")
+			http.Error(w, "Failed to create API key", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		h.writeJSON(w, api.Response{
+			Success: true,
+			Data:    key,
+			Message: "Store the API key now, it will not be shown again",
+		})
+
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// RevokeAPIKey handles DELETE /api/me/api-keys/{id}
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != "DELETE" {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	keyID, err := strconv.ParseInt(path.Base(r.URL.Path), 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid API key ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	affected, err := h.queries.RevokeAPIKey(ctx, generated.RevokeAPIKeyParams{
+		ID:     int32(keyID),
+		UserID: user.ID,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Int64("api_key_id", keyID).Msg("Failed to revoke API key")
+		http.Error(w, "Failed to revoke API key", http.StatusInternalServerError)
+		return
+	}
+	if affected == 0 {
+		http.Error(w, "API key not found", http.StatusNotFound)
+		return
+	}
+
+	h.writeJSON(w, api.Response{Success: true, Message: "API key revoked"})
+}
+
+// Preferences handles GET and PUT /api/me/preferences
+func (h *Handler) Preferences(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case "GET":
+		prefs, err := h.queries.GetUserSmartMoneyPreferences(ctx, user.ID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				http.Error(w, "Preferences not found", http.StatusNotFound)
+				return
+			}
+			h.logger.Error().Err(err).Int32("user_id", user.ID).Msg("Failed to get preferences")
+			http.Error(w, "Failed to get preferences", http.StatusInternalServerError)
+			return
+		}
+		h.writeJSON(w, api.Response{Success: true, Data: toPreferencesResponse(prefs)})
+
+	case "PUT":
+		var req PreferencesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if msg := validatePreferences(req); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+
+		enabled := make(map[string]bool, len(req.AlertTypes))
+		for _, t := range req.AlertTypes {
+			enabled[t] = true
+		}
+
+		prefs, err := h.queries.UpsertUserSmartMoneyPreferences(ctx, generated.UpsertUserSmartMoneyPreferencesParams{
+			UserID:              user.ID,
+			MinChangePercentage: float32(req.MinChangePercentage),
+			MinMultiplier:       req.MinMultiplier,
+			MinConfidenceScore:  float32(req.MinConfidenceScore),
+			BigMoverAlerts:      enabled["big_mover"],
+			ReverseLineAlerts:   enabled["reverse_line"],
+			SharpMoneyAlerts:    enabled["sharp_money"],
+			ValueSpotAlerts:     enabled["value_spot"],
+			PreferredSports:     nonNil(req.FollowedSports),
+			PreferredLeagues:    nonNil(req.FollowedLeagues),
+			MaxDailyAlerts:      req.MaxDailyAlerts,
+			PushNotifications:   req.PushNotifications,
+			QuietHoursStart:     req.QuietHoursStart,
+			QuietHoursEnd:       req.QuietHoursEnd,
+		})
+		if err != nil {
+			h.logger.Error().Err(err).Int32("user_id", user.ID).Msg("Failed to update preferences")
+			http.Error(w, "Failed to update preferences", http.StatusInternalServerError)
+			return
+		}
+		h.writeJSON(w, api.Response{
+			Success: true,
+			Data:    toPreferencesResponse(prefs),
+			Message: "Preferences updated",
+		})
+
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Alerts handles GET /api/me/alerts
+func (h *Handler) Alerts(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit := 50
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 200 {
+			limit = parsed
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	alerts, err := h.queries.GetAlertsByUser(ctx, generated.GetAlertsByUserParams{
+		UserID:     user.ID,
+		LimitCount: int32(limit),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Int32("user_id", user.ID).Msg("Failed to get user alerts")
+		http.Error(w, "Failed to retrieve alerts", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]UserAlertResponse, 0, len(alerts))
+	for _, a := range alerts {
+		response = append(response, UserAlertResponse{
+			ID:               a.ID,
+			AlertType:        a.AlertType,
+			Severity:         a.Severity,
+			Title:            a.Title,
+			Message:          a.Message,
+			ChangePercentage: a.ChangePercentage,
+			Multiplier:       a.Multiplier,
+			ConfidenceScore:  a.ConfidenceScore,
+			MinutesToKickoff: a.MinutesToKickoff,
+			EventSlug:        a.EventSlug,
+			EventDate:        a.EventDate.Time,
+			HomeTeam:         stringValue(a.HomeTeamName),
+			AwayTeam:         stringValue(a.AwayTeamName),
+			MarketName:       a.MarketName,
+			Outcome:          a.Outcome,
+			Viewed:           a.Viewed,
+			Clicked:          a.Clicked,
+			CreatedAt:        a.CreatedAt.Time,
+		})
+	}
+
+	h.writeJSON(w, api.Response{
+		Success: true,
+		Data:    response,
+		Meta: map[string]any{
+			"total": len(response),
+		},
+	})
+}
+
+// issueAPIKey generates, stores and returns a new key; the plaintext is never persisted
+func (h *Handler) issueAPIKey(ctx context.Context, userID int32, name string, expiresInDays int) (APIKeyResponse, error) {
+	plaintext, err := middleware.GenerateAPIKey()
+	if err != nil {
+		return APIKeyResponse{}, err
+	}
+
+	var expiresAt pgtype.Timestamp
+	if expiresInDays > 0 {
+		expiresAt = pgtype.Timestamp{Time: time.Now().AddDate(0, 0, expiresInDays), Valid: true}
+	}
+
+	key, err := h.queries.CreateAPIKey(ctx, generated.CreateAPIKeyParams{
+		UserID:    userID,
+		Name:      name,
+		KeyPrefix: middleware.DisplayPrefix(plaintext),
+		KeyHash:   middleware.HashAPIKey(plaintext),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return APIKeyResponse{}, err
+	}
+
+	return toAPIKeyResponse(key, plaintext), nil
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, response api.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func validatePreferences(req PreferencesRequest) string {
+	if req.MinChangePercentage < 0 {
+		return "min_change_percentage must not be negative"
+	}
+	if req.MinMultiplier < 0 {
+		return "min_multiplier must not be negative"
+	}
+	if req.MinConfidenceScore < 0 || req.MinConfidenceScore > 1 {
+		return "min_confidence_score must be between 0 and 1"
+	}
+	for _, t := range req.AlertTypes {
+		valid := false
+		for _, known := range alertTypes {
+			if t == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return "Invalid alert type: " + t
+		}
+	}
+	if req.MaxDailyAlerts < 0 {
+		return "max_daily_alerts must not be negative"
+	}
+	for _, hour := range []*int32{req.QuietHoursStart, req.QuietHoursEnd} {
+		if hour != nil && (*hour < 0 || *hour > 23) {
+			return "quiet hours must be between 0 and 23"
+		}
+	}
+	return ""
+}
+
+func toPreferencesResponse(p generated.SmartMoneyPreference) PreferencesResponse {
+	enabled := map[string]bool{
+		"big_mover":    p.BigMoverAlerts,
+		"reverse_line": p.ReverseLineAlerts,
+		"sharp_money":  p.SharpMoneyAlerts,
+		"value_spot":   p.ValueSpotAlerts,
+	}
+	types := make([]string, 0, len(alertTypes))
+	for _, t := range alertTypes {
+		if enabled[t] {
+			types = append(types, t)
+		}
+	}
+
+	return PreferencesResponse{
+		MinChangePercentage: float64(p.MinChangePercentage),
+		MinMultiplier:       p.MinMultiplier,
+		MinConfidenceScore:  float64(p.MinConfidenceScore),
+		AlertTypes:          types,
+		FollowedSports:      nonNil(p.PreferredSports),
+		FollowedLeagues:     nonNil(p.PreferredLeagues),
+		MaxDailyAlerts:      p.MaxDailyAlerts,
+		PushNotifications:   p.PushNotifications,
+		QuietHoursStart:     p.QuietHoursStart,
+		QuietHoursEnd:       p.QuietHoursEnd,
+		UpdatedAt:           p.UpdatedAt.Time,
+	}
+}
+
+func toAPIKeyResponse(k generated.ApiKey, plaintext string) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.KeyPrefix,
+		Key:        plaintext,
+		LastUsedAt: timePtr(k.LastUsedAt),
+		ExpiresAt:  timePtr(k.ExpiresAt),
+		RevokedAt:  timePtr(k.RevokedAt),
+		CreatedAt:  k.CreatedAt.Time,
+	}
+}
+
+func timePtr(ts pgtype.Timestamp) *time.Time {
+	if !ts.Valid {
+		return nil
+	}
+	return &ts.Time
+}
+
+func nonNil(ids []int32) []int32 {
+	if ids == nil {
+		return []int32{}
+	}
+	return ids
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
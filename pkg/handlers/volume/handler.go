@@ -0,0 +1,239 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/models/api"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// Handler handles betting volume endpoints
+type Handler struct {
+	queries *generated.Queries
+	service *services.VolumeService
+	logger  *logger.Logger
+}
+
+// NewHandler creates a new volume handler
+func NewHandler(queries *generated.Queries, service *services.VolumeService, log *logger.Logger) *Handler {
+	return &Handler{
+		queries: queries,
+		service: service,
+		logger:  log,
+	}
+}
+
+// VolumePoint is one betting_volume_history reading
+type VolumePoint struct {
+	VolumePercentage   float32   `json:"volume_percentage"`
+	RankPosition       *int32    `json:"rank_position,omitempty"`
+	TotalEventsTracked *int32    `json:"total_events_tracked,omitempty"`
+	VolumeChange       *float32  `json:"volume_change,omitempty"`
+	RecordedAt         time.Time `json:"recorded_at"`
+}
+
+// EventVolumeHistory is an event's current volume and its recorded series, oldest first
+type EventVolumeHistory struct {
+	EventSlug               string        `json:"event_slug"`
+	Match                   string        `json:"match"`
+	Sport                   string        `json:"sport"`
+	League                  string        `json:"league"`
+	EventDate               time.Time     `json:"event_date"`
+	BettingVolumePercentage *float32      `json:"betting_volume_percentage,omitempty"`
+	VolumeRank              *int32        `json:"volume_rank,omitempty"`
+	VolumeUpdatedAt         *time.Time    `json:"volume_updated_at,omitempty"`
+	History                 []VolumePoint `json:"history"`
+}
+
+// HotMovers handles GET /api/volume/hot-movers
+// Filters: sport, min_volume (default 2), min_movement (default 10), hours of volume history (default 24), limit (default 50)
+// meta.by_sport covers every matching event, not only the returned page
+func (h *Handler) HotMovers(w http.ResponseWriter, r *http.Request) {
+	filter := parseFilter(r, 50)
+	filter.MinVolume = parseFloat(r, "min_volume", 2, 0, 100)
+	filter.MinMovement = parseFloat(r, "min_movement", 10, 0, 1000)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	movers, err := h.service.GetHotMovers(ctx, filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to get hot movers")
+		http.Error(w, "Failed to retrieve hot movers", http.StatusInternalServerError)
+		return
+	}
+
+	bySport, err := h.service.GetHotMoversBySport(ctx, filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to get hot movers by sport")
+		http.Error(w, "Failed to retrieve hot movers", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, api.Response{
+		Success: true,
+		Data:    movers,
+		Meta: map[string]any{
+			"count":        len(movers),
+			"min_volume":   filter.MinVolume,
+			"min_movement": filter.MinMovement,
+			"since":        filter.Since,
+			"by_sport":     bySport,
+		},
+	})
+}
+
+// HiddenGems handles GET /api/volume/hidden-gems
+// Filters: sport, max_volume (default 1), min_movement (default 20), hours of volume history (default 24), limit (default 20)
+// meta.by_sport covers every matching event, not only the returned page
+func (h *Handler) HiddenGems(w http.ResponseWriter, r *http.Request) {
+	filter := parseFilter(r, 20)
+	filter.MaxVolume = parseFloat(r, "max_volume", 1, 0, 100)
+	filter.MinMovement = parseFloat(r, "min_movement", 20, 0, 1000)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	gems, err := h.service.GetHiddenGems(ctx, filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to get hidden gems")
+		http.Error(w, "Failed to retrieve hidden gems", http.StatusInternalServerError)
+		return
+	}
+
+	bySport, err := h.service.GetHiddenGemsBySport(ctx, filter)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to get hidden gems by sport")
+		http.Error(w, "Failed to retrieve hidden gems", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, api.Response{
+		Success: true,
+		Data:    gems,
+		Meta: map[string]any{
+			"count":        len(gems),
+			"max_volume":   filter.MaxVolume,
+			"min_movement": filter.MinMovement,
+			"since":        filter.Since,
+			"by_sport":     bySport,
+		},
+	})
+}
+
+// EventHistory handles GET /api/events/{slug}/volume-history
+// Query: hours back (default 72, max 720), limit on readings (default 500, max 2000)
+func (h *Handler) EventHistory(w http.ResponseWriter, r *http.Request) {
+	slug := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/events/"), "/volume-history")
+	if slug == "" || strings.Contains(slug, "/") {
+		http.Error(w, "Invalid event slug", http.StatusBadRequest)
+		return
+	}
+
+	hours := parseInt(r, "hours", 72, 1, 720)
+	limit := parseInt(r, "limit", 500, 1, 2000)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	event, err := h.queries.GetEventBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to get event")
+		http.Error(w, "Failed to get event", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := h.queries.GetVolumeHistory(ctx, generated.GetVolumeHistoryParams{
+		EventID:     event.ID,
+		SinceTime:   pgtype.Timestamp{Time: time.Now().Add(-time.Duration(hours) * time.Hour), Valid: true},
+		LimitCount:  int32(limit),
+		OffsetCount: 0,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Str("slug", slug).Msg("Failed to get volume history")
+		http.Error(w, "Failed to retrieve volume history", http.StatusInternalServerError)
+		return
+	}
+
+	// Rows come newest first; charts want them oldest first
+	history := make([]VolumePoint, len(rows))
+	for i, row := range rows {
+		history[len(rows)-1-i] = VolumePoint{
+			VolumePercentage:   row.VolumePercentage,
+			RankPosition:       row.RankPosition,
+			TotalEventsTracked: row.TotalEventsTracked,
+			VolumeChange:       row.VolumeChange,
+			RecordedAt:         row.RecordedAt.Time,
+		}
+	}
+
+	response := EventVolumeHistory{
+		EventSlug:               event.Slug,
+		Match:                   event.HomeTeamName + " vs " + event.AwayTeamName,
+		Sport:                   event.SportName,
+		League:                  event.LeagueName,
+		EventDate:               event.EventDate.Time,
+		BettingVolumePercentage: event.BettingVolumePercentage,
+		VolumeRank:              event.VolumeRank,
+		History:                 history,
+	}
+	if event.VolumeUpdatedAt.Valid {
+		response.VolumeUpdatedAt = &event.VolumeUpdatedAt.Time
+	}
+
+	h.writeJSON(w, api.Response{
+		Success: true,
+		Data:    response,
+		Meta: map[string]any{
+			"hours":  hours,
+			"points": len(history),
+		},
+	})
+}
+
+// parseFilter reads the sport, hours and limit parameters shared by the volume searches
+func parseFilter(r *http.Request, defaultLimit int) services.VolumeFilter {
+	hours := parseInt(r, "hours", 24, 1, 168)
+	return services.VolumeFilter{
+		Sport: r.URL.Query().Get("sport"),
+		Since: time.Now().Add(-time.Duration(hours) * time.Hour),
+		Limit: parseInt(r, "limit", defaultLimit, 1, 200),
+	}
+}
+
+func parseInt(r *http.Request, name string, def, minValue, maxValue int) int {
+	if v, err := strconv.Atoi(r.URL.Query().Get(name)); err == nil && v >= minValue && v <= maxValue {
+		return v
+	}
+	return def
+}
+
+func parseFloat(r *http.Request, name string, def, minValue, maxValue float64) float64 {
+	if v, err := strconv.ParseFloat(r.URL.Query().Get(name), 64); err == nil && v >= minValue && v <= maxValue {
+		return v
+	}
+	return def
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, response api.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
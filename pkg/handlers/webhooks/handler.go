@@ -0,0 +1,448 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/middleware"
+	"github.com/iddaa-lens/core/pkg/models/api"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+var validSeverities = map[string]bool{"low": true, "medium": true, "high": true, "critical": true}
+
+// Handler handles webhook subscription endpoints
+type Handler struct {
+	queries *generated.Queries
+	logger  *logger.Logger
+}
+
+// NewHandler creates a new webhooks handler
+func NewHandler(queries *generated.Queries, log *logger.Logger) *Handler {
+	return &Handler{
+		queries: queries,
+		logger:  log,
+	}
+}
+
+// CreateRequest is the body of POST /api/webhooks
+type CreateRequest struct {
+	Name          string   `json:"name"`
+	URL           string   `json:"url"`
+	Secret        string   `json:"secret"`
+	AlertTypes    []string `json:"alert_types"`
+	Severities    []string `json:"severities"`
+	SportCodes    []string `json:"sport_codes"`
+	MinConfidence float64  `json:"min_confidence"`
+}
+
+// SubscriptionResponse represents a webhook subscription without its secret
+type SubscriptionResponse struct {
+	ID            int32     `json:"id"`
+	Name          string    `json:"name"`
+	URL           string    `json:"url"`
+	Secret        string    `json:"secret,omitempty"`
+	AlertTypes    []string  `json:"alert_types"`
+	Severities    []string  `json:"severities"`
+	SportCodes    []string  `json:"sport_codes"`
+	MinConfidence float64   `json:"min_confidence"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DeliveryResponse represents one webhook delivery and its attempts
+type DeliveryResponse struct {
+	ID             int32             `json:"id"`
+	AlertID        int32             `json:"alert_id"`
+	Status         string            `json:"status"`
+	Attempts       int32             `json:"attempts"`
+	ResponseStatus *int32            `json:"response_status,omitempty"`
+	LastError      *string           `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time        `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time        `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	History        []AttemptResponse `json:"history,omitempty"`
+}
+
+// AttemptResponse represents a single HTTP attempt
+type AttemptResponse struct {
+	Attempt        int32     `json:"attempt"`
+	ResponseStatus *int32    `json:"response_status,omitempty"`
+	Error          *string   `json:"error,omitempty"`
+	DurationMs     int32     `json:"duration_ms"`
+	AttemptedAt    time.Time `json:"attempted_at"`
+}
+
+// List handles GET /api/webhooks, the active subscriptions of the caller
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	subscriptions, err := h.queries.ListWebhookSubscriptions(ctx, user.ID)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list webhook subscriptions")
+		http.Error(w, "Failed to list webhooks", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]SubscriptionResponse, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		response = append(response, toSubscriptionResponse(s, false))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.Response{
+		Success: true,
+		Data:    response,
+		Meta: map[string]any{
+			"total": len(response),
+		},
+	}); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// Create handles POST /api/webhooks
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	parsed, err := url.Parse(req.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		http.Error(w, "Invalid webhook URL", http.StatusBadRequest)
+		return
+	}
+	if req.MinConfidence < 0 || req.MinConfidence > 1 {
+		http.Error(w, "min_confidence must be between 0 and 1", http.StatusBadRequest)
+		return
+	}
+	for _, severity := range req.Severities {
+		if !validSeverities[severity] {
+			http.Error(w, "Invalid severity: "+severity, http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Name == "" {
+		req.Name = parsed.Host
+	}
+	if req.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			http.Error(w, "Failed to generate secret", http.StatusInternalServerError)
+			return
+		}
+		req.Secret = hex.EncodeToString(secret)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	// Deliveries run inside the deployment, internal addresses are refused
+	if err := services.ValidateWebhookURL(ctx, req.URL); err != nil {
+		if errors.Is(err, services.ErrWebhookAddressBlocked) {
+			http.Error(w, "Webhook URL must resolve to a public address", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Invalid webhook URL: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := h.queries.CreateWebhookSubscription(ctx, generated.CreateWebhookSubscriptionParams{
+		UserID:        user.ID,
+		Name:          req.Name,
+		Url:           req.URL,
+		Secret:        req.Secret,
+		AlertTypes:    nonNil(req.AlertTypes),
+		Severities:    nonNil(req.Severities),
+		SportCodes:    nonNil(req.SportCodes),
+		MinConfidence: req.MinConfidence,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to create webhook subscription")
+		http.Error(w, "Failed to create webhook", http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info().
+		Int32("subscription_id", subscription.ID).
+		Int32("user_id", user.ID).
+		Str("host", parsed.Host).
+		Msg("Webhook subscription created")
+
+	// The secret is only returned once, on creation
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(api.Response{
+		Success: true,
+		Data:    toSubscriptionResponse(subscription, true),
+	}); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+	}
+}
+
+// Delete handles DELETE /api/webhooks/{id}
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(path.Base(r.URL.Path), 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	affected, err := h.queries.DeactivateWebhookSubscription(ctx, generated.DeactivateWebhookSubscriptionParams{
+		ID:     int32(id),
+		UserID: user.ID,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Int64("subscription_id", id).Msg("Failed to deactivate webhook")
+		http.Error(w, "Failed to delete webhook", http.StatusInternalServerError)
+		return
+	}
+	if affected == 0 {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.Response{
+		Success: true,
+		Message: "Webhook deleted",
+	}); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+	}
+}
+
+// Deliveries handles GET /api/webhooks/{id}/deliveries
+func (h *Handler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(path.Base(path.Dir(r.URL.Path)), 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed >= 1 && parsed <= 200 {
+			limit = parsed
+		}
+	}
+	withHistory := r.URL.Query().Get("history") == "true"
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if !h.ownsSubscription(ctx, w, int32(id), user.ID) {
+		return
+	}
+
+	deliveries, err := h.queries.ListWebhookDeliveries(ctx, generated.ListWebhookDeliveriesParams{
+		SubscriptionID: int32(id),
+		UserID:         user.ID,
+		LimitCount:     int32(limit),
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Int64("subscription_id", id).Msg("Failed to list webhook deliveries")
+		http.Error(w, "Failed to list deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]DeliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		item := DeliveryResponse{
+			ID:             d.ID,
+			AlertID:        d.AlertID,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			ResponseStatus: d.ResponseStatus,
+			LastError:      d.LastError,
+			CreatedAt:      d.CreatedAt.Time,
+		}
+		if d.Status == "pending" || d.Status == "retrying" {
+			item.NextAttemptAt = timePtr(d.NextAttemptAt.Time, d.NextAttemptAt.Valid)
+		}
+		item.DeliveredAt = timePtr(d.DeliveredAt.Time, d.DeliveredAt.Valid)
+
+		if withHistory {
+			attempts, err := h.queries.ListWebhookDeliveryAttempts(ctx, d.ID)
+			if err != nil {
+				h.logger.Error().Err(err).Int32("delivery_id", d.ID).Msg("Failed to list delivery attempts")
+				http.Error(w, "Failed to list deliveries", http.StatusInternalServerError)
+				return
+			}
+			for _, a := range attempts {
+				item.History = append(item.History, AttemptResponse{
+					Attempt:        a.Attempt,
+					ResponseStatus: a.ResponseStatus,
+					Error:          a.Error,
+					DurationMs:     a.DurationMs,
+					AttemptedAt:    a.AttemptedAt.Time,
+				})
+			}
+		}
+
+		response = append(response, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.Response{
+		Success: true,
+		Data:    response,
+		Meta: map[string]any{
+			"total": len(response),
+		},
+	}); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// DeadLetters handles GET /api/webhooks/{id}/dead-letters
+func (h *Handler) DeadLetters(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(path.Base(path.Dir(r.URL.Path)), 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if !h.ownsSubscription(ctx, w, int32(id), user.ID) {
+		return
+	}
+
+	deadLetters, err := h.queries.ListWebhookDeadLetters(ctx, generated.ListWebhookDeadLettersParams{
+		SubscriptionID: int32(id),
+		UserID:         user.ID,
+		LimitCount:     100,
+	})
+	if err != nil {
+		h.logger.Error().Err(err).Int64("subscription_id", id).Msg("Failed to list dead letters")
+		http.Error(w, "Failed to list dead letters", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.Response{
+		Success: true,
+		Data:    deadLetters,
+		Meta: map[string]any{
+			"total": len(deadLetters),
+		},
+	}); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// Route dispatches /api/webhooks/{id}[/deliveries|/dead-letters]
+func (h *Handler) Route(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/deliveries"):
+		h.Deliveries(w, r)
+	case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/dead-letters"):
+		h.DeadLetters(w, r)
+	case r.Method == "DELETE":
+		h.Delete(w, r)
+	default:
+		http.Error(w, "Not Found", http.StatusNotFound)
+	}
+}
+
+// ownsSubscription answers 404 unless the subscription belongs to the user, deactivated ones
+// included
+func (h *Handler) ownsSubscription(ctx context.Context, w http.ResponseWriter, id, userID int32) bool {
+	_, err := h.queries.GetWebhookSubscription(ctx, generated.GetWebhookSubscriptionParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return false
+	}
+	h.logger.Error().Err(err).Int32("subscription_id", id).Msg("Failed to get webhook subscription")
+	http.Error(w, "Failed to get webhook", http.StatusInternalServerError)
+	return false
+}
+
+func toSubscriptionResponse(s generated.WebhookSubscription, withSecret bool) SubscriptionResponse {
+	response := SubscriptionResponse{
+		ID:            s.ID,
+		Name:          s.Name,
+		URL:           s.Url,
+		AlertTypes:    s.AlertTypes,
+		Severities:    s.Severities,
+		SportCodes:    s.SportCodes,
+		MinConfidence: float64(s.MinConfidence),
+		CreatedAt:     s.CreatedAt.Time,
+	}
+	if withSecret {
+		response.Secret = s.Secret
+	}
+	return response
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func timePtr(t time.Time, valid bool) *time.Time {
+	if !valid {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,268 @@
+package iddaamock
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fixture is one recorded Iddaa response
+type Fixture struct {
+	URL         string          `json:"url"`
+	Host        string          `json:"host"`
+	Path        string          `json:"path"`
+	Query       string          `json:"query,omitempty"`
+	Status      int             `json:"status"`
+	ContentType string          `json:"content_type,omitempty"`
+	RecordedAt  time.Time       `json:"recorded_at"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	// BodyText holds responses that are not JSON, such as error pages
+	BodyText string `json:"body_text,omitempty"`
+}
+
+// NewFixture builds a fixture for a response body received from u
+func NewFixture(u *url.URL, status int, contentType string, body []byte, recordedAt time.Time) Fixture {
+	fixture := Fixture{
+		URL:         u.String(),
+		Host:        u.Host,
+		Path:        u.Path,
+		Query:       canonicalQuery(u.Query()),
+		Status:      status,
+		ContentType: contentType,
+		RecordedAt:  recordedAt,
+	}
+	if json.Valid(body) {
+		fixture.Body = json.RawMessage(body)
+	} else {
+		fixture.BodyText = string(body)
+	}
+	return fixture
+}
+
+// Key identifies the request a fixture answers. Iddaa paths do not overlap between hosts,
+// so the key leaves the host out and a single mock server can stand in for all of them.
+func (f Fixture) Key() string {
+	return requestKey(f.Path, f.Query)
+}
+
+// Payload returns the recorded response body
+func (f Fixture) Payload() []byte {
+	if f.Body != nil {
+		return f.Body
+	}
+	return []byte(f.BodyText)
+}
+
+// FileName returns the fixture's path relative to the fixture directory. The readable part flattens
+// the path and query, which can map distinct requests to one name, so a hash of the request follows it.
+func (f Fixture) FileName() string {
+	name := strings.Trim(f.Path, "/")
+	if f.Query != "" {
+		name += "__" + f.Query
+	}
+	name = strings.NewReplacer("/", "_", "&", "_", ":", "-", "%", "-").Replace(name)
+	sum := sha256.Sum256([]byte(f.Host + f.Key()))
+	return filepath.Join(f.Host, name+"-"+hex.EncodeToString(sum[:6])+".json")
+}
+
+// WriteFixture saves a fixture under dir, replacing an earlier recording of the same request
+func WriteFixture(dir string, fixture Fixture) error {
+	path := filepath.Join(dir, fixture.FileName())
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+
+	// Write then rename so the mock server never reads a half written file
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".fixture-*")
+	if err != nil {
+		return fmt.Errorf("failed to create fixture file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save fixture: %w", err)
+	}
+	return nil
+}
+
+// Store holds the fixtures of a recording directory
+type Store struct {
+	mu       sync.RWMutex
+	fixtures map[string]Fixture
+	// Fixtures grouped by path for lookups that ignore part of the query
+	byPath map[string][]Fixture
+}
+
+// NewStore creates a store from fixtures
+func NewStore(fixtures ...Fixture) *Store {
+	s := &Store{
+		fixtures: make(map[string]Fixture),
+		byPath:   make(map[string][]Fixture),
+	}
+	for _, fixture := range fixtures {
+		s.Add(fixture)
+	}
+	return s
+}
+
+// LoadStore reads every fixture below dir
+func LoadStore(dir string) (*Store, error) {
+	store := NewStore()
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read fixture %s: %w", path, err)
+		}
+		var fixture Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return fmt.Errorf("failed to decode fixture %s: %w", path, err)
+		}
+		store.Add(fixture)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Add adds a fixture, keeping the most recent recording of a request
+func (s *Store) Add(fixture Fixture) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := fixture.Key()
+	if existing, ok := s.fixtures[key]; ok && existing.RecordedAt.After(fixture.RecordedAt) {
+		return
+	}
+	s.fixtures[key] = fixture
+
+	list := s.byPath[fixture.Path]
+	for i, f := range list {
+		if f.Key() == key {
+			list = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	s.byPath[fixture.Path] = append(list, fixture)
+}
+
+// Get returns the fixture recorded for exactly this path and query
+func (s *Store) Get(path string, query url.Values) (Fixture, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fixture, ok := s.fixtures[requestKey(path, canonicalQuery(query))]
+	return fixture, ok
+}
+
+// Match returns the most recent fixture for path whose query has the given values,
+// ignoring any other parameters
+func (s *Store) Match(path string, want url.Values) (Fixture, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var (
+		best  Fixture
+		found bool
+	)
+	for _, fixture := range s.byPath[path] {
+		query, err := url.ParseQuery(fixture.Query)
+		if err != nil || !hasValues(query, want) {
+			continue
+		}
+		if !found || fixture.RecordedAt.After(best.RecordedAt) {
+			best, found = fixture, true
+		}
+	}
+	return best, found
+}
+
+// ByPath returns the fixtures recorded for path
+func (s *Store) ByPath(path string) []Fixture {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Fixture(nil), s.byPath[path]...)
+}
+
+// All returns every fixture ordered by key
+func (s *Store) All() []Fixture {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fixtures := make([]Fixture, 0, len(s.fixtures))
+	for _, fixture := range s.fixtures {
+		fixtures = append(fixtures, fixture)
+	}
+	sort.Slice(fixtures, func(i, j int) bool { return fixtures[i].Key() < fixtures[j].Key() })
+	return fixtures
+}
+
+// LatestRecordedAt returns when the newest fixture was recorded
+func (s *Store) LatestRecordedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest time.Time
+	for _, fixture := range s.fixtures {
+		if fixture.RecordedAt.After(latest) {
+			latest = fixture.RecordedAt
+		}
+	}
+	return latest
+}
+
+// Len returns the number of fixtures
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.fixtures)
+}
+
+func requestKey(path, query string) string {
+	if query == "" {
+		return path
+	}
+	return path + "?" + query
+}
+
+// canonicalQuery encodes a query with sorted keys so parameter order does not matter
+func canonicalQuery(query url.Values) string {
+	return query.Encode()
+}
+
+func hasValues(query, want url.Values) bool {
+	for key := range want {
+		if query.Get(key) != want.Get(key) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,244 @@
+package iddaamock
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+// Scenario actions
+const (
+	ActionDrift   = "drift"
+	ActionGoal    = "goal"
+	ActionSuspend = "suspend"
+	ActionResume  = "resume"
+)
+
+// Market statuses written for suspend and resume steps
+const (
+	marketStatusSuspended = 2
+	minOdds               = 1.01
+)
+
+// Scenario scripts changes on top of the recorded responses, timed from the start of the mock server
+type Scenario struct {
+	Name  string `json:"name"`
+	Steps []Step `json:"steps"`
+}
+
+// Step is one scripted change to an event
+type Step struct {
+	// Offset from the start of the scenario
+	At     Duration `json:"at"`
+	Action string   `json:"action"`
+	// Iddaa event id (IddaaEvent.ID)
+	EventID int `json:"event"`
+	// Market type (IddaaMarket.Type) and special value the step targets, 0 for all markets
+	MarketType   int    `json:"market_type,omitempty"`
+	SpecialValue string `json:"special_value,omitempty"`
+	// Outcome number (IddaaOutcome.Number) a drift targets, 0 for all outcomes
+	Outcome int `json:"outcome,omitempty"`
+	// Drift: total odds change in percent, reached gradually over Over (at once when Over is 0)
+	ChangePct float64  `json:"change_pct,omitempty"`
+	Over      Duration `json:"over,omitempty"`
+	// Goal: scoring side (home or away) and optionally the match minute
+	Team   string `json:"team,omitempty"`
+	Minute int    `json:"minute,omitempty"`
+}
+
+// Duration reads either a Go duration string ("90s", "2m") or a number of seconds
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		parsed, err := time.ParseDuration(text)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", text, err)
+		}
+		d.Duration = parsed
+		return nil
+	}
+
+	seconds, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	d.Duration = time.Duration(seconds * float64(time.Second))
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// LoadScenario reads and validates a scenario file
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	var scenario Scenario
+	if err := json.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("failed to decode scenario: %w", err)
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return &scenario, nil
+}
+
+// Validate checks every step and orders the steps by time
+func (s *Scenario) Validate() error {
+	for i, step := range s.Steps {
+		if step.EventID == 0 {
+			return fmt.Errorf("step %d: event is required", i+1)
+		}
+		switch step.Action {
+		case ActionDrift:
+			if step.ChangePct <= -100 {
+				return fmt.Errorf("step %d: change_pct must be above -100", i+1)
+			}
+		case ActionGoal:
+			if step.Team != "home" && step.Team != "away" {
+				return fmt.Errorf("step %d: team must be home or away", i+1)
+			}
+		case ActionSuspend, ActionResume:
+		default:
+			return fmt.Errorf("step %d: unknown action %q", i+1, step.Action)
+		}
+	}
+
+	sort.SliceStable(s.Steps, func(i, j int) bool { return s.Steps[i].At.Duration < s.Steps[j].At.Duration })
+	return nil
+}
+
+// active returns the steps for an event that have started by elapsed
+func (s *Scenario) active(eventID int, elapsed time.Duration) []Step {
+	if s == nil {
+		return nil
+	}
+	var steps []Step
+	for _, step := range s.Steps {
+		if step.At.Duration > elapsed {
+			break
+		}
+		if step.EventID == eventID {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// ApplyEvent applies the started steps to a bulletin event
+func (s *Scenario) ApplyEvent(event *models.IddaaEvent, elapsed time.Duration) {
+	for _, step := range s.active(event.ID, elapsed) {
+		if step.Action == ActionGoal {
+			event.IsLive = true
+			continue
+		}
+		for i := range event.Markets {
+			step.applyMarket(&event.Markets[i], elapsed)
+		}
+	}
+}
+
+// ApplySingleEvent applies the started steps to a single event response
+func (s *Scenario) ApplySingleEvent(event *models.IddaaSingleEvent, elapsed time.Duration) {
+	for _, step := range s.active(event.ID, elapsed) {
+		if step.Action == ActionGoal {
+			event.IsLive = true
+			continue
+		}
+		for i := range event.Markets {
+			detailed := &event.Markets[i]
+			market := models.IddaaMarket{
+				Type:         detailed.Type,
+				Status:       detailed.Status,
+				SpecialValue: detailed.SpecialValue,
+				Outcomes:     make([]models.IddaaOutcome, len(detailed.Outcomes)),
+			}
+			for j, outcome := range detailed.Outcomes {
+				market.Outcomes[j] = models.IddaaOutcome(outcome)
+			}
+
+			step.applyMarket(&market, elapsed)
+
+			detailed.Status = market.Status
+			for j, outcome := range market.Outcomes {
+				detailed.Outcomes[j] = models.IddaaDetailedOutcome(outcome)
+			}
+		}
+	}
+}
+
+// ApplyStatistics applies the goals scripted for an event to its statistics
+func (s *Scenario) ApplyStatistics(stats *models.IddaaEventStatistics, elapsed time.Duration) {
+	for _, step := range s.active(stats.EventID, elapsed) {
+		if step.Action != ActionGoal {
+			continue
+		}
+		stats.IsLive = true
+		if step.Minute > 0 {
+			stats.MinuteOfMatch = step.Minute
+		}
+
+		isHome := step.Team == "home"
+		if isHome {
+			stats.HomeScore++
+		} else {
+			stats.AwayScore++
+		}
+		stats.Events = append(stats.Events, models.IddaaMatchEvent{
+			Minute:    stats.MinuteOfMatch,
+			EventType: "Goal",
+			IsHome:    isHome,
+		})
+	}
+}
+
+func (step Step) matchesMarket(market *models.IddaaMarket) bool {
+	if step.MarketType != 0 && market.Type != step.MarketType {
+		return false
+	}
+	return step.SpecialValue == "" || market.SpecialValue == step.SpecialValue
+}
+
+func (step Step) applyMarket(market *models.IddaaMarket, elapsed time.Duration) {
+	if !step.matchesMarket(market) {
+		return
+	}
+
+	switch step.Action {
+	case ActionSuspend:
+		market.Status = marketStatusSuspended
+	case ActionResume:
+		market.Status = models.MarketStatusOpen
+	case ActionDrift:
+		factor := 1 + step.ChangePct/100*step.progress(elapsed)
+		for i := range market.Outcomes {
+			outcome := &market.Outcomes[i]
+			if step.Outcome != 0 && outcome.Number != step.Outcome {
+				continue
+			}
+			outcome.Odds = math.Max(minOdds, math.Round(outcome.Odds*factor*100)/100)
+		}
+	}
+}
+
+// progress returns how much of a drift has happened, from 0 to 1
+func (step Step) progress(elapsed time.Duration) float64 {
+	if step.Over.Duration <= 0 {
+		return 1
+	}
+	return math.Min(1, float64(elapsed-step.At.Duration)/float64(step.Over.Duration))
+}
@@ -0,0 +1,353 @@
+package iddaamock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+const (
+	eventsPath     = "/sportsbook/events"
+	singleEvent    = "/sportsbook/event/"
+	statisticsPath = "/broadage/getEventListCache"
+	searchDate     = "2006-01-02"
+)
+
+// Layouts tried when shifting statistics match dates
+var matchDateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05"}
+
+// Options control how recorded responses are replayed
+type Options struct {
+	// Shift is added to every recorded event date, so a recording made yesterday can play as today
+	Shift time.Duration
+	// Scenario scripts odds drift, goals and suspensions on top of the recording, nil for plain replay
+	Scenario *Scenario
+	// Now is the clock used for scenario timing, time.Now when nil
+	Now func() time.Time
+}
+
+// Server replays recorded Iddaa responses. All Iddaa hosts share one server because their paths do not overlap.
+// Responses that are rewritten for time shifting or scenarios are re-encoded from the models package,
+// so they only carry the fields the client decodes.
+type Server struct {
+	store   *Store
+	options Options
+	mux     *http.ServeMux
+	logger  *logger.Logger
+
+	mu      sync.RWMutex
+	started time.Time
+}
+
+// NewServer creates a mock server replaying the fixtures in store
+func NewServer(store *Store, options Options) *Server {
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+
+	s := &Server{
+		store:   store,
+		options: options,
+		mux:     http.NewServeMux(),
+		logger:  logger.New("iddaa-mock"),
+		started: options.Now(),
+	}
+
+	s.mux.HandleFunc(eventsPath, s.handleEvents)
+	s.mux.HandleFunc(singleEvent, s.handleSingleEvent)
+	s.mux.HandleFunc(statisticsPath, s.handleStatistics)
+	s.mux.HandleFunc("/_mock/fixtures", s.handleFixtures)
+	s.mux.HandleFunc("/_mock/reset", s.handleReset)
+	// Competitions, sport info, market config, app config and the play percentage endpoints are served as recorded
+	s.mux.HandleFunc("/", s.handleRecorded)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.logger.Debug().
+		Str("method", r.Method).
+		Str("path", r.URL.Path).
+		Str("query", r.URL.RawQuery).
+		Msg("Mock request")
+	s.mux.ServeHTTP(w, r)
+}
+
+// Reset restarts the scenario clock
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = s.options.Now()
+}
+
+// Elapsed returns the time since the scenario clock started
+func (s *Server) Elapsed() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.options.Now().Sub(s.started)
+}
+
+func (s *Server) handleRecorded(w http.ResponseWriter, r *http.Request) {
+	fixture, ok := s.store.Get(r.URL.Path, r.URL.Query())
+	if !ok {
+		s.notFound(w, r)
+		return
+	}
+	s.writeFixture(w, fixture, fixture.Payload())
+}
+
+// handleEvents serves the bulletin. A diff request for a version that was not recorded gets the
+// full bulletin, which the client treats like Iddaa no longer holding the version.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	fixture, ok := s.store.Get(r.URL.Path, query)
+	if !ok {
+		full := url.Values{"st": {query.Get("st")}, "type": {query.Get("type")}, "version": {"0"}}
+		fixture, ok = s.store.Match(r.URL.Path, full)
+	}
+	if !ok {
+		s.notFound(w, r)
+		return
+	}
+
+	var response models.IddaaEventsResponse
+	if fixture.Body == nil || json.Unmarshal(fixture.Body, &response) != nil || response.Data == nil {
+		s.writeFixture(w, fixture, fixture.Payload())
+		return
+	}
+
+	elapsed := s.Elapsed()
+	for i := range response.Data.Events {
+		event := &response.Data.Events[i]
+		event.Date = s.shiftUnix(event.Date)
+		s.options.Scenario.ApplyEvent(event, elapsed)
+	}
+	s.writeJSON(w, fixture, response)
+}
+
+// handleSingleEvent serves /sportsbook/event/{id}, falling back to the event's entry in a recorded bulletin
+func (s *Server) handleSingleEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, singleEvent))
+	if err != nil {
+		http.Error(w, "Invalid event id", http.StatusBadRequest)
+		return
+	}
+
+	var response models.IddaaSingleEventResponse
+	fixture, ok := s.store.Get(r.URL.Path, nil)
+	if ok {
+		if fixture.Body == nil || json.Unmarshal(fixture.Body, &response) != nil {
+			s.writeFixture(w, fixture, fixture.Payload())
+			return
+		}
+	} else {
+		fixture, response, ok = s.eventFromBulletin(eventID)
+		if !ok {
+			s.notFound(w, r)
+			return
+		}
+	}
+
+	response.Data.Date = s.shiftUnix(response.Data.Date)
+	s.options.Scenario.ApplySingleEvent(&response.Data, s.Elapsed())
+	s.writeJSON(w, fixture, response)
+}
+
+// eventFromBulletin builds a single event response from the most recent bulletin containing the event
+func (s *Server) eventFromBulletin(eventID int) (Fixture, models.IddaaSingleEventResponse, bool) {
+	var (
+		found    Fixture
+		response models.IddaaSingleEventResponse
+		ok       bool
+	)
+	for _, fixture := range s.store.ByPath(eventsPath) {
+		if ok && !fixture.RecordedAt.After(found.RecordedAt) {
+			continue
+		}
+
+		var bulletin models.IddaaEventsResponse
+		if json.Unmarshal(fixture.Body, &bulletin) != nil || bulletin.Data == nil {
+			continue
+		}
+		for _, event := range bulletin.Data.Events {
+			if event.ID != eventID {
+				continue
+			}
+			// The single event shape uses the same field names as the bulletin entry
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			var single models.IddaaSingleEvent
+			if json.Unmarshal(data, &single) != nil {
+				continue
+			}
+			found, ok = fixture, true
+			response = models.IddaaSingleEventResponse{IsSuccess: true, Data: single}
+			break
+		}
+	}
+	return found, response, ok
+}
+
+// handleStatistics serves the statistics feed. The requested date is mapped back by the time shift,
+// and any recording for the sport is used when that date was not recorded.
+func (s *Server) handleStatistics(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	sportID := query.Get("SportId")
+
+	fixture, ok := s.store.Get(r.URL.Path, query)
+	if !ok {
+		if day, err := time.Parse(searchDate, query.Get("SearchDate")); err == nil {
+			recorded := url.Values{"SportId": {sportID}, "SearchDate": {day.Add(-s.options.Shift).Format(searchDate)}}
+			fixture, ok = s.store.Get(r.URL.Path, recorded)
+		}
+	}
+	if !ok {
+		fixture, ok = s.store.Match(r.URL.Path, url.Values{"SportId": {sportID}})
+	}
+	if !ok {
+		s.notFound(w, r)
+		return
+	}
+
+	elapsed := s.Elapsed()
+	rewrite := func(stats []models.IddaaEventStatistics) {
+		for i := range stats {
+			stats[i].MatchDate = s.shiftMatchDate(stats[i].MatchDate)
+			s.options.Scenario.ApplyStatistics(&stats[i], elapsed)
+		}
+	}
+
+	// The feed answers either with the usual wrapper or a bare array; other shapes are replayed untouched
+	var wrapped models.IddaaAPIResponse[models.IddaaEventStatistics]
+	if fixture.Body != nil && json.Unmarshal(fixture.Body, &wrapped) == nil && wrapped.IsSuccess {
+		rewrite(wrapped.Data)
+		s.writeJSON(w, fixture, wrapped)
+		return
+	}
+	var direct []models.IddaaEventStatistics
+	if fixture.Body != nil && json.Unmarshal(fixture.Body, &direct) == nil {
+		rewrite(direct)
+		s.writeJSON(w, fixture, direct)
+		return
+	}
+	s.writeFixture(w, fixture, fixture.Payload())
+}
+
+// FixtureInfo describes a loaded fixture in /_mock/fixtures
+type FixtureInfo struct {
+	Key        string    `json:"key"`
+	Host       string    `json:"host"`
+	Status     int       `json:"status"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+func (s *Server) handleFixtures(w http.ResponseWriter, r *http.Request) {
+	fixtures := s.store.All()
+	infos := make([]FixtureInfo, len(fixtures))
+	for i, fixture := range fixtures {
+		infos[i] = FixtureInfo{
+			Key:        fixture.Key(),
+			Host:       fixture.Host,
+			Status:     fixture.Status,
+			RecordedAt: fixture.RecordedAt,
+		}
+	}
+
+	scenario := ""
+	if s.options.Scenario != nil {
+		scenario = s.options.Scenario.Name
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"fixtures": infos,
+		"shift":    s.options.Shift.String(),
+		"scenario": scenario,
+		"elapsed":  s.Elapsed().String(),
+	})
+}
+
+func (s *Server) handleReset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.Reset()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) shiftUnix(seconds int64) int64 {
+	if seconds == 0 {
+		return 0
+	}
+	return seconds + int64(s.options.Shift/time.Second)
+}
+
+func (s *Server) shiftMatchDate(value string) string {
+	if s.options.Shift == 0 {
+		return value
+	}
+	for _, layout := range matchDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Add(s.options.Shift).Format(layout)
+		}
+	}
+	return value
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, fixture Fixture, response any) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		s.logger.Error().Err(err).Str("key", fixture.Key()).Msg("Failed to encode rewritten fixture")
+		data = fixture.Payload()
+	}
+	s.writeFixture(w, fixture, data)
+}
+
+func (s *Server) writeFixture(w http.ResponseWriter, fixture Fixture, body []byte) {
+	contentType := fixture.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	status := fixture.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+func (s *Server) notFound(w http.ResponseWriter, r *http.Request) {
+	s.logger.Warn().
+		Str("path", r.URL.Path).
+		Str("query", r.URL.RawQuery).
+		Msg("No fixture recorded for request")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"isSuccess": false,
+		"data":      nil,
+		"message":   "no fixture recorded for " + r.URL.RequestURI(),
+	})
+}
+
+// ShiftToDay returns the whole number of days that moves recordedAt onto the day of now,
+// keeping kickoff times of day and statistics dates aligned
+func ShiftToDay(recordedAt, now time.Time) time.Duration {
+	if recordedAt.IsZero() {
+		return 0
+	}
+	from := time.Date(recordedAt.Year(), recordedAt.Month(), recordedAt.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return to.Sub(from)
+}
@@ -0,0 +1,226 @@
+package iddaamock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+var recordedAt = time.Date(2025, 6, 5, 12, 0, 0, 0, time.UTC)
+
+func fixtureFor(t *testing.T, rawURL string, body any) Fixture {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewFixture(u, http.StatusOK, "application/json", data, recordedAt)
+}
+
+func bulletin() models.IddaaEventsResponse {
+	return models.IddaaEventsResponse{
+		IsSuccess: true,
+		Data: &models.IddaaEventsData{
+			Version: 100,
+			Events: []models.IddaaEvent{{
+				ID:       42,
+				HomeTeam: "Home",
+				AwayTeam: "Away",
+				Date:     recordedAt.Add(3 * time.Hour).Unix(),
+				Markets: []models.IddaaMarket{{
+					Type:   1,
+					Status: models.MarketStatusOpen,
+					Outcomes: []models.IddaaOutcome{
+						{Number: 1, Odds: 2.00},
+						{Number: 2, Odds: 3.20},
+						{Number: 3, Odds: 3.50},
+					},
+				}},
+			}},
+		},
+	}
+}
+
+func get(t *testing.T, server *Server, target string, into any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if into != nil && rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), into); err != nil {
+			t.Fatalf("decode %s: %v", target, err)
+		}
+	}
+	return rec.Code
+}
+
+func TestServer_EventsShiftAndScenario(t *testing.T) {
+	store := NewStore(fixtureFor(t, "https://sportsbookv2.iddaa.com/sportsbook/events?st=1&type=0&version=0", bulletin()))
+
+	now := recordedAt
+	scenario := &Scenario{Steps: []Step{
+		{At: Duration{time.Minute}, Action: ActionDrift, EventID: 42, MarketType: 1, Outcome: 1, ChangePct: -10, Over: Duration{2 * time.Minute}},
+		{At: Duration{2 * time.Minute}, Action: ActionSuspend, EventID: 42, MarketType: 1},
+	}}
+	if err := scenario.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer(store, Options{Shift: 24 * time.Hour, Scenario: scenario, Now: func() time.Time { return now }})
+
+	// A diff from an unrecorded version falls back to the full bulletin
+	var response models.IddaaEventsResponse
+	if code := get(t, server, "/sportsbook/events?st=1&type=0&version=55", &response); code != http.StatusOK {
+		t.Fatalf("status = %d", code)
+	}
+	event := response.Data.Events[0]
+	if want := recordedAt.Add(27 * time.Hour).Unix(); event.Date != want {
+		t.Errorf("date = %d, want %d", event.Date, want)
+	}
+	if event.Markets[0].Outcomes[0].Odds != 2.00 {
+		t.Errorf("odds before the drift = %v, want 2.00", event.Markets[0].Outcomes[0].Odds)
+	}
+
+	// Halfway through the drift
+	now = recordedAt.Add(2 * time.Minute)
+	get(t, server, "/sportsbook/events?type=0&st=1&version=0", &response)
+	market := response.Data.Events[0].Markets[0]
+	if market.Outcomes[0].Odds != 1.90 {
+		t.Errorf("odds mid drift = %v, want 1.90", market.Outcomes[0].Odds)
+	}
+	if market.Outcomes[1].Odds != 3.20 {
+		t.Errorf("untargeted outcome odds = %v, want 3.20", market.Outcomes[1].Odds)
+	}
+	if market.IsOpen() {
+		t.Error("market should be suspended")
+	}
+
+	server.Reset()
+	get(t, server, "/sportsbook/events?st=1&type=0&version=0", &response)
+	if !response.Data.Events[0].Markets[0].IsOpen() {
+		t.Error("market should be open again after reset")
+	}
+}
+
+func TestServer_SingleEventFromBulletin(t *testing.T) {
+	store := NewStore(fixtureFor(t, "https://sportsbookv2.iddaa.com/sportsbook/events?st=1&type=0&version=0", bulletin()))
+	server := NewServer(store, Options{})
+
+	var response models.IddaaSingleEventResponse
+	if code := get(t, server, "/sportsbook/event/42", &response); code != http.StatusOK {
+		t.Fatalf("status = %d", code)
+	}
+	if response.Data.ID != 42 || len(response.Data.Markets) != 1 || len(response.Data.Markets[0].Outcomes) != 3 {
+		t.Errorf("single event = %+v", response.Data)
+	}
+
+	if code := get(t, server, "/sportsbook/event/7", nil); code != http.StatusNotFound {
+		t.Errorf("unknown event status = %d, want 404", code)
+	}
+}
+
+func TestServer_StatisticsShiftedDateAndGoal(t *testing.T) {
+	stats := models.IddaaAPIResponse[models.IddaaEventStatistics]{
+		IsSuccess: true,
+		Data:      []models.IddaaEventStatistics{{EventID: 42, HomeScore: 1, MinuteOfMatch: 60, MatchDate: "2025-06-05T20:00:00"}},
+	}
+	store := NewStore(fixtureFor(t, "https://statisticsv2.iddaa.com/broadage/getEventListCache?SportId=1&SearchDate=2025-06-05", stats))
+
+	scenario := &Scenario{Steps: []Step{{Action: ActionGoal, EventID: 42, Team: "away", Minute: 75}}}
+	server := NewServer(store, Options{Shift: 48 * time.Hour, Scenario: scenario})
+
+	var response models.IddaaAPIResponse[models.IddaaEventStatistics]
+	if code := get(t, server, "/broadage/getEventListCache?SportId=1&SearchDate=2025-06-07", &response); code != http.StatusOK {
+		t.Fatalf("status = %d", code)
+	}
+	got := response.Data[0]
+	if got.MatchDate != "2025-06-07T20:00:00" {
+		t.Errorf("match date = %s, want 2025-06-07T20:00:00", got.MatchDate)
+	}
+	if got.HomeScore != 1 || got.AwayScore != 1 || got.MinuteOfMatch != 75 || !got.IsLive {
+		t.Errorf("stats after goal = %+v", got)
+	}
+}
+
+func TestServer_RecordedAndMissing(t *testing.T) {
+	info := models.IddaaAPIResponse[models.IddaaSportInfo]{IsSuccess: true, Data: []models.IddaaSportInfo{{SportID: 1, LiveCount: 3}}}
+	server := NewServer(NewStore(fixtureFor(t, "https://sportsbookv2.iddaa.com/sportsbook/info", info)), Options{})
+
+	var response models.IddaaAPIResponse[models.IddaaSportInfo]
+	if code := get(t, server, "/sportsbook/info", &response); code != http.StatusOK || response.Data[0].LiveCount != 3 {
+		t.Errorf("sport info = %d %+v", code, response)
+	}
+	if code := get(t, server, "/sportsbook/get_market_config", nil); code != http.StatusNotFound {
+		t.Errorf("missing fixture status = %d, want 404", code)
+	}
+}
+
+func TestStore_KeepsNewestRecording(t *testing.T) {
+	older := fixtureFor(t, "https://sportsbookv2.iddaa.com/sportsbook/info", map[string]int{"n": 1})
+	newer := older
+	newer.RecordedAt = recordedAt.Add(time.Hour)
+	newer.Body = json.RawMessage(`{"n":2}`)
+
+	store := NewStore(newer, older)
+	got, ok := store.Get("/sportsbook/info", nil)
+	if !ok || string(got.Body) != `{"n":2}` {
+		t.Errorf("Get = %s, want the newer recording", got.Body)
+	}
+	if store.Len() != 1 {
+		t.Errorf("Len = %d, want 1", store.Len())
+	}
+}
+
+func TestWriteFixtureAndLoadStore(t *testing.T) {
+	dir := t.TempDir()
+	fixture := fixtureFor(t, "https://contentv2.iddaa.com/appconfig?platform=WEB", map[string]bool{"isSuccess": true})
+	if err := WriteFixture(dir, fixture); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := LoadStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := store.Get("/appconfig", url.Values{"platform": {"WEB"}})
+	if !ok || got.Host != "contentv2.iddaa.com" {
+		t.Errorf("loaded fixture = %+v, found %v", got, ok)
+	}
+}
+
+func TestWriteFixture_KeepsRequestsWithSimilarNamesApart(t *testing.T) {
+	dir := t.TempDir()
+	urls := []string{
+		"https://sportsbookv2.iddaa.com/sportsbook/info",
+		"https://sportsbookv2.iddaa.com/sportsbook_info",
+		"https://sportsbookv2.iddaa.com/sportsbook/events?st=1&type=0",
+		"https://sportsbookv2.iddaa.com/sportsbook/events?st=1_type=0",
+	}
+	for i, rawURL := range urls {
+		if err := WriteFixture(dir, fixtureFor(t, rawURL, map[string]int{"n": i})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, err := LoadStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.Len() != len(urls) {
+		t.Errorf("loaded %d fixtures, want %d", store.Len(), len(urls))
+	}
+}
+
+func TestShiftToDay(t *testing.T) {
+	got := ShiftToDay(time.Date(2025, 6, 5, 23, 30, 0, 0, time.UTC), time.Date(2025, 6, 8, 1, 0, 0, 0, time.UTC))
+	if got != 72*time.Hour {
+		t.Errorf("ShiftToDay = %v, want 72h", got)
+	}
+}
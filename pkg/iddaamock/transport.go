@@ -0,0 +1,92 @@
+package iddaamock
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/logger"
+)
+
+// iddaaHostSuffix matches sportsbookv2, contentv2 and statisticsv2
+const iddaaHostSuffix = ".iddaa.com"
+
+// RecordingTransport saves every upstream response as a fixture file that cmd/iddaa-mock can replay.
+// Wrap it around the client's other transports so fixtures keep the URLs the client requested.
+type RecordingTransport struct {
+	dir    string
+	next   http.RoundTripper
+	logger *logger.Logger
+}
+
+// NewRecordingTransport records responses received through next into dir
+func NewRecordingTransport(dir string, next http.RoundTripper) *RecordingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RecordingTransport{
+		dir:    dir,
+		next:   next,
+		logger: logger.New("iddaa-recorder"),
+	}
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The request URL may be rewritten further down the chain, so keep the original
+	original := *req.URL
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response for recording: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// A failed recording must not fail the request
+	fixture := NewFixture(&original, resp.StatusCode, resp.Header.Get("Content-Type"), body, time.Now())
+	if err := WriteFixture(t.dir, fixture); err != nil {
+		t.logger.Warn().Err(err).Str("url", original.String()).Msg("Failed to record response")
+	}
+
+	return resp, nil
+}
+
+// MockTransport sends requests for Iddaa hosts to a mock server, leaving the path and query untouched
+type MockTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+// NewMockTransport redirects Iddaa requests to the server at mockURL
+func NewMockTransport(mockURL string, next http.RoundTripper) (*MockTransport, error) {
+	target, err := url.Parse(mockURL)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("invalid mock URL %q", mockURL)
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &MockTransport{target: target, next: next}, nil
+}
+
+func (t *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.HasSuffix(req.URL.Hostname(), iddaaHostSuffix) {
+		return t.next.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the caller's request
+	redirected := req.Clone(req.Context())
+	redirected.URL.Scheme = t.target.Scheme
+	redirected.URL.Host = t.target.Host
+	redirected.Host = t.target.Host
+	return t.next.RoundTrip(redirected)
+}
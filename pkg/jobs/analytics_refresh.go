@@ -2,6 +2,7 @@ package jobs
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/iddaa-lens/core/pkg/database/generated"
@@ -83,10 +84,14 @@ func (j *AnalyticsRefreshJob) Execute(ctx context.Context) error {
 		Msg("Analytics refresh completed")
 
 	duration := time.Since(start)
+	RecordItemsProcessed(ctx, refreshedViews)
 	log.LogJobComplete("analytics_refresh", duration, refreshedViews, errorCount)
 
 	// Return nil even if some views failed - we logged the errors above
-	// This allows the job to continue running on schedule
+	// This allows the job to continue running on schedule. A run where every view failed is a failed run
+	if refreshedViews == 0 && errorCount > 0 {
+		return fmt.Errorf("analytics refresh failed for all %d views", errorCount)
+	}
 	return nil
 }
 
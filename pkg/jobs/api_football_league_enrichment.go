@@ -73,6 +73,7 @@ func (j *APIFootballLeagueEnrichmentJob) Execute(ctx context.Context) error {
 			Msg("No leagues need enrichment at this time")
 
 		duration := time.Since(start)
+		RecordItemsProcessed(ctx, 0)
 		log.LogJobComplete("api_football_league_enrichment", duration, 0, 0)
 		return nil
 	}
@@ -148,6 +149,7 @@ func (j *APIFootballLeagueEnrichmentJob) Execute(ctx context.Context) error {
 	}
 
 	duration := time.Since(start)
+	RecordItemsProcessed(ctx, successCount)
 	log.LogJobComplete("api_football_league_enrichment", duration, successCount, errorCount)
 
 	if errorCount > 0 {
@@ -163,6 +165,10 @@ func (j *APIFootballLeagueEnrichmentJob) Execute(ctx context.Context) error {
 			Msg("League enrichment completed successfully")
 	}
 
+	// Partial failures are only logged, a run where every league failed is a failed run
+	if errorCount > 0 && errorCount == len(leaguesToEnrich) {
+		return fmt.Errorf("league enrichment failed for all %d leagues", errorCount)
+	}
 	return nil
 }
 
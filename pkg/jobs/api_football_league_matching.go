@@ -77,6 +77,7 @@ func (j *APIFootballLeagueMatchingJob) Execute(ctx context.Context) error {
 			Msg("No unmapped football leagues found")
 
 		duration := time.Since(start)
+		RecordItemsProcessed(ctx, 0)
 		log.LogJobComplete("api_football_league_matching", duration, 0, 0)
 		return nil
 	}
@@ -99,6 +100,7 @@ func (j *APIFootballLeagueMatchingJob) Execute(ctx context.Context) error {
 
 			// For rate limit errors, exit gracefully without failing the job
 			duration := time.Since(start)
+			RecordItemsProcessed(ctx, 0)
 			log.LogJobComplete("api_football_league_matching", duration, 0, 0)
 			return nil
 		}
@@ -195,6 +197,7 @@ func (j *APIFootballLeagueMatchingJob) Execute(ctx context.Context) error {
 	}
 
 	duration := time.Since(start)
+	RecordItemsProcessed(ctx, successCount)
 	log.LogJobComplete("api_football_league_matching", duration, successCount, errorCount)
 
 	if errorCount > 0 {
@@ -210,6 +213,10 @@ func (j *APIFootballLeagueMatchingJob) Execute(ctx context.Context) error {
 			Msg("League matching completed successfully")
 	}
 
+	// Partial failures are only logged, a run where every league failed is a failed run
+	if errorCount > 0 && errorCount == len(unmappedLeagues) {
+		return fmt.Errorf("league matching failed for all %d leagues", errorCount)
+	}
 	return nil
 }
 
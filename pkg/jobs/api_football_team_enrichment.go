@@ -71,6 +71,7 @@ func (j *APIFootballTeamEnrichmentJob) Execute(ctx context.Context) error {
 			Msg("No teams need enrichment")
 
 		duration := time.Since(start)
+		RecordItemsProcessed(ctx, 0)
 		log.LogJobComplete("api_football_team_enrichment", duration, 0, 0)
 		return nil
 	}
@@ -145,6 +146,7 @@ func (j *APIFootballTeamEnrichmentJob) Execute(ctx context.Context) error {
 	}
 
 	duration := time.Since(start)
+	RecordItemsProcessed(ctx, successCount)
 	log.LogJobComplete("api_football_team_enrichment", duration, successCount, errorCount)
 
 	if errorCount > 0 {
@@ -160,6 +162,10 @@ func (j *APIFootballTeamEnrichmentJob) Execute(ctx context.Context) error {
 			Msg("Team enrichment completed successfully")
 	}
 
+	// Partial failures are only logged, a run where every team failed is a failed run
+	if errorCount > 0 && errorCount == len(teamsToEnrich) {
+		return fmt.Errorf("team enrichment failed for all %d teams", errorCount)
+	}
 	return nil
 }
 
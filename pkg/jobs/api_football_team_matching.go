@@ -77,6 +77,7 @@ func (j *APIFootballTeamMatchingJob) Execute(ctx context.Context) error {
 			Msg("No mapped leagues found, skipping team matching")
 
 		duration := time.Since(start)
+		RecordItemsProcessed(ctx, 0)
 		log.LogJobComplete("api_football_team_matching", duration, 0, 0)
 		return nil
 	}
@@ -89,6 +90,7 @@ func (j *APIFootballTeamMatchingJob) Execute(ctx context.Context) error {
 	// Step 2: Process each mapped league
 	totalSuccessCount := 0
 	totalErrorCount := 0
+	failedLeagues := 0
 
 	for i, mapping := range mappedLeagues {
 		// Rate limiting between league requests
@@ -113,6 +115,7 @@ func (j *APIFootballTeamMatchingJob) Execute(ctx context.Context) error {
 				Dur("duration", time.Since(leagueStart)).
 				Msg("Failed to process teams for league")
 			totalErrorCount++
+			failedLeagues++
 			continue
 		}
 
@@ -130,6 +133,7 @@ func (j *APIFootballTeamMatchingJob) Execute(ctx context.Context) error {
 	}
 
 	duration := time.Since(start)
+	RecordItemsProcessed(ctx, totalSuccessCount)
 	log.LogJobComplete("api_football_team_matching", duration, totalSuccessCount, totalErrorCount)
 
 	if totalErrorCount > 0 {
@@ -145,6 +149,10 @@ func (j *APIFootballTeamMatchingJob) Execute(ctx context.Context) error {
 			Msg("Team matching completed successfully")
 	}
 
+	// Partial failures are only logged, a run where every league failed is a failed run
+	if failedLeagues > 0 && failedLeagues == len(mappedLeagues) {
+		return fmt.Errorf("team matching failed for all %d leagues", failedLeagues)
+	}
 	return nil
 }
 
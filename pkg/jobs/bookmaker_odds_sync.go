@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/apifootball"
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+const (
+	// bookmakerOddsHorizon limits provider requests to events kicking off soon
+	bookmakerOddsHorizon = 48 * time.Hour
+	// bookmakerOddsEventLimit keeps a run within API-Football's per-minute budget
+	bookmakerOddsEventLimit = 100
+)
+
+// BookmakerOddsSyncJob stores prices from other bookmakers for upcoming mapped events
+type BookmakerOddsSyncJob struct {
+	apiclient *apifootball.Client
+	service   *services.BookmakerOddsService
+}
+
+// NewBookmakerOddsSyncJob creates a new bookmaker odds sync job backed by API-Football
+func NewBookmakerOddsSyncJob(db *generated.Queries) *BookmakerOddsSyncJob {
+	apiKey := os.Getenv("API_FOOTBALL_API_KEY")
+
+	// Create API-Football client
+	apiConfig := apifootball.DefaultConfig(apiKey)
+	apiclient := apifootball.NewClient(apiConfig)
+
+	return &BookmakerOddsSyncJob{
+		apiclient: apiclient,
+		service:   services.NewBookmakerOddsService(db, services.NewAPIFootballOddsProvider(apiclient)),
+	}
+}
+
+// Name returns the job name
+func (j *BookmakerOddsSyncJob) Name() string {
+	return "bookmaker_odds_sync"
+}
+
+// Schedule returns the cron schedule - every 30 minutes
+func (j *BookmakerOddsSyncJob) Schedule() string {
+	return "*/30 * * * *"
+}
+
+// Execute fetches provider prices and stores those matching an Iddaa outcome
+func (j *BookmakerOddsSyncJob) Execute(ctx context.Context) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 25*time.Minute) // 25 minutes to avoid overlap
+	defer cancel()
+	ctx = timeoutCtx
+
+	log := logger.WithContext(ctx, "bookmaker-odds-sync")
+	start := time.Now()
+
+	// Check if API client is available
+	if !j.apiclient.IsAvailable() {
+		log.Warn().
+			Str("action", "api_key_missing").
+			Msg("API_FOOTBALL_API_KEY not set, skipping bookmaker odds sync")
+		return nil
+	}
+
+	stats, err := j.service.SyncUpcoming(ctx, bookmakerOddsHorizon, bookmakerOddsEventLimit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to sync bookmaker odds")
+		return err
+	}
+
+	RecordItemsProcessed(ctx, stats.Quotes)
+	log.Info().
+		Str("action", "bookmaker_odds_complete").
+		Int("events", stats.Events).
+		Int("quotes", stats.Quotes).
+		Int("matched", stats.Matched).
+		Int64("changed", stats.Changed).
+		Int("failures", stats.Failures).
+		Dur("duration", time.Since(start)).
+		Msg("Bookmaker odds sync completed")
+
+	return nil
+}
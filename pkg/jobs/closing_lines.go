@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// ClosingLinesJob freezes closing lines and computes CLV for finished events
+// Events sync handles fresh transitions, this job backfills anything it missed
+type ClosingLinesJob struct {
+	closingLineService *services.ClosingLineService
+}
+
+// NewClosingLinesJob creates a new closing lines job
+func NewClosingLinesJob(closingLineService *services.ClosingLineService) *ClosingLinesJob {
+	return &ClosingLinesJob{
+		closingLineService: closingLineService,
+	}
+}
+
+// Name returns the job name for CLI execution
+func (j *ClosingLinesJob) Name() string {
+	return "closing_lines"
+}
+
+// Schedule returns the cron schedule - every 10 minutes
+func (j *ClosingLinesJob) Schedule() string {
+	return "*/10 * * * *"
+}
+
+// Execute freezes closing lines for finished events without one
+func (j *ClosingLinesJob) Execute(ctx context.Context) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Second) // 50 seconds to avoid overlap
+	defer cancel()
+	ctx = timeoutCtx
+
+	log := logger.WithContext(ctx, "closing-lines")
+	start := time.Now()
+
+	stats, err := j.closingLineService.ProcessPending(ctx, 200)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to process closing lines")
+		return err
+	}
+
+	RecordItemsProcessed(ctx, stats.Events)
+	log.Info().
+		Str("action", "closing_lines_complete").
+		Int("events", stats.Events).
+		Int64("closing_odds", stats.FrozenOdds).
+		Int64("clv_snapshots", stats.CLVSnapshots).
+		Dur("duration", time.Since(start)).
+		Msg("Closing lines job completed")
+
+	return nil
+}
@@ -43,6 +43,8 @@ func (j *ConfigSyncJob) Execute(ctx context.Context) error {
 		return err
 	}
 
+	RecordItemsProcessed(ctx, 1)
+
 	log.LogJobComplete("config_sync", duration, 1, 0)
 	return nil
 }
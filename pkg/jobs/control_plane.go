@@ -0,0 +1,274 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+)
+
+// maxClaimedTriggers bounds the manual triggers one instance claims per poll
+const maxClaimedTriggers = 10
+
+// JobControlPlane applies the job controls set through the admin API to a job manager and runs
+// the manual triggers queued for its jobs. Every cron instance runs one: the pause state and
+// schedule in job_controls apply to all of them, each trigger is claimed by a single instance.
+// Manual runs go through the manager like scheduled ones, so they take the same distributed
+// lock in production mode and are recorded in job_runs.
+type JobControlPlane struct {
+	db            *generated.Queries
+	manager       JobManager
+	instanceID    string
+	interval      time.Duration
+	retentionDays int
+	logger        *logger.Logger
+
+	registered  bool
+	lastCleanup time.Time
+	// Schedules that failed to apply, logged once per value
+	invalidSchedules map[string]string
+	// Manual runs in progress
+	runs sync.WaitGroup
+}
+
+// NewJobControlPlane creates a control plane polling every interval. Finished triggers older than
+// retentionDays are deleted, 0 keeps them all.
+func NewJobControlPlane(db *generated.Queries, manager JobManager, instanceID string, interval time.Duration, retentionDays int) *JobControlPlane {
+	return &JobControlPlane{
+		db:               db,
+		manager:          manager,
+		instanceID:       instanceID,
+		interval:         interval,
+		retentionDays:    retentionDays,
+		logger:           logger.New("job-control-plane"),
+		invalidSchedules: make(map[string]string),
+	}
+}
+
+// Run polls the controls and triggers until ctx is done, then waits for the manual runs it started
+func (c *JobControlPlane) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.logger.Info().
+		Str("action", "control_plane_start").
+		Str("instance_id", c.instanceID).
+		Dur("interval", c.interval).
+		Msg("Starting job control plane")
+
+	for {
+		c.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			c.runs.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll registers the jobs until that succeeds, then applies the controls and claims triggers
+func (c *JobControlPlane) poll(ctx context.Context) {
+	pollCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if !c.registered {
+		if err := c.register(pollCtx); err != nil {
+			c.logger.Error().Err(err).Str("action", "control_register_failed").Msg("Failed to register jobs for runtime control")
+			return
+		}
+		c.registered = true
+	}
+
+	states := c.manager.JobStates()
+	if err := c.applyControls(pollCtx, states); err != nil {
+		c.logger.Error().Err(err).Str("action", "control_apply_failed").Msg("Failed to apply job controls")
+	}
+	if err := c.claimTriggers(pollCtx, states); err != nil {
+		c.logger.Error().Err(err).Str("action", "trigger_claim_failed").Msg("Failed to claim job triggers")
+	}
+	c.cleanup(pollCtx)
+}
+
+// register records the jobs of this instance with their default schedules
+func (c *JobControlPlane) register(ctx context.Context) error {
+	var params generated.RegisterJobControlsParams
+	for _, state := range c.manager.JobStates() {
+		params.JobNames = append(params.JobNames, state.Name)
+		params.DefaultSchedules = append(params.DefaultSchedules, state.DefaultSchedule)
+	}
+	if err := c.db.RegisterJobControls(ctx, params); err != nil {
+		return err
+	}
+
+	c.logger.Info().
+		Str("action", "jobs_registered").
+		Int("job_count", len(params.JobNames)).
+		Msg("Registered jobs for runtime control")
+	return nil
+}
+
+// applyControls pauses, resumes and reschedules jobs to match job_controls
+func (c *JobControlPlane) applyControls(ctx context.Context, states []JobState) error {
+	controls, err := c.db.ListJobControls(ctx)
+	if err != nil {
+		return err
+	}
+
+	byName := make(map[string]JobState, len(states))
+	for _, state := range states {
+		byName[state.Name] = state
+	}
+
+	for _, control := range controls {
+		state, ok := byName[control.JobName]
+		if !ok {
+			continue
+		}
+
+		schedule := state.DefaultSchedule
+		if control.ScheduleOverride != nil {
+			schedule = *control.ScheduleOverride
+		}
+		if schedule != state.Schedule && c.invalidSchedules[control.JobName] != schedule {
+			if err := c.manager.RescheduleJob(control.JobName, schedule); err != nil {
+				c.invalidSchedules[control.JobName] = schedule
+				c.logger.Error().
+					Err(err).
+					Str("action", "job_reschedule_failed").
+					Str("job_name", control.JobName).
+					Str("schedule", schedule).
+					Msg("Failed to apply job schedule, keeping the current one")
+			} else {
+				delete(c.invalidSchedules, control.JobName)
+				c.logger.Info().
+					Str("action", "job_rescheduled").
+					Str("job_name", control.JobName).
+					Str("previous_schedule", state.Schedule).
+					Str("schedule", schedule).
+					Msg("Job rescheduled")
+			}
+		}
+
+		if control.Paused == state.Paused {
+			continue
+		}
+		if control.Paused {
+			err = c.manager.PauseJob(control.JobName)
+		} else {
+			err = c.manager.ResumeJob(control.JobName)
+		}
+		if err != nil {
+			c.logger.Error().
+				Err(err).
+				Str("action", "job_pause_failed").
+				Str("job_name", control.JobName).
+				Bool("paused", control.Paused).
+				Msg("Failed to change job pause state")
+			continue
+		}
+		c.logger.Info().
+			Str("action", "job_pause_changed").
+			Str("job_name", control.JobName).
+			Bool("paused", control.Paused).
+			Msg("Job pause state changed")
+	}
+
+	return nil
+}
+
+// claimTriggers starts the pending manual runs of jobs not running in this instance, a trigger
+// for a running job waits for the run to finish
+func (c *JobControlPlane) claimTriggers(ctx context.Context, states []JobState) error {
+	var idle []string
+	for _, state := range states {
+		if !state.Running {
+			idle = append(idle, state.Name)
+		}
+	}
+	if len(idle) == 0 {
+		return nil
+	}
+
+	triggers, err := c.db.ClaimJobTriggers(ctx, generated.ClaimJobTriggersParams{
+		InstanceID: c.instanceID,
+		JobNames:   idle,
+		LimitCount: maxClaimedTriggers,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, trigger := range triggers {
+		c.runs.Add(1)
+		go c.runTrigger(trigger)
+	}
+	return nil
+}
+
+// runTrigger runs a claimed trigger and stores its outcome
+func (c *JobControlPlane) runTrigger(trigger generated.JobTrigger) {
+	defer c.runs.Done()
+
+	c.logger.Info().
+		Str("action", "job_triggered").
+		Str("job_name", trigger.JobName).
+		Int64("trigger_id", trigger.ID).
+		Msg("Running manually triggered job")
+
+	result, err := c.manager.RunJob(trigger.JobName, TriggerManual)
+
+	params := generated.FinishJobTriggerParams{
+		ID:     trigger.ID,
+		Status: result.Status,
+	}
+	if result.RunID != 0 {
+		params.RunID = &result.RunID
+	}
+	if err != nil {
+		message := err.Error()
+		params.Error = &message
+		params.Status = RunFailed
+		if errors.Is(err, ErrJobRunning) {
+			params.Status = RunSkipped
+		}
+	}
+
+	// The run may have outlived the poll context, the update uses its own
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := c.db.FinishJobTrigger(ctx, params); err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("action", "trigger_finish_failed").
+			Int64("trigger_id", trigger.ID).
+			Msg("Failed to record job trigger result")
+	}
+}
+
+// cleanup expires stale triggers on every poll and applies the retention once an hour
+func (c *JobControlPlane) cleanup(ctx context.Context) {
+	expired, err := c.db.ExpireJobTriggers(ctx)
+	if err != nil {
+		c.logger.Error().Err(err).Str("action", "trigger_expire_failed").Msg("Failed to expire job triggers")
+	} else if expired > 0 {
+		c.logger.Warn().
+			Str("action", "triggers_expired").
+			Int64("expired", expired).
+			Msg("Expired unclaimed or abandoned job triggers")
+	}
+
+	if c.retentionDays <= 0 || time.Since(c.lastCleanup) < jobRunCleanupInterval {
+		return
+	}
+	c.lastCleanup = time.Now()
+
+	if _, err := c.db.DeleteOldJobTriggers(ctx, int32(c.retentionDays)); err != nil {
+		c.logger.Error().Err(err).Str("action", "trigger_retention_failed").Msg("Failed to delete old job triggers")
+	}
+}
@@ -18,7 +18,9 @@ type DetailedOddsSyncJob struct {
 	queries *generated.Queries
 	client  services.IddaaClientInterface
 	events  services.EventsServiceInterface
-	logger  *logger.Logger
+	// Optional, loaded per batch so workers find their events' odds cached
+	oddsCache *services.OddsCache
+	logger    *logger.Logger
 }
 
 // eventResult holds the result of processing a single event
@@ -30,13 +32,14 @@ type eventResult struct {
 	duration   time.Duration
 }
 
-// NewDetailedOddsSyncJob creates a new detailed odds sync job
-func NewDetailedOddsSyncJob(queries *generated.Queries, client services.IddaaClientInterface, events services.EventsServiceInterface) *DetailedOddsSyncJob {
+// NewDetailedOddsSyncJob creates a new detailed odds sync job, oddsCache may be nil
+func NewDetailedOddsSyncJob(queries *generated.Queries, client services.IddaaClientInterface, events services.EventsServiceInterface, oddsCache *services.OddsCache) *DetailedOddsSyncJob {
 	return &DetailedOddsSyncJob{
-		queries: queries,
-		client:  client,
-		events:  events,
-		logger:  logger.New("detailed-odds-sync"),
+		queries:   queries,
+		client:    client,
+		events:    events,
+		oddsCache: oddsCache,
+		logger:    logger.New("detailed-odds-sync"),
 	}
 }
 
@@ -80,6 +83,7 @@ func (j *DetailedOddsSyncJob) Execute(ctx context.Context) error {
 
 	// Process events in smart batches to handle large volumes
 	totalSuccess, totalErrors := j.processBatchedEvents(ctx, activeEvents)
+	RecordItemsProcessed(ctx, totalSuccess)
 
 	duration := time.Since(start)
 	j.logger.Info().
@@ -93,6 +97,11 @@ func (j *DetailedOddsSyncJob) Execute(ctx context.Context) error {
 		Bool("has_errors", totalErrors > 0).
 		Msg("All active events processing completed")
 
+	// Partial failures are only logged, a sync where every event failed is a failed run
+	if totalSuccess == 0 && totalErrors > 0 {
+		return fmt.Errorf("detailed odds sync failed for all %d events", totalErrors)
+	}
+
 	return nil
 }
 
@@ -127,6 +136,7 @@ func (j *DetailedOddsSyncJob) processBatchedEvents(ctx context.Context, allEvent
 
 		// Process this batch
 		batchStart := time.Now()
+		j.preloadOddsCache(ctx, batch)
 		eventResults := j.parallelFetchEventData(ctx, batch)
 		successCount, errorCount := j.bulkProcessMarkets(ctx, eventResults)
 
@@ -151,6 +161,25 @@ func (j *DetailedOddsSyncJob) processBatchedEvents(ctx context.Context, allEvent
 	return totalSuccess, totalErrors
 }
 
+// preloadOddsCache loads the cached odds of a batch with one query instead of one per worker
+func (j *DetailedOddsSyncJob) preloadOddsCache(ctx context.Context, batch []generated.Event) {
+	if j.oddsCache == nil {
+		return
+	}
+
+	eventIDs := make([]int32, len(batch))
+	for i, event := range batch {
+		eventIDs[i] = event.ID
+	}
+	if err := j.oddsCache.Preload(ctx, eventIDs); err != nil {
+		j.logger.Warn().
+			Err(err).
+			Str("action", "odds_cache_preload_failed").
+			Int("event_count", len(eventIDs)).
+			Msg("Failed to preload odds cache, events load their odds one by one")
+	}
+}
+
 // // Legacy Execute method content moved to processBatchedEvents
 // func (j *DetailedOddsSyncJob) executeLegacyBatch(ctx context.Context, activeEvents []generated.Event) (int, int) {
 
@@ -196,11 +225,8 @@ func (j *DetailedOddsSyncJob) parallelFetchEventData(ctx context.Context, active
 				return
 			}
 
-			// Individual event timeout (context used in API call)
-			_ = ctx // API call will use the main context with its own timeout
-
-			// Fetch event data
-			eventResponse, err := j.client.GetSingleEvent(externalID)
+			// Fetch event data; the job context cancels requests still waiting on rate budget or retries
+			eventResponse, err := j.client.GetSingleEvent(ctx, externalID)
 			if err != nil {
 				results[index] = eventResult{
 					eventID:    int(evt.ID),
@@ -57,7 +57,13 @@ func (j *DistributionSyncJob) Execute(ctx context.Context) error {
 	totalProcessed, errorCount := j.processSportsConcurrently(ctx, sports)
 
 	duration := time.Since(start)
+	RecordItemsProcessed(ctx, totalProcessed)
 	log.LogJobComplete("distribution_sync", duration, totalProcessed, errorCount)
+
+	// Partial failures are only logged, a sync where every sport failed is a failed run
+	if totalProcessed == 0 && errorCount > 0 {
+		return fmt.Errorf("distribution sync failed for all %d sports", errorCount)
+	}
 	return nil
 }
 
@@ -10,14 +10,14 @@ import (
 )
 
 type EventsSyncJob struct {
-	iddaaClient   *services.IddaaClient
 	eventsService *services.EventsService
+	syncService   *services.EventSyncService
 }
 
 func NewEventsSyncJob(iddaaClient *services.IddaaClient, eventsService *services.EventsService) Job {
 	return &EventsSyncJob{
-		iddaaClient:   iddaaClient,
 		eventsService: eventsService,
+		syncService:   services.NewEventSyncService(eventsService, iddaaClient),
 	}
 }
 
@@ -53,6 +53,7 @@ func (j *EventsSyncJob) Execute(ctx context.Context) error {
 
 	totalEvents := 0
 	errorCount := 0
+	fullSyncs := 0
 
 	for _, sport := range sports {
 		sportStart := time.Now()
@@ -62,59 +63,56 @@ func (j *EventsSyncJob) Execute(ctx context.Context) error {
 			Int("sport_id", int(sport.ID)).
 			Msg("Fetching events for sport")
 
-		// Fetch all events from iddaa API for this sport using type=0
-		response, err := j.iddaaClient.GetEvents(int(sport.ID))
+		// Apply the diff since the last synced version, or the full bulletin when needed
+		result, err := j.syncService.SyncSport(ctx, sport.ID)
 		if err != nil {
 			errorCount++
 			log.Error().
 				Err(err).
-				Str("action", "fetch_failed").
+				Str("action", "sync_failed").
 				Str("sport_name", sport.Name).
 				Int("sport_id", int(sport.ID)).
-				Msg("Failed to fetch events")
+				Str("mode", result.Mode).
+				Msg("Failed to sync events")
 			continue // Continue with other sports
 		}
 
-		log.Debug().
-			Str("action", "api_response_received").
-			Str("sport_name", sport.Name).
-			Int("sport_id", int(sport.ID)).
-			Bool("success", response.IsSuccess).
-			Msg("Received API response, starting processing")
-
-		// Process and store the events
-		if err := j.eventsService.ProcessEventsResponse(ctx, response); err != nil {
-			errorCount++
-			log.Error().
-				Err(err).
-				Str("action", "process_failed").
-				Str("sport_name", sport.Name).
-				Int("sport_id", int(sport.ID)).
-				Msg("Failed to process events response")
-			continue // Continue with other sports
-		}
-
-		eventCount := 0
-		if response.Data != nil {
-			eventCount = len(response.Data.Events)
+		totalEvents += result.Events
+		if result.Mode == services.SyncModeFull {
+			fullSyncs++
 		}
-		totalEvents += eventCount
 		log.Info().
 			Str("action", "sport_sync_complete").
 			Str("sport_name", sport.Name).
 			Int("sport_id", int(sport.ID)).
-			Int("event_count", eventCount).
+			Str("mode", result.Mode).
+			Str("full_reason", result.FullReason).
+			Int64("from_version", result.FromVersion).
+			Int64("version", result.Version).
+			Int("event_count", result.Events).
 			Dur("duration", time.Since(sportStart)).
 			Msg("Processed events for sport")
 	}
 
+	log.Info().
+		Str("action", "sync_modes").
+		Int("full_syncs", fullSyncs).
+		Int("diff_syncs", len(sports)-fullSyncs-errorCount).
+		Msg("Events sync modes")
+
 	duration := time.Since(start)
+	RecordItemsProcessed(ctx, totalEvents)
 	log.LogJobComplete("events_sync", duration, totalEvents, errorCount)
+
+	// Partial failures are only logged, a sync where every sport failed is a failed run
+	if errorCount > 0 && errorCount == len(sports) {
+		return fmt.Errorf("events sync failed for all %d sports", errorCount)
+	}
 	return nil
 }
 
 // Schedule returns the cron schedule for this job
 func (j *EventsSyncJob) Schedule() string {
-	// Run every 5 minutes to capture rapid odds movements
-	return "*/5 * * * *"
+	// Run every minute; most runs only apply a small diff
+	return "* * * * *"
 }
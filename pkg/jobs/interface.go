@@ -29,4 +29,23 @@ type JobManager interface {
 
 	// GetJobs returns all registered jobs
 	GetJobs() []Job
+
+	// SetRunRecorder persists every run in job_runs, call it before Start
+	SetRunRecorder(recorder *JobRunRecorder)
+
+	// RunJob runs a registered job now and waits for it. It returns ErrJobNotFound for an
+	// unknown name and ErrJobRunning while a run of the job is in progress in this process.
+	RunJob(name, trigger string) (JobRunResult, error)
+
+	// PauseJob stops scheduling a job, a run in progress finishes
+	PauseJob(name string) error
+
+	// ResumeJob schedules a paused job again
+	ResumeJob(name string) error
+
+	// RescheduleJob replaces the cron schedule of a job
+	RescheduleJob(name, schedule string) error
+
+	// JobStates returns the runtime state of every registered job
+	JobStates() []JobState
 }
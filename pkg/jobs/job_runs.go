@@ -0,0 +1,251 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+)
+
+// Triggers of a job run
+const (
+	TriggerCron    = "cron"
+	TriggerStartup = "startup"
+	TriggerManual  = "manual"
+)
+
+// Statuses of a job run
+const (
+	RunRunning   = "running"
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+	// RunSkipped runs found the job lock held by another instance
+	RunSkipped = "skipped"
+	// RunAbandoned runs never finished because their process stopped
+	RunAbandoned = "abandoned"
+)
+
+// Outcomes of the distributed lock acquisition of a run
+const (
+	LockAcquired = "acquired"
+	LockBusy     = "busy"
+	LockError    = "error"
+)
+
+// staleRunAge is how long after its start a run still marked running is considered abandoned,
+// well past the 30 minute job timeout
+const staleRunAge = 2 * time.Hour
+
+// jobRunCleanupInterval spaces out the abandoned run and retention sweeps
+const jobRunCleanupInterval = time.Hour
+
+// JobRunRecorder persists every job run in job_runs. Recording never fails a job: database
+// errors are logged and the job runs unrecorded.
+type JobRunRecorder struct {
+	db            *generated.Queries
+	instanceID    string
+	retentionDays int
+	logger        *logger.Logger
+
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+// NewJobRunRecorder creates a recorder tagging runs with instanceID, DefaultInstanceID when empty.
+// Runs older than retentionDays are deleted, 0 keeps them all.
+func NewJobRunRecorder(db *generated.Queries, instanceID string, retentionDays int) *JobRunRecorder {
+	if instanceID == "" {
+		instanceID = DefaultInstanceID()
+	}
+	return &JobRunRecorder{
+		db:            db,
+		instanceID:    instanceID,
+		retentionDays: retentionDays,
+		logger:        logger.New("job-run-recorder"),
+	}
+}
+
+// DefaultInstanceID identifies this process by hostname and pid
+func DefaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// InstanceID returns the identifier stored with this process's runs
+func (r *JobRunRecorder) InstanceID() string {
+	return r.instanceID
+}
+
+// jobRun tracks a run in progress, jobs report into it through their context
+type jobRun struct {
+	id      int64
+	jobName string
+
+	mu             sync.Mutex
+	itemsProcessed *int32
+	lockOutcome    *string
+}
+
+type jobRunKey struct{}
+
+// RecordItemsProcessed adds n items to the run executing under ctx. Jobs call it with the
+// counts they log, it does nothing outside a recorded run.
+func RecordItemsProcessed(ctx context.Context, n int) {
+	run, ok := ctx.Value(jobRunKey{}).(*jobRun)
+	if !ok {
+		return
+	}
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	if run.itemsProcessed == nil {
+		run.itemsProcessed = new(int32)
+	}
+	*run.itemsProcessed += int32(n)
+}
+
+// recordLockOutcome stores the lock result of the run executing under ctx
+func recordLockOutcome(ctx context.Context, outcome string) {
+	run, ok := ctx.Value(jobRunKey{}).(*jobRun)
+	if !ok {
+		return
+	}
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	run.lockOutcome = &outcome
+}
+
+// items returns the items reported so far, 0 when none were
+func (run *jobRun) items() int {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	if run.itemsProcessed == nil {
+		return 0
+	}
+	return int(*run.itemsProcessed)
+}
+
+// start records a run of jobName and returns a context carrying it. A nil recorder still
+// tracks the run in memory so the managers can log reported items.
+func (r *JobRunRecorder) start(ctx context.Context, jobName, trigger string) (context.Context, *jobRun) {
+	run := &jobRun{jobName: jobName}
+	ctx = context.WithValue(ctx, jobRunKey{}, run)
+	if r == nil {
+		return ctx, run
+	}
+
+	id, err := r.db.CreateJobRun(ctx, generated.CreateJobRunParams{
+		JobName:    jobName,
+		InstanceID: r.instanceID,
+		Trigger:    trigger,
+	})
+	if err != nil {
+		r.logger.Error().
+			Err(err).
+			Str("action", "job_run_create_failed").
+			Str("job_name", jobName).
+			Msg("Failed to record job run start")
+		return ctx, run
+	}
+	run.id = id
+	return ctx, run
+}
+
+// Run executes job once as a recorded run, for runs outside the job manager
+func (r *JobRunRecorder) Run(ctx context.Context, job Job, trigger string) error {
+	ctx, run := r.start(ctx, job.Name(), trigger)
+	err := job.Execute(ctx)
+	r.finish(run, err)
+	return err
+}
+
+// status derives the status of a finished run from its error and lock outcome
+func (run *jobRun) status(jobErr error) string {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	switch {
+	case jobErr != nil:
+		return RunFailed
+	case run.lockOutcome != nil && *run.lockOutcome == LockBusy:
+		return RunSkipped
+	default:
+		return RunSucceeded
+	}
+}
+
+// finish stores the outcome of a run. A run that found the lock busy is skipped rather than
+// succeeded. The job context may have expired, so the update uses its own.
+func (r *JobRunRecorder) finish(run *jobRun, jobErr error) JobRunResult {
+	result := JobRunResult{RunID: run.id, Status: run.status(jobErr)}
+	if r == nil || run.id == 0 {
+		return result
+	}
+
+	run.mu.Lock()
+	params := generated.FinishJobRunParams{
+		ID:             run.id,
+		Status:         result.Status,
+		LockOutcome:    run.lockOutcome,
+		ItemsProcessed: run.itemsProcessed,
+	}
+	run.mu.Unlock()
+
+	if jobErr != nil {
+		message := jobErr.Error()
+		params.Error = &message
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := r.db.FinishJobRun(ctx, params); err != nil {
+		r.logger.Error().
+			Err(err).
+			Str("action", "job_run_finish_failed").
+			Str("job_name", run.jobName).
+			Int64("run_id", run.id).
+			Msg("Failed to record job run result")
+	}
+
+	r.cleanup(ctx)
+	return result
+}
+
+// cleanup closes abandoned runs and applies the retention, at most once an hour
+func (r *JobRunRecorder) cleanup(ctx context.Context) {
+	r.mu.Lock()
+	if time.Since(r.lastCleanup) < jobRunCleanupInterval {
+		r.mu.Unlock()
+		return
+	}
+	r.lastCleanup = time.Now()
+	r.mu.Unlock()
+
+	abandoned, err := r.db.AbandonStaleJobRuns(ctx, int32(staleRunAge/time.Minute))
+	if err != nil {
+		r.logger.Error().Err(err).Str("action", "job_run_abandon_failed").Msg("Failed to close abandoned job runs")
+	}
+
+	var deleted int64
+	if r.retentionDays > 0 {
+		deleted, err = r.db.DeleteOldJobRuns(ctx, int32(r.retentionDays))
+		if err != nil {
+			r.logger.Error().Err(err).Str("action", "job_run_retention_failed").Msg("Failed to delete old job runs")
+		}
+	}
+
+	if abandoned > 0 || deleted > 0 {
+		r.logger.Info().
+			Str("action", "job_runs_cleaned").
+			Int64("abandoned", abandoned).
+			Int64("deleted", deleted).
+			Msg("Cleaned up job runs")
+	}
+}
@@ -0,0 +1,163 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+// runsDB records the job_runs statements of a recorder
+type runsDB struct {
+	finished []generated.FinishJobRunParams
+}
+
+func (d *runsDB) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	return &runIDRow{id: 42}
+}
+
+func (d *runsDB) Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("unexpected Query")
+}
+
+func (d *runsDB) Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error) {
+	if strings.Contains(query, "name: FinishJobRun") {
+		params := generated.FinishJobRunParams{
+			Status: args[0].(string),
+			ID:     args[4].(int64),
+		}
+		params.LockOutcome, _ = args[1].(*string)
+		params.ItemsProcessed, _ = args[2].(*int32)
+		params.Error, _ = args[3].(*string)
+		d.finished = append(d.finished, params)
+	}
+	return pgconn.CommandTag{}, nil
+}
+
+type runIDRow struct {
+	id int64
+}
+
+func (r *runIDRow) Scan(dest ...interface{}) error {
+	*dest[0].(*int64) = r.id
+	return nil
+}
+
+func TestJobRunRecorderFinish(t *testing.T) {
+	tests := []struct {
+		name       string
+		lock       string
+		items      []int
+		jobErr     error
+		wantStatus string
+		wantItems  *int32
+	}{
+		{name: "succeeded", items: []int{3, 4}, wantStatus: RunSucceeded, wantItems: ptr(int32(7))},
+		{name: "succeeded without items", lock: LockAcquired, wantStatus: RunSucceeded},
+		{name: "failed", lock: LockAcquired, jobErr: errors.New("upstream down"), wantStatus: RunFailed},
+		{name: "lock busy", lock: LockBusy, wantStatus: RunSkipped},
+		{name: "lock error", lock: LockError, jobErr: errors.New("no connection"), wantStatus: RunFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &runsDB{}
+			recorder := NewJobRunRecorder(generated.New(db), "test-instance", 0)
+
+			ctx, run := recorder.start(context.Background(), "events_sync", TriggerCron)
+			if tt.lock != "" {
+				recordLockOutcome(ctx, tt.lock)
+			}
+			for _, n := range tt.items {
+				RecordItemsProcessed(ctx, n)
+			}
+			recorder.finish(run, tt.jobErr)
+
+			if len(db.finished) != 1 {
+				t.Fatalf("got %d finished runs, want 1", len(db.finished))
+			}
+			got := db.finished[0]
+			if got.ID != 42 || got.Status != tt.wantStatus {
+				t.Errorf("run %d status %q, want 42 %q", got.ID, got.Status, tt.wantStatus)
+			}
+			if (got.ItemsProcessed == nil) != (tt.wantItems == nil) ||
+				(got.ItemsProcessed != nil && *got.ItemsProcessed != *tt.wantItems) {
+				t.Errorf("items = %v, want %v", got.ItemsProcessed, tt.wantItems)
+			}
+			if tt.lock == "" && got.LockOutcome != nil {
+				t.Errorf("lock outcome = %q, want none", *got.LockOutcome)
+			}
+			if tt.lock != "" && (got.LockOutcome == nil || *got.LockOutcome != tt.lock) {
+				t.Errorf("lock outcome = %v, want %q", got.LockOutcome, tt.lock)
+			}
+			if (got.Error != nil) != (tt.jobErr != nil) {
+				t.Errorf("error = %v, want %v", got.Error, tt.jobErr)
+			}
+		})
+	}
+}
+
+func TestJobRunRecorderNil(t *testing.T) {
+	var recorder *JobRunRecorder
+
+	ctx, run := recorder.start(context.Background(), "events_sync", TriggerStartup)
+	RecordItemsProcessed(ctx, 5)
+	recorder.finish(run, nil)
+
+	if run.items() != 5 {
+		t.Errorf("items = %d, want 5 tracked without a recorder", run.items())
+	}
+
+	// Reporting outside a run is a no-op
+	RecordItemsProcessed(context.Background(), 1)
+}
+
+func TestProductionJobRecordsLockOutcome(t *testing.T) {
+	locks := NewMockDB()
+	lockManager := NewPostgreSQLLockManager(locks)
+	config := &ProductionJobConfig{SkipIfLocked: true}
+
+	job := &mockJob{name: "detailed_odds", schedule: "* * * * *"}
+	runsDB := &runsDB{}
+	recorder := NewJobRunRecorder(generated.New(runsDB), "test-instance", 0)
+
+	// Another instance holds the lock
+	if acquired, err := lockManager.AcquireLock(context.Background(), job.Name()); err != nil || !acquired {
+		t.Fatalf("failed to take the lock: %v", err)
+	}
+
+	ctx, run := recorder.start(context.Background(), job.Name(), TriggerCron)
+	err := NewProductionJob(job, lockManager, config).Execute(ctx)
+	recorder.finish(run, err)
+
+	if err != nil || job.executed {
+		t.Fatalf("job should be skipped, err = %v, executed = %v", err, job.executed)
+	}
+	if got := runsDB.finished[0]; got.Status != RunSkipped || *got.LockOutcome != LockBusy {
+		t.Errorf("status %q lock %q, want skipped busy", got.Status, *got.LockOutcome)
+	}
+
+	if err := lockManager.ReleaseLock(context.Background(), job.Name()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, run = recorder.start(context.Background(), job.Name(), TriggerManual)
+	err = NewProductionJob(job, lockManager, config).Execute(ctx)
+	recorder.finish(run, err)
+
+	if err != nil || !job.executed {
+		t.Fatalf("job should run, err = %v, executed = %v", err, job.executed)
+	}
+	if got := runsDB.finished[1]; got.Status != RunSucceeded || *got.LockOutcome != LockAcquired {
+		t.Errorf("status %q lock %q, want succeeded acquired", got.Status, *got.LockOutcome)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
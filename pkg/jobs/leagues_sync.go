@@ -84,6 +84,7 @@ func (j *LeaguesSyncJob) Execute(ctx context.Context) error {
 	// The api_football_team_matching job runs weekly on Tuesdays at 4 AM
 
 	duration := time.Since(start)
+	RecordItemsProcessed(ctx, completedSteps)
 	log.LogJobComplete("leagues_sync", duration, completedSteps, errorCount)
 
 	return nil
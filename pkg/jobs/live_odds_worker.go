@@ -0,0 +1,315 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// liveOddsLockName is the advisory lock that keeps a single live odds worker active across instances
+const liveOddsLockName = "live_odds_worker"
+
+// LiveOddsWorkerConfig holds the polling and backpressure settings of the live odds worker
+type LiveOddsWorkerConfig struct {
+	// Pause between cycles when keeping up
+	Interval time.Duration
+	// Upper bound for the pause after failures or slow cycles, also the timeout of a single cycle
+	MaxInterval time.Duration
+	// Sports polled in parallel within a cycle
+	MaxConcurrency int
+	// How often the list of sports with live events is refreshed
+	SportsRefresh time.Duration
+	// How often live scores are refreshed through the statistics feed, 0 disables it
+	ScoreInterval time.Duration
+	// Sports whose scores are refreshed, the statistics feed is football only today
+	ScoreSports []int
+}
+
+// DefaultLiveOddsWorkerConfig returns the default live odds worker settings
+func DefaultLiveOddsWorkerConfig() *LiveOddsWorkerConfig {
+	return &LiveOddsWorkerConfig{
+		Interval:       5 * time.Second,
+		MaxInterval:    time.Minute,
+		MaxConcurrency: 2,
+		SportsRefresh:  time.Minute,
+		ScoreInterval:  30 * time.Second,
+		ScoreSports:    []int{1},
+	}
+}
+
+// LiveOddsWorker polls GetLiveEvents in its own loop rather than on a cron tick.
+// Cycles never overlap; the pause between them grows when a cycle fails or takes longer than
+// the interval, and returns to the interval once the worker keeps up again.
+type LiveOddsWorker struct {
+	client     *services.IddaaClient
+	events     *services.EventsService
+	statistics *services.StatisticsService
+	locks      JobLockManager
+	config     *LiveOddsWorkerConfig
+	logger     *logger.Logger
+
+	liveSports       []int
+	sportsRefreshed  time.Time
+	scoresRefreshed  time.Time
+	consecutiveFails int
+}
+
+// NewLiveOddsWorker creates a live odds worker. locks may be nil when only one instance runs;
+// with a lock manager the worker only polls while it holds the live odds lock.
+func NewLiveOddsWorker(client *services.IddaaClient, events *services.EventsService, statistics *services.StatisticsService, locks JobLockManager, config *LiveOddsWorkerConfig) *LiveOddsWorker {
+	if config == nil {
+		config = DefaultLiveOddsWorkerConfig()
+	}
+	if config.MaxConcurrency < 1 {
+		config.MaxConcurrency = 1
+	}
+	if config.MaxInterval < config.Interval {
+		config.MaxInterval = config.Interval
+	}
+	return &LiveOddsWorker{
+		client:     client,
+		events:     events,
+		statistics: statistics,
+		locks:      locks,
+		config:     config,
+		logger:     logger.New("live-odds-worker"),
+	}
+}
+
+// Run polls until ctx is cancelled
+func (w *LiveOddsWorker) Run(ctx context.Context) error {
+	if w.locks != nil {
+		if err := w.waitForLock(ctx); err != nil {
+			return err
+		}
+		defer func() {
+			// The run context is already cancelled here
+			releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := w.locks.ReleaseLock(releaseCtx, liveOddsLockName); err != nil {
+				w.logger.Error().Err(err).Msg("Failed to release live odds lock")
+			}
+		}()
+	}
+
+	w.logger.Info().
+		Str("action", "worker_start").
+		Dur("interval", w.config.Interval).
+		Dur("max_interval", w.config.MaxInterval).
+		Int("max_concurrency", w.config.MaxConcurrency).
+		Msg("Live odds worker started")
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	delay := w.config.Interval
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info().Str("action", "worker_stop").Msg("Live odds worker stopped")
+			return nil
+		case <-timer.C:
+		}
+
+		start := time.Now()
+		err := w.cycle(ctx)
+		elapsed := time.Since(start)
+
+		if err != nil && ctx.Err() == nil {
+			w.consecutiveFails++
+			w.logger.Error().
+				Err(err).
+				Str("action", "cycle_failed").
+				Int("consecutive_failures", w.consecutiveFails).
+				Dur("duration", elapsed).
+				Msg("Live odds cycle failed")
+		} else {
+			w.consecutiveFails = 0
+		}
+
+		next := nextLiveDelay(w.config, delay, elapsed, err != nil)
+		if next != delay && next > w.config.Interval {
+			w.logger.Warn().
+				Str("action", "backpressure").
+				Dur("delay", next).
+				Dur("cycle_duration", elapsed).
+				Bool("failed", err != nil).
+				Msg("Slowing down live odds polling")
+		}
+		delay = next
+		timer.Reset(delay)
+	}
+}
+
+// nextLiveDelay picks the pause before the next cycle: doubling after a failure, matching the
+// cycle duration when cycles run longer than the interval, and the interval otherwise
+func nextLiveDelay(config *LiveOddsWorkerConfig, previous, elapsed time.Duration, failed bool) time.Duration {
+	next := config.Interval
+	switch {
+	case failed:
+		next = previous * 2
+		if next < config.Interval {
+			next = config.Interval
+		}
+	case elapsed > config.Interval:
+		next = elapsed
+	}
+	if next > config.MaxInterval {
+		next = config.MaxInterval
+	}
+	return next
+}
+
+// waitForLock blocks until this instance holds the live odds lock
+func (w *LiveOddsWorker) waitForLock(ctx context.Context) error {
+	for {
+		acquired, err := w.locks.AcquireLock(ctx, liveOddsLockName)
+		if err != nil {
+			w.logger.Error().Err(err).Msg("Failed to acquire live odds lock")
+		}
+		if acquired {
+			return nil
+		}
+
+		w.logger.Debug().
+			Str("action", "lock_wait").
+			Msg("Another instance runs the live odds worker, waiting")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(30 * time.Second):
+		}
+	}
+}
+
+// cycle refreshes scores and polls every sport with live events once
+func (w *LiveOddsWorker) cycle(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, w.config.MaxInterval)
+	defer cancel()
+
+	sports := w.sportsWithLiveEvents(ctx)
+	if len(sports) == 0 {
+		return nil
+	}
+
+	w.refreshScores(ctx, sports)
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		total  services.LiveOddsStats
+		errs   []error
+		tokens = make(chan struct{}, w.config.MaxConcurrency)
+	)
+	for _, sportID := range sports {
+		wg.Add(1)
+		tokens <- struct{}{}
+		go func(sportID int) {
+			defer wg.Done()
+			defer func() { <-tokens }()
+
+			stats, err := w.pollSport(ctx, sportID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("sport %d: %w", sportID, err))
+				return
+			}
+			total.Events += stats.Events
+			total.UnknownEvents += stats.UnknownEvents
+			total.OddsProcessed += stats.OddsProcessed
+			total.HistoryCreated += stats.HistoryCreated
+			total.Suspended += stats.Suspended
+			total.Resumed += stats.Resumed
+		}(sportID)
+	}
+	wg.Wait()
+
+	event := w.logger.Debug()
+	if total.HistoryCreated > 0 || total.Suspended > 0 || total.Resumed > 0 {
+		event = w.logger.Info()
+	}
+	event.
+		Str("action", "cycle_complete").
+		Int("sports", len(sports)).
+		Int("live_events", total.Events).
+		Int("unknown_events", total.UnknownEvents).
+		Int("odds_processed", total.OddsProcessed).
+		Int("history_records", total.HistoryCreated).
+		Int("suspended", total.Suspended).
+		Int("resumed", total.Resumed).
+		Int("failed_sports", len(errs)).
+		Msg("Live odds cycle completed")
+
+	// A single failing sport is logged; the cycle only counts as failed when nothing worked
+	if len(errs) == len(sports) {
+		return errors.Join(errs...)
+	}
+	for _, err := range errs {
+		w.logger.Warn().Err(err).Msg("Failed to poll live odds")
+	}
+	return nil
+}
+
+func (w *LiveOddsWorker) pollSport(ctx context.Context, sportID int) (services.LiveOddsStats, error) {
+	response, err := w.client.GetLiveEvents(ctx, sportID)
+	if err != nil {
+		return services.LiveOddsStats{}, err
+	}
+	if response.Data == nil {
+		return services.LiveOddsStats{}, nil
+	}
+	return w.events.ProcessLiveEvents(ctx, response.Data.Events)
+}
+
+// sportsWithLiveEvents returns the sports Iddaa reports live events for, refreshed every SportsRefresh.
+// The previous list is kept when the refresh fails.
+func (w *LiveOddsWorker) sportsWithLiveEvents(ctx context.Context) []int {
+	if time.Since(w.sportsRefreshed) < w.config.SportsRefresh {
+		return w.liveSports
+	}
+
+	info, err := w.client.GetSportInfo(ctx)
+	if err != nil {
+		w.logger.Warn().Err(err).Msg("Failed to refresh live sports, using previous list")
+		return w.liveSports
+	}
+
+	sports := make([]int, 0, len(info.Data))
+	for _, sport := range info.Data {
+		if sport.LiveCount > 0 {
+			sports = append(sports, sport.SportID)
+		}
+	}
+	w.liveSports = sports
+	w.sportsRefreshed = time.Now()
+	return sports
+}
+
+// refreshScores pulls today's statistics so odds history is stamped with current scores and minutes
+func (w *LiveOddsWorker) refreshScores(ctx context.Context, liveSports []int) {
+	if w.statistics == nil || w.config.ScoreInterval <= 0 || time.Since(w.scoresRefreshed) < w.config.ScoreInterval {
+		return
+	}
+	w.scoresRefreshed = time.Now()
+
+	live := make(map[int]bool, len(liveSports))
+	for _, sportID := range liveSports {
+		live[sportID] = true
+	}
+	today := time.Now().Format("2006-01-02")
+	for _, sportID := range w.config.ScoreSports {
+		if !live[sportID] {
+			continue
+		}
+		if err := w.statistics.SyncEventStatistics(ctx, sportID, today); err != nil {
+			w.logger.Warn().Err(err).Int("sport_id", sportID).Msg("Failed to refresh live scores")
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextLiveDelay(t *testing.T) {
+	config := &LiveOddsWorkerConfig{Interval: 5 * time.Second, MaxInterval: time.Minute}
+
+	tests := []struct {
+		name     string
+		previous time.Duration
+		elapsed  time.Duration
+		failed   bool
+		want     time.Duration
+	}{
+		{"keeping up", 5 * time.Second, time.Second, false, 5 * time.Second},
+		{"slow cycle", 5 * time.Second, 12 * time.Second, false, 12 * time.Second},
+		{"very slow cycle", 5 * time.Second, 2 * time.Minute, false, time.Minute},
+		{"first failure", 5 * time.Second, time.Second, true, 10 * time.Second},
+		{"repeated failure", 40 * time.Second, time.Second, true, time.Minute},
+		{"recovered", time.Minute, time.Second, false, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextLiveDelay(config, tt.previous, tt.elapsed, tt.failed); got != tt.want {
+				t.Errorf("nextLiveDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -11,17 +11,21 @@ import (
 )
 
 type cronJobManager struct {
-	cron   *cron.Cron
-	jobs   []Job
-	logger *logger.Logger
+	cron     *cron.Cron
+	schedule *jobSchedule
+	jobs     []Job
+	logger   *logger.Logger
+	runs     *JobRunRecorder
 }
 
 // NewJobManager creates a new job manager
 func NewJobManager() JobManager {
+	c := cron.New(cron.WithLocation(time.UTC))
 	return &cronJobManager{
-		cron:   cron.New(cron.WithLocation(time.UTC)),
-		jobs:   make([]Job, 0),
-		logger: logger.New("job-manager"),
+		cron:     c,
+		schedule: newJobSchedule(c),
+		jobs:     make([]Job, 0),
+		logger:   logger.New("job-manager"),
 	}
 }
 
@@ -36,34 +40,9 @@ func (m *cronJobManager) RegisterJob(job Job) error {
 		Str("schedule", job.Schedule()).
 		Msg("Registering job")
 
-	_, err := m.cron.AddFunc(job.Schedule(), func() {
-		// Create unique request ID for job execution
-		requestID := uuid.New().String()
-		jobLogger := m.logger.WithRequestID(requestID).WithJob(job.Name())
-
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
-		defer cancel()
-
-		// Add logger to context
-		ctx = jobLogger.ToContext(ctx)
-
-		jobLogger.LogJobStart(job.Name(), job.Schedule())
-		start := time.Now()
-
-		if err := job.Execute(ctx); err != nil {
-			jobLogger.Error().
-				Err(err).
-				Str("action", "job_failed").
-				Dur("duration", time.Since(start)).
-				Msg("Job execution failed")
-		} else {
-			duration := time.Since(start)
-			// For successful completion without specific metrics, indicate job ran successfully
-			// Individual jobs should log their own detailed metrics during execution
-			jobLogger.LogJobComplete(job.Name(), duration, 0, 0) // Generic successful completion
-		}
+	err := m.schedule.add(job.Name(), job.Schedule(), func() {
+		_, _ = m.execute(job, TriggerCron)
 	})
-
 	if err != nil {
 		return fmt.Errorf("failed to schedule job %s: %w", job.Name(), err)
 	}
@@ -72,6 +51,50 @@ func (m *cronJobManager) RegisterJob(job Job) error {
 	return nil
 }
 
+// execute runs a job once, unless a run of it is still in progress
+func (m *cronJobManager) execute(job Job, trigger string) (JobRunResult, error) {
+	if !m.schedule.tryStart(job.Name()) {
+		m.logger.Warn().
+			Str("action", "job_overlap_skipped").
+			Str("job_name", job.Name()).
+			Str("trigger", trigger).
+			Msg("Job still running, run skipped")
+		return JobRunResult{}, ErrJobRunning
+	}
+	defer m.schedule.finish(job.Name())
+
+	// Create unique request ID for job execution
+	requestID := uuid.New().String()
+	jobLogger := m.logger.WithRequestID(requestID).WithJob(job.Name())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	// Add logger to context
+	ctx = jobLogger.ToContext(ctx)
+	ctx, run := m.runs.start(ctx, job.Name(), trigger)
+
+	jobLogger.LogJobStart(job.Name(), job.Schedule())
+	start := time.Now()
+
+	err := job.Execute(ctx)
+	result := m.runs.finish(run, err)
+	if err != nil {
+		jobLogger.Error().
+			Err(err).
+			Str("action", "job_failed").
+			Str("trigger", trigger).
+			Dur("duration", time.Since(start)).
+			Msg("Job execution failed")
+	} else {
+		duration := time.Since(start)
+		// Items are those the job reported, individual jobs log their own detailed metrics
+		jobLogger.LogJobComplete(job.Name(), duration, run.items(), 0)
+	}
+
+	return result, err
+}
+
 func (m *cronJobManager) Start() {
 	m.logger.Info().
 		Str("action", "start").
@@ -81,6 +104,11 @@ func (m *cronJobManager) Start() {
 	// Run all jobs once on startup
 	for _, job := range m.jobs {
 		go func(j Job) {
+			if !m.schedule.tryStart(j.Name()) {
+				return
+			}
+			defer m.schedule.finish(j.Name())
+
 			// Create unique request ID for startup job execution
 			requestID := uuid.New().String()
 			jobLogger := m.logger.WithRequestID(requestID).WithJob(j.Name())
@@ -90,6 +118,7 @@ func (m *cronJobManager) Start() {
 
 			// Add logger to context
 			ctx = jobLogger.ToContext(ctx)
+			ctx, run := m.runs.start(ctx, j.Name(), TriggerStartup)
 
 			jobLogger.Info().
 				Str("action", "startup_job_start").
@@ -98,7 +127,9 @@ func (m *cronJobManager) Start() {
 
 			start := time.Now()
 
-			if err := j.Execute(ctx); err != nil {
+			err := j.Execute(ctx)
+			m.runs.finish(run, err)
+			if err != nil {
 				jobLogger.Error().
 					Err(err).
 					Str("action", "startup_job_failed").
@@ -110,6 +141,7 @@ func (m *cronJobManager) Start() {
 					Str("action", "startup_job_complete").
 					Str("job_name", j.Name()).
 					Dur("duration", duration).
+					Int("items_processed", run.items()).
 					Msg("Startup job completed successfully")
 			}
 		}(job)
@@ -134,3 +166,32 @@ func (m *cronJobManager) Stop() {
 func (m *cronJobManager) GetJobs() []Job {
 	return append([]Job(nil), m.jobs...)
 }
+
+func (m *cronJobManager) SetRunRecorder(recorder *JobRunRecorder) {
+	m.runs = recorder
+}
+
+func (m *cronJobManager) RunJob(name, trigger string) (JobRunResult, error) {
+	for _, job := range m.jobs {
+		if job.Name() == name {
+			return m.execute(job, trigger)
+		}
+	}
+	return JobRunResult{}, ErrJobNotFound
+}
+
+func (m *cronJobManager) PauseJob(name string) error {
+	return m.schedule.pause(name)
+}
+
+func (m *cronJobManager) ResumeJob(name string) error {
+	return m.schedule.resume(name)
+}
+
+func (m *cronJobManager) RescheduleJob(name, schedule string) error {
+	return m.schedule.reschedule(name, schedule)
+}
+
+func (m *cronJobManager) JobStates() []JobState {
+	return m.schedule.states()
+}
@@ -41,6 +41,7 @@ func (j *MarketConfigSyncJob) Execute(ctx context.Context) error {
 	}
 
 	duration := time.Since(start)
+	RecordItemsProcessed(ctx, 1)
 	log.LogJobComplete("market_config_sync", duration, 1, 0)
 	return nil
 }
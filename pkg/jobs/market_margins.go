@@ -0,0 +1,62 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// marginLookback covers three runs, so a failed run is picked up by the next ones
+const marginLookback = 15 * time.Minute
+
+// MarketMarginsJob recomputes bookmaker margins and fair probabilities of markets whose odds moved
+type MarketMarginsJob struct {
+	marginService *services.MarginService
+}
+
+// NewMarketMarginsJob creates a new market margins job
+func NewMarketMarginsJob(marginService *services.MarginService) *MarketMarginsJob {
+	return &MarketMarginsJob{
+		marginService: marginService,
+	}
+}
+
+// Name returns the job name for CLI execution
+func (j *MarketMarginsJob) Name() string {
+	return "market_margins"
+}
+
+// Schedule returns the cron schedule - every 5 minutes
+func (j *MarketMarginsJob) Schedule() string {
+	return "*/5 * * * *"
+}
+
+// Execute de-vigs every market of events with odds changes in the lookback window
+func (j *MarketMarginsJob) Execute(ctx context.Context) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 4*time.Minute) // 4 minutes to avoid overlap
+	defer cancel()
+	ctx = timeoutCtx
+
+	log := logger.WithContext(ctx, "market-margins")
+	start := time.Now()
+
+	stats, err := j.marginService.Refresh(ctx, start.Add(-marginLookback))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to refresh market margins")
+		return err
+	}
+
+	RecordItemsProcessed(ctx, stats.Markets)
+	log.Info().
+		Str("action", "market_margins_complete").
+		Int("events", stats.Events).
+		Int("markets", stats.Markets).
+		Int("skipped", stats.Skipped).
+		Int64("changed", stats.Changed).
+		Dur("duration", time.Since(start)).
+		Msg("Market margins job completed")
+
+	return nil
+}
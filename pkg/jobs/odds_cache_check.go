@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// OddsCacheCheckJob compares the in-process current odds cache with the database and rebuilds it
+type OddsCacheCheckJob struct {
+	oddsCache *services.OddsCache
+}
+
+// NewOddsCacheCheckJob creates a new odds cache consistency check job
+func NewOddsCacheCheckJob(oddsCache *services.OddsCache) *OddsCacheCheckJob {
+	return &OddsCacheCheckJob{
+		oddsCache: oddsCache,
+	}
+}
+
+// Name returns the job name for CLI execution
+func (j *OddsCacheCheckJob) Name() string {
+	return "odds_cache_check"
+}
+
+// Schedule returns the cron schedule - every 10 minutes
+func (j *OddsCacheCheckJob) Schedule() string {
+	return "*/10 * * * *"
+}
+
+// Execute rebuilds the cache from current_odds, logging differences found in a cache that was not stale
+func (j *OddsCacheCheckJob) Execute(ctx context.Context) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	ctx = timeoutCtx
+
+	log := logger.WithContext(ctx, "odds-cache-check")
+	start := time.Now()
+
+	report, err := j.oddsCache.Rebuild(ctx, start)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to rebuild odds cache")
+		return err
+	}
+
+	if report.Inconsistent() {
+		log.Warn().
+			Str("action", "odds_cache_inconsistent").
+			Int("missing", report.Missing).
+			Int("mismatched", report.Mismatched).
+			Int("extra", report.Extra).
+			Int64("version", report.Version).
+			Msg("Odds cache differed from current odds")
+	}
+
+	RecordItemsProcessed(ctx, report.Entries)
+	log.Info().
+		Str("action", "odds_cache_rebuilt").
+		Int("entries", report.Entries).
+		Bool("was_stale", report.WasStale).
+		Int64("version", report.Version).
+		Dur("duration", time.Since(start)).
+		Msg("Odds cache check completed")
+
+	return nil
+}
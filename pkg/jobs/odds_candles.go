@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// OddsCandlesJob rolls odds_history up into OHLC candles for the candles API
+type OddsCandlesJob struct {
+	candleService *services.CandleService
+}
+
+// NewOddsCandlesJob creates a new odds candles job
+func NewOddsCandlesJob(candleService *services.CandleService) *OddsCandlesJob {
+	return &OddsCandlesJob{
+		candleService: candleService,
+	}
+}
+
+// Name returns the job name for CLI execution
+func (j *OddsCandlesJob) Name() string {
+	return "odds_candles"
+}
+
+// Schedule returns the cron schedule - every 5 minutes
+func (j *OddsCandlesJob) Schedule() string {
+	return "*/5 * * * *"
+}
+
+// Execute rebuilds the 5-minute, hourly and daily candles since the newest stored candle
+func (j *OddsCandlesJob) Execute(ctx context.Context) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 4*time.Minute) // 4 minutes to avoid overlap
+	defer cancel()
+	ctx = timeoutCtx
+
+	log := logger.WithContext(ctx, "odds-candles")
+	start := time.Now()
+
+	stats, err := j.candleService.Rollup(ctx, start)
+	if err != nil {
+		log.Error().Err(err).Time("since", stats.Since).Msg("Failed to roll up odds candles")
+		return err
+	}
+
+	RecordItemsProcessed(ctx, int(stats.FiveMinute))
+	log.Info().
+		Str("action", "odds_candles_complete").
+		Time("since", stats.Since).
+		Int64("candles_5m", stats.FiveMinute).
+		Int64("candles_1h", stats.Hourly).
+		Int64("candles_1d", stats.Daily).
+		Dur("duration", time.Since(start)).
+		Msg("Odds candles job completed")
+
+	return nil
+}
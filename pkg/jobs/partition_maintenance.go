@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// PartitionMaintenanceJob creates future partitions of the history tables and archives expired ones
+type PartitionMaintenanceJob struct {
+	partitionService *services.PartitionService
+}
+
+// NewPartitionMaintenanceJob creates a new partition maintenance job
+func NewPartitionMaintenanceJob(partitionService *services.PartitionService) *PartitionMaintenanceJob {
+	return &PartitionMaintenanceJob{
+		partitionService: partitionService,
+	}
+}
+
+// Name returns the job name for CLI execution
+func (j *PartitionMaintenanceJob) Name() string {
+	return "partition_maintenance"
+}
+
+// Schedule returns the cron schedule - daily at 03:30
+func (j *PartitionMaintenanceJob) Schedule() string {
+	return "30 3 * * *"
+}
+
+// Execute creates the premade partitions first, so inserts never miss a partition even when
+// archiving an old one fails
+func (j *PartitionMaintenanceJob) Execute(ctx context.Context) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 2*time.Hour) // archiving large partitions takes a while
+	defer cancel()
+	ctx = timeoutCtx
+
+	log := logger.WithContext(ctx, "partition-maintenance")
+	start := time.Now()
+
+	created, err := j.partitionService.EnsurePartitions(ctx, start)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create history partitions")
+		return err
+	}
+
+	stats, err := j.partitionService.ApplyRetention(ctx, start)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Int("archived", stats.Archived).
+			Msg("Failed to apply history retention")
+		return err
+	}
+
+	log.Info().
+		Str("action", "partition_maintenance_complete").
+		Int("created", created).
+		Int("archived", stats.Archived).
+		Int64("archived_rows", stats.ArchivedRows).
+		Dur("duration", time.Since(start)).
+		Msg("Partition maintenance job completed")
+
+	return nil
+}
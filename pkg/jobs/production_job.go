@@ -92,6 +92,7 @@ func (p *ProductionJob) Execute(ctx context.Context) error {
 	}
 
 	if err != nil {
+		recordLockOutcome(ctx, LockError)
 		p.logger.Error().
 			Err(err).
 			Str("job_name", jobName).
@@ -101,6 +102,7 @@ func (p *ProductionJob) Execute(ctx context.Context) error {
 	}
 
 	if !acquired {
+		recordLockOutcome(ctx, LockBusy)
 		if p.skipIfLocked {
 			p.logger.Info().
 				Str("job_name", jobName).
@@ -123,6 +125,7 @@ func (p *ProductionJob) Execute(ctx context.Context) error {
 		}
 	}()
 
+	recordLockOutcome(ctx, LockAcquired)
 	p.logger.Info().
 		Str("job_name", jobName).
 		Str("action", "lock_acquired").
@@ -15,9 +15,11 @@ import (
 // ProductionJobManager extends the regular job manager with production features
 type ProductionJobManager struct {
 	cron        *cron.Cron
+	schedule    *jobSchedule
 	jobs        []Job
 	logger      *logger.Logger
 	lockManager JobLockManager
+	runs        *JobRunRecorder
 
 	// Production features
 	enableLocking bool
@@ -40,9 +42,11 @@ func NewProductionJobManager(db generated.DBTX, config *ProductionJobManagerConf
 	}
 
 	lockManager := NewPostgreSQLLockManager(db)
+	c := cron.New(cron.WithLocation(time.UTC))
 
 	return &ProductionJobManager{
-		cron:          cron.New(cron.WithLocation(time.UTC)),
+		cron:          c,
+		schedule:      newJobSchedule(c),
 		jobs:          make([]Job, 0),
 		logger:        logger.New("production-job-manager"),
 		lockManager:   lockManager,
@@ -77,38 +81,59 @@ func (m *ProductionJobManager) RegisterJob(job Job) error {
 		Bool("locking_enabled", m.enableLocking).
 		Msg("Registering production job")
 
-	_, err := m.cron.AddFunc(finalJob.Schedule(), func() {
-		// Create unique request ID for job execution
-		requestID := uuid.New().String()
-		jobLogger := m.logger.WithRequestID(requestID).WithJob(finalJob.Name())
+	err := m.schedule.add(finalJob.Name(), finalJob.Schedule(), func() {
+		_, _ = m.execute(finalJob, TriggerCron)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule job %s: %w", finalJob.Name(), err)
+	}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
-		defer cancel()
+	m.jobs = append(m.jobs, finalJob)
+	return nil
+}
 
-		// Add logger to context
-		ctx = jobLogger.ToContext(ctx)
+// execute runs a job once, unless a run of it is still in progress in this process.
+// Wrapped jobs take their distributed lock, so runs never overlap across instances either.
+func (m *ProductionJobManager) execute(job Job, trigger string) (JobRunResult, error) {
+	if !m.schedule.tryStart(job.Name()) {
+		m.logger.Warn().
+			Str("action", "job_overlap_skipped").
+			Str("job_name", job.Name()).
+			Str("trigger", trigger).
+			Msg("Job still running, run skipped")
+		return JobRunResult{}, ErrJobRunning
+	}
+	defer m.schedule.finish(job.Name())
 
-		jobLogger.LogJobStart(finalJob.Name(), finalJob.Schedule())
-		start := time.Now()
+	// Create unique request ID for job execution
+	requestID := uuid.New().String()
+	jobLogger := m.logger.WithRequestID(requestID).WithJob(job.Name())
 
-		if err := finalJob.Execute(ctx); err != nil {
-			jobLogger.Error().
-				Err(err).
-				Str("action", "job_failed").
-				Dur("duration", time.Since(start)).
-				Msg("Production job execution failed")
-		} else {
-			duration := time.Since(start)
-			jobLogger.LogJobComplete(finalJob.Name(), duration, 0, 0)
-		}
-	})
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	// Add logger to context
+	ctx = jobLogger.ToContext(ctx)
+	ctx, run := m.runs.start(ctx, job.Name(), trigger)
+
+	jobLogger.LogJobStart(job.Name(), job.Schedule())
+	start := time.Now()
 
+	err := job.Execute(ctx)
+	result := m.runs.finish(run, err)
 	if err != nil {
-		return fmt.Errorf("failed to schedule job %s: %w", finalJob.Name(), err)
+		jobLogger.Error().
+			Err(err).
+			Str("action", "job_failed").
+			Str("trigger", trigger).
+			Dur("duration", time.Since(start)).
+			Msg("Production job execution failed")
+	} else {
+		duration := time.Since(start)
+		jobLogger.LogJobComplete(job.Name(), duration, run.items(), 0)
 	}
 
-	m.jobs = append(m.jobs, finalJob)
-	return nil
+	return result, err
 }
 
 // RegisterJobWithConfig registers a job with custom production configuration
@@ -193,7 +218,7 @@ func (m *ProductionJobManager) runStartupJobs() {
 			}
 		}
 
-		if !isStartupJob {
+		if !isStartupJob || !m.schedule.tryStart(jobName) {
 			continue
 		}
 
@@ -208,9 +233,12 @@ func (m *ProductionJobManager) runStartupJobs() {
 
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 		ctx = jobLogger.ToContext(ctx)
+		ctx, run := m.runs.start(ctx, jobName, TriggerStartup)
 
 		start := time.Now()
-		if err := job.Execute(ctx); err != nil {
+		err := job.Execute(ctx)
+		m.runs.finish(run, err)
+		if err != nil {
 			jobLogger.Error().
 				Err(err).
 				Str("action", "startup_job_failed").
@@ -218,10 +246,11 @@ func (m *ProductionJobManager) runStartupJobs() {
 				Msg("Startup job execution failed")
 		} else {
 			duration := time.Since(start)
-			jobLogger.LogJobComplete(jobName, duration, 0, 0)
+			jobLogger.LogJobComplete(jobName, duration, run.items(), 0)
 		}
 
 		cancel()
+		m.schedule.finish(jobName)
 	}
 }
 
@@ -247,6 +276,41 @@ func (m *ProductionJobManager) GetJobs() []Job {
 	return m.jobs
 }
 
+// SetRunRecorder persists every run in job_runs
+func (m *ProductionJobManager) SetRunRecorder(recorder *JobRunRecorder) {
+	m.runs = recorder
+}
+
+// RunJob runs a registered job now, under its distributed lock, and waits for it
+func (m *ProductionJobManager) RunJob(name, trigger string) (JobRunResult, error) {
+	for _, job := range m.jobs {
+		if job.Name() == name {
+			return m.execute(job, trigger)
+		}
+	}
+	return JobRunResult{}, ErrJobNotFound
+}
+
+// PauseJob stops scheduling a job, a run in progress finishes
+func (m *ProductionJobManager) PauseJob(name string) error {
+	return m.schedule.pause(name)
+}
+
+// ResumeJob schedules a paused job again
+func (m *ProductionJobManager) ResumeJob(name string) error {
+	return m.schedule.resume(name)
+}
+
+// RescheduleJob replaces the cron schedule of a job
+func (m *ProductionJobManager) RescheduleJob(name, schedule string) error {
+	return m.schedule.reschedule(name, schedule)
+}
+
+// JobStates returns the runtime state of every registered job
+func (m *ProductionJobManager) JobStates() []JobState {
+	return m.schedule.states()
+}
+
 // GetLockManager returns the distributed lock manager
 func (m *ProductionJobManager) GetLockManager() JobLockManager {
 	return m.lockManager
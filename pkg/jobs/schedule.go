@@ -0,0 +1,205 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	// ErrJobNotFound is returned for a job name no job is registered under
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobRunning is returned when a run would overlap a run of the same job in this process
+	ErrJobRunning = errors.New("job is already running")
+)
+
+// JobRunResult is the outcome of a run started with RunJob
+type JobRunResult struct {
+	// RunID of the job_runs row, 0 when the run was not recorded
+	RunID  int64
+	Status string
+}
+
+// JobState describes a registered job at runtime
+type JobState struct {
+	Name string
+	// Schedule in effect and the one the job registered with
+	Schedule        string
+	DefaultSchedule string
+	Paused          bool
+	Running         bool
+	// Next scheduled run, zero while paused or before the manager starts
+	Next time.Time
+}
+
+// jobSchedule keeps the cron entries of the registered jobs so they can be paused, resumed and
+// rescheduled at runtime, and keeps runs of the same job from overlapping in this process.
+// Advisory locks are reentrant within a database session, so the distributed locks alone do not.
+type jobSchedule struct {
+	cron *cron.Cron
+
+	mu   sync.Mutex
+	jobs map[string]*scheduledJob
+}
+
+type scheduledJob struct {
+	run             func()
+	entryID         cron.EntryID
+	schedule        string
+	defaultSchedule string
+	paused          bool
+	running         bool
+}
+
+func newJobSchedule(c *cron.Cron) *jobSchedule {
+	return &jobSchedule{
+		cron: c,
+		jobs: make(map[string]*scheduledJob),
+	}
+}
+
+// add schedules run under the job name
+func (s *jobSchedule) add(name, schedule string, run func()) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %s is already registered", name)
+	}
+
+	entryID, err := s.cron.AddFunc(schedule, run)
+	if err != nil {
+		return err
+	}
+
+	s.jobs[name] = &scheduledJob{
+		run:             run,
+		entryID:         entryID,
+		schedule:        schedule,
+		defaultSchedule: schedule,
+	}
+	return nil
+}
+
+// pause removes the cron entry of a job, a run in progress finishes
+func (s *jobSchedule) pause(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[name]
+	if !ok {
+		return ErrJobNotFound
+	}
+	if job.paused {
+		return nil
+	}
+
+	s.cron.Remove(job.entryID)
+	job.entryID = 0
+	job.paused = true
+	return nil
+}
+
+// resume schedules a paused job again
+func (s *jobSchedule) resume(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[name]
+	if !ok {
+		return ErrJobNotFound
+	}
+	if !job.paused {
+		return nil
+	}
+
+	entryID, err := s.cron.AddFunc(job.schedule, job.run)
+	if err != nil {
+		return fmt.Errorf("failed to schedule job %s: %w", name, err)
+	}
+	job.entryID = entryID
+	job.paused = false
+	return nil
+}
+
+// reschedule replaces the schedule of a job, a paused job keeps it for when it resumes
+func (s *jobSchedule) reschedule(name, schedule string) error {
+	if _, err := cron.ParseStandard(schedule); err != nil {
+		return fmt.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[name]
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	if !job.paused {
+		entryID, err := s.cron.AddFunc(schedule, job.run)
+		if err != nil {
+			return fmt.Errorf("failed to schedule job %s: %w", name, err)
+		}
+		s.cron.Remove(job.entryID)
+		job.entryID = entryID
+	}
+	job.schedule = schedule
+	return nil
+}
+
+// tryStart marks a job running, false when it already is
+func (s *jobSchedule) tryStart(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[name]
+	if !ok {
+		return true
+	}
+	if job.running {
+		return false
+	}
+	job.running = true
+	return true
+}
+
+// finish marks a run started with tryStart as done
+func (s *jobSchedule) finish(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job, ok := s.jobs[name]; ok {
+		job.running = false
+	}
+}
+
+// states returns the runtime state of every job, sorted by name
+func (s *jobSchedule) states() []JobState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make([]JobState, 0, len(s.jobs))
+	for name, job := range s.jobs {
+		state := JobState{
+			Name:            name,
+			Schedule:        job.schedule,
+			DefaultSchedule: job.defaultSchedule,
+			Paused:          job.paused,
+			Running:         job.running,
+		}
+		if !job.paused {
+			state.Next = s.cron.Entry(job.entryID).Next
+		}
+		states = append(states, state)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+	return states
+}
@@ -0,0 +1,158 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestJobManager_PauseResumeReschedule(t *testing.T) {
+	manager := NewJobManager()
+	if err := manager.RegisterJob(&mockJob{name: "events_sync", schedule: "@every 1h"}); err != nil {
+		t.Fatalf("Failed to register job: %v", err)
+	}
+	manager.Start()
+	defer manager.Stop()
+
+	if err := manager.PauseJob("events_sync"); err != nil {
+		t.Fatalf("PauseJob() error = %v", err)
+	}
+	state := manager.JobStates()[0]
+	if !state.Paused || !state.Next.IsZero() {
+		t.Errorf("paused = %v, next = %v, want paused without a next run", state.Paused, state.Next)
+	}
+
+	if err := manager.RescheduleJob("events_sync", "invalid-cron"); err == nil {
+		t.Error("RescheduleJob() accepted an invalid schedule")
+	}
+	if err := manager.RescheduleJob("events_sync", "*/5 * * * *"); err != nil {
+		t.Fatalf("RescheduleJob() error = %v", err)
+	}
+	if err := manager.ResumeJob("events_sync"); err != nil {
+		t.Fatalf("ResumeJob() error = %v", err)
+	}
+
+	state = manager.JobStates()[0]
+	if state.Paused || state.Next.IsZero() {
+		t.Errorf("paused = %v, next = %v, want a scheduled run", state.Paused, state.Next)
+	}
+	if state.Schedule != "*/5 * * * *" || state.DefaultSchedule != "@every 1h" {
+		t.Errorf("schedule = %q default = %q, want the new schedule and the registered one", state.Schedule, state.DefaultSchedule)
+	}
+
+	for name, err := range map[string]error{
+		"pause":      manager.PauseJob("unknown"),
+		"resume":     manager.ResumeJob("unknown"),
+		"reschedule": manager.RescheduleJob("unknown", "* * * * *"),
+	} {
+		if !errors.Is(err, ErrJobNotFound) {
+			t.Errorf("%s of an unknown job: error = %v, want ErrJobNotFound", name, err)
+		}
+	}
+}
+
+func TestJobManager_RegisterDuplicate(t *testing.T) {
+	manager := NewJobManager()
+	if err := manager.RegisterJob(&mockJob{name: "events_sync", schedule: "@every 1h"}); err != nil {
+		t.Fatalf("Failed to register job: %v", err)
+	}
+	if err := manager.RegisterJob(&mockJob{name: "events_sync", schedule: "@every 2h"}); err == nil {
+		t.Error("RegisterJob() accepted a second job with the same name")
+	}
+}
+
+func TestJobManager_RunJob(t *testing.T) {
+	manager := NewJobManager()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	if err := manager.RegisterJob(&mockJob{
+		name:     "events_sync",
+		schedule: "@every 1h",
+		executeFunc: func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		},
+	}); err != nil {
+		t.Fatalf("Failed to register job: %v", err)
+	}
+	if err := manager.RegisterJob(&mockJob{
+		name:     "volume_sync",
+		schedule: "@every 1h",
+		executeFunc: func(ctx context.Context) error {
+			return errors.New("upstream down")
+		},
+	}); err != nil {
+		t.Fatalf("Failed to register job: %v", err)
+	}
+
+	if _, err := manager.RunJob("unknown", TriggerManual); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("RunJob() of an unknown job: error = %v, want ErrJobNotFound", err)
+	}
+
+	type outcome struct {
+		result JobRunResult
+		err    error
+	}
+	done := make(chan outcome)
+	go func() {
+		result, err := manager.RunJob("events_sync", TriggerManual)
+		done <- outcome{result, err}
+	}()
+	<-started
+
+	// A second run while the first is in progress is refused
+	if _, err := manager.RunJob("events_sync", TriggerManual); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("overlapping RunJob(): error = %v, want ErrJobRunning", err)
+	}
+	if !manager.JobStates()[0].Running {
+		t.Error("job should be reported running")
+	}
+
+	close(release)
+	if got := <-done; got.err != nil || got.result.Status != RunSucceeded {
+		t.Errorf("RunJob() = %+v, %v, want succeeded", got.result, got.err)
+	}
+	if manager.JobStates()[0].Running {
+		t.Error("job should not be running after its run finished")
+	}
+
+	result, err := manager.RunJob("volume_sync", TriggerManual)
+	if err == nil || result.Status != RunFailed {
+		t.Errorf("RunJob() = %+v, %v, want failed", result, err)
+	}
+}
+
+func TestProductionJobManager_RunJobRespectsLock(t *testing.T) {
+	locks := NewMockDB()
+	manager := NewProductionJobManager(locks, &ProductionJobManagerConfig{
+		EnableLocking: true,
+		DefaultConfig: &ProductionJobConfig{SkipIfLocked: true},
+	})
+
+	job := &mockJob{name: "detailed_odds", schedule: "@every 1h"}
+	if err := manager.RegisterJob(job); err != nil {
+		t.Fatalf("Failed to register job: %v", err)
+	}
+
+	// A scheduled run on another instance holds the lock
+	lockManager := NewPostgreSQLLockManager(locks)
+	if acquired, err := lockManager.AcquireLock(context.Background(), job.Name()); err != nil || !acquired {
+		t.Fatalf("failed to take the lock: %v", err)
+	}
+
+	result, err := manager.RunJob(job.Name(), TriggerManual)
+	if err != nil || result.Status != RunSkipped || job.executed {
+		t.Fatalf("RunJob() = %+v, %v, executed = %v, want skipped", result, err, job.executed)
+	}
+
+	if err := lockManager.ReleaseLock(context.Background(), job.Name()); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = manager.RunJob(job.Name(), TriggerManual)
+	if err != nil || result.Status != RunSucceeded || !job.executed {
+		t.Errorf("RunJob() = %+v, %v, executed = %v, want succeeded", result, err, job.executed)
+	}
+}
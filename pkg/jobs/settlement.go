@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// SettlementJob grades market outcomes from final scores stored by the statistics sync
+type SettlementJob struct {
+	settlementService *services.SettlementService
+}
+
+// NewSettlementJob creates a new settlement job
+func NewSettlementJob(settlementService *services.SettlementService) *SettlementJob {
+	return &SettlementJob{
+		settlementService: settlementService,
+	}
+}
+
+// Name returns the job name for CLI execution
+func (j *SettlementJob) Name() string {
+	return "settlement"
+}
+
+// Schedule returns the cron schedule - every 15 minutes
+func (j *SettlementJob) Schedule() string {
+	return "*/15 * * * *"
+}
+
+// Execute settles outcomes for new or corrected results, voids cancelled events and grades alerts
+func (j *SettlementJob) Execute(ctx context.Context) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Second) // 50 seconds to avoid overlap
+	defer cancel()
+	ctx = timeoutCtx
+
+	log := logger.WithContext(ctx, "settlement")
+	start := time.Now()
+
+	stats, err := j.settlementService.ProcessPending(ctx, 200)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to settle outcomes")
+		return err
+	}
+
+	RecordItemsProcessed(ctx, stats.Settled)
+	log.Info().
+		Str("action", "settlement_complete").
+		Int("events", stats.Events).
+		Int("settled", stats.Settled).
+		Int("skipped", stats.Skipped).
+		Int64("voided", stats.Voided).
+		Int("failures", stats.Failures).
+		Int64("graded_alerts", stats.GradedAlerts).
+		Dur("duration", time.Since(start)).
+		Msg("Settlement job completed")
+
+	return nil
+}
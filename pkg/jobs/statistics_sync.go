@@ -2,6 +2,7 @@ package jobs
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/iddaa-lens/core/pkg/logger"
@@ -96,7 +97,13 @@ func (j *StatisticsSyncJob) Execute(ctx context.Context) error {
 	}
 
 	duration := time.Since(start)
+	RecordItemsProcessed(ctx, processedDates)
 	log.LogJobComplete("statistics_sync", duration, processedDates, errorCount)
+
+	// Partial failures are only logged, a sync where both dates failed is a failed run
+	if processedDates == 0 && errorCount > 0 {
+		return fmt.Errorf("statistics sync failed for all %d dates", errorCount)
+	}
 	return nil
 }
 
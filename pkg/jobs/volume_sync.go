@@ -57,7 +57,13 @@ func (j *VolumeSyncJob) Execute(ctx context.Context) error {
 	totalProcessed, errorCount := j.processSportsConcurrently(ctx, sports)
 
 	duration := time.Since(start)
+	RecordItemsProcessed(ctx, totalProcessed)
 	log.LogJobComplete("volume_sync", duration, totalProcessed, errorCount)
+
+	// Partial failures are only logged, a sync where every sport failed is a failed run
+	if totalProcessed == 0 && errorCount > 0 {
+		return fmt.Errorf("volume sync failed for all %d sports", errorCount)
+	}
 	return nil
 }
 
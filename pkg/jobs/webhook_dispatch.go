@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/services"
+)
+
+// WebhookDispatchJob delivers new movement alerts to registered webhooks
+type WebhookDispatchJob struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookDispatchJob creates a new webhook dispatch job
+func NewWebhookDispatchJob(webhookService *services.WebhookService) *WebhookDispatchJob {
+	return &WebhookDispatchJob{
+		webhookService: webhookService,
+	}
+}
+
+// Name returns the job name for CLI execution
+func (j *WebhookDispatchJob) Name() string {
+	return "webhook_dispatch"
+}
+
+// Schedule returns the cron schedule - every minute
+func (j *WebhookDispatchJob) Schedule() string {
+	return "* * * * *"
+}
+
+// Execute enqueues and sends due webhook deliveries
+func (j *WebhookDispatchJob) Execute(ctx context.Context) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Second) // 50 seconds to avoid overlap
+	defer cancel()
+	ctx = timeoutCtx
+
+	log := logger.WithContext(ctx, "webhook-dispatch")
+	start := time.Now()
+
+	stats, err := j.webhookService.ProcessDeliveries(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to process webhook deliveries")
+		return err
+	}
+
+	RecordItemsProcessed(ctx, stats.Delivered)
+	log.Info().
+		Str("action", "dispatch_complete").
+		Int64("enqueued", stats.Enqueued).
+		Int("delivered", stats.Delivered).
+		Int("retrying", stats.Retrying).
+		Int("dead", stats.Dead).
+		Dur("duration", time.Since(start)).
+		Msg("Webhook dispatch completed")
+
+	return nil
+}
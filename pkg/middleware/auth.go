@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+)
+
+// APIKeyPrefix marks keys issued by this service
+const APIKeyPrefix = "il_"
+
+// APIKeyHeader is an alternative to "Authorization: Bearer <key>"
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyStore is the subset of queries needed to authenticate API keys
+type APIKeyStore interface {
+	GetAPIKeyUser(ctx context.Context, keyHash string) (generated.GetAPIKeyUserRow, error)
+	TouchAPIKey(ctx context.Context, id int32) error
+}
+
+// AuthUser is the authenticated caller attached to the request context
+type AuthUser struct {
+	ID       int32
+	Email    string
+	Name     *string
+	APIKeyID int32
+}
+
+type userContextKey struct{}
+
+// Auth authenticates requests with per-user API keys or the admin key
+type Auth struct {
+	store    APIKeyStore
+	adminKey string
+	logger   *logger.Logger
+}
+
+// NewAuth creates API key middleware; an empty adminKey disables admin endpoints
+func NewAuth(store APIKeyStore, adminKey string) *Auth {
+	return &Auth{
+		store:    store,
+		adminKey: adminKey,
+		logger:   logger.New("auth-middleware"),
+	}
+}
+
+// RequireUser rejects requests without a valid user API key
+func (a *Auth) RequireUser(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := apiKeyFromRequest(r)
+		if key == "" {
+			http.Error(w, "Missing API key", http.StatusUnauthorized)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		row, err := a.store.GetAPIKeyUser(ctx, HashAPIKey(key))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				http.Error(w, "Invalid API key", http.StatusUnauthorized)
+				return
+			}
+			a.logger.Error().Err(err).Msg("Failed to authenticate API key")
+			http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+			return
+		}
+
+		if err := a.store.TouchAPIKey(ctx, row.ApiKeyID); err != nil {
+			a.logger.Warn().Err(err).Int32("api_key_id", row.ApiKeyID).Msg("Failed to record API key usage")
+		}
+
+		user := AuthUser{
+			ID:       row.UserID,
+			Email:    row.Email,
+			Name:     row.Name,
+			APIKeyID: row.ApiKeyID,
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), userContextKey{}, user)))
+	}
+}
+
+// RequireAdmin rejects requests that do not carry the configured admin key
+func (a *Auth) RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if a.adminKey == "" {
+			http.Error(w, "Admin API is disabled", http.StatusForbidden)
+			return
+		}
+
+		key := apiKeyFromRequest(r)
+		if subtle.ConstantTimeCompare([]byte(key), []byte(a.adminKey)) != 1 {
+			http.Error(w, "Invalid admin key", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// UserFromContext returns the user set by RequireUser
+func UserFromContext(ctx context.Context) (AuthUser, bool) {
+	user, ok := ctx.Value(userContextKey{}).(AuthUser)
+	return user, ok
+}
+
+// GenerateAPIKey returns a new random API key
+func GenerateAPIKey() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return APIKeyPrefix + hex.EncodeToString(buf), nil
+}
+
+// HashAPIKey returns the hex SHA-256 of a key, the only form that is stored
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// DisplayPrefix returns the leading characters of a key shown in listings
+func DisplayPrefix(key string) string {
+	if len(key) <= 10 {
+		return key
+	}
+	return key[:10]
+}
+
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return strings.TrimSpace(key)
+	}
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return ""
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+type fakeAPIKeyStore struct {
+	keys    map[string]generated.GetAPIKeyUserRow
+	touched []int32
+}
+
+func (f *fakeAPIKeyStore) GetAPIKeyUser(ctx context.Context, keyHash string) (generated.GetAPIKeyUserRow, error) {
+	row, ok := f.keys[keyHash]
+	if !ok {
+		return generated.GetAPIKeyUserRow{}, pgx.ErrNoRows
+	}
+	return row, nil
+}
+
+func (f *fakeAPIKeyStore) TouchAPIKey(ctx context.Context, id int32) error {
+	f.touched = append(f.touched, id)
+	return nil
+}
+
+func TestAuth_RequireUser(t *testing.T) {
+	key, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("GenerateAPIKey() error = %v", err)
+	}
+	store := &fakeAPIKeyStore{keys: map[string]generated.GetAPIKeyUserRow{
+		HashAPIKey(key): {ApiKeyID: 3, UserID: 7, Email: "user@example.com"},
+	}}
+	auth := NewAuth(store, "")
+
+	var gotUser AuthUser
+	handler := auth.RequireUser(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, _ = UserFromContext(r.Context())
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{"missing key", "", "", http.StatusUnauthorized},
+		{"unknown key", APIKeyHeader, "il_unknown", http.StatusUnauthorized},
+		{"api key header", APIKeyHeader, key, http.StatusOK},
+		{"bearer token", "Authorization", "Bearer " + key, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotUser = AuthUser{}
+			req := httptest.NewRequest("GET", "/api/me", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && (gotUser.ID != 7 || gotUser.APIKeyID != 3) {
+				t.Errorf("unexpected user in context: %+v", gotUser)
+			}
+		})
+	}
+
+	if len(store.touched) != 2 {
+		t.Errorf("expected 2 key usages recorded, got %d", len(store.touched))
+	}
+}
+
+func TestAuth_RequireAdmin(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	tests := []struct {
+		name     string
+		adminKey string
+		value    string
+		want     int
+	}{
+		{"disabled", "", "anything", http.StatusForbidden},
+		{"wrong key", "secret", "nope", http.StatusUnauthorized},
+		{"valid key", "secret", "secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/users", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.value)
+			rec := httptest.NewRecorder()
+			NewAuth(&fakeAPIKeyStore{}, tt.adminKey).RequireAdmin(handler)(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -102,3 +102,239 @@ type Response struct {
 	Meta    interface{} `json:"meta,omitempty"`
 	Message string      `json:"message,omitempty"`
 }
+
+// OddsTimelineResponse represents the odds history of a single event
+type OddsTimelineResponse struct {
+	EventSlug string               `json:"event_slug"`
+	Match     string               `json:"match"`
+	League    string               `json:"league"`
+	Sport     string               `json:"sport"`
+	EventTime time.Time            `json:"event_time"`
+	Status    string               `json:"status"`
+	Markets   []OddsTimelineMarket `json:"markets"`
+}
+
+// OddsTimelineMarket groups the outcome series of one market
+type OddsTimelineMarket struct {
+	MarketTypeID int32                 `json:"market_type_id"`
+	MarketCode   string                `json:"market_code"`
+	MarketName   string                `json:"market_name"`
+	MarketParams []string              `json:"market_params"`
+	Outcomes     []OddsTimelineOutcome `json:"outcomes"`
+}
+
+// OddsTimelineOutcome represents the odds series of one outcome
+type OddsTimelineOutcome struct {
+	Outcome          string              `json:"outcome"`
+	OpeningOdds      float64             `json:"opening_odds"`
+	CurrentOdds      float64             `json:"current_odds"`
+	HighestOdds      float64             `json:"highest_odds"`
+	LowestOdds       float64             `json:"lowest_odds"`
+	ChangePercentage float64             `json:"change_percentage"`
+	TotalPoints      int                 `json:"total_points"`
+	Points           []OddsTimelinePoint `json:"points"`
+}
+
+// OddsTimelinePoint represents a single odds_history snapshot
+type OddsTimelinePoint struct {
+	Timestamp        time.Time `json:"timestamp"`
+	OddsValue        float64   `json:"odds_value"`
+	PreviousValue    *float64  `json:"previous_value,omitempty"`
+	ChangePercentage *float64  `json:"change_percentage,omitempty"`
+	MinutesToKickoff *int32    `json:"minutes_to_kickoff,omitempty"`
+}
+
+// OddsCandlesResponse represents the OHLC odds candles of an event at one interval
+type OddsCandlesResponse struct {
+	EventSlug string             `json:"event_slug"`
+	Match     string             `json:"match"`
+	League    string             `json:"league"`
+	Sport     string             `json:"sport"`
+	EventTime time.Time          `json:"event_time"`
+	Status    string             `json:"status"`
+	Bookmaker string             `json:"bookmaker"`
+	Interval  string             `json:"interval"`
+	Markets   []OddsCandleMarket `json:"markets"`
+}
+
+// OddsCandleMarket groups the candle series of one market
+type OddsCandleMarket struct {
+	MarketTypeID int32               `json:"market_type_id"`
+	MarketCode   string              `json:"market_code"`
+	MarketName   string              `json:"market_name"`
+	MarketParams []string            `json:"market_params"`
+	Outcomes     []OddsCandleOutcome `json:"outcomes"`
+}
+
+// OddsCandleOutcome represents the candle series of one outcome, oldest first
+type OddsCandleOutcome struct {
+	Outcome string       `json:"outcome"`
+	Candles []OddsCandle `json:"candles"`
+}
+
+// OddsCandle represents the open, high, low and close odds of one interval
+type OddsCandle struct {
+	Timestamp time.Time `json:"timestamp"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Ticks     int32     `json:"ticks"`
+}
+
+// OddsComparisonResponse compares Iddaa's current prices of an event with other bookmakers
+type OddsComparisonResponse struct {
+	EventSlug string                 `json:"event_slug"`
+	Match     string                 `json:"match"`
+	League    string                 `json:"league"`
+	Sport     string                 `json:"sport"`
+	EventTime time.Time              `json:"event_time"`
+	Status    string                 `json:"status"`
+	Markets   []OddsComparisonMarket `json:"markets"`
+}
+
+// OddsComparisonMarket groups the compared outcomes of one market
+type OddsComparisonMarket struct {
+	MarketTypeID int32                   `json:"market_type_id"`
+	MarketCode   string                  `json:"market_code"`
+	MarketName   string                  `json:"market_name"`
+	MarketParams []string                `json:"market_params"`
+	Outcomes     []OddsComparisonOutcome `json:"outcomes"`
+}
+
+// OddsComparisonOutcome represents every bookmaker's price for one outcome
+type OddsComparisonOutcome struct {
+	Outcome        string                `json:"outcome"`
+	IddaaOdds      float64               `json:"iddaa_odds"`
+	BestOdds       float64               `json:"best_odds"`
+	BestBookmaker  string                `json:"best_bookmaker"`
+	MarketOdds     *float64              `json:"market_odds,omitempty"`
+	BookmakerCount int                   `json:"bookmaker_count"`
+	Deviation      *float64              `json:"deviation_percentage,omitempty"`
+	OutOfLine      bool                  `json:"out_of_line"`
+	Prices         []BookmakerPriceEntry `json:"prices"`
+}
+
+// BookmakerPriceEntry represents one bookmaker's current price
+type BookmakerPriceEntry struct {
+	Bookmaker   string    `json:"bookmaker"`
+	Name        string    `json:"name"`
+	OddsValue   float64   `json:"odds_value"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+// OddsMarginsResponse represents the bookmaker margin and fair probabilities of an event's markets
+type OddsMarginsResponse struct {
+	EventSlug string             `json:"event_slug"`
+	Match     string             `json:"match"`
+	League    string             `json:"league"`
+	Sport     string             `json:"sport"`
+	EventTime time.Time          `json:"event_time"`
+	Status    string             `json:"status"`
+	Bookmaker string             `json:"bookmaker"`
+	Method    string             `json:"method"`
+	Markets   []OddsMarginMarket `json:"markets"`
+}
+
+// OddsMarginMarket represents the margin of one market with its de-vigged outcomes and margin history
+type OddsMarginMarket struct {
+	MarketTypeID     int32                  `json:"market_type_id"`
+	MarketCode       string                 `json:"market_code"`
+	MarketName       string                 `json:"market_name"`
+	MarketParams     []string               `json:"market_params"`
+	OutcomeCount     int32                  `json:"outcome_count"`
+	Booksum          float64                `json:"booksum"`
+	MarginPercentage float32                `json:"margin_percentage"`
+	PowerK           *float64               `json:"power_k,omitempty"`
+	ShinZ            *float64               `json:"shin_z,omitempty"`
+	CalculatedAt     time.Time              `json:"calculated_at"`
+	Outcomes         []FairProbabilityEntry `json:"outcomes"`
+	History          []MarginHistoryPoint   `json:"history"`
+}
+
+// FairProbabilityEntry represents one outcome's probability in percent, raw and under each de-vig method
+type FairProbabilityEntry struct {
+	Outcome            string   `json:"outcome"`
+	OddsValue          float64  `json:"odds_value"`
+	ImpliedProbability float32  `json:"implied_probability"`
+	FairProbability    *float32 `json:"fair_probability,omitempty"`
+	FairOdds           *float64 `json:"fair_odds,omitempty"`
+	Multiplicative     float32  `json:"multiplicative"`
+	Additive           *float32 `json:"additive,omitempty"`
+	Power              *float32 `json:"power,omitempty"`
+	Shin               *float32 `json:"shin,omitempty"`
+}
+
+// MarginHistoryPoint represents a market's margin at one point in time
+type MarginHistoryPoint struct {
+	Timestamp        time.Time `json:"timestamp"`
+	MarginPercentage float32   `json:"margin_percentage"`
+}
+
+// EventDetailResponse represents a single event with all of its markets
+type EventDetailResponse struct {
+	EventResponse
+	VolumeUpdatedAt *time.Time                  `json:"volume_updated_at,omitempty"`
+	Markets         []EventMarketResponse       `json:"markets"`
+	Distributions   []EventDistributionResponse `json:"distributions"`
+	Statistics      []MatchStatisticResponse    `json:"statistics"`
+	MatchEvents     []MatchEventResponse        `json:"match_events"`
+}
+
+// EventMarketResponse represents one market with its current odds
+type EventMarketResponse struct {
+	MarketTypeID int32                  `json:"market_type_id"`
+	MarketCode   string                 `json:"market_code"`
+	MarketName   string                 `json:"market_name"`
+	MarketParams []string               `json:"market_params"`
+	Outcomes     []EventOutcomeResponse `json:"outcomes"`
+}
+
+// EventOutcomeResponse represents the current odds of one outcome
+type EventOutcomeResponse struct {
+	Outcome            string    `json:"outcome"`
+	OddsValue          float64   `json:"odds_value"`
+	OpeningValue       *float64  `json:"opening_value,omitempty"`
+	HighestValue       *float64  `json:"highest_value,omitempty"`
+	LowestValue        *float64  `json:"lowest_value,omitempty"`
+	MovementPercentage *float64  `json:"movement_percentage,omitempty"`
+	LastUpdated        time.Time `json:"last_updated"`
+}
+
+// EventDistributionResponse represents the public betting split for one outcome
+type EventDistributionResponse struct {
+	MarketID           int32     `json:"market_id"`
+	MarketTypeID       *int32    `json:"market_type_id,omitempty"`
+	Outcome            string    `json:"outcome"`
+	BetPercentage      float64   `json:"bet_percentage"`
+	ImpliedProbability *float64  `json:"implied_probability,omitempty"`
+	ValueIndicator     *float64  `json:"value_indicator,omitempty"`
+	LastUpdated        time.Time `json:"last_updated"`
+}
+
+// MatchStatisticResponse represents one team's match statistics
+type MatchStatisticResponse struct {
+	IsHome        bool   `json:"is_home"`
+	Shots         *int32 `json:"shots,omitempty"`
+	ShotsOnTarget *int32 `json:"shots_on_target,omitempty"`
+	Possession    *int32 `json:"possession,omitempty"`
+	Corners       *int32 `json:"corners,omitempty"`
+	YellowCards   *int32 `json:"yellow_cards,omitempty"`
+	RedCards      *int32 `json:"red_cards,omitempty"`
+	Fouls         *int32 `json:"fouls,omitempty"`
+	Offsides      *int32 `json:"offsides,omitempty"`
+	FreeKicks     *int32 `json:"free_kicks,omitempty"`
+	ThrowIns      *int32 `json:"throw_ins,omitempty"`
+	GoalKicks     *int32 `json:"goal_kicks,omitempty"`
+	Saves         *int32 `json:"saves,omitempty"`
+}
+
+// MatchEventResponse represents an in-match event such as a goal or card
+type MatchEventResponse struct {
+	Minute      int32   `json:"minute"`
+	EventType   string  `json:"event_type"`
+	Team        string  `json:"team"`
+	Player      *string `json:"player,omitempty"`
+	Description string  `json:"description"`
+	IsHome      bool    `json:"is_home"`
+}
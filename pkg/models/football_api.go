@@ -126,6 +126,69 @@ type FootballAPITeamData struct {
 	Venue FootballAPIVenue `json:"venue"`
 }
 
+// FootballAPIFixtureData represents an individual fixture from the /fixtures endpoint
+type FootballAPIFixtureData struct {
+	Fixture FootballAPIFixture       `json:"fixture"`
+	League  FootballAPIFixtureLeague `json:"league"`
+	Teams   FootballAPIFixtureTeams  `json:"teams"`
+}
+
+// FootballAPIFixture represents the fixture block of a fixture or odds response
+type FootballAPIFixture struct {
+	ID        int    `json:"id"`
+	Date      string `json:"date"`
+	Timestamp int64  `json:"timestamp"`
+	Status    struct {
+		Short string `json:"short"`
+	} `json:"status"`
+}
+
+// FootballAPIFixtureLeague represents the league a fixture is played in
+type FootballAPIFixtureLeague struct {
+	ID     int `json:"id"`
+	Season int `json:"season"`
+}
+
+// FootballAPIFixtureTeams represents the home and away teams of a fixture
+type FootballAPIFixtureTeams struct {
+	Home FootballAPIFixtureTeam `json:"home"`
+	Away FootballAPIFixtureTeam `json:"away"`
+}
+
+// FootballAPIFixtureTeam represents one side of a fixture
+type FootballAPIFixtureTeam struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// FootballAPIOddsData represents the pre-match odds of one fixture from the /odds endpoint
+type FootballAPIOddsData struct {
+	Fixture    FootballAPIFixture        `json:"fixture"`
+	League     FootballAPIFixtureLeague  `json:"league"`
+	Update     string                    `json:"update"`
+	Bookmakers []FootballAPIBookmakerOdd `json:"bookmakers"`
+}
+
+// FootballAPIBookmakerOdd represents one bookmaker's markets for a fixture
+type FootballAPIBookmakerOdd struct {
+	ID   int              `json:"id"`
+	Name string           `json:"name"`
+	Bets []FootballAPIBet `json:"bets"`
+}
+
+// FootballAPIBet represents a market, e.g. "Match Winner" or "Goals Over/Under"
+type FootballAPIBet struct {
+	ID     int                   `json:"id"`
+	Name   string                `json:"name"`
+	Values []FootballAPIBetValue `json:"values"`
+}
+
+// FootballAPIBetValue represents one outcome price; API-Football sends odds as strings
+type FootballAPIBetValue struct {
+	Value string `json:"value"`
+	Odd   string `json:"odd"`
+}
+
 // LeagueMapping represents the mapping between internal and external leagues
 type LeagueMapping struct {
 	ID                  int       `json:"id" db:"id"`
@@ -48,6 +48,14 @@ type IddaaMarket struct {
 	Outcomes     []IddaaOutcome `json:"o"`
 }
 
+// MarketStatusOpen is the IddaaMarket.Status of a market accepting bets; any other status means suspended
+const MarketStatusOpen = 1
+
+// IsOpen reports whether the market currently accepts bets
+func (m IddaaMarket) IsOpen() bool {
+	return m.Status == MarketStatusOpen
+}
+
 type IddaaOutcome struct {
 	Number      int     `json:"no"`
 	Odds        float64 `json:"odd"`
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -12,13 +13,19 @@ import (
 	"github.com/iddaa-lens/core/internal/config"
 	"github.com/iddaa-lens/core/pkg/database/generated"
 	"github.com/iddaa-lens/core/pkg/database/pool"
+	"github.com/iddaa-lens/core/pkg/handlers/analytics"
 	"github.com/iddaa-lens/core/pkg/handlers/events"
 	"github.com/iddaa-lens/core/pkg/handlers/health"
+	"github.com/iddaa-lens/core/pkg/handlers/jobs"
 	"github.com/iddaa-lens/core/pkg/handlers/leagues"
 	"github.com/iddaa-lens/core/pkg/handlers/odds"
 	"github.com/iddaa-lens/core/pkg/handlers/smart_money"
 	"github.com/iddaa-lens/core/pkg/handlers/sports"
+	"github.com/iddaa-lens/core/pkg/handlers/stream"
 	"github.com/iddaa-lens/core/pkg/handlers/teams"
+	"github.com/iddaa-lens/core/pkg/handlers/users"
+	"github.com/iddaa-lens/core/pkg/handlers/volume"
+	"github.com/iddaa-lens/core/pkg/handlers/webhooks"
 	"github.com/iddaa-lens/core/pkg/logger"
 	"github.com/iddaa-lens/core/pkg/middleware"
 	"github.com/iddaa-lens/core/pkg/services"
@@ -31,6 +38,8 @@ type Server struct {
 	logger   *logger.Logger
 	dbPool   *pgxpool.Pool
 	queries  *generated.Queries
+	cancel   context.CancelFunc
+	auth     *middleware.Auth
 	handlers struct {
 		health     *health.Handler
 		events     *events.Handler
@@ -39,6 +48,12 @@ type Server struct {
 		teams      *teams.Handler
 		leagues    *leagues.Handler
 		smartMoney *smart_money.Handler
+		stream     *stream.Handler
+		webhooks   *webhooks.Handler
+		users      *users.Handler
+		analytics  *analytics.Handler
+		volume     *volume.Handler
+		jobs       *jobs.Handler
 	}
 }
 
@@ -73,6 +88,7 @@ func New(cfg *config.Config, log *logger.Logger) (*Server, error) {
 		logger:  log,
 		dbPool:  dbPool,
 		queries: queries,
+		auth:    middleware.NewAuth(queries, cfg.Server.AdminAPIKey),
 	}
 
 	// Initialize handlers
@@ -82,11 +98,25 @@ func New(cfg *config.Config, log *logger.Logger) (*Server, error) {
 	server.handlers.sports = sports.NewHandler(queries, log)
 	server.handlers.teams = teams.NewHandler(queries, log)
 	server.handlers.leagues = leagues.NewHandler(queries, log)
+	server.handlers.webhooks = webhooks.NewHandler(queries, log)
+	server.handlers.users = users.NewHandler(queries, log)
+	server.handlers.analytics = analytics.NewHandler(queries, log)
+	server.handlers.jobs = jobs.NewHandler(queries, log)
+
+	// The volume service only reads here, so it needs no Iddaa client
+	server.handlers.volume = volume.NewHandler(queries, services.NewVolumeService(queries, nil), log)
 
 	// Initialize smart money tracker service and handler
 	smartMoneyTracker := services.NewSmartMoneyTracker(queries)
 	server.handlers.smartMoney = smart_money.NewHandler(queries, smartMoneyTracker)
 
+	// Bridge database notifications into the live stream
+	listenerCtx, cancel := context.WithCancel(context.Background())
+	server.cancel = cancel
+	broker := services.NewStreamBroker(64)
+	go services.NewNotificationListener(dbPool, broker).Run(listenerCtx)
+	server.handlers.stream = stream.NewHandler(broker, log)
+
 	// Setup routes
 	server.setupRoutes()
 
@@ -117,7 +147,8 @@ func (s *Server) setupRoutes() {
 	s.router.HandleFunc("/api/smart-money/alerts", middleware.CORS(s.handlers.smartMoney.GetAlerts))
 	s.router.HandleFunc("/api/smart-money/value-spots", middleware.CORS(s.handlers.smartMoney.GetValueSpots))
 	s.router.HandleFunc("/api/smart-money/dashboard", middleware.CORS(s.handlers.smartMoney.GetDashboard))
-	s.router.HandleFunc("/api/smart-money/alerts/", middleware.CORS(func(w http.ResponseWriter, r *http.Request) {
+	s.router.HandleFunc("/api/smart-money/performance", middleware.CORS(s.handlers.smartMoney.GetPerformance))
+	s.router.HandleFunc("/api/smart-money/alerts/", middleware.CORS(s.auth.RequireUser(func(w http.ResponseWriter, r *http.Request) {
 		// Handle both /alerts/{id}/view and /alerts/{id}/click
 		if r.Method == "POST" {
 			if r.URL.Path[len(r.URL.Path)-5:] == "/view" {
@@ -130,13 +161,90 @@ func (s *Server) setupRoutes() {
 		} else {
 			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
 		}
-	}))
+	})))
+
+	// Smart money rule administration
+	s.router.HandleFunc("/api/admin/smart-money/rules", middleware.CORS(s.auth.RequireAdmin(s.handlers.smartMoney.Rules)))
+	s.router.HandleFunc("/api/admin/smart-money/rules/", middleware.CORS(s.auth.RequireAdmin(s.handlers.smartMoney.Rule))) // handles /api/admin/smart-money/rules/{id}
+
+	// Cron job run history
+	s.router.HandleFunc("/api/admin/jobs", middleware.CORS(s.auth.RequireAdmin(s.handlers.jobs.List)))
+	s.router.HandleFunc("/api/admin/jobs/", middleware.CORS(s.auth.RequireAdmin(s.handlers.jobs.Route))) // handles /api/admin/jobs/{name}/{runs,triggers,trigger,pause,resume,schedule}
+
+	// User endpoints
+	s.router.HandleFunc("/api/users", middleware.CORS(s.auth.RequireAdmin(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handlers.users.Create(w, r)
+	})))
+	s.router.HandleFunc("/api/me", middleware.CORS(s.auth.RequireUser(s.handlers.users.Me)))
+	s.router.HandleFunc("/api/me/api-keys", middleware.CORS(s.auth.RequireUser(s.handlers.users.APIKeys)))
+	s.router.HandleFunc("/api/me/api-keys/", middleware.CORS(s.auth.RequireUser(s.handlers.users.RevokeAPIKey))) // handles /api/me/api-keys/{id}
+	s.router.HandleFunc("/api/me/preferences", middleware.CORS(s.auth.RequireUser(s.handlers.users.Preferences)))
+	s.router.HandleFunc("/api/me/alerts", middleware.CORS(s.auth.RequireUser(s.handlers.users.Alerts)))
 
 	// Events endpoints
 	s.router.HandleFunc("/api/events", middleware.CORS(s.handlers.events.List))
 	s.router.HandleFunc("/api/events/upcoming", middleware.CORS(s.handlers.events.Upcoming))
 	s.router.HandleFunc("/api/events/daily", middleware.CORS(s.handlers.events.Daily))
 	s.router.HandleFunc("/api/events/live", middleware.CORS(s.handlers.events.Live))
+	s.router.HandleFunc("/api/events/", middleware.CORS(func(w http.ResponseWriter, r *http.Request) {
+		// Handle /api/events/{slug}, /api/events/{slug}/odds/timeline, /api/events/{slug}/odds/compare,
+		// /api/events/{slug}/odds/margins, /api/events/{slug}/candles and /api/events/{slug}/volume-history
+		if r.Method != "GET" {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/odds/timeline") {
+			s.handlers.odds.Timeline(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/odds/compare") {
+			s.handlers.odds.Compare(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/odds/margins") {
+			s.handlers.odds.Margins(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/candles") {
+			s.handlers.odds.Candles(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/volume-history") {
+			s.handlers.volume.EventHistory(w, r)
+		} else if !strings.Contains(strings.Trim(r.URL.Path[len("/api/events/"):], "/"), "/") {
+			s.handlers.events.Detail(w, r)
+		} else {
+			http.Error(w, "Not Found", http.StatusNotFound)
+		}
+	}))
+
+	// Analytics endpoints
+	s.router.HandleFunc("/api/analytics/best-prices", middleware.CORS(s.handlers.analytics.BestPrices))
+	s.router.HandleFunc("/api/analytics/clv", middleware.CORS(s.handlers.analytics.CLV))
+	s.router.HandleFunc("/api/analytics/contrarian-bets", middleware.CORS(s.handlers.analytics.ContrarianBets))
+	s.router.HandleFunc("/api/analytics/margins", middleware.CORS(s.handlers.analytics.Margins))
+	s.router.HandleFunc("/api/analytics/live-opportunities", middleware.CORS(s.handlers.analytics.LiveOpportunities))
+	s.router.HandleFunc("/api/analytics/high-volume-events", middleware.CORS(s.handlers.analytics.HighVolumeEvents))
+	s.router.HandleFunc("/api/analytics/suspicious-movements", middleware.CORS(s.handlers.analytics.SuspiciousMovements))
+	s.router.HandleFunc("/api/analytics/top-volume-events", middleware.CORS(s.handlers.analytics.TopVolumeEvents))
+	s.router.HandleFunc("/api/analytics/volume-history", middleware.CORS(s.handlers.analytics.VolumeHistory))
+	s.router.HandleFunc("/api/analytics/volume-patterns", middleware.CORS(s.handlers.analytics.VolumePatterns))
+
+	// Volume endpoints
+	s.router.HandleFunc("/api/volume/hot-movers", middleware.CORS(s.handlers.volume.HotMovers))
+	s.router.HandleFunc("/api/volume/hidden-gems", middleware.CORS(s.handlers.volume.HiddenGems))
+
+	// Live stream endpoint
+	s.router.HandleFunc("/api/stream", middleware.CORS(s.handlers.stream.Stream))
+
+	// Webhook endpoints, subscriptions belong to the user of the API key
+	s.router.HandleFunc("/api/webhooks", middleware.CORS(s.auth.RequireUser(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			s.handlers.webhooks.List(w, r)
+		case "POST":
+			s.handlers.webhooks.Create(w, r)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+	s.router.HandleFunc("/api/webhooks/", middleware.CORS(s.auth.RequireUser(s.handlers.webhooks.Route))) // handles /api/webhooks/{id}[/deliveries|/dead-letters]
 
 	// Sports endpoints
 	s.router.HandleFunc("/api/sports", middleware.CORS(s.handlers.sports.List))
@@ -166,6 +274,9 @@ func (s *Server) Start() error {
 
 // Close gracefully shuts down the server and closes database connections
 func (s *Server) Close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
 	if s.dbPool != nil {
 		s.dbPool.Close()
 		s.logger.Info().Msg("Database connection pool closed")
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+const (
+	// alertGradeWindowDays is how recently an outcome must have settled or closed for its alerts to
+	// be graded, wide enough to cover settlement runs that were missed
+	alertGradeWindowDays = 7
+	// alertGradeLookbackDays is how long before grading an alert may have fired
+	alertGradeLookbackDays = 45
+)
+
+// GradeAlert returns the profit of a 1 unit stake on an alert at the alert price and, when the
+// outcome has a closing line, at the closing price. Void and push outcomes return the stake.
+func GradeAlert(result SettlementResult, alertOdds float64, closingOdds *float64) (float64, *float64) {
+	profitAtAlert := stakeProfit(result, alertOdds)
+	if closingOdds == nil {
+		return profitAtAlert, nil
+	}
+	profitAtClose := stakeProfit(result, *closingOdds)
+	return profitAtAlert, &profitAtClose
+}
+
+func stakeProfit(result SettlementResult, odds float64) float64 {
+	switch result {
+	case SettlementWon:
+		return odds - 1
+	case SettlementLost:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// AlertPerformance is the record of a group of graded alerts, for a 1 unit stake per alert
+type AlertPerformance struct {
+	Alerts          int
+	Won             int
+	Lost            int
+	Refunded        int
+	ProfitAtAlert   float64
+	WithClosingOdds int
+	ProfitAtClose   float64
+}
+
+// HitRate is the percentage of decided alerts that won, void and push outcomes are left out
+func (p AlertPerformance) HitRate() float64 {
+	if decided := p.Won + p.Lost; decided > 0 {
+		return float64(p.Won) / float64(decided) * 100
+	}
+	return 0
+}
+
+// ROIAtAlert is the return in percent of staking every alert at its alert price
+func (p AlertPerformance) ROIAtAlert() float64 {
+	if p.Alerts > 0 {
+		return p.ProfitAtAlert / float64(p.Alerts) * 100
+	}
+	return 0
+}
+
+// ROIAtClose is the return in percent of staking the alerts that have a closing line at that line
+func (p AlertPerformance) ROIAtClose() float64 {
+	if p.WithClosingOdds > 0 {
+		return p.ProfitAtClose / float64(p.WithClosingOdds) * 100
+	}
+	return 0
+}
+
+// GradeMovementAlerts grades the alerts of outcomes settled or closed recently, re-grading after
+// settlement or closing line changes. It returns the number of alerts graded.
+func (s *SettlementService) GradeMovementAlerts(ctx context.Context) (int64, error) {
+	rows, err := s.db.GetGradableMovementAlerts(ctx, generated.GetGradableMovementAlertsParams{
+		WindowDays:   alertGradeWindowDays,
+		LookbackDays: alertGradeLookbackDays,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get gradable alerts: %w", err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	var params generated.UpsertAlertResultsParams
+	graded := make(map[int32]bool, len(rows))
+	for _, row := range rows {
+		// An alert matching several settlements is graded once, an upsert cannot touch a row twice
+		if graded[row.AlertID] {
+			continue
+		}
+		graded[row.AlertID] = true
+
+		profitAtAlert, profitAtClose := GradeAlert(SettlementResult(row.Result), row.AlertOdds, row.ClosingOdds)
+		params.AlertIds = append(params.AlertIds, row.AlertID)
+		params.EventIds = append(params.EventIds, row.EventID)
+		params.SettlementIds = append(params.SettlementIds, row.SettlementID)
+		params.Results = append(params.Results, row.Result)
+		params.AlertOdds = append(params.AlertOdds, row.AlertOdds)
+		params.ClosingOdds = append(params.ClosingOdds, row.ClosingOdds)
+		params.ProfitsAtAlert = append(params.ProfitsAtAlert, profitAtAlert)
+		params.ProfitsAtClose = append(params.ProfitsAtClose, profitAtClose)
+	}
+
+	stored, err := s.db.UpsertAlertResults(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to store alert results: %w", err)
+	}
+	return stored, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGradeAlert(t *testing.T) {
+	tests := []struct {
+		name        string
+		result      SettlementResult
+		closingOdds *float64
+		wantAlert   float64
+		wantClose   *float64
+	}{
+		{"won", SettlementWon, f64(2.1), 1.5, f64(1.1)},
+		{"lost", SettlementLost, f64(2.1), -1, f64(-1)},
+		{"void", SettlementVoid, f64(2.1), 0, f64(0)},
+		{"push", SettlementPush, nil, 0, nil},
+		{"won without a closing line", SettlementWon, nil, 1.5, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atAlert, atClose := GradeAlert(tt.result, 2.5, tt.closingOdds)
+			if math.Abs(atAlert-tt.wantAlert) > 1e-9 {
+				t.Errorf("profit at alert = %v, want %v", atAlert, tt.wantAlert)
+			}
+			switch {
+			case tt.wantClose == nil && atClose != nil:
+				t.Errorf("profit at close = %v, want nil", *atClose)
+			case tt.wantClose != nil && (atClose == nil || math.Abs(*atClose-*tt.wantClose) > 1e-9):
+				t.Errorf("profit at close = %v, want %v", atClose, *tt.wantClose)
+			}
+		})
+	}
+}
+
+func TestAlertPerformance_Rollup(t *testing.T) {
+	alerts := []struct {
+		result      SettlementResult
+		alertOdds   float64
+		closingOdds *float64
+	}{
+		{SettlementWon, 2.0, f64(1.8)},
+		{SettlementWon, 3.0, nil},
+		{SettlementLost, 1.5, f64(1.6)},
+		{SettlementVoid, 2.2, f64(2.2)},
+	}
+
+	// Rolled up the way GetAlertPerformance sums alert_results
+	var performance AlertPerformance
+	for _, alert := range alerts {
+		atAlert, atClose := GradeAlert(alert.result, alert.alertOdds, alert.closingOdds)
+		performance.Alerts++
+		switch alert.result {
+		case SettlementWon:
+			performance.Won++
+		case SettlementLost:
+			performance.Lost++
+		default:
+			performance.Refunded++
+		}
+		performance.ProfitAtAlert += atAlert
+		if atClose != nil {
+			performance.WithClosingOdds++
+			performance.ProfitAtClose += *atClose
+		}
+	}
+
+	// 2 of 3 decided alerts won, the void one is left out
+	if got := performance.HitRate(); math.Abs(got-200.0/3) > 1e-9 {
+		t.Errorf("HitRate() = %v, want 66.67", got)
+	}
+	// (1 + 2 - 1 + 0) / 4 alerts
+	if got := performance.ROIAtAlert(); math.Abs(got-50) > 1e-9 {
+		t.Errorf("ROIAtAlert() = %v, want 50", got)
+	}
+	// (0.8 - 1 + 0) / 3 alerts with a closing line
+	if got := performance.ROIAtClose(); math.Abs(got-(-20.0/3)) > 1e-9 {
+		t.Errorf("ROIAtClose() = %v, want -6.67", got)
+	}
+
+	var empty AlertPerformance
+	if empty.HitRate() != 0 || empty.ROIAtAlert() != 0 || empty.ROIAtClose() != 0 {
+		t.Error("an empty group should report zero rates")
+	}
+}
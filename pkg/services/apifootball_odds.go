@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gosimple/slug"
+
+	"github.com/iddaa-lens/core/pkg/apifootball"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+// APIFootballOddsProvider reads pre-match prices of the bookmakers API-Football aggregates
+type APIFootballOddsProvider struct {
+	client *apifootball.Client
+	logger *logger.Logger
+}
+
+// NewAPIFootballOddsProvider creates an odds provider backed by the API-Football client
+func NewAPIFootballOddsProvider(client *apifootball.Client) *APIFootballOddsProvider {
+	return &APIFootballOddsProvider{
+		client: client,
+		logger: logger.New("apifootball-odds"),
+	}
+}
+
+// Name returns the provider name stored with its bookmakers
+func (p *APIFootballOddsProvider) Name() string {
+	return "api-football"
+}
+
+// FetchOdds finds each event's fixture by date and team ids, then loads the fixture's odds.
+// Fixtures are listed once per kick-off day, so a day's events cost one request plus one per matched fixture.
+func (p *APIFootballOddsProvider) FetchOdds(ctx context.Context, events []ProviderEvent) ([]ProviderQuote, error) {
+	byDay := make(map[string][]ProviderEvent)
+	var days []string
+	for _, event := range events {
+		day := event.EventDate.UTC().Format("2006-01-02")
+		if _, ok := byDay[day]; !ok {
+			days = append(days, day)
+		}
+		byDay[day] = append(byDay[day], event)
+	}
+
+	var quotes []ProviderQuote
+	for _, day := range days {
+		dayEvents := byDay[day]
+
+		fixtures, err := p.client.GetFixturesByDate(ctx, dayEvents[0].EventDate)
+		if err != nil {
+			if isRateLimit(err) || ctx.Err() != nil {
+				return quotes, err
+			}
+			p.logger.Warn().Err(err).Str("date", day).Msg("Failed to get fixtures")
+			continue
+		}
+
+		fixtureIDs := make(map[[2]int]int, len(fixtures))
+		for _, fixture := range fixtures {
+			fixtureIDs[[2]int{fixture.Teams.Home.ID, fixture.Teams.Away.ID}] = fixture.Fixture.ID
+		}
+
+		for _, event := range dayEvents {
+			fixtureID, ok := fixtureIDs[[2]int{event.APIFootballHomeTeamID, event.APIFootballAwayTeamID}]
+			if !ok {
+				continue
+			}
+
+			odds, err := p.client.GetOddsByFixture(ctx, fixtureID)
+			if err != nil {
+				if isRateLimit(err) || ctx.Err() != nil {
+					return quotes, err
+				}
+				p.logger.Warn().
+					Err(err).
+					Int32("event_id", event.EventID).
+					Int("fixture_id", fixtureID).
+					Msg("Failed to get fixture odds")
+				continue
+			}
+			if odds == nil {
+				continue
+			}
+
+			quotes = append(quotes, apiFootballQuotes(event.EventID, odds)...)
+		}
+	}
+
+	return quotes, nil
+}
+
+func isRateLimit(err error) bool {
+	var rateLimitErr *apifootball.RateLimitError
+	return errors.As(err, &rateLimitErr)
+}
+
+// apiFootballQuotes converts a fixture's odds into quotes, skipping bets that have no Iddaa equivalent
+func apiFootballQuotes(eventID int32, odds *models.FootballAPIOddsData) []ProviderQuote {
+	var quotes []ProviderQuote
+	for _, book := range odds.Bookmakers {
+		code := slug.Make(book.Name)
+		if code == "" || code == IddaaBookmaker {
+			continue
+		}
+
+		for _, bet := range book.Bets {
+			for _, value := range bet.Values {
+				selection, ok := apiFootballSelection(bet.Name, value.Value)
+				if !ok {
+					continue
+				}
+				price, err := strconv.ParseFloat(strings.TrimSpace(value.Odd), 64)
+				if err != nil || price <= 1 {
+					continue
+				}
+
+				quotes = append(quotes, ProviderQuote{
+					EventID:             eventID,
+					Bookmaker:           code,
+					BookmakerName:       book.Name,
+					ProviderBookmakerID: book.ID,
+					Selection:           selection,
+					Odds:                price,
+				})
+			}
+		}
+	}
+	return quotes
+}
+
+// apiFootballSelection maps an API-Football bet and value, e.g. "Goals Over/Under" and "Over 2.5"
+func apiFootballSelection(bet, value string) (Selection, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	switch bet {
+	case "Match Winner", "First Half Winner":
+		result, ok := sideResult(value)
+		if !ok {
+			return Selection{}, false
+		}
+		return Selection{
+			Family:    MarketFamily1X2,
+			FirstHalf: bet == "First Half Winner",
+			Pick:      resultPick(result),
+		}, true
+
+	case "Double Chance":
+		parts := strings.Split(value, "/")
+		if len(parts) != 2 {
+			return Selection{}, false
+		}
+		first, ok1 := sideResult(parts[0])
+		second, ok2 := sideResult(parts[1])
+		if !ok1 || !ok2 || first == second {
+			return Selection{}, false
+		}
+		return Selection{Family: MarketFamilyDoubleChance, Pick: doubleChancePick(first, second)}, true
+
+	case "Goals Over/Under", "Goals Over/Under First Half":
+		fields := strings.Fields(value)
+		if len(fields) != 2 || (fields[0] != "over" && fields[0] != "under") {
+			return Selection{}, false
+		}
+		line, ok := parseLine(fields[1])
+		if !ok {
+			return Selection{}, false
+		}
+		return Selection{
+			Family:    MarketFamilyOverUnder,
+			FirstHalf: bet == "Goals Over/Under First Half",
+			Pick:      fields[0],
+			Line:      line,
+		}, true
+
+	case "Both Teams Score":
+		if value != "yes" && value != "no" {
+			return Selection{}, false
+		}
+		return Selection{Family: MarketFamilyBTTS, Pick: value}, true
+
+	case "HT/FT Double":
+		parts := strings.Split(value, "/")
+		if len(parts) != 2 {
+			return Selection{}, false
+		}
+		ht, ok1 := sideResult(parts[0])
+		ft, ok2 := sideResult(parts[1])
+		if !ok1 || !ok2 {
+			return Selection{}, false
+		}
+		return Selection{Family: MarketFamilyHTFT, Pick: fmt.Sprintf("%s/%s", resultPick(ht), resultPick(ft))}, true
+
+	case "Exact Score":
+		pick, ok := scorePick(value)
+		if !ok {
+			return Selection{}, false
+		}
+		return Selection{Family: MarketFamilyCorrectScore, Pick: pick}, true
+	}
+
+	return Selection{}, false
+}
+
+// sideResult maps API-Football's "Home", "Draw" and "Away" to match results
+func sideResult(value string) (int, bool) {
+	switch strings.TrimSpace(value) {
+	case "home", "1":
+		return 1, true
+	case "draw", "x":
+		return 0, true
+	case "away", "2":
+		return 2, true
+	}
+	return 0, false
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+)
+
+const (
+	// candleBackfill is how far back the first rollup reads odds_history when no candles exist yet
+	candleBackfill = 7 * 24 * time.Hour
+	// candleChunk bounds the odds_history window a single 5-minute rollup statement reads
+	candleChunk = 6 * time.Hour
+)
+
+// CandleService rolls odds_history up into OHLC candles at 5-minute, hourly and daily resolution
+type CandleService struct {
+	db     *generated.Queries
+	logger *logger.Logger
+}
+
+// NewCandleService creates a new candle service
+func NewCandleService(db *generated.Queries) *CandleService {
+	return &CandleService{
+		db:     db,
+		logger: logger.New("candle-service"),
+	}
+}
+
+// CandleStats summarizes a candle rollup
+type CandleStats struct {
+	// Since is the time the rollup resumed from
+	Since      time.Time
+	FiveMinute int64
+	Hourly     int64
+	Daily      int64
+	// Deleted counts the candles a range rebuild removed first, at all resolutions
+	Deleted int64
+}
+
+// Rollup rebuilds every candle from the newest 5-minute candle up to now.
+// The newest candle may still have been filling, so it is rebuilt with the rest; hourly and
+// daily candles are rebuilt from the finer candles of their period, which keeps them exact.
+// Rows written behind the newest candle, by cmd/reprocess, are rolled up by RebuildRange.
+func (s *CandleService) Rollup(ctx context.Context, now time.Time) (CandleStats, error) {
+	latest, err := s.db.GetLatestOddsCandle(ctx)
+	if err != nil {
+		return CandleStats{}, fmt.Errorf("failed to get latest candle: %w", err)
+	}
+
+	stats := CandleStats{Since: now.Add(-candleBackfill)}
+	if latest.Valid {
+		stats.Since = latest.Time
+	}
+
+	if stats.FiveMinute, err = s.rollup5m(ctx, stats.Since, now); err != nil {
+		return stats, err
+	}
+
+	since := pgtype.Timestamp{Time: stats.Since, Valid: true}
+	until := pgtype.Timestamp{Time: now, Valid: true}
+
+	stats.Hourly, err = s.db.RollupOddsCandles1h(ctx, generated.RollupOddsCandles1hParams{SinceTime: since, UntilTime: until})
+	if err != nil {
+		return stats, fmt.Errorf("failed to roll up hourly candles: %w", err)
+	}
+
+	stats.Daily, err = s.db.RollupOddsCandles1d(ctx, generated.RollupOddsCandles1dParams{SinceTime: since, UntilTime: until})
+	if err != nil {
+		return stats, fmt.Errorf("failed to roll up daily candles: %w", err)
+	}
+
+	return stats, nil
+}
+
+// RebuildRange deletes and rebuilds the candles of odds_history rows recorded from from up to to,
+// for history rewritten behind the newest candle. The range is widened to whole buckets at each
+// resolution, so buckets whose rows were deleted go away and partially covered ones stay exact.
+// Candles of the range are missing while it runs.
+func (s *CandleService) RebuildRange(ctx context.Context, from, to time.Time) (CandleStats, error) {
+	stats := CandleStats{Since: from}
+
+	fiveFrom, fiveTo := candleRange(from, to, 5*time.Minute)
+	deleted, err := s.db.DeleteOddsCandles5mRange(ctx, generated.DeleteOddsCandles5mRangeParams{FromTime: fiveFrom, ToTime: fiveTo})
+	if err != nil {
+		return stats, fmt.Errorf("failed to delete 5-minute candles: %w", err)
+	}
+	stats.Deleted += deleted
+	if stats.FiveMinute, err = s.rollup5m(ctx, fiveFrom.Time, fiveTo.Time); err != nil {
+		return stats, err
+	}
+
+	// Hourly and daily candles are rebuilt from the finer candles of the whole period
+	hourFrom, hourTo := candleRange(from, to, time.Hour)
+	deleted, err = s.db.DeleteOddsCandles1hRange(ctx, generated.DeleteOddsCandles1hRangeParams{FromTime: hourFrom, ToTime: hourTo})
+	if err != nil {
+		return stats, fmt.Errorf("failed to delete hourly candles: %w", err)
+	}
+	stats.Deleted += deleted
+	stats.Hourly, err = s.db.RollupOddsCandles1h(ctx, generated.RollupOddsCandles1hParams{SinceTime: hourFrom, UntilTime: hourTo})
+	if err != nil {
+		return stats, fmt.Errorf("failed to roll up hourly candles: %w", err)
+	}
+
+	dayFrom, dayTo := candleRange(from, to, 24*time.Hour)
+	deleted, err = s.db.DeleteOddsCandles1dRange(ctx, generated.DeleteOddsCandles1dRangeParams{FromTime: dayFrom, ToTime: dayTo})
+	if err != nil {
+		return stats, fmt.Errorf("failed to delete daily candles: %w", err)
+	}
+	stats.Deleted += deleted
+	stats.Daily, err = s.db.RollupOddsCandles1d(ctx, generated.RollupOddsCandles1dParams{SinceTime: dayFrom, UntilTime: dayTo})
+	if err != nil {
+		return stats, fmt.Errorf("failed to roll up daily candles: %w", err)
+	}
+
+	return stats, nil
+}
+
+// rollup5m rebuilds the 5-minute candles of rows recorded from the bucket of since up to until,
+// one chunk at a time. Chunks may split a bucket, the next chunk rebuilds it from the bucket start.
+func (s *CandleService) rollup5m(ctx context.Context, since, until time.Time) (int64, error) {
+	var total int64
+	for from := since; from.Before(until); from = from.Add(candleChunk) {
+		chunkEnd := from.Add(candleChunk)
+		if chunkEnd.After(until) {
+			chunkEnd = until
+		}
+
+		rows, err := s.db.RollupOddsCandles5m(ctx, generated.RollupOddsCandles5mParams{
+			SinceTime: pgtype.Timestamp{Time: from, Valid: true},
+			UntilTime: pgtype.Timestamp{Time: chunkEnd, Valid: true},
+		})
+		if err != nil {
+			return total, fmt.Errorf("failed to roll up 5-minute candles from %s: %w", from.Format(time.RFC3339), err)
+		}
+		total += rows
+
+		s.logger.Debug().
+			Time("from", from).
+			Time("until", chunkEnd).
+			Int64("candles", rows).
+			Msg("Rolled up 5-minute candles")
+	}
+	return total, nil
+}
+
+// candleRange widens from and to to whole buckets of the given size. Times are UTC, so day
+// buckets start at midnight like date_trunc('day').
+func candleRange(from, to time.Time, bucket time.Duration) (pgtype.Timestamp, pgtype.Timestamp) {
+	end := to.Truncate(bucket)
+	if end.Before(to) {
+		end = end.Add(bucket)
+	}
+	return pgtype.Timestamp{Time: from.Truncate(bucket), Valid: true}, pgtype.Timestamp{Time: end, Valid: true}
+}
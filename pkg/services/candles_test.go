@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCandleRange(t *testing.T) {
+	from := time.Date(2025, 3, 1, 10, 7, 30, 0, time.UTC)
+	to := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		bucket   time.Duration
+		wantFrom time.Time
+		wantTo   time.Time
+	}{
+		{5 * time.Minute, time.Date(2025, 3, 1, 10, 5, 0, 0, time.UTC), to},
+		{time.Hour, time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC), to},
+		{24 * time.Hour, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), to},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bucket.String(), func(t *testing.T) {
+			gotFrom, gotTo := candleRange(from, to, tt.bucket)
+			if !gotFrom.Time.Equal(tt.wantFrom) || !gotTo.Time.Equal(tt.wantTo) {
+				t.Errorf("candleRange() = %v - %v, want %v - %v", gotFrom.Time, gotTo.Time, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+
+	// An end inside a bucket is rounded up to cover the whole bucket
+	_, gotTo := candleRange(from, time.Date(2025, 3, 1, 10, 7, 31, 0, time.UTC), 5*time.Minute)
+	if want := time.Date(2025, 3, 1, 10, 10, 0, 0, time.UTC); !gotTo.Time.Equal(want) {
+		t.Errorf("end = %v, want %v", gotTo.Time, want)
+	}
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
@@ -9,6 +11,7 @@ import (
 	"time"
 
 	"github.com/iddaa-lens/core/internal/config"
+	"github.com/iddaa-lens/core/pkg/archive"
 	"github.com/iddaa-lens/core/pkg/logger"
 	"github.com/iddaa-lens/core/pkg/models"
 )
@@ -17,16 +20,86 @@ type IddaaClient struct {
 	baseURL string
 	client  *http.Client
 	logger  *logger.Logger
+	// Stores every successful payload for reprocessing, nil when archiving is off
+	archive *archive.Archive
+}
+
+// IddaaTransport changes where the client's requests go, cmd/cron sets it to replay or record
+// Iddaa with the iddaamock transports
+type IddaaTransport struct {
+	// Upstream sends requests once retries and rate budgets allowed them, http.DefaultTransport when nil
+	Upstream http.RoundTripper
+	// Wrap is applied around the retries and rate budgets, so it sees every request once and with its
+	// original URL
+	Wrap func(http.RoundTripper) http.RoundTripper
 }
 
 func NewIddaaClient(cfg *config.Config) *IddaaClient {
-	return &IddaaClient{
+	return NewIddaaClientWithTransport(cfg, IddaaTransport{})
+}
+
+// NewIddaaClientWithTransport creates a client sending its requests through transport
+func NewIddaaClientWithTransport(cfg *config.Config, iddaaTransport IddaaTransport) *IddaaClient {
+	log := logger.New("iddaa-client")
+
+	transport := iddaaTransport.Upstream
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	transport = NewResilientTransport(resilienceConfig(cfg.Iddaa), transport)
+	if iddaaTransport.Wrap != nil {
+		transport = iddaaTransport.Wrap(transport)
+	}
+
+	client := &IddaaClient{
 		baseURL: "https://sportsbookv2.iddaa.com",
 		client: &http.Client{
-			Timeout: time.Duration(cfg.External.Timeout) * time.Second,
+			Timeout:   time.Duration(cfg.External.Timeout) * time.Second,
+			Transport: transport,
 		},
-		logger: logger.New("iddaa-client"),
+		logger: log,
+	}
+
+	if cfg.Archive.Dir != "" {
+		payloads, err := archive.Shared(cfg.Archive.Dir)
+		if err != nil {
+			log.Error().Err(err).Str("archive_dir", cfg.Archive.Dir).Msg("Payload archive disabled")
+		} else {
+			client.archive = payloads
+		}
+	}
+
+	return client
+}
+
+// resilienceConfig applies the configured overrides to DefaultResilienceConfig
+func resilienceConfig(cfg config.IddaaConfig) ResilienceConfig {
+	resilience := DefaultResilienceConfig()
+	switch {
+	case cfg.MaxRetries < 0:
+		resilience.MaxRetries = 0
+	case cfg.MaxRetries > 0:
+		resilience.MaxRetries = cfg.MaxRetries
+	}
+
+	for host, rps := range map[string]int{
+		"sportsbookv2.iddaa.com": cfg.SportsbookRPS,
+		"contentv2.iddaa.com":    cfg.ContentRPS,
+		"statisticsv2.iddaa.com": cfg.StatisticsRPS,
+	} {
+		if rps > 0 {
+			// Allow two seconds of requests in a burst
+			resilience.Budgets[host] = HostBudget{Rate: float64(rps), Burst: 2 * rps}
+		}
+	}
+
+	if cfg.BreakerThreshold > 0 {
+		resilience.FailureThreshold = cfg.BreakerThreshold
+	}
+	if cfg.BreakerCooldown > 0 {
+		resilience.Cooldown = time.Duration(cfg.BreakerCooldown) * time.Second
 	}
+	return resilience
 }
 
 // generateClientTransactionID creates a unique transaction ID like the real site
@@ -79,11 +152,11 @@ func (c *IddaaClient) addBrowserHeaders(req *http.Request) {
 	}
 }
 
-// makeRequest creates a request with browser headers
-func (c *IddaaClient) makeRequest(url string) (*http.Response, error) {
+// makeRequest creates a request with browser headers, bound to ctx so a cancelled job stops waiting on Iddaa
+func (c *IddaaClient) makeRequest(ctx context.Context, url string) (*http.Response, error) {
 	start := time.Now()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -104,13 +177,35 @@ func (c *IddaaClient) makeRequest(url string) (*http.Response, error) {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 
+	if c.archive != nil && resp.StatusCode == http.StatusOK {
+		if err := c.archiveResponse(url, resp); err != nil {
+			return nil, err
+		}
+	}
+
 	return resp, nil
 }
 
-func (c *IddaaClient) GetCompetitions() (*models.IddaaAPIResponse[models.IddaaCompetition], error) {
+// archiveResponse stores a successful payload in the archive, leaving the body readable for the caller.
+// Archive failures are logged and never fail the request.
+func (c *IddaaClient) archiveResponse(url string, resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if _, err := c.archive.Put(archive.SourceIddaa, url, resp.StatusCode, body, time.Now()); err != nil {
+		c.logger.Warn().Err(err).Str("url", url).Msg("Failed to archive payload")
+	}
+	return nil
+}
+
+func (c *IddaaClient) GetCompetitions(ctx context.Context) (*models.IddaaAPIResponse[models.IddaaCompetition], error) {
 	url := fmt.Sprintf("%s/sportsbook/competitions", c.baseURL)
 
-	resp, err := c.makeRequest(url)
+	resp, err := c.makeRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -133,10 +228,16 @@ func (c *IddaaClient) GetCompetitions() (*models.IddaaAPIResponse[models.IddaaCo
 }
 
 // GetEvents fetches all events for a specific sport (live + upcoming)
-func (c *IddaaClient) GetEvents(sportID int) (*models.IddaaEventsResponse, error) {
-	url := fmt.Sprintf("%s/sportsbook/events?st=%d&type=0&version=0", c.baseURL, sportID)
+func (c *IddaaClient) GetEvents(ctx context.Context, sportID int) (*models.IddaaEventsResponse, error) {
+	return c.GetEventsSince(ctx, sportID, 0)
+}
+
+// GetEventsSince fetches the events that changed after the given bulletin version.
+// Version 0 returns the full bulletin; otherwise the response is a diff when Data.IsDiff is set.
+func (c *IddaaClient) GetEventsSince(ctx context.Context, sportID int, version int64) (*models.IddaaEventsResponse, error) {
+	url := fmt.Sprintf("%s/sportsbook/events?st=%d&type=0&version=%d", c.baseURL, sportID, version)
 
-	resp, err := c.makeRequest(url)
+	resp, err := c.makeRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -159,10 +260,10 @@ func (c *IddaaClient) GetEvents(sportID int) (*models.IddaaEventsResponse, error
 }
 
 // GetLiveEvents fetches only live events for a specific sport
-func (c *IddaaClient) GetLiveEvents(sportID int) (*models.IddaaEventsResponse, error) {
+func (c *IddaaClient) GetLiveEvents(ctx context.Context, sportID int) (*models.IddaaEventsResponse, error) {
 	url := fmt.Sprintf("%s/sportsbook/events?st=%d&type=1&version=0", c.baseURL, sportID)
 
-	resp, err := c.makeRequest(url)
+	resp, err := c.makeRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -185,10 +286,10 @@ func (c *IddaaClient) GetLiveEvents(sportID int) (*models.IddaaEventsResponse, e
 }
 
 // GetEventsByCompetition fetches events for a specific competition (legacy method)
-func (c *IddaaClient) GetEventsByCompetition(competitionID int) (*models.IddaaAPIResponse[models.IddaaEvent], error) {
+func (c *IddaaClient) GetEventsByCompetition(ctx context.Context, competitionID int) (*models.IddaaAPIResponse[models.IddaaEvent], error) {
 	url := fmt.Sprintf("%s/sportsbook/competitions/%d/events", c.baseURL, competitionID)
 
-	resp, err := c.makeRequest(url)
+	resp, err := c.makeRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -210,10 +311,10 @@ func (c *IddaaClient) GetEventsByCompetition(competitionID int) (*models.IddaaAP
 	return &result, nil
 }
 
-func (c *IddaaClient) GetOdds(eventID int) (*models.IddaaAPIResponse[models.IddaaOdds], error) {
+func (c *IddaaClient) GetOdds(ctx context.Context, eventID int) (*models.IddaaAPIResponse[models.IddaaOdds], error) {
 	url := fmt.Sprintf("%s/sportsbook/events/%d/odds", c.baseURL, eventID)
 
-	resp, err := c.makeRequest(url)
+	resp, err := c.makeRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -235,10 +336,10 @@ func (c *IddaaClient) GetOdds(eventID int) (*models.IddaaAPIResponse[models.Idda
 	return &result, nil
 }
 
-func (c *IddaaClient) GetAppConfig(platform string) (*models.IddaaConfigResponse, error) {
+func (c *IddaaClient) GetAppConfig(ctx context.Context, platform string) (*models.IddaaConfigResponse, error) {
 	url := fmt.Sprintf("https://contentv2.iddaa.com/appconfig?platform=%s", platform)
 
-	resp, err := c.makeRequest(url)
+	resp, err := c.makeRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -260,10 +361,10 @@ func (c *IddaaClient) GetAppConfig(platform string) (*models.IddaaConfigResponse
 	return &result, nil
 }
 
-func (c *IddaaClient) GetSportInfo() (*models.IddaaAPIResponse[models.IddaaSportInfo], error) {
+func (c *IddaaClient) GetSportInfo(ctx context.Context) (*models.IddaaAPIResponse[models.IddaaSportInfo], error) {
 	url := fmt.Sprintf("%s/sportsbook/info", c.baseURL)
 
-	resp, err := c.makeRequest(url)
+	resp, err := c.makeRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -285,10 +386,10 @@ func (c *IddaaClient) GetSportInfo() (*models.IddaaAPIResponse[models.IddaaSport
 	return &result, nil
 }
 
-func (c *IddaaClient) GetMarketConfig() (*models.IddaaMarketConfigResponse, error) {
+func (c *IddaaClient) GetMarketConfig(ctx context.Context) (*models.IddaaMarketConfigResponse, error) {
 	url := fmt.Sprintf("%s/sportsbook/get_market_config", c.baseURL)
 
-	resp, err := c.makeRequest(url)
+	resp, err := c.makeRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -310,10 +411,10 @@ func (c *IddaaClient) GetMarketConfig() (*models.IddaaMarketConfigResponse, erro
 	return &result, nil
 }
 
-func (c *IddaaClient) GetEventStatistics(sportID int, searchDate string) ([]models.IddaaEventStatistics, error) {
+func (c *IddaaClient) GetEventStatistics(ctx context.Context, sportID int, searchDate string) ([]models.IddaaEventStatistics, error) {
 	url := fmt.Sprintf("https://statisticsv2.iddaa.com/broadage/getEventListCache?SportId=%d&SearchDate=%s", sportID, searchDate)
 
-	resp, err := c.makeRequest(url)
+	resp, err := c.makeRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -403,10 +504,10 @@ func min(a, b int) int {
 	return b
 }
 
-func (c *IddaaClient) GetSingleEvent(eventID int) (*models.IddaaSingleEventResponse, error) {
+func (c *IddaaClient) GetSingleEvent(ctx context.Context, eventID int) (*models.IddaaSingleEventResponse, error) {
 	url := fmt.Sprintf("%s/sportsbook/event/%d", c.baseURL, eventID)
 
-	resp, err := c.makeRequest(url)
+	resp, err := c.makeRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -429,8 +530,8 @@ func (c *IddaaClient) GetSingleEvent(eventID int) (*models.IddaaSingleEventRespo
 }
 
 // FetchData fetches raw JSON data from the given URL
-func (c *IddaaClient) FetchData(url string) ([]byte, error) {
-	resp, err := c.makeRequest(url)
+func (c *IddaaClient) FetchData(ctx context.Context, url string) ([]byte, error) {
+	resp, err := c.makeRequest(ctx, url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch data from %s: %w", url, err)
 	}
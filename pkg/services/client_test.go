@@ -2,6 +2,7 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 	"testing"
@@ -63,7 +64,7 @@ func TestGetEventStatistics_DirectArray(t *testing.T) {
 		logger:  logger.New("test"),
 	}
 
-	stats, err := iddaaClient.GetEventStatistics(1, "2025-06-05")
+	stats, err := iddaaClient.GetEventStatistics(context.Background(), 1, "2025-06-05")
 	if err != nil {
 		t.Errorf("Expected no error, got: %v", err)
 	}
@@ -125,7 +126,7 @@ func TestGetEventStatistics_WrappedResponse(t *testing.T) {
 		logger:  logger.New("test"),
 	}
 
-	stats, err := iddaaClient.GetEventStatistics(1, "2025-06-05")
+	stats, err := iddaaClient.GetEventStatistics(context.Background(), 1, "2025-06-05")
 	if err != nil {
 		t.Errorf("Expected no error, got: %v", err)
 	}
@@ -191,7 +192,7 @@ func TestGetEventStatistics_ObjectMapResponse(t *testing.T) {
 		logger:  logger.New("test"),
 	}
 
-	stats, err := iddaaClient.GetEventStatistics(1, "2025-06-05")
+	stats, err := iddaaClient.GetEventStatistics(context.Background(), 1, "2025-06-05")
 	if err != nil {
 		t.Errorf("Expected no error, got: %v", err)
 	}
@@ -232,7 +233,7 @@ func TestGetEventStatistics_APIFailure(t *testing.T) {
 		logger:  logger.New("test"),
 	}
 
-	_, err := iddaaClient.GetEventStatistics(1, "2025-06-05")
+	_, err := iddaaClient.GetEventStatistics(context.Background(), 1, "2025-06-05")
 	if err == nil {
 		t.Errorf("Expected error for API failure, got nil")
 	}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+)
+
+// closingLineLookbackDays bounds the finished events the backfill retries, an event whose freeze
+// stores nothing is selected again on every run until it is older than this
+const closingLineLookbackDays = 7
+
+// ClosingLineService freezes closing lines for finished events and computes CLV
+type ClosingLineService struct {
+	db     *generated.Queries
+	logger *logger.Logger
+}
+
+// ClosingLineStats summarizes a closing line run
+type ClosingLineStats struct {
+	Events       int
+	FrozenOdds   int64
+	CLVSnapshots int64
+}
+
+// NewClosingLineService creates a new closing line service
+func NewClosingLineService(db *generated.Queries) *ClosingLineService {
+	return &ClosingLineService{
+		db:     db,
+		logger: logger.New("closing-line-service"),
+	}
+}
+
+// FreezeEvents freezes closing odds for the given events and computes CLV for their history.
+// Events that are not finished are ignored and already frozen lines are kept as they are.
+func (s *ClosingLineService) FreezeEvents(ctx context.Context, eventIDs []int32) (ClosingLineStats, error) {
+	stats := ClosingLineStats{Events: len(eventIDs)}
+	if len(eventIDs) == 0 {
+		return stats, nil
+	}
+
+	frozen, err := s.db.FreezeClosingOdds(ctx, eventIDs)
+	if err != nil {
+		return stats, fmt.Errorf("failed to freeze closing odds: %w", err)
+	}
+	stats.FrozenOdds = frozen
+
+	// Only new closing lines change CLV, skip the history scan otherwise
+	if frozen == 0 {
+		return stats, nil
+	}
+
+	computed, err := s.computeClosingLineValues(ctx, eventIDs)
+	if err != nil {
+		return stats, err
+	}
+	stats.CLVSnapshots = computed
+
+	return stats, nil
+}
+
+// RecomputeEvents computes CLV again for the history of events whose closing lines are already
+// frozen, after their odds history was rewritten
+func (s *ClosingLineService) RecomputeEvents(ctx context.Context, eventIDs []int32) (ClosingLineStats, error) {
+	stats := ClosingLineStats{Events: len(eventIDs)}
+	if len(eventIDs) == 0 {
+		return stats, nil
+	}
+
+	computed, err := s.computeClosingLineValues(ctx, eventIDs)
+	if err != nil {
+		return stats, err
+	}
+	stats.CLVSnapshots = computed
+
+	return stats, nil
+}
+
+// ClosingLineValue compares a price with the closing price of the same outcome. percentage is
+// (odds / closing - 1) * 100 and probability the implied probability edge in percentage points,
+// (1 / closing - 1 / odds) * 100. Both are positive when the price beat the close.
+func ClosingLineValue(odds, closing float64) (percentage, probability float64) {
+	percentage = (odds/closing - 1) * 100
+	probability = (1/closing - 1/odds) * 100
+	return percentage, probability
+}
+
+// computeClosingLineValues stores the CLV of every pre-kickoff odds_history snapshot of the events
+func (s *ClosingLineService) computeClosingLineValues(ctx context.Context, eventIDs []int32) (int64, error) {
+	snapshots, err := s.db.GetClosingLineSnapshots(ctx, eventIDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get closing line snapshots: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return 0, nil
+	}
+
+	params := generated.UpsertClosingLineValuesParams{
+		OddsHistoryIds:   make([]int32, len(snapshots)),
+		EventIds:         make([]int32, len(snapshots)),
+		ClosingOddsIds:   make([]int32, len(snapshots)),
+		OddsValues:       make([]float64, len(snapshots)),
+		ClosingValues:    make([]float64, len(snapshots)),
+		ClvPercentages:   make([]float32, len(snapshots)),
+		ClvProbabilities: make([]float32, len(snapshots)),
+	}
+	for i, snapshot := range snapshots {
+		percentage, probability := ClosingLineValue(snapshot.OddsValue, snapshot.ClosingValue)
+		params.OddsHistoryIds[i] = snapshot.OddsHistoryID
+		params.EventIds[i] = snapshot.EventID
+		params.ClosingOddsIds[i] = snapshot.ClosingOddsID
+		params.OddsValues[i] = snapshot.OddsValue
+		params.ClosingValues[i] = snapshot.ClosingValue
+		params.ClvPercentages[i] = float32(percentage)
+		params.ClvProbabilities[i] = float32(probability)
+	}
+
+	computed, err := s.db.UpsertClosingLineValues(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to store closing line values: %w", err)
+	}
+	return computed, nil
+}
+
+// ProcessPending freezes closing lines for recently finished events that do not have one yet
+func (s *ClosingLineService) ProcessPending(ctx context.Context, limit int32) (ClosingLineStats, error) {
+	eventIDs, err := s.db.GetEventsPendingClosingLines(ctx, generated.GetEventsPendingClosingLinesParams{
+		LookbackDays: closingLineLookbackDays,
+		LimitCount:   limit,
+	})
+	if err != nil {
+		return ClosingLineStats{}, fmt.Errorf("failed to get events pending closing lines: %w", err)
+	}
+
+	return s.FreezeEvents(ctx, eventIDs)
+}
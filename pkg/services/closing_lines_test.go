@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+func TestClosingLineValue(t *testing.T) {
+	tests := []struct {
+		name            string
+		odds, closing   float64
+		wantPercentage  float64
+		wantProbability float64
+	}{
+		{"beat the close", 2.2, 2.0, 10, 4.545454545},
+		{"matched the close", 1.85, 1.85, 0, 0},
+		{"worse than the close", 1.6, 2.0, -20, -12.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percentage, probability := ClosingLineValue(tt.odds, tt.closing)
+			if math.Abs(percentage-tt.wantPercentage) > 1e-6 {
+				t.Errorf("percentage = %v, want %v", percentage, tt.wantPercentage)
+			}
+			if math.Abs(probability-tt.wantProbability) > 1e-6 {
+				t.Errorf("probability = %v, want %v", probability, tt.wantProbability)
+			}
+		})
+	}
+}
+
+// clvDB answers GetClosingLineSnapshots with snapshots and records the UpsertClosingLineValues arguments
+type clvDB struct {
+	snapshots []generated.GetClosingLineSnapshotsRow
+	upserted  []interface{}
+}
+
+func (d *clvDB) Exec(_ context.Context, query string, args ...interface{}) (pgconn.CommandTag, error) {
+	if !strings.Contains(query, "UpsertClosingLineValues") {
+		return pgconn.CommandTag{}, errors.New("unexpected Exec")
+	}
+	d.upserted = args
+	return pgconn.NewCommandTag(fmt.Sprintf("INSERT 0 %d", len(d.snapshots))), nil
+}
+
+func (d *clvDB) Query(_ context.Context, query string, _ ...interface{}) (pgx.Rows, error) {
+	if !strings.Contains(query, "GetClosingLineSnapshots") {
+		return nil, errors.New("unexpected Query")
+	}
+	return &snapshotRows{rows: d.snapshots, at: -1}, nil
+}
+
+func (d *clvDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return nil
+}
+
+type snapshotRows struct {
+	pgx.Rows
+	rows []generated.GetClosingLineSnapshotsRow
+	at   int
+}
+
+func (r *snapshotRows) Next() bool { r.at++; return r.at < len(r.rows) }
+func (r *snapshotRows) Err() error { return nil }
+func (r *snapshotRows) Close()     {}
+
+func (r *snapshotRows) Scan(dest ...any) error {
+	row := r.rows[r.at]
+	*dest[0].(*int32) = row.OddsHistoryID
+	*dest[1].(*int32) = row.EventID
+	*dest[2].(*int32) = row.ClosingOddsID
+	*dest[3].(*float64) = row.OddsValue
+	*dest[4].(*float64) = row.ClosingValue
+	return nil
+}
+
+func TestClosingLineService_ComputesCLVOfEverySnapshot(t *testing.T) {
+	db := &clvDB{snapshots: []generated.GetClosingLineSnapshotsRow{
+		{OddsHistoryID: 11, EventID: 1, ClosingOddsID: 5, OddsValue: 2.2, ClosingValue: 2.0},
+		{OddsHistoryID: 12, EventID: 1, ClosingOddsID: 5, OddsValue: 1.6, ClosingValue: 2.0},
+	}}
+
+	stats, err := NewClosingLineService(generated.New(db)).RecomputeEvents(context.Background(), []int32{1})
+	if err != nil {
+		t.Fatalf("RecomputeEvents() error = %v", err)
+	}
+	if stats.Events != 1 || stats.CLVSnapshots != 2 {
+		t.Errorf("stats = %+v, want 1 event and 2 snapshots", stats)
+	}
+
+	if len(db.upserted) != 7 {
+		t.Fatalf("upserted %d arguments, want 7", len(db.upserted))
+	}
+	if ids := db.upserted[0].([]int32); len(ids) != 2 || ids[0] != 11 || ids[1] != 12 {
+		t.Errorf("odds history ids = %v", ids)
+	}
+	percentages := db.upserted[5].([]float32)
+	probabilities := db.upserted[6].([]float32)
+	if math.Abs(float64(percentages[0])-10) > 1e-4 || math.Abs(float64(percentages[1])+20) > 1e-4 {
+		t.Errorf("clv percentages = %v, want [10 -20]", percentages)
+	}
+	if math.Abs(float64(probabilities[0])-4.5454545) > 1e-4 || math.Abs(float64(probabilities[1])+12.5) > 1e-4 {
+		t.Errorf("clv probabilities = %v, want [4.545 -12.5]", probabilities)
+	}
+}
+
+func TestClosingLineService_SkipsEventsWithoutSnapshots(t *testing.T) {
+	db := &clvDB{}
+
+	stats, err := NewClosingLineService(generated.New(db)).RecomputeEvents(context.Background(), []int32{1})
+	if err != nil {
+		t.Fatalf("RecomputeEvents() error = %v", err)
+	}
+	if stats.CLVSnapshots != 0 || db.upserted != nil {
+		t.Errorf("stats = %+v, upserted = %v, want nothing stored", stats, db.upserted)
+	}
+}
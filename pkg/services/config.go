@@ -29,7 +29,7 @@ func NewConfigService(db *generated.Queries, client *IddaaClient) *ConfigService
 func (s *ConfigService) SyncConfig(ctx context.Context, platform string) error {
 	log.Printf("Starting config sync for platform: %s", platform)
 
-	resp, err := s.client.GetAppConfig(platform)
+	resp, err := s.client.GetAppConfig(ctx, platform)
 	if err != nil {
 		return fmt.Errorf("failed to fetch config: %w", err)
 	}
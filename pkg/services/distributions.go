@@ -16,6 +16,7 @@ type DistributionService struct {
 	db     *generated.Queries
 	client *IddaaClient
 	logger *logger.Logger
+	replayClock
 }
 
 func NewDistributionService(db *generated.Queries, client *IddaaClient) *DistributionService {
@@ -46,15 +47,20 @@ type OutcomeDistributionResponse struct {
 
 // FetchAndUpdateDistributions fetches outcome betting distribution data using bulk operations
 func (s *DistributionService) FetchAndUpdateDistributions(ctx context.Context, sportType int) error {
-	start := time.Now()
-
 	url := fmt.Sprintf("https://sportsbookv2.iddaa.com/sportsbook/outcome-play-percentages?sportType=%d", sportType)
 
-	data, err := s.client.FetchData(url)
+	data, err := s.client.FetchData(ctx, url)
 	if err != nil {
 		return fmt.Errorf("failed to fetch distribution data: %w", err)
 	}
 
+	return s.ProcessDistributionPayload(ctx, sportType, data)
+}
+
+// ProcessDistributionPayload applies an outcome-play-percentages response, fetched live or read from the payload archive
+func (s *DistributionService) ProcessDistributionPayload(ctx context.Context, sportType int, data []byte) error {
+	start := time.Now()
+
 	var response OutcomeDistributionResponse
 	if err := json.Unmarshal(data, &response); err != nil {
 		return fmt.Errorf("failed to unmarshal distribution response: %w", err)
@@ -78,7 +84,7 @@ func (s *DistributionService) FetchAndUpdateDistributions(ctx context.Context, s
 	}
 
 	// Step 2: Execute bulk operations
-	err = s.executeBulkDistributionUpdate(ctx, flatDistributions)
+	err := s.executeBulkDistributionUpdate(ctx, flatDistributions)
 	if err != nil {
 		return fmt.Errorf("bulk distribution update failed: %w", err)
 	}
@@ -197,8 +203,8 @@ func (s *DistributionService) executeBulkDistributionUpdate(ctx context.Context,
 		currentMap[key] = curr.BetPercentage
 	}
 
-	// Step 4: Calculate implied probabilities from odds (bulk fetch)
-	oddsMap, err := s.getImpliedProbabilitiesInBulk(ctx, eventIDs)
+	// Step 4: Calculate margin-free implied probabilities from odds (bulk fetch)
+	probabilityMap, err := s.getImpliedProbabilitiesInBulk(ctx, eventIDs)
 	if err != nil {
 		s.logger.Warn().Err(err).Msg("Failed to fetch odds for implied probabilities")
 	}
@@ -224,13 +230,13 @@ func (s *DistributionService) executeBulkDistributionUpdate(ctx context.Context,
 	const significantChangeThreshold = 0.01 // 0.01% change
 
 	for _, dist := range validDistributions {
-		// Calculate implied probability
+		// Look up implied probability
 		impliedProb := 0.0
-		if oddsValue, exists := oddsMap[oddsKey{
+		if probability, exists := probabilityMap[oddsKey{
 			eventExtID: dist.EventExternalID,
 			outcome:    dist.Outcome,
-		}]; exists && oddsValue > 0 {
-			impliedProb = (1.0 / oddsValue) * 100
+		}]; exists {
+			impliedProb = probability
 		}
 
 		// Always add to upsert arrays
@@ -267,6 +273,7 @@ func (s *DistributionService) executeBulkDistributionUpdate(ctx context.Context,
 			Outcomes:             upsertOutcomes,
 			BetPercentages:       upsertPercentages,
 			ImpliedProbabilities: upsertImpliedProb,
+			RecordedAt:           s.recordedAt(),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to bulk upsert distributions: %w", err)
@@ -281,6 +288,7 @@ func (s *DistributionService) executeBulkDistributionUpdate(ctx context.Context,
 			Outcomes:            historyOutcomes,
 			BetPercentages:      historyPercentages,
 			PreviousPercentages: historyPrevPercent,
+			RecordedAt:          s.recordedAt(),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to bulk insert distribution history: %w", err)
@@ -297,29 +305,51 @@ func (s *DistributionService) executeBulkDistributionUpdate(ctx context.Context,
 	return nil
 }
 
-// oddsKey for looking up odds values
+// oddsKey for looking up implied probabilities
 type oddsKey struct {
 	eventExtID string
 	outcome    string
 }
 
-// getImpliedProbabilitiesInBulk fetches odds for all events and returns a lookup map
+// getImpliedProbabilitiesInBulk fetches odds for all events and returns their fair probabilities in percent.
+// Raw 1/odds includes the bookmaker margin and overstates every outcome, so each event's market is de-vigged first.
 func (s *DistributionService) getImpliedProbabilitiesInBulk(ctx context.Context, eventExtIDs []string) (map[oddsKey]float64, error) {
 	odds, err := s.db.GetCurrentOddsForEvents(ctx, eventExtIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	oddsMap := make(map[oddsKey]float64)
+	// Group outcomes by event, keeping the query order
+	type eventMarket struct {
+		outcomes []string
+		odds     []float64
+	}
+	markets := make(map[string]*eventMarket)
 	for _, odd := range odds {
-		key := oddsKey{
-			eventExtID: odd.ExternalID,
-			outcome:    odd.Outcome,
+		market, ok := markets[odd.ExternalID]
+		if !ok {
+			market = &eventMarket{}
+			markets[odd.ExternalID] = market
+		}
+		market.outcomes = append(market.outcomes, odd.Outcome)
+		market.odds = append(market.odds, odd.OddsValue)
+	}
+
+	probabilityMap := make(map[oddsKey]float64)
+	for externalID, market := range markets {
+		prices, ok := Devig(market.odds)
+		if !ok {
+			continue
+		}
+		for i, fair := range prices.Fair() {
+			probabilityMap[oddsKey{
+				eventExtID: externalID,
+				outcome:    market.outcomes[i],
+			}] = fair * 100
 		}
-		oddsMap[key] = odd.OddsValue
 	}
 
-	return oddsMap, nil
+	return probabilityMap, nil
 }
 
 // Legacy methods kept for compatibility but should not be used
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+// Reasons a diff cannot be applied; both trigger a full resync
+var (
+	ErrDiffRejected = errors.New("events diff rejected")
+	ErrVersionGap   = errors.New("events diff version gap")
+)
+
+const (
+	SyncModeFull = "full"
+	SyncModeDiff = "diff"
+)
+
+// EventSyncResult describes one sport's sync
+type EventSyncResult struct {
+	SportID     int32
+	Mode        string
+	FromVersion int64
+	Version     int64
+	Events      int
+	// Why a full bulletin was fetched instead of a diff, empty for diffs
+	FullReason string
+}
+
+// EventSyncService keeps events in step with the Iddaa bulletin using the version/isdiff protocol.
+// It remembers the last applied version per sport in event_sync_versions and asks for diffs from it.
+type EventSyncService struct {
+	db     *generated.Queries
+	client *IddaaClient
+	events *EventsService
+	logger *logger.Logger
+	// A diff base older than this is not trusted, Iddaa only keeps recent versions
+	MaxDiffAge time.Duration
+	// A full bulletin is fetched at least this often to repair anything diffs missed
+	FullSyncInterval time.Duration
+}
+
+func NewEventSyncService(events *EventsService, client *IddaaClient) *EventSyncService {
+	return &EventSyncService{
+		db:               events.db,
+		client:           client,
+		events:           events,
+		logger:           logger.New("event-sync"),
+		MaxDiffAge:       30 * time.Minute,
+		FullSyncInterval: 6 * time.Hour,
+	}
+}
+
+// SyncSport applies the diff since the stored version, or the full bulletin when no usable
+// version exists or the diff is rejected or has a gap
+func (s *EventSyncService) SyncSport(ctx context.Context, sportID int32) (EventSyncResult, error) {
+	reason := "no sync state"
+	state, err := s.db.GetEventSyncVersion(ctx, sportID)
+	switch {
+	case err == nil:
+		var from int64
+		from, reason = s.diffBase(state, time.Now())
+		if from > 0 {
+			result, err := s.syncDiff(ctx, sportID, from)
+			if err == nil {
+				return result, nil
+			}
+			if !errors.Is(err, ErrDiffRejected) && !errors.Is(err, ErrVersionGap) {
+				return result, err
+			}
+			reason = err.Error()
+			s.logger.Warn().
+				Err(err).
+				Int32("sport_id", sportID).
+				Int64("from_version", from).
+				Str("action", "diff_fallback").
+				Msg("Falling back to full events sync")
+		}
+	case !errors.Is(err, pgx.ErrNoRows):
+		return EventSyncResult{SportID: sportID}, fmt.Errorf("failed to get sync version: %w", err)
+	}
+
+	return s.syncFull(ctx, sportID, reason)
+}
+
+// diffBase returns the version to diff from, or 0 and the reason a full sync is needed
+func (s *EventSyncService) diffBase(state generated.EventSyncVersion, now time.Time) (int64, string) {
+	if state.Version <= 0 {
+		return 0, "no stored version"
+	}
+	if !state.LastSyncedAt.Valid || now.Sub(state.LastSyncedAt.Time) > s.MaxDiffAge {
+		return 0, "stored version too old"
+	}
+	if !state.LastFullSyncAt.Valid || now.Sub(state.LastFullSyncAt.Time) > s.FullSyncInterval {
+		return 0, "periodic full resync"
+	}
+	return state.Version, ""
+}
+
+func (s *EventSyncService) syncDiff(ctx context.Context, sportID int32, from int64) (EventSyncResult, error) {
+	result := EventSyncResult{SportID: sportID, Mode: SyncModeDiff, FromVersion: from}
+
+	response, err := s.client.GetEventsSince(ctx, int(sportID), from)
+	if err != nil {
+		return result, fmt.Errorf("%w: %v", ErrDiffRejected, err)
+	}
+	if err := checkDiff(from, response); err != nil {
+		return result, err
+	}
+
+	result.Version = response.Data.Version
+	result.Events = len(response.Data.Events)
+
+	// Iddaa answers with the full bulletin when it no longer holds the requested version
+	if !response.Data.IsDiff {
+		result.Mode = SyncModeFull
+		result.FullReason = "server sent full bulletin"
+		if err := s.events.ProcessEventsResponse(ctx, response); err != nil {
+			return result, err
+		}
+		return result, s.saveVersion(ctx, sportID, result.Version, true)
+	}
+
+	if err := s.events.ProcessEventsDiff(ctx, response); err != nil {
+		return result, err
+	}
+	return result, s.saveVersion(ctx, sportID, result.Version, false)
+}
+
+func (s *EventSyncService) syncFull(ctx context.Context, sportID int32, reason string) (EventSyncResult, error) {
+	result := EventSyncResult{SportID: sportID, Mode: SyncModeFull, FullReason: reason}
+
+	response, err := s.client.GetEventsSince(ctx, int(sportID), 0)
+	if err != nil {
+		return result, err
+	}
+	if err := s.events.ProcessEventsResponse(ctx, response); err != nil {
+		return result, err
+	}
+
+	result.Version = response.Data.Version
+	result.Events = len(response.Data.Events)
+	return result, s.saveVersion(ctx, sportID, result.Version, true)
+}
+
+func (s *EventSyncService) saveVersion(ctx context.Context, sportID int32, version int64, isFull bool) error {
+	if _, err := s.db.UpsertEventSyncVersion(ctx, generated.UpsertEventSyncVersionParams{
+		SportID: sportID,
+		Version: version,
+		IsFull:  isFull,
+	}); err != nil {
+		return fmt.Errorf("failed to save sync version: %w", err)
+	}
+	return nil
+}
+
+// checkDiff validates a response to a diff request made from version from
+func checkDiff(from int64, response *models.IddaaEventsResponse) error {
+	if response == nil || !response.IsSuccess || response.Data == nil {
+		return ErrDiffRejected
+	}
+	if response.Data.Version < from {
+		return fmt.Errorf("%w: version went back from %d to %d", ErrVersionGap, from, response.Data.Version)
+	}
+	return nil
+}
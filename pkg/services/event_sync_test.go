@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+func TestEventSyncService_DiffBase(t *testing.T) {
+	now := time.Now()
+	ts := func(ago time.Duration) pgtype.Timestamp {
+		return pgtype.Timestamp{Time: now.Add(-ago), Valid: true}
+	}
+	s := &EventSyncService{MaxDiffAge: 30 * time.Minute, FullSyncInterval: 6 * time.Hour}
+
+	tests := []struct {
+		name  string
+		state generated.EventSyncVersion
+		want  int64
+	}{
+		{"fresh state", generated.EventSyncVersion{Version: 42, LastSyncedAt: ts(time.Minute), LastFullSyncAt: ts(time.Hour)}, 42},
+		{"no version", generated.EventSyncVersion{LastSyncedAt: ts(time.Minute), LastFullSyncAt: ts(time.Hour)}, 0},
+		{"stale base", generated.EventSyncVersion{Version: 42, LastSyncedAt: ts(time.Hour), LastFullSyncAt: ts(time.Hour)}, 0},
+		{"full sync due", generated.EventSyncVersion{Version: 42, LastSyncedAt: ts(time.Minute), LastFullSyncAt: ts(7 * time.Hour)}, 0},
+		{"never fully synced", generated.EventSyncVersion{Version: 42, LastSyncedAt: ts(time.Minute)}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := s.diffBase(tt.state, now)
+			if got != tt.want {
+				t.Errorf("diffBase() = %d, want %d", got, tt.want)
+			}
+			if (got == 0) == (reason == "") {
+				t.Errorf("diffBase() reason = %q for version %d", reason, got)
+			}
+		})
+	}
+}
+
+func TestCheckDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *models.IddaaEventsResponse
+		want     error
+	}{
+		{"diff", &models.IddaaEventsResponse{IsSuccess: true, Data: &models.IddaaEventsData{IsDiff: true, Version: 11}}, nil},
+		{"unchanged", &models.IddaaEventsResponse{IsSuccess: true, Data: &models.IddaaEventsData{IsDiff: true, Version: 10}}, nil},
+		{"not successful", &models.IddaaEventsResponse{IsSuccess: false}, ErrDiffRejected},
+		{"no data", &models.IddaaEventsResponse{IsSuccess: true}, ErrDiffRejected},
+		{"version went back", &models.IddaaEventsResponse{IsSuccess: true, Data: &models.IddaaEventsData{IsDiff: true, Version: 3}}, ErrVersionGap},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDiff(10, tt.response)
+			if tt.want == nil && err != nil {
+				t.Errorf("checkDiff() error = %v, want nil", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("checkDiff() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -22,14 +22,25 @@ type EventsService struct {
 	logger *logger.Logger
 	// Market type cache - loaded once at startup
 	marketTypes map[string]int32 // code -> id mapping
+	// Freezes closing lines when events finish
+	closingLines *ClosingLineService
+	// Writes current odds and history, UNNEST chunks unless SetOddsWriter picks another path
+	oddsWriter OddsWriter
+	// Optional in-process copy of current odds, replaces reading current_odds before writes
+	oddsCache *OddsCache
+	// Set while reprocessing, replaces both the odds cache and current_odds for change detection
+	replayOdds *ReplayOdds
+	replayClock
 }
 
 func NewEventsService(db *generated.Queries, client *IddaaClient) *EventsService {
 	service := &EventsService{
-		db:          db,
-		client:      client,
-		logger:      logger.New("events-service"),
-		marketTypes: make(map[string]int32),
+		db:           db,
+		client:       client,
+		logger:       logger.New("events-service"),
+		marketTypes:  make(map[string]int32),
+		closingLines: NewClosingLineService(db),
+		oddsWriter:   NewUnnestOddsWriter(db),
 	}
 
 	// Load all market types once at startup
@@ -51,13 +62,149 @@ func NewEventsService(db *generated.Queries, client *IddaaClient) *EventsService
 	return service
 }
 
+// SetOddsWriter replaces the writer used for current odds and odds history
+func (s *EventsService) SetOddsWriter(writer OddsWriter) {
+	s.oddsWriter = writer
+}
+
+// SetOddsCache makes change detection read the cache instead of current_odds
+func (s *EventsService) SetOddsCache(cache *OddsCache) {
+	s.oddsCache = cache
+}
+
+// SetReplayOdds makes change detection compare with the prices of a replay instead of today's
+func (s *EventsService) SetReplayOdds(replay *ReplayOdds) {
+	s.replayOdds = replay
+}
+
+// currentOddsForComparison returns the stored prices of keys from the replay state or the odds
+// cache, or from current_odds when there is no cache or it cannot answer
+func (s *EventsService) currentOddsForComparison(ctx context.Context, keys []OddsCacheKey) ([]generated.BulkGetCurrentOddsForComparisonRow, error) {
+	if s.replayOdds != nil {
+		return s.replayOdds.CurrentOdds(ctx, keys)
+	}
+	if s.oddsCache != nil {
+		rows, ok, err := s.oddsCache.CurrentOdds(ctx, keys)
+		if err != nil {
+			s.logger.Warn().Err(err).Msg("Odds cache lookup failed, reading current odds")
+		} else if ok {
+			return rows, nil
+		}
+	}
+
+	params := generated.BulkGetCurrentOddsForComparisonParams{
+		EventIds:      make([]int32, len(keys)),
+		MarketTypeIds: make([]int32, len(keys)),
+		Outcomes:      make([]string, len(keys)),
+	}
+	for i, key := range keys {
+		params.EventIds[i] = key.EventID
+		params.MarketTypeIds[i] = key.MarketTypeID
+		params.Outcomes[i] = key.Outcome
+	}
+	return s.db.BulkGetCurrentOddsForComparison(ctx, params)
+}
+
+// writeOdds writes a batch through the odds writer and takes the next current odds version once
+// it is stored, so odds caches in other processes notice the write. keys matches batch.Current
+// row for row; the odds cache is updated with them.
+func (s *EventsService) writeOdds(ctx context.Context, batch OddsBatch, keys []OddsCacheKey) error {
+	if s.oddsCache != nil {
+		s.oddsCache.BeginWrite()
+	}
+
+	writeErr := s.oddsWriter.WriteOdds(ctx, batch)
+
+	version, err := s.db.NextCurrentOddsVersion(ctx)
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("Failed to take current odds version")
+		version = 0
+	}
+
+	if s.oddsCache != nil {
+		s.oddsCache.FinishWrite(version, keys, batch.Current.OddsValues, writeErr)
+	}
+	if s.replayOdds != nil && writeErr == nil {
+		s.replayOdds.Apply(keys, batch.Current.OddsValues)
+	}
+	return writeErr
+}
+
+// withoutUnchanged drops the prices equal to the stored ones, the upsert would leave them untouched
+func withoutUnchanged(current generated.BulkUpsertCurrentOddsParams, keys []OddsCacheKey, unchanged func(i int) bool) (generated.BulkUpsertCurrentOddsParams, []OddsCacheKey) {
+	changed := generated.BulkUpsertCurrentOddsParams{RecordedAt: current.RecordedAt}
+	var changedKeys []OddsCacheKey
+	for i := range current.EventIds {
+		if unchanged(i) {
+			continue
+		}
+		changed.EventIds = append(changed.EventIds, current.EventIds[i])
+		changed.MarketTypeIds = append(changed.MarketTypeIds, current.MarketTypeIds[i])
+		changed.Outcomes = append(changed.Outcomes, current.Outcomes[i])
+		changed.OddsValues = append(changed.OddsValues, current.OddsValues[i])
+		changed.MarketParams = append(changed.MarketParams, current.MarketParams[i])
+		changedKeys = append(changedKeys, keys[i])
+	}
+	return changed, changedKeys
+}
+
 // ProcessEventsResponse processes the API response using bulk operations
 func (s *EventsService) ProcessEventsResponse(ctx context.Context, response *models.IddaaEventsResponse) error {
 	if !response.IsSuccess || response.Data == nil {
 		return fmt.Errorf("invalid API response")
 	}
 
-	events := response.Data.Events
+	return s.processEvents(ctx, response.Data.Events)
+}
+
+// ProcessEventsDiff applies a bulletin diff. Events carrying team names are upserted in full;
+// events without them only update odds and must already be known, otherwise ErrVersionGap is returned.
+func (s *EventsService) ProcessEventsDiff(ctx context.Context, response *models.IddaaEventsResponse) error {
+	if !response.IsSuccess || response.Data == nil {
+		return fmt.Errorf("invalid API response")
+	}
+
+	complete := make([]models.IddaaEvent, 0, len(response.Data.Events))
+	partial := make([]models.IddaaEvent, 0)
+	for _, event := range response.Data.Events {
+		if event.HomeTeam != "" && event.AwayTeam != "" {
+			complete = append(complete, event)
+		} else {
+			partial = append(partial, event)
+		}
+	}
+
+	if len(partial) > 0 {
+		seen := make(map[int]bool, len(partial))
+		externalIDs := make([]string, 0, len(partial))
+		for _, event := range partial {
+			if !seen[event.ID] {
+				seen[event.ID] = true
+				externalIDs = append(externalIDs, strconv.Itoa(event.ID))
+			}
+		}
+		known, err := s.db.GetEventIDsByExternalIDs(ctx, externalIDs)
+		if err != nil {
+			return fmt.Errorf("failed to look up diff events: %w", err)
+		}
+		if len(known) < len(externalIDs) {
+			return fmt.Errorf("%w: %d of %d diff events are unknown", ErrVersionGap, len(externalIDs)-len(known), len(externalIDs))
+		}
+
+		eventMapping := make(map[string]int32, len(known))
+		for _, event := range known {
+			eventMapping[event.ExternalID] = event.ID
+		}
+		if _, _, err := s.bulkProcessOdds(ctx, partial, eventMapping); err != nil {
+			return fmt.Errorf("failed to process diff odds: %w", err)
+		}
+	}
+
+	return s.processEvents(ctx, complete)
+}
+
+// processEvents upserts teams, events and odds for a batch of bulletin events
+func (s *EventsService) processEvents(ctx context.Context, events []models.IddaaEvent) error {
 	if len(events) == 0 {
 		return nil
 	}
@@ -85,6 +232,30 @@ func (s *EventsService) ProcessEventsResponse(ctx context.Context, response *mod
 		return fmt.Errorf("failed to process odds: %w", err)
 	}
 
+	// Step 4: Freeze closing lines for events that just finished
+	finishedEventIDs := make([]int32, 0)
+	for _, event := range events {
+		if s.convertEventStatus(event.Status) != "finished" {
+			continue
+		}
+		if eventID, ok := eventMapping[strconv.Itoa(event.ID)]; ok {
+			finishedEventIDs = append(finishedEventIDs, eventID)
+		}
+	}
+	if len(finishedEventIDs) > 0 {
+		clvStats, err := s.closingLines.FreezeEvents(ctx, finishedEventIDs)
+		if err != nil {
+			// Not fatal, the closing lines job picks these events up later
+			log.Error().Err(err).Int("finished_events", len(finishedEventIDs)).Msg("Failed to freeze closing lines")
+		} else if clvStats.FrozenOdds > 0 {
+			log.Info().
+				Int("finished_events", len(finishedEventIDs)).
+				Int64("closing_odds", clvStats.FrozenOdds).
+				Int64("clv_snapshots", clvStats.CLVSnapshots).
+				Msg("Closing lines frozen")
+		}
+	}
+
 	duration := time.Since(startTime)
 
 	// Calculate comprehensive metrics
@@ -246,6 +417,8 @@ func (s *EventsService) bulkProcessOdds(ctx context.Context, events []models.Idd
 	var outcomes []string
 	var oddsValues []float64
 	var marketParams [][]byte
+	var inPlay []bool
+	var keys []OddsCacheKey
 
 	newOddsMap := make(map[string]float64)
 
@@ -275,6 +448,8 @@ func (s *EventsService) bulkProcessOdds(ctx context.Context, events []models.Idd
 				outcomes = append(outcomes, outcomeStr)
 				oddsValues = append(oddsValues, outcome.Odds)
 				marketParams = append(marketParams, paramsJSON)
+				inPlay = append(inPlay, event.IsLive)
+				keys = append(keys, OddsCacheKey{EventID: eventID, MarketTypeID: marketTypeID, Outcome: outcomeStr})
 
 				// Store for history tracking
 				key := fmt.Sprintf("%d-%d-%s", eventID, marketTypeID, outcomeStr)
@@ -288,11 +463,7 @@ func (s *EventsService) bulkProcessOdds(ctx context.Context, events []models.Idd
 	}
 
 	// Get existing odds for comparison
-	existingOdds, err := s.db.BulkGetCurrentOddsForComparison(ctx, generated.BulkGetCurrentOddsForComparisonParams{
-		EventIds:      eventIDs,
-		MarketTypeIds: marketTypeIDs,
-		Outcomes:      outcomes,
-	})
+	existingOdds, err := s.currentOddsForComparison(ctx, keys)
 	if err != nil {
 		s.logger.Warn().Err(err).Msg("Failed to get existing odds for comparison")
 	}
@@ -300,32 +471,11 @@ func (s *EventsService) bulkProcessOdds(ctx context.Context, events []models.Idd
 	// Build map of existing odds
 	existingOddsMap := make(map[string]generated.BulkGetCurrentOddsForComparisonRow)
 	for _, existing := range existingOdds {
-		key := fmt.Sprintf("%v-%v-%s", existing.EventID, existing.MarketTypeID, existing.Outcome)
-		existingOddsMap[key] = existing
-	}
-
-	// Bulk upsert current odds
-	const chunkSize = 1000
-	for i := 0; i < len(eventIDs); i += chunkSize {
-		end := i + chunkSize
-		if end > len(eventIDs) {
-			end = len(eventIDs)
-		}
-
-		err := s.db.BulkUpsertCurrentOdds(ctx, generated.BulkUpsertCurrentOddsParams{
-			EventIds:      eventIDs[i:end],
-			MarketTypeIds: marketTypeIDs[i:end],
-			Outcomes:      outcomes[i:end],
-			OddsValues:    oddsValues[i:end],
-			MarketParams:  marketParams[i:end],
-		})
-		if err != nil {
-			s.logger.Error().
-				Err(err).
-				Int("chunk_start", i).
-				Int("chunk_size", end-i).
-				Msg("Failed to bulk upsert odds chunk")
+		if existing.EventID == nil || existing.MarketTypeID == nil {
+			continue
 		}
+		key := fmt.Sprintf("%d-%d-%s", *existing.EventID, *existing.MarketTypeID, existing.Outcome)
+		existingOddsMap[key] = existing
 	}
 
 	// Create history records for changed odds
@@ -341,6 +491,7 @@ func (s *EventsService) bulkProcessOdds(ctx context.Context, events []models.Idd
 	var historySignificanceLevels []string
 	var historyMinutesToKickoffs []int32
 	var historyMarketParams [][]byte
+	var historyInPlay []bool
 
 	// Process changes
 	for i := range eventIDs {
@@ -366,7 +517,7 @@ func (s *EventsService) bulkProcessOdds(ctx context.Context, events []models.Idd
 				// Calculate minutes to kickoff
 				minutesToKickoff := int32(0)
 				if existing.EventDate.Valid {
-					duration := time.Until(existing.EventDate.Time)
+					duration := existing.EventDate.Time.Sub(s.now())
 					minutesToKickoff = int32(duration.Minutes())
 				}
 
@@ -383,43 +534,65 @@ func (s *EventsService) bulkProcessOdds(ctx context.Context, events []models.Idd
 				historySignificanceLevels = append(historySignificanceLevels, significanceLevel)
 				historyMinutesToKickoffs = append(historyMinutesToKickoffs, minutesToKickoff)
 				historyMarketParams = append(historyMarketParams, marketParams[i])
+				historyInPlay = append(historyInPlay, inPlay[i])
 			}
 		}
 	}
 
-	// Bulk insert history records if any
-	if len(historyEventIDs) > 0 {
-		const historyChunkSize = 500
-		for i := 0; i < len(historyEventIDs); i += historyChunkSize {
-			end := i + historyChunkSize
-			if end > len(historyEventIDs) {
-				end = len(historyEventIDs)
-			}
+	// Prices equal to the stored ones are not written at all
+	current, changedKeys := withoutUnchanged(generated.BulkUpsertCurrentOddsParams{
+		EventIds:      eventIDs,
+		MarketTypeIds: marketTypeIDs,
+		Outcomes:      outcomes,
+		OddsValues:    oddsValues,
+		MarketParams:  marketParams,
+		RecordedAt:    s.recordedAt(),
+	}, keys, func(i int) bool {
+		existing, exists := existingOddsMap[fmt.Sprintf("%d-%d-%s", eventIDs[i], marketTypeIDs[i], outcomes[i])]
+		return exists && existing.OddsValue == oddsValues[i]
+	})
+	if len(current.EventIds) == 0 {
+		return len(eventIDs), 0, nil
+	}
+	batch := OddsBatch{Current: current}
 
-			err := s.db.BulkInsertOddsHistory(ctx, generated.BulkInsertOddsHistoryParams{
-				EventIds:           historyEventIDs[i:end],
-				MarketTypeIds:      historyMarketTypeIDs[i:end],
-				Outcomes:           historyOutcomes[i:end],
-				OddsValues:         historyOddsValues[i:end],
-				PreviousValues:     historyPreviousValues[i:end],
-				ChangeAmounts:      historyChangeAmounts[i:end],
-				ChangePercentages:  historyChangePercentages[i:end],
-				Multipliers:        historyMultipliers[i:end],
-				IsReverseMovements: historyIsReverseMovements[i:end],
-				SignificanceLevels: historySignificanceLevels[i:end],
-				MinutesToKickoffs:  historyMinutesToKickoffs[i:end],
-				MarketParams:       historyMarketParams[i:end],
-			})
-			if err != nil {
-				s.logger.Error().
-					Err(err).
-					Int("history_chunk_size", end-i).
-					Msg("Failed to insert odds history")
+	// History records if any, in-play rows get the live score and minute
+	if len(historyEventIDs) > 0 {
+		prematch := generated.BulkInsertOddsHistoryParams{RecordedAt: s.recordedAt()}
+		live := generated.BulkInsertOddsHistoryParams{RecordedAt: s.recordedAt()}
+		for i := range historyEventIDs {
+			target := &prematch
+			if historyInPlay[i] {
+				target = &live
 			}
+			target.EventIds = append(target.EventIds, historyEventIDs[i])
+			target.MarketTypeIds = append(target.MarketTypeIds, historyMarketTypeIDs[i])
+			target.Outcomes = append(target.Outcomes, historyOutcomes[i])
+			target.OddsValues = append(target.OddsValues, historyOddsValues[i])
+			target.PreviousValues = append(target.PreviousValues, historyPreviousValues[i])
+			target.ChangeAmounts = append(target.ChangeAmounts, historyChangeAmounts[i])
+			target.ChangePercentages = append(target.ChangePercentages, historyChangePercentages[i])
+			target.Multipliers = append(target.Multipliers, historyMultipliers[i])
+			target.IsReverseMovements = append(target.IsReverseMovements, historyIsReverseMovements[i])
+			target.SignificanceLevels = append(target.SignificanceLevels, historySignificanceLevels[i])
+			target.MinutesToKickoffs = append(target.MinutesToKickoffs, historyMinutesToKickoffs[i])
+			target.MarketParams = append(target.MarketParams, historyMarketParams[i])
 		}
 
+		batch.History = prematch
+		batch.LiveHistory = live
+	}
+
+	if err := s.writeOdds(ctx, batch, changedKeys); err != nil {
+		s.logger.Error().
+			Err(err).
+			Int("odds_count", len(current.EventIds)).
+			Int("history_records", len(historyEventIDs)).
+			Msg("Failed to write odds")
+	} else if len(historyEventIDs) > 0 {
 		s.logger.Info().
 			Int("history_records", len(historyEventIDs)).
+			Int("in_play_records", len(batch.LiveHistory.EventIds)).
 			Msg("Created odds history records")
 	}
 
@@ -601,7 +774,7 @@ func (s *EventsService) ProcessDetailedMarkets(ctx context.Context, eventID int,
 // 	return nil
 // }
 
-// processMarketsBulk handles market processing using bulk UNNEST operations for maximum performance
+// processMarketsBulk handles market processing using bulk operations through the odds writer
 func (s *EventsService) processMarketsBulk(ctx context.Context, eventID int, markets []models.IddaaMarket) error {
 	// Prepare slices for bulk operations
 	var (
@@ -610,6 +783,7 @@ func (s *EventsService) processMarketsBulk(ctx context.Context, eventID int, mar
 		outcomes      []string
 		oddsValues    []float64
 		marketParams  [][]byte
+		keys          []OddsCacheKey
 
 		// For history tracking
 		histEventIDs      []int32
@@ -650,6 +824,7 @@ func (s *EventsService) processMarketsBulk(ctx context.Context, eventID int, mar
 			outcomes = append(outcomes, outcomeStr)
 			oddsValues = append(oddsValues, outcome.Odds)
 			marketParams = append(marketParams, paramsJSON)
+			keys = append(keys, OddsCacheKey{EventID: int32(eventID), MarketTypeID: marketTypeID, Outcome: outcomeStr})
 		}
 	}
 
@@ -658,12 +833,8 @@ func (s *EventsService) processMarketsBulk(ctx context.Context, eventID int, mar
 		return nil
 	}
 
-	// Get current odds for comparison (bulk query)
-	currentOddsRows, err := s.db.BulkGetCurrentOddsForComparison(ctx, generated.BulkGetCurrentOddsForComparisonParams{
-		EventIds:      eventIDs,
-		MarketTypeIds: marketTypeIDs,
-		Outcomes:      outcomes,
-	})
+	// Get current odds for comparison (odds cache or bulk query)
+	currentOddsRows, err := s.currentOddsForComparison(ctx, keys)
 	if err != nil {
 		s.logger.Warn().Err(err).Int("event_id", eventID).Msg("Failed to get current odds for comparison")
 	}
@@ -723,7 +894,7 @@ func (s *EventsService) processMarketsBulk(ctx context.Context, eventID int, mar
 				}
 
 				// Calculate minutes to kickoff
-				minutesToKO := int32(time.Until(currentOdd.EventDate.Time).Minutes())
+				minutesToKO := int32(currentOdd.EventDate.Time.Sub(s.now()).Minutes())
 
 				// Add to history arrays
 				histEventIDs = append(histEventIDs, eventIDs[i])
@@ -742,21 +913,30 @@ func (s *EventsService) processMarketsBulk(ctx context.Context, eventID int, mar
 		}
 	}
 
-	// Bulk upsert current odds
-	err = s.db.BulkUpsertCurrentOdds(ctx, generated.BulkUpsertCurrentOddsParams{
+	// Prices equal to the stored ones are not written at all
+	current, changedKeys := withoutUnchanged(generated.BulkUpsertCurrentOddsParams{
 		EventIds:      eventIDs,
 		MarketTypeIds: marketTypeIDs,
 		Outcomes:      outcomes,
 		OddsValues:    oddsValues,
 		MarketParams:  marketParams,
+		RecordedAt:    s.recordedAt(),
+	}, keys, func(i int) bool {
+		currentOdd, exists := currentOddsMap[oddsKey{eventID: eventIDs[i], marketTypeID: marketTypeIDs[i], outcome: outcomes[i]}]
+		return exists && currentOdd.OddsValue == oddsValues[i]
 	})
-	if err != nil {
-		return fmt.Errorf("failed to bulk upsert current odds: %w", err)
+	if len(current.EventIds) == 0 {
+		s.logger.Debug().
+			Int("event_id", eventID).
+			Int("total_odds", len(eventIDs)).
+			Msg("Event markets unchanged")
+		return nil
 	}
 
-	// Bulk insert history records if any odds changed
-	if len(histEventIDs) > 0 {
-		err = s.db.BulkInsertOddsHistory(ctx, generated.BulkInsertOddsHistoryParams{
+	// Upsert current odds and insert history records for the odds that changed
+	err = s.writeOdds(ctx, OddsBatch{
+		Current: current,
+		History: generated.BulkInsertOddsHistoryParams{
 			EventIds:           histEventIDs,
 			MarketTypeIds:      histMarketTypeIDs,
 			Outcomes:           histOutcomes,
@@ -769,14 +949,11 @@ func (s *EventsService) processMarketsBulk(ctx context.Context, eventID int, mar
 			SignificanceLevels: histSigLevels,
 			MinutesToKickoffs:  histMinutesToKO,
 			MarketParams:       histMarketParams,
-		})
-		if err != nil {
-			s.logger.Error().
-				Err(err).
-				Int("event_id", eventID).
-				Int("history_count", len(histEventIDs)).
-				Msg("Failed to bulk insert odds history")
-		}
+			RecordedAt:         s.recordedAt(),
+		},
+	}, changedKeys)
+	if err != nil {
+		return fmt.Errorf("failed to write odds for event %d: %w", eventID, err)
 	}
 
 	s.logger.Debug().
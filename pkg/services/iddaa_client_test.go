@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -87,7 +88,7 @@ func TestIddaaClient_GetCompetitions(t *testing.T) {
 			client := NewIddaaClient(cfg)
 			client.baseURL = server.URL
 
-			result, err := client.GetCompetitions()
+			result, err := client.GetCompetitions(context.Background())
 
 			if tt.wantError {
 				if err == nil {
@@ -171,7 +172,7 @@ func TestIddaaClient_GetEvents(t *testing.T) {
 	client := NewIddaaClient(cfg)
 	client.baseURL = server.URL
 
-	result, err := client.GetEvents(1) // Pass sport ID instead of competition ID
+	result, err := client.GetEvents(context.Background(), 1) // Pass sport ID instead of competition ID
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -199,3 +200,32 @@ func TestIddaaClient_GetEvents(t *testing.T) {
 		t.Errorf("Expected competition ID 123, got %d", event.CompetitionID)
 	}
 }
+
+func TestIddaaClient_GetEventsSince(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "st=1&type=0&version=1234" {
+			t.Errorf("Expected query st=1&type=0&version=1234, got %s", r.URL.RawQuery)
+		}
+
+		_ = json.NewEncoder(w).Encode(models.IddaaEventsResponse{
+			IsSuccess: true,
+			Data: &models.IddaaEventsData{
+				IsDiff:  true,
+				Version: 1240,
+				Events:  []models.IddaaEvent{{ID: 456}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewIddaaClient(&config.Config{External: config.ExternalAPIConfig{Timeout: 30}})
+	client.baseURL = server.URL
+
+	result, err := client.GetEventsSince(context.Background(), 1, 1234)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.Data.IsDiff || result.Data.Version != 1240 {
+		t.Errorf("Expected diff at version 1240, got isdiff=%v version=%d", result.Data.IsDiff, result.Data.Version)
+	}
+}
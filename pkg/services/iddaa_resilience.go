@@ -0,0 +1,485 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/logger"
+)
+
+// ErrCircuitOpen is returned without contacting Iddaa while a host's circuit breaker is open
+var ErrCircuitOpen = errors.New("iddaa circuit breaker open")
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+// HostBudget is the request rate allowed against one Iddaa host
+type HostBudget struct {
+	// Requests per second refilled into the bucket
+	Rate float64
+	// Requests that may be sent back to back after a quiet period
+	Burst int
+}
+
+// ResilienceConfig controls retries, rate budgets and the circuit breaker of the Iddaa transport
+type ResilienceConfig struct {
+	// Retries after the first attempt for 429, 5xx and network errors
+	MaxRetries int
+	// Backoff before the first retry, doubled per attempt with full jitter
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Token buckets per host; hosts without a budget are not limited
+	Budgets map[string]HostBudget
+	// Consecutive failed requests that open a host's circuit
+	FailureThreshold int
+	// Time an open circuit rejects requests before letting a probe through
+	Cooldown time.Duration
+}
+
+// DefaultResilienceConfig returns budgets sized for the bulletin, detailed odds and statistics jobs
+func DefaultResilienceConfig() ResilienceConfig {
+	return ResilienceConfig{
+		MaxRetries:  3,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  10 * time.Second,
+		Budgets: map[string]HostBudget{
+			"sportsbookv2.iddaa.com": {Rate: 10, Burst: 20},
+			"contentv2.iddaa.com":    {Rate: 2, Burst: 5},
+			"statisticsv2.iddaa.com": {Rate: 5, Burst: 10},
+		},
+		FailureThreshold: 5,
+		Cooldown:         30 * time.Second,
+	}
+}
+
+// ResilientTransport rate limits, retries and circuit breaks requests per Iddaa host.
+// Waits and backoffs stop when the request context is done.
+type ResilientTransport struct {
+	config ResilienceConfig
+	next   http.RoundTripper
+	logger *logger.Logger
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+// NewResilientTransport wraps next with the given retry, budget and breaker settings
+func NewResilientTransport(config ResilienceConfig, next http.RoundTripper) *ResilientTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &ResilientTransport{
+		config: config,
+		next:   next,
+		logger: logger.New("iddaa-transport"),
+		hosts:  make(map[string]*hostState),
+	}
+}
+
+func (t *ResilientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := t.host(req.URL.Hostname())
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if err := host.allow(time.Now()); err != nil {
+			return nil, err
+		}
+		if err := host.bucket.wait(ctx, host.metrics); err != nil {
+			host.release()
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := t.next.RoundTrip(req)
+		// The caller gave up, which says nothing about the host: no failure and no retry
+		if ctx.Err() != nil {
+			host.release()
+			return resp, err
+		}
+		retryable := isRetryable(resp, err)
+		host.record(time.Since(start), resp, err, retryable)
+
+		if !retryable || attempt >= t.config.MaxRetries {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt, resp)
+		t.logger.Debug().
+			Str("host", host.name).
+			Int("attempt", attempt+1).
+			Dur("delay", delay).
+			Err(err).
+			Msg("Retrying Iddaa request")
+
+		// The body of a response we are about to retry is never read by the caller
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		host.metrics.retry()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the pause before retry attempt+1, honouring Retry-After when Iddaa sends one
+func (t *ResilientTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			if delay := time.Duration(seconds) * time.Second; delay < t.config.MaxBackoff {
+				return delay
+			}
+			return t.config.MaxBackoff
+		}
+	}
+
+	ceiling := t.config.BaseBackoff << attempt
+	if ceiling <= 0 || ceiling > t.config.MaxBackoff {
+		ceiling = t.config.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	// Full jitter keeps parallel jobs from retrying in lockstep
+	return time.Duration(rand.Int63n(int64(ceiling)) + 1)
+}
+
+func (t *ResilientTransport) host(name string) *hostState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if state, ok := t.hosts[name]; ok {
+		return state
+	}
+	state := &hostState{
+		name:      name,
+		threshold: t.config.FailureThreshold,
+		cooldown:  t.config.Cooldown,
+		state:     CircuitClosed,
+		logger:    t.logger,
+		metrics:   upstreamMetrics.host(name),
+	}
+	if budget, ok := t.config.Budgets[name]; ok && budget.Rate > 0 {
+		state.bucket = newTokenBucket(budget.Rate, budget.Burst)
+	}
+	t.hosts[name] = state
+	return state
+}
+
+// isRetryable reports whether a request failed in a way worth retrying: network errors, 429 and 5xx.
+// Attempts the caller cancelled never get here.
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// hostState holds the circuit breaker and token bucket of one host
+type hostState struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	bucket    *tokenBucket
+	logger    *logger.Logger
+	metrics   *hostMetrics
+
+	mu          sync.Mutex
+	state       string
+	failures    int
+	openedAt    time.Time
+	probeActive bool
+}
+
+// allow rejects requests while the circuit is open and lets a single probe through once the cooldown passed
+func (h *hostState) allow(now time.Time) error {
+	if h.threshold <= 0 {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch h.state {
+	case CircuitOpen:
+		if now.Sub(h.openedAt) < h.cooldown {
+			h.metrics.reject()
+			return fmt.Errorf("%w for %s", ErrCircuitOpen, h.name)
+		}
+		h.setState(CircuitHalfOpen)
+		h.probeActive = true
+	case CircuitHalfOpen:
+		if h.probeActive {
+			h.metrics.reject()
+			return fmt.Errorf("%w for %s", ErrCircuitOpen, h.name)
+		}
+		h.probeActive = true
+	}
+	return nil
+}
+
+// release frees the half-open probe slot of a request that was never sent
+func (h *hostState) release() {
+	h.mu.Lock()
+	h.probeActive = false
+	h.mu.Unlock()
+}
+
+// record updates the breaker and metrics with the outcome of one attempt
+func (h *hostState) record(latency time.Duration, resp *http.Response, err error, failed bool) {
+	h.metrics.observe(latency, resp, err, failed)
+	if h.threshold <= 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.probeActive = false
+	if !failed {
+		h.failures = 0
+		if h.state != CircuitClosed {
+			h.setState(CircuitClosed)
+		}
+		return
+	}
+
+	h.failures++
+	if h.state == CircuitHalfOpen || h.failures >= h.threshold {
+		h.openedAt = time.Now()
+		if h.state != CircuitOpen {
+			h.metrics.opened()
+		}
+		h.setState(CircuitOpen)
+	}
+}
+
+func (h *hostState) setState(state string) {
+	if h.state == state {
+		return
+	}
+	h.logger.Warn().
+		Str("host", h.name).
+		Str("from", h.state).
+		Str("to", state).
+		Int("consecutive_failures", h.failures).
+		Msg("Iddaa circuit breaker state changed")
+	h.state = state
+	h.metrics.setState(state)
+}
+
+// tokenBucket is a blocking rate limiter refilled continuously at rate tokens per second
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// reserve takes a token and returns how long the caller must wait before using it
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// wait blocks until a token is available. A nil bucket never blocks.
+func (b *tokenBucket) wait(ctx context.Context, metrics *hostMetrics) error {
+	if b == nil {
+		return nil
+	}
+	delay := b.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+	metrics.throttle(delay)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		// Hand the token back so cancelled callers do not slow down the rest
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// UpstreamHealth is a snapshot of the requests sent to one Iddaa host
+type UpstreamHealth struct {
+	Host                string    `json:"host"`
+	Circuit             string    `json:"circuit"`
+	Requests            int64     `json:"requests"`
+	Failures            int64     `json:"failures"`
+	ConsecutiveFailures int64     `json:"consecutive_failures"`
+	Retries             int64     `json:"retries"`
+	RateLimited         int64     `json:"rate_limited"`
+	Rejected            int64     `json:"rejected"`
+	CircuitOpens        int64     `json:"circuit_opens"`
+	Throttled           int64     `json:"throttled"`
+	ThrottledSeconds    float64   `json:"throttled_seconds"`
+	AvgLatencyMs        float64   `json:"avg_latency_ms"`
+	LastStatus          int       `json:"last_status,omitempty"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastSuccessAt       time.Time `json:"last_success_at"`
+	LastFailureAt       time.Time `json:"last_failure_at"`
+}
+
+// IddaaUpstreamHealth returns the health of every Iddaa host contacted by this process, also published
+// through expvar as iddaa_upstream
+func IddaaUpstreamHealth() []UpstreamHealth {
+	return upstreamMetrics.snapshot()
+}
+
+var upstreamMetrics = newMetricsRegistry()
+
+func init() {
+	expvar.Publish("iddaa_upstream", expvar.Func(func() any { return IddaaUpstreamHealth() }))
+}
+
+type metricsRegistry struct {
+	mu    sync.Mutex
+	hosts map[string]*hostMetrics
+}
+
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{hosts: make(map[string]*hostMetrics)}
+}
+
+// host returns the metrics of a host; clients sharing a host share its metrics
+func (r *metricsRegistry) host(name string) *hostMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if m, ok := r.hosts[name]; ok {
+		return m
+	}
+	m := &hostMetrics{health: UpstreamHealth{Host: name, Circuit: CircuitClosed}}
+	r.hosts[name] = m
+	return m
+}
+
+func (r *metricsRegistry) snapshot() []UpstreamHealth {
+	r.mu.Lock()
+	hosts := make([]*hostMetrics, 0, len(r.hosts))
+	for _, m := range r.hosts {
+		hosts = append(hosts, m)
+	}
+	r.mu.Unlock()
+
+	health := make([]UpstreamHealth, len(hosts))
+	for i, m := range hosts {
+		health[i] = m.snapshot()
+	}
+	sort.Slice(health, func(i, j int) bool { return health[i].Host < health[j].Host })
+	return health
+}
+
+type hostMetrics struct {
+	mu           sync.Mutex
+	health       UpstreamHealth
+	totalLatency time.Duration
+}
+
+func (m *hostMetrics) observe(latency time.Duration, resp *http.Response, err error, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.health.Requests++
+	m.totalLatency += latency
+	m.health.AvgLatencyMs = float64(m.totalLatency.Milliseconds()) / float64(m.health.Requests)
+	m.health.LastStatus = 0
+	if resp != nil {
+		m.health.LastStatus = resp.StatusCode
+		if resp.StatusCode == http.StatusTooManyRequests {
+			m.health.RateLimited++
+		}
+	}
+
+	if !failed {
+		m.health.ConsecutiveFailures = 0
+		m.health.LastSuccessAt = now
+		return
+	}
+	m.health.Failures++
+	m.health.ConsecutiveFailures++
+	m.health.LastFailureAt = now
+	if err != nil {
+		m.health.LastError = err.Error()
+	} else {
+		m.health.LastError = resp.Status
+	}
+}
+
+func (m *hostMetrics) retry() {
+	m.mu.Lock()
+	m.health.Retries++
+	m.mu.Unlock()
+}
+
+func (m *hostMetrics) reject() {
+	m.mu.Lock()
+	m.health.Rejected++
+	m.mu.Unlock()
+}
+
+func (m *hostMetrics) opened() {
+	m.mu.Lock()
+	m.health.CircuitOpens++
+	m.mu.Unlock()
+}
+
+func (m *hostMetrics) setState(state string) {
+	m.mu.Lock()
+	m.health.Circuit = state
+	m.mu.Unlock()
+}
+
+func (m *hostMetrics) throttle(delay time.Duration) {
+	m.mu.Lock()
+	m.health.Throttled++
+	m.health.ThrottledSeconds += delay.Seconds()
+	m.mu.Unlock()
+}
+
+func (m *hostMetrics) snapshot() UpstreamHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.health
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testResilienceConfig() ResilienceConfig {
+	return ResilienceConfig{
+		MaxRetries:       3,
+		BaseBackoff:      time.Millisecond,
+		MaxBackoff:       5 * time.Millisecond,
+		Budgets:          map[string]HostBudget{},
+		FailureThreshold: 10,
+		Cooldown:         time.Minute,
+	}
+}
+
+func TestResilientTransport_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewResilientTransport(testResilienceConfig(), nil)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200 after retries", resp.StatusCode)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("server saw %d requests, want 3", calls.Load())
+	}
+}
+
+func TestResilientTransport_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewResilientTransport(testResilienceConfig(), nil)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if calls.Load() != 1 {
+		t.Errorf("server saw %d requests, want 1", calls.Load())
+	}
+}
+
+func TestResilientTransport_CircuitBreaker(t *testing.T) {
+	var (
+		calls   atomic.Int32
+		healthy atomic.Bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	config := testResilienceConfig()
+	config.MaxRetries = 0
+	config.FailureThreshold = 2
+	config.Cooldown = 20 * time.Millisecond
+	client := &http.Client{Transport: NewResilientTransport(config, nil)}
+
+	get := func() error {
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	// Two failures open the circuit
+	for i := 0; i < 2; i++ {
+		if err := get(); err != nil {
+			t.Fatalf("request %d failed: %v", i+1, err)
+		}
+	}
+	if err := get(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("error = %v, want ErrCircuitOpen", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("server saw %d requests, want 2 while the circuit is open", calls.Load())
+	}
+
+	// After the cooldown a successful probe closes the circuit again
+	healthy.Store(true)
+	time.Sleep(30 * time.Millisecond)
+	if err := get(); err != nil {
+		t.Fatalf("probe failed: %v", err)
+	}
+	if err := get(); err != nil {
+		t.Fatalf("request after recovery failed: %v", err)
+	}
+
+	var found bool
+	for _, health := range IddaaUpstreamHealth() {
+		if health.Host == "127.0.0.1" {
+			found = true
+			if health.Circuit != CircuitClosed || health.CircuitOpens < 1 || health.Rejected < 1 {
+				t.Errorf("health = %+v, want a closed circuit that opened and rejected before", health)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected upstream health for the test host")
+	}
+}
+
+func TestResilientTransport_StopsWaitingOnCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	config := testResilienceConfig()
+	config.BaseBackoff = time.Minute
+	config.MaxBackoff = time.Minute
+	client := &http.Client{Transport: NewResilientTransport(config, nil)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+
+	start := time.Now()
+	_, err := client.Do(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("request returned after %s, want it to stop at the deadline", elapsed)
+	}
+}
+
+func TestResilientTransport_CancelDoesNotTripBreaker(t *testing.T) {
+	var (
+		calls atomic.Int32
+		slow  atomic.Bool
+	)
+	slow.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if slow.Load() {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}))
+	defer server.Close()
+
+	config := testResilienceConfig()
+	config.FailureThreshold = 1
+	client := &http.Client{Transport: NewResilientTransport(config, nil)}
+
+	// Requests abandoned by their callers are neither retried nor counted as failures
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("request %d: error = %v, want context.DeadlineExceeded", i+1, err)
+		}
+		cancel()
+	}
+	if calls.Load() != 2 {
+		t.Errorf("server saw %d requests, want 2 without retries", calls.Load())
+	}
+
+	slow.Store(false)
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request after cancellations failed: %v, want the circuit closed", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	bucket := newTokenBucket(10, 2)
+	now := bucket.last
+
+	if delay := bucket.reserve(now); delay != 0 {
+		t.Errorf("first token delay = %s, want 0", delay)
+	}
+	if delay := bucket.reserve(now); delay != 0 {
+		t.Errorf("second token delay = %s, want 0 within the burst", delay)
+	}
+	if delay := bucket.reserve(now); delay != 100*time.Millisecond {
+		t.Errorf("third token delay = %s, want 100ms at 10 requests per second", delay)
+	}
+
+	// A quiet second refills up to the burst, not beyond
+	later := now.Add(time.Second)
+	for i := 0; i < 2; i++ {
+		if delay := bucket.reserve(later); delay != 0 {
+			t.Errorf("token %d after refill delay = %s, want 0", i+1, delay)
+		}
+	}
+	if delay := bucket.reserve(later); delay <= 0 {
+		t.Errorf("token beyond the burst delay = %s, want a wait", delay)
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/iddaa-lens/core/internal/config"
+	"github.com/iddaa-lens/core/pkg/archive"
+	"github.com/iddaa-lens/core/pkg/iddaamock"
+)
+
+// mockTransport redirects the client to mockURL and records into recordDir when set, as cmd/cron does
+func mockTransport(t *testing.T, mockURL, recordDir string) IddaaTransport {
+	t.Helper()
+	mock, err := iddaamock.NewMockTransport(mockURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := IddaaTransport{Upstream: mock}
+	if recordDir != "" {
+		transport.Wrap = func(next http.RoundTripper) http.RoundTripper {
+			return iddaamock.NewRecordingTransport(recordDir, next)
+		}
+	}
+	return transport
+}
+
+func TestIddaaClient_RecordAndReplay(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"isSuccess":true,"data":[{"i":1,"lc":2}],"message":""}`))
+	}))
+	defer upstream.Close()
+
+	// Record: requests to the real host names are redirected to the fake upstream
+	dir := t.TempDir()
+	recorder := NewIddaaClientWithTransport(&config.Config{
+		External: config.ExternalAPIConfig{Timeout: 30},
+	}, mockTransport(t, upstream.URL, dir))
+	if _, err := recorder.GetSportInfo(context.Background()); err != nil {
+		t.Fatalf("recording request failed: %v", err)
+	}
+
+	store, err := iddaamock.LoadStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixture, ok := store.Get("/sportsbook/info", url.Values{})
+	if !ok {
+		t.Fatal("expected a fixture for /sportsbook/info")
+	}
+	if fixture.Host != "sportsbookv2.iddaa.com" {
+		t.Errorf("fixture host = %s, want the real Iddaa host", fixture.Host)
+	}
+
+	// Replay the recording through the mock server
+	mock := httptest.NewServer(iddaamock.NewServer(store, iddaamock.Options{}))
+	defer mock.Close()
+
+	client := NewIddaaClientWithTransport(&config.Config{
+		External: config.ExternalAPIConfig{Timeout: 30},
+	}, mockTransport(t, mock.URL, ""))
+	info, err := client.GetSportInfo(context.Background())
+	if err != nil {
+		t.Fatalf("replayed request failed: %v", err)
+	}
+	if len(info.Data) != 1 || info.Data[0].LiveCount != 2 {
+		t.Errorf("replayed sport info = %+v", info.Data)
+	}
+}
+
+func TestIddaaClient_ArchivesPayloads(t *testing.T) {
+	payload := `{"isSuccess":true,"data":{"123":4.5},"message":""}`
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer upstream.Close()
+
+	dir := t.TempDir()
+	client := NewIddaaClientWithTransport(&config.Config{
+		External: config.ExternalAPIConfig{Timeout: 30},
+		Archive:  config.ArchiveConfig{Dir: dir},
+	}, mockTransport(t, upstream.URL, ""))
+	data, err := client.FetchData(context.Background(), "https://sportsbookv2.iddaa.com/sportsbook/played-event-percentage?sportType=1")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if string(data) != payload {
+		t.Errorf("caller got %s, want the untouched payload", data)
+	}
+
+	payloads, err := archive.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := payloads.Entries(archive.Filter{Source: archive.SourceIddaa})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("archived %d payloads, want 1", len(entries))
+	}
+	if PayloadKind(entries[0]) != PayloadVolumes || entries[0].SportID != 1 {
+		t.Errorf("entry = %+v, want a sport 1 volume payload", entries[0])
+	}
+	stored, err := payloads.Get(entries[0].Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(stored) != payload {
+		t.Errorf("archived %s, want %s", stored, payload)
+	}
+}
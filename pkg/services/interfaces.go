@@ -13,9 +13,9 @@ type CompetitionSyncer interface {
 
 // IddaaClientInterface defines the interface for Iddaa API client
 type IddaaClientInterface interface {
-	GetSingleEvent(eventID int) (*models.IddaaSingleEventResponse, error)
-	GetSportInfo() (*models.IddaaAPIResponse[models.IddaaSportInfo], error)
-	GetEvents(sportID int) (*models.IddaaEventsResponse, error)
+	GetSingleEvent(ctx context.Context, eventID int) (*models.IddaaSingleEventResponse, error)
+	GetSportInfo(ctx context.Context) (*models.IddaaAPIResponse[models.IddaaSportInfo], error)
+	GetEvents(ctx context.Context, sportID int) (*models.IddaaEventsResponse, error)
 }
 
 // EventsServiceInterface defines the interface for events service
@@ -48,7 +48,7 @@ func (s *LeaguesService) SyncLeaguesFromIddaa(ctx context.Context) error {
 	}
 
 	// Fetch competitions from Iddaa API
-	response, err := s.iddaaClient.GetCompetitions()
+	response, err := s.iddaaClient.GetCompetitions(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch competitions: %w", err)
 	}
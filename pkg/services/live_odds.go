@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+// LiveOddsStats summarizes one batch of in-play events
+type LiveOddsStats struct {
+	Events         int
+	UnknownEvents  int
+	OddsProcessed  int
+	HistoryCreated int
+	Suspended      int
+	Resumed        int
+}
+
+// ProcessLiveEvents applies in-play odds from GetLiveEvents. Outcomes of markets that are not
+// open are flagged suspended and keep their last price; open markets are resumed and their
+// changes recorded as in-play odds history. Events not yet synced are skipped.
+func (s *EventsService) ProcessLiveEvents(ctx context.Context, events []models.IddaaEvent) (LiveOddsStats, error) {
+	stats := LiveOddsStats{Events: len(events)}
+	if len(events) == 0 {
+		return stats, nil
+	}
+
+	externalIDs := make([]string, len(events))
+	for i, event := range events {
+		externalIDs[i] = strconv.Itoa(event.ID)
+	}
+	known, err := s.db.GetEventIDsByExternalIDs(ctx, externalIDs)
+	if err != nil {
+		return stats, fmt.Errorf("failed to look up live events: %w", err)
+	}
+	eventMapping := make(map[string]int32, len(known))
+	for _, event := range known {
+		eventMapping[event.ExternalID] = event.ID
+	}
+
+	var suspension generated.BulkSetOddsSuspendedParams
+	open := make([]models.IddaaEvent, 0, len(events))
+	for _, event := range events {
+		eventID, ok := eventMapping[strconv.Itoa(event.ID)]
+		if !ok {
+			stats.UnknownEvents++
+			continue
+		}
+
+		openMarkets := make([]models.IddaaMarket, 0, len(event.Markets))
+		for _, market := range event.Markets {
+			marketTypeID, exists := s.marketTypes[fmt.Sprintf("%d_%d", market.Type, market.SubType)]
+			if !exists {
+				continue
+			}
+			for _, outcome := range market.Outcomes {
+				suspension.EventIds = append(suspension.EventIds, eventID)
+				suspension.MarketTypeIds = append(suspension.MarketTypeIds, marketTypeID)
+				suspension.Outcomes = append(suspension.Outcomes, s.formatOutcomeName(outcome.Name, market.SubType, market.SpecialValue))
+				suspension.Suspended = append(suspension.Suspended, !market.IsOpen())
+			}
+			if market.IsOpen() {
+				openMarkets = append(openMarkets, market)
+			}
+		}
+
+		// Live events are in play even if the feed flag lags behind
+		event.IsLive = true
+		event.Markets = openMarkets
+		open = append(open, event)
+	}
+
+	if len(suspension.EventIds) > 0 {
+		suspension.RecordedAt = s.recordedAt()
+		changed, err := s.db.BulkSetOddsSuspended(ctx, suspension)
+		if err != nil {
+			return stats, fmt.Errorf("failed to update market suspension: %w", err)
+		}
+		for _, suspended := range changed {
+			if suspended {
+				stats.Suspended++
+			} else {
+				stats.Resumed++
+			}
+		}
+	}
+
+	stats.OddsProcessed, stats.HistoryCreated, err = s.bulkProcessOdds(ctx, open, eventMapping)
+	if err != nil {
+		return stats, fmt.Errorf("failed to process live odds: %w", err)
+	}
+
+	return stats, nil
+}
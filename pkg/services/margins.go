@@ -0,0 +1,409 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+// DevigMethod names a way of removing the bookmaker margin from implied probabilities
+type DevigMethod string
+
+const (
+	// DevigMultiplicative scales every implied probability by the same factor
+	DevigMultiplicative DevigMethod = "multiplicative"
+	// DevigAdditive subtracts an equal share of the overround from every outcome
+	DevigAdditive DevigMethod = "additive"
+	// DevigPower raises implied probabilities to the power that makes them sum to one
+	DevigPower DevigMethod = "power"
+	// DevigShin assumes part of the margin protects against insiders, loading longshots more than favourites
+	DevigShin DevigMethod = "shin"
+)
+
+const (
+	// maxMarginBooksum rejects markets whose outcomes are not exclusive or were read incompletely
+	maxMarginBooksum = 1.5
+	devigIterations  = 100
+	devigTolerance   = 1e-10
+)
+
+// FairPrices holds the margin and the fair probabilities of one market's outcomes, in outcome order
+type FairPrices struct {
+	// Booksum is the sum of the implied probabilities, 1 plus the overround
+	Booksum        float64
+	Implied        []float64
+	Multiplicative []float64
+	// Additive is nil when removing the margin evenly leaves an outcome without probability
+	Additive []float64
+	// Power and Shin are nil when the method has no solution; PowerK and ShinZ are then NaN
+	Power  []float64
+	PowerK float64
+	Shin   []float64
+	ShinZ  float64
+}
+
+// MarginPercentage is the overround in percent
+func (f FairPrices) MarginPercentage() float64 {
+	return (f.Booksum - 1) * 100
+}
+
+// Fair returns the default fair probabilities: Shin, falling back to multiplicative
+func (f FairPrices) Fair() []float64 {
+	if f.Shin != nil {
+		return f.Shin
+	}
+	return f.Multiplicative
+}
+
+// Devig computes fair probabilities of a market from its decimal odds.
+// It returns false for markets that cannot be priced: fewer than two outcomes, odds not above 1,
+// or a booksum below 1 or so large the outcomes cannot be one exclusive market.
+func Devig(odds []float64) (FairPrices, bool) {
+	if len(odds) < 2 {
+		return FairPrices{}, false
+	}
+
+	prices := FairPrices{
+		Implied: make([]float64, len(odds)),
+		PowerK:  math.NaN(),
+		ShinZ:   math.NaN(),
+	}
+	for i, o := range odds {
+		if o <= 1 || math.IsNaN(o) || math.IsInf(o, 0) {
+			return FairPrices{}, false
+		}
+		prices.Implied[i] = 1 / o
+		prices.Booksum += prices.Implied[i]
+	}
+	if prices.Booksum < 1 || prices.Booksum > maxMarginBooksum {
+		return FairPrices{}, false
+	}
+
+	prices.Multiplicative = devigMultiplicative(prices.Implied, prices.Booksum)
+	prices.Additive = devigAdditive(prices.Implied, prices.Booksum)
+	if fair, k, ok := devigPower(prices.Implied); ok {
+		prices.Power, prices.PowerK = fair, k
+	}
+	if fair, z, ok := devigShin(prices.Implied, prices.Booksum); ok {
+		prices.Shin, prices.ShinZ = fair, z
+	}
+
+	return prices, true
+}
+
+func devigMultiplicative(implied []float64, booksum float64) []float64 {
+	fair := make([]float64, len(implied))
+	for i, p := range implied {
+		fair[i] = p / booksum
+	}
+	return fair
+}
+
+func devigAdditive(implied []float64, booksum float64) []float64 {
+	share := (booksum - 1) / float64(len(implied))
+	fair := make([]float64, len(implied))
+	for i, p := range implied {
+		fair[i] = p - share
+		if fair[i] <= 0 {
+			return nil
+		}
+	}
+	return fair
+}
+
+// devigPower solves sum(p^k) = 1 for k by bisection; the sum falls as k grows since every p is below 1
+func devigPower(implied []float64) ([]float64, float64, bool) {
+	sum := func(k float64) float64 {
+		total := 0.0
+		for _, p := range implied {
+			total += math.Pow(p, k)
+		}
+		return total
+	}
+
+	low, high := 1.0, 2.0
+	for sum(high) > 1 {
+		high *= 2
+		if high > 1e6 {
+			return nil, math.NaN(), false
+		}
+	}
+	k := bisect(low, high, func(k float64) bool { return sum(k) > 1 })
+
+	fair := make([]float64, len(implied))
+	for i, p := range implied {
+		fair[i] = math.Pow(p, k)
+	}
+	return normalize(fair), k, true
+}
+
+// devigShin solves Shin's model for the insider share z by bisection.
+// With booksum B, fair p_i = (sqrt(z^2 + 4(1-z) pi_i^2 / B) - z) / (2(1-z)), whose sum falls from sqrt(B) at z = 0.
+func devigShin(implied []float64, booksum float64) ([]float64, float64, bool) {
+	if booksum == 1 {
+		return devigMultiplicative(implied, booksum), 0, true
+	}
+
+	probabilities := func(z float64) []float64 {
+		fair := make([]float64, len(implied))
+		for i, p := range implied {
+			fair[i] = (math.Sqrt(z*z+4*(1-z)*p*p/booksum) - z) / (2 * (1 - z))
+		}
+		return fair
+	}
+	sum := func(z float64) float64 {
+		total := 0.0
+		for _, p := range probabilities(z) {
+			total += p
+		}
+		return total
+	}
+
+	const maxZ = 0.999
+	if sum(maxZ) > 1 {
+		return nil, math.NaN(), false
+	}
+	z := bisect(0, maxZ, func(z float64) bool { return sum(z) > 1 })
+
+	return normalize(probabilities(z)), z, true
+}
+
+// bisect finds the boundary in [low, high] where above turns false
+func bisect(low, high float64, above func(float64) bool) float64 {
+	for i := 0; i < devigIterations && high-low > devigTolerance; i++ {
+		mid := (low + high) / 2
+		if above(mid) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
+
+// normalize removes the bisection residue so probabilities sum to exactly one
+func normalize(values []float64) []float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	for i := range values {
+		values[i] /= total
+	}
+	return values
+}
+
+// MarginService computes bookmaker margins and fair probabilities from current odds
+type MarginService struct {
+	db     *generated.Queries
+	logger *logger.Logger
+}
+
+// NewMarginService creates a new margin service
+func NewMarginService(db *generated.Queries) *MarginService {
+	return &MarginService{
+		db:     db,
+		logger: logger.New("margin-service"),
+	}
+}
+
+// MarginStats summarizes a margin refresh
+type MarginStats struct {
+	Events  int
+	Markets int
+	// Skipped counts markets with a suspended outcome or prices Devig rejects
+	Skipped int
+	// Changed counts markets whose margin was new or moved, each written to market_margin_history
+	Changed int64
+}
+
+// marketKey identifies one priced market: an event's market type at one bookmaker and line
+type marketKey struct {
+	eventID      int32
+	marketTypeID int32
+	bookmaker    string
+	params       string
+}
+
+type marketPrices struct {
+	outcomes  []string
+	odds      []float64
+	suspended bool
+}
+
+// Refresh recomputes margins and fair probabilities of every event whose odds changed since the given time
+func (s *MarginService) Refresh(ctx context.Context, since time.Time) (MarginStats, error) {
+	var stats MarginStats
+
+	rows, err := s.db.GetOddsForMargins(ctx, pgtype.Timestamp{Time: since, Valid: true})
+	if err != nil {
+		return stats, fmt.Errorf("failed to get odds for margins: %w", err)
+	}
+	if len(rows) == 0 {
+		return stats, nil
+	}
+
+	markets, order := groupMarkets(rows)
+	events := make(map[int32]bool)
+
+	var (
+		margins generated.BulkUpsertMarketMarginsParams
+		fair    generated.BulkUpsertFairProbabilitiesParams
+	)
+	for _, key := range order {
+		market := markets[key]
+		events[key.eventID] = true
+		if market.suspended {
+			stats.Skipped++
+			continue
+		}
+		prices, ok := Devig(market.odds)
+		if !ok {
+			stats.Skipped++
+			continue
+		}
+		stats.Markets++
+
+		margins.EventIds = append(margins.EventIds, key.eventID)
+		margins.MarketTypeIds = append(margins.MarketTypeIds, key.marketTypeID)
+		margins.Bookmakers = append(margins.Bookmakers, key.bookmaker)
+		margins.MarketKeys = append(margins.MarketKeys, key.params)
+		margins.OutcomeCounts = append(margins.OutcomeCounts, int32(len(market.odds)))
+		margins.Booksums = append(margins.Booksums, prices.Booksum)
+		margins.MarginPercentages = append(margins.MarginPercentages, prices.MarginPercentage())
+		margins.PowerKs = append(margins.PowerKs, prices.PowerK)
+		margins.ShinZs = append(margins.ShinZs, prices.ShinZ)
+
+		defaults := prices.Fair()
+		for i, outcome := range market.outcomes {
+			fair.EventIds = append(fair.EventIds, key.eventID)
+			fair.MarketTypeIds = append(fair.MarketTypeIds, key.marketTypeID)
+			fair.Bookmakers = append(fair.Bookmakers, key.bookmaker)
+			fair.MarketKeys = append(fair.MarketKeys, key.params)
+			fair.Outcomes = append(fair.Outcomes, outcome)
+			fair.OddsValues = append(fair.OddsValues, market.odds[i])
+			fair.ImpliedProbabilities = append(fair.ImpliedProbabilities, prices.Implied[i]*100)
+			fair.Multiplicative = append(fair.Multiplicative, prices.Multiplicative[i]*100)
+			fair.Additive = append(fair.Additive, percentOrNaN(prices.Additive, i))
+			fair.Power = append(fair.Power, percentOrNaN(prices.Power, i))
+			fair.Shin = append(fair.Shin, percentOrNaN(prices.Shin, i))
+			fair.FairOdds = append(fair.FairOdds, 1/defaults[i])
+		}
+	}
+	stats.Events = len(events)
+
+	changed, err := s.writeMargins(ctx, margins)
+	stats.Changed = changed
+	if err != nil {
+		return stats, err
+	}
+	if err := s.writeFairProbabilities(ctx, fair); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// groupMarkets splits current odds into markets, keeping the order rows were read in
+func groupMarkets(rows []generated.GetOddsForMarginsRow) (map[marketKey]*marketPrices, []marketKey) {
+	markets := make(map[marketKey]*marketPrices)
+	var order []marketKey
+	for _, row := range rows {
+		if row.EventID == nil || row.MarketTypeID == nil {
+			continue
+		}
+		var params models.MarketParams
+		if len(row.MarketParams) > 0 {
+			_ = json.Unmarshal(row.MarketParams, &params)
+		}
+		key := marketKey{
+			eventID:      *row.EventID,
+			marketTypeID: *row.MarketTypeID,
+			bookmaker:    row.Bookmaker,
+			params:       strings.Join(params.Values, "|"),
+		}
+		market, ok := markets[key]
+		if !ok {
+			market = &marketPrices{}
+			markets[key] = market
+			order = append(order, key)
+		}
+		market.outcomes = append(market.outcomes, row.Outcome)
+		market.odds = append(market.odds, row.OddsValue)
+		market.suspended = market.suspended || row.IsSuspended
+	}
+	return markets, order
+}
+
+func percentOrNaN(values []float64, i int) float64 {
+	if values == nil {
+		return math.NaN()
+	}
+	return values[i] * 100
+}
+
+const marginChunkSize = 1000
+
+func (s *MarginService) writeMargins(ctx context.Context, all generated.BulkUpsertMarketMarginsParams) (int64, error) {
+	var changed int64
+	for i := 0; i < len(all.EventIds); i += marginChunkSize {
+		end := i + marginChunkSize
+		if end > len(all.EventIds) {
+			end = len(all.EventIds)
+		}
+
+		rows, err := s.db.BulkUpsertMarketMargins(ctx, generated.BulkUpsertMarketMarginsParams{
+			EventIds:          all.EventIds[i:end],
+			MarketTypeIds:     all.MarketTypeIds[i:end],
+			Bookmakers:        all.Bookmakers[i:end],
+			MarketKeys:        all.MarketKeys[i:end],
+			OutcomeCounts:     all.OutcomeCounts[i:end],
+			Booksums:          all.Booksums[i:end],
+			MarginPercentages: all.MarginPercentages[i:end],
+			PowerKs:           all.PowerKs[i:end],
+			ShinZs:            all.ShinZs[i:end],
+		})
+		if err != nil {
+			return changed, fmt.Errorf("failed to upsert market margins: %w", err)
+		}
+		changed += rows
+	}
+	return changed, nil
+}
+
+func (s *MarginService) writeFairProbabilities(ctx context.Context, all generated.BulkUpsertFairProbabilitiesParams) error {
+	for i := 0; i < len(all.EventIds); i += marginChunkSize {
+		end := i + marginChunkSize
+		if end > len(all.EventIds) {
+			end = len(all.EventIds)
+		}
+
+		err := s.db.BulkUpsertFairProbabilities(ctx, generated.BulkUpsertFairProbabilitiesParams{
+			EventIds:             all.EventIds[i:end],
+			MarketTypeIds:        all.MarketTypeIds[i:end],
+			Bookmakers:           all.Bookmakers[i:end],
+			MarketKeys:           all.MarketKeys[i:end],
+			Outcomes:             all.Outcomes[i:end],
+			OddsValues:           all.OddsValues[i:end],
+			ImpliedProbabilities: all.ImpliedProbabilities[i:end],
+			Multiplicative:       all.Multiplicative[i:end],
+			Additive:             all.Additive[i:end],
+			Power:                all.Power[i:end],
+			Shin:                 all.Shin[i:end],
+			FairOdds:             all.FairOdds[i:end],
+		})
+		if err != nil {
+			return fmt.Errorf("failed to upsert fair probabilities: %w", err)
+		}
+	}
+	return nil
+}
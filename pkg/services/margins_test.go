@@ -0,0 +1,151 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func TestDevigMethodsSumToOne(t *testing.T) {
+	markets := map[string][]float64{
+		"match result": {1.85, 3.40, 4.50},
+		"over under":   {1.80, 1.95},
+		"longshots":    {1.20, 6.50, 15.00},
+	}
+
+	for name, odds := range markets {
+		t.Run(name, func(t *testing.T) {
+			prices, ok := Devig(odds)
+			if !ok {
+				t.Fatalf("Devig(%v) rejected", odds)
+			}
+			if prices.MarginPercentage() <= 0 {
+				t.Errorf("margin = %.2f, want positive", prices.MarginPercentage())
+			}
+
+			methods := map[string][]float64{
+				"multiplicative": prices.Multiplicative,
+				"additive":       prices.Additive,
+				"power":          prices.Power,
+				"shin":           prices.Shin,
+			}
+			for method, fair := range methods {
+				if fair == nil {
+					t.Errorf("%s has no solution", method)
+					continue
+				}
+				if got := sum(fair); math.Abs(got-1) > 1e-6 {
+					t.Errorf("%s sums to %.8f, want 1", method, got)
+				}
+			}
+			if math.IsNaN(prices.PowerK) || prices.PowerK <= 1 {
+				t.Errorf("power k = %f, want above 1", prices.PowerK)
+			}
+			if math.IsNaN(prices.ShinZ) || prices.ShinZ <= 0 {
+				t.Errorf("shin z = %f, want positive", prices.ShinZ)
+			}
+		})
+	}
+}
+
+func TestDevigShinLoadsLongshots(t *testing.T) {
+	prices, ok := Devig([]float64{1.30, 5.00, 11.00})
+	if !ok {
+		t.Fatal("market rejected")
+	}
+
+	// Shin and power move probability from the longshot to the favourite compared to multiplicative
+	if prices.Shin[0] <= prices.Multiplicative[0] || prices.Shin[2] >= prices.Multiplicative[2] {
+		t.Errorf("shin %v not favouring the favourite over multiplicative %v", prices.Shin, prices.Multiplicative)
+	}
+	if prices.Power[0] <= prices.Multiplicative[0] || prices.Power[2] >= prices.Multiplicative[2] {
+		t.Errorf("power %v not favouring the favourite over multiplicative %v", prices.Power, prices.Multiplicative)
+	}
+	if fair := prices.Fair(); &fair[0] != &prices.Shin[0] {
+		t.Error("Fair should return Shin probabilities")
+	}
+}
+
+func TestDevigAdditiveWithoutSolution(t *testing.T) {
+	// A third of the margin is larger than the implied probability of the 200.00 outcome
+	prices, ok := Devig([]float64{1.10, 8.00, 200.00})
+	if !ok {
+		t.Fatal("market rejected")
+	}
+	if prices.Additive != nil {
+		t.Errorf("additive = %v, want nil", prices.Additive)
+	}
+	if prices.Shin == nil || math.Abs(sum(prices.Shin)-1) > 1e-6 {
+		t.Errorf("shin = %v, want a solution", prices.Shin)
+	}
+}
+
+func TestDevigRejectsUnpricedMarkets(t *testing.T) {
+	tests := []struct {
+		name string
+		odds []float64
+	}{
+		{"single outcome", []float64{1.50}},
+		{"odds of one", []float64{1.00, 3.00}},
+		{"booksum below one", []float64{2.50, 2.50}},
+		{"incomplete market read as one", []float64{1.50, 1.50, 1.50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := Devig(tt.odds); ok {
+				t.Errorf("Devig(%v) accepted", tt.odds)
+			}
+		})
+	}
+}
+
+func TestGroupMarketsSplitsByParams(t *testing.T) {
+	event, overUnder, result := int32(7), int32(60), int32(1)
+	row := func(market *int32, outcome string, odds float64, params string) generated.GetOddsForMarginsRow {
+		r := generated.GetOddsForMarginsRow{
+			EventID:      &event,
+			MarketTypeID: market,
+			Bookmaker:    IddaaBookmaker,
+			Outcome:      outcome,
+			OddsValue:    odds,
+		}
+		if params != "" {
+			r.MarketParams = []byte(`{"values":["` + params + `"]}`)
+		}
+		return r
+	}
+
+	markets, order := groupMarkets([]generated.GetOddsForMarginsRow{
+		row(&result, "1", 2.10, ""),
+		row(&overUnder, "Alt", 1.60, "2.5"),
+		row(&result, "X", 3.20, ""),
+		row(&overUnder, "Alt", 2.30, "3.5"),
+		row(&overUnder, "Üst", 2.20, "2.5"),
+		row(&overUnder, "Üst", 1.55, "3.5"),
+		row(&result, "2", 3.60, ""),
+		row(nil, "1", 2.00, ""),
+	})
+
+	if len(order) != 3 {
+		t.Fatalf("got %d markets, want 3: %v", len(order), order)
+	}
+	if order[1].params != "2.5" || order[2].params != "3.5" {
+		t.Errorf("unexpected market order %v", order)
+	}
+	if got := markets[order[0]].outcomes; len(got) != 3 {
+		t.Errorf("match result outcomes = %v, want 3", got)
+	}
+	if got := markets[order[2]].odds; len(got) != 2 || got[0] != 2.30 || got[1] != 1.55 {
+		t.Errorf("3.5 line odds = %v", got)
+	}
+}
@@ -29,7 +29,7 @@ func (s *MarketConfigService) SyncMarketConfigs(ctx context.Context) error {
 		Str("action", "sync_start").
 		Msg("Starting market config sync")
 
-	resp, err := s.client.GetMarketConfig()
+	resp, err := s.client.GetMarketConfig(ctx)
 	if err != nil {
 		log.Error().
 			Err(err).
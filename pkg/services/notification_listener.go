@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+)
+
+// Notification channels populated by the triggers in migration 000023
+const (
+	OddsHistoryChannel   = "odds_history_inserted"
+	MovementAlertChannel = "movement_alert_inserted"
+)
+
+// StreamRowStore loads the rows a notification announces
+type StreamRowStore interface {
+	GetStreamOdds(ctx context.Context, arg generated.GetStreamOddsParams) ([]generated.GetStreamOddsRow, error)
+	GetStreamAlerts(ctx context.Context, ids []int32) ([]generated.GetStreamAlertsRow, error)
+}
+
+// streamNotification is a trigger payload: the ids of up to 500 inserted rows and, for odds, the
+// range of their recorded_at
+type streamNotification struct {
+	Type string  `json:"type"`
+	IDs  []int32 `json:"ids"`
+	From string  `json:"from"`
+	To   string  `json:"to"`
+}
+
+// notificationTimeLayout is how json_build_object renders a timestamp without time zone
+const notificationTimeLayout = "2006-01-02T15:04:05.999999"
+
+// NotificationListener bridges PostgreSQL LISTEN/NOTIFY into a StreamBroker
+type NotificationListener struct {
+	pool     *pgxpool.Pool
+	rows     StreamRowStore
+	broker   *StreamBroker
+	channels []string
+	logger   *logger.Logger
+}
+
+// NewNotificationListener creates a listener for the odds and alert channels
+func NewNotificationListener(pool *pgxpool.Pool, broker *StreamBroker) *NotificationListener {
+	return &NotificationListener{
+		pool:     pool,
+		rows:     generated.New(pool),
+		broker:   broker,
+		channels: []string{OddsHistoryChannel, MovementAlertChannel},
+		logger:   logger.New("notification-listener"),
+	}
+}
+
+// Run listens until the context is cancelled, reconnecting with backoff on errors
+func (l *NotificationListener) Run(ctx context.Context) {
+	backoff := time.Second
+	for {
+		started := time.Now()
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A connection that stayed up for a while starts the backoff over
+		if time.Since(started) > time.Minute {
+			backoff = time.Second
+		}
+
+		l.logger.Warn().
+			Err(err).
+			Dur("retry_in", backoff).
+			Str("action", "listen_reconnect").
+			Msg("Notification listener disconnected")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > 30*time.Second {
+			backoff = 30 * time.Second
+		}
+	}
+}
+
+// listen holds a dedicated connection and forwards notifications to the broker
+func (l *NotificationListener) listen(ctx context.Context) error {
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	for _, channel := range l.channels {
+		if _, err := conn.Exec(ctx, "LISTEN "+channel); err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", channel, err)
+		}
+	}
+
+	l.logger.Info().
+		Strs("channels", l.channels).
+		Str("action", "listen_started").
+		Msg("Listening for database notifications")
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to wait for notification: %w", err)
+		}
+
+		// Skip the work entirely when nobody is connected
+		if l.broker.SubscriberCount() == 0 {
+			continue
+		}
+
+		if err := l.publish(ctx, notification.Payload); err != nil {
+			l.logger.Warn().
+				Err(err).
+				Str("channel", notification.Channel).
+				Msg("Failed to publish notification")
+		}
+	}
+}
+
+// publish loads the rows a notification announces and hands them to the broker
+func (l *NotificationListener) publish(ctx context.Context, payload string) error {
+	var notification streamNotification
+	if err := json.Unmarshal([]byte(payload), &notification); err != nil {
+		return fmt.Errorf("failed to decode payload: %w", err)
+	}
+	if len(notification.IDs) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	switch notification.Type {
+	case StreamTypeOdds:
+		from, err := time.Parse(notificationTimeLayout, notification.From)
+		if err != nil {
+			return fmt.Errorf("invalid from time: %w", err)
+		}
+		to, err := time.Parse(notificationTimeLayout, notification.To)
+		if err != nil {
+			return fmt.Errorf("invalid to time: %w", err)
+		}
+
+		rows, err := l.rows.GetStreamOdds(ctx, generated.GetStreamOddsParams{
+			Ids:      notification.IDs,
+			FromTime: pgtype.Timestamp{Time: from, Valid: true},
+			ToTime:   pgtype.Timestamp{Time: to, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load odds: %w", err)
+		}
+		for _, row := range rows {
+			l.broker.Publish(StreamMessage{
+				Type:      StreamTypeOdds,
+				EventID:   derefInt32(row.EventID),
+				EventSlug: row.EventSlug,
+				SportCode: row.SportCode,
+				LeagueID:  derefInt32(row.LeagueID),
+				Data:      row.Data,
+			})
+		}
+
+	case StreamTypeAlert:
+		rows, err := l.rows.GetStreamAlerts(ctx, notification.IDs)
+		if err != nil {
+			return fmt.Errorf("failed to load alerts: %w", err)
+		}
+		for _, row := range rows {
+			l.broker.Publish(StreamMessage{
+				Type:      StreamTypeAlert,
+				EventID:   derefInt32(row.EventID),
+				EventSlug: row.EventSlug,
+				SportCode: row.SportCode,
+				LeagueID:  derefInt32(row.LeagueID),
+				AlertType: row.AlertType,
+				Data:      row.Data,
+			})
+		}
+
+	default:
+		return fmt.Errorf("unknown notification type %q", notification.Type)
+	}
+
+	return nil
+}
+
+func derefInt32(v *int32) int32 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
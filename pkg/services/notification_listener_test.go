@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+// fakeStreamRows returns one row per requested id
+type fakeStreamRows struct {
+	odds   []generated.GetStreamOddsParams
+	alerts [][]int32
+}
+
+func (f *fakeStreamRows) GetStreamOdds(ctx context.Context, arg generated.GetStreamOddsParams) ([]generated.GetStreamOddsRow, error) {
+	f.odds = append(f.odds, arg)
+	eventID, leagueID := int32(10), int32(7)
+	rows := make([]generated.GetStreamOddsRow, 0, len(arg.Ids))
+	for _, id := range arg.Ids {
+		rows = append(rows, generated.GetStreamOddsRow{ID: id, EventID: &eventID, EventSlug: "a-vs-b", SportCode: "FOOTBALL", LeagueID: &leagueID, Data: []byte(`{}`)})
+	}
+	return rows, nil
+}
+
+func (f *fakeStreamRows) GetStreamAlerts(ctx context.Context, ids []int32) ([]generated.GetStreamAlertsRow, error) {
+	f.alerts = append(f.alerts, ids)
+	rows := make([]generated.GetStreamAlertsRow, 0, len(ids))
+	for _, id := range ids {
+		rows = append(rows, generated.GetStreamAlertsRow{ID: id, EventSlug: "a-vs-b", AlertType: "sharp_money", Data: []byte(`{}`)})
+	}
+	return rows, nil
+}
+
+func TestNotificationListener_Publish(t *testing.T) {
+	rows := &fakeStreamRows{}
+	broker := NewStreamBroker(10)
+	sub := broker.Subscribe(StreamFilter{})
+	listener := &NotificationListener{rows: rows, broker: broker}
+
+	err := listener.publish(context.Background(), `{"type":"odds","ids":[3,4],"from":"2026-10-16T12:00:00.5","to":"2026-10-16T12:00:01"}`)
+	if err != nil {
+		t.Fatalf("publish() error = %v", err)
+	}
+	if len(rows.odds) != 1 {
+		t.Fatalf("expected one odds lookup, got %d", len(rows.odds))
+	}
+	wantFrom := time.Date(2026, 10, 16, 12, 0, 0, 500000000, time.UTC)
+	if got := rows.odds[0]; !got.FromTime.Time.Equal(wantFrom) || got.ToTime.Time.Sub(got.FromTime.Time) != 500*time.Millisecond {
+		t.Errorf("lookup range %v - %v, want the notified recorded_at range", got.FromTime.Time, got.ToTime.Time)
+	}
+	for i := 0; i < 2; i++ {
+		msg := <-sub.C
+		if msg.Type != StreamTypeOdds || msg.EventID != 10 || msg.LeagueID != 7 || msg.SportCode != "FOOTBALL" {
+			t.Errorf("unexpected odds message %+v", msg)
+		}
+	}
+
+	if err := listener.publish(context.Background(), `{"type":"alert","ids":[9]}`); err != nil {
+		t.Fatalf("publish() error = %v", err)
+	}
+	if msg := <-sub.C; msg.Type != StreamTypeAlert || msg.AlertType != "sharp_money" {
+		t.Errorf("unexpected alert message %+v", msg)
+	}
+
+	if err := listener.publish(context.Background(), `{"type":"odds","ids":[1],"from":"yesterday","to":"today"}`); err == nil {
+		t.Error("publish() accepted an invalid time range")
+	}
+	if err := listener.publish(context.Background(), `{"type":"unknown","ids":[1]}`); err == nil {
+		t.Error("publish() accepted an unknown type")
+	}
+}
@@ -0,0 +1,374 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+)
+
+// oddsCacheWindow is how far back in kickoff time a rebuild loads events, long enough to keep live matches
+const oddsCacheWindow = 12 * time.Hour
+
+// OddsCacheKey identifies one Iddaa price like the current_odds unique key, market parameters are
+// part of the price and not of its identity
+type OddsCacheKey struct {
+	EventID      int32
+	MarketTypeID int32
+	Outcome      string
+}
+
+// OddsCacheStats describes the cache at one moment
+type OddsCacheStats struct {
+	Entries int
+	Events  int
+	Version int64
+	Stale   bool
+}
+
+// OddsCacheReport is the result of comparing the cache with the database during a rebuild
+type OddsCacheReport struct {
+	// Entries loaded from the database
+	Entries int
+	// Missing prices of cached events, prices whose value differed and cached prices the database no longer has
+	Missing    int
+	Mismatched int
+	Extra      int
+	// WasStale is set when the cache already knew it missed writes, mismatches are expected then
+	WasStale bool
+	Version  int64
+}
+
+// Inconsistent reports whether a cache that was not stale disagreed with the database
+func (r OddsCacheReport) Inconsistent() bool {
+	return !r.WasStale && r.Missing+r.Mismatched+r.Extra > 0
+}
+
+// OddsCache is a process-local copy of Iddaa's current odds used to detect price changes in Go
+// instead of reading current_odds before every write.
+//
+// Every odds write takes the next value of current_odds_version_seq once committed. The cache
+// follows the versions of the writes it applied itself; a version it did not take means another
+// process wrote odds, the cache is then stale and callers fall back to the database until Rebuild.
+// Events are loaded whole, so a price missing from a loaded event is a new price.
+//
+// With several processes writing odds the cache answers from the version read at the start of
+// CurrentOdds. A price another process writes between that read and the caller's write is compared
+// against the older cached price once, so an unchanged price may be skipped while current_odds holds
+// the other process's value. That write moves the version past the cache, so the next read falls back
+// to the database and writes the price again; the error lasts at most one sync of the event.
+type OddsCache struct {
+	db     *generated.Queries
+	logger *logger.Logger
+
+	mu      sync.RWMutex
+	entries map[OddsCacheKey]float64
+	// Loaded events and their kickoff
+	events map[int32]pgtype.Timestamp
+	// version is the database version the entries are consistent with
+	version int64
+	// applied holds versions of own writes above version, waiting for the gap below them to close
+	applied map[int64]bool
+	// inFlight counts own writes that started but are not applied yet
+	inFlight int
+	stale    bool
+	// Writes applied while a rebuild loads are replayed over its snapshot
+	rebuilding bool
+	pending    []cachedWrite
+	// A write failed during the rebuild, which may have missed part of it
+	failedWhileRebuilding bool
+}
+
+type cachedWrite struct {
+	keys   []OddsCacheKey
+	values []float64
+}
+
+// NewOddsCache creates an empty cache, it answers no lookups until warmed with Rebuild
+func NewOddsCache(db *generated.Queries) *OddsCache {
+	return &OddsCache{
+		db:      db,
+		logger:  logger.New("odds-cache"),
+		entries: make(map[OddsCacheKey]float64),
+		events:  make(map[int32]pgtype.Timestamp),
+		applied: make(map[int64]bool),
+		stale:   true,
+	}
+}
+
+// Stats returns the size, version and state of the cache
+func (c *OddsCache) Stats() OddsCacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return OddsCacheStats{
+		Entries: len(c.entries),
+		Events:  len(c.events),
+		Version: c.version,
+		Stale:   c.stale,
+	}
+}
+
+// CurrentOdds returns the cached prices of keys in the shape of BulkGetCurrentOddsForComparison.
+// Events not cached yet are loaded first. ok is false when the cache cannot answer because it
+// missed writes, callers then read the database.
+func (c *OddsCache) CurrentOdds(ctx context.Context, keys []OddsCacheKey) ([]generated.BulkGetCurrentOddsForComparisonRow, bool, error) {
+	dbVersion, err := c.db.GetCurrentOddsVersion(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get current odds version: %w", err)
+	}
+
+	c.mu.Lock()
+	consistent := c.consistentWith(dbVersion)
+	c.mu.Unlock()
+	if !consistent {
+		return nil, false, nil
+	}
+
+	eventIDs := make([]int32, len(keys))
+	for i, key := range keys {
+		eventIDs[i] = key.EventID
+	}
+	if err := c.Preload(ctx, eventIDs); err != nil {
+		return nil, false, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.stale {
+		return nil, false, nil
+	}
+
+	rows := make([]generated.BulkGetCurrentOddsForComparisonRow, 0, len(keys))
+	for _, key := range keys {
+		value, ok := c.entries[key]
+		if !ok {
+			continue
+		}
+		eventID, marketTypeID := key.EventID, key.MarketTypeID
+		rows = append(rows, generated.BulkGetCurrentOddsForComparisonRow{
+			EventID:      &eventID,
+			MarketTypeID: &marketTypeID,
+			Outcome:      key.Outcome,
+			OddsValue:    value,
+			EventDate:    c.events[key.EventID],
+		})
+	}
+	return rows, true, nil
+}
+
+// consistentWith checks the database version against the writes the cache knows of and marks the
+// cache stale when another process wrote. Own writes in flight may already have taken a version.
+// Called with the lock held.
+func (c *OddsCache) consistentWith(dbVersion int64) bool {
+	if c.stale {
+		return false
+	}
+	if dbVersion > c.version+int64(c.inFlight+len(c.applied)) {
+		c.stale = true
+		c.logger.Warn().
+			Str("action", "cache_stale").
+			Int64("cache_version", c.version).
+			Int64("db_version", dbVersion).
+			Msg("Current odds written by another process, using the database until the cache is rebuilt")
+		return false
+	}
+	return true
+}
+
+// Preload loads every price of the events not cached yet with one query. Prices already cached
+// come from writes applied meanwhile and are newer, so they are kept.
+func (c *OddsCache) Preload(ctx context.Context, eventIDs []int32) error {
+	c.mu.RLock()
+	var missing []int32
+	seen := make(map[int32]bool)
+	for _, eventID := range eventIDs {
+		if _, loaded := c.events[eventID]; !loaded && !seen[eventID] {
+			seen[eventID] = true
+			missing = append(missing, eventID)
+		}
+	}
+	c.mu.RUnlock()
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	rows, err := c.db.GetCurrentOddsForCacheByEvents(ctx, missing)
+	if err != nil {
+		return fmt.Errorf("failed to load current odds of %d events: %w", len(missing), err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, row := range rows {
+		key, ok := oddsCacheKey(generated.ListCurrentOddsForCacheRow(row))
+		if !ok {
+			continue
+		}
+		if _, cached := c.entries[key]; !cached {
+			c.entries[key] = row.OddsValue
+		}
+		c.events[key.EventID] = row.EventDate
+	}
+	// Events without prices stay unloaded and are looked up again once they have some,
+	// their first prices are new either way
+	return nil
+}
+
+// BeginWrite registers an odds write about to start, pair it with FinishWrite
+func (c *OddsCache) BeginWrite() {
+	c.mu.Lock()
+	c.inFlight++
+	c.mu.Unlock()
+}
+
+// FinishWrite applies a finished write. version is the value the write took from
+// current_odds_version_seq, 0 when that failed. A failed write may have been partially stored,
+// so it leaves the cache stale.
+func (c *OddsCache) FinishWrite(version int64, keys []OddsCacheKey, values []float64, writeErr error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inFlight--
+	if writeErr != nil || version == 0 {
+		if !c.stale {
+			c.logger.Warn().
+				Str("action", "cache_stale").
+				Int64("version", version).
+				Msg("Odds write failed or was not versioned, using the database until the cache is rebuilt")
+		}
+		c.stale = true
+		c.failedWhileRebuilding = c.rebuilding
+		return
+	}
+
+	c.apply(keys, values)
+	if c.rebuilding {
+		c.pending = append(c.pending, cachedWrite{keys: keys, values: values})
+	}
+
+	if version > c.version {
+		c.applied[version] = true
+		c.advance()
+	}
+}
+
+func (c *OddsCache) apply(keys []OddsCacheKey, values []float64) {
+	for i, key := range keys {
+		c.entries[key] = values[i]
+	}
+}
+
+// advance moves the version over the contiguous own writes above it
+func (c *OddsCache) advance() {
+	for c.applied[c.version+1] {
+		delete(c.applied, c.version+1)
+		c.version++
+	}
+}
+
+// Rebuild replaces the cache with the current odds of events kicking off from 12 hours ago on and
+// reports how the old contents differed. It warms the cache at startup and repairs it once stale.
+func (c *OddsCache) Rebuild(ctx context.Context, now time.Time) (OddsCacheReport, error) {
+	c.mu.Lock()
+	c.rebuilding = true
+	c.pending = nil
+	c.failedWhileRebuilding = false
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.rebuilding = false
+		c.pending = nil
+		c.mu.Unlock()
+	}()
+
+	// Writes commit before taking their version, so every write up to this version is in the snapshot
+	version, err := c.db.GetCurrentOddsVersion(ctx)
+	if err != nil {
+		return OddsCacheReport{}, fmt.Errorf("failed to get current odds version: %w", err)
+	}
+
+	rows, err := c.db.ListCurrentOddsForCache(ctx, pgtype.Timestamp{Time: now.Add(-oddsCacheWindow), Valid: true})
+	if err != nil {
+		return OddsCacheReport{}, fmt.Errorf("failed to load current odds: %w", err)
+	}
+
+	entries := make(map[OddsCacheKey]float64, len(rows))
+	events := make(map[int32]pgtype.Timestamp)
+	for _, row := range rows {
+		key, ok := oddsCacheKey(row)
+		if !ok {
+			continue
+		}
+		entries[key] = row.OddsValue
+		events[key.EventID] = row.EventDate
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := compareOddsCache(c.entries, c.events, entries, events)
+	report.Entries = len(entries)
+	report.WasStale = c.stale
+	report.Version = version
+
+	c.entries = entries
+	c.events = events
+	for _, write := range c.pending {
+		c.apply(write.keys, write.values)
+	}
+	c.version = version
+	for applied := range c.applied {
+		if applied <= version {
+			delete(c.applied, applied)
+		}
+	}
+	c.advance()
+	c.stale = c.failedWhileRebuilding
+
+	return report, nil
+}
+
+// compareOddsCache counts the differences between cached prices and a database snapshot,
+// only for events present in both
+func compareOddsCache(cached map[OddsCacheKey]float64, cachedEvents map[int32]pgtype.Timestamp, loaded map[OddsCacheKey]float64, loadedEvents map[int32]pgtype.Timestamp) OddsCacheReport {
+	var report OddsCacheReport
+	for key, value := range loaded {
+		if _, ok := cachedEvents[key.EventID]; !ok {
+			continue
+		}
+		cachedValue, ok := cached[key]
+		switch {
+		case !ok:
+			report.Missing++
+		case cachedValue != value:
+			report.Mismatched++
+		}
+	}
+	for key := range cached {
+		if _, ok := loadedEvents[key.EventID]; !ok {
+			continue
+		}
+		if _, ok := loaded[key]; !ok {
+			report.Extra++
+		}
+	}
+	return report
+}
+
+func oddsCacheKey(row generated.ListCurrentOddsForCacheRow) (OddsCacheKey, bool) {
+	if row.EventID == nil || row.MarketTypeID == nil {
+		return OddsCacheKey{}, false
+	}
+	return OddsCacheKey{
+		EventID:      *row.EventID,
+		MarketTypeID: *row.MarketTypeID,
+		Outcome:      row.Outcome,
+	}, true
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+// versionDB answers GetCurrentOddsVersion and fails every other query
+type versionDB struct {
+	version int64
+}
+
+func (d *versionDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("unexpected Exec")
+}
+
+func (d *versionDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("unexpected Query")
+}
+
+func (d *versionDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return versionRow(d.version)
+}
+
+type versionRow int64
+
+func (r versionRow) Scan(dest ...any) error {
+	*dest[0].(*int64) = int64(r)
+	return nil
+}
+
+// warmOddsCache returns a cache holding one loaded event at the given version
+func warmOddsCache(db *versionDB, version int64, entries map[OddsCacheKey]float64) *OddsCache {
+	cache := NewOddsCache(generated.New(db))
+	kickoff := pgtype.Timestamp{Time: time.Date(2025, 5, 1, 19, 0, 0, 0, time.UTC), Valid: true}
+	for key, value := range entries {
+		cache.entries[key] = value
+		cache.events[key.EventID] = kickoff
+	}
+	cache.version = version
+	cache.stale = false
+	return cache
+}
+
+func TestOddsCacheAppliesOwnWrites(t *testing.T) {
+	home := OddsCacheKey{EventID: 1, MarketTypeID: 10, Outcome: "1"}
+	over := OddsCacheKey{EventID: 1, MarketTypeID: 11, Outcome: "Üst 2.5"}
+
+	db := &versionDB{version: 5}
+	cache := warmOddsCache(db, 5, map[OddsCacheKey]float64{home: 1.80})
+
+	// Two writes finishing out of order
+	cache.BeginWrite()
+	cache.BeginWrite()
+	cache.FinishWrite(7, []OddsCacheKey{over}, []float64{1.95}, nil)
+	db.version = 7
+	if _, ok, err := cache.CurrentOdds(context.Background(), []OddsCacheKey{home}); err != nil || !ok {
+		t.Fatalf("cache with a write in flight should answer, ok = %v, err = %v", ok, err)
+	}
+	cache.FinishWrite(6, []OddsCacheKey{home}, []float64{1.75}, nil)
+
+	if stats := cache.Stats(); stats.Version != 7 || stats.Stale {
+		t.Fatalf("stats = %+v, want version 7 and not stale", stats)
+	}
+
+	rows, ok, err := cache.CurrentOdds(context.Background(), []OddsCacheKey{home, over, {EventID: 1, MarketTypeID: 10, Outcome: "2"}})
+	if err != nil || !ok {
+		t.Fatalf("CurrentOdds ok = %v, err = %v", ok, err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 (new outcome not cached): %+v", len(rows), rows)
+	}
+	if rows[0].OddsValue != 1.75 || rows[1].OddsValue != 1.95 {
+		t.Errorf("values = %v, %v, want 1.75, 1.95", rows[0].OddsValue, rows[1].OddsValue)
+	}
+	if *rows[0].EventID != 1 || !rows[0].EventDate.Valid {
+		t.Errorf("unexpected row %+v", rows[0])
+	}
+}
+
+func TestOddsCacheStaleOnForeignWrite(t *testing.T) {
+	home := OddsCacheKey{EventID: 1, MarketTypeID: 10, Outcome: "1"}
+	db := &versionDB{version: 6}
+	cache := warmOddsCache(db, 5, map[OddsCacheKey]float64{home: 1.80})
+
+	_, ok, err := cache.CurrentOdds(context.Background(), []OddsCacheKey{home})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("cache behind the database without own writes should not answer")
+	}
+	if !cache.Stats().Stale {
+		t.Error("cache should be stale")
+	}
+
+	// Own writes do not make it usable again, only a rebuild does
+	cache.BeginWrite()
+	cache.FinishWrite(7, []OddsCacheKey{home}, []float64{1.70}, nil)
+	db.version = 7
+	if _, ok, _ := cache.CurrentOdds(context.Background(), []OddsCacheKey{home}); ok {
+		t.Error("stale cache answered")
+	}
+}
+
+func TestOddsCacheStaleOnFailedWrite(t *testing.T) {
+	home := OddsCacheKey{EventID: 1, MarketTypeID: 10, Outcome: "1"}
+
+	cache := warmOddsCache(&versionDB{version: 5}, 5, map[OddsCacheKey]float64{home: 1.80})
+	cache.BeginWrite()
+	cache.FinishWrite(6, []OddsCacheKey{home}, []float64{1.70}, errors.New("chunk failed"))
+	if !cache.Stats().Stale {
+		t.Error("failed write should leave the cache stale")
+	}
+
+	cache = warmOddsCache(&versionDB{version: 5}, 5, map[OddsCacheKey]float64{home: 1.80})
+	cache.BeginWrite()
+	cache.FinishWrite(0, []OddsCacheKey{home}, []float64{1.70}, nil)
+	if !cache.Stats().Stale {
+		t.Error("unversioned write should leave the cache stale")
+	}
+}
+
+func TestCompareOddsCache(t *testing.T) {
+	key := func(event int32, outcome string) OddsCacheKey {
+		return OddsCacheKey{EventID: event, MarketTypeID: 1, Outcome: outcome}
+	}
+	loaded := map[int32]pgtype.Timestamp{1: {}, 2: {}}
+
+	report := compareOddsCache(
+		map[OddsCacheKey]float64{key(1, "1"): 1.5, key(1, "2"): 2.5, key(1, "X"): 3.0, key(3, "1"): 1.1},
+		map[int32]pgtype.Timestamp{1: {}, 3: {}},
+		map[OddsCacheKey]float64{key(1, "1"): 1.5, key(1, "2"): 2.4, key(1, "1X"): 1.2, key(2, "1"): 1.9},
+		loaded,
+	)
+
+	// Event 2 was never cached and event 3 is outside the snapshot, neither counts
+	if report.Missing != 1 || report.Mismatched != 1 || report.Extra != 1 {
+		t.Errorf("report = %+v, want 1 missing, 1 mismatched, 1 extra", report)
+	}
+	if !report.Inconsistent() {
+		t.Error("differences in a cache that was not stale are inconsistent")
+	}
+	report.WasStale = true
+	if report.Inconsistent() {
+		t.Error("differences in a stale cache are expected")
+	}
+}
+
+func TestWithoutUnchanged(t *testing.T) {
+	current := generated.BulkUpsertCurrentOddsParams{
+		EventIds:      []int32{1, 1, 1},
+		MarketTypeIds: []int32{10, 10, 10},
+		Outcomes:      []string{"1", "X", "2"},
+		OddsValues:    []float64{1.8, 3.2, 4.5},
+		MarketParams:  [][]byte{nil, nil, nil},
+		RecordedAt:    pgtype.Timestamp{Time: time.Now(), Valid: true},
+	}
+	keys := []OddsCacheKey{
+		{EventID: 1, MarketTypeID: 10, Outcome: "1"},
+		{EventID: 1, MarketTypeID: 10, Outcome: "X"},
+		{EventID: 1, MarketTypeID: 10, Outcome: "2"},
+	}
+
+	changed, changedKeys := withoutUnchanged(current, keys, func(i int) bool { return i == 1 })
+	if len(changed.EventIds) != 2 || len(changed.MarketParams) != 2 || len(changedKeys) != 2 {
+		t.Fatalf("got %d rows and %d keys, want 2", len(changed.EventIds), len(changedKeys))
+	}
+	if changed.Outcomes[1] != "2" || changedKeys[1].Outcome != "2" || changed.OddsValues[1] != 4.5 {
+		t.Errorf("unexpected rows %+v keys %+v", changed, changedKeys)
+	}
+	if changed.RecordedAt != current.RecordedAt {
+		t.Error("recorded_at not kept")
+	}
+}
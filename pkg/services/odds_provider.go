@@ -0,0 +1,533 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+// IddaaBookmaker is the bookmaker code of prices written by the Iddaa sync
+const IddaaBookmaker = "iddaa"
+
+// OddsProvider supplies prices from bookmakers other than Iddaa for events we already track
+type OddsProvider interface {
+	// Name identifies the provider, stored as bookmakers.provider
+	Name() string
+	// FetchOdds returns the current prices for the given events. A provider that stops early,
+	// e.g. on a rate limit, returns the quotes collected so far together with the error.
+	FetchOdds(ctx context.Context, events []ProviderEvent) ([]ProviderQuote, error)
+}
+
+// ProviderEvent is an upcoming Iddaa event with the API-Football ids from league_mappings and team_mappings
+type ProviderEvent struct {
+	EventID               int32
+	EventDate             time.Time
+	APIFootballLeagueID   int
+	APIFootballHomeTeamID int
+	APIFootballAwayTeamID int
+}
+
+// Selection identifies an outcome independently of how a bookmaker names it
+type Selection struct {
+	Family    MarketFamily
+	FirstHalf bool
+	// Pick is "1", "x" or "2" for results, "1x", "12" or "x2" for double chance, "1/x" for half time/full time,
+	// "over" or "under", "yes" or "no" for both teams to score and "2:1" for correct scores
+	Pick string
+	// Line is the goal line of over/under markets, empty otherwise
+	Line string
+}
+
+// ProviderQuote is one bookmaker's price for one selection
+type ProviderQuote struct {
+	EventID   int32
+	Bookmaker string
+	// BookmakerName and ProviderBookmakerID are stored in the bookmakers table
+	BookmakerName       string
+	ProviderBookmakerID int
+	Selection           Selection
+	Odds                float64
+}
+
+// IddaaSelection maps an Iddaa outcome to its selection, false for markets other books cannot be matched on
+func IddaaSelection(marketName, outcome string, params []string) (Selection, bool) {
+	spec, ok := ClassifyMarket(marketName)
+	if !ok {
+		return Selection{}, false
+	}
+	selection := Selection{Family: spec.Family, FirstHalf: spec.FirstHalf}
+	name := normalizeOutcome(outcome)
+
+	switch spec.Family {
+	case MarketFamily1X2:
+		result, ok := matchResult(name)
+		if !ok {
+			return Selection{}, false
+		}
+		selection.Pick = resultPick(result)
+
+	case MarketFamilyDoubleChance:
+		pair := strings.NewReplacer("-", "", "/", "", " ", "", "0", "x").Replace(name)
+		if len(pair) != 2 {
+			return Selection{}, false
+		}
+		first, ok1 := matchResult(pair[:1])
+		second, ok2 := matchResult(pair[1:])
+		if !ok1 || !ok2 || first == second {
+			return Selection{}, false
+		}
+		selection.Pick = doubleChancePick(first, second)
+
+	case MarketFamilyOverUnder:
+		fields := strings.Fields(name)
+		if len(fields) == 0 {
+			return Selection{}, false
+		}
+		switch {
+		case strings.HasPrefix(fields[0], "üst"), strings.HasPrefix(fields[0], "over"):
+			selection.Pick = "over"
+		case strings.HasPrefix(fields[0], "alt"), strings.HasPrefix(fields[0], "under"):
+			selection.Pick = "under"
+		default:
+			return Selection{}, false
+		}
+		// Same fallback as settlement: the market parameter, else the number in the outcome name
+		lineText := ""
+		if len(params) == 1 {
+			lineText = params[0]
+		} else if trimmed := strings.Fields(strings.TrimSpace(outcome)); len(trimmed) > 1 {
+			lineText = trimmed[len(trimmed)-1]
+		}
+		line, ok := parseLine(lineText)
+		if !ok {
+			return Selection{}, false
+		}
+		selection.Line = line
+
+	case MarketFamilyBTTS:
+		switch name {
+		case "var", "evet", "yes":
+			selection.Pick = "yes"
+		case "yok", "hayır", "no":
+			selection.Pick = "no"
+		default:
+			return Selection{}, false
+		}
+
+	case MarketFamilyHTFT:
+		parts := strings.Split(name, "/")
+		if len(parts) != 2 {
+			return Selection{}, false
+		}
+		ht, ok1 := matchResult(parts[0])
+		ft, ok2 := matchResult(parts[1])
+		if !ok1 || !ok2 {
+			return Selection{}, false
+		}
+		selection.Pick = resultPick(ht) + "/" + resultPick(ft)
+
+	case MarketFamilyCorrectScore:
+		pick, ok := scorePick(name)
+		if !ok {
+			return Selection{}, false
+		}
+		selection.Pick = pick
+
+	default:
+		// Iddaa handicaps are three-way with goal starts, other books quote Asian lines
+		return Selection{}, false
+	}
+
+	return selection, true
+}
+
+func resultPick(result int) string {
+	switch result {
+	case 1:
+		return "1"
+	case 2:
+		return "2"
+	default:
+		return "x"
+	}
+}
+
+// doubleChancePick orders the two results as 1, x, 2 so "X-1" and "Home/Draw" agree
+func doubleChancePick(first, second int) string {
+	rank := map[int]int{1: 0, 0: 1, 2: 2}
+	if rank[second] < rank[first] {
+		first, second = second, first
+	}
+	return resultPick(first) + resultPick(second)
+}
+
+func parseLine(text string) (string, bool) {
+	line, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(text), ",", "."), 64)
+	if err != nil {
+		return "", false
+	}
+	return strconv.FormatFloat(line, 'f', -1, 64), true
+}
+
+func scorePick(name string) (string, bool) {
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == ':' || r == '-' })
+	if len(parts) != 2 {
+		return "", false
+	}
+	home, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+	away, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%d:%d", home, away), true
+}
+
+// BookmakerOddsStats summarizes a provider sync run
+type BookmakerOddsStats struct {
+	Events int
+	Quotes int
+	// Matched counts quotes that map to an outcome Iddaa offers
+	Matched int
+	// Changed counts prices that were new or moved, each written to odds_history
+	Changed  int64
+	Failures int
+}
+
+// BookmakerOddsService stores prices from odds providers next to Iddaa's own
+type BookmakerOddsService struct {
+	db        *generated.Queries
+	providers []OddsProvider
+	logger    *logger.Logger
+}
+
+// NewBookmakerOddsService creates a service syncing the given providers
+func NewBookmakerOddsService(db *generated.Queries, providers ...OddsProvider) *BookmakerOddsService {
+	return &BookmakerOddsService{
+		db:        db,
+		providers: providers,
+		logger:    logger.New("bookmaker-odds"),
+	}
+}
+
+// iddaaOutcome is the Iddaa row a provider quote is stored against
+type iddaaOutcome struct {
+	marketTypeID int32
+	outcome      string
+	params       []byte
+}
+
+// SyncUpcoming fetches provider prices for mapped events kicking off within the horizon.
+// Only selections Iddaa also offers are stored, under Iddaa's market type and outcome name.
+func (s *BookmakerOddsService) SyncUpcoming(ctx context.Context, horizon time.Duration, limit int) (BookmakerOddsStats, error) {
+	var stats BookmakerOddsStats
+
+	targets, err := s.db.GetProviderOddsTargets(ctx, generated.GetProviderOddsTargetsParams{
+		ToTime:     pgtype.Timestamp{Time: time.Now().Add(horizon), Valid: true},
+		LimitCount: int32(limit),
+	})
+	if err != nil {
+		return stats, fmt.Errorf("failed to get events for provider odds: %w", err)
+	}
+	if len(targets) == 0 {
+		return stats, nil
+	}
+
+	events := make([]ProviderEvent, 0, len(targets))
+	eventIDs := make([]int32, 0, len(targets))
+	for _, target := range targets {
+		events = append(events, ProviderEvent{
+			EventID:               target.ID,
+			EventDate:             target.EventDate.Time,
+			APIFootballLeagueID:   int(target.FootballApiLeagueID),
+			APIFootballHomeTeamID: int(target.HomeApiTeamID),
+			APIFootballAwayTeamID: int(target.AwayApiTeamID),
+		})
+		eventIDs = append(eventIDs, target.ID)
+	}
+	stats.Events = len(events)
+
+	rows, err := s.db.GetIddaaOutcomesForEvents(ctx, eventIDs)
+	if err != nil {
+		return stats, fmt.Errorf("failed to get Iddaa outcomes: %w", err)
+	}
+	outcomes := indexIddaaOutcomes(rows)
+
+	for _, provider := range s.providers {
+		quotes, err := provider.FetchOdds(ctx, events)
+		if err != nil {
+			stats.Failures++
+			s.logger.Warn().
+				Err(err).
+				Str("provider", provider.Name()).
+				Int("quotes", len(quotes)).
+				Msg("Provider odds fetch stopped early")
+		}
+		stats.Quotes += len(quotes)
+
+		matched, changed, err := s.storeQuotes(ctx, provider.Name(), quotes, outcomes)
+		stats.Matched += matched
+		stats.Changed += changed
+		if err != nil {
+			stats.Failures++
+			s.logger.Error().
+				Err(err).
+				Str("provider", provider.Name()).
+				Msg("Failed to store provider odds")
+		}
+	}
+
+	return stats, nil
+}
+
+// indexIddaaOutcomes groups Iddaa outcomes by event and selection
+func indexIddaaOutcomes(rows []generated.GetIddaaOutcomesForEventsRow) map[int32]map[Selection][]iddaaOutcome {
+	index := make(map[int32]map[Selection][]iddaaOutcome)
+	for _, row := range rows {
+		if row.EventID == nil || row.MarketTypeID == nil {
+			continue
+		}
+		var params models.MarketParams
+		if len(row.MarketParams) > 0 {
+			_ = json.Unmarshal(row.MarketParams, &params)
+		}
+		selection, ok := IddaaSelection(row.MarketName, row.Outcome, params.Values)
+		if !ok {
+			continue
+		}
+		if index[*row.EventID] == nil {
+			index[*row.EventID] = make(map[Selection][]iddaaOutcome)
+		}
+		index[*row.EventID][selection] = append(index[*row.EventID][selection], iddaaOutcome{
+			marketTypeID: *row.MarketTypeID,
+			outcome:      row.Outcome,
+			params:       row.MarketParams,
+		})
+	}
+	return index
+}
+
+func (s *BookmakerOddsService) storeQuotes(ctx context.Context, provider string, quotes []ProviderQuote, outcomes map[int32]map[Selection][]iddaaOutcome) (int, int64, error) {
+	if len(quotes) == 0 {
+		return 0, 0, nil
+	}
+
+	type bookmaker struct {
+		name       string
+		providerID int
+	}
+	books := make(map[string]bookmaker)
+	seen := make(map[string]bool)
+
+	var (
+		eventIDs      []int32
+		marketTypeIDs []int32
+		outcomeNames  []string
+		bookmakers    []string
+		oddsValues    []float64
+		marketParams  [][]byte
+		matched       int
+	)
+	for _, quote := range quotes {
+		if quote.Bookmaker == "" || quote.Bookmaker == IddaaBookmaker || quote.Odds <= 1 {
+			continue
+		}
+		targets := outcomes[quote.EventID][quote.Selection]
+		if len(targets) == 0 {
+			continue
+		}
+		matched++
+		books[quote.Bookmaker] = bookmaker{name: quote.BookmakerName, providerID: quote.ProviderBookmakerID}
+
+		for _, target := range targets {
+			// A book may list the same selection under two bets; the first price wins
+			key := fmt.Sprintf("%d|%d|%s|%s", quote.EventID, target.marketTypeID, target.outcome, quote.Bookmaker)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			eventIDs = append(eventIDs, quote.EventID)
+			marketTypeIDs = append(marketTypeIDs, target.marketTypeID)
+			outcomeNames = append(outcomeNames, target.outcome)
+			bookmakers = append(bookmakers, quote.Bookmaker)
+			oddsValues = append(oddsValues, quote.Odds)
+			marketParams = append(marketParams, target.params)
+		}
+	}
+	if len(eventIDs) == 0 {
+		return matched, 0, nil
+	}
+
+	codes := make([]string, 0, len(books))
+	for code := range books {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	names := make([]string, 0, len(codes))
+	providerIDs := make([]int32, 0, len(codes))
+	for _, code := range codes {
+		names = append(names, books[code].name)
+		providerIDs = append(providerIDs, int32(books[code].providerID))
+	}
+	if err := s.db.UpsertBookmakers(ctx, generated.UpsertBookmakersParams{
+		Codes:                codes,
+		Names:                names,
+		ProviderBookmakerIds: providerIDs,
+		Provider:             provider,
+	}); err != nil {
+		return matched, 0, fmt.Errorf("failed to upsert bookmakers: %w", err)
+	}
+
+	var changed int64
+	const chunkSize = 1000
+	for i := 0; i < len(eventIDs); i += chunkSize {
+		end := i + chunkSize
+		if end > len(eventIDs) {
+			end = len(eventIDs)
+		}
+
+		rows, err := s.db.BulkUpsertBookmakerOdds(ctx, generated.BulkUpsertBookmakerOddsParams{
+			EventIds:      eventIDs[i:end],
+			MarketTypeIds: marketTypeIDs[i:end],
+			Outcomes:      outcomeNames[i:end],
+			Bookmakers:    bookmakers[i:end],
+			OddsValues:    oddsValues[i:end],
+			MarketParams:  marketParams[i:end],
+		})
+		if err != nil {
+			return matched, changed, fmt.Errorf("failed to upsert bookmaker odds: %w", err)
+		}
+		changed += rows
+	}
+
+	s.logger.Info().
+		Str("provider", provider).
+		Int("bookmakers", len(codes)).
+		Int("prices", len(eventIDs)).
+		Int64("changed", changed).
+		Msg("Stored provider odds")
+
+	return matched, changed, nil
+}
+
+// BookmakerPrice is one bookmaker's current price for an outcome
+type BookmakerPrice struct {
+	Bookmaker   string
+	Name        string
+	Odds        float64
+	LastUpdated time.Time
+}
+
+// PriceComparison lines up every bookmaker's price for one Iddaa outcome
+type PriceComparison struct {
+	MarketTypeID int32
+	MarketCode   string
+	MarketName   string
+	MarketParams []string
+	Outcome      string
+	IddaaOdds    float64
+	// BestOdds is the highest price across all books, Iddaa included
+	BestOdds      float64
+	BestBookmaker string
+	// MarketOdds is the median price of the other books, 0 when none quote the outcome
+	MarketOdds     float64
+	BookmakerCount int
+	// Deviation is Iddaa's price against MarketOdds in percent, positive when Iddaa pays more
+	Deviation float64
+	// OutOfLine is set when enough books quote the outcome and Iddaa deviates by at least the threshold
+	OutOfLine bool
+	// Prices are ordered best first
+	Prices []BookmakerPrice
+}
+
+// ComparePrices groups an event's bookmaker prices by outcome. Suspended prices are left out.
+func ComparePrices(rows []generated.GetEventBookmakerOddsRow, minDeviation float64, minBookmakers int) []PriceComparison {
+	var (
+		comparisons []PriceComparison
+		index       = make(map[string]int)
+	)
+	for _, row := range rows {
+		if row.IsSuspended || row.MarketTypeID == nil {
+			continue
+		}
+		key := fmt.Sprintf("%d|%s", *row.MarketTypeID, row.Outcome)
+		i, ok := index[key]
+		if !ok {
+			i = len(comparisons)
+			index[key] = i
+			var params models.MarketParams
+			if len(row.MarketParams) > 0 {
+				_ = json.Unmarshal(row.MarketParams, &params)
+			}
+			if params.Values == nil {
+				params.Values = []string{}
+			}
+			comparisons = append(comparisons, PriceComparison{
+				MarketTypeID: *row.MarketTypeID,
+				MarketCode:   row.MarketCode,
+				MarketName:   row.MarketName,
+				MarketParams: params.Values,
+				Outcome:      row.Outcome,
+			})
+		}
+		comparisons[i].Prices = append(comparisons[i].Prices, BookmakerPrice{
+			Bookmaker:   row.Bookmaker,
+			Name:        row.BookmakerName,
+			Odds:        row.OddsValue,
+			LastUpdated: row.LastUpdated.Time,
+		})
+	}
+
+	result := comparisons[:0]
+	for _, comparison := range comparisons {
+		sort.SliceStable(comparison.Prices, func(a, b int) bool {
+			return comparison.Prices[a].Odds > comparison.Prices[b].Odds
+		})
+
+		var others []float64
+		for _, price := range comparison.Prices {
+			if price.Bookmaker == IddaaBookmaker {
+				comparison.IddaaOdds = price.Odds
+			} else {
+				others = append(others, price.Odds)
+			}
+		}
+		// Outcomes Iddaa suspended are not comparable
+		if comparison.IddaaOdds == 0 {
+			continue
+		}
+
+		comparison.BestOdds = comparison.Prices[0].Odds
+		comparison.BestBookmaker = comparison.Prices[0].Bookmaker
+		comparison.BookmakerCount = len(others)
+		if len(others) > 0 {
+			comparison.MarketOdds = median(others)
+			comparison.Deviation = (comparison.IddaaOdds/comparison.MarketOdds - 1) * 100
+			comparison.OutOfLine = len(others) >= minBookmakers && math.Abs(comparison.Deviation) >= minDeviation
+		}
+		result = append(result, comparison)
+	}
+
+	return result
+}
+
+// median of a non-empty slice; the slice is sorted in place
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[mid]
+	}
+	return (values[mid-1] + values[mid]) / 2
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+func TestSelectionsMatchAcrossBookmakers(t *testing.T) {
+	tests := []struct {
+		name    string
+		market  string
+		outcome string
+		params  []string
+		bet     string
+		value   string
+	}{
+		{"home win", "Maç Sonucu", "1", nil, "Match Winner", "Home"},
+		{"draw", "Maç Sonucu", "X", nil, "Match Winner", "Draw"},
+		{"first half away", "İlk Yarı Sonucu", "2", nil, "First Half Winner", "Away"},
+		{"double chance", "Çifte Şans", "X-1", nil, "Double Chance", "Home/Draw"},
+		{"double chance away", "Çifte Şans", "X-2", nil, "Double Chance", "Draw/Away"},
+		{"over from params", "{0} Alt/Üst", "Üst", []string{"2.5"}, "Goals Over/Under", "Over 2.5"},
+		{"under from outcome", "{0} Alt/Üst", "Alt 3.5", nil, "Goals Over/Under", "Under 3.50"},
+		{"first half over", "İlk Yarı {0} Alt/Üst", "Üst", []string{"1,5"}, "Goals Over/Under First Half", "Over 1.5"},
+		{"btts", "Karşılıklı Gol", "Var", nil, "Both Teams Score", "Yes"},
+		{"ht ft", "İlk Yarı/Maç Sonucu", "X/1", nil, "HT/FT Double", "Draw/Home"},
+		{"correct score", "Maç Skoru", "2-1", nil, "Exact Score", "2:1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iddaa, ok := IddaaSelection(tt.market, tt.outcome, tt.params)
+			if !ok {
+				t.Fatalf("IddaaSelection(%q, %q) not mapped", tt.market, tt.outcome)
+			}
+			provider, ok := apiFootballSelection(tt.bet, tt.value)
+			if !ok {
+				t.Fatalf("apiFootballSelection(%q, %q) not mapped", tt.bet, tt.value)
+			}
+			if iddaa != provider {
+				t.Errorf("selections differ: iddaa %+v, provider %+v", iddaa, provider)
+			}
+		})
+	}
+}
+
+func TestSelectionsNotMapped(t *testing.T) {
+	if _, ok := IddaaSelection("Handikaplı Maç Sonucu ({0}:{1})", "1", []string{"0", "1"}); ok {
+		t.Error("three-way handicap should not be mapped")
+	}
+	if _, ok := IddaaSelection("Ev Sahibi Toplam Korner Altı/Üstü {0}", "Üst", []string{"4.5"}); ok {
+		t.Error("corner market should not be mapped")
+	}
+	if _, ok := apiFootballSelection("Asian Handicap", "Home -1"); ok {
+		t.Error("Asian handicap should not be mapped")
+	}
+	if _, ok := apiFootballSelection("Goals Over/Under", "Over"); ok {
+		t.Error("over/under without a line should not be mapped")
+	}
+}
+
+func TestAPIFootballQuotes(t *testing.T) {
+	odds := &models.FootballAPIOddsData{
+		Bookmakers: []models.FootballAPIBookmakerOdd{
+			{ID: 8, Name: "Bet365", Bets: []models.FootballAPIBet{
+				{ID: 1, Name: "Match Winner", Values: []models.FootballAPIBetValue{
+					{Value: "Home", Odd: "1.95"},
+					{Value: "Draw", Odd: "3.40"},
+					{Value: "Away", Odd: "bad"},
+				}},
+				{ID: 4, Name: "Asian Handicap", Values: []models.FootballAPIBetValue{{Value: "Home -1", Odd: "2.10"}}},
+			}},
+			{ID: 99, Name: "Iddaa", Bets: []models.FootballAPIBet{
+				{ID: 1, Name: "Match Winner", Values: []models.FootballAPIBetValue{{Value: "Home", Odd: "1.80"}}},
+			}},
+		},
+	}
+
+	quotes := apiFootballQuotes(42, odds)
+	if len(quotes) != 2 {
+		t.Fatalf("got %d quotes, want 2: %+v", len(quotes), quotes)
+	}
+	if quotes[0].Bookmaker != "bet365" || quotes[0].ProviderBookmakerID != 8 || quotes[0].EventID != 42 {
+		t.Errorf("unexpected quote %+v", quotes[0])
+	}
+	if quotes[1].Selection.Pick != "x" || quotes[1].Odds != 3.40 {
+		t.Errorf("unexpected draw quote %+v", quotes[1])
+	}
+}
+
+func TestComparePrices(t *testing.T) {
+	market := int32(1)
+	row := func(outcome, bookmaker string, odds float64, suspended bool) generated.GetEventBookmakerOddsRow {
+		return generated.GetEventBookmakerOddsRow{
+			MarketTypeID: &market,
+			MarketCode:   "1_1",
+			MarketName:   "Maç Sonucu",
+			Outcome:      outcome,
+			Bookmaker:    bookmaker,
+			OddsValue:    odds,
+			IsSuspended:  suspended,
+		}
+	}
+	rows := []generated.GetEventBookmakerOddsRow{
+		row("1", "bet365", 2.10, false),
+		row("1", "pinnacle", 2.05, false),
+		row("1", "iddaa", 1.80, false),
+		row("1", "unibet", 2.00, false),
+		row("X", "iddaa", 3.30, false),
+		row("X", "bet365", 3.25, false),
+		row("2", "iddaa", 4.00, true),
+		row("2", "bet365", 4.20, false),
+	}
+
+	comparisons := ComparePrices(rows, 5, 2)
+	if len(comparisons) != 2 {
+		t.Fatalf("got %d comparisons, want 2 (suspended Iddaa outcome skipped)", len(comparisons))
+	}
+
+	home := comparisons[0]
+	if home.Outcome != "1" || home.IddaaOdds != 1.80 || home.MarketOdds != 2.05 {
+		t.Errorf("unexpected home comparison %+v", home)
+	}
+	if home.BestBookmaker != "bet365" || home.BestOdds != 2.10 || home.BookmakerCount != 3 {
+		t.Errorf("unexpected best price %+v", home)
+	}
+	if want := (1.80/2.05 - 1) * 100; math.Abs(home.Deviation-want) > 1e-9 || !home.OutOfLine {
+		t.Errorf("deviation = %.2f (out of line %v), want %.2f", home.Deviation, home.OutOfLine, want)
+	}
+	if home.Prices[0].Bookmaker != "bet365" || home.Prices[len(home.Prices)-1].Bookmaker != "iddaa" {
+		t.Errorf("prices not ordered best first: %+v", home.Prices)
+	}
+
+	draw := comparisons[1]
+	if draw.OutOfLine {
+		t.Errorf("draw priced by a single other book should not be out of line: %+v", draw)
+	}
+	if draw.BestBookmaker != "iddaa" {
+		t.Errorf("best bookmaker = %s, want iddaa", draw.BestBookmaker)
+	}
+}
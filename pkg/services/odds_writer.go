@@ -0,0 +1,362 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+)
+
+// Odds ingestion modes selectable per job
+const (
+	// OddsIngestUnnest sends parallel arrays through UNNEST in chunks
+	OddsIngestUnnest = "unnest"
+	// OddsIngestCopy streams rows with COPY into staging tables and merges them in one transaction
+	OddsIngestCopy = "copy"
+)
+
+// OddsBatch is one write of current odds and the history rows of the prices that changed.
+// In-play history rows are kept apart since they are stamped with the live score and minute.
+type OddsBatch struct {
+	Current     generated.BulkUpsertCurrentOddsParams
+	History     generated.BulkInsertOddsHistoryParams
+	LiveHistory generated.BulkInsertOddsHistoryParams
+}
+
+// OddsWriter stores odds batches into current_odds and odds_history
+type OddsWriter interface {
+	WriteOdds(ctx context.Context, batch OddsBatch) error
+}
+
+// TxBeginner starts the transaction the COPY writer works in, *pgxpool.Pool and pgx.Tx both qualify
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// NewOddsWriter returns the writer of the given ingestion mode, the empty mode is unnest
+func NewOddsWriter(mode string, db TxBeginner, queries *generated.Queries) (OddsWriter, error) {
+	switch mode {
+	case "", OddsIngestUnnest:
+		return NewUnnestOddsWriter(queries), nil
+	case OddsIngestCopy:
+		return NewCopyOddsWriter(db), nil
+	default:
+		return nil, fmt.Errorf("unknown odds ingest mode %q, expected %s or %s", mode, OddsIngestUnnest, OddsIngestCopy)
+	}
+}
+
+// UnnestOddsWriter writes batches with the BulkUpsertCurrentOdds and BulkInsert*OddsHistory queries.
+// Chunks are independent: a failed chunk is logged and the others are still written.
+type UnnestOddsWriter struct {
+	db     *generated.Queries
+	logger *logger.Logger
+}
+
+// NewUnnestOddsWriter creates the UNNEST based writer
+func NewUnnestOddsWriter(db *generated.Queries) *UnnestOddsWriter {
+	return &UnnestOddsWriter{
+		db:     db,
+		logger: logger.New("odds-writer"),
+	}
+}
+
+// WriteOdds upserts current odds in chunks of 1000 and inserts history in chunks of 500.
+// The first current odds failure is returned, history failures are only logged.
+func (w *UnnestOddsWriter) WriteOdds(ctx context.Context, batch OddsBatch) error {
+	var upsertErr error
+
+	const chunkSize = 1000
+	current := batch.Current
+	for i := 0; i < len(current.EventIds); i += chunkSize {
+		end := min(i+chunkSize, len(current.EventIds))
+
+		err := w.db.BulkUpsertCurrentOdds(ctx, generated.BulkUpsertCurrentOddsParams{
+			EventIds:      current.EventIds[i:end],
+			MarketTypeIds: current.MarketTypeIds[i:end],
+			Outcomes:      current.Outcomes[i:end],
+			OddsValues:    current.OddsValues[i:end],
+			MarketParams:  current.MarketParams[i:end],
+			RecordedAt:    current.RecordedAt,
+		})
+		if err != nil {
+			w.logger.Error().
+				Err(err).
+				Int("chunk_start", i).
+				Int("chunk_size", end-i).
+				Msg("Failed to bulk upsert odds chunk")
+			if upsertErr == nil {
+				upsertErr = fmt.Errorf("failed to bulk upsert current odds: %w", err)
+			}
+		}
+	}
+
+	w.insertHistory(ctx, batch.History, false)
+	w.insertHistory(ctx, batch.LiveHistory, true)
+
+	return upsertErr
+}
+
+// insertHistory writes history rows in chunks, logging failed chunks
+func (w *UnnestOddsWriter) insertHistory(ctx context.Context, rows generated.BulkInsertOddsHistoryParams, inPlay bool) {
+	const historyChunkSize = 500
+	for i := 0; i < len(rows.EventIds); i += historyChunkSize {
+		end := min(i+historyChunkSize, len(rows.EventIds))
+
+		chunk := generated.BulkInsertOddsHistoryParams{
+			EventIds:           rows.EventIds[i:end],
+			MarketTypeIds:      rows.MarketTypeIds[i:end],
+			Outcomes:           rows.Outcomes[i:end],
+			OddsValues:         rows.OddsValues[i:end],
+			PreviousValues:     rows.PreviousValues[i:end],
+			ChangeAmounts:      rows.ChangeAmounts[i:end],
+			ChangePercentages:  rows.ChangePercentages[i:end],
+			Multipliers:        rows.Multipliers[i:end],
+			IsReverseMovements: rows.IsReverseMovements[i:end],
+			SignificanceLevels: rows.SignificanceLevels[i:end],
+			MinutesToKickoffs:  rows.MinutesToKickoffs[i:end],
+			MarketParams:       rows.MarketParams[i:end],
+			RecordedAt:         rows.RecordedAt,
+		}
+
+		var err error
+		if inPlay {
+			err = w.db.BulkInsertLiveOddsHistory(ctx, generated.BulkInsertLiveOddsHistoryParams(chunk))
+		} else {
+			err = w.db.BulkInsertOddsHistory(ctx, chunk)
+		}
+		if err != nil {
+			w.logger.Error().
+				Err(err).
+				Int("history_chunk_size", end-i).
+				Bool("in_play", inPlay).
+				Msg("Failed to insert odds history")
+		}
+	}
+}
+
+// Staging tables are temporary, so concurrent jobs on other connections never see each other's rows.
+// They live as long as the pooled connection; ON COMMIT DELETE ROWS empties them after every batch
+// and the TRUNCATE covers batches written inside an outer transaction.
+const createOddsStaging = `
+CREATE TEMPORARY TABLE IF NOT EXISTS current_odds_staging (
+    event_id INTEGER NOT NULL,
+    market_type_id INTEGER NOT NULL,
+    outcome TEXT NOT NULL,
+    odds_value DOUBLE PRECISION NOT NULL,
+    market_params JSONB
+) ON COMMIT DELETE ROWS;
+
+CREATE TEMPORARY TABLE IF NOT EXISTS odds_history_staging (
+    event_id INTEGER NOT NULL,
+    market_type_id INTEGER NOT NULL,
+    outcome TEXT NOT NULL,
+    odds_value DOUBLE PRECISION NOT NULL,
+    previous_value DOUBLE PRECISION,
+    change_amount DOUBLE PRECISION,
+    change_percentage DOUBLE PRECISION,
+    multiplier DOUBLE PRECISION,
+    is_reverse_movement BOOLEAN,
+    significance_level TEXT,
+    minutes_to_kickoff INTEGER,
+    market_params JSONB,
+    in_play BOOLEAN NOT NULL
+) ON COMMIT DELETE ROWS;
+
+TRUNCATE current_odds_staging, odds_history_staging;
+`
+
+// mergeCurrentOddsStaging matches BulkUpsertCurrentOdds
+const mergeCurrentOddsStaging = `
+INSERT INTO
+    current_odds (
+        event_id,
+        market_type_id,
+        outcome,
+        odds_value,
+        opening_value,
+        highest_value,
+        lowest_value,
+        market_params,
+        last_updated
+    )
+SELECT
+    event_id,
+    market_type_id,
+    outcome,
+    odds_value,
+    odds_value,
+    odds_value,
+    odds_value,
+    market_params,
+    COALESCE($1::timestamp, NOW())
+FROM
+    current_odds_staging ON CONFLICT (event_id, market_type_id, outcome, bookmaker) DO
+UPDATE
+SET
+    odds_value = EXCLUDED.odds_value,
+    highest_value = GREATEST(current_odds.highest_value, EXCLUDED.odds_value),
+    lowest_value = LEAST(current_odds.lowest_value, EXCLUDED.odds_value),
+    last_updated = EXCLUDED.last_updated
+WHERE
+    current_odds.odds_value IS DISTINCT
+FROM
+    EXCLUDED.odds_value
+    -- Replayed payloads never overwrite newer prices
+    AND (
+        current_odds.last_updated IS NULL
+        OR current_odds.last_updated <= EXCLUDED.last_updated
+    )
+`
+
+// mergeOddsHistoryStaging matches BulkInsertOddsHistory, and BulkInsertLiveOddsHistory for in-play rows
+const mergeOddsHistoryStaging = `
+INSERT INTO
+    odds_history (
+        event_id,
+        market_type_id,
+        outcome,
+        odds_value,
+        previous_value,
+        change_amount,
+        change_percentage,
+        multiplier,
+        is_reverse_movement,
+        significance_level,
+        minutes_to_kickoff,
+        market_params,
+        recorded_at,
+        in_play,
+        live_home_score,
+        live_away_score,
+        live_minute
+    )
+SELECT
+    s.event_id,
+    s.market_type_id,
+    s.outcome,
+    s.odds_value,
+    s.previous_value,
+    s.change_amount,
+    s.change_percentage,
+    s.multiplier,
+    s.is_reverse_movement,
+    s.significance_level,
+    s.minutes_to_kickoff,
+    s.market_params,
+    COALESCE($1::timestamp, NOW()),
+    s.in_play,
+    CASE WHEN s.in_play THEN e.home_score END,
+    CASE WHEN s.in_play THEN e.away_score END,
+    CASE WHEN s.in_play THEN e.minute_of_match END
+FROM
+    odds_history_staging s
+    JOIN events e ON e.id = s.event_id
+`
+
+var (
+	currentOddsStagingColumns = []string{"event_id", "market_type_id", "outcome", "odds_value", "market_params"}
+	oddsHistoryStagingColumns = []string{
+		"event_id", "market_type_id", "outcome", "odds_value", "previous_value", "change_amount",
+		"change_percentage", "multiplier", "is_reverse_movement", "significance_level",
+		"minutes_to_kickoff", "market_params", "in_play",
+	}
+)
+
+// CopyOddsWriter streams batches with COPY into temporary staging tables and merges them into
+// current_odds and odds_history in one transaction, so a batch is written completely or not at all
+type CopyOddsWriter struct {
+	db TxBeginner
+}
+
+// NewCopyOddsWriter creates the COPY based writer
+func NewCopyOddsWriter(db TxBeginner) *CopyOddsWriter {
+	return &CopyOddsWriter{db: db}
+}
+
+// WriteOdds copies and merges the whole batch, returning the first failure
+func (w *CopyOddsWriter) WriteOdds(ctx context.Context, batch OddsBatch) error {
+	historyRows := len(batch.History.EventIds) + len(batch.LiveHistory.EventIds)
+	if len(batch.Current.EventIds) == 0 && historyRows == 0 {
+		return nil
+	}
+
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, createOddsStaging); err != nil {
+		return fmt.Errorf("failed to prepare staging tables: %w", err)
+	}
+
+	current := batch.Current
+	if len(current.EventIds) > 0 {
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{"current_odds_staging"}, currentOddsStagingColumns,
+			pgx.CopyFromSlice(len(current.EventIds), func(i int) ([]any, error) {
+				return []any{
+					current.EventIds[i],
+					current.MarketTypeIds[i],
+					current.Outcomes[i],
+					current.OddsValues[i],
+					current.MarketParams[i],
+				}, nil
+			}))
+		if err != nil {
+			return fmt.Errorf("failed to copy current odds: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, mergeCurrentOddsStaging, current.RecordedAt); err != nil {
+			return fmt.Errorf("failed to merge current odds: %w", err)
+		}
+	}
+
+	if historyRows > 0 {
+		prematch, live := batch.History, batch.LiveHistory
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{"odds_history_staging"}, oddsHistoryStagingColumns,
+			pgx.CopyFromSlice(historyRows, func(i int) ([]any, error) {
+				if i < len(prematch.EventIds) {
+					return historyCopyRow(prematch, i, false), nil
+				}
+				return historyCopyRow(live, i-len(prematch.EventIds), true), nil
+			}))
+		if err != nil {
+			return fmt.Errorf("failed to copy odds history: %w", err)
+		}
+
+		recordedAt := prematch.RecordedAt
+		if len(prematch.EventIds) == 0 {
+			recordedAt = live.RecordedAt
+		}
+		if _, err := tx.Exec(ctx, mergeOddsHistoryStaging, recordedAt); err != nil {
+			return fmt.Errorf("failed to merge odds history: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit odds batch: %w", err)
+	}
+	return nil
+}
+
+// historyCopyRow returns row i of a history batch in odds_history_staging column order
+func historyCopyRow(rows generated.BulkInsertOddsHistoryParams, i int, inPlay bool) []any {
+	return []any{
+		rows.EventIds[i],
+		rows.MarketTypeIds[i],
+		rows.Outcomes[i],
+		rows.OddsValues[i],
+		rows.PreviousValues[i],
+		rows.ChangeAmounts[i],
+		rows.ChangePercentages[i],
+		rows.Multipliers[i],
+		rows.IsReverseMovements[i],
+		rows.SignificanceLevels[i],
+		rows.MinutesToKickoffs[i],
+		rows.MarketParams[i],
+		inPlay,
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+func TestNewOddsWriter(t *testing.T) {
+	queries := generated.New(nil)
+
+	for _, mode := range []string{"", OddsIngestUnnest} {
+		writer, err := NewOddsWriter(mode, nil, queries)
+		if err != nil {
+			t.Fatalf("NewOddsWriter(%q) error: %v", mode, err)
+		}
+		if _, ok := writer.(*UnnestOddsWriter); !ok {
+			t.Errorf("NewOddsWriter(%q) = %T, want *UnnestOddsWriter", mode, writer)
+		}
+	}
+
+	writer, err := NewOddsWriter(OddsIngestCopy, nil, queries)
+	if err != nil {
+		t.Fatalf("NewOddsWriter(copy) error: %v", err)
+	}
+	if _, ok := writer.(*CopyOddsWriter); !ok {
+		t.Errorf("NewOddsWriter(copy) = %T, want *CopyOddsWriter", writer)
+	}
+
+	if _, err := NewOddsWriter("bulk", nil, queries); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+func TestCopyOddsWriterSkipsEmptyBatch(t *testing.T) {
+	// A nil database would panic if the writer opened a transaction
+	if err := NewCopyOddsWriter(nil).WriteOdds(context.Background(), OddsBatch{}); err != nil {
+		t.Fatalf("WriteOdds(empty) error: %v", err)
+	}
+}
+
+func TestHistoryCopyRowMatchesStagingColumns(t *testing.T) {
+	rows := generated.BulkInsertOddsHistoryParams{
+		EventIds:           []int32{1},
+		MarketTypeIds:      []int32{2},
+		Outcomes:           []string{"1"},
+		OddsValues:         []float64{1.9},
+		PreviousValues:     []float64{2.0},
+		ChangeAmounts:      []float64{-0.1},
+		ChangePercentages:  []float64{-5},
+		Multipliers:        []float64{0.95},
+		IsReverseMovements: []bool{false},
+		SignificanceLevels: []string{"normal"},
+		MinutesToKickoffs:  []int32{90},
+		MarketParams:       [][]byte{[]byte(`{}`)},
+	}
+
+	row := historyCopyRow(rows, 0, true)
+	if len(row) != len(oddsHistoryStagingColumns) {
+		t.Fatalf("got %d values for %d staging columns", len(row), len(oddsHistoryStagingColumns))
+	}
+	if inPlay, ok := row[len(row)-1].(bool); !ok || !inPlay {
+		t.Errorf("last value = %v, want in_play true", row[len(row)-1])
+	}
+}
+
+// BenchmarkOddsWriter compares the UNNEST and COPY ingestion paths against a real database:
+//
+//	BENCH_DATABASE_URL=postgres://... go test ./pkg/services -run '^$' -bench OddsWriter
+//
+// Batches use existing events and market types inside a transaction that is rolled back.
+// Every tenth price is written as a history row, roughly what a bulletin sync sees.
+func BenchmarkOddsWriter(b *testing.B) {
+	url := os.Getenv("BENCH_DATABASE_URL")
+	if url == "" {
+		b.Skip("BENCH_DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		b.Fatalf("failed to connect: %v", err)
+	}
+	defer pool.Close()
+
+	for _, size := range []int{1000, 10000} {
+		for _, mode := range []string{OddsIngestUnnest, OddsIngestCopy} {
+			b.Run(fmt.Sprintf("%s/%d", mode, size), func(b *testing.B) {
+				tx, err := pool.Begin(ctx)
+				if err != nil {
+					b.Fatalf("failed to begin transaction: %v", err)
+				}
+				defer func() { _ = tx.Rollback(ctx) }()
+
+				batch := benchmarkOddsBatch(ctx, b, tx, size)
+				writer, err := NewOddsWriter(mode, tx, generated.New(tx))
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					// Move every price so the upsert updates each row
+					for j := range batch.Current.OddsValues {
+						batch.Current.OddsValues[j] = 1.5 + float64((i+j)%100)/100
+					}
+					if err := writer.WriteOdds(ctx, batch); err != nil {
+						b.Fatalf("WriteOdds error: %v", err)
+					}
+				}
+				b.ReportMetric(float64(size*b.N)/b.Elapsed().Seconds(), "odds/s")
+			})
+		}
+	}
+}
+
+// benchmarkOddsBatch builds a batch of size distinct prices over existing events and market types
+func benchmarkOddsBatch(ctx context.Context, b *testing.B, tx pgx.Tx, size int) OddsBatch {
+	b.Helper()
+
+	var eventIDs, marketTypeIDs []int32
+	if err := tx.QueryRow(ctx, "SELECT ARRAY(SELECT id FROM events ORDER BY id DESC LIMIT 500)").Scan(&eventIDs); err != nil {
+		b.Fatalf("failed to load events: %v", err)
+	}
+	if err := tx.QueryRow(ctx, "SELECT ARRAY(SELECT id FROM market_types ORDER BY id LIMIT 20)").Scan(&marketTypeIDs); err != nil {
+		b.Fatalf("failed to load market types: %v", err)
+	}
+
+	const outcomesPerMarket = 5
+	if len(eventIDs)*len(marketTypeIDs)*outcomesPerMarket < size {
+		b.Skipf("need %d distinct prices, database has %d events and %d market types", size, len(eventIDs), len(marketTypeIDs))
+	}
+
+	var batch OddsBatch
+	params := []byte(`{}`)
+	for i := 0; i < size; i++ {
+		eventID := eventIDs[i/(len(marketTypeIDs)*outcomesPerMarket)]
+		marketTypeID := marketTypeIDs[(i/outcomesPerMarket)%len(marketTypeIDs)]
+		outcome := fmt.Sprintf("bench-%d", i%outcomesPerMarket)
+
+		batch.Current.EventIds = append(batch.Current.EventIds, eventID)
+		batch.Current.MarketTypeIds = append(batch.Current.MarketTypeIds, marketTypeID)
+		batch.Current.Outcomes = append(batch.Current.Outcomes, outcome)
+		batch.Current.OddsValues = append(batch.Current.OddsValues, 2.0)
+		batch.Current.MarketParams = append(batch.Current.MarketParams, params)
+
+		if i%10 != 0 {
+			continue
+		}
+		history := &batch.History
+		history.EventIds = append(history.EventIds, eventID)
+		history.MarketTypeIds = append(history.MarketTypeIds, marketTypeID)
+		history.Outcomes = append(history.Outcomes, outcome)
+		history.OddsValues = append(history.OddsValues, 2.1)
+		history.PreviousValues = append(history.PreviousValues, 2.0)
+		history.ChangeAmounts = append(history.ChangeAmounts, 0.1)
+		history.ChangePercentages = append(history.ChangePercentages, 5)
+		history.Multipliers = append(history.Multipliers, 1.05)
+		history.IsReverseMovements = append(history.IsReverseMovements, false)
+		history.SignificanceLevels = append(history.SignificanceLevels, "normal")
+		history.MinutesToKickoffs = append(history.MinutesToKickoffs, 120)
+		history.MarketParams = append(history.MarketParams, params)
+	}
+
+	return batch
+}
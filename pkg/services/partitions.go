@@ -0,0 +1,243 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/iddaa-lens/core/internal/config"
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+)
+
+// PartitionPolicy is the retention of one monthly partitioned history table
+type PartitionPolicy struct {
+	Table string
+	// RetentionMonths keeps partitions ending within this many months before the current month, 0 keeps all
+	RetentionMonths int
+}
+
+// PartitionService keeps future partitions of the history tables ready and archives expired ones
+type PartitionService struct {
+	pool          *pgxpool.Pool
+	db            *generated.Queries
+	logger        *logger.Logger
+	policies      []PartitionPolicy
+	archiveDir    string
+	premakeMonths int
+}
+
+// NewPartitionService creates a partition service for odds_history, outcome_distribution_history
+// and betting_volume_history. Archiving streams partitions with COPY, so it needs the pool itself.
+func NewPartitionService(pool *pgxpool.Pool, cfg config.HistoryConfig) *PartitionService {
+	return &PartitionService{
+		pool:   pool,
+		db:     generated.New(pool),
+		logger: logger.New("partition-service"),
+		policies: []PartitionPolicy{
+			{Table: "odds_history", RetentionMonths: cfg.OddsRetentionMonths},
+			{Table: "outcome_distribution_history", RetentionMonths: cfg.DistributionRetentionMonths},
+			{Table: "betting_volume_history", RetentionMonths: cfg.VolumeRetentionMonths},
+		},
+		archiveDir:    cfg.ArchiveDir,
+		premakeMonths: cfg.PremakeMonths,
+	}
+}
+
+// PartitionStats summarizes a maintenance run
+type PartitionStats struct {
+	Created  int
+	Archived int
+	// ArchivedRows counts rows exported from the dropped partitions
+	ArchivedRows int64
+}
+
+// EnsurePartitions creates the partitions of the current month and the premake months after it
+func (s *PartitionService) EnsurePartitions(ctx context.Context, now time.Time) (int, error) {
+	return s.EnsureRange(ctx, now, now.AddDate(0, s.premakeMonths, 0))
+}
+
+// EnsureRange creates the missing partitions of every history table for the months from through to.
+// The history tables have no default partition, rows recorded outside every partition are rejected.
+func (s *PartitionService) EnsureRange(ctx context.Context, from, to time.Time) (int, error) {
+	created := 0
+	for _, policy := range s.policies {
+		n, err := s.db.CreateHistoryPartitions(ctx, generated.CreateHistoryPartitionsParams{
+			ParentTable: policy.Table,
+			FromTime:    pgtype.Timestamp{Time: from, Valid: true},
+			ToTime:      pgtype.Timestamp{Time: to, Valid: true},
+		})
+		if err != nil {
+			return created, fmt.Errorf("failed to create partitions of %s: %w", policy.Table, err)
+		}
+		created += int(n)
+	}
+	return created, nil
+}
+
+// ApplyRetention exports every expired partition to a gzipped CSV file in the archive directory and
+// drops it. Without an archive directory nothing is dropped.
+func (s *PartitionService) ApplyRetention(ctx context.Context, now time.Time) (PartitionStats, error) {
+	var stats PartitionStats
+
+	if s.archiveDir == "" {
+		s.logger.Info().
+			Str("action", "retention_skipped").
+			Msg("HISTORY_ARCHIVE_DIR not set, keeping every history partition")
+		return stats, nil
+	}
+
+	tables := make([]string, 0, len(s.policies))
+	for _, policy := range s.policies {
+		tables = append(tables, policy.Table)
+	}
+
+	partitions, err := s.db.ListHistoryPartitions(ctx, tables)
+	if err != nil {
+		return stats, fmt.Errorf("failed to list history partitions: %w", err)
+	}
+
+	for _, partition := range expiredPartitions(partitions, s.policies, now) {
+		rows, path, err := s.archivePartition(ctx, partition)
+		if err != nil {
+			return stats, fmt.Errorf("failed to archive %s: %w", partition.PartitionName, err)
+		}
+
+		if err := s.dropPartition(ctx, partition, rows); err != nil {
+			return stats, fmt.Errorf("failed to drop %s: %w", partition.PartitionName, err)
+		}
+
+		stats.Archived++
+		stats.ArchivedRows += rows
+
+		s.logger.Info().
+			Str("action", "partition_archived").
+			Str("partition", partition.PartitionName).
+			Time("range_start", partition.RangeStart.Time).
+			Int64("rows", rows).
+			Str("path", path).
+			Msg("Archived and dropped history partition")
+	}
+
+	return stats, nil
+}
+
+// expiredPartitions returns the partitions whose whole range lies before their table's retention cutoff,
+// the first day of the month retention months before the current one
+func expiredPartitions(partitions []generated.ListHistoryPartitionsRow, policies []PartitionPolicy, now time.Time) []generated.ListHistoryPartitionsRow {
+	retention := make(map[string]int, len(policies))
+	for _, policy := range policies {
+		retention[policy.Table] = policy.RetentionMonths
+	}
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	var expired []generated.ListHistoryPartitionsRow
+	for _, partition := range partitions {
+		months := retention[partition.ParentTable]
+		if months <= 0 || !partition.RangeEnd.Valid {
+			continue
+		}
+		cutoff := monthStart.AddDate(0, -months, 0)
+		if !partition.RangeEnd.Time.After(cutoff) {
+			expired = append(expired, partition)
+		}
+	}
+	return expired
+}
+
+// archivePartition streams the partition with COPY into <archive>/<table>/<partition>.csv.gz.
+// The file is written under a temporary name and renamed once complete.
+func (s *PartitionService) archivePartition(ctx context.Context, partition generated.ListHistoryPartitionsRow) (int64, string, error) {
+	dir := filepath.Join(s.archiveDir, partition.ParentTable)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	path := filepath.Join(dir, partition.PartitionName+".csv.gz")
+	tmpPath := path + ".tmp"
+
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmpPath) }()
+	defer func() { _ = file.Close() }()
+
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	gz := gzip.NewWriter(file)
+	copySQL := fmt.Sprintf("COPY %s TO STDOUT WITH (FORMAT csv, HEADER)", pgx.Identifier{partition.PartitionName}.Sanitize())
+	tag, err := conn.Conn().PgConn().CopyTo(ctx, gz, copySQL)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to copy rows: %w", err)
+	}
+
+	if err := gz.Close(); err != nil {
+		return 0, "", fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return 0, "", fmt.Errorf("failed to sync archive: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return 0, "", fmt.Errorf("failed to close archive: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, "", fmt.Errorf("failed to move archive into place: %w", err)
+	}
+
+	return tag.RowsAffected(), path, nil
+}
+
+// dropPartition drops an archived partition, refusing when rows were added after the export.
+// Alerts and CLV rows pointing into an odds_history partition go with it, as the foreign keys
+// to the unpartitioned table used to cascade.
+func (s *PartitionService) dropPartition(ctx context.Context, partition generated.ListHistoryPartitionsRow, archivedRows int64) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	name := pgx.Identifier{partition.PartitionName}.Sanitize()
+
+	// Block writes to the partition until it is dropped
+	if _, err := tx.Exec(ctx, "LOCK TABLE "+name+" IN SHARE MODE"); err != nil {
+		return fmt.Errorf("failed to lock partition: %w", err)
+	}
+
+	var rows int64
+	if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM "+name).Scan(&rows); err != nil {
+		return fmt.Errorf("failed to count rows: %w", err)
+	}
+	if rows != archivedRows {
+		return fmt.Errorf("partition has %d rows but %d were archived", rows, archivedRows)
+	}
+
+	// Dropping a partition does not fire the odds_history delete trigger
+	if partition.ParentTable == "odds_history" {
+		for _, table := range []string{"movement_alerts", "closing_line_values"} {
+			query := fmt.Sprintf("DELETE FROM %s WHERE odds_history_id IN (SELECT id FROM %s)", table, name)
+			if _, err := tx.Exec(ctx, query); err != nil {
+				return fmt.Errorf("failed to delete %s rows: %w", table, err)
+			}
+		}
+	}
+
+	if _, err := tx.Exec(ctx, "DROP TABLE "+name); err != nil {
+		return fmt.Errorf("failed to drop partition: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+func TestExpiredPartitions(t *testing.T) {
+	month := func(table string, year int, m time.Month) generated.ListHistoryPartitionsRow {
+		start := time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+		return generated.ListHistoryPartitionsRow{
+			ParentTable:   table,
+			PartitionName: table + start.Format("_p2006_01"),
+			RangeStart:    pgtype.Timestamp{Time: start, Valid: true},
+			RangeEnd:      pgtype.Timestamp{Time: start.AddDate(0, 1, 0), Valid: true},
+		}
+	}
+
+	partitions := []generated.ListHistoryPartitionsRow{
+		month("odds_history", 2026, time.March),
+		month("odds_history", 2026, time.April),
+		month("odds_history", 2026, time.May),
+		month("outcome_distribution_history", 2025, time.January),
+		month("betting_volume_history", 2026, time.January),
+	}
+	policies := []PartitionPolicy{
+		{Table: "odds_history", RetentionMonths: 6},
+		{Table: "outcome_distribution_history", RetentionMonths: 0},
+		{Table: "betting_volume_history", RetentionMonths: 12},
+	}
+
+	// Six months before October 2026 is April: March ends on the cutoff, April ends after it
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	expired := expiredPartitions(partitions, policies, now)
+
+	if len(expired) != 1 || expired[0].PartitionName != "odds_history_p2026_03" {
+		t.Errorf("expired = %+v, want only odds_history_p2026_03", expired)
+	}
+}
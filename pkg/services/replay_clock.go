@@ -0,0 +1,33 @@
+package services
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// replayClock lets reprocessing stamp derived rows with the fetch time of an archived payload.
+// Left unset, rows are stamped by the database clock as usual.
+type replayClock struct {
+	at time.Time
+}
+
+// SetReplayTime makes following writes use t as the current time, the zero time restores the live clock
+func (c *replayClock) SetReplayTime(t time.Time) {
+	c.at = t
+}
+
+func (c *replayClock) now() time.Time {
+	if c.at.IsZero() {
+		return time.Now()
+	}
+	return c.at
+}
+
+// recordedAt is the timestamp passed to the bulk writes, NULL lets the query fall back to NOW()
+func (c *replayClock) recordedAt() pgtype.Timestamp {
+	if c.at.IsZero() {
+		return pgtype.Timestamp{}
+	}
+	return pgtype.Timestamp{Time: c.at, Valid: true}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+// ReplayOdds is the price state archived payloads are compared with during a replay. current_odds
+// holds today's prices, so the state starts from the prices as they stood before the replayed
+// range and follows the replayed writes from there. Payloads are applied one at a time, it is not
+// safe for concurrent use.
+type ReplayOdds struct {
+	db     *generated.Queries
+	before pgtype.Timestamp
+	prices map[replayOddsKey]float64
+	// Loaded events and their kickoff
+	events map[int32]pgtype.Timestamp
+}
+
+type replayOddsKey struct {
+	eventID      int32
+	marketTypeID int32
+	outcome      string
+}
+
+// NewReplayOdds creates the state of a replay starting at from
+func NewReplayOdds(db *generated.Queries, from time.Time) *ReplayOdds {
+	return &ReplayOdds{
+		db:     db,
+		before: pgtype.Timestamp{Time: from, Valid: true},
+		prices: make(map[replayOddsKey]float64),
+		events: make(map[int32]pgtype.Timestamp),
+	}
+}
+
+// CurrentOdds returns the replayed prices of keys in the shape of BulkGetCurrentOddsForComparison.
+// Events met for the first time are loaded as of the start of the replay.
+func (r *ReplayOdds) CurrentOdds(ctx context.Context, keys []OddsCacheKey) ([]generated.BulkGetCurrentOddsForComparisonRow, error) {
+	var missing []int32
+	seen := make(map[int32]bool)
+	for _, key := range keys {
+		if _, loaded := r.events[key.EventID]; !loaded && !seen[key.EventID] {
+			seen[key.EventID] = true
+			missing = append(missing, key.EventID)
+		}
+	}
+
+	if len(missing) > 0 {
+		rows, err := r.db.GetReplayOddsForComparison(ctx, generated.GetReplayOddsForComparisonParams{
+			BeforeTime: r.before,
+			EventIds:   missing,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load replay odds of %d events: %w", len(missing), err)
+		}
+		for _, row := range rows {
+			if row.EventID == nil || row.MarketTypeID == nil {
+				continue
+			}
+			key := replayOddsKey{eventID: *row.EventID, marketTypeID: *row.MarketTypeID, outcome: row.Outcome}
+			// Prices written by the replay meanwhile are newer
+			if _, ok := r.prices[key]; !ok {
+				r.prices[key] = row.OddsValue
+			}
+			r.events[key.eventID] = row.EventDate
+		}
+		// Events without prices stay unloaded, their first replayed prices are new and the next
+		// lookup loads the rows written for them
+	}
+
+	rows := make([]generated.BulkGetCurrentOddsForComparisonRow, 0, len(keys))
+	for _, key := range keys {
+		value, ok := r.prices[replayOddsKey{eventID: key.EventID, marketTypeID: key.MarketTypeID, outcome: key.Outcome}]
+		if !ok {
+			continue
+		}
+		eventID, marketTypeID := key.EventID, key.MarketTypeID
+		rows = append(rows, generated.BulkGetCurrentOddsForComparisonRow{
+			EventID:      &eventID,
+			MarketTypeID: &marketTypeID,
+			Outcome:      key.Outcome,
+			OddsValue:    value,
+			EventDate:    r.events[key.EventID],
+		})
+	}
+	return rows, nil
+}
+
+// Apply records the prices of a replayed write
+func (r *ReplayOdds) Apply(keys []OddsCacheKey, values []float64) {
+	for i, key := range keys {
+		r.prices[replayOddsKey{eventID: key.EventID, marketTypeID: key.MarketTypeID, outcome: key.Outcome}] = values[i]
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+func TestReplayOddsFollowsReplayedWrites(t *testing.T) {
+	home := OddsCacheKey{EventID: 1, MarketTypeID: 10, Outcome: "1"}
+	over := OddsCacheKey{EventID: 1, MarketTypeID: 11, Outcome: "Üst 2.5"}
+
+	// Event 1 already loaded as of the start of the range, so no query is made
+	replay := NewReplayOdds(generated.New(&versionDB{}), time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	kickoff := pgtype.Timestamp{Time: time.Date(2025, 3, 1, 19, 0, 0, 0, time.UTC), Valid: true}
+	replay.events[1] = kickoff
+	replay.prices[replayOddsKey{eventID: 1, marketTypeID: 10, outcome: "1"}] = 2.10
+
+	rows, err := replay.CurrentOdds(context.Background(), []OddsCacheKey{home, over})
+	if err != nil {
+		t.Fatalf("CurrentOdds() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0].OddsValue != 2.10 || rows[0].EventDate != kickoff {
+		t.Fatalf("rows = %+v, want the starting price of the home outcome only", rows)
+	}
+
+	replay.Apply([]OddsCacheKey{home, over}, []float64{1.95, 1.80})
+
+	rows, err = replay.CurrentOdds(context.Background(), []OddsCacheKey{home, over})
+	if err != nil {
+		t.Fatalf("CurrentOdds() error = %v", err)
+	}
+	got := make(map[string]float64)
+	for _, row := range rows {
+		got[row.Outcome] = row.OddsValue
+	}
+	if got["1"] != 1.95 || got["Üst 2.5"] != 1.80 {
+		t.Errorf("prices = %v, want the replayed writes", got)
+	}
+}
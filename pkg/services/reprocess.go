@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iddaa-lens/core/pkg/archive"
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+// Archived payload kinds that can be reprocessed
+const (
+	PayloadEvents        = "events"
+	PayloadDetailed      = "detailed"
+	PayloadDistributions = "distributions"
+	PayloadVolumes       = "volumes"
+)
+
+// reprocessEndpoints maps archived Iddaa endpoints to the payload kind they carry
+var reprocessEndpoints = map[string]string{
+	"/sportsbook/events":                   PayloadEvents,
+	"/sportsbook/event/{id}":               PayloadDetailed,
+	"/sportsbook/outcome-play-percentages": PayloadDistributions,
+	"/sportsbook/played-event-percentage":  PayloadVolumes,
+}
+
+// ReprocessEndpoints returns the archive endpoints carrying the given payload kinds
+func ReprocessEndpoints(kinds []string) []string {
+	wanted := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		wanted[kind] = true
+	}
+
+	var endpoints []string
+	for endpoint, kind := range reprocessEndpoints {
+		if wanted[kind] {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
+// PayloadKind returns the payload kind of an archived fetch, empty when it is not reprocessed
+func PayloadKind(entry archive.Entry) string {
+	if entry.Source != archive.SourceIddaa {
+		return ""
+	}
+	return reprocessEndpoints[entry.Endpoint]
+}
+
+// ReprocessStats counts the payloads applied by a Reprocessor
+type ReprocessStats struct {
+	Applied map[string]int
+	Skipped int
+	Failed  int
+}
+
+// Reprocessor rebuilds current_odds, odds_history, distributions and volumes from archived payloads.
+// Payloads must be applied oldest first; each one is stamped with its original fetch time. Odds
+// changes are measured from the prices as they stood at the start of the range, and current_odds
+// rows updated after a payload was fetched keep their newer price.
+type Reprocessor struct {
+	db            *generated.Queries
+	events        *EventsService
+	distributions *DistributionService
+	volumes       *VolumeService
+	logger        *logger.Logger
+	stats         ReprocessStats
+}
+
+// NewReprocessor creates a reprocessor writing through the regular sync services, replaying
+// payloads fetched from from on
+func NewReprocessor(db *generated.Queries, from time.Time, events *EventsService, distributions *DistributionService, volumes *VolumeService) *Reprocessor {
+	events.SetReplayOdds(NewReplayOdds(db, from))
+	return &Reprocessor{
+		db:            db,
+		events:        events,
+		distributions: distributions,
+		volumes:       volumes,
+		logger:        logger.New("reprocessor"),
+		stats:         ReprocessStats{Applied: make(map[string]int)},
+	}
+}
+
+// Stats returns the counts so far
+func (r *Reprocessor) Stats() ReprocessStats {
+	return r.stats
+}
+
+// Apply replays one archived payload. Payloads that no longer fit the database, such as a diff for
+// events that were never synced, are counted as skipped rather than failing the run.
+func (r *Reprocessor) Apply(ctx context.Context, entry archive.Entry, body []byte) error {
+	kind := PayloadKind(entry)
+	if kind == "" || entry.Status != http.StatusOK {
+		r.stats.Skipped++
+		return nil
+	}
+
+	r.events.SetReplayTime(entry.FetchedAt)
+	r.distributions.SetReplayTime(entry.FetchedAt)
+	r.volumes.SetReplayTime(entry.FetchedAt)
+
+	var err error
+	switch kind {
+	case PayloadEvents:
+		err = r.applyEvents(ctx, entry, body)
+	case PayloadDetailed:
+		err = r.applyDetailed(ctx, entry, body)
+	case PayloadDistributions:
+		err = r.distributions.ProcessDistributionPayload(ctx, entry.SportID, body)
+	case PayloadVolumes:
+		err = r.volumes.ProcessVolumePayload(ctx, entry.SportID, body)
+	}
+
+	switch {
+	case errors.Is(err, errReprocessSkip), errors.Is(err, ErrVersionGap):
+		r.logger.Debug().
+			Err(err).
+			Str("url", entry.URL).
+			Time("fetched_at", entry.FetchedAt).
+			Msg("Skipped archived payload")
+		r.stats.Skipped++
+		return nil
+	case err != nil:
+		r.stats.Failed++
+		return fmt.Errorf("failed to reprocess %s fetched at %s: %w", entry.URL, entry.FetchedAt.Format(time.RFC3339), err)
+	}
+	r.stats.Applied[kind]++
+	return nil
+}
+
+// errReprocessSkip marks payloads that carry nothing to apply
+var errReprocessSkip = errors.New("nothing to reprocess")
+
+func (r *Reprocessor) applyEvents(ctx context.Context, entry archive.Entry, body []byte) error {
+	var response models.IddaaEventsResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("failed to decode events: %w", err)
+	}
+	if !response.IsSuccess || response.Data == nil {
+		return errReprocessSkip
+	}
+
+	// Live bulletins (type=1) go through the in-play path, which stamps odds history as in play
+	if u, err := url.Parse(entry.URL); err == nil && u.Query().Get("type") == "1" {
+		_, err := r.events.ProcessLiveEvents(ctx, response.Data.Events)
+		return err
+	}
+	if response.Data.IsDiff {
+		return r.events.ProcessEventsDiff(ctx, &response)
+	}
+	return r.events.ProcessEventsResponse(ctx, &response)
+}
+
+func (r *Reprocessor) applyDetailed(ctx context.Context, entry archive.Entry, body []byte) error {
+	var response models.IddaaSingleEventResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("failed to decode event: %w", err)
+	}
+	if !response.IsSuccess || len(response.Data.Markets) == 0 {
+		return errReprocessSkip
+	}
+
+	externalID := response.Data.ID
+	if externalID == 0 {
+		// Fall back to the id in /sportsbook/event/{id}
+		u, err := url.Parse(entry.URL)
+		if err != nil {
+			return errReprocessSkip
+		}
+		if externalID, err = strconv.Atoi(u.Path[strings.LastIndex(u.Path, "/")+1:]); err != nil {
+			return errReprocessSkip
+		}
+	}
+
+	known, err := r.db.GetEventIDsByExternalIDs(ctx, []string{strconv.Itoa(externalID)})
+	if err != nil {
+		return fmt.Errorf("failed to look up event: %w", err)
+	}
+	if len(known) == 0 {
+		return errReprocessSkip
+	}
+	return r.events.ProcessDetailedMarkets(ctx, int(known[0].ID), response.Data.Markets)
+}
@@ -0,0 +1,432 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+// MarketFamily groups market types that are graded the same way
+type MarketFamily string
+
+const (
+	MarketFamily1X2          MarketFamily = "1x2"
+	MarketFamilyDoubleChance MarketFamily = "double_chance"
+	MarketFamilyOverUnder    MarketFamily = "over_under"
+	MarketFamilyBTTS         MarketFamily = "btts"
+	MarketFamilyHandicap     MarketFamily = "handicap"
+	MarketFamilyHTFT         MarketFamily = "ht_ft"
+	MarketFamilyCorrectScore MarketFamily = "correct_score"
+)
+
+// SettlementResult is the graded result of a single outcome
+type SettlementResult string
+
+const (
+	SettlementWon  SettlementResult = "won"
+	SettlementLost SettlementResult = "lost"
+	SettlementVoid SettlementResult = "void"
+	SettlementPush SettlementResult = "push"
+)
+
+// MarketSpec describes how a market type is graded
+type MarketSpec struct {
+	Family MarketFamily
+	// FirstHalf markets are graded on the half time score
+	FirstHalf bool
+}
+
+// Score is a home/away goal count
+type Score struct {
+	Home int
+	Away int
+}
+
+// MatchScore is the final score of an event; HalfTime is nil when unknown
+type MatchScore struct {
+	FullTime Score
+	HalfTime *Score
+}
+
+// ClassifyMarket maps an Iddaa market type name to a gradable market family.
+// Markets that are not decided by goals alone (corners, cards, time ranges, team totals) are not gradable.
+func ClassifyMarket(marketName string) (MarketSpec, bool) {
+	name := strings.ToLowerSpecial(unicode.TurkishCase, marketName)
+
+	for _, excluded := range []string{"korner", "kart", "dakika", "dk.", "ev sahibi", "deplasman", "2. yarı", "ikinci yarı", "tek/çift", "aralığı"} {
+		if strings.Contains(name, excluded) {
+			return MarketSpec{}, false
+		}
+	}
+
+	if strings.Contains(name, "ilk yarı/maç sonucu") || strings.Contains(name, "iy/ms") {
+		return MarketSpec{Family: MarketFamilyHTFT}, true
+	}
+
+	spec := MarketSpec{
+		FirstHalf: strings.Contains(name, "ilk yarı") || strings.Contains(name, "1. yarı"),
+	}
+
+	switch {
+	case strings.Contains(name, "çifte şans"):
+		spec.Family = MarketFamilyDoubleChance
+	case strings.Contains(name, "karşılıklı gol"):
+		spec.Family = MarketFamilyBTTS
+	case strings.Contains(name, "handikap"):
+		spec.Family = MarketFamilyHandicap
+	case strings.Contains(name, "skor"):
+		spec.Family = MarketFamilyCorrectScore
+	case strings.Contains(name, "alt/üst") || strings.Contains(name, "altı/üstü"):
+		spec.Family = MarketFamilyOverUnder
+	case strings.Contains(name, "maç sonucu") || strings.Contains(name, "yarı sonucu"):
+		spec.Family = MarketFamily1X2
+	default:
+		return MarketSpec{}, false
+	}
+
+	return spec, true
+}
+
+// SettleOutcome grades a single outcome against the final score.
+// It returns false when the outcome cannot be graded reliably, e.g. missing half time score or an unknown line.
+func SettleOutcome(spec MarketSpec, outcome string, params []string, score MatchScore) (SettlementResult, bool) {
+	if spec.Family == MarketFamilyHTFT {
+		if score.HalfTime == nil {
+			return "", false
+		}
+		parts := strings.Split(normalizeOutcome(outcome), "/")
+		if len(parts) != 2 {
+			return "", false
+		}
+		ht, ok1 := matchResult(parts[0])
+		ft, ok2 := matchResult(parts[1])
+		if !ok1 || !ok2 {
+			return "", false
+		}
+		return wonIf(ht == resultOf(*score.HalfTime) && ft == resultOf(score.FullTime)), true
+	}
+
+	final := score.FullTime
+	if spec.FirstHalf {
+		if score.HalfTime == nil {
+			return "", false
+		}
+		final = *score.HalfTime
+	}
+
+	name := normalizeOutcome(outcome)
+
+	switch spec.Family {
+	case MarketFamily1X2:
+		want, ok := matchResult(name)
+		if !ok {
+			return "", false
+		}
+		return wonIf(want == resultOf(final)), true
+
+	case MarketFamilyDoubleChance:
+		pair := strings.NewReplacer("-", "", "/", "", " ", "", "0", "x").Replace(name)
+		if len(pair) != 2 {
+			return "", false
+		}
+		first, ok1 := matchResult(pair[:1])
+		second, ok2 := matchResult(pair[1:])
+		if !ok1 || !ok2 || first == second {
+			return "", false
+		}
+		actual := resultOf(final)
+		return wonIf(actual == first || actual == second), true
+
+	case MarketFamilyOverUnder:
+		return settleOverUnder(name, outcome, params, final)
+
+	case MarketFamilyBTTS:
+		var wantBoth bool
+		switch name {
+		case "var", "evet", "yes":
+			wantBoth = true
+		case "yok", "hayır", "no":
+			wantBoth = false
+		default:
+			return "", false
+		}
+		both := final.Home > 0 && final.Away > 0
+		return wonIf(both == wantBoth), true
+
+	case MarketFamilyHandicap:
+		// Iddaa handicaps are three-way with the goal start for each side, e.g. "0:1"
+		if len(params) != 2 {
+			return "", false
+		}
+		homeStart, err1 := strconv.Atoi(params[0])
+		awayStart, err2 := strconv.Atoi(params[1])
+		if err1 != nil || err2 != nil {
+			return "", false
+		}
+		want, ok := matchResult(name)
+		if !ok {
+			return "", false
+		}
+		adjusted := Score{Home: final.Home + homeStart, Away: final.Away + awayStart}
+		return wonIf(want == resultOf(adjusted)), true
+
+	case MarketFamilyCorrectScore:
+		parts := strings.FieldsFunc(name, func(r rune) bool { return r == ':' || r == '-' })
+		if len(parts) != 2 {
+			return "", false
+		}
+		home, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+		away, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err1 != nil || err2 != nil {
+			return "", false
+		}
+		return wonIf(home == final.Home && away == final.Away), true
+	}
+
+	return "", false
+}
+
+func settleOverUnder(name, outcome string, params []string, final Score) (SettlementResult, bool) {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	var over bool
+	switch {
+	case strings.HasPrefix(fields[0], "üst"), strings.HasPrefix(fields[0], "over"):
+		over = true
+	case strings.HasPrefix(fields[0], "alt"), strings.HasPrefix(fields[0], "under"):
+		over = false
+	default:
+		return "", false
+	}
+
+	// The line is the market's single parameter, falling back to the number in the outcome name ("Alt 2.5")
+	lineText := ""
+	if len(params) == 1 {
+		lineText = params[0]
+	} else if trimmed := strings.Fields(strings.TrimSpace(outcome)); len(trimmed) > 1 {
+		lineText = trimmed[len(trimmed)-1]
+	}
+	line, err := strconv.ParseFloat(strings.ReplaceAll(lineText, ",", "."), 64)
+	if err != nil {
+		return "", false
+	}
+
+	// Quarter lines settle half won/half lost, which a single result cannot express
+	if frac := math.Mod(line*2, 1); frac != 0 {
+		return "", false
+	}
+
+	goals := float64(final.Home + final.Away)
+	switch {
+	case goals == line:
+		return SettlementPush, true
+	case over:
+		return wonIf(goals > line), true
+	default:
+		return wonIf(goals < line), true
+	}
+}
+
+// normalizeOutcome lowercases an outcome and strips the " (line)" suffix added during sync
+func normalizeOutcome(outcome string) string {
+	name := strings.TrimSpace(outcome)
+	if i := strings.Index(name, " ("); i > 0 {
+		name = name[:i]
+	}
+	return strings.ToLowerSpecial(unicode.TurkishCase, strings.TrimSpace(name))
+}
+
+// matchResult maps "1", "X"/"0" and "2" to a comparable result
+func matchResult(token string) (int, bool) {
+	switch strings.TrimSpace(token) {
+	case "1":
+		return 1, true
+	case "x", "0":
+		return 0, true
+	case "2":
+		return 2, true
+	}
+	return 0, false
+}
+
+func resultOf(score Score) int {
+	switch {
+	case score.Home > score.Away:
+		return 1
+	case score.Home < score.Away:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func wonIf(won bool) SettlementResult {
+	if won {
+		return SettlementWon
+	}
+	return SettlementLost
+}
+
+// SettlementService grades market outcomes once final scores are known
+type SettlementService struct {
+	db     *generated.Queries
+	logger *logger.Logger
+}
+
+// SettlementStats summarizes a settlement run
+type SettlementStats struct {
+	Events   int
+	Settled  int
+	Skipped  int
+	Voided   int64
+	Failures int
+	// GradedAlerts counts movement alerts graded or re-graded against settlements
+	GradedAlerts int64
+}
+
+// NewSettlementService creates a new settlement service
+func NewSettlementService(db *generated.Queries) *SettlementService {
+	return &SettlementService{
+		db:     db,
+		logger: logger.New("settlement-service"),
+	}
+}
+
+// ProcessPending voids cancelled events, settles events whose result is new or was corrected
+// and grades the movement alerts of settled outcomes
+func (s *SettlementService) ProcessPending(ctx context.Context, limit int32) (SettlementStats, error) {
+	var stats SettlementStats
+
+	voided, err := s.db.VoidCancelledEventOutcomes(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("failed to void cancelled events: %w", err)
+	}
+	stats.Voided = voided
+
+	results, err := s.db.GetEventResultsPendingSettlement(ctx, limit)
+	if err != nil {
+		return stats, fmt.Errorf("failed to get results pending settlement: %w", err)
+	}
+
+	for _, result := range results {
+		settled, skipped, err := s.SettleEvent(ctx, result)
+		if err != nil {
+			stats.Failures++
+			s.logger.Error().
+				Err(err).
+				Int32("event_id", result.EventID).
+				Str("action", "settle_failed").
+				Msg("Failed to settle event")
+			continue
+		}
+		stats.Events++
+		stats.Settled += settled
+		stats.Skipped += skipped
+	}
+
+	graded, err := s.GradeMovementAlerts(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("failed to grade movement alerts: %w", err)
+	}
+	stats.GradedAlerts = graded
+
+	return stats, nil
+}
+
+// SettleEvent grades every current outcome of an event and marks the result as settled.
+// It returns the number of settled and skipped outcomes.
+func (s *SettlementService) SettleEvent(ctx context.Context, result generated.EventResult) (int, int, error) {
+	candidates, err := s.db.GetSettlementCandidates(ctx, result.EventID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get settlement candidates: %w", err)
+	}
+
+	score := MatchScore{FullTime: Score{Home: int(result.HomeScore), Away: int(result.AwayScore)}}
+	if result.HtHomeScore != nil && result.HtAwayScore != nil {
+		score.HalfTime = &Score{Home: int(*result.HtHomeScore), Away: int(*result.HtAwayScore)}
+	}
+
+	var (
+		eventIDs       []int32
+		marketTypeIDs  []int32
+		outcomes       []string
+		marketFamilies []string
+		results        []string
+		skipped        int
+	)
+
+	for _, c := range candidates {
+		if c.MarketTypeID == nil {
+			continue
+		}
+		spec, ok := ClassifyMarket(c.MarketName)
+		if !ok {
+			skipped++
+			continue
+		}
+
+		var params models.MarketParams
+		if len(c.MarketParams) > 0 {
+			if err := json.Unmarshal(c.MarketParams, &params); err != nil {
+				skipped++
+				continue
+			}
+		}
+
+		graded, ok := SettleOutcome(spec, c.Outcome, params.Values, score)
+		if !ok {
+			skipped++
+			continue
+		}
+
+		eventIDs = append(eventIDs, result.EventID)
+		marketTypeIDs = append(marketTypeIDs, *c.MarketTypeID)
+		outcomes = append(outcomes, c.Outcome)
+		marketFamilies = append(marketFamilies, string(spec.Family))
+		results = append(results, string(graded))
+	}
+
+	if len(eventIDs) > 0 {
+		err = s.db.BulkUpsertOutcomeSettlements(ctx, generated.BulkUpsertOutcomeSettlementsParams{
+			EventIds:       eventIDs,
+			MarketTypeIds:  marketTypeIDs,
+			Outcomes:       outcomes,
+			MarketFamilies: marketFamilies,
+			Results:        results,
+		})
+		if err != nil {
+			return 0, skipped, fmt.Errorf("failed to upsert settlements: %w", err)
+		}
+	}
+
+	if err := s.db.MarkEventResultSettled(ctx, result.EventID); err != nil {
+		return len(eventIDs), skipped, fmt.Errorf("failed to mark result settled: %w", err)
+	}
+
+	return len(eventIDs), skipped, nil
+}
+
+// ParseScore parses an Iddaa score string such as "2-1" or "2:1"
+func ParseScore(value string) (Score, bool) {
+	parts := strings.FieldsFunc(value, func(r rune) bool { return r < '0' || r > '9' })
+	if len(parts) != 2 {
+		return Score{}, false
+	}
+	home, err1 := strconv.Atoi(parts[0])
+	away, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return Score{}, false
+	}
+	return Score{Home: home, Away: away}, true
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/iddaa-lens/core/pkg/models"
+)
+
+func TestClassifyMarket(t *testing.T) {
+	tests := []struct {
+		name      string
+		market    string
+		want      MarketFamily
+		firstHalf bool
+		ok        bool
+	}{
+		{"match result", "Maç Sonucu", MarketFamily1X2, false, true},
+		{"first half result", "İlk Yarı Sonucu", MarketFamily1X2, true, true},
+		{"double chance", "Çifte Şans", MarketFamilyDoubleChance, false, true},
+		{"over under", "{0} Alt/Üst", MarketFamilyOverUnder, false, true},
+		{"first half over under", "İlk Yarı {0} Alt/Üst", MarketFamilyOverUnder, true, true},
+		{"btts", "Karşılıklı Gol", MarketFamilyBTTS, false, true},
+		{"handicap", "Handikaplı Maç Sonucu ({0}:{1})", MarketFamilyHandicap, false, true},
+		{"ht ft", "İlk Yarı/Maç Sonucu", MarketFamilyHTFT, false, true},
+		{"correct score", "Maç Skoru", MarketFamilyCorrectScore, false, true},
+		{"corners", "Ev Sahibi Toplam Korner Altı/Üstü {0}", "", false, false},
+		{"cards in range", "{0} - {1} dk. Kart Sayısı Altı/Üstü {2}", "", false, false},
+		{"second half", "2. Yarı Sonucu", "", false, false},
+		{"unknown", "İlk Golü Kim Atar", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, ok := ClassifyMarket(tt.market)
+			if ok != tt.ok {
+				t.Fatalf("ClassifyMarket(%q) ok = %v, want %v", tt.market, ok, tt.ok)
+			}
+			if spec.Family != tt.want || spec.FirstHalf != tt.firstHalf {
+				t.Errorf("ClassifyMarket(%q) = %+v, want %s (first half %v)", tt.market, spec, tt.want, tt.firstHalf)
+			}
+		})
+	}
+}
+
+func TestSettleOutcome(t *testing.T) {
+	score := MatchScore{FullTime: Score{Home: 2, Away: 1}, HalfTime: &Score{Home: 0, Away: 1}}
+	noHalfTime := MatchScore{FullTime: Score{Home: 1, Away: 1}}
+
+	tests := []struct {
+		name    string
+		family  MarketFamily
+		half    bool
+		outcome string
+		params  []string
+		score   MatchScore
+		want    SettlementResult
+		ok      bool
+	}{
+		{"home win", MarketFamily1X2, false, "1", nil, score, SettlementWon, true},
+		{"draw lost", MarketFamily1X2, false, "X", nil, score, SettlementLost, true},
+		{"first half away", MarketFamily1X2, true, "2", nil, score, SettlementWon, true},
+		{"first half without score", MarketFamily1X2, true, "2", nil, noHalfTime, "", false},
+		{"double chance 1-X", MarketFamilyDoubleChance, false, "1-X", nil, score, SettlementWon, true},
+		{"double chance X-2", MarketFamilyDoubleChance, false, "X-2", nil, score, SettlementLost, true},
+		{"over 2.5", MarketFamilyOverUnder, false, "Üst 2.5", []string{"2.5"}, score, SettlementWon, true},
+		{"under 2.5", MarketFamilyOverUnder, false, "Alt 2.5", []string{"2.5"}, score, SettlementLost, true},
+		{"over whole line push", MarketFamilyOverUnder, false, "Üst 3", []string{"3"}, score, SettlementPush, true},
+		{"line from outcome", MarketFamilyOverUnder, false, "Alt 3.5", nil, score, SettlementWon, true},
+		{"quarter line", MarketFamilyOverUnder, false, "Üst 2.25", []string{"2.25"}, score, "", false},
+		{"btts yes", MarketFamilyBTTS, false, "Var", nil, score, SettlementWon, true},
+		{"btts no", MarketFamilyBTTS, false, "Yok", nil, score, SettlementLost, true},
+		{"handicap draw", MarketFamilyHandicap, false, "X (0:1)", []string{"0", "1"}, score, SettlementWon, true},
+		{"handicap home", MarketFamilyHandicap, false, "1 (0:1)", []string{"0", "1"}, score, SettlementLost, true},
+		{"handicap single value", MarketFamilyHandicap, false, "1", []string{"1"}, score, "", false},
+		{"ht ft 2/1", MarketFamilyHTFT, false, "2/1", nil, score, SettlementWon, true},
+		{"ht ft 1/1", MarketFamilyHTFT, false, "1/1", nil, score, SettlementLost, true},
+		{"ht ft without score", MarketFamilyHTFT, false, "X/X", nil, noHalfTime, "", false},
+		{"correct score", MarketFamilyCorrectScore, false, "2:1", nil, score, SettlementWon, true},
+		{"correct score dash", MarketFamilyCorrectScore, false, "1-1", nil, score, SettlementLost, true},
+		{"correct score other", MarketFamilyCorrectScore, false, "Diğer", nil, score, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := MarketSpec{Family: tt.family, FirstHalf: tt.half}
+			got, ok := SettleOutcome(spec, tt.outcome, tt.params, tt.score)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("SettleOutcome(%s, %q) = (%q, %v), want (%q, %v)", tt.family, tt.outcome, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseScore(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Score
+		ok    bool
+	}{
+		{"2-1", Score{Home: 2, Away: 1}, true},
+		{"0 : 0", Score{}, true},
+		{"", Score{}, false},
+		{"3", Score{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseScore(tt.value)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseScore(%q) = (%+v, %v), want (%+v, %v)", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIsFinalResult(t *testing.T) {
+	tests := []struct {
+		name        string
+		stat        models.IddaaEventStatistics
+		eventStatus string
+		want        bool
+	}{
+		{"finished", models.IddaaEventStatistics{Status: 2, FullTimeScore: "2:1"}, "live", true},
+		{"finished by the events sync", models.IddaaEventStatistics{Status: 1, FullTimeScore: "2:1"}, "finished", true},
+		{"half time", models.IddaaEventStatistics{Status: 1, FullTimeScore: "1:0"}, "live", false},
+		{"live", models.IddaaEventStatistics{Status: 2, FullTimeScore: "2:1", IsLive: true}, "finished", false},
+		{"no score", models.IddaaEventStatistics{Status: 2}, "finished", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFinalResult(tt.stat, tt.eventStatus); got != tt.want {
+				t.Errorf("isFinalResult() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
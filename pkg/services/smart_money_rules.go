@@ -0,0 +1,278 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"text/template"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+// Confidence sources a rule can derive its alert confidence from
+const (
+	ConfidenceFixed           = "fixed"
+	ConfidenceSharpScore      = "sharp_score"
+	ConfidenceReverseStrength = "reverse_strength"
+	ConfidencePublicBias      = "public_bias"
+	ConfidenceMoves           = "moves"
+)
+
+// MinRuleChangeFloor is the smallest absolute odds change, in percent, any rule matches. Candidates
+// are prefiltered on the lowest floor of the active rules, without it a rule with no change bound
+// would load every movement of the window.
+const MinRuleChangeFloor = 1.0
+
+// MovementCandidate is an odds movement with the context smart money rules evaluate
+type MovementCandidate struct {
+	OddsHistoryID    int32
+	Match            string
+	MarketName       string
+	Outcome          string
+	Odds             float64
+	ChangePercentage float64
+	Multiplier       float64
+	MinutesToKickoff int32
+	// Optional context, nil when the data is not available
+	BetPercentage      *float64
+	ImpliedProbability *float64
+	VolumePercentage   *float64
+	VolumeRank         *int32
+	MovesLastHour      int
+}
+
+// SharpScore returns the sharp money score of the candidate
+func (c MovementCandidate) SharpScore() int32 {
+	return SharpMoneyScore(MovementInput{
+		ChangePercentage:   c.ChangePercentage,
+		BetPercentage:      c.BetPercentage,
+		ImpliedProbability: c.ImpliedProbability,
+		VolumePercentage:   c.VolumePercentage,
+		HoursToKickoff:     float64(c.MinutesToKickoff) / 60,
+	})
+}
+
+// RuleTemplateData is available to rule title and message templates
+type RuleTemplateData struct {
+	Rule               string
+	Match              string
+	MarketName         string
+	Outcome            string
+	Odds               float64
+	ChangePercentage   float64
+	Multiplier         float64
+	MinutesToKickoff   int32
+	HasBetPercentage   bool
+	BetPercentage      float64
+	ImpliedProbability float64
+	PublicBias         float64
+	ReverseStrength    float64
+	VolumePercentage   float64
+	VolumeRank         int32
+	SharpScore         int32
+	MovesLastHour      int
+}
+
+// SmartMoneyRule is a stored detection rule with its templates parsed
+type SmartMoneyRule struct {
+	generated.SmartMoneyRule
+	title   *template.Template
+	message *template.Template
+}
+
+// CompileSmartMoneyRule validates a stored rule and parses its templates
+func CompileSmartMoneyRule(rule generated.SmartMoneyRule) (*SmartMoneyRule, error) {
+	switch rule.ConfidenceSource {
+	case ConfidenceFixed, ConfidenceSharpScore, ConfidenceReverseStrength, ConfidencePublicBias, ConfidenceMoves:
+	default:
+		return nil, fmt.Errorf("unknown confidence source %q", rule.ConfidenceSource)
+	}
+
+	title, err := template.New("title").Option("missingkey=error").Parse(rule.TitleTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid title template: %w", err)
+	}
+	message, err := template.New("message").Option("missingkey=error").Parse(rule.MessageTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message template: %w", err)
+	}
+
+	compiled := &SmartMoneyRule{SmartMoneyRule: rule, title: title, message: message}
+
+	// Execute once so references to unknown fields fail here rather than at alert time
+	if _, _, err := compiled.Render(MovementCandidate{}); err != nil {
+		return nil, err
+	}
+
+	return compiled, nil
+}
+
+// Matches reports whether every condition of the rule holds; conditions are strict bounds
+func (r *SmartMoneyRule) Matches(c MovementCandidate) bool {
+	change := c.ChangePercentage
+
+	if math.Abs(change) < MinRuleChangeFloor {
+		return false
+	}
+	if !above32(change, r.MinChangePct) || !below32(change, r.MaxChangePct) {
+		return false
+	}
+	if !above32(math.Abs(change), r.MinAbsChangePct) {
+		return false
+	}
+	if r.MinMultiplier != nil && c.Multiplier <= *r.MinMultiplier {
+		return false
+	}
+	if r.MaxMultiplier != nil && c.Multiplier >= *r.MaxMultiplier {
+		return false
+	}
+
+	if r.MinBetPct != nil || r.MaxBetPct != nil || r.MinPublicBias != nil {
+		if c.BetPercentage == nil {
+			return false
+		}
+		if !above32(*c.BetPercentage, r.MinBetPct) || !below32(*c.BetPercentage, r.MaxBetPct) {
+			return false
+		}
+		if r.MinPublicBias != nil {
+			if c.ImpliedProbability == nil || *c.BetPercentage-*c.ImpliedProbability <= float64(*r.MinPublicBias) {
+				return false
+			}
+		}
+	}
+
+	if r.MinVolumeRank != nil || r.MaxVolumeRank != nil {
+		if c.VolumeRank == nil {
+			return false
+		}
+		if r.MinVolumeRank != nil && *c.VolumeRank <= *r.MinVolumeRank {
+			return false
+		}
+		if r.MaxVolumeRank != nil && *c.VolumeRank >= *r.MaxVolumeRank {
+			return false
+		}
+	}
+	if r.MaxVolumePct != nil && (c.VolumePercentage == nil || !below32(*c.VolumePercentage, r.MaxVolumePct)) {
+		return false
+	}
+
+	if r.MinMinutesToKickoff != nil && c.MinutesToKickoff <= *r.MinMinutesToKickoff {
+		return false
+	}
+	if r.MaxMinutesToKickoff != nil && c.MinutesToKickoff >= *r.MaxMinutesToKickoff {
+		return false
+	}
+
+	if r.MinSharpScore != nil && c.SharpScore() <= *r.MinSharpScore {
+		return false
+	}
+	if r.MinMovesLastHour != nil && int32(c.MovesLastHour) <= *r.MinMovesLastHour {
+		return false
+	}
+
+	return true
+}
+
+// ChangeFloor is the smallest absolute odds change the rule can match, used to prefilter candidates.
+// It is never below MinRuleChangeFloor.
+func (r *SmartMoneyRule) ChangeFloor() float64 {
+	floor := MinRuleChangeFloor
+	if r.MinAbsChangePct != nil {
+		floor = math.Max(floor, float64(*r.MinAbsChangePct))
+	}
+	if r.MinChangePct != nil && *r.MinChangePct > 0 {
+		floor = math.Max(floor, float64(*r.MinChangePct))
+	}
+	if r.MaxChangePct != nil && *r.MaxChangePct < 0 {
+		floor = math.Max(floor, -float64(*r.MaxChangePct))
+	}
+	return floor
+}
+
+// Confidence derives the alert confidence (0-1) for a matching candidate
+func (r *SmartMoneyRule) Confidence(c MovementCandidate) float32 {
+	var confidence float64
+	switch r.ConfidenceSource {
+	case ConfidenceSharpScore:
+		confidence = float64(c.SharpScore()) / 100
+	case ConfidenceReverseStrength:
+		confidence = reverseStrength(c) / 100
+	case ConfidencePublicBias:
+		confidence = publicBias(c) / 100
+	case ConfidenceMoves:
+		confidence = float64(SteamMoveConfidence(c.MovesLastHour))
+	default:
+		confidence = float64(r.BaseConfidence)
+	}
+	return float32(math.Max(0, math.Min(1, confidence)))
+}
+
+// Severity returns the rule's fixed severity or derives it from the confidence
+func (r *SmartMoneyRule) Severity(confidence float32) string {
+	if r.SmartMoneyRule.Severity != nil {
+		return *r.SmartMoneyRule.Severity
+	}
+	return AlertSeverity(confidence)
+}
+
+// Render executes the title and message templates for a candidate
+func (r *SmartMoneyRule) Render(c MovementCandidate) (string, string, error) {
+	data := RuleTemplateData{
+		Rule:             r.Name,
+		Match:            c.Match,
+		MarketName:       c.MarketName,
+		Outcome:          c.Outcome,
+		Odds:             c.Odds,
+		ChangePercentage: c.ChangePercentage,
+		Multiplier:       c.Multiplier,
+		MinutesToKickoff: c.MinutesToKickoff,
+		HasBetPercentage: c.BetPercentage != nil,
+		PublicBias:       publicBias(c),
+		ReverseStrength:  reverseStrength(c),
+		SharpScore:       c.SharpScore(),
+		MovesLastHour:    c.MovesLastHour,
+	}
+	if c.BetPercentage != nil {
+		data.BetPercentage = *c.BetPercentage
+	}
+	if c.ImpliedProbability != nil {
+		data.ImpliedProbability = *c.ImpliedProbability
+	}
+	if c.VolumePercentage != nil {
+		data.VolumePercentage = *c.VolumePercentage
+	}
+	if c.VolumeRank != nil {
+		data.VolumeRank = *c.VolumeRank
+	}
+
+	var title, message bytes.Buffer
+	if err := r.title.Execute(&title, data); err != nil {
+		return "", "", fmt.Errorf("failed to render title: %w", err)
+	}
+	if err := r.message.Execute(&message, data); err != nil {
+		return "", "", fmt.Errorf("failed to render message: %w", err)
+	}
+	return title.String(), message.String(), nil
+}
+
+func reverseStrength(c MovementCandidate) float64 {
+	if c.BetPercentage == nil {
+		return 0
+	}
+	return *c.BetPercentage * math.Abs(c.ChangePercentage) / 100
+}
+
+func publicBias(c MovementCandidate) float64 {
+	if c.BetPercentage == nil || c.ImpliedProbability == nil {
+		return 0
+	}
+	return *c.BetPercentage - *c.ImpliedProbability
+}
+
+func above32(value float64, min *float32) bool {
+	return min == nil || value > float64(*min)
+}
+
+func below32(value float64, max *float32) bool {
+	return max == nil || value < float64(*max)
+}
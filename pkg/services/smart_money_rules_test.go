@@ -0,0 +1,176 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+func f32(v float32) *float32 { return &v }
+func i32(v int32) *int32     { return &v }
+func f64(v float64) *float64 { return &v }
+
+func compileRule(t *testing.T, rule generated.SmartMoneyRule) *SmartMoneyRule {
+	t.Helper()
+	if rule.ConfidenceSource == "" {
+		rule.ConfidenceSource = ConfidenceFixed
+	}
+	if rule.TitleTemplate == "" {
+		rule.TitleTemplate = "{{.Match}}"
+	}
+	if rule.MessageTemplate == "" {
+		rule.MessageTemplate = "{{.Outcome}}"
+	}
+	compiled, err := CompileSmartMoneyRule(rule)
+	if err != nil {
+		t.Fatalf("CompileSmartMoneyRule() error = %v", err)
+	}
+	return compiled
+}
+
+func TestSmartMoneyRule_Matches(t *testing.T) {
+	candidate := MovementCandidate{
+		ChangePercentage:   8,
+		Multiplier:         1.08,
+		MinutesToKickoff:   90,
+		BetPercentage:      f64(75),
+		ImpliedProbability: f64(50),
+		VolumeRank:         i32(3),
+		MovesLastHour:      4,
+	}
+
+	tests := []struct {
+		name      string
+		rule      generated.SmartMoneyRule
+		candidate MovementCandidate
+		want      bool
+	}{
+		{"no conditions", generated.SmartMoneyRule{}, candidate, true},
+		{"below the minimum change", generated.SmartMoneyRule{}, MovementCandidate{ChangePercentage: -0.5}, false},
+		{"change above min", generated.SmartMoneyRule{MinChangePct: f32(5)}, candidate, true},
+		{"change bound is strict", generated.SmartMoneyRule{MinChangePct: f32(8)}, candidate, false},
+		{"change below max", generated.SmartMoneyRule{MaxChangePct: f32(-5)}, candidate, false},
+		{"absolute change", generated.SmartMoneyRule{MinAbsChangePct: f32(4.99)}, MovementCandidate{ChangePercentage: -5}, true},
+		{"multiplier range", generated.SmartMoneyRule{MinMultiplier: f64(1.05), MaxMultiplier: f64(1.1)}, candidate, true},
+		{"public heavy", generated.SmartMoneyRule{MinBetPct: f32(65)}, candidate, true},
+		{"missing bet percentage", generated.SmartMoneyRule{MinBetPct: f32(65)}, MovementCandidate{ChangePercentage: 8}, false},
+		{"public bias", generated.SmartMoneyRule{MinPublicBias: f32(15)}, candidate, true},
+		{"public bias too small", generated.SmartMoneyRule{MinPublicBias: f32(25)}, candidate, false},
+		{"top volume ranks", generated.SmartMoneyRule{MaxVolumeRank: i32(4)}, candidate, true},
+		{"missing volume rank", generated.SmartMoneyRule{MaxVolumeRank: i32(4)}, MovementCandidate{}, false},
+		{"close to kickoff", generated.SmartMoneyRule{MaxMinutesToKickoff: i32(60)}, candidate, false},
+		{"steam", generated.SmartMoneyRule{MinMovesLastHour: i32(2)}, candidate, true},
+		// 40 (reverse) + 20 (timing) + 5 (size) = 65
+		{"sharp score", generated.SmartMoneyRule{MinSharpScore: i32(60)}, candidate, true},
+		{"sharp score too low", generated.SmartMoneyRule{MinSharpScore: i32(65)}, candidate, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := compileRule(t, tt.rule)
+			if got := rule.Matches(tt.candidate); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmartMoneyRule_ChangeFloor(t *testing.T) {
+	tests := []struct {
+		name string
+		rule generated.SmartMoneyRule
+		want float64
+	}{
+		{"none", generated.SmartMoneyRule{}, MinRuleChangeFloor},
+		{"absolute", generated.SmartMoneyRule{MinAbsChangePct: f32(3)}, 3},
+		{"drifting", generated.SmartMoneyRule{MinChangePct: f32(5)}, 5},
+		{"shortening", generated.SmartMoneyRule{MaxChangePct: f32(-5)}, 5},
+		{"range around zero", generated.SmartMoneyRule{MinChangePct: f32(-2), MaxChangePct: f32(2)}, MinRuleChangeFloor},
+		{"below the minimum", generated.SmartMoneyRule{MinAbsChangePct: f32(0.5)}, MinRuleChangeFloor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compileRule(t, tt.rule).ChangeFloor(); got != tt.want {
+				t.Errorf("ChangeFloor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmartMoneyRule_ConfidenceAndSeverity(t *testing.T) {
+	candidate := MovementCandidate{ChangePercentage: -10, BetPercentage: f64(20), ImpliedProbability: f64(60), MovesLastHour: 5}
+
+	tests := []struct {
+		source   string
+		severity *string
+		want     float32
+		wantSev  string
+	}{
+		{ConfidenceFixed, nil, 0.5, "medium"},
+		{ConfidenceReverseStrength, nil, 0.02, "low"},
+		{ConfidencePublicBias, nil, 0, "low"},
+		{ConfidenceMoves, strPtr("high"), SteamMoveConfidence(5), "high"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			rule := compileRule(t, generated.SmartMoneyRule{
+				ConfidenceSource: tt.source,
+				BaseConfidence:   0.5,
+				Severity:         tt.severity,
+			})
+			got := rule.Confidence(candidate)
+			if math.Abs(float64(got-tt.want)) > 1e-6 {
+				t.Errorf("Confidence() = %v, want %v", got, tt.want)
+			}
+			if sev := rule.Severity(got); sev != tt.wantSev {
+				t.Errorf("Severity() = %q, want %q", sev, tt.wantSev)
+			}
+		})
+	}
+}
+
+func TestCompileSmartMoneyRule_Templates(t *testing.T) {
+	rule := compileRule(t, generated.SmartMoneyRule{
+		Name:            "sharp",
+		TitleTemplate:   "Sharp Money Detected: {{.Match}}",
+		MessageTemplate: `{{.Outcome}} score {{.SharpScore}}{{if .HasBetPercentage}} (Public: {{printf "%.0f" .BetPercentage}}%){{end}}`,
+	})
+
+	title, message, err := rule.Render(MovementCandidate{
+		Match:            "A vs B",
+		Outcome:          "1",
+		ChangePercentage: 8,
+		MinutesToKickoff: 90,
+		BetPercentage:    f64(75),
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if title != "Sharp Money Detected: A vs B" {
+		t.Errorf("title = %q", title)
+	}
+	if message != "1 score 65 (Public: 75%)" {
+		t.Errorf("message = %q", message)
+	}
+
+	for _, tmpl := range []string{"{{.Match", "{{.Unknown}}"} {
+		_, err := CompileSmartMoneyRule(generated.SmartMoneyRule{
+			ConfidenceSource: ConfidenceFixed,
+			TitleTemplate:    tmpl,
+			MessageTemplate:  "ok",
+		})
+		if err == nil || !strings.Contains(err.Error(), "title") {
+			t.Errorf("template %q: error = %v, want title error", tmpl, err)
+		}
+	}
+
+	if _, err := CompileSmartMoneyRule(generated.SmartMoneyRule{ConfidenceSource: "magic"}); err == nil {
+		t.Error("expected an error for an unknown confidence source")
+	}
+}
+
+func strPtr(s string) *string { return &s }
@@ -0,0 +1,165 @@
+package services
+
+import "math"
+
+// SmartMoneyThresholds are the detection thresholds used by SmartMoneyTracker.
+// The defaults mirror the production queries so offline backtests can tune them.
+type SmartMoneyThresholds struct {
+	// MinSharpScore is the sharp money score (0-100) an indicator must exceed
+	MinSharpScore int32
+	// MinBiasPct is the minimum gap between public bet % and implied probability for value spots
+	MinBiasPct float64
+	// MinMovementPct is the minimum absolute odds change for value spots
+	MinMovementPct float64
+	// ReverseHeavyPct and ReverseLightPct bound the public bet % for reverse line movements
+	ReverseHeavyPct float64
+	ReverseLightPct float64
+	// ReverseMovementPct is the minimum odds change against the public side
+	ReverseMovementPct float64
+	// SteamMinMoves is the number of moves within an hour that makes a steam move
+	SteamMinMoves int
+	// SteamMinChangePct is the minimum absolute odds change counted as a steam move
+	SteamMinChangePct float64
+}
+
+// DefaultSmartMoneyThresholds returns the thresholds used in production
+func DefaultSmartMoneyThresholds() SmartMoneyThresholds {
+	return SmartMoneyThresholds{
+		MinSharpScore:      60,
+		MinBiasPct:         15,
+		MinMovementPct:     5,
+		ReverseHeavyPct:    65,
+		ReverseLightPct:    35,
+		ReverseMovementPct: 5,
+		SteamMinMoves:      3,
+		SteamMinChangePct:  3,
+	}
+}
+
+// MovementInput is a single odds movement with the market context known at that time
+type MovementInput struct {
+	ChangePercentage float64
+	// BetPercentage is the public bet share of the outcome, nil when unknown
+	BetPercentage *float64
+	// ImpliedProbability is in percent, nil when unknown
+	ImpliedProbability *float64
+	// VolumePercentage is the event's share of total betting volume, nil when unknown
+	VolumePercentage *float64
+	HoursToKickoff   float64
+}
+
+// SharpMoneyScore scores a movement from 0 to 100 the same way GetSharpMoneyIndicators does:
+// reverse movement (0-40), low volume (0-20), late timing (0-20) and movement size (0-20)
+func SharpMoneyScore(m MovementInput) int32 {
+	change := m.ChangePercentage
+	absChange := math.Abs(change)
+	var score int32
+
+	if m.BetPercentage != nil {
+		bet := *m.BetPercentage
+		switch {
+		case bet > 70 && change > 5:
+			score += 40
+		case bet > 60 && change > 3:
+			score += 30
+		case bet < 30 && change < -5:
+			score += 40
+		case bet < 40 && change < -3:
+			score += 30
+		}
+	}
+
+	if m.VolumePercentage != nil {
+		volume := *m.VolumePercentage
+		switch {
+		case volume < 1 && absChange > 10:
+			score += 20
+		case volume < 2 && absChange > 7:
+			score += 15
+		case volume < 5 && absChange > 5:
+			score += 10
+		}
+	}
+
+	switch {
+	case m.HoursToKickoff < 2:
+		score += 20
+	case m.HoursToKickoff < 6:
+		score += 15
+	case m.HoursToKickoff < 24:
+		score += 10
+	default:
+		score += 5
+	}
+
+	switch {
+	case absChange > 20:
+		score += 20
+	case absChange > 15:
+		score += 15
+	case absChange > 10:
+		score += 10
+	case absChange > 5:
+		score += 5
+	}
+
+	return score
+}
+
+// ReverseLineStrength reports whether odds moved against the public side and how strongly
+func ReverseLineStrength(m MovementInput, t SmartMoneyThresholds) (float64, bool) {
+	if m.BetPercentage == nil || math.Abs(m.ChangePercentage) < t.ReverseMovementPct {
+		return 0, false
+	}
+
+	bet := *m.BetPercentage
+	heavyDrifting := bet > t.ReverseHeavyPct && m.ChangePercentage > t.ReverseMovementPct
+	lightShortening := bet < t.ReverseLightPct && m.ChangePercentage < -t.ReverseMovementPct
+	if !heavyDrifting && !lightShortening {
+		return 0, false
+	}
+
+	return bet * math.Abs(m.ChangePercentage) / 100, true
+}
+
+// ValueSpotBias reports whether the public overbets an outcome relative to its implied probability
+func ValueSpotBias(m MovementInput, t SmartMoneyThresholds) (float64, bool) {
+	if m.BetPercentage == nil || m.ImpliedProbability == nil {
+		return 0, false
+	}
+	if math.Abs(m.ChangePercentage) < t.MinMovementPct {
+		return 0, false
+	}
+
+	bias := *m.BetPercentage - *m.ImpliedProbability
+	if bias <= t.MinBiasPct {
+		return 0, false
+	}
+	return bias, true
+}
+
+// SteamMoveConfidence maps the number of moves in the last hour to an alert confidence
+func SteamMoveConfidence(movesLastHour int) float32 {
+	switch {
+	case movesLastHour > 5:
+		return 0.8
+	case movesLastHour > 3:
+		return 0.7
+	default:
+		return 0.5
+	}
+}
+
+// AlertSeverity maps an alert confidence to its severity
+func AlertSeverity(confidence float32) string {
+	switch {
+	case confidence >= 0.8:
+		return "critical"
+	case confidence >= 0.6:
+		return "high"
+	case confidence >= 0.4:
+		return "medium"
+	default:
+		return "low"
+	}
+}
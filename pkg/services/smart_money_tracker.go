@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/iddaa-lens/core/pkg/database/generated"
@@ -10,7 +12,10 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-// SmartMoneyTracker analyzes odds movements using real betting distribution data
+// maxSmartMoneyCandidates bounds the movements loaded per run, the largest changes are kept
+const maxSmartMoneyCandidates = 5000
+
+// SmartMoneyTracker analyzes odds movements against the stored smart money rules
 type SmartMoneyTracker struct {
 	db     *generated.Queries
 	logger *logger.Logger
@@ -24,103 +29,96 @@ func NewSmartMoneyTracker(db *generated.Queries) *SmartMoneyTracker {
 	}
 }
 
-// ProcessRecentMovements analyzes recent odds movements for smart money patterns
-func (smt *SmartMoneyTracker) ProcessRecentMovements(ctx context.Context, hours int) error {
-	sinceTime := pgtype.Timestamp{
-		Time:  time.Now().Add(-time.Duration(hours) * time.Hour),
-		Valid: true,
-	}
+// ruleMatch is a candidate movement that fired a rule
+type ruleMatch struct {
+	candidate  MovementCandidate
+	confidence float32
+}
 
-	// 1. Process reverse line movements using real betting data
-	reverseMovements, err := smt.db.GetReverseLineMovements(ctx, generated.GetReverseLineMovementsParams{
-		SinceTime:  sinceTime,
-		LimitCount: 100,
-	})
+// ProcessRecentMovements evaluates recent odds movements against the active smart money rules.
+// Rules are tried in priority order and only the first matching rule per alert type fires for a
+// movement; each rule then keeps its max_alerts most confident matches.
+func (smt *SmartMoneyTracker) ProcessRecentMovements(ctx context.Context, hours int) error {
+	rules, err := smt.LoadRules(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get reverse line movements: %w", err)
+		return err
 	}
 
-	smt.logger.Info().
-		Int("count", len(reverseMovements)).
-		Msg("Found reverse line movements")
-
-	for _, movement := range reverseMovements {
-		if err := smt.createReverseLineAlert(ctx, movement); err != nil {
-			smt.logger.Error().Err(err).
-				Int32("odds_history_id", movement.ID).
-				Msg("Failed to create reverse line alert")
+	if len(rules) == 0 {
+		smt.logger.Warn().Msg("No active smart money rules, skipping movement analysis")
+	} else {
+		sinceTime := pgtype.Timestamp{
+			Time:  time.Now().Add(-time.Duration(hours) * time.Hour),
+			Valid: true,
 		}
-	}
 
-	// 2. Process sharp money indicators
-	sharpIndicators, err := smt.db.GetSharpMoneyIndicators(ctx, generated.GetSharpMoneyIndicatorsParams{
-		SinceTime:  sinceTime,
-		LimitCount: 100,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to get sharp money indicators: %w", err)
-	}
-
-	smt.logger.Info().
-		Int("count", len(sharpIndicators)).
-		Msg("Found sharp money indicators")
-
-	for _, indicator := range sharpIndicators {
-		// Only create alerts for high-confidence sharp money (score > 60)
-		if indicator.SharpMoneyScore > 60 {
-			if err := smt.createSharpMoneyAlert(ctx, indicator); err != nil {
-				smt.logger.Error().Err(err).
-					Int32("odds_history_id", indicator.ID).
-					Msg("Failed to create sharp money alert")
-			}
+		// Only fetch movements at least one rule can match
+		minChange := rules[0].ChangeFloor()
+		for _, rule := range rules[1:] {
+			minChange = math.Min(minChange, rule.ChangeFloor())
 		}
-	}
 
-	// 3. Process steam moves
-	steamMoves, err := smt.db.GetSteamMoves(ctx, generated.GetSteamMovesParams{
-		SinceTime:  sinceTime,
-		LimitCount: 50,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to get steam moves: %w", err)
-	}
-
-	smt.logger.Info().
-		Int("count", len(steamMoves)).
-		Msg("Found steam moves")
-
-	for _, steam := range steamMoves {
-		if err := smt.createSteamMoveAlert(ctx, steam); err != nil {
-			smt.logger.Error().Err(err).
-				Int32("odds_history_id", steam.ID).
-				Msg("Failed to create steam move alert")
+		rows, err := smt.db.GetSmartMoneyCandidates(ctx, generated.GetSmartMoneyCandidatesParams{
+			SinceTime:    sinceTime,
+			MinAbsChange: minChange,
+			LimitCount:   maxSmartMoneyCandidates,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get smart money candidates: %w", err)
+		}
+		if len(rows) == maxSmartMoneyCandidates {
+			smt.logger.Warn().
+				Int("limit", maxSmartMoneyCandidates).
+				Float64("min_change", minChange).
+				Msg("Smart money candidates truncated to the largest movements")
 		}
-	}
 
-	// 4. Process value spots
-	valueSpots, err := smt.db.GetValueSpots(ctx, generated.GetValueSpotsParams{
-		SinceTime:      sinceTime,
-		MinBiasPct:     15.0, // At least 15% public bias
-		MinMovementPct: 5.0,  // At least 5% movement
-		LimitCount:     50,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to get value spots: %w", err)
-	}
+		smt.logger.Info().
+			Int("candidates", len(rows)).
+			Int("rules", len(rules)).
+			Float64("min_change", minChange).
+			Msg("Evaluating smart money rules")
+
+		matches := make([][]ruleMatch, len(rules))
+		for _, row := range rows {
+			candidate := candidateFromRow(row)
+			fired := make(map[string]bool)
+			for i, rule := range rules {
+				if fired[rule.AlertType] || !rule.Matches(candidate) {
+					continue
+				}
+				fired[rule.AlertType] = true
+				matches[i] = append(matches[i], ruleMatch{candidate: candidate, confidence: rule.Confidence(candidate)})
+			}
+		}
 
-	smt.logger.Info().
-		Int("count", len(valueSpots)).
-		Msg("Found value spots")
+		for i, rule := range rules {
+			ruleMatches := matches[i]
+			sort.SliceStable(ruleMatches, func(a, b int) bool {
+				return ruleMatches[a].confidence > ruleMatches[b].confidence
+			})
+			if rule.MaxAlerts > 0 && len(ruleMatches) > int(rule.MaxAlerts) {
+				ruleMatches = ruleMatches[:rule.MaxAlerts]
+			}
 
-	for _, value := range valueSpots {
-		if err := smt.createValueSpotAlert(ctx, value); err != nil {
-			smt.logger.Error().Err(err).
-				Int32("odds_history_id", value.ID).
-				Msg("Failed to create value spot alert")
+			smt.logger.Info().
+				Str("rule", rule.Name).
+				Str("alert_type", rule.AlertType).
+				Int("count", len(ruleMatches)).
+				Msg("Smart money rule matched")
+
+			for _, match := range ruleMatches {
+				if err := smt.createAlert(ctx, rule, match); err != nil {
+					smt.logger.Error().Err(err).
+						Str("rule", rule.Name).
+						Int32("odds_history_id", match.candidate.OddsHistoryID).
+						Msg("Failed to create smart money alert")
+				}
+			}
 		}
 	}
 
-	// 5. Deactivate expired alerts
+	// Deactivate expired alerts
 	if err := smt.db.DeactivateExpiredAlerts(ctx); err != nil {
 		smt.logger.Error().Err(err).Msg("Failed to deactivate expired alerts")
 	}
@@ -128,200 +126,81 @@ func (smt *SmartMoneyTracker) ProcessRecentMovements(ctx context.Context, hours
 	return nil
 }
 
-// createReverseLineAlert creates an alert for true reverse line movements
-func (smt *SmartMoneyTracker) createReverseLineAlert(ctx context.Context, movement generated.GetReverseLineMovementsRow) error {
-	// Build match name
-	matchName := "Unknown Match"
-	if movement.HomeTeamName != nil && movement.AwayTeamName != nil {
-		matchName = fmt.Sprintf("%s vs %s", *movement.HomeTeamName, *movement.AwayTeamName)
-	}
-
-	// Calculate confidence based on reverse strength
-	confidence := float32(movement.ReverseStrength) / 100.0
-	if confidence > 1.0 {
-		confidence = 1.0
-	}
-
-	title := fmt.Sprintf("Reverse Line Movement: %s", matchName)
-
-	var movementType string
-	switch movement.MovementType {
-	case "public_heavy_odds_worse":
-		movementType = "Public betting heavy but odds getting worse"
-	case "public_light_odds_better":
-		movementType = "Public avoiding but odds getting better"
-	default:
-		movementType = "Unknown"
-	}
-
-	message := fmt.Sprintf("🔄 %s - %s: %s (Public: %.0f%%, Strength: %d)",
-		matchName, movement.Outcome, movementType,
-		*movement.BetPercentage, movement.ReverseStrength)
-
-	changePercent := float32(0)
-	if movement.ChangePercentage != nil {
-		changePercent = *movement.ChangePercentage
-	}
-
-	multiplier := float64(1)
-	if movement.Multiplier != nil {
-		multiplier = *movement.Multiplier
-	}
-
-	_, err := smt.db.CreateMovementAlert(ctx, generated.CreateMovementAlertParams{
-		OddsHistoryID:    movement.ID,
-		AlertType:        "reverse_line",
-		Severity:         smt.calculateSeverity(confidence),
-		Title:            title,
-		Message:          message,
-		ChangePercentage: changePercent,
-		Multiplier:       multiplier,
-		ConfidenceScore:  confidence,
-		MinutesToKickoff: movement.MinutesToKickoff,
-	})
-	return err
-}
-
-// createSharpMoneyAlert creates an alert for sharp money indicators
-func (smt *SmartMoneyTracker) createSharpMoneyAlert(ctx context.Context, indicator generated.GetSharpMoneyIndicatorsRow) error {
-	// Build match name
-	matchName := "Unknown Match"
-	if indicator.HomeTeamName != nil && indicator.AwayTeamName != nil {
-		matchName = fmt.Sprintf("%s vs %s", *indicator.HomeTeamName, *indicator.AwayTeamName)
-	}
-
-	confidence := float32(indicator.SharpMoneyScore) / 100.0
-
-	title := fmt.Sprintf("Sharp Money Detected: %s", matchName)
-
-	publicInfo := ""
-	if indicator.BetPercentage != nil {
-		publicInfo = fmt.Sprintf(" (Public: %.0f%%)", *indicator.BetPercentage)
-	}
-
-	message := fmt.Sprintf("🎯 %s - %s shows sharp activity (Score: %d/100)%s",
-		matchName, indicator.Outcome, indicator.SharpMoneyScore, publicInfo)
-
-	changePercent := float32(0)
-	if indicator.ChangePercentage != nil {
-		changePercent = *indicator.ChangePercentage
+// LoadRules returns the active rules in priority order, skipping rules that fail to compile
+func (smt *SmartMoneyTracker) LoadRules(ctx context.Context) ([]*SmartMoneyRule, error) {
+	stored, err := smt.db.ListActiveSmartMoneyRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list smart money rules: %w", err)
 	}
 
-	multiplier := float64(1)
-	if indicator.Multiplier != nil {
-		multiplier = *indicator.Multiplier
+	rules := make([]*SmartMoneyRule, 0, len(stored))
+	for _, r := range stored {
+		rule, err := CompileSmartMoneyRule(r)
+		if err != nil {
+			smt.logger.Error().Err(err).
+				Str("rule", r.Name).
+				Msg("Skipping invalid smart money rule")
+			continue
+		}
+		rules = append(rules, rule)
 	}
-
-	_, err := smt.db.CreateMovementAlert(ctx, generated.CreateMovementAlertParams{
-		OddsHistoryID:    indicator.ID,
-		AlertType:        "sharp_money",
-		Severity:         smt.calculateSeverity(confidence),
-		Title:            title,
-		Message:          message,
-		ChangePercentage: changePercent,
-		Multiplier:       multiplier,
-		ConfidenceScore:  confidence,
-		MinutesToKickoff: indicator.MinutesToKickoff,
-	})
-	return err
+	return rules, nil
 }
 
-// createSteamMoveAlert creates an alert for steam moves
-func (smt *SmartMoneyTracker) createSteamMoveAlert(ctx context.Context, steam generated.GetSteamMovesRow) error {
-	// Build match name
-	matchName := "Unknown Match"
-	if steam.HomeTeamName != nil && steam.AwayTeamName != nil {
-		matchName = fmt.Sprintf("%s vs %s", *steam.HomeTeamName, *steam.AwayTeamName)
-	}
-
-	// Base confidence on movement count and speed
-	confidence := float32(0.5)
-	if steam.MovementsLastHour > 5 {
-		confidence = 0.8
-	} else if steam.MovementsLastHour > 3 {
-		confidence = 0.7
-	}
-
-	title := fmt.Sprintf("Steam Move: %s", matchName)
-	message := fmt.Sprintf("⚡ %s - %s moving rapidly (%d moves/hour, last: %.0fs ago)",
-		matchName, steam.Outcome, steam.MovementsLastHour, steam.SecondsSinceLastMove)
-
-	changePercent := float32(0)
-	if steam.ChangePercentage != nil {
-		changePercent = *steam.ChangePercentage
-	}
-
-	multiplier := float64(1)
-	if steam.Multiplier != nil {
-		multiplier = *steam.Multiplier
+// createAlert stores a movement alert for a rule match
+func (smt *SmartMoneyTracker) createAlert(ctx context.Context, rule *SmartMoneyRule, match ruleMatch) error {
+	title, message, err := rule.Render(match.candidate)
+	if err != nil {
+		return err
 	}
 
-	_, err := smt.db.CreateMovementAlert(ctx, generated.CreateMovementAlertParams{
-		OddsHistoryID:    steam.ID,
-		AlertType:        "steam_move",
-		Severity:         "high",
+	minutesToKickoff := match.candidate.MinutesToKickoff
+	_, err = smt.db.CreateMovementAlert(ctx, generated.CreateMovementAlertParams{
+		OddsHistoryID:    match.candidate.OddsHistoryID,
+		AlertType:        rule.AlertType,
+		Severity:         rule.Severity(match.confidence),
 		Title:            title,
 		Message:          message,
-		ChangePercentage: changePercent,
-		Multiplier:       multiplier,
-		ConfidenceScore:  confidence,
-		MinutesToKickoff: steam.MinutesToKickoff,
+		ChangePercentage: float32(match.candidate.ChangePercentage),
+		Multiplier:       match.candidate.Multiplier,
+		ConfidenceScore:  match.confidence,
+		MinutesToKickoff: &minutesToKickoff,
 	})
 	return err
 }
 
-// createValueSpotAlert creates an alert for value betting opportunities
-func (smt *SmartMoneyTracker) createValueSpotAlert(ctx context.Context, value generated.GetValueSpotsRow) error {
-	// Build match name
+// candidateFromRow converts a candidate query row for rule evaluation
+func candidateFromRow(row generated.GetSmartMoneyCandidatesRow) MovementCandidate {
 	matchName := "Unknown Match"
-	if value.HomeTeamName != nil && value.AwayTeamName != nil {
-		matchName = fmt.Sprintf("%s vs %s", *value.HomeTeamName, *value.AwayTeamName)
-	}
-
-	// Confidence based on public bias
-	confidence := float32(value.PublicBias) / 100.0
-	if confidence > 1.0 {
-		confidence = 1.0
-	}
-
-	title := fmt.Sprintf("Value Spot: %s", matchName)
-	message := fmt.Sprintf("💰 %s - %s overbet by public (Bet: %.0f%%, Fair: %.0f%%)",
-		matchName, value.Outcome, *value.BetPercentage, *value.ImpliedProbability)
-
-	changePercent := float32(0)
-	if value.ChangePercentage != nil {
-		changePercent = *value.ChangePercentage
-	}
-
-	multiplier := float64(1)
-	if value.Multiplier != nil {
-		multiplier = *value.Multiplier
-	}
-
-	_, err := smt.db.CreateMovementAlert(ctx, generated.CreateMovementAlertParams{
-		OddsHistoryID:    value.ID,
-		AlertType:        "value_spot",
-		Severity:         smt.calculateSeverity(confidence),
-		Title:            title,
-		Message:          message,
-		ChangePercentage: changePercent,
-		Multiplier:       multiplier,
-		ConfidenceScore:  confidence,
-		MinutesToKickoff: value.MinutesToKickoff,
-	})
-	return err
-}
-
-// calculateSeverity determines alert severity based on confidence score
-func (smt *SmartMoneyTracker) calculateSeverity(confidence float32) string {
-	if confidence >= 0.8 {
-		return "critical"
-	} else if confidence >= 0.6 {
-		return "high"
-	} else if confidence >= 0.4 {
-		return "medium"
-	}
-	return "low"
+	if row.HomeTeamName != nil && row.AwayTeamName != nil {
+		matchName = fmt.Sprintf("%s vs %s", *row.HomeTeamName, *row.AwayTeamName)
+	}
+
+	candidate := MovementCandidate{
+		OddsHistoryID:    row.ID,
+		Match:            matchName,
+		MarketName:       row.MarketName,
+		Outcome:          row.Outcome,
+		Odds:             row.OddsValue,
+		ChangePercentage: row.ChangePercentage,
+		Multiplier:       row.Multiplier,
+		MinutesToKickoff: row.MinutesToKickoff,
+		VolumeRank:       row.VolumeRank,
+		MovesLastHour:    int(row.MovesLastHour),
+	}
+	if row.BetPercentage != nil {
+		bet := float64(*row.BetPercentage)
+		candidate.BetPercentage = &bet
+	}
+	if row.ImpliedProbability != nil {
+		implied := float64(*row.ImpliedProbability)
+		candidate.ImpliedProbability = &implied
+	}
+	if row.BettingVolumePercentage != nil {
+		volume := float64(*row.BettingVolumePercentage)
+		candidate.VolumePercentage = &volume
+	}
+	return candidate
 }
 
 // GetActiveAlerts retrieves active smart money alerts
@@ -44,7 +44,7 @@ var sportMapping = map[int]struct {
 // SyncSports fetches sports from Iddaa API and bulk upserts them to database
 func (s *SportService) SyncSports(ctx context.Context) error {
 	// Fetch sports info from Iddaa API
-	resp, err := s.client.GetSportInfo()
+	resp, err := s.client.GetSportInfo(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch sport info: %w", err)
 	}
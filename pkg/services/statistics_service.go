@@ -33,7 +33,7 @@ func (s *StatisticsService) SyncEventStatistics(ctx context.Context, sportID int
 		Str("action", "sync_start").
 		Msg("Starting statistics sync")
 
-	stats, err := s.client.GetEventStatistics(sportID, searchDate)
+	stats, err := s.client.GetEventStatistics(ctx, sportID, searchDate)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -106,6 +106,17 @@ func (s *StatisticsService) saveEventStatistics(ctx context.Context, stat models
 		return fmt.Errorf("failed to update event live data: %w", err)
 	}
 
+	// Store the final score once the match is over so outcomes can be settled
+	if isFinalResult(stat, event.Status) {
+		if err := s.saveEventResult(ctx, event.ID, stat); err != nil {
+			s.logger.Error().
+				Err(err).
+				Int("event_id", stat.EventID).
+				Str("action", "event_result_failed").
+				Msg("Failed to save event result")
+		}
+	}
+
 	// Save match statistics if available
 	if stat.HasStatistics {
 		err = s.saveMatchStatistics(ctx, event.ID, stat.Statistics)
@@ -133,6 +144,40 @@ func (s *StatisticsService) saveEventStatistics(ctx context.Context, stat models
 	return nil
 }
 
+// iddaaStatusFinished is Iddaa's status of a finished match, see EventsService.convertEventStatus
+const iddaaStatusFinished = 2
+
+// isFinalResult reports whether the statistics carry the final score. A match between halves or
+// not started yet is not live either, so the result also needs Iddaa or the events sync to mark
+// the match finished.
+func isFinalResult(stat models.IddaaEventStatistics, eventStatus string) bool {
+	if stat.IsLive || stat.FullTimeScore == "" {
+		return false
+	}
+	return stat.Status == iddaaStatusFinished || eventStatus == "finished"
+}
+
+func (s *StatisticsService) saveEventResult(ctx context.Context, eventID int32, stat models.IddaaEventStatistics) error {
+	fullTime, ok := ParseScore(stat.FullTimeScore)
+	if !ok {
+		return fmt.Errorf("invalid full time score %q", stat.FullTimeScore)
+	}
+
+	params := generated.UpsertEventResultParams{
+		EventID:   eventID,
+		HomeScore: int32(fullTime.Home),
+		AwayScore: int32(fullTime.Away),
+	}
+	if halfTime, ok := ParseScore(stat.HalfTimeScore); ok {
+		htHome := int32(halfTime.Home)
+		htAway := int32(halfTime.Away)
+		params.HtHomeScore = &htHome
+		params.HtAwayScore = &htAway
+	}
+
+	return s.db.UpsertEventResult(ctx, params)
+}
+
 func (s *StatisticsService) saveMatchStatistics(ctx context.Context, eventID int32, stats models.IddaaMatchStatistics) error {
 	// Upsert home team statistics
 	shots := int32(stats.HomeStats.Shots)
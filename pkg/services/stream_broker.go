@@ -0,0 +1,139 @@
+package services
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Stream message types published by the database triggers
+const (
+	StreamTypeOdds  = "odds"
+	StreamTypeAlert = "alert"
+)
+
+// StreamMessage is a single notification pushed to stream subscribers
+type StreamMessage struct {
+	Type      string          `json:"type"`
+	EventID   int32           `json:"event_id"`
+	EventSlug string          `json:"event_slug"`
+	SportCode string          `json:"sport_code"`
+	LeagueID  int32           `json:"league_id"`
+	AlertType string          `json:"alert_type,omitempty"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// StreamFilter narrows down which messages a subscriber receives
+// Empty fields match everything
+type StreamFilter struct {
+	Types      map[string]bool
+	SportCode  string
+	LeagueID   int32
+	EventSlug  string
+	AlertTypes map[string]bool
+}
+
+// Matches reports whether the message passes the filter
+func (f StreamFilter) Matches(msg StreamMessage) bool {
+	if len(f.Types) > 0 && !f.Types[msg.Type] {
+		return false
+	}
+	if f.SportCode != "" && f.SportCode != msg.SportCode {
+		return false
+	}
+	if f.LeagueID != 0 && f.LeagueID != msg.LeagueID {
+		return false
+	}
+	if f.EventSlug != "" && f.EventSlug != msg.EventSlug {
+		return false
+	}
+	if len(f.AlertTypes) > 0 && msg.Type == StreamTypeAlert && !f.AlertTypes[msg.AlertType] {
+		return false
+	}
+	return true
+}
+
+// StreamSubscription receives messages matching its filter
+type StreamSubscription struct {
+	C      chan StreamMessage
+	filter StreamFilter
+
+	mu      sync.Mutex
+	dropped int
+}
+
+// Dropped returns how many messages were skipped because the subscriber was too slow
+func (s *StreamSubscription) Dropped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// StreamBroker fans out stream messages to subscribers
+type StreamBroker struct {
+	mu          sync.RWMutex
+	subscribers map[*StreamSubscription]struct{}
+	bufferSize  int
+}
+
+// NewStreamBroker creates a new broker with the given per-subscriber buffer
+func NewStreamBroker(bufferSize int) *StreamBroker {
+	if bufferSize <= 0 {
+		bufferSize = 64
+	}
+	return &StreamBroker{
+		subscribers: make(map[*StreamSubscription]struct{}),
+		bufferSize:  bufferSize,
+	}
+}
+
+// Subscribe registers a new subscriber
+func (b *StreamBroker) Subscribe(filter StreamFilter) *StreamSubscription {
+	sub := &StreamSubscription{
+		C:      make(chan StreamMessage, b.bufferSize),
+		filter: filter,
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+// Unsubscribe removes a subscriber and closes its channel
+func (b *StreamBroker) Unsubscribe(sub *StreamSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.C)
+	}
+}
+
+// Publish delivers the message to every matching subscriber without blocking
+// Slow subscribers lose messages instead of stalling the listener
+func (b *StreamBroker) Publish(msg StreamMessage) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		if !sub.filter.Matches(msg) {
+			continue
+		}
+		select {
+		case sub.C <- msg:
+		default:
+			sub.mu.Lock()
+			sub.dropped++
+			sub.mu.Unlock()
+		}
+	}
+}
+
+// SubscriberCount returns the number of active subscribers
+func (b *StreamBroker) SubscriberCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers)
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+)
+
+func TestStreamFilter_Matches(t *testing.T) {
+	odds := StreamMessage{Type: StreamTypeOdds, EventSlug: "a-vs-b", SportCode: "FOOTBALL", LeagueID: 7}
+	alert := StreamMessage{Type: StreamTypeAlert, EventSlug: "a-vs-b", SportCode: "FOOTBALL", LeagueID: 7, AlertType: "sharp_money"}
+
+	tests := []struct {
+		name   string
+		filter StreamFilter
+		msg    StreamMessage
+		want   bool
+	}{
+		{"empty filter matches odds", StreamFilter{}, odds, true},
+		{"type filter excludes odds", StreamFilter{Types: map[string]bool{StreamTypeAlert: true}}, odds, false},
+		{"sport mismatch", StreamFilter{SportCode: "BASKETBALL"}, odds, false},
+		{"league match", StreamFilter{LeagueID: 7}, odds, true},
+		{"event mismatch", StreamFilter{EventSlug: "c-vs-d"}, alert, false},
+		{"alert type match", StreamFilter{AlertTypes: map[string]bool{"sharp_money": true}}, alert, true},
+		{"alert type mismatch", StreamFilter{AlertTypes: map[string]bool{"reverse_line": true}}, alert, false},
+		{"alert type ignored for odds", StreamFilter{AlertTypes: map[string]bool{"reverse_line": true}}, odds, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.msg); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamBroker_PublishDropsWhenFull(t *testing.T) {
+	broker := NewStreamBroker(1)
+	sub := broker.Subscribe(StreamFilter{})
+	other := broker.Subscribe(StreamFilter{SportCode: "BASKETBALL"})
+
+	msg := StreamMessage{Type: StreamTypeOdds, SportCode: "FOOTBALL"}
+	broker.Publish(msg)
+	broker.Publish(msg)
+
+	if len(sub.C) != 1 {
+		t.Fatalf("expected 1 buffered message, got %d", len(sub.C))
+	}
+	if sub.Dropped() != 1 {
+		t.Errorf("expected 1 dropped message, got %d", sub.Dropped())
+	}
+	if len(other.C) != 0 {
+		t.Errorf("expected filtered subscriber to receive nothing, got %d", len(other.C))
+	}
+
+	broker.Unsubscribe(sub)
+	broker.Unsubscribe(other)
+	if broker.SubscriberCount() != 0 {
+		t.Errorf("expected no subscribers, got %d", broker.SubscriberCount())
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
+
 	"github.com/iddaa-lens/core/pkg/database/generated"
 	"github.com/iddaa-lens/core/pkg/logger"
 )
@@ -15,6 +17,7 @@ type VolumeService struct {
 	db     *generated.Queries
 	client *IddaaClient
 	logger *logger.Logger
+	replayClock
 }
 
 func NewVolumeService(db *generated.Queries, client *IddaaClient) *VolumeService {
@@ -39,16 +42,21 @@ type EventVolume struct {
 
 // FetchAndUpdateVolumes fetches betting volume data and updates the database using bulk operations
 func (s *VolumeService) FetchAndUpdateVolumes(ctx context.Context, sportType int) error {
-	start := time.Now()
-
 	// Fetch volume data from API
 	url := fmt.Sprintf("https://sportsbookv2.iddaa.com/sportsbook/played-event-percentage?sportType=%d", sportType)
 
-	data, err := s.client.FetchData(url)
+	data, err := s.client.FetchData(ctx, url)
 	if err != nil {
 		return fmt.Errorf("failed to fetch volume data: %w", err)
 	}
 
+	return s.ProcessVolumePayload(ctx, sportType, data)
+}
+
+// ProcessVolumePayload applies a played-event-percentage response, fetched live or read from the payload archive
+func (s *VolumeService) ProcessVolumePayload(ctx context.Context, sportType int, data []byte) error {
+	start := time.Now()
+
 	var response VolumeResponse
 	if err := json.Unmarshal(data, &response); err != nil {
 		return fmt.Errorf("failed to unmarshal volume response: %w", err)
@@ -75,7 +83,7 @@ func (s *VolumeService) FetchAndUpdateVolumes(ctx context.Context, sportType int
 	}
 
 	// Execute bulk operations in a transaction
-	err = s.executeBulkVolumeUpdate(ctx, externalIDs, percentages)
+	err := s.executeBulkVolumeUpdate(ctx, externalIDs, percentages)
 	if err != nil {
 		return fmt.Errorf("bulk volume update failed: %w", err)
 	}
@@ -130,6 +138,7 @@ func (s *VolumeService) executeBulkVolumeUpdate(ctx context.Context, externalIDs
 	rowsUpdated, err := s.db.BulkUpdateEventVolumes(ctx, generated.BulkUpdateEventVolumesParams{
 		ExternalIds: validExternalIDs,
 		Percentages: validPercentages,
+		RecordedAt:  s.recordedAt(),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to bulk update volumes: %w", err)
@@ -140,6 +149,7 @@ func (s *VolumeService) executeBulkVolumeUpdate(ctx context.Context, externalIDs
 		ExternalIds: validExternalIDs,
 		Percentages: validPercentages,
 		TotalEvents: int64(len(validExternalIDs)),
+		RecordedAt:  s.recordedAt(),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to bulk insert volume history: %w", err)
@@ -160,11 +170,24 @@ var (
 	ErrAPIFailure   = errors.New("API request failed")
 )
 
+// VolumeFilter narrows the hot mover and hidden gem searches
+type VolumeFilter struct {
+	Sport       string    // sport code, empty for all sports
+	MinVolume   float64   // hot movers: minimum share of betting volume (%)
+	MaxVolume   float64   // hidden gems: maximum share of betting volume (%)
+	MinMovement float64   // minimum absolute odds movement (%) on any outcome
+	Since       time.Time // start of the betting_volume_history window for volume change
+	Limit       int
+}
+
 // GetHotMovers finds events with high volume AND significant odds movement
-func (s *VolumeService) GetHotMovers(ctx context.Context, minVolume float64, minMovement float64) ([]HotMover, error) {
+func (s *VolumeService) GetHotMovers(ctx context.Context, filter VolumeFilter) ([]HotMover, error) {
 	rows, err := s.db.GetHotMovers(ctx, generated.GetHotMoversParams{
-		MinVolume:   minVolume,
-		MinMovement: minMovement,
+		MinVolume:   filter.MinVolume,
+		SportCode:   filter.Sport,
+		SinceTime:   pgtype.Timestamp{Time: filter.Since, Valid: true},
+		MinMovement: filter.MinMovement,
+		LimitCount:  int32(filter.Limit),
 	})
 	if err != nil {
 		return nil, err
@@ -183,10 +206,16 @@ func (s *VolumeService) GetHotMovers(ctx context.Context, minVolume float64, min
 
 		movers[i] = HotMover{
 			EventSlug:       row.Slug,
-			MatchName:       row.MatchName.(string),
+			MatchName:       row.MatchName,
+			Sport:           row.SportCode,
+			SportName:       row.SportName,
+			League:          row.LeagueName,
+			EventDate:       row.EventDate.Time,
 			Volume:          volume,
 			VolumeRank:      volumeRank,
-			MaxMovement:     row.MaxMovement.(float64),
+			BestRank:        row.BestRank,
+			VolumeChange:    row.VolumeChange,
+			MaxMovement:     row.MaxMovement,
 			PopularityLevel: row.PopularityLevel,
 			EventType:       row.EventType,
 		}
@@ -196,10 +225,13 @@ func (s *VolumeService) GetHotMovers(ctx context.Context, minVolume float64, min
 }
 
 // GetHiddenGems finds low-volume events with big odds movements (potential sharp money)
-func (s *VolumeService) GetHiddenGems(ctx context.Context, maxVolume float64, minMovement float64) ([]HiddenGem, error) {
+func (s *VolumeService) GetHiddenGems(ctx context.Context, filter VolumeFilter) ([]HiddenGem, error) {
 	rows, err := s.db.GetHiddenGems(ctx, generated.GetHiddenGemsParams{
-		MaxVolume:   maxVolume,
-		MinMovement: minMovement,
+		MaxVolume:   filter.MaxVolume,
+		SportCode:   filter.Sport,
+		SinceTime:   pgtype.Timestamp{Time: filter.Since, Valid: true},
+		MinMovement: filter.MinMovement,
+		LimitCount:  int32(filter.Limit),
 	})
 	if err != nil {
 		return nil, err
@@ -211,13 +243,23 @@ func (s *VolumeService) GetHiddenGems(ctx context.Context, maxVolume float64, mi
 		if row.BettingVolumePercentage != nil {
 			volume = float64(*row.BettingVolumePercentage)
 		}
+		volumeRank := 0
+		if row.VolumeRank != nil {
+			volumeRank = int(*row.VolumeRank)
+		}
 
 		gems[i] = HiddenGem{
-			EventSlug:   row.Slug,
-			MatchName:   row.MatchName.(string),
-			Volume:      volume,
-			MaxMovement: row.MaxMovement.(float64),
-			Insight:     "Low public interest but sharp money moving - potential value",
+			EventSlug:    row.Slug,
+			MatchName:    row.MatchName,
+			Sport:        row.SportCode,
+			SportName:    row.SportName,
+			League:       row.LeagueName,
+			EventDate:    row.EventDate.Time,
+			Volume:       volume,
+			VolumeRank:   volumeRank,
+			VolumeChange: row.VolumeChange,
+			MaxMovement:  row.MaxMovement,
+			Insight:      "Low public interest but sharp money moving - potential value",
 		}
 	}
 
@@ -225,19 +267,93 @@ func (s *VolumeService) GetHiddenGems(ctx context.Context, maxVolume float64, mi
 }
 
 type HotMover struct {
-	EventSlug       string  `json:"event_slug"`
-	MatchName       string  `json:"match_name"`
-	Volume          float64 `json:"volume_percentage"`
-	VolumeRank      int     `json:"volume_rank"`
-	MaxMovement     float64 `json:"max_movement_percentage"`
-	PopularityLevel string  `json:"popularity_level"`
-	EventType       string  `json:"event_type"`
+	EventSlug       string    `json:"event_slug"`
+	MatchName       string    `json:"match_name"`
+	Sport           string    `json:"sport"`
+	SportName       string    `json:"sport_name"`
+	League          string    `json:"league"`
+	EventDate       time.Time `json:"event_date"`
+	Volume          float64   `json:"volume_percentage"`
+	VolumeRank      int       `json:"volume_rank"`
+	BestRank        *int32    `json:"best_rank,omitempty"`
+	VolumeChange    float64   `json:"volume_change"`
+	MaxMovement     float64   `json:"max_movement_percentage"`
+	PopularityLevel string    `json:"popularity_level"`
+	EventType       string    `json:"event_type"`
 }
 
 type HiddenGem struct {
-	EventSlug   string  `json:"event_slug"`
-	MatchName   string  `json:"match_name"`
-	Volume      float64 `json:"volume_percentage"`
-	MaxMovement float64 `json:"max_movement_percentage"`
-	Insight     string  `json:"insight"`
+	EventSlug    string    `json:"event_slug"`
+	MatchName    string    `json:"match_name"`
+	Sport        string    `json:"sport"`
+	SportName    string    `json:"sport_name"`
+	League       string    `json:"league"`
+	EventDate    time.Time `json:"event_date"`
+	Volume       float64   `json:"volume_percentage"`
+	VolumeRank   int       `json:"volume_rank"`
+	VolumeChange float64   `json:"volume_change"`
+	MaxMovement  float64   `json:"max_movement_percentage"`
+	Insight      string    `json:"insight"`
+}
+
+// SportVolumeSummary aggregates hot movers or hidden gems for one sport
+type SportVolumeSummary struct {
+	Sport          string  `json:"sport"`
+	SportName      string  `json:"sport_name"`
+	Events         int     `json:"events"`
+	AvgVolume      float64 `json:"avg_volume_percentage"`
+	AvgMovement    float64 `json:"avg_movement_percentage"`
+	MaxMovement    float64 `json:"max_movement_percentage"`
+	NetVolumeShift float64 `json:"net_volume_change"`
+}
+
+// GetHotMoversBySport rolls every hot mover matching the filter up per sport, busiest sports first.
+// The limit of the filter does not apply.
+func (s *VolumeService) GetHotMoversBySport(ctx context.Context, filter VolumeFilter) ([]SportVolumeSummary, error) {
+	rows, err := s.db.GetHotMoversBySport(ctx, generated.GetHotMoversBySportParams{
+		MinVolume:   filter.MinVolume,
+		SportCode:   filter.Sport,
+		SinceTime:   pgtype.Timestamp{Time: filter.Since, Valid: true},
+		MinMovement: filter.MinMovement,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sportVolumeSummaries(rows), nil
+}
+
+// GetHiddenGemsBySport rolls every hidden gem matching the filter up per sport, busiest sports
+// first. The limit of the filter does not apply.
+func (s *VolumeService) GetHiddenGemsBySport(ctx context.Context, filter VolumeFilter) ([]SportVolumeSummary, error) {
+	rows, err := s.db.GetHiddenGemsBySport(ctx, generated.GetHiddenGemsBySportParams{
+		MaxVolume:   filter.MaxVolume,
+		SportCode:   filter.Sport,
+		SinceTime:   pgtype.Timestamp{Time: filter.Since, Valid: true},
+		MinMovement: filter.MinMovement,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]generated.GetHotMoversBySportRow, len(rows))
+	for i, row := range rows {
+		summaries[i] = generated.GetHotMoversBySportRow(row)
+	}
+	return sportVolumeSummaries(summaries), nil
+}
+
+func sportVolumeSummaries(rows []generated.GetHotMoversBySportRow) []SportVolumeSummary {
+	summaries := make([]SportVolumeSummary, len(rows))
+	for i, row := range rows {
+		summaries[i] = SportVolumeSummary{
+			Sport:          row.SportCode,
+			SportName:      row.SportName,
+			Events:         int(row.Events),
+			AvgVolume:      row.AvgVolume,
+			AvgMovement:    row.AvgMovement,
+			MaxMovement:    row.MaxMovement,
+			NetVolumeShift: row.NetVolumeChange,
+		}
+	}
+	return summaries
 }
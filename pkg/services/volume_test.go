@@ -0,0 +1,38 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+func TestSportVolumeSummaries(t *testing.T) {
+	rows := []generated.GetHotMoversBySportRow{
+		{SportCode: "1", SportName: "Futbol", Events: 2, AvgVolume: 5, AvgMovement: 40, MaxMovement: 60, NetVolumeChange: 3},
+		{SportCode: "2", SportName: "Basketbol", Events: 1, AvgVolume: 3, AvgMovement: 40, MaxMovement: 40, NetVolumeChange: -0.5},
+	}
+
+	got := sportVolumeSummaries(rows)
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2", len(got))
+	}
+
+	football := got[0]
+	if football.Sport != "1" || football.SportName != "Futbol" || football.Events != 2 {
+		t.Fatalf("first summary = %+v, want sport 1 with 2 events", football)
+	}
+	if football.AvgVolume != 5 || football.AvgMovement != 40 || football.MaxMovement != 60 || football.NetVolumeShift != 3 {
+		t.Errorf("football summary = %+v", football)
+	}
+
+	basketball := got[1]
+	if basketball.Events != 1 || basketball.AvgVolume != 3 || basketball.NetVolumeShift != -0.5 {
+		t.Errorf("basketball summary = %+v", basketball)
+	}
+}
+
+func TestSportVolumeSummaries_Empty(t *testing.T) {
+	if got := sportVolumeSummaries(nil); len(got) != 0 {
+		t.Errorf("sportVolumeSummaries(nil) = %v, want empty", got)
+	}
+}
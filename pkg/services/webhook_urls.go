@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrWebhookAddressBlocked is returned for webhook hosts that are not publicly routable
+var ErrWebhookAddressBlocked = errors.New("webhook address is not publicly routable")
+
+// Ranges the IP helpers do not classify: shared address space, IETF protocol assignments,
+// benchmarking, reserved and NAT64, which can embed any IPv4 address
+var blockedWebhookPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// ValidateWebhookURL checks that a webhook URL is http(s) and that every address its host
+// resolves to is public. Deliveries check the address again when they connect, the host may
+// resolve differently by then.
+func ValidateWebhookURL(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return fmt.Errorf("invalid webhook URL")
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", parsed.Hostname())
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook host %s: %w", parsed.Hostname(), err)
+	}
+	for _, addr := range addrs {
+		if blockedWebhookAddr(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrWebhookAddressBlocked, parsed.Hostname(), addr)
+		}
+	}
+	return nil
+}
+
+// newWebhookHTTPClient returns a client that refuses to connect to addresses that are not public,
+// whatever the host resolved to and across redirects. It does not use a proxy, which would
+// connect on its behalf.
+func newWebhookHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: webhookDialControl,
+	}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        20,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// webhookDialControl runs after resolution, address is the IP being connected to
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if blockedWebhookAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrWebhookAddressBlocked, addr)
+	}
+	return nil
+}
+
+// blockedWebhookAddr reports loopback, private, link-local (cloud metadata), multicast and
+// reserved addresses
+func blockedWebhookAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() {
+		return true
+	}
+	for _, prefix := range blockedWebhookPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+	"github.com/iddaa-lens/core/pkg/logger"
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookSignatureHeader = "X-Iddaa-Signature"
+	WebhookTimestampHeader = "X-Iddaa-Timestamp"
+	WebhookDeliveryHeader  = "X-Iddaa-Delivery"
+	WebhookEventHeader     = "X-Iddaa-Event"
+)
+
+// WebhookStore is the subset of queries the webhook service needs
+type WebhookStore interface {
+	EnqueueWebhookDeliveries(ctx context.Context, sinceTime pgtype.Timestamp) (int64, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, arg generated.ClaimDueWebhookDeliveriesParams) ([]generated.ClaimDueWebhookDeliveriesRow, error)
+	CreateWebhookDeliveryAttempt(ctx context.Context, arg generated.CreateWebhookDeliveryAttemptParams) error
+	MarkWebhookDelivered(ctx context.Context, arg generated.MarkWebhookDeliveredParams) error
+	ScheduleWebhookRetry(ctx context.Context, arg generated.ScheduleWebhookRetryParams) error
+	MarkWebhookDead(ctx context.Context, arg generated.MarkWebhookDeadParams) error
+}
+
+// WebhookStats summarizes a single dispatch run
+type WebhookStats struct {
+	Enqueued  int64
+	Delivered int
+	Retrying  int
+	Dead      int
+}
+
+// WebhookService delivers movement alerts to registered webhook URLs
+type WebhookService struct {
+	store       WebhookStore
+	client      *http.Client
+	logger      *logger.Logger
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	batchSize   int32
+	lookback    time.Duration
+	// claimLease keeps claimed deliveries from other dispatchers, it outlasts sending a whole batch
+	claimLease time.Duration
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(store WebhookStore) *WebhookService {
+	return &WebhookService{
+		store:       store,
+		client:      newWebhookHTTPClient(),
+		logger:      logger.New("webhook-service"),
+		maxAttempts: 6,
+		baseDelay:   30 * time.Second,
+		maxDelay:    time.Hour,
+		batchSize:   100,
+		lookback:    2 * time.Hour,
+		claimLease:  30 * time.Minute,
+	}
+}
+
+// SetHTTPClient replaces the HTTP client used for deliveries, and with it the check that
+// deliveries only connect to public addresses
+func (s *WebhookService) SetHTTPClient(client *http.Client) {
+	s.client = client
+}
+
+// SetRetryPolicy configures the number of attempts and the backoff window
+func (s *WebhookService) SetRetryPolicy(maxAttempts int, baseDelay, maxDelay time.Duration) {
+	s.maxAttempts = maxAttempts
+	s.baseDelay = baseDelay
+	s.maxDelay = maxDelay
+}
+
+// ProcessDeliveries enqueues new alerts and sends every delivery that is due
+func (s *WebhookService) ProcessDeliveries(ctx context.Context) (WebhookStats, error) {
+	var stats WebhookStats
+
+	enqueued, err := s.store.EnqueueWebhookDeliveries(ctx, pgtype.Timestamp{
+		Time:  time.Now().Add(-s.lookback),
+		Valid: true,
+	})
+	if err != nil {
+		return stats, fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+	stats.Enqueued = enqueued
+
+	claimedAt := time.Now()
+	deliveries, err := s.store.ClaimDueWebhookDeliveries(ctx, generated.ClaimDueWebhookDeliveriesParams{
+		LimitCount:   s.batchSize,
+		LeaseSeconds: int32(s.claimLease.Seconds()),
+	})
+	if err != nil {
+		return stats, fmt.Errorf("failed to claim due webhook deliveries: %w", err)
+	}
+
+	for i, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return stats, ctx.Err()
+		}
+		// Deliveries left once the lease ended may be claimed by another dispatcher already
+		if time.Since(claimedAt) >= s.claimLease {
+			s.logger.Warn().
+				Int("skipped", len(deliveries)-i).
+				Dur("lease", s.claimLease).
+				Msg("Webhook claim lease ended, leaving the remaining deliveries to the next run")
+			break
+		}
+
+		status, err := s.deliver(ctx, delivery)
+		if err != nil {
+			s.logger.Error().Err(err).
+				Int32("delivery_id", delivery.ID).
+				Msg("Failed to record webhook delivery result")
+			continue
+		}
+
+		switch status {
+		case "delivered":
+			stats.Delivered++
+		case "retrying":
+			stats.Retrying++
+		case "dead":
+			stats.Dead++
+		}
+	}
+
+	return stats, nil
+}
+
+// deliver sends one delivery and records the outcome, returning the new status
+func (s *WebhookService) deliver(ctx context.Context, delivery generated.ClaimDueWebhookDeliveriesRow) (string, error) {
+	attempt := delivery.Attempts + 1
+	start := time.Now()
+	statusCode, sendErr := s.send(ctx, delivery.Url, delivery.Secret, delivery.ID, delivery.Payload)
+	duration := time.Since(start)
+
+	var responseStatus *int32
+	if statusCode > 0 {
+		code := int32(statusCode)
+		responseStatus = &code
+	}
+	var lastError *string
+	if sendErr != nil {
+		msg := sendErr.Error()
+		lastError = &msg
+	}
+
+	if err := s.store.CreateWebhookDeliveryAttempt(ctx, generated.CreateWebhookDeliveryAttemptParams{
+		DeliveryID:     delivery.ID,
+		Attempt:        attempt,
+		ResponseStatus: responseStatus,
+		Error:          lastError,
+		DurationMs:     int32(duration.Milliseconds()),
+	}); err != nil {
+		return "", fmt.Errorf("failed to record attempt: %w", err)
+	}
+
+	if sendErr == nil {
+		return "delivered", s.store.MarkWebhookDelivered(ctx, generated.MarkWebhookDeliveredParams{
+			Attempts:       attempt,
+			ResponseStatus: responseStatus,
+			ID:             delivery.ID,
+		})
+	}
+
+	s.logger.Warn().
+		Err(sendErr).
+		Int32("delivery_id", delivery.ID).
+		Int32("attempt", attempt).
+		Msg("Webhook delivery failed")
+
+	if int(attempt) >= s.maxAttempts {
+		return "dead", s.store.MarkWebhookDead(ctx, generated.MarkWebhookDeadParams{
+			Attempts:       attempt,
+			ResponseStatus: responseStatus,
+			LastError:      lastError,
+			ID:             delivery.ID,
+		})
+	}
+
+	return "retrying", s.store.ScheduleWebhookRetry(ctx, generated.ScheduleWebhookRetryParams{
+		Attempts:       attempt,
+		ResponseStatus: responseStatus,
+		LastError:      lastError,
+		NextAttemptAt: pgtype.Timestamp{
+			Time:  time.Now().Add(WebhookRetryDelay(int(attempt), s.baseDelay, s.maxDelay)),
+			Valid: true,
+		},
+		ID: delivery.ID,
+	})
+}
+
+// send POSTs the signed payload and treats any 2xx response as success
+func (s *WebhookService) send(ctx context.Context, url, secret string, deliveryID int32, payload []byte) (int, error) {
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "iddaa-lens-webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, "movement_alert")
+	req.Header.Set(WebhookDeliveryHeader, strconv.Itoa(int(deliveryID)))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(secret, timestamp, payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "timestamp.payload"
+// Receivers recompute it with their secret to verify origin and freshness
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookRetryDelay returns the exponential backoff before the next attempt
+func WebhookRetryDelay(attempt int, baseDelay, maxDelay time.Duration) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := baseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/iddaa-lens/core/pkg/database/generated"
+)
+
+// fakeWebhookStore records state transitions in memory
+type fakeWebhookStore struct {
+	due       []generated.ClaimDueWebhookDeliveriesRow
+	claims    []generated.ClaimDueWebhookDeliveriesParams
+	attempts  []generated.CreateWebhookDeliveryAttemptParams
+	delivered []generated.MarkWebhookDeliveredParams
+	retries   []generated.ScheduleWebhookRetryParams
+	dead      []generated.MarkWebhookDeadParams
+}
+
+func (f *fakeWebhookStore) EnqueueWebhookDeliveries(ctx context.Context, sinceTime pgtype.Timestamp) (int64, error) {
+	return int64(len(f.due)), nil
+}
+
+// ClaimDueWebhookDeliveries hands out due deliveries once, like the claim moving them past the lease
+func (f *fakeWebhookStore) ClaimDueWebhookDeliveries(ctx context.Context, arg generated.ClaimDueWebhookDeliveriesParams) ([]generated.ClaimDueWebhookDeliveriesRow, error) {
+	f.claims = append(f.claims, arg)
+	claimed := f.due
+	f.due = nil
+	return claimed, nil
+}
+
+func (f *fakeWebhookStore) CreateWebhookDeliveryAttempt(ctx context.Context, arg generated.CreateWebhookDeliveryAttemptParams) error {
+	f.attempts = append(f.attempts, arg)
+	return nil
+}
+
+func (f *fakeWebhookStore) MarkWebhookDelivered(ctx context.Context, arg generated.MarkWebhookDeliveredParams) error {
+	f.delivered = append(f.delivered, arg)
+	return nil
+}
+
+func (f *fakeWebhookStore) ScheduleWebhookRetry(ctx context.Context, arg generated.ScheduleWebhookRetryParams) error {
+	f.retries = append(f.retries, arg)
+	return nil
+}
+
+func (f *fakeWebhookStore) MarkWebhookDead(ctx context.Context, arg generated.MarkWebhookDeadParams) error {
+	f.dead = append(f.dead, arg)
+	return nil
+}
+
+func TestWebhookService_DeliversSignedPayload(t *testing.T) {
+	payload := []byte(`{"event":"movement_alert","alert":{"id":1}}`)
+	secret := "s3cret"
+
+	var gotSignature, gotTimestamp, gotDelivery string
+	var gotBody []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(WebhookSignatureHeader)
+		gotTimestamp = r.Header.Get(WebhookTimestampHeader)
+		gotDelivery = r.Header.Get(WebhookDeliveryHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	store := &fakeWebhookStore{due: []generated.ClaimDueWebhookDeliveriesRow{
+		{ID: 42, SubscriptionID: 1, AlertID: 7, Payload: payload, Url: receiver.URL, Secret: secret},
+	}}
+	service := NewWebhookService(store)
+	service.SetHTTPClient(receiver.Client())
+
+	stats, err := service.ProcessDeliveries(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessDeliveries() error = %v", err)
+	}
+	if stats.Delivered != 1 {
+		t.Fatalf("expected 1 delivered, got %+v", stats)
+	}
+
+	timestamp, err := strconv.ParseInt(gotTimestamp, 10, 64)
+	if err != nil {
+		t.Fatalf("invalid timestamp header %q", gotTimestamp)
+	}
+	if want := "sha256=" + SignWebhookPayload(secret, timestamp, gotBody); gotSignature != want {
+		t.Errorf("signature = %q, want %q", gotSignature, want)
+	}
+	if gotDelivery != "42" {
+		t.Errorf("delivery header = %q, want 42", gotDelivery)
+	}
+	if string(gotBody) != string(payload) {
+		t.Errorf("body = %s, want %s", gotBody, payload)
+	}
+	if len(store.attempts) != 1 || store.attempts[0].ResponseStatus == nil || *store.attempts[0].ResponseStatus != http.StatusNoContent {
+		t.Errorf("expected one attempt with status 204, got %+v", store.attempts)
+	}
+}
+
+func TestWebhookService_RetriesThenDeadLetters(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer receiver.Close()
+
+	store := &fakeWebhookStore{due: []generated.ClaimDueWebhookDeliveriesRow{
+		{ID: 1, Payload: []byte(`{}`), Url: receiver.URL, Secret: "x", Attempts: 0},
+		{ID: 2, Payload: []byte(`{}`), Url: receiver.URL, Secret: "x", Attempts: 2},
+	}}
+	service := NewWebhookService(store)
+	service.SetHTTPClient(receiver.Client())
+	service.SetRetryPolicy(3, time.Minute, time.Hour)
+
+	stats, err := service.ProcessDeliveries(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessDeliveries() error = %v", err)
+	}
+	if stats.Retrying != 1 || stats.Dead != 1 {
+		t.Fatalf("expected 1 retrying and 1 dead, got %+v", stats)
+	}
+	if store.retries[0].ID != 1 || store.retries[0].Attempts != 1 {
+		t.Errorf("unexpected retry %+v", store.retries[0])
+	}
+	if !store.retries[0].NextAttemptAt.Time.After(time.Now().Add(30 * time.Second)) {
+		t.Errorf("expected retry to be scheduled about a minute out, got %v", store.retries[0].NextAttemptAt.Time)
+	}
+	if store.dead[0].ID != 2 || store.dead[0].Attempts != 3 {
+		t.Errorf("unexpected dead letter %+v", store.dead[0])
+	}
+}
+
+func TestWebhookService_ClaimsDeliveriesForTheWholeBatch(t *testing.T) {
+	requests := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	store := &fakeWebhookStore{due: []generated.ClaimDueWebhookDeliveriesRow{
+		{ID: 1, Payload: []byte(`{}`), Url: receiver.URL, Secret: "x"},
+	}}
+	service := NewWebhookService(store)
+	service.SetHTTPClient(receiver.Client())
+
+	// A second dispatcher running meanwhile finds nothing left to claim
+	for range 2 {
+		if _, err := service.ProcessDeliveries(context.Background()); err != nil {
+			t.Fatalf("ProcessDeliveries() error = %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected the delivery to be sent once, got %d requests", requests)
+	}
+
+	// Sending a full batch at the client timeout must not outlast the lease
+	lease := time.Duration(store.claims[0].LeaseSeconds) * time.Second
+	if batch := time.Duration(store.claims[0].LimitCount) * newWebhookHTTPClient().Timeout; lease < batch {
+		t.Errorf("claim lease %v is shorter than sending a batch, %v", lease, batch)
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	base := 30 * time.Second
+	max := 10 * time.Minute
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{6, 10 * time.Minute},
+		{20, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := WebhookRetryDelay(tt.attempt, base, max); got != tt.want {
+			t.Errorf("WebhookRetryDelay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		blocked bool
+		wantErr bool
+	}{
+		{url: "https://93.184.216.34/hooks/alerts"},
+		{url: "http://[2606:4700::1111]/hook"},
+		{url: "ftp://93.184.216.34/hook", wantErr: true},
+		{url: "http:///hook", wantErr: true},
+		{url: "http://127.0.0.1:8080/hook", blocked: true},
+		{url: "http://localhost/hook", blocked: true},
+		{url: "http://10.1.2.3/hook", blocked: true},
+		{url: "http://192.168.1.10/hook", blocked: true},
+		{url: "http://169.254.169.254/latest/meta-data/", blocked: true},
+		{url: "http://100.100.100.200/latest/meta-data/", blocked: true},
+		{url: "http://0.0.0.0/hook", blocked: true},
+		{url: "http://[::1]/hook", blocked: true},
+		{url: "http://[::ffff:10.0.0.1]/hook", blocked: true},
+		{url: "http://[fd00:ec2::254]/hook", blocked: true},
+		{url: "http://[fe80::1]/hook", blocked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := ValidateWebhookURL(context.Background(), tt.url)
+			if got := errors.Is(err, ErrWebhookAddressBlocked); got != tt.blocked {
+				t.Errorf("ValidateWebhookURL() error = %v, blocked = %v, want %v", err, got, tt.blocked)
+			}
+			if (err != nil) != (tt.blocked || tt.wantErr) {
+				t.Errorf("ValidateWebhookURL() error = %v, want error %v", err, tt.blocked || tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookService_RefusesPrivateAddressOnDelivery(t *testing.T) {
+	received := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	defer receiver.Close()
+
+	// The URL passed validation once, the host now resolves to loopback
+	store := &fakeWebhookStore{due: []generated.ClaimDueWebhookDeliveriesRow{
+		{ID: 1, Payload: []byte(`{}`), Url: receiver.URL, Secret: "x"},
+	}}
+	service := NewWebhookService(store)
+
+	stats, err := service.ProcessDeliveries(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessDeliveries() error = %v", err)
+	}
+	if received || stats.Retrying != 1 {
+		t.Fatalf("expected the delivery to be refused and retried, received = %v, stats = %+v", received, stats)
+	}
+	if lastError := store.retries[0].LastError; lastError == nil || !strings.Contains(*lastError, ErrWebhookAddressBlocked.Error()) {
+		t.Errorf("last error = %v, want the blocked address", lastError)
+	}
+}
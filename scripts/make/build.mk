@@ -1,8 +1,8 @@
 # Build related targets
 
-.PHONY: build build-api build-cron clean
+.PHONY: build build-api build-cron build-backtest build-iddaa-mock build-reprocess clean
 
-build: build-api build-cron ## Build all services
+build: build-api build-cron build-backtest build-iddaa-mock build-reprocess ## Build all services
 
 build-api: ## Build the REST API service
 	@mkdir -p bin
@@ -12,5 +12,17 @@ build-cron: ## Build the cron jobs service
 	@mkdir -p bin
 	go build -o bin/cron ./cmd/cron
 
+build-backtest: ## Build the backtest CLI
+	@mkdir -p bin
+	go build -o bin/backtest ./cmd/backtest
+
+build-iddaa-mock: ## Build the Iddaa mock server
+	@mkdir -p bin
+	go build -o bin/iddaa-mock ./cmd/iddaa-mock
+
+build-reprocess: ## Build the archive reprocess CLI
+	@mkdir -p bin
+	go build -o bin/reprocess ./cmd/reprocess
+
 clean: ## Clean build artifacts
 	rm -rf bin/
\ No newline at end of file